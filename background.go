@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// backgroundTasks tracks long-running goroutines (watchers, schedulers, workers)
+// so shutdown can wait for them instead of killing them mid-write.
+//
+// Lifecycle:
+//  1. main creates a root context that is cancelled on SIGINT/SIGTERM
+//  2. Every task is started with Go(ctx, ...) and must return when ctx is done
+//  3. On shutdown, main calls Wait with a deadline
+//
+// Why sync.WaitGroup?
+//   - Add(1) before starting a goroutine, Done() when it returns
+//   - Wait() blocks until the counter is back to zero
+//   - It doesn't support timeouts, so Wait below races it against a context
+type backgroundTasks struct {
+	wg sync.WaitGroup
+}
+
+// Go starts task in a new goroutine and tracks it until it returns
+//
+// Parameters:
+//   - ctx: Context passed to the task; cancelled on shutdown
+//   - name: Task name for logs
+//   - task: Function that runs until ctx is done
+func (b *backgroundTasks) Go(ctx context.Context, name string, task func(ctx context.Context)) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		slog.Info("Background task started", "task", name)
+		task(ctx)
+		slog.Info("Background task stopped", "task", name)
+	}()
+}
+
+// Wait blocks until all tasks have returned or ctx is done
+//
+// Parameters:
+//   - ctx: Deadline for waiting (usually the shutdown timeout)
+//
+// Returns:
+//   - error: nil if all tasks finished, otherwise the context error
+func (b *backgroundTasks) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background tasks did not stop in time: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestBackgroundTasks_StopOnCancel verifies a task exits promptly when the root context is cancelled
+func TestBackgroundTasks_StopOnCancel(t *testing.T) {
+	var tasks backgroundTasks
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Dummy task: ticks until cancelled, then finishes its "write"
+	finished := make(chan struct{})
+	tasks.Go(ctx, "dummy", func(ctx context.Context) {
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				close(finished)
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+
+	cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), time.Second)
+	defer waitCancel()
+	if err := tasks.Wait(waitCtx); err != nil {
+		t.Fatalf("Wait() error = %v, want nil", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Errorf("task returned without finishing its cleanup")
+	}
+}
+
+// TestBackgroundTasks_WaitTimeout verifies Wait gives up when a task ignores cancellation
+func TestBackgroundTasks_WaitTimeout(t *testing.T) {
+	var tasks backgroundTasks
+
+	release := make(chan struct{})
+	// Let the stuck goroutine exit and wait for it, so its "stopped" log
+	// doesn't land in a later test that captures slog output
+	defer func() {
+		close(release)
+		_ = tasks.Wait(context.Background())
+	}()
+
+	tasks.Go(context.Background(), "stuck", func(context.Context) {
+		<-release
+	})
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+
+	err := tasks.Wait(waitCtx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want context.DeadlineExceeded", err)
+	}
+}
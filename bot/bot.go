@@ -3,10 +3,72 @@ package bot
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/Alrem/run-tbot/config"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// BotSender is the part of *tgbotapi.BotAPI that handlers actually use.
+// *tgbotapi.BotAPI satisfies it automatically (Go interfaces are implicit).
+//
+// Why an interface?
+//   - Handlers depend on behavior (send a message), not on a concrete type
+//   - Tests can pass a fake sender that records messages instead of calling Telegram
+//   - Wrappers (logging, dry run, ...) can be slotted in without touching handlers
+//
+// Methods:
+//   - Send: sends a Chattable and returns the resulting Message (sendMessage, sendDocument, ...)
+//   - Request: calls methods that don't return a Message (answerCallbackQuery, setWebhook, ...)
+type BotSender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// MessageType returns the concrete type name of a Chattable for logging
+// (e.g. "MessageConfig", "ChatActionConfig", "EditMessageTextConfig")
+//
+// Why?
+//   - "Failed to send" logs are easier to analyze when they say what failed:
+//     a text message, a document, a callback answer, ...
+//
+// Parameters:
+//   - c: Chattable passed to Send or Request
+//
+// Returns:
+//   - string: Type name without package prefix ("nil" for a nil Chattable)
+func MessageType(c tgbotapi.Chattable) string {
+	// Types the bot sends are listed explicitly (no formatting cost on the hot path)
+	switch c.(type) {
+	case nil:
+		return "nil"
+	case tgbotapi.MessageConfig:
+		return "MessageConfig"
+	case tgbotapi.ChatActionConfig:
+		return "ChatActionConfig"
+	case tgbotapi.EditMessageTextConfig:
+		return "EditMessageTextConfig"
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return "EditMessageReplyMarkupConfig"
+	case tgbotapi.DiceConfig:
+		return "DiceConfig"
+	case tgbotapi.DocumentConfig:
+		return "DocumentConfig"
+	case tgbotapi.PhotoConfig:
+		return "PhotoConfig"
+	case tgbotapi.CallbackConfig:
+		return "CallbackConfig"
+	case tgbotapi.InlineConfig:
+		return "InlineConfig"
+	case tgbotapi.LeaveChatConfig:
+		return "LeaveChatConfig"
+	default:
+		// Anything else: "%T" gives "tgbotapi.X" or "*pkg.X", keep only "X"
+		name := fmt.Sprintf("%T", c)
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+}
+
 // NewBot creates a new Telegram bot instance
 // Parameters:
 //   - token: token from @BotFather for API access
@@ -30,32 +92,213 @@ func NewBot(token string, debug bool) (*tgbotapi.BotAPI, error) {
 	return bot, nil
 }
 
-// GetMainKeyboard returns a reply keyboard with all bot features
+// NewPhotoFromURL creates a photo message that Telegram downloads from photoURL
+//
+// How it works:
+//   - tgbotapi.FileURL makes the request send the URL instead of uploading a file
+//   - Telegram fetches the image itself (max 5 MB, JPEG/PNG/...), so the bot
+//     never downloads it
+//   - If Telegram can't fetch it, Send fails with "Bad Request: wrong file
+//     identifier/HTTP URL specified" or "failed to get HTTP URL content"
+//
+// Parameters:
+//   - chatID: Target chat
+//   - photoURL: Public HTTP(S) URL of the image
+//   - caption: Text under the photo (0-1024 characters, plain text)
+//
+// Returns:
+//   - tgbotapi.PhotoConfig: Ready to send (or to adjust, e.g. ReplyToMessageID)
+func NewPhotoFromURL(chatID int64, photoURL, caption string) tgbotapi.PhotoConfig {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(photoURL))
+	photo.Caption = caption
+	return photo
+}
+
+// SendPhotoFromURL sends the image at photoURL to a chat (see NewPhotoFromURL)
+//
+// Parameters:
+//   - bot: Bot sender
+//   - chatID: Target chat
+//   - photoURL: Public HTTP(S) URL of the image
+//   - caption: Text under the photo (plain text)
+//
+// Returns:
+//   - error: If Telegram rejected the photo or couldn't fetch the URL
+func SendPhotoFromURL(bot BotSender, chatID int64, photoURL, caption string) error {
+	if _, err := bot.Send(NewPhotoFromURL(chatID, photoURL, caption)); err != nil {
+		return fmt.Errorf("failed to send photo: %w", err)
+	}
+	return nil
+}
+
+// Button is one reply keyboard button, with the line describing it in /start
+type Button struct {
+	Text        string // Label; also the text Telegram sends when the button is pressed
+	Key         string // Stable name, e.g. "dice": the translated description is button_<Key>
+	Description string // Shown next to the label in the /start welcome (English)
+	Feature     string // config.Feature* flag that enables the button ("": always enabled)
+	Private     bool   // Only shown to authorized users (ALLOWED_USERS)
+}
+
+// mainButtons are the feature buttons in display order
+var mainButtons = []Button{
+	{Text: "🎲 Dice", Key: "dice", Description: "Roll a single die (1-6)", Feature: config.FeatureDice},
+	{Text: "🎲🎲 Double Dice", Key: "double_dice", Description: "Roll two dice (2-12)", Feature: config.FeatureDoubleDice},
+	{Text: "🌀 Twister", Key: "twister", Description: "Get a random Twister move", Feature: config.FeatureTwister},
+	{Text: "🖥️ OVH Servers", Key: "ovh", Description: "Check server availability", Feature: config.FeatureOVH, Private: true},
+}
+
+// adminFeatureButtons are feature buttons only authorized users ever get,
+// in their own row above the admin row (left out when the feature is disabled)
+var adminFeatureButtons = []Button{
+	{Text: "🔵 Dedicated Servers", Key: "dedicated", Description: "Check dedicated (Advance) server availability", Feature: config.FeatureOVH, Private: true},
+}
+
+// adminButtons are the extra row shown to authorized users
+var adminButtons = []Button{
+	{Text: "📊 Stats", Key: "stats", Description: "Bot runtime statistics", Private: true},
+	{Text: "📢 Broadcast", Key: "broadcast", Description: "How to message all known chats", Private: true},
+	{Text: "⚙️ Settings", Key: "settings", Description: "Current bot settings", Private: true},
+}
+
+// mainButtonsPerRow is the width of the feature rows (2x2 with every feature enabled)
+const mainButtonsPerRow = 2
+
+// enabledButtons filters buttons down to the ones a user can use
+//
+// Parameters:
+//   - buttons: Buttons in display order
+//   - features: Enabled features (cfg.Features)
+//   - includePrivate: false leaves out private buttons
+//
+// Returns:
+//   - []Button: The usable buttons, in the same order (nil if none)
+func enabledButtons(buttons []Button, features config.Features, includePrivate bool) []Button {
+	var enabled []Button
+	for _, button := range buttons {
+		if !features.Enabled(button.Feature) || (button.Private && !includePrivate) {
+			continue
+		}
+		enabled = append(enabled, button)
+	}
+	return enabled
+}
+
+// buttonLabels returns the label of every button
+func buttonLabels(buttons []Button) []string {
+	labels := make([]string, len(buttons))
+	for i, button := range buttons {
+		labels[i] = button.Text
+	}
+	return labels
+}
+
+// groupRows splits items into rows of perRow items; the last row takes
+// whatever is left (7 items, 3 per row: 3, 3, 1)
+//
+// Parameters:
+//   - items: Items in display order
+//   - perRow: Row width (< 1 puts everything in one row)
+//
+// Returns:
+//   - [][]T: Rows (nil if items is empty)
+func groupRows[T any](items []T, perRow int) [][]T {
+	if len(items) == 0 {
+		return nil
+	}
+	if perRow < 1 {
+		perRow = len(items)
+	}
+
+	rows := make([][]T, 0, (len(items)+perRow-1)/perRow)
+	for len(items) > perRow {
+		rows = append(rows, items[:perRow:perRow])
+		items = items[perRow:]
+	}
+	return append(rows, items)
+}
+
+// mainButtonRows lays out the enabled main buttons mainButtonsPerRow per row
+//
+// Parameters:
+//   - features: Enabled features (cfg.Features)
+//   - includePrivate: false leaves out buttons of private features
+//
+// Returns:
+//   - [][]Button: Rows of buttons (nil if none is enabled)
+func mainButtonRows(features config.Features, includePrivate bool) [][]Button {
+	return groupRows(enabledButtons(mainButtons, features, includePrivate), mainButtonsPerRow)
+}
+
+// UserButtons returns the buttons a user can actually use, as keyboard rows
+// The /start keyboard and welcome text are both built from it, so they
+// always list the same features.
+//
+// Rules:
+//   - Disabled features (cfg.Features) have no button
+//   - Private buttons (🖥️ OVH Servers, admin rows) only for authorized users
+//   - Authorized users get the admin rows after the feature rows:
+//     🔵 Dedicated Servers (if OVH is enabled), then the admin buttons
+//
+// Parameters:
+//   - features: Enabled features (cfg.Features)
+//   - authorized: Whether the user is in ALLOWED_USERS (cfg.IsUserAllowed)
+//
+// Returns:
+//   - [][]Button: Rows of buttons (nil if the user has none)
+func UserButtons(features config.Features, authorized bool) [][]Button {
+	rows := mainButtonRows(features, authorized)
+	if authorized {
+		if featureRow := enabledButtons(adminFeatureButtons, features, true); len(featureRow) > 0 {
+			rows = append(rows, featureRow)
+		}
+		rows = append(rows, adminButtons)
+	}
+	return rows
+}
+
+// ButtonKeyboard turns rows of buttons into a reply keyboard
+//
+// Parameters:
+//   - rows: Buttons from UserButtons
+//
+// Returns ReplyKeyboardMarkup with the same layout (no rows if rows is empty)
+func ButtonKeyboard(rows [][]Button) tgbotapi.ReplyKeyboardMarkup {
+	labelRows := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		labelRows = append(labelRows, buttonLabels(row))
+	}
+	return newReplyKeyboard(labelRows)
+}
+
+// BuildKeyboard lays out button labels in rows of buttonsPerRow buttons
+// The last row holds the rest: 7 buttons, 3 per row gives rows of 3, 3 and 1.
+// Adding a button to a keyboard is then just adding a label to the list.
+//
+// Parameters:
+//   - buttons: Button labels in display order
+//   - buttonsPerRow: Row width (< 1 puts every button in one row)
+//
+// Returns ReplyKeyboardMarkup with the buttons (no rows if buttons is empty)
+func BuildKeyboard(buttons []string, buttonsPerRow int) tgbotapi.ReplyKeyboardMarkup {
+	return newReplyKeyboard(groupRows(buttons, buttonsPerRow))
+}
+
+// newReplyKeyboard builds a reply keyboard from rows of labels
 // Reply keyboard - persistent buttons displayed at the bottom of the screen
 // Unlike inline keyboard (buttons in messages), reply keyboard stays visible
 // and sends regular messages when buttons are clicked
-//
-// Features:
-//   - 🎲 Dice - Roll single die (1-6)
-//   - 🎲🎲 Double Dice - Roll two dice (2-12)
-//   - 🌀 Twister - Random Twister game move
-//   - 🖥️ OVH Servers - Check OVH server availability (private)
-//
-// Returns ReplyKeyboardMarkup with 2x2 button layout
-func GetMainKeyboard() tgbotapi.ReplyKeyboardMarkup {
-	// Create keyboard with 2 rows of 2 buttons each
-	keyboard := tgbotapi.NewReplyKeyboard(
-		// Row 1: Dice features
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🎲 Dice"),
-			tgbotapi.NewKeyboardButton("🎲🎲 Double Dice"),
-		),
-		// Row 2: Other features
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🌀 Twister"),
-			tgbotapi.NewKeyboardButton("🖥️ OVH Servers"),
-		),
-	)
+func newReplyKeyboard(rows [][]string) tgbotapi.ReplyKeyboardMarkup {
+	keyboardRows := make([][]tgbotapi.KeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		var keyboardRow []tgbotapi.KeyboardButton
+		for _, label := range row {
+			keyboardRow = append(keyboardRow, tgbotapi.NewKeyboardButton(label))
+		}
+		keyboardRows = append(keyboardRows, keyboardRow)
+	}
+
+	keyboard := tgbotapi.NewReplyKeyboard(keyboardRows...)
 
 	// ResizeKeyboard optimizes button size for user's screen
 	// Without this, keyboard may be too large on mobile devices
@@ -67,3 +310,60 @@ func GetMainKeyboard() tgbotapi.ReplyKeyboardMarkup {
 
 	return keyboard
 }
+
+// GetUserKeyboard returns the reply keyboard for one user (see UserButtons)
+//
+// Parameters:
+//   - features: Enabled features (cfg.Features)
+//   - authorized: Whether the user is in ALLOWED_USERS
+//
+// Returns ReplyKeyboardMarkup with the user's buttons
+func GetUserKeyboard(features config.Features, authorized bool) tgbotapi.ReplyKeyboardMarkup {
+	return ButtonKeyboard(UserButtons(features, authorized))
+}
+
+// GetMainKeyboard returns a reply keyboard with all enabled bot features
+// Used where the audience is mixed (group greetings); /start and /menu use
+// GetUserKeyboard instead.
+//
+// Features (each only if enabled in features, see config.Features):
+//   - 🎲 Dice - Roll single die (1-6)
+//   - 🎲🎲 Double Dice - Roll two dice (2-12)
+//   - 🌀 Twister - Random Twister game move
+//   - 🖥️ OVH Servers - Check OVH server availability (private)
+//
+// Parameters:
+//   - features: Enabled features (cfg.Features)
+//
+// Returns ReplyKeyboardMarkup with enabled buttons, mainButtonsPerRow per row
+// (2x2 with everything enabled; no rows at all if everything is disabled)
+func GetMainKeyboard(features config.Features) tgbotapi.ReplyKeyboardMarkup {
+	return BuildKeyboard(buttonLabels(enabledButtons(mainButtons, features, true)), mainButtonsPerRow)
+}
+
+// GetAdminKeyboard returns the main keyboard plus rows of admin-only buttons
+// Shown to users in ALLOWED_USERS so they can see what extra features they have
+//
+// Features (in addition to GetMainKeyboard):
+//   - 🔵 Dedicated Servers - Check dedicated server availability (if OVH is enabled)
+//   - 📊 Stats - Bot runtime statistics
+//   - 📢 Broadcast - Message all known chats
+//   - ⚙️ Settings - Current bot settings
+//
+// Note: hiding buttons is not security - handlers still check authorization,
+// because anyone can type "📊 Stats" by hand
+//
+// Parameters:
+//   - features: Enabled features (cfg.Features)
+//
+// Returns ReplyKeyboardMarkup with the main layout + 1x1 dedicated row + 1x3 admin row
+// (the same layout as GetUserKeyboard for an authorized user)
+func GetAdminKeyboard(features config.Features) tgbotapi.ReplyKeyboardMarkup {
+	keyboard := GetMainKeyboard(features)
+
+	// Each admin section is one full row (an empty section adds no row)
+	for _, section := range [][]Button{enabledButtons(adminFeatureButtons, features, true), adminButtons} {
+		keyboard.Keyboard = append(keyboard.Keyboard, BuildKeyboard(buttonLabels(section), len(section)).Keyboard...)
+	}
+	return keyboard
+}
@@ -0,0 +1,267 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestGetAdminKeyboard verifies the admin keyboard extends the main keyboard
+// with the dedicated servers row and the row of admin buttons
+func TestGetAdminKeyboard(t *testing.T) {
+	main := GetMainKeyboard(config.AllFeatures())
+	admin := GetAdminKeyboard(config.AllFeatures())
+
+	if len(admin.Keyboard) != len(main.Keyboard)+2 {
+		t.Fatalf("admin keyboard has %d rows, want %d", len(admin.Keyboard), len(main.Keyboard)+2)
+	}
+
+	// Main rows must be identical
+	for i, row := range main.Keyboard {
+		for j, button := range row {
+			if admin.Keyboard[i][j].Text != button.Text {
+				t.Errorf("row %d button %d = %q, want %q", i, j, admin.Keyboard[i][j].Text, button.Text)
+			}
+		}
+	}
+
+	// Dedicated servers row, then admin buttons
+	if dedicated := admin.Keyboard[len(main.Keyboard)]; len(dedicated) != 1 || dedicated[0].Text != "🔵 Dedicated Servers" {
+		t.Errorf("row %d = %+v, want [🔵 Dedicated Servers]", len(main.Keyboard), dedicated)
+	}
+	want := []string{"📊 Stats", "📢 Broadcast", "⚙️ Settings"}
+	last := admin.Keyboard[len(admin.Keyboard)-1]
+	if len(last) != len(want) {
+		t.Fatalf("admin row has %d buttons, want %d", len(last), len(want))
+	}
+	for i, text := range want {
+		if last[i].Text != text {
+			t.Errorf("admin button %d = %q, want %q", i, last[i].Text, text)
+		}
+	}
+
+	if !admin.ResizeKeyboard {
+		t.Errorf("admin keyboard ResizeKeyboard = false, want true")
+	}
+
+	// /start gives authorized users the same layout
+	user := GetUserKeyboard(config.AllFeatures(), true)
+	if !slices.EqualFunc(admin.Keyboard, user.Keyboard, slices.Equal[[]tgbotapi.KeyboardButton]) {
+		t.Errorf("GetAdminKeyboard() = %v, want GetUserKeyboard(authorized) = %v", admin.Keyboard, user.Keyboard)
+	}
+}
+
+// TestUserButtons tests which buttons each user gets
+//
+// Cases:
+//   - Authorized: every enabled feature plus the admin rows
+//   - Authorized with OVH disabled: no 🔵 Dedicated Servers row
+//   - Unauthorized: private buttons (OVH, admin row) are left out
+//   - Nothing usable: no rows
+func TestUserButtons(t *testing.T) {
+	tests := []struct {
+		name       string
+		features   config.Features
+		authorized bool
+		wantRows   [][]string
+	}{
+		{
+			name:       "authorized",
+			features:   config.AllFeatures(),
+			authorized: true,
+			wantRows:   [][]string{{"🎲 Dice", "🎲🎲 Double Dice"}, {"🌀 Twister", "🖥️ OVH Servers"}, {"🔵 Dedicated Servers"}, {"📊 Stats", "📢 Broadcast", "⚙️ Settings"}},
+		},
+		{
+			name:       "authorized, OVH disabled",
+			features:   config.Features{Dice: true},
+			authorized: true,
+			wantRows:   [][]string{{"🎲 Dice"}, {"📊 Stats", "📢 Broadcast", "⚙️ Settings"}},
+		},
+		{
+			name:     "unauthorized",
+			features: config.AllFeatures(),
+			wantRows: [][]string{{"🎲 Dice", "🎲🎲 Double Dice"}, {"🌀 Twister"}},
+		},
+		{
+			name:     "unauthorized, only OVH enabled",
+			features: config.Features{OVH: true},
+			wantRows: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRows [][]string
+			for _, row := range UserButtons(tt.features, tt.authorized) {
+				var texts []string
+				for _, button := range row {
+					if button.Description == "" || button.Key == "" {
+						t.Errorf("button %q has no description or key", button.Text)
+					}
+					texts = append(texts, button.Text)
+				}
+				gotRows = append(gotRows, texts)
+			}
+
+			if !slices.EqualFunc(gotRows, tt.wantRows, slices.Equal[[]string]) {
+				t.Errorf("UserButtons() rows = %v, want %v", gotRows, tt.wantRows)
+			}
+		})
+	}
+}
+
+// TestMessageType tests type names for listed and unlisted Chattables
+func TestMessageType(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        tgbotapi.Chattable
+		expected string
+	}{
+		{name: "text", c: tgbotapi.NewMessage(1, "hi"), expected: "MessageConfig"},
+		{name: "chat action", c: tgbotapi.NewChatAction(1, tgbotapi.ChatTyping), expected: "ChatActionConfig"},
+		{name: "edit text", c: tgbotapi.NewEditMessageText(1, 2, "hi"), expected: "EditMessageTextConfig"},
+		{name: "callback", c: tgbotapi.NewCallback("id", ""), expected: "CallbackConfig"},
+		{name: "unlisted type", c: tgbotapi.NewDeleteMessage(1, 2), expected: "DeleteMessageConfig"},
+		{name: "nil", c: nil, expected: "nil"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessageType(tt.c); got != tt.expected {
+				t.Errorf("MessageType() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGetMainKeyboard_Features tests that disabled features are left out
+// and the remaining buttons are laid out two per row
+func TestGetMainKeyboard_Features(t *testing.T) {
+	tests := []struct {
+		name     string
+		features config.Features
+		wantRows [][]string
+	}{
+		{
+			name:     "all enabled",
+			features: config.AllFeatures(),
+			wantRows: [][]string{{"🎲 Dice", "🎲🎲 Double Dice"}, {"🌀 Twister", "🖥️ OVH Servers"}},
+		},
+		{
+			name:     "twister disabled",
+			features: config.Features{Dice: true, DoubleDice: true, OVH: true},
+			wantRows: [][]string{{"🎲 Dice", "🎲🎲 Double Dice"}, {"🖥️ OVH Servers"}},
+		},
+		{
+			name:     "only OVH",
+			features: config.Features{OVH: true},
+			wantRows: [][]string{{"🖥️ OVH Servers"}},
+		},
+		{
+			name:     "all disabled",
+			features: config.Features{},
+			wantRows: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyboard := GetMainKeyboard(tt.features)
+
+			var gotRows [][]string
+			for _, row := range keyboard.Keyboard {
+				var texts []string
+				for _, button := range row {
+					texts = append(texts, button.Text)
+				}
+				gotRows = append(gotRows, texts)
+			}
+
+			if !slices.EqualFunc(gotRows, tt.wantRows, slices.Equal[[]string]) {
+				t.Errorf("GetMainKeyboard() rows = %v, want %v", gotRows, tt.wantRows)
+			}
+		})
+	}
+}
+
+// TestBuildKeyboard tests that labels are grouped into rows of the given width
+//
+// Cases:
+//   - 7 buttons, 3 per row: rows of 3, 3 and 1
+//   - Exact multiple: no short last row
+//   - Fewer buttons than the width: one short row
+//   - Width below 1: everything in one row
+//   - No buttons: no rows
+func TestBuildKeyboard(t *testing.T) {
+	seven := []string{"1", "2", "3", "4", "5", "6", "7"}
+
+	tests := []struct {
+		name          string
+		buttons       []string
+		buttonsPerRow int
+		wantRows      [][]string
+	}{
+		{name: "last row shorter", buttons: seven, buttonsPerRow: 3, wantRows: [][]string{{"1", "2", "3"}, {"4", "5", "6"}, {"7"}}},
+		{name: "exact multiple", buttons: seven[:4], buttonsPerRow: 2, wantRows: [][]string{{"1", "2"}, {"3", "4"}}},
+		{name: "one short row", buttons: seven[:2], buttonsPerRow: 3, wantRows: [][]string{{"1", "2"}}},
+		{name: "one per row", buttons: seven[:2], buttonsPerRow: 1, wantRows: [][]string{{"1"}, {"2"}}},
+		{name: "zero width", buttons: seven[:3], buttonsPerRow: 0, wantRows: [][]string{{"1", "2", "3"}}},
+		{name: "no buttons", buttons: nil, buttonsPerRow: 3, wantRows: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyboard := BuildKeyboard(tt.buttons, tt.buttonsPerRow)
+
+			var gotRows [][]string
+			for _, row := range keyboard.Keyboard {
+				var texts []string
+				for _, button := range row {
+					texts = append(texts, button.Text)
+				}
+				gotRows = append(gotRows, texts)
+			}
+
+			if !slices.EqualFunc(gotRows, tt.wantRows, slices.Equal[[]string]) {
+				t.Errorf("BuildKeyboard(%d buttons, %d) rows = %v, want %v", len(tt.buttons), tt.buttonsPerRow, gotRows, tt.wantRows)
+			}
+			if !keyboard.ResizeKeyboard || keyboard.OneTimeKeyboard {
+				t.Errorf("ResizeKeyboard = %v, OneTimeKeyboard = %v, want true, false", keyboard.ResizeKeyboard, keyboard.OneTimeKeyboard)
+			}
+		})
+	}
+}
+
+// TestSendPhotoFromURL verifies the PhotoConfig sent for a photo URL
+//
+// Checks:
+//   - Chat ID and caption are set
+//   - The file is a FileURL (Telegram downloads it; nothing is uploaded)
+//   - ParseMode stays empty: captions are plain text
+func TestSendPhotoFromURL(t *testing.T) {
+	sender := &fakeSender{}
+	const photoURL = "https://example.com/map.png"
+
+	if err := SendPhotoFromURL(sender, -100123, photoURL, "OVH datacenters"); err != nil {
+		t.Fatalf("SendPhotoFromURL() error = %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d Chattables, want 1", len(sender.sent))
+	}
+	photo, ok := sender.sent[0].(tgbotapi.PhotoConfig)
+	if !ok {
+		t.Fatalf("sent %T, want tgbotapi.PhotoConfig", sender.sent[0])
+	}
+	if photo.ChatID != -100123 {
+		t.Errorf("ChatID = %d, want -100123", photo.ChatID)
+	}
+	if photo.Caption != "OVH datacenters" || photo.ParseMode != "" {
+		t.Errorf("Caption = %q, ParseMode = %q; want the caption as plain text", photo.Caption, photo.ParseMode)
+	}
+	if file, ok := photo.File.(tgbotapi.FileURL); !ok || string(file) != photoURL {
+		t.Errorf("File = %#v, want tgbotapi.FileURL(%q)", photo.File, photoURL)
+	}
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// RegisterCommands sets the command menu Telegram shows when a user types "/"
+// (setMyCommands API method). Replaces the previous list for the same scope.
+//
+// Scopes:
+//   - No chatIDs: default scope, the menu every user sees
+//   - With chatIDs: a separate menu per chat (e.g., authorized users' private
+//     chats, where the chat ID equals the user ID), overriding the default there
+//
+// Parameters:
+//   - sender: Bot sender (setMyCommands returns True, not a Message, so Request is used)
+//   - commands: Menu entries (see handlers.BotCommands)
+//   - chatIDs: Optional chats to set the menu for instead of the default scope
+//
+// Returns:
+//   - error: First error from Telegram (remaining chats are still tried)
+func RegisterCommands(sender BotSender, commands []tgbotapi.BotCommand, chatIDs ...int64) error {
+	if len(chatIDs) == 0 {
+		if _, err := sender.Request(tgbotapi.NewSetMyCommands(commands...)); err != nil {
+			return fmt.Errorf("failed to set bot commands: %w", err)
+		}
+		return nil
+	}
+
+	var firstErr error
+	for _, chatID := range chatIDs {
+		scope := tgbotapi.NewBotCommandScopeChat(chatID)
+		if _, err := sender.Request(tgbotapi.NewSetMyCommandsWithScope(scope, commands...)); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to set bot commands for chat %d: %w", chatID, err)
+		}
+	}
+	return firstErr
+}
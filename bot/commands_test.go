@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestRegisterCommands tests default-scope and per-chat command menus
+func TestRegisterCommands(t *testing.T) {
+	commands := []tgbotapi.BotCommand{
+		{Command: "start", Description: "Start the bot"},
+		{Command: "help", Description: "Show help"},
+	}
+
+	t.Run("default scope", func(t *testing.T) {
+		sender := &fakeSender{}
+		if err := RegisterCommands(sender, commands); err != nil {
+			t.Fatalf("RegisterCommands() unexpected error: %v", err)
+		}
+
+		if len(sender.requested) != 1 {
+			t.Fatalf("got %d requests, want 1", len(sender.requested))
+		}
+		got, ok := sender.requested[0].(tgbotapi.SetMyCommandsConfig)
+		if !ok {
+			t.Fatalf("request is %T, want SetMyCommandsConfig", sender.requested[0])
+		}
+		if got.Scope != nil || len(got.Commands) != 2 {
+			t.Errorf("got scope %+v with %d commands, want default scope with 2", got.Scope, len(got.Commands))
+		}
+	})
+
+	t.Run("per chat", func(t *testing.T) {
+		sender := &fakeSender{}
+		if err := RegisterCommands(sender, commands, 111, 222); err != nil {
+			t.Fatalf("RegisterCommands() unexpected error: %v", err)
+		}
+
+		if len(sender.requested) != 2 {
+			t.Fatalf("got %d requests, want 2 (one per chat)", len(sender.requested))
+		}
+		for i, wantChat := range []int64{111, 222} {
+			got := sender.requested[i].(tgbotapi.SetMyCommandsConfig)
+			if got.Scope == nil || got.Scope.Type != "chat" || got.Scope.ChatID != wantChat {
+				t.Errorf("request %d scope = %+v, want chat %d", i, got.Scope, wantChat)
+			}
+		}
+	})
+}
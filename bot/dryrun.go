@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dryRunHistory is how many payloads a LoggingSender keeps for Sent
+// Older ones are dropped, so a long dry run doesn't grow memory forever.
+const dryRunHistory = 100
+
+// SentPayload is one call a LoggingSender logged instead of making
+type SentPayload struct {
+	Method    string // Chattable type (MessageType) or raw API method (MakeRequest)
+	ChatID    int64  // Target chat (0 if the call has none, e.g. setMyCommands)
+	Text      string // Message text, or caption of photos and documents
+	ParseMode string // "MarkdownV2", "HTML" or "" (plain text)
+}
+
+// LoggingSender is a BotSender that logs outgoing calls instead of calling
+// Telegram (DRY_RUN)
+//
+// Why?
+//   - Test the full routing and formatting path against production config
+//     without messaging real chats
+//
+// Behavior:
+//   - Send logs the chat, text and parse mode, and returns a made-up Message
+//     (increasing IDs, so later edits of a status message still "work")
+//   - Request and MakeRequest log the call and report success
+//   - The last dryRunHistory payloads are kept, see Sent
+//
+// Safe for concurrent use: updates are handled in parallel.
+type LoggingSender struct {
+	mu     sync.Mutex
+	nextID int
+	sent   []SentPayload
+}
+
+// NewLoggingSender creates a LoggingSender
+//
+// Returns:
+//   - *LoggingSender: Sender implementing BotSender and RawRequester
+func NewLoggingSender() *LoggingSender {
+	return &LoggingSender{}
+}
+
+// Send logs a message instead of sending it
+// The returned Message has the chat, text and a new ID, as Telegram's would.
+func (s *LoggingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	payload := payloadOf(c)
+	id := s.record(payload)
+
+	slog.Info("DRY_RUN: message not sent",
+		"method", payload.Method,
+		"chat_id", payload.ChatID,
+		"parse_mode", payload.ParseMode,
+		"text", payload.Text)
+
+	return tgbotapi.Message{
+		MessageID: id,
+		Chat:      &tgbotapi.Chat{ID: payload.ChatID},
+		Text:      payload.Text,
+		Date:      int(time.Now().Unix()),
+	}, nil
+}
+
+// Request logs a call instead of making it (answerCallbackQuery, setWebhook, ...)
+func (s *LoggingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	payload := payloadOf(c)
+	s.record(payload)
+
+	slog.Info("DRY_RUN: request not sent",
+		"method", payload.Method,
+		"chat_id", payload.ChatID)
+
+	return &tgbotapi.APIResponse{Ok: true, Result: json.RawMessage("true")}, nil
+}
+
+// MakeRequest logs a raw API call instead of making it (setMessageReaction,
+// setChatMenuButton, see RawRequester)
+func (s *LoggingSender) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	s.record(SentPayload{Method: endpoint})
+
+	slog.Info("DRY_RUN: request not sent",
+		"method", endpoint,
+		"params", params)
+
+	return &tgbotapi.APIResponse{Ok: true, Result: json.RawMessage("true")}, nil
+}
+
+// Sent returns the payloads logged so far, oldest first (at most dryRunHistory)
+func (s *LoggingSender) Sent() []SentPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]SentPayload(nil), s.sent...)
+}
+
+// record keeps a payload and returns the next made-up message ID
+func (s *LoggingSender) record(payload SentPayload) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.sent) == dryRunHistory {
+		s.sent = append(s.sent[:0], s.sent[1:]...)
+	}
+	s.sent = append(s.sent, payload)
+	s.nextID++
+	return s.nextID
+}
+
+// payloadOf extracts what a dry run logs from a Chattable
+// Types without a chat or text (e.g., setMyCommands) only get their method.
+func payloadOf(c tgbotapi.Chattable) SentPayload {
+	payload := SentPayload{Method: MessageType(c)}
+
+	switch v := c.(type) {
+	case tgbotapi.MessageConfig:
+		payload.ChatID, payload.Text, payload.ParseMode = v.ChatID, v.Text, v.ParseMode
+	case tgbotapi.EditMessageTextConfig:
+		payload.ChatID, payload.Text, payload.ParseMode = v.ChatID, v.Text, v.ParseMode
+	case tgbotapi.PhotoConfig:
+		payload.ChatID, payload.Text, payload.ParseMode = v.ChatID, v.Caption, v.ParseMode
+	case tgbotapi.DocumentConfig:
+		payload.ChatID, payload.Text, payload.ParseMode = v.ChatID, v.Caption, v.ParseMode
+	case tgbotapi.DiceConfig:
+		payload.ChatID = v.ChatID
+	case tgbotapi.ChatActionConfig:
+		payload.ChatID = v.ChatID
+	case tgbotapi.DeleteMessageConfig:
+		payload.ChatID = v.ChatID
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		payload.ChatID = v.ChatID
+	}
+	return payload
+}
@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestLoggingSender tests that a dry run records what would have been sent
+//
+// Checks:
+//   - Send returns a Message with the chat, text and increasing IDs
+//   - Chat, text and parse mode are recorded for messages, edits and documents
+//   - Request and MakeRequest report success and record their method
+//   - Wrapped in MarkdownCheckingSender, invalid MarkdownV2 is recorded as plain text
+func TestLoggingSender(t *testing.T) {
+	sender := NewLoggingSender()
+
+	msg := tgbotapi.NewMessage(42, "*Hello*")
+	msg.ParseMode = tgfmt.ParseMode
+	first, err := sender.Send(msg)
+	if err != nil || first.MessageID != 1 || first.Chat.ID != 42 || first.Text != "*Hello*" {
+		t.Fatalf("Send() = %+v, %v, want message 1 in chat 42", first, err)
+	}
+
+	second, _ := sender.Send(tgbotapi.NewEditMessageText(42, first.MessageID, "Done"))
+	if second.MessageID != 2 {
+		t.Errorf("second MessageID = %d, want 2", second.MessageID)
+	}
+
+	document := tgbotapi.NewDocument(7, tgbotapi.FileBytes{Name: "offers.csv", Bytes: []byte("a,b")})
+	document.Caption = "Offers"
+	if _, err := sender.Send(document); err != nil {
+		t.Fatalf("Send(document) unexpected error: %v", err)
+	}
+
+	resp, err := sender.Request(tgbotapi.NewCallback("query-id", ""))
+	if err != nil || !resp.Ok {
+		t.Errorf("Request() = %+v, %v, want ok", resp, err)
+	}
+	if resp, err := sender.MakeRequest("setMessageReaction", tgbotapi.Params{"chat_id": "42"}); err != nil || !resp.Ok {
+		t.Errorf("MakeRequest() = %+v, %v, want ok", resp, err)
+	}
+
+	checking := NewMarkdownCheckingSender(sender, false)
+	invalid := tgbotapi.NewMessage(42, "1. Not escaped")
+	invalid.ParseMode = tgfmt.ParseMode
+	if _, err := checking.Send(invalid); err != nil {
+		t.Errorf("lenient Send() unexpected error: %v", err)
+	}
+
+	want := []SentPayload{
+		{Method: "MessageConfig", ChatID: 42, Text: "*Hello*", ParseMode: "MarkdownV2"},
+		{Method: "EditMessageTextConfig", ChatID: 42, Text: "Done"},
+		{Method: "DocumentConfig", ChatID: 7, Text: "Offers"},
+		{Method: "CallbackConfig"},
+		{Method: "setMessageReaction"},
+		{Method: "MessageConfig", ChatID: 42, Text: "1. Not escaped"},
+	}
+	got := sender.Sent()
+	if len(got) != len(want) {
+		t.Fatalf("Sent() = %+v, want %d payloads", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Sent()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestLoggingSender_History tests that only the last dryRunHistory payloads are kept
+func TestLoggingSender_History(t *testing.T) {
+	sender := NewLoggingSender()
+	for i := range dryRunHistory + 5 {
+		sender.Send(tgbotapi.NewMessage(1, fmt.Sprint(i)))
+	}
+
+	got := sender.Sent()
+	if len(got) != dryRunHistory || got[0].Text != "5" || got[len(got)-1].Text != fmt.Sprint(dryRunHistory+4) {
+		t.Errorf("Sent() has %d payloads from %q to %q, want %d from \"5\"", len(got), got[0].Text, got[len(got)-1].Text, dryRunHistory)
+	}
+}
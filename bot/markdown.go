@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MarkdownCheckingSender wraps a BotSender and validates MarkdownV2 messages before sending
+//
+// Why?
+//   - Telegram rejects a whole message if a single reserved character isn't escaped
+//   - The error only shows up at runtime, often in a rarely used code path
+//
+// Modes:
+//   - strict (development): invalid messages are not sent, Send returns the validation error
+//   - lenient (production): a warning is logged and the message is sent as plain text,
+//     so the user still gets an answer (with visible backslashes) instead of nothing
+//
+// Only MessageConfig values with ParseMode "MarkdownV2" are checked,
+// everything else is passed through unchanged.
+type MarkdownCheckingSender struct {
+	next   BotSender
+	strict bool
+}
+
+// NewMarkdownCheckingSender creates a MarkdownCheckingSender
+//
+// Parameters:
+//   - next: Sender that actually talks to Telegram (usually *tgbotapi.BotAPI)
+//   - strict: true to fail on invalid MarkdownV2, false to downgrade to plain text
+//
+// Returns:
+//   - *MarkdownCheckingSender: Wrapper implementing BotSender
+func NewMarkdownCheckingSender(next BotSender, strict bool) *MarkdownCheckingSender {
+	return &MarkdownCheckingSender{next: next, strict: strict}
+}
+
+// Send validates MarkdownV2 text messages and forwards them to the wrapped sender
+func (s *MarkdownCheckingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, ok := c.(tgbotapi.MessageConfig)
+	if !ok || msg.ParseMode != tgfmt.ParseMode {
+		return s.next.Send(c)
+	}
+
+	if err := tgfmt.ValidateMarkdownV2(msg.Text); err != nil {
+		if s.strict {
+			slog.Error("Refusing to send invalid MarkdownV2 message",
+				"error", err,
+				"chat_id", msg.ChatID,
+				"text", msg.Text)
+			return tgbotapi.Message{}, fmt.Errorf("message not sent: %w", err)
+		}
+
+		slog.Warn("Invalid MarkdownV2 message, sending as plain text",
+			"error", err,
+			"chat_id", msg.ChatID)
+		msg.ParseMode = ""
+		return s.next.Send(msg)
+	}
+
+	return s.next.Send(msg)
+}
+
+// Request forwards to the wrapped sender unchanged
+func (s *MarkdownCheckingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return s.next.Request(c)
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeSender records what reaches the "real" sender
+type fakeSender struct {
+	sent      []tgbotapi.Chattable
+	requested []tgbotapi.Chattable
+}
+
+func (f *fakeSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.sent = append(f.sent, c)
+	return tgbotapi.Message{}, nil
+}
+
+func (f *fakeSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	f.requested = append(f.requested, c)
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// TestMarkdownCheckingSender tests strict and lenient modes
+//
+// Cases:
+//   - Valid MarkdownV2: forwarded unchanged in both modes
+//   - Invalid + strict: not forwarded, error wraps tgfmt.ErrInvalidMarkdownV2
+//   - Invalid + lenient: forwarded with ParseMode cleared
+//   - Non-MarkdownV2 message: forwarded unchanged even if text has reserved characters
+func TestMarkdownCheckingSender(t *testing.T) {
+	tests := []struct {
+		name          string
+		strict        bool
+		text          string
+		parseMode     string
+		wantErr       bool
+		wantForwarded bool
+		wantParseMode string
+	}{
+		{name: "valid strict", strict: true, text: "*12\\.99*", parseMode: "MarkdownV2", wantForwarded: true, wantParseMode: "MarkdownV2"},
+		{name: "valid lenient", strict: false, text: "*12\\.99*", parseMode: "MarkdownV2", wantForwarded: true, wantParseMode: "MarkdownV2"},
+		{name: "invalid strict", strict: true, text: "*12.99*", parseMode: "MarkdownV2", wantErr: true},
+		{name: "invalid lenient", strict: false, text: "*12.99*", parseMode: "MarkdownV2", wantForwarded: true, wantParseMode: ""},
+		{name: "plain text not checked", strict: true, text: "12.99!", parseMode: "", wantForwarded: true, wantParseMode: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &fakeSender{}
+			sender := NewMarkdownCheckingSender(next, tt.strict)
+
+			msg := tgbotapi.NewMessage(1, tt.text)
+			msg.ParseMode = tt.parseMode
+
+			_, err := sender.Send(msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, tgfmt.ErrInvalidMarkdownV2) {
+				t.Errorf("Send() error %v does not wrap tgfmt.ErrInvalidMarkdownV2", err)
+			}
+
+			if (len(next.sent) == 1) != tt.wantForwarded {
+				t.Fatalf("forwarded %d messages, wantForwarded %v", len(next.sent), tt.wantForwarded)
+			}
+			if tt.wantForwarded {
+				if got := next.sent[0].(tgbotapi.MessageConfig).ParseMode; got != tt.wantParseMode {
+					t.Errorf("forwarded ParseMode = %q, want %q", got, tt.wantParseMode)
+				}
+			}
+		})
+	}
+}
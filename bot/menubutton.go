@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Menu button types of the Bot API (MenuButton.Type)
+const (
+	// MenuButtonCommands opens the bot's command list (see RegisterCommands)
+	MenuButtonCommands = "commands"
+
+	// MenuButtonWebApp opens a Web App (MenuButton.WebAppURL)
+	MenuButtonWebApp = "web_app"
+
+	// MenuButtonDefault lets Telegram decide (currently the command list)
+	MenuButtonDefault = "default"
+)
+
+// MenuButton is the persistent button next to the message input in private chats
+//
+// Unlike the reply keyboard (GetMainKeyboard), it's always there: users
+// don't need /menu to bring it back after hiding the keyboard.
+type MenuButton struct {
+	// Type is MenuButtonCommands, MenuButtonWebApp or MenuButtonDefault
+	Type string `json:"type"`
+
+	// Text is the button label (MenuButtonWebApp only)
+	Text string `json:"text,omitempty"`
+
+	// WebAppURL is the HTTPS page to open (MenuButtonWebApp only)
+	WebAppURL string `json:"-"`
+}
+
+// MarshalJSON encodes the button in the Bot API format:
+// {"type":"web_app","text":"...","web_app":{"url":"..."}}
+func (b MenuButton) MarshalJSON() ([]byte, error) {
+	type webAppInfo struct {
+		URL string `json:"url"`
+	}
+	type menuButton struct {
+		Type   string      `json:"type"`
+		Text   string      `json:"text,omitempty"`
+		WebApp *webAppInfo `json:"web_app,omitempty"`
+	}
+
+	encoded := menuButton{Type: b.Type, Text: b.Text}
+	if b.Type == MenuButtonWebApp {
+		encoded.WebApp = &webAppInfo{URL: b.WebAppURL}
+	}
+	return json.Marshal(encoded)
+}
+
+// ConfigureMenuButton sets the default menu button of all private chats
+// (setChatMenuButton API method without chat_id). Safe to call on every start.
+//
+// Parameters:
+//   - api: Bot API (*tgbotapi.BotAPI); setChatMenuButton has no Chattable, see RawRequester
+//   - button: Button to show (e.g., MenuButton{Type: MenuButtonCommands})
+//
+// Returns:
+//   - error: If the button is invalid or Telegram rejected the request
+func ConfigureMenuButton(api RawRequester, button MenuButton) error {
+	switch button.Type {
+	case MenuButtonCommands, MenuButtonDefault:
+	case MenuButtonWebApp:
+		if button.Text == "" || button.WebAppURL == "" {
+			return fmt.Errorf("web app menu button needs a text and a URL")
+		}
+	default:
+		return fmt.Errorf("unknown menu button type: %q", button.Type)
+	}
+
+	encoded, err := json.Marshal(button)
+	if err != nil {
+		return fmt.Errorf("failed to encode menu button: %w", err)
+	}
+	if _, err := api.MakeRequest("setChatMenuButton", tgbotapi.Params{"menu_button": string(encoded)}); err != nil {
+		return fmt.Errorf("failed to set menu button: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestConfigureMenuButton tests the setChatMenuButton request for each button type
+//
+// Cases:
+//   - Commands and default buttons: only the type is sent
+//   - Web App button: text and web_app.url are sent
+//   - Invalid buttons are refused without calling Telegram
+//   - Telegram errors are returned
+func TestConfigureMenuButton(t *testing.T) {
+	tests := []struct {
+		name       string
+		button     MenuButton
+		apiErr     error
+		wantButton string // "" = no request expected
+		wantErr    bool
+	}{
+		{name: "commands", button: MenuButton{Type: MenuButtonCommands}, wantButton: `{"type":"commands"}`},
+		{name: "default", button: MenuButton{Type: MenuButtonDefault}, wantButton: `{"type":"default"}`},
+		{
+			name:       "web app",
+			button:     MenuButton{Type: MenuButtonWebApp, Text: "Servers", WebAppURL: "https://example.com/app"},
+			wantButton: `{"type":"web_app","text":"Servers","web_app":{"url":"https://example.com/app"}}`,
+		},
+		{name: "web app without URL", button: MenuButton{Type: MenuButtonWebApp, Text: "Servers"}, wantErr: true},
+		{name: "unknown type", button: MenuButton{Type: "keyboard"}, wantErr: true},
+		{
+			name:       "telegram error",
+			button:     MenuButton{Type: MenuButtonCommands},
+			apiErr:     errors.New("Bad Request"),
+			wantButton: `{"type":"commands"}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeRawRequester{err: tt.apiErr}
+
+			err := ConfigureMenuButton(api, tt.button)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigureMenuButton() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantButton == "" {
+				if len(api.endpoints) != 0 {
+					t.Errorf("made requests %v, want none for an invalid button", api.endpoints)
+				}
+				return
+			}
+			if len(api.endpoints) != 1 || api.endpoints[0] != "setChatMenuButton" {
+				t.Fatalf("requests = %v, want one setChatMenuButton", api.endpoints)
+			}
+			params := api.params[0]
+			if params["menu_button"] != tt.wantButton {
+				t.Errorf("menu_button = %s, want %s", params["menu_button"], tt.wantButton)
+			}
+			if _, ok := params["chat_id"]; ok {
+				t.Errorf("chat_id = %s, want none (default button for all private chats)", params["chat_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// RawRequester calls Bot API methods by name (*tgbotapi.BotAPI satisfies it)
+//
+// Why not BotSender?
+//   - Send and Request take a tgbotapi.Chattable, whose methods are unexported:
+//     only the library can define new request types
+//   - The library predates setMessageReaction and setChatMenuButton, so
+//     those are sent by name with their raw parameters instead
+type RawRequester interface {
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// React sets the bot's reaction on a message (setMessageReaction API method)
+//
+// A reaction is a lighter acknowledgment than a reply: the user sees
+// the emoji on their own message, and the chat gets no extra message.
+//
+// Only Telegram's fixed reaction set is accepted (👍 👌 🔥 👀 🎉 ...);
+// other emoji, such as 🎲, fail with "REACTION_INVALID". Clients without
+// reaction support simply don't show it.
+//
+// Parameters:
+//   - sender: Bot API (see RawRequester)
+//   - chatID: Chat of the message
+//   - messageID: Message to react to
+//   - emoji: Reaction emoji; "" removes the bot's reaction
+//
+// Returns:
+//   - error: If Telegram rejected the reaction (invalid emoji, message
+//     deleted, reactions disabled in the chat)
+func React(sender RawRequester, chatID int64, messageID int, emoji string) error {
+	type reactionType struct {
+		Type  string `json:"type"`
+		Emoji string `json:"emoji"`
+	}
+	reactions := []reactionType{}
+	if emoji != "" {
+		reactions = append(reactions, reactionType{Type: "emoji", Emoji: emoji})
+	}
+
+	encoded, err := json.Marshal(reactions)
+	if err != nil {
+		return fmt.Errorf("failed to encode reaction: %w", err)
+	}
+
+	params := tgbotapi.Params{
+		"chat_id":    strconv.FormatInt(chatID, 10),
+		"message_id": strconv.Itoa(messageID),
+		"reaction":   string(encoded),
+	}
+	if _, err := sender.MakeRequest("setMessageReaction", params); err != nil {
+		return fmt.Errorf("failed to react to message: %w", err)
+	}
+	return nil
+}
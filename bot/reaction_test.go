@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeRawRequester records raw API calls (MakeRequest)
+type fakeRawRequester struct {
+	endpoints []string
+	params    []tgbotapi.Params
+	err       error
+}
+
+func (f *fakeRawRequester) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	f.endpoints = append(f.endpoints, endpoint)
+	f.params = append(f.params, params)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// TestReact tests the setMessageReaction request
+//
+// Cases:
+//   - An emoji becomes a one-element reaction list
+//   - "" sends an empty list (removes the reaction)
+//   - Telegram errors are returned
+func TestReact(t *testing.T) {
+	tests := []struct {
+		name         string
+		emoji        string
+		apiErr       error
+		wantReaction string
+		wantErr      bool
+	}{
+		{name: "emoji", emoji: "👌", wantReaction: `[{"type":"emoji","emoji":"👌"}]`},
+		{name: "remove", emoji: "", wantReaction: `[]`},
+		{name: "telegram error", emoji: "🎲", apiErr: errors.New("Bad Request: REACTION_INVALID"), wantReaction: `[{"type":"emoji","emoji":"🎲"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeRawRequester{err: tt.apiErr}
+
+			err := React(api, -100123, 42, tt.emoji)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("React() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(api.endpoints) != 1 || api.endpoints[0] != "setMessageReaction" {
+				t.Fatalf("requests = %v, want one setMessageReaction", api.endpoints)
+			}
+			params := api.params[0]
+			if params["chat_id"] != "-100123" || params["message_id"] != "42" {
+				t.Errorf("chat_id, message_id = %s, %s; want -100123, 42", params["chat_id"], params["message_id"])
+			}
+			if params["reaction"] != tt.wantReaction {
+				t.Errorf("reaction = %s, want %s", params["reaction"], tt.wantReaction)
+			}
+		})
+	}
+}
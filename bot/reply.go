@@ -0,0 +1,28 @@
+package bot
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+// Reply creates a text message that answers the message replyToMsgID.
+//
+// Threading rules:
+//   - Groups and supergroups: the message replies to (quotes) replyToMsgID,
+//     so in a busy chat everyone sees which request the answer belongs to
+//   - Private chats: no reply reference - there's only one user, quoting is just noise
+//
+// Group and supergroup chat IDs are negative (private chats use the user ID,
+// which is positive), so the chat ID alone tells which rule applies.
+//
+// Parameters:
+//   - chatID: Chat to send to (message.Chat.ID)
+//   - replyToMsgID: Message being answered (message.MessageID)
+//   - text: Message text
+//
+// Returns:
+//   - tgbotapi.MessageConfig: Message ready to send (ParseMode etc. can still be set)
+func Reply(chatID, replyToMsgID int64, text string) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(chatID, text)
+	if chatID < 0 {
+		msg.ReplyToMessageID = int(replyToMsgID)
+	}
+	return msg
+}
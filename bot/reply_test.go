@@ -0,0 +1,29 @@
+package bot
+
+import "testing"
+
+// TestReply tests that only group and supergroup messages carry a reply reference
+func TestReply(t *testing.T) {
+	tests := []struct {
+		name          string
+		chatID        int64
+		wantReplyToID int
+	}{
+		{"private chat", 12345, 0},
+		{"group", -4567, 42},
+		{"supergroup", -1001234567890, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := Reply(tt.chatID, 42, "🎲")
+
+			if msg.ChatID != tt.chatID || msg.Text != "🎲" {
+				t.Errorf("Reply() = chat %d text %q, want chat %d text %q", msg.ChatID, msg.Text, tt.chatID, "🎲")
+			}
+			if msg.ReplyToMessageID != tt.wantReplyToID {
+				t.Errorf("ReplyToMessageID = %d, want %d", msg.ReplyToMessageID, tt.wantReplyToID)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"time"
+
+	"github.com/Alrem/run-tbot/status"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// StatusSender wraps a BotSender and records successful Telegram API calls
+// in a status.Registry (reported by the health endpoint)
+type StatusSender struct {
+	next     BotSender
+	registry *status.Registry
+}
+
+// NewStatusSender creates a StatusSender
+//
+// Parameters:
+//   - next: Sender that actually talks to Telegram (usually *tgbotapi.BotAPI)
+//   - registry: Where successes are recorded (usually status.Default)
+//
+// Returns:
+//   - *StatusSender: Wrapper implementing BotSender
+func NewStatusSender(next BotSender, registry *status.Registry) *StatusSender {
+	return &StatusSender{next: next, registry: registry}
+}
+
+// Send forwards to the wrapped sender and records the call if it succeeded
+func (s *StatusSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, err := s.next.Send(c)
+	if err == nil {
+		s.registry.RecordTelegramSuccess(time.Now())
+	}
+	return msg, err
+}
+
+// Request forwards to the wrapped sender and records the call if it succeeded
+func (s *StatusSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	resp, err := s.next.Request(c)
+	if err == nil {
+		s.registry.RecordTelegramSuccess(time.Now())
+	}
+	return resp, err
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/status"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// failingSender fails every call
+type failingSender struct{}
+
+func (failingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return tgbotapi.Message{}, errors.New("telegram down")
+}
+
+func (failingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return nil, errors.New("telegram down")
+}
+
+// TestStatusSender tests that only successful calls are recorded
+func TestStatusSender(t *testing.T) {
+	registry := status.NewRegistry()
+
+	failing := NewStatusSender(failingSender{}, registry)
+	_, _ = failing.Send(tgbotapi.NewMessage(1, "hi"))
+	_, _ = failing.Request(tgbotapi.NewCallback("id", ""))
+	if got := registry.Snapshot(time.Now()).LastTelegramSuccess; got != nil {
+		t.Fatalf("LastTelegramSuccess after failures = %v, want nil", got)
+	}
+
+	before := time.Now()
+	working := NewStatusSender(&fakeSender{}, registry)
+	if _, err := working.Send(tgbotapi.NewMessage(1, "hi")); err != nil {
+		t.Fatalf("Send() unexpected error: %v", err)
+	}
+	got := registry.Snapshot(time.Now()).LastTelegramSuccess
+	if got == nil || got.Before(before.Truncate(time.Second)) {
+		t.Errorf("LastTelegramSuccess after Send = %v, want >= %v", got, before)
+	}
+}
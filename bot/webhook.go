@@ -0,0 +1,195 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// WebhookURL joins the service's public base URL and the webhook path
+// If baseURL already ends with path (e.g., a full webhook URL), it is used as-is.
+//
+// Parameters:
+//   - baseURL: Public URL of the service (e.g., "https://run-tbot-xyz.run.app")
+//   - path: Webhook path (e.g., "/webhook")
+//
+// Returns:
+//   - string: Full webhook URL (e.g., "https://run-tbot-xyz.run.app/webhook")
+func WebhookURL(baseURL, path string) string {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if strings.HasSuffix(baseURL, path) {
+		return baseURL
+	}
+	return baseURL + path
+}
+
+// NewWebhookConfig builds the setWebhook request for our webhook endpoint
+//
+// drop_pending_updates:
+//   - Telegram keeps undelivered updates for up to 24 hours
+//   - After downtime, all of them arrive at once on the next start
+//   - With dropPendingUpdates=true Telegram discards them instead,
+//     so hours-old dice taps aren't answered after a redeploy
+//
+// allowed_updates:
+//   - Without it Telegram sends every update type (polls, reactions, ...)
+//   - Each one is a webhook request the bot ignores but Cloud Run bills
+//   - Empty list = Telegram keeps the previous setting, so pass it explicitly
+//
+// Parameters:
+//   - baseURL: Public URL of the service (WEBHOOK_URL)
+//   - path: Webhook path (WEBHOOK_PATH)
+//   - dropPendingUpdates: Discard updates queued while the bot was down (DROP_PENDING_UPDATES)
+//   - allowedUpdates: Update types to receive (ALLOWED_UPDATES)
+//
+// Returns:
+//   - tgbotapi.WebhookConfig: Config to pass to SetWebhook
+//   - error: If the resulting URL is invalid or not HTTPS (Telegram requires HTTPS)
+func NewWebhookConfig(baseURL, path string, dropPendingUpdates bool, allowedUpdates []string) (tgbotapi.WebhookConfig, error) {
+	webhook, err := tgbotapi.NewWebhook(WebhookURL(baseURL, path))
+	if err != nil {
+		return tgbotapi.WebhookConfig{}, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if webhook.URL.Scheme != "https" || webhook.URL.Host == "" {
+		return tgbotapi.WebhookConfig{}, fmt.Errorf("invalid webhook URL %q: must be an absolute https URL", baseURL)
+	}
+
+	webhook.DropPendingUpdates = dropPendingUpdates
+	webhook.AllowedUpdates = allowedUpdates
+	return webhook, nil
+}
+
+// SetWebhook registers the webhook with Telegram (setWebhook API method)
+// Replaces any previously registered webhook, so it's safe to call on every start.
+//
+// Parameters:
+//   - sender: Bot sender (setWebhook returns True, not a Message, so Request is used)
+//   - webhook: Config built by NewWebhookConfig
+//
+// Returns:
+//   - error: If Telegram rejected the request
+func SetWebhook(sender BotSender, webhook tgbotapi.WebhookConfig) error {
+	if _, err := sender.Request(webhook); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
+
+// WebhookAPI is the part of *tgbotapi.BotAPI needed to update an existing webhook
+// getWebhookInfo has no Chattable config in the library, so it can't go through BotSender.Request
+type WebhookAPI interface {
+	BotSender
+	GetWebhookInfo() (tgbotapi.WebhookInfo, error)
+}
+
+// ErrNoWebhook is returned by ConfigureAllowedUpdates when no webhook is registered
+var ErrNoWebhook = errors.New("no webhook registered")
+
+// ConfigureAllowedUpdates sets allowed_updates on the already registered webhook
+//
+// Why?
+//   - Without WEBHOOK_URL the webhook is registered manually (see README),
+//     usually without allowed_updates, so Telegram sends every update type
+//   - The Bot API can only change allowed_updates through setWebhook, so we
+//     read the current webhook and register it again with the same URL
+//
+// Kept from the current webhook: URL, max_connections and ip_address.
+// A self-signed certificate can't be read back: webhooks using one are left
+// untouched (re-registering without it would break delivery).
+//
+// Parameters:
+//   - api: Bot API (*tgbotapi.BotAPI satisfies WebhookAPI)
+//   - allowedUpdates: Update types to receive (e.g., handlers.AllowedUpdates())
+//
+// Returns:
+//   - bool: True if the webhook was updated (false if it already had this list)
+//   - error: ErrNoWebhook if no webhook is set, or any Telegram API error
+func ConfigureAllowedUpdates(api WebhookAPI, allowedUpdates []string) (bool, error) {
+	info, err := api.GetWebhookInfo()
+	if err != nil {
+		return false, fmt.Errorf("failed to get webhook info: %w", err)
+	}
+	if info.URL == "" {
+		return false, ErrNoWebhook
+	}
+	if slices.Equal(info.AllowedUpdates, allowedUpdates) {
+		return false, nil
+	}
+	if info.HasCustomCertificate {
+		return false, fmt.Errorf("webhook uses a custom certificate, set allowed_updates manually")
+	}
+
+	webhook, err := tgbotapi.NewWebhook(info.URL)
+	if err != nil {
+		return false, fmt.Errorf("invalid registered webhook URL: %w", err)
+	}
+	webhook.MaxConnections = info.MaxConnections
+	webhook.IPAddress = info.IPAddress
+	webhook.AllowedUpdates = allowedUpdates
+
+	if err := SetWebhook(api, webhook); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FlushPendingUpdates drops every queued update and keeps the webhook working
+//
+// Why?
+//   - Telegram queues updates for up to 24 hours while the bot is down
+//   - On the next deploy they all arrive at once: old dice taps, stale /ovh requests
+//   - deleteWebhook(drop_pending_updates=true) is the only API call that drops them
+//     without registering a webhook URL (DROP_PENDING_UPDATES needs WEBHOOK_URL)
+//
+// Steps:
+//  1. getWebhookInfo: remember the webhook and the pending count
+//  2. deleteWebhook with drop_pending_updates=true
+//  3. setWebhook again with the same URL, max_connections, ip_address and
+//     allowed_updates, so delivery continues (skipped if no webhook was set)
+//  4. getWebhookInfo again: dropped = pending before - pending after
+//
+// Webhooks with a self-signed certificate are refused before step 2: the
+// certificate can't be read back, so the webhook couldn't be restored.
+//
+// Parameters:
+//   - api: Bot API (*tgbotapi.BotAPI satisfies WebhookAPI)
+//
+// Returns:
+//   - int: Number of updates dropped (best effort: updates arriving meanwhile aren't counted)
+//   - error: Any Telegram API error; after a failed step 3 the webhook is NOT registered
+func FlushPendingUpdates(api WebhookAPI) (int, error) {
+	before, err := api.GetWebhookInfo()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook info: %w", err)
+	}
+	if before.HasCustomCertificate {
+		return 0, fmt.Errorf("webhook uses a custom certificate, flush pending updates manually")
+	}
+
+	if _, err := api.Request(tgbotapi.DeleteWebhookConfig{DropPendingUpdates: true}); err != nil {
+		return 0, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	if before.URL != "" {
+		webhook, err := tgbotapi.NewWebhook(before.URL)
+		if err != nil {
+			return 0, fmt.Errorf("invalid registered webhook URL: %w", err)
+		}
+		webhook.MaxConnections = before.MaxConnections
+		webhook.IPAddress = before.IPAddress
+		webhook.AllowedUpdates = before.AllowedUpdates
+		if err := SetWebhook(api, webhook); err != nil {
+			return 0, fmt.Errorf("webhook deleted but not restored: %w", err)
+		}
+	}
+
+	// Only for the count: the flush itself already succeeded
+	after, err := api.GetWebhookInfo()
+	if err != nil {
+		return before.PendingUpdateCount, nil
+	}
+	return max(before.PendingUpdateCount-after.PendingUpdateCount, 0), nil
+}
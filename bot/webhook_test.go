@@ -0,0 +1,270 @@
+package bot
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestNewWebhookConfig tests URL joining, validation and DROP_PENDING_UPDATES
+func TestNewWebhookConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		baseURL     string
+		path        string
+		dropPending bool
+		wantURL     string
+		wantErr     bool
+	}{
+		{name: "base URL", baseURL: "https://bot.run.app", path: "/webhook", wantURL: "https://bot.run.app/webhook"},
+		{name: "trailing slash", baseURL: "https://bot.run.app/", path: "/hook-7f3a9c", wantURL: "https://bot.run.app/hook-7f3a9c"},
+		{name: "full URL kept", baseURL: "https://bot.run.app/webhook", path: "/webhook", wantURL: "https://bot.run.app/webhook"},
+		{name: "drop pending updates", baseURL: "https://bot.run.app", path: "/webhook", dropPending: true, wantURL: "https://bot.run.app/webhook"},
+		{name: "http rejected", baseURL: "http://bot.run.app", path: "/webhook", wantErr: true},
+		{name: "relative rejected", baseURL: "bot.run.app", path: "/webhook", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook, err := NewWebhookConfig(tt.baseURL, tt.path, tt.dropPending, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWebhookConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := webhook.URL.String(); got != tt.wantURL {
+				t.Errorf("URL = %q, want %q", got, tt.wantURL)
+			}
+			if webhook.DropPendingUpdates != tt.dropPending {
+				t.Errorf("DropPendingUpdates = %v, want %v", webhook.DropPendingUpdates, tt.dropPending)
+			}
+		})
+	}
+}
+
+// TestSetWebhook tests that the webhook config reaches Telegram via Request,
+// including drop_pending_updates and allowed_updates
+func TestSetWebhook(t *testing.T) {
+	allowedUpdates := []string{"message", "callback_query"}
+	webhook, err := NewWebhookConfig("https://bot.run.app", "/webhook", true, allowedUpdates)
+	if err != nil {
+		t.Fatalf("NewWebhookConfig() unexpected error: %v", err)
+	}
+
+	sender := &fakeSender{}
+	if err := SetWebhook(sender, webhook); err != nil {
+		t.Fatalf("SetWebhook() unexpected error: %v", err)
+	}
+
+	if len(sender.requested) != 1 {
+		t.Fatalf("got %d requests, want 1", len(sender.requested))
+	}
+	got, ok := sender.requested[0].(tgbotapi.WebhookConfig)
+	if !ok {
+		t.Fatalf("request is %T, want WebhookConfig", sender.requested[0])
+	}
+	if !got.DropPendingUpdates {
+		t.Errorf("DropPendingUpdates not passed to setWebhook")
+	}
+	if !slices.Equal(got.AllowedUpdates, allowedUpdates) {
+		t.Errorf("AllowedUpdates = %v, want %v", got.AllowedUpdates, allowedUpdates)
+	}
+}
+
+// fakeWebhookAPI is a fakeSender that also answers getWebhookInfo
+//
+// infos are returned by successive GetWebhookInfo calls (the last one repeats).
+// failMethod makes Request fail for one API method (e.g., "deleteWebhook").
+type fakeWebhookAPI struct {
+	fakeSender
+	infos      []tgbotapi.WebhookInfo
+	infoCalls  int
+	failMethod string
+}
+
+func (f *fakeWebhookAPI) GetWebhookInfo() (tgbotapi.WebhookInfo, error) {
+	if len(f.infos) == 0 {
+		return tgbotapi.WebhookInfo{}, nil
+	}
+	info := f.infos[min(f.infoCalls, len(f.infos)-1)]
+	f.infoCalls++
+	return info, nil
+}
+
+func (f *fakeWebhookAPI) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	f.requested = append(f.requested, c)
+	if f.failMethod != "" && requestMethod(c) == f.failMethod {
+		return nil, errors.New("telegram error")
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// requestMethod names the Bot API method of the webhook requests used in these tests
+func requestMethod(c tgbotapi.Chattable) string {
+	switch c.(type) {
+	case tgbotapi.DeleteWebhookConfig:
+		return "deleteWebhook"
+	case tgbotapi.WebhookConfig:
+		return "setWebhook"
+	default:
+		return MessageType(c)
+	}
+}
+
+// TestConfigureAllowedUpdates tests updating allowed_updates on an existing webhook
+func TestConfigureAllowedUpdates(t *testing.T) {
+	allowedUpdates := []string{"message", "edited_message"}
+
+	tests := []struct {
+		name        string
+		info        tgbotapi.WebhookInfo
+		wantUpdated bool
+		wantErr     bool
+	}{
+		{
+			name:        "re-registered with same URL",
+			info:        tgbotapi.WebhookInfo{URL: "https://bot.run.app/webhook", MaxConnections: 10, AllowedUpdates: []string{"message"}},
+			wantUpdated: true,
+		},
+		{
+			name: "already configured",
+			info: tgbotapi.WebhookInfo{URL: "https://bot.run.app/webhook", AllowedUpdates: allowedUpdates},
+		},
+		{name: "no webhook", info: tgbotapi.WebhookInfo{}, wantErr: true},
+		{name: "custom certificate", info: tgbotapi.WebhookInfo{URL: "https://1.2.3.4/webhook", HasCustomCertificate: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeWebhookAPI{infos: []tgbotapi.WebhookInfo{tt.info}}
+
+			updated, err := ConfigureAllowedUpdates(api, allowedUpdates)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ConfigureAllowedUpdates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if updated != tt.wantUpdated {
+				t.Errorf("ConfigureAllowedUpdates() updated = %v, want %v", updated, tt.wantUpdated)
+			}
+			if !tt.wantUpdated {
+				if len(api.requested) != 0 {
+					t.Errorf("got %d requests, want none", len(api.requested))
+				}
+				return
+			}
+
+			if len(api.requested) != 1 {
+				t.Fatalf("got %d requests, want 1", len(api.requested))
+			}
+			got := api.requested[0].(tgbotapi.WebhookConfig)
+			if got.URL.String() != tt.info.URL || got.MaxConnections != tt.info.MaxConnections {
+				t.Errorf("webhook = %s (max %d), want %s (max %d)", got.URL, got.MaxConnections, tt.info.URL, tt.info.MaxConnections)
+			}
+			if !slices.Equal(got.AllowedUpdates, allowedUpdates) {
+				t.Errorf("AllowedUpdates = %v, want %v", got.AllowedUpdates, allowedUpdates)
+			}
+		})
+	}
+
+	// No webhook is reported as ErrNoWebhook, so callers can treat it as informational
+	if _, err := ConfigureAllowedUpdates(&fakeWebhookAPI{}, allowedUpdates); !errors.Is(err, ErrNoWebhook) {
+		t.Errorf("ConfigureAllowedUpdates() without webhook error = %v, want ErrNoWebhook", err)
+	}
+}
+
+// TestFlushPendingUpdates tests the delete/restore sequence and the dropped count
+//
+// Cases:
+//   - Registered webhook: deleted with drop_pending_updates, restored unchanged
+//   - No webhook: only deleteWebhook (drops getUpdates queue), nothing to restore
+//   - Custom certificate: refused before anything is deleted
+//   - deleteWebhook fails: error, webhook untouched
+//   - setWebhook fails: error (the webhook is gone, caller must know)
+func TestFlushPendingUpdates(t *testing.T) {
+	registered := tgbotapi.WebhookInfo{
+		URL:                "https://bot.run.app/webhook",
+		MaxConnections:     40,
+		AllowedUpdates:     []string{"message"},
+		PendingUpdateCount: 1200,
+	}
+	drained := tgbotapi.WebhookInfo{URL: registered.URL, PendingUpdateCount: 2}
+
+	tests := []struct {
+		name        string
+		infos       []tgbotapi.WebhookInfo
+		failMethod  string
+		wantDropped int
+		wantMethods []string
+		wantErr     bool
+	}{
+		{
+			name:        "registered webhook",
+			infos:       []tgbotapi.WebhookInfo{registered, drained},
+			wantDropped: 1198,
+			wantMethods: []string{"deleteWebhook", "setWebhook"},
+		},
+		{
+			name:        "no webhook",
+			infos:       []tgbotapi.WebhookInfo{{PendingUpdateCount: 5}, {}},
+			wantDropped: 5,
+			wantMethods: []string{"deleteWebhook"},
+		},
+		{
+			name:    "custom certificate",
+			infos:   []tgbotapi.WebhookInfo{{URL: "https://1.2.3.4/webhook", HasCustomCertificate: true}},
+			wantErr: true,
+		},
+		{
+			name:        "delete fails",
+			infos:       []tgbotapi.WebhookInfo{registered},
+			failMethod:  "deleteWebhook",
+			wantMethods: []string{"deleteWebhook"},
+			wantErr:     true,
+		},
+		{
+			name:        "restore fails",
+			infos:       []tgbotapi.WebhookInfo{registered},
+			failMethod:  "setWebhook",
+			wantMethods: []string{"deleteWebhook", "setWebhook"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeWebhookAPI{infos: tt.infos, failMethod: tt.failMethod}
+
+			dropped, err := FlushPendingUpdates(api)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FlushPendingUpdates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if dropped != tt.wantDropped {
+				t.Errorf("FlushPendingUpdates() dropped = %d, want %d", dropped, tt.wantDropped)
+			}
+
+			methods := make([]string, len(api.requested))
+			for i, c := range api.requested {
+				methods[i] = requestMethod(c)
+			}
+			if !slices.Equal(methods, tt.wantMethods) {
+				t.Fatalf("requests = %v, want %v", methods, tt.wantMethods)
+			}
+
+			if len(methods) > 0 {
+				if del := api.requested[0].(tgbotapi.DeleteWebhookConfig); !del.DropPendingUpdates {
+					t.Errorf("deleteWebhook without drop_pending_updates")
+				}
+			}
+			if len(methods) == 2 && !tt.wantErr {
+				restored := api.requested[1].(tgbotapi.WebhookConfig)
+				if restored.URL.String() != registered.URL || restored.MaxConnections != registered.MaxConnections ||
+					!slices.Equal(restored.AllowedUpdates, registered.AllowedUpdates) {
+					t.Errorf("restored webhook = %+v, want settings of %+v", restored, registered)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/handlers"
+	"github.com/Alrem/run-tbot/status"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// botInstance is one Telegram bot served by this process.
+//
+// A single BOT_TOKEN gives one instance with the shared config.
+// BOT_TOKENS gives one instance per bot, each with its own Config copy
+// (token, admins, webhook path WEBHOOK_PATH/<name>, username), see config.ForBot.
+// Everything else is shared: the OVH client and its cache, the in-memory
+// stores, the /metrics registry.
+type botInstance struct {
+	// name is the BOT_TOKENS name ("" for a single BOT_TOKEN bot)
+	name string
+
+	// sender delivers this bot's replies
+	sender bot.BotSender
+
+	// api is the unwrapped Bot API (getUpdates for POLLING); nil in tests
+	api *tgbotapi.BotAPI
+
+	// cfg is this bot's configuration, passed to every handler
+	cfg *config.Config
+}
+
+// label returns the bot name used in logs and metric labels
+// A single BOT_TOKEN bot has no name and is reported as "default"
+func (b botInstance) label() string {
+	if b.name == "" {
+		return "default"
+	}
+	return b.name
+}
+
+// botInstances returns one (not yet started) instance per configured bot
+//
+// Parameters:
+//   - cfg: Application configuration (BOT_TOKEN or BOT_TOKENS)
+//
+// Returns:
+//   - []botInstance: Instances with name and cfg set; sender is set by startBot
+func botInstances(cfg *config.Config) []botInstance {
+	if len(cfg.Bots) == 0 {
+		return []botInstance{{cfg: cfg}}
+	}
+
+	instances := make([]botInstance, 0, len(cfg.Bots))
+	for _, b := range cfg.Bots {
+		instances = append(instances, botInstance{name: b.Name, cfg: cfg.ForBot(b)})
+	}
+	return instances
+}
+
+// webhookPaths returns the webhook path of every configured bot
+// (used for rate limits and access log labels, which match exact paths)
+func webhookPaths(cfg *config.Config) []string {
+	if len(cfg.Bots) == 0 {
+		return []string{cfg.WebhookPath}
+	}
+
+	paths := make([]string, 0, len(cfg.Bots))
+	for _, b := range cfg.Bots {
+		paths = append(paths, config.BotWebhookPath(cfg.WebhookPath, b.Name))
+	}
+	return paths
+}
+
+// webhookPathLabels maps every webhook path to its access log label
+// The paths are secret: "<webhook>" for a single bot, "<webhook:name>" per bot of BOT_TOKENS
+func webhookPathLabels(cfg *config.Config) map[string]string {
+	if len(cfg.Bots) == 0 {
+		return map[string]string{cfg.WebhookPath: "<webhook>"}
+	}
+
+	labels := make(map[string]string, len(cfg.Bots))
+	for _, b := range cfg.Bots {
+		labels[config.BotWebhookPath(cfg.WebhookPath, b.Name)] = "<webhook:" + b.Name + ">"
+	}
+	return labels
+}
+
+// startBot connects one bot to Telegram and prepares its webhook.
+//
+// Steps:
+//  1. getMe: checks the token, fills cfg.BotUsername
+//  2. Wraps the bot in the MarkdownV2-checking, status-recording sender
+//  3. DROP_PENDING_ON_START, webhook registration (or allowed_updates update);
+//     with POLLING the webhook is deleted instead
+//  4. Registers the "/" command menu
+//
+// Only step 1 and webhook registration (WEBHOOK_URL) are fatal; the rest is
+// logged and startup continues, as for a single bot.
+//
+// DRY_RUN: after getMe, every call goes to a bot.LoggingSender instead of
+// Telegram, and pending updates are never dropped. The webhook is left as it
+// is, so with POLLING the bot only receives updates if no webhook is set.
+//
+// Parameters:
+//   - instance: Bot to start (name and cfg from botInstances)
+//   - allowedUpdates: Update types Telegram should deliver
+//
+// Returns:
+//   - botInstance: The instance with its sender set
+//   - error: If the bot can't be used
+func startBot(instance botInstance, allowedUpdates []string) (botInstance, error) {
+	cfg := instance.cfg
+	log := slog.Default()
+	if instance.name != "" {
+		log = log.With("bot", instance.name)
+	}
+
+	// cfg.IsDevelopment() enables debug mode which logs all HTTP requests/responses
+	// Useful for learning and debugging, but disable in production (verbose)
+	botAPI, err := bot.NewBot(cfg.BotToken, cfg.IsDevelopment())
+	if err != nil {
+		return instance, fmt.Errorf("failed to create bot: %w", err)
+	}
+
+	// Log bot info (bot.Self contains bot's username, ID, etc.)
+	log.Info("Bot authorized successfully",
+		"bot_username", botAPI.Self.UserName,
+		"bot_id", botAPI.Self.ID)
+
+	// Router needs our username to recognize /command@our_bot in group chats
+	cfg.BotUsername = botAPI.Self.UserName
+
+	// Wrap the bot so MarkdownV2 mistakes are caught before reaching Telegram
+	// STRICT_MARKDOWN (default on in development): invalid messages fail loudly
+	// Otherwise: warning is logged and the message is sent as plain text
+	// StatusSender records successful Telegram calls for the health endpoint
+	var next bot.BotSender = bot.NewStatusSender(botAPI, status.Default)
+	var raw bot.RawRequester = botAPI
+	if cfg.DryRun {
+		// DRY_RUN: every call below and every reply is logged instead of sent
+		// (getMe above was the only call made, it changes nothing)
+		dryRun := bot.NewLoggingSender()
+		next, raw = dryRun, dryRun
+		log.Warn("DRY_RUN: Telegram calls are logged, not sent")
+	}
+	sender := bot.NewMarkdownCheckingSender(next, cfg.StrictMarkdown)
+	instance.sender = sender
+	instance.api = botAPI
+
+	// /flushupdates needs getWebhookInfo, which the wrapped sender doesn't offer
+	// (not in a dry run: flushing drops real updates)
+	if !cfg.DryRun {
+		handlers.SetWebhookAPI(cfg.BotUsername, botAPI)
+	}
+	// Same for setMessageReaction (REACT_TO_REQUESTS)
+	handlers.SetReactionAPI(cfg.BotUsername, raw)
+
+	// POLLING: getUpdates doesn't work while a webhook is set, so delete it
+	// (DROP_PENDING_ON_START drops the queue in the same call)
+	// Fatal: without it no update would ever arrive
+	if cfg.Polling {
+		deleteWebhook := tgbotapi.DeleteWebhookConfig{DropPendingUpdates: cfg.DropPendingOnStart}
+		if _, err := sender.Request(deleteWebhook); err != nil {
+			return instance, fmt.Errorf("failed to delete webhook for polling: %w", err)
+		}
+		log.Info("Webhook deleted, receiving updates by polling",
+			"drop_pending_updates", cfg.DropPendingOnStart)
+	}
+
+	// DROP_PENDING_ON_START: throw away updates queued while the bot was down
+	// Not fatal: a Telegram failure here must not keep the bot from starting
+	if cfg.DropPendingOnStart && !cfg.Polling && !cfg.DryRun {
+		if dropped, err := bot.FlushPendingUpdates(botAPI); err != nil {
+			log.Warn("Failed to drop pending updates on start", "error", err)
+		} else {
+			log.Info("Dropped pending updates on start", "dropped", dropped)
+		}
+	}
+
+	// Register webhook with Telegram if WEBHOOK_URL is set
+	// Otherwise the webhook is expected to be registered manually (see README),
+	// and only its allowed_updates are brought in line with the router
+	switch {
+	case cfg.Polling:
+		// Nothing to register
+	case cfg.DryRun && cfg.WebhookURL == "":
+		// Leave the real webhook's allowed_updates alone
+	case cfg.WebhookURL != "":
+		webhook, err := bot.NewWebhookConfig(cfg.WebhookURL, cfg.WebhookPath, cfg.DropPendingUpdates, allowedUpdates)
+		if err == nil {
+			err = bot.SetWebhook(sender, webhook)
+		}
+		if err != nil {
+			return instance, fmt.Errorf("failed to register webhook: %w", err)
+		}
+		log.Info("Webhook registered",
+			"drop_pending_updates", cfg.DropPendingUpdates,
+			"allowed_updates", allowedUpdates)
+	default:
+		if cfg.DropPendingUpdates {
+			log.Warn("DROP_PENDING_UPDATES has no effect without WEBHOOK_URL")
+		}
+		// Not fatal: the webhook still works, it just receives extra update types
+		updated, err := bot.ConfigureAllowedUpdates(botAPI, allowedUpdates)
+		switch {
+		case errors.Is(err, bot.ErrNoWebhook):
+			log.Info("No webhook registered yet, allowed_updates not configured")
+		case err != nil:
+			log.Warn("Failed to configure allowed updates", "error", err)
+		case updated:
+			log.Info("Webhook allowed_updates updated", "allowed_updates", allowedUpdates)
+		}
+	}
+
+	// Register the command menu shown when users type "/"
+	// Everyone sees public commands; authorized users' private chats also list private ones
+	// (a failure only affects the menu, commands still work, so it's not fatal)
+	if err := bot.RegisterCommands(sender, handlers.BotCommands(false)); err != nil {
+		log.Warn("Failed to register bot commands", "error", err)
+	}
+	if len(cfg.AllowedUsers) > 0 {
+		if err := bot.RegisterCommands(sender, handlers.BotCommands(true), cfg.AllowedUsers...); err != nil {
+			log.Warn("Failed to register private bot commands", "error", err)
+		}
+	}
+
+	// Persistent menu button next to the input field: the command list, or
+	// a Web App when MENU_BUTTON_WEB_APP_URL is set
+	menuButton := bot.MenuButton{Type: bot.MenuButtonCommands}
+	if cfg.MenuButtonWebAppURL != "" {
+		menuButton = bot.MenuButton{Type: bot.MenuButtonWebApp, Text: cfg.MenuButtonText, WebAppURL: cfg.MenuButtonWebAppURL}
+	}
+	if err := bot.ConfigureMenuButton(raw, menuButton); err != nil {
+		log.Warn("Failed to configure menu button", "error", err, "type", menuButton.Type)
+	}
+
+	return instance, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/config"
+)
+
+// TestNewBotsMux_MultipleBots tests that every bot of BOT_TOKENS gets its own
+// webhook path and that updates are answered by the bot they were sent to
+//
+// Cases:
+//   - POST WEBHOOK_PATH/prod: only the prod sender replies
+//   - POST WEBHOOK_PATH/staging: only the staging sender replies
+//   - POST WEBHOOK_PATH itself: 404, no bot replies
+//
+// Every update's logs carry the bot name, and webhook_updates_total counts per bot.
+func TestNewBotsMux_MultipleBots(t *testing.T) {
+	handler := newRecordingHandler()
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	defer slog.SetDefault(oldLogger)
+
+	cfg := &config.Config{
+		WebhookPath: "/webhook",
+		Bots: []config.BotConfig{
+			{Name: "prod", Token: "111:AAA", AllowedUsers: []int64{1}},
+			{Name: "staging", Token: "222:BBB", AllowedUsers: []int64{2}},
+		},
+	}
+	bots := botInstances(cfg)
+	senders := map[string]*countingSender{}
+	for i := range bots {
+		sender := &countingSender{}
+		senders[bots[i].name] = sender
+		bots[i].sender = sender
+	}
+	mux := newBotsMux(cfg, bots)
+
+	prodBefore, stagingBefore := webhookUpdates.Value("prod"), webhookUpdates.Value("staging")
+
+	tests := []struct {
+		name        string
+		path        string
+		userID      int64
+		wantStatus  int
+		wantProd    int
+		wantStaging int
+	}{
+		{name: "prod bot", path: "/webhook/prod", userID: 600, wantStatus: http.StatusOK, wantProd: 1},
+		{name: "staging bot", path: "/webhook/staging", userID: 601, wantStatus: http.StatusOK, wantProd: 1, wantStaging: 1},
+		{name: "shared path is not a webhook", path: "/webhook", userID: 602, wantStatus: http.StatusNotFound, wantProd: 1, wantStaging: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(helpUpdate(tt.userID)))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("POST %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if got := senders["prod"].sends; got != tt.wantProd {
+				t.Errorf("prod sends = %d, want %d", got, tt.wantProd)
+			}
+			if got := senders["staging"].sends; got != tt.wantStaging {
+				t.Errorf("staging sends = %d, want %d", got, tt.wantStaging)
+			}
+		})
+	}
+
+	// Every "Received update" line names the bot that received it
+	wantBots := map[int64]string{600: "prod", 601: "staging"}
+	received := 0
+	for _, record := range *handler.records {
+		if record["msg"] != "Received update" {
+			continue
+		}
+		received++
+		userID, _ := record["user_id"].(int64)
+		if want := wantBots[userID]; record["bot"] != want {
+			t.Errorf("Received update for user %d logged bot = %v, want %q", userID, record["bot"], want)
+		}
+	}
+	if received != 2 {
+		t.Errorf("logged %d received updates, want 2", received)
+	}
+
+	if got := webhookUpdates.Value("prod") - prodBefore; got != 1 {
+		t.Errorf(`webhook_updates_total{bot="prod"} increased by %v, want 1`, got)
+	}
+	if got := webhookUpdates.Value("staging") - stagingBefore; got != 1 {
+		t.Errorf(`webhook_updates_total{bot="staging"} increased by %v, want 1`, got)
+	}
+}
+
+// TestBotInstances tests the per-bot configs and the paths used by rate limits and access logs
+func TestBotInstances(t *testing.T) {
+	t.Run("single bot", func(t *testing.T) {
+		cfg := &config.Config{WebhookPath: "/hook"}
+
+		bots := botInstances(cfg)
+		if len(bots) != 1 || bots[0].cfg != cfg || bots[0].label() != "default" {
+			t.Errorf("botInstances() = %+v, want one default bot using the shared config", bots)
+		}
+		if labels := webhookPathLabels(cfg); len(labels) != 1 || labels["/hook"] != "<webhook>" {
+			t.Errorf("webhookPathLabels() = %v, want /hook labelled <webhook>", labels)
+		}
+	})
+
+	t.Run("BOT_TOKENS", func(t *testing.T) {
+		cfg := &config.Config{
+			WebhookPath:  "/hook",
+			AllowedUsers: []int64{1},
+			Bots: []config.BotConfig{
+				{Name: "prod", Token: "111:AAA", AllowedUsers: []int64{1}},
+				{Name: "staging", Token: "222:BBB", AllowedUsers: []int64{2}},
+			},
+		}
+
+		bots := botInstances(cfg)
+		if len(bots) != 2 {
+			t.Fatalf("botInstances() returned %d bots, want 2", len(bots))
+		}
+		staging := bots[1]
+		if staging.label() != "staging" || staging.cfg.WebhookPath != "/hook/staging" ||
+			staging.cfg.BotToken != "222:BBB" || !staging.cfg.IsUserAllowed(2, "") || staging.cfg.IsUserAllowed(1, "") {
+			t.Errorf("staging bot = %+v (cfg %+v), want its own token, path and admins", staging, staging.cfg)
+		}
+
+		wantLabels := map[string]string{"/hook/prod": "<webhook:prod>", "/hook/staging": "<webhook:staging>"}
+		labels := webhookPathLabels(cfg)
+		if len(labels) != len(wantLabels) {
+			t.Errorf("webhookPathLabels() = %v, want %v", labels, wantLabels)
+		}
+		for path, want := range wantLabels {
+			if labels[path] != want {
+				t.Errorf("webhookPathLabels()[%q] = %q, want %q", path, labels[path], want)
+			}
+		}
+	})
+}
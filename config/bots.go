@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// BotConfig is one bot of a multi-bot deployment (BOT_TOKENS)
+//
+// Every bot shares the rest of the configuration (OVH settings, features,
+// rate limits, ...); only the token, the admins and the webhook path differ.
+type BotConfig struct {
+	// Name - short identifier from BOT_TOKENS (e.g., "prod", "staging")
+	// Used in the webhook path (WEBHOOK_PATH/<name>), logs and metric labels
+	Name string
+
+	// Token - Telegram bot token from @BotFather
+	Token string
+
+	// AllowedUsers - admins of this bot
+	// Parsed from ALLOWED_USERS_<NAME> (e.g., ALLOWED_USERS_STAGING),
+	// falls back to ALLOWED_USERS when unset
+	AllowedUsers []int64
+
+	// AllowedUsernames - admins of this bot by @username (same source as AllowedUsers)
+	AllowedUsernames []string
+}
+
+// botNamePattern restricts bot names to what is safe in URL paths,
+// metric labels and environment variable names
+var botNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// parseBotTokensEnv reads BOT_TOKENS: comma-separated "name=token" pairs
+//
+// Example:
+//
+//	BOT_TOKENS=prod=123456:AAA...,staging=654321:BBB...
+//	ALLOWED_USERS_STAGING=111,222,@alice
+//
+// Parameters:
+//   - name: Environment variable name ("BOT_TOKENS")
+//   - defaultUsers: Admin IDs for bots without their own ALLOWED_USERS_<NAME> (ALLOWED_USERS)
+//   - defaultUsernames: Admin @usernames for the same bots
+//
+// Returns:
+//   - []BotConfig: Bots in the order they are listed (nil if unset or empty)
+//   - error: If an entry is malformed, a name is invalid or used twice
+func parseBotTokensEnv(name string, defaultUsers []int64, defaultUsernames []string) ([]BotConfig, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var bots []BotConfig
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// Tokens contain ":" but never "=", so the first "=" separates the name
+		botName, token, ok := strings.Cut(entry, "=")
+		botName, token = strings.TrimSpace(botName), strings.TrimSpace(token)
+		if !ok || token == "" {
+			return nil, fmt.Errorf("invalid entry in %s: expected name=token", name)
+		}
+		if !botNamePattern.MatchString(botName) {
+			return nil, fmt.Errorf("invalid bot name in %s: %q (use a-z, 0-9 and _)", name, botName)
+		}
+		if seen[botName] {
+			return nil, fmt.Errorf("duplicate bot name in %s: %q", name, botName)
+		}
+		seen[botName] = true
+
+		// Per-bot admins: ALLOWED_USERS_<NAME>, e.g., ALLOWED_USERS_STAGING
+		usersVar := "ALLOWED_USERS_" + strings.ToUpper(botName)
+		users, usernames, err := parseAllowedUsersEnv(usersVar)
+		if err != nil {
+			return nil, err
+		}
+		if _, set := os.LookupEnv(usersVar); !set {
+			users, usernames = defaultUsers, defaultUsernames
+		}
+
+		bots = append(bots, BotConfig{Name: botName, Token: token, AllowedUsers: users, AllowedUsernames: usernames})
+	}
+	return bots, nil
+}
+
+// ForBot returns a copy of the configuration for one bot of BOT_TOKENS.
+// Handlers keep receiving a single *Config, they don't need to know about other bots.
+//
+// The copy has the bot's token, admins and webhook path (WEBHOOK_PATH/<name>);
+// BotUsername is left for main.go to fill from getMe, as for a single bot.
+//
+// Parameters:
+//   - b: Bot from c.Bots
+//
+// Returns:
+//   - *Config: Configuration for that bot (c itself is not modified)
+func (c *Config) ForBot(b BotConfig) *Config {
+	// Copying the struct copies slices and maps by reference, which is fine:
+	// shared fields are never modified after Load
+	botCfg := *c
+	botCfg.BotToken = b.Token
+	botCfg.AllowedUsers = b.AllowedUsers
+	botCfg.allowedUsersSet = newIDSet(b.AllowedUsers)
+	botCfg.AllowedUsernames = b.AllowedUsernames
+	botCfg.allowedUsernamesSet = newUsernameSet(b.AllowedUsernames)
+	botCfg.WebhookPath = BotWebhookPath(c.WebhookPath, b.Name)
+	botCfg.Bots = nil
+	return &botCfg
+}
+
+// BotWebhookPath returns the webhook path of a bot in a multi-bot deployment
+//
+// Parameters:
+//   - webhookPath: Shared WEBHOOK_PATH (e.g., "/webhook")
+//   - name: Bot name from BOT_TOKENS (e.g., "staging")
+//
+// Returns:
+//   - string: e.g., "/webhook/staging"
+func BotWebhookPath(webhookPath, name string) string {
+	return strings.TrimSuffix(webhookPath, "/") + "/" + name
+}
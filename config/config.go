@@ -4,9 +4,17 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
+	"math"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	// Embedded time zone database (~450 KB): the runtime image has no
+	// /usr/share/zoneinfo, and TIMEZONE/ADMIN_SUMMARY_TZ need time.LoadLocation
+	_ "time/tzdata"
 )
 
 // Config stores application configuration
@@ -19,108 +27,927 @@ type Config struct {
 	// Cloud Run automatically sets PORT environment variable
 	Port string
 
+	// WebhookPath - HTTP path Telegram sends updates to (default "/webhook")
+	// Parsed from WEBHOOK_PATH environment variable, must start with "/"
+	// A hard-to-guess path (e.g., /webhook-7f3a9c) keeps random scanners
+	// from posting fake updates to the bot
+	WebhookPath string
+
+	// WebhookURL - public base URL of the service (e.g., https://run-tbot-xyz.run.app)
+	// Parsed from WEBHOOK_URL environment variable (optional)
+	// When set, the bot registers WebhookURL + WebhookPath with Telegram on startup;
+	// when empty, the webhook must be registered manually with setWebhook
+	WebhookURL string
+
+	// DropPendingUpdates - discard updates queued while the bot was down
+	// Parsed from DROP_PENDING_UPDATES environment variable (default false)
+	// Applied when the bot registers its webhook (requires WEBHOOK_URL)
+	DropPendingUpdates bool
+
+	// DropPendingOnStart - drop queued updates on startup with deleteWebhook
+	// Parsed from DROP_PENDING_ON_START environment variable (default false)
+	// Unlike DropPendingUpdates it works without WEBHOOK_URL: the webhook is
+	// deleted and registered again as it was (see bot.FlushPendingUpdates)
+	DropPendingOnStart bool
+
+	// AllowedUpdates - update types Telegram should send to the webhook
+	// Parsed from ALLOWED_UPDATES environment variable (comma-separated list)
+	// Default: nil, meaning the types the router handles (handlers.AllowedUpdates).
+	// Telegram doesn't deliver other types at all, which saves requests
+	// (and Cloud Run cost) for updates the bot would ignore
+	// Applied at startup when registering the webhook (or updating an existing one)
+	AllowedUpdates []string
+
+	// GoogleCloudProject - Google Cloud project ID (e.g., my-project-123)
+	// Parsed from GOOGLE_CLOUD_PROJECT environment variable (optional)
+	// When set, update logs carry the request's Cloud Trace ID, so Cloud Logging
+	// groups all log lines of one webhook request together
+	GoogleCloudProject string
+
+	// RootHealthCheck - also answer the health check at "/" (besides /healthz)
+	// Parsed from ROOT_HEALTH_CHECK environment variable (default true)
+	// Kept for Cloud Run: its front end may intercept paths ending in "z"
+	// (like /healthz), so probes and the deploy workflow still use "/"
+	RootHealthCheck bool
+
+	// EnablePprof - serve Go profiling endpoints under /debug/pprof/
+	// Parsed from ENABLE_PPROF environment variable (default false)
+	// Requires PprofToken: requests must send "Authorization: Bearer <token>"
+	EnablePprof bool
+
+	// PprofToken - bearer token protecting /debug/pprof/ (secret, never logged)
+	// Parsed from PPROF_TOKEN environment variable, at least 16 characters
+	PprofToken string
+
 	// Environment - environment (development or production)
 	// Used to enable debug mode in development
 	Environment string
 
+	// LogLevel - minimum level of log lines that are written
+	// Parsed from LOG_LEVEL environment variable (default info)
+	LogLevel slog.Level
+
+	// Polling - fetch updates with getUpdates instead of receiving them by webhook
+	// Parsed from POLLING environment variable (default false)
+	// Meant for local development: no public URL or ngrok needed. The webhook
+	// is deleted on startup, because Telegram refuses getUpdates while one is set
+	Polling bool
+
+	// DryRun - log outgoing Telegram calls instead of making them
+	// Parsed from DRY_RUN environment variable (default false)
+	// Updates are still received and routed, so the whole handler path runs
+	// against this config without messaging anyone (see bot.LoggingSender)
+	DryRun bool
+
 	// AllowedUsers - list of Telegram user IDs allowed to access private functions
 	// Parsed from ALLOWED_USERS environment variable (comma-separated list)
-	// Empty list means no users have access to private functions
+	// Empty list (and no AllowedUsernames) means no users have access to private functions
 	// Example: ALLOWED_USERS=123456789,987654321
 	AllowedUsers []int64
+
+	// AllowedUsernames - @usernames allowed to access private functions,
+	// lower case and without "@"
+	// Parsed from the "@name" entries of ALLOWED_USERS (ALLOWED_USERS=123456789,@alice)
+	// The Bot API can't look up a username, so these are matched against
+	// message.From.UserName at message time (see IsUserAllowed). Features
+	// that need a chat ID up front (admin summary, private command menu)
+	// only reach the numeric IDs.
+	AllowedUsernames []string
+
+	// AllowedChats - group chat IDs the bot may stay in
+	// Parsed from ALLOWED_CHATS environment variable (comma-separated list)
+	// Empty list means any group is allowed
+	// Group IDs are negative, e.g., ALLOWED_CHATS=-1001234567890
+	AllowedChats []int64
+
+	// UseAnimatedDice - send Telegram's native animated 🎲 instead of text results
+	// Parsed from USE_ANIMATED_DICE environment variable (true/false, default false)
+	// When enabled, both dice buttons use the animated handler variants
+	UseAnimatedDice bool
+
+	// DiceShowProbability - add the chance of the sum to double dice results
+	// (e.g., "P(sum=12) = 2.8% — lucky!")
+	// Parsed from DICE_SHOW_PROBABILITY environment variable (true/false, default false)
+	// Text results only: the animated variant sends just the sum
+	DiceShowProbability bool
+
+	// ReactToRequests - react to button presses with an emoji (see bot.React)
+	// Parsed from REACT_TO_REQUESTS environment variable (true/false, default false)
+	// Off by default: older clients don't show reactions, and each one is an extra API call
+	ReactToRequests bool
+
+	// StrictMarkdown - reject outgoing MarkdownV2 messages that fail validation
+	// Parsed from STRICT_MARKDOWN environment variable (default: true in development)
+	// When false, invalid messages are logged and sent as plain text instead
+	StrictMarkdown bool
+
+	// HandleEditedMessages - treat recently edited commands like new ones
+	// Parsed from HANDLE_EDITED_MESSAGES environment variable (default true)
+	// Lets users fix a typo (/hep -> /help) without sending a new message
+	HandleEditedMessages bool
+
+	// MorningHour - UTC hour (0-23) when /goodmorning subscribers get their daily message
+	// Parsed from MORNING_HOUR environment variable (default 8, i.e., 08:00 UTC)
+	MorningHour int
+
+	// AdminSummaryEnabled - whether admins get a daily OVH summary
+	// True when ADMIN_SUMMARY_TIME is set (default: unset, no summary)
+	AdminSummaryEnabled bool
+
+	// AdminSummaryHour, AdminSummaryMinute - wall-clock time of the daily admin summary
+	// Parsed from ADMIN_SUMMARY_TIME environment variable ("HH:MM", 24-hour clock)
+	AdminSummaryHour   int
+	AdminSummaryMinute int
+
+	// AdminSummaryLocation - time zone ADMIN_SUMMARY_TIME is given in
+	// Parsed from ADMIN_SUMMARY_TZ environment variable (IANA name, default "UTC")
+	// A zone (not a fixed offset) keeps the summary at the same local time across DST changes
+	AdminSummaryLocation *time.Location
+
+	// Location - time zone of the "as of 14:32 Europe/London" line under OVH results
+	// Parsed from TIMEZONE, else TZ environment variable (IANA name, default "UTC")
+	Location *time.Location
+
+	// Features - per-feature enable flags (ENABLE_DICE, ENABLE_OVH, ...), see features.go
+	Features Features
+
+	// RateLimit - requests per second allowed per client IP on all paths except the webhook
+	// Parsed from RATE_LIMIT environment variable (default 10, 0 disables)
+	RateLimit int
+
+	// WebhookRateLimit - requests per second allowed per client IP on WEBHOOK_PATH
+	// Parsed from WEBHOOK_RATE_LIMIT environment variable (default 50, 0 disables)
+	// Higher than RateLimit: Telegram delivers bursts of updates from a few IPs
+	WebhookRateLimit int
+
+	// UpdateTimeout - processing deadline for one update (see middleware.TimeoutMiddleware)
+	// Parsed from UPDATE_TIMEOUT environment variable in seconds (default 10, 0 disables)
+	// Must be shorter than ServerWriteTimeout
+	// When it fires, the handler's context is cancelled and the user is asked to retry
+	UpdateTimeout time.Duration
+
+	// SlowRequestThreshold - webhook requests slower than this are logged as warnings
+	// Parsed from SLOW_REQUEST_THRESHOLD environment variable (Go duration, default 3s)
+	// The warning carries slow_request=true, a simple filter for Cloud Logging alerts
+	SlowRequestThreshold time.Duration
+
+	// OVHCooldown - minimum time between two OVH requests of the same user
+	// Parsed from OVH_COOLDOWN environment variable (Go duration, default 10s, 0 disables)
+	// Requests inside the cooldown are answered with the remaining wait (see handlers.SetOVHCooldown)
+	OVHCooldown time.Duration
+
+	// OVHTimeout - how long an OVH API request may take
+	// Parsed from OVH_TIMEOUT environment variable (Go duration, default 10s, must be > 0)
+	// Bounds each OVH HTTP request (ovh.RequestTimeout) and each interactive OVH fetch
+	OVHTimeout time.Duration
+
+	// PriceChangeThresholdPct - smallest OVH price change (percent) worth notifying subscribers
+	// Parsed from PRICE_CHANGE_THRESHOLD_PCT environment variable (default 5, see ovh.PriceWatcher)
+	PriceChangeThresholdPct float64
+
+	// StorageBackend - where the bot keeps its state (see storage.Open)
+	// Parsed from STORAGE_BACKEND environment variable: "memory" (default), "file", "firestore" or "redis"
+	StorageBackend string
+
+	// StoragePath - JSON file used by the "file" storage backend
+	// Parsed from STORAGE_PATH environment variable (required when STORAGE_BACKEND=file)
+	StoragePath string
+
+	// FirestoreCollection - collection used by the "firestore" storage backend
+	// Parsed from FIRESTORE_COLLECTION environment variable (default "run-tbot")
+	// The project is GoogleCloudProject, or detected from the credentials when empty
+	FirestoreCollection string
+
+	// RedisURL - server used by the "redis" storage backend
+	// Parsed from REDIS_URL environment variable (required when STORAGE_BACKEND=redis),
+	// e.g. redis://:password@localhost:6379/0 (rediss:// for TLS)
+	RedisURL string
+
+	// MenuButtonWebAppURL - Web App opened by the chat menu button (see bot.ConfigureMenuButton)
+	// Parsed from MENU_BUTTON_WEB_APP_URL environment variable (optional, must be https://)
+	// Empty: the menu button opens the command list
+	MenuButtonWebAppURL string
+
+	// MenuButtonText - label of the Web App menu button
+	// Parsed from MENU_BUTTON_TEXT environment variable (default "Menu")
+	MenuButtonText string
+
+	// Bots - bots served by this process, parsed from BOT_TOKENS (see bots.go)
+	// nil when BOT_TOKEN is used (a single bot, configured by the fields above)
+	// Each bot gets its own Config via ForBot, with the webhook at WEBHOOK_PATH/<name>
+	Bots []BotConfig
+
+	// BotUsername - the bot's own @username (without @)
+	// NOT read from environment: main.go fills it from Telegram's getMe response
+	// Used in group chats to tell our commands (/start@our_bot) from other bots'
+	BotUsername string
+
+	// allowedUsersSet - AllowedUsers as a set for O(1) lookups in IsUserAllowed
+	// Built once by Load; nil for configs created as struct literals (e.g., in tests)
+	allowedUsersSet map[int64]struct{}
+
+	// allowedUsernamesSet - AllowedUsernames as a set, built with allowedUsersSet
+	allowedUsernamesSet map[string]struct{}
+}
+
+// ServerWriteTimeout is the HTTP server's WriteTimeout (main.go)
+// UPDATE_TIMEOUT must be shorter: a webhook update still running when the
+// write deadline passes loses its 200 response, so Telegram delivers it
+// again and it is handled twice, and the user is never asked to retry.
+const ServerWriteTimeout = 15 * time.Second
+
+// defaultUpdateTimeout leaves the webhook response a few seconds before ServerWriteTimeout
+const defaultUpdateTimeout = 10
+
+// minPprofTokenLength keeps PPROF_TOKEN from being trivially guessable
+const minPprofTokenLength = 16
+
+// knownUpdateTypes lists every update type of the Telegram Bot API
+// Used to catch typos in ALLOWED_UPDATES (Telegram silently ignores unknown names)
+var knownUpdateTypes = map[string]bool{
+	"message": true, "edited_message": true, "channel_post": true, "edited_channel_post": true,
+	"business_connection": true, "business_message": true, "edited_business_message": true,
+	"deleted_business_messages": true, "message_reaction": true, "message_reaction_count": true,
+	"inline_query": true, "chosen_inline_result": true, "callback_query": true,
+	"shipping_query": true, "pre_checkout_query": true, "purchased_paid_media": true,
+	"poll": true, "poll_answer": true, "my_chat_member": true, "chat_member": true,
+	"chat_join_request": true, "chat_boost": true, "removed_chat_boost": true,
 }
 
 // Load reads configuration from environment variables
 // Returns pointer to Config or error if required variables are not set
-func Load() (*Config, error) {
+//
+// Parameters:
+//   - overrides: Command-line values that win over the environment
+//     (Overrides{} reads the environment only), see overrides.go
+func Load(overrides Overrides) (*Config, error) {
+	// Fill unset variables from the config file first, so everything
+	// below reads them as if they came from the environment
+	if overrides.ConfigFile != "" {
+		if err := loadEnvFile(overrides.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
 	// Read BOT_TOKEN from environment variable
 	// os.Getenv returns empty string if variable is not set
+	// BOT_TOKENS (several bots, see bots.go) replaces it
 	botToken := os.Getenv("BOT_TOKEN")
-	if botToken == "" {
+	if botToken == "" && strings.TrimSpace(os.Getenv("BOT_TOKENS")) == "" {
 		// fmt.Errorf creates a new error with formatted message
 		return nil, fmt.Errorf("BOT_TOKEN environment variable is required")
 	}
 
 	// Read PORT, use "8080" as default if not set
 	port := os.Getenv("PORT")
+	if overrides.Port != "" {
+		port = overrides.Port
+	}
 	if port == "" {
 		port = "8080" // Default port for local development
 	}
 
+	// Read WEBHOOK_PATH, use "/webhook" as default
+	webhookPath := strings.TrimSpace(os.Getenv("WEBHOOK_PATH"))
+	if webhookPath == "" {
+		webhookPath = "/webhook"
+	}
+	// "/" is taken by the health check, and ServeMux patterns must start with "/"
+	// Spaces and braces have special meaning in ServeMux patterns ("POST /x", "/{id}")
+	if !strings.HasPrefix(webhookPath, "/") || webhookPath == "/" || strings.ContainsAny(webhookPath, " \t{}") {
+		return nil, fmt.Errorf("invalid WEBHOOK_PATH: %q (must start with /, not be /, and have no spaces or braces)", webhookPath)
+	}
+
+	// Read WEBHOOK_URL (optional, no default: empty means "don't register")
+	webhookURL := strings.TrimSpace(os.Getenv("WEBHOOK_URL"))
+
+	// Read DROP_PENDING_UPDATES (optional boolean flag)
+	dropPendingUpdates, err := parseBoolEnv("DROP_PENDING_UPDATES", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read DROP_PENDING_ON_START (optional boolean flag)
+	dropPendingOnStart, err := parseBoolEnv("DROP_PENDING_ON_START", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read ALLOWED_UPDATES (nil if not set: main.go derives it from the router)
+	allowedUpdates, err := parseAllowedUpdatesEnv("ALLOWED_UPDATES")
+	if err != nil {
+		return nil, err
+	}
+
+	// Read GOOGLE_CLOUD_PROJECT (optional, enables trace correlation in logs)
+	googleCloudProject := strings.TrimSpace(os.Getenv("GOOGLE_CLOUD_PROJECT"))
+
+	// Read ROOT_HEALTH_CHECK (optional boolean flag, on by default)
+	rootHealthCheck, err := parseBoolEnv("ROOT_HEALTH_CHECK", true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read ENABLE_PPROF and PPROF_TOKEN
+	// Profiling endpoints expose internals, so they are never served without a token
+	enablePprof, err := parseBoolEnv("ENABLE_PPROF", false)
+	if err != nil {
+		return nil, err
+	}
+	pprofToken := strings.TrimSpace(os.Getenv("PPROF_TOKEN"))
+	if enablePprof && len(pprofToken) < minPprofTokenLength {
+		return nil, fmt.Errorf("ENABLE_PPROF requires PPROF_TOKEN with at least %d characters", minPprofTokenLength)
+	}
+
 	// Read ENVIRONMENT, use "production" as default
 	environment := os.Getenv("ENVIRONMENT")
+	if overrides.Environment != "" {
+		environment = overrides.Environment
+	}
 	if environment == "" {
 		environment = "production"
 	}
 
-	// Read ALLOWED_USERS and parse comma-separated list of user IDs
-	// strings.TrimSpace removes leading/trailing whitespace
-	// If ALLOWED_USERS is empty or not set, allowedUsers will be empty slice
-	allowedUsersStr := strings.TrimSpace(os.Getenv("ALLOWED_USERS"))
-	var allowedUsers []int64
-	if allowedUsersStr != "" {
-		// strings.Split divides string by comma: "123,456" -> ["123", "456"]
-		userIDs := strings.Split(allowedUsersStr, ",")
-		for _, userIDStr := range userIDs {
-			// strings.TrimSpace removes whitespace around each ID: " 123 " -> "123"
-			userIDStr = strings.TrimSpace(userIDStr)
-			if userIDStr == "" {
-				continue // Skip empty strings (e.g., from "123,,456")
-			}
+	// Read LOG_LEVEL (optional: debug, info, warn, error)
+	logLevelName := strings.TrimSpace(os.Getenv("LOG_LEVEL"))
+	if overrides.LogLevel != "" {
+		logLevelName = overrides.LogLevel
+	}
+	logLevel := slog.LevelInfo
+	if logLevelName != "" {
+		// UnmarshalText accepts the names slog prints (case-insensitive), e.g. "DEBUG", "warn"
+		if err := logLevel.UnmarshalText([]byte(logLevelName)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: %q (use debug, info, warn or error)", logLevelName)
+		}
+	}
 
-			// strconv.ParseInt converts string to int64
-			// Parameters: string, base (10 for decimal), bitSize (64 for int64)
-			// Telegram user IDs are large numbers that require 64-bit integers
-			userID, err := strconv.ParseInt(userIDStr, 10, 64)
-			if err != nil {
-				// If conversion fails, return error with context
-				return nil, fmt.Errorf("invalid user ID in ALLOWED_USERS: %s: %w", userIDStr, err)
-			}
-			allowedUsers = append(allowedUsers, userID)
+	// Read POLLING (optional boolean flag, off by default)
+	polling, err := parseBoolEnv("POLLING", false)
+	if err != nil {
+		return nil, err
+	}
+	if overrides.Polling != nil {
+		polling = *overrides.Polling
+	}
+
+	// Read DRY_RUN (optional boolean flag, off by default)
+	dryRun, err := parseBoolEnv("DRY_RUN", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read ALLOWED_USERS and parse comma-separated list of user IDs and @usernames
+	// If ALLOWED_USERS is empty or not set, both lists will be empty
+	allowedUsers, allowedUsernames, err := parseAllowedUsersEnv("ALLOWED_USERS")
+	if err != nil {
+		return nil, err
+	}
+
+	// Read BOT_TOKENS (optional, several bots in one process)
+	// Per-bot admins come from ALLOWED_USERS_<NAME>, defaulting to ALLOWED_USERS
+	bots, err := parseBotTokensEnv("BOT_TOKENS", allowedUsers, allowedUsernames)
+	if err != nil {
+		return nil, err
+	}
+	if len(bots) > 0 && botToken != "" {
+		return nil, fmt.Errorf("set either BOT_TOKEN or BOT_TOKENS, not both")
+	}
+	if len(bots) == 0 && botToken == "" {
+		return nil, fmt.Errorf("BOT_TOKENS has no bots")
+	}
+
+	// Read ALLOWED_CHATS (same format, group chat IDs)
+	allowedChats, err := parseIDListEnv("ALLOWED_CHATS")
+	if err != nil {
+		return nil, err
+	}
+
+	// Read USE_ANIMATED_DICE (optional boolean flag)
+	useAnimatedDice, err := parseBoolEnv("USE_ANIMATED_DICE", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read DICE_SHOW_PROBABILITY (optional boolean flag)
+	diceShowProbability, err := parseBoolEnv("DICE_SHOW_PROBABILITY", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read REACT_TO_REQUESTS (optional boolean flag)
+	reactToRequests, err := parseBoolEnv("REACT_TO_REQUESTS", false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read STRICT_MARKDOWN (optional boolean flag)
+	// Defaults to on in development so formatting bugs fail loudly there
+	strictMarkdown, err := parseBoolEnv("STRICT_MARKDOWN", environment == "development")
+	if err != nil {
+		return nil, err
+	}
+
+	// Read HANDLE_EDITED_MESSAGES (optional boolean flag, on by default)
+	handleEditedMessages, err := parseBoolEnv("HANDLE_EDITED_MESSAGES", true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read MORNING_HOUR (optional, 0-23)
+	morningHour, err := parseIntEnv("MORNING_HOUR", 8)
+	if err != nil {
+		return nil, err
+	}
+	if morningHour < 0 || morningHour > 23 {
+		return nil, fmt.Errorf("invalid MORNING_HOUR: %d (must be 0-23)", morningHour)
+	}
+
+	// Read ADMIN_SUMMARY_TIME (optional, "HH:MM") and ADMIN_SUMMARY_TZ (optional, IANA zone)
+	adminSummaryEnabled := false
+	adminSummaryHour, adminSummaryMinute := 0, 0
+	if value := os.Getenv("ADMIN_SUMMARY_TIME"); value != "" {
+		// "15:04" is Go's reference layout for a 24-hour HH:MM clock
+		at, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ADMIN_SUMMARY_TIME: %q (must be HH:MM)", value)
+		}
+		adminSummaryEnabled = true
+		adminSummaryHour, adminSummaryMinute = at.Hour(), at.Minute()
+	}
+	adminSummaryTZ := os.Getenv("ADMIN_SUMMARY_TZ")
+	if adminSummaryTZ == "" {
+		adminSummaryTZ = "UTC"
+	}
+	adminSummaryLocation, err := time.LoadLocation(adminSummaryTZ)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ADMIN_SUMMARY_TZ: %q: %w", adminSummaryTZ, err)
+	}
+
+	// Read TIMEZONE (optional, IANA zone; TZ is the fallback, as most containers set it)
+	location, err := loadLocationEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Read ENABLE_* feature flags (all on by default, ENABLE_ECHO only in development)
+	features, err := loadFeatures(environment == "development")
+	if err != nil {
+		return nil, err
+	}
+
+	// Read RATE_LIMIT and WEBHOOK_RATE_LIMIT (optional, requests per second per IP)
+	rateLimit, err := parseIntEnv("RATE_LIMIT", 10)
+	if err != nil {
+		return nil, err
+	}
+	webhookRateLimit, err := parseIntEnv("WEBHOOK_RATE_LIMIT", 50)
+	if err != nil {
+		return nil, err
+	}
+	if rateLimit < 0 || webhookRateLimit < 0 {
+		return nil, fmt.Errorf("invalid rate limit: RATE_LIMIT=%d, WEBHOOK_RATE_LIMIT=%d (must be >= 0)", rateLimit, webhookRateLimit)
+	}
+
+	// Read UPDATE_TIMEOUT (optional, seconds)
+	updateTimeout, err := parseIntEnv("UPDATE_TIMEOUT", defaultUpdateTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if updateTimeout < 0 {
+		return nil, fmt.Errorf("invalid UPDATE_TIMEOUT: %d (must be >= 0)", updateTimeout)
+	}
+	if time.Duration(updateTimeout)*time.Second >= ServerWriteTimeout {
+		return nil, fmt.Errorf("invalid UPDATE_TIMEOUT: %d (must be less than the HTTP write timeout, %v)",
+			updateTimeout, ServerWriteTimeout)
+	}
+
+	// Read SLOW_REQUEST_THRESHOLD (optional, Go duration like "3s" or "500ms")
+	slowRequestThreshold, err := parseDurationEnv("SLOW_REQUEST_THRESHOLD", 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if slowRequestThreshold <= 0 {
+		return nil, fmt.Errorf("invalid SLOW_REQUEST_THRESHOLD: %s (must be > 0)", slowRequestThreshold)
+	}
+
+	// Read OVH_COOLDOWN (optional, Go duration like "10s" or "1m")
+	ovhCooldown, err := parseDurationEnv("OVH_COOLDOWN", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if ovhCooldown < 0 {
+		return nil, fmt.Errorf("invalid OVH_COOLDOWN: %s (must be >= 0)", ovhCooldown)
+	}
+
+	// Read OVH_TIMEOUT (optional, Go duration like "10s")
+	ovhTimeout, err := parseDurationEnv("OVH_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if ovhTimeout <= 0 {
+		return nil, fmt.Errorf("invalid OVH_TIMEOUT: %s (must be > 0)", ovhTimeout)
+	}
+
+	// Read PRICE_CHANGE_THRESHOLD_PCT (optional, percent)
+	priceChangeThreshold, err := parseFloatEnv("PRICE_CHANGE_THRESHOLD_PCT", 5)
+	if err != nil {
+		return nil, err
+	}
+	if priceChangeThreshold < 0 || math.IsNaN(priceChangeThreshold) || math.IsInf(priceChangeThreshold, 0) {
+		return nil, fmt.Errorf("invalid PRICE_CHANGE_THRESHOLD_PCT: %v (must be >= 0)", priceChangeThreshold)
+	}
+
+	// Read STORAGE_BACKEND and the settings of its backend (optional, in-memory by default)
+	storageBackend := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_BACKEND")))
+	if storageBackend == "" {
+		storageBackend = "memory"
+	}
+	storagePath := strings.TrimSpace(os.Getenv("STORAGE_PATH"))
+	redisURL := strings.TrimSpace(os.Getenv("REDIS_URL"))
+	switch storageBackend {
+	case "memory", "firestore":
+	case "file":
+		if storagePath == "" {
+			return nil, fmt.Errorf("STORAGE_PATH is required when STORAGE_BACKEND=file")
+		}
+	case "redis":
+		if redisURL == "" {
+			return nil, fmt.Errorf("REDIS_URL is required when STORAGE_BACKEND=redis")
 		}
+	default:
+		return nil, fmt.Errorf("invalid STORAGE_BACKEND: %q (must be memory, file, firestore or redis)", storageBackend)
+	}
+	firestoreCollection := strings.TrimSpace(os.Getenv("FIRESTORE_COLLECTION"))
+	if firestoreCollection == "" {
+		firestoreCollection = "run-tbot"
+	}
+	if strings.Contains(firestoreCollection, "/") {
+		return nil, fmt.Errorf("invalid FIRESTORE_COLLECTION: %q (must not contain /)", firestoreCollection)
+	}
+
+	// Read MENU_BUTTON_WEB_APP_URL and MENU_BUTTON_TEXT (optional)
+	// Telegram only opens Web Apps over HTTPS
+	menuButtonWebAppURL := strings.TrimSpace(os.Getenv("MENU_BUTTON_WEB_APP_URL"))
+	if menuButtonWebAppURL != "" && !strings.HasPrefix(menuButtonWebAppURL, "https://") {
+		return nil, fmt.Errorf("invalid MENU_BUTTON_WEB_APP_URL: %q (must start with https://)", menuButtonWebAppURL)
+	}
+	menuButtonText := strings.TrimSpace(os.Getenv("MENU_BUTTON_TEXT"))
+	if menuButtonText == "" {
+		menuButtonText = "Menu"
 	}
 
 	// Create and return pointer to Config struct
 	// & creates a pointer to the struct
 	return &Config{
-		BotToken:     botToken,
-		Port:         port,
-		Environment:  environment,
-		AllowedUsers: allowedUsers,
+		BotToken:            botToken,
+		Bots:                bots,
+		LogLevel:            logLevel,
+		Polling:             polling,
+		DryRun:              dryRun,
+		Port:                port,
+		WebhookPath:         webhookPath,
+		WebhookURL:          webhookURL,
+		Environment:         environment,
+		AllowedUsers:        allowedUsers,
+		AllowedUsernames:    allowedUsernames,
+		AllowedChats:        allowedChats,
+		UseAnimatedDice:     useAnimatedDice,
+		DiceShowProbability: diceShowProbability,
+		ReactToRequests:     reactToRequests,
+		StrictMarkdown:      strictMarkdown,
+
+		HandleEditedMessages: handleEditedMessages,
+		DropPendingUpdates:   dropPendingUpdates,
+		DropPendingOnStart:   dropPendingOnStart,
+		AllowedUpdates:       allowedUpdates,
+		GoogleCloudProject:   googleCloudProject,
+		RootHealthCheck:      rootHealthCheck,
+		EnablePprof:          enablePprof,
+		PprofToken:           pprofToken,
+		MorningHour:          morningHour,
+		AdminSummaryEnabled:  adminSummaryEnabled,
+		AdminSummaryHour:     adminSummaryHour,
+		AdminSummaryMinute:   adminSummaryMinute,
+		AdminSummaryLocation: adminSummaryLocation,
+		Location:             location,
+		Features:             features,
+		RateLimit:            rateLimit,
+		WebhookRateLimit:     webhookRateLimit,
+		UpdateTimeout:        time.Duration(updateTimeout) * time.Second,
+		SlowRequestThreshold: slowRequestThreshold,
+		OVHCooldown:          ovhCooldown,
+		OVHTimeout:           ovhTimeout,
+
+		PriceChangeThresholdPct: priceChangeThreshold,
+		StorageBackend:          storageBackend,
+		StoragePath:             storagePath,
+		FirestoreCollection:     firestoreCollection,
+		RedisURL:                redisURL,
+		MenuButtonWebAppURL:     menuButtonWebAppURL,
+		MenuButtonText:          menuButtonText,
+
+		allowedUsersSet:     newIDSet(allowedUsers),
+		allowedUsernamesSet: newUsernameSet(allowedUsernames),
 	}, nil
 }
 
+// newIDSet converts a list of IDs into a set
+// map[int64]struct{} is Go's idiomatic set: struct{} takes zero bytes,
+// only the keys matter
+func newIDSet(ids []int64) map[int64]struct{} {
+	set := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+// newUsernameSet converts a list of usernames (already lower case) into a set
+func newUsernameSet(usernames []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(usernames))
+	for _, username := range usernames {
+		set[username] = struct{}{}
+	}
+	return set
+}
+
+// usernamePattern matches Telegram usernames (without "@"):
+// 5-32 characters, letters, digits and underscores
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{5,32}$`)
+
+// parseAllowedUsersEnv reads a comma-separated allowlist of user IDs and @usernames
+//
+// Example: ALLOWED_USERS=123456789,@alice,@Bob_Admin
+//
+// Parameters:
+//   - name: Environment variable name (e.g., "ALLOWED_USERS")
+//
+// Returns:
+//   - []int64: Numeric entries (nil if none)
+//   - []string: "@" entries, lower case and without "@" (nil if none);
+//     usernames are case-insensitive in Telegram
+//   - error: If an entry is neither an ID nor a valid @username
+func parseAllowedUsersEnv(name string) ([]int64, []string, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return nil, nil, nil
+	}
+
+	var ids []int64
+	var usernames []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		switch {
+		case entry == "":
+			continue // Skip empty strings (e.g., from "123,,456")
+
+		case strings.HasPrefix(entry, "@"):
+			username := strings.TrimPrefix(entry, "@")
+			if !usernamePattern.MatchString(username) {
+				return nil, nil, fmt.Errorf("invalid username in %s: %s (5-32 letters, digits or _)", name, entry)
+			}
+			usernames = append(usernames, strings.ToLower(username))
+
+		default:
+			id, err := strconv.ParseInt(entry, 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid ID in %s: %s (use a numeric ID or @username): %w", name, entry, err)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, usernames, nil
+}
+
+// parseIDListEnv reads a comma-separated list of Telegram IDs from an environment variable
+//
+// Parameters:
+//   - name: Environment variable name (e.g., "ALLOWED_USERS")
+//
+// Returns:
+//   - []int64: Parsed IDs (nil if the variable is unset or empty)
+//   - error: If any entry is not a valid integer
+func parseIDListEnv(name string) ([]int64, error) {
+	// strings.TrimSpace removes leading/trailing whitespace
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var ids []int64
+	// strings.Split divides string by comma: "123,456" -> ["123", "456"]
+	for _, idStr := range strings.Split(raw, ",") {
+		// strings.TrimSpace removes whitespace around each ID: " 123 " -> "123"
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			continue // Skip empty strings (e.g., from "123,,456")
+		}
+
+		// strconv.ParseInt converts string to int64
+		// Parameters: string, base (10 for decimal), bitSize (64 for int64)
+		// Telegram IDs are large numbers that require 64-bit integers
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			// If conversion fails, return error with context
+			return nil, fmt.Errorf("invalid ID in %s: %s: %w", name, idStr, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseAllowedUpdatesEnv reads a comma-separated list of Telegram update types
+//
+// Parameters:
+//   - name: Environment variable name (e.g., "ALLOWED_UPDATES")
+//
+// Returns:
+//   - []string: Update types (nil if unset or empty)
+//   - error: If an entry is not a known Telegram update type
+func parseAllowedUpdatesEnv(name string) ([]string, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var types []string
+	for _, updateType := range strings.Split(raw, ",") {
+		updateType = strings.TrimSpace(updateType)
+		if updateType == "" {
+			continue
+		}
+		if !knownUpdateTypes[updateType] {
+			return nil, fmt.Errorf("invalid update type in %s: %s", name, updateType)
+		}
+		types = append(types, updateType)
+	}
+	return types, nil
+}
+
+// loadLocationEnv reads the display time zone from TIMEZONE, else TZ
+//
+// TZ may start with ":" (POSIX "use this zone file" form, e.g. ":Europe/London");
+// it is stripped, as the Go runtime does for the local zone.
+//
+// Returns:
+//   - *time.Location: The zone (UTC when neither variable is set)
+//   - error: If the set variable isn't a known IANA zone name
+func loadLocationEnv() (*time.Location, error) {
+	name, value := "TIMEZONE", os.Getenv("TIMEZONE")
+	if value == "" {
+		name, value = "TZ", strings.TrimPrefix(os.Getenv("TZ"), ":")
+	}
+	if value == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %q: %w", name, value, err)
+	}
+	return location, nil
+}
+
+// parseBoolEnv reads an optional boolean environment variable
+// Accepts the values understood by strconv.ParseBool: 1, t, true, 0, f, false (any case)
+//
+// Parameters:
+//   - name: Environment variable name
+//   - defaultValue: Value used when the variable is unset or empty
+//
+// Returns:
+//   - bool: Parsed value or defaultValue
+//   - error: If the variable is set to something that isn't a boolean
+func parseBoolEnv(name string, defaultValue bool) (bool, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean in %s: %s: %w", name, raw, err)
+	}
+	return value, nil
+}
+
+// parseIntEnv reads an optional integer environment variable
+//
+// Parameters:
+//   - name: Environment variable name
+//   - defaultValue: Value used when the variable is unset or empty
+//
+// Returns:
+//   - int: Parsed value or defaultValue
+//   - error: If the variable is set to something that isn't an integer
+func parseIntEnv(name string, defaultValue int) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer in %s: %s: %w", name, raw, err)
+	}
+	return value, nil
+}
+
+// parseFloatEnv reads an optional decimal number environment variable
+//
+// Parameters:
+//   - name: Environment variable name
+//   - defaultValue: Value used when the variable is unset or empty
+//
+// Returns:
+//   - float64: Parsed value or defaultValue
+//   - error: If the variable is set to something that isn't a number
+func parseFloatEnv(name string, defaultValue float64) (float64, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number in %s: %s: %w", name, raw, err)
+	}
+	return value, nil
+}
+
+// parseDurationEnv reads an optional duration environment variable
+// Accepts the format understood by time.ParseDuration: "3s", "500ms", "1m30s"
+//
+// Parameters:
+//   - name: Environment variable name
+//   - defaultValue: Value used when the variable is unset or empty
+//
+// Returns:
+//   - time.Duration: Parsed value or defaultValue
+//   - error: If the variable is set to something that isn't a duration
+func parseDurationEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration in %s: %s: %w", name, raw, err)
+	}
+	return value, nil
+}
+
 // IsDevelopment checks if application is running in development mode
 // Returns true if ENVIRONMENT = "development"
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
 
-// IsUserAllowed checks if a Telegram user ID is in the allowed users list
+// IsUserAllowed checks if a Telegram user is in the allowed users list
+// A user is allowed if their ID is in AllowedUsers or their @username is
+// in AllowedUsernames (case-insensitive).
+//
+// Note: usernames can be changed, and a freed username can be taken by
+// someone else; numeric IDs never change, so prefer them when known.
+//
 // Parameters:
 //   - userID: Telegram user ID to check (from message.From.ID or callback.From.ID)
+//   - username: The user's @username without "@" (message.From.UserName), "" if none
 //
 // Returns:
-//   - true if user is in AllowedUsers list
-//   - false if user is not in the list OR if AllowedUsers is empty
+//   - true if the ID or the username is allowed
+//   - false otherwise, or if both allowlists are empty
 //
 // Usage:
 //
-//	if cfg.IsUserAllowed(message.From.ID) {
+//	if cfg.IsUserAllowed(message.From.ID, message.From.UserName) {
 //	    // User has access to private functions
 //	} else {
 //	    // Public functions only
 //	}
-func (c *Config) IsUserAllowed(userID int64) bool {
-	// If AllowedUsers is empty, no users have access to private functions
+func (c *Config) IsUserAllowed(userID int64, username string) bool {
+	// If both lists are empty, no users have access to private functions
 	// This is a security-first approach: explicit > implicit
-	if len(c.AllowedUsers) == 0 {
+	if len(c.AllowedUsers) == 0 && len(c.AllowedUsernames) == 0 {
 		return false
 	}
 
-	// Linear search through allowed users
-	// This is fine for small lists (typically 1-10 users)
-	// For larger lists, consider using a map for O(1) lookup
+	return c.isIDAllowed(userID) || c.isUsernameAllowed(username)
+}
+
+// isIDAllowed checks the numeric part of the allowlist
+func (c *Config) isIDAllowed(userID int64) bool {
+	// Fast path: O(1) map lookup (set built once by Load)
+	// This check runs on every update, so it must stay cheap even
+	// when AllowedUsers grows to hundreds of entries
+	if c.allowedUsersSet != nil {
+		_, ok := c.allowedUsersSet[userID]
+		return ok
+	}
+
+	// Slow path: linear search for configs not created by Load
+	// (struct literals in tests). Fine for the short lists used there.
 	for _, allowedID := range c.AllowedUsers {
 		if allowedID == userID {
 			return true
@@ -129,3 +956,68 @@ func (c *Config) IsUserAllowed(userID int64) bool {
 
 	return false
 }
+
+// isUsernameAllowed checks the @username part of the allowlist
+// Users without a username never match.
+func (c *Config) isUsernameAllowed(username string) bool {
+	if username == "" || len(c.AllowedUsernames) == 0 {
+		return false
+	}
+
+	username = strings.ToLower(strings.TrimPrefix(username, "@"))
+	if c.allowedUsernamesSet != nil {
+		_, ok := c.allowedUsernamesSet[username]
+		return ok
+	}
+	for _, allowed := range c.AllowedUsernames {
+		if strings.EqualFold(allowed, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedUsersCount returns the number of allowlist entries (IDs plus usernames)
+func (c *Config) AllowedUsersCount() int {
+	return len(c.AllowedUsers) + len(c.AllowedUsernames)
+}
+
+// AllowedUsersSet returns AllowedUsers as a set for O(1) membership checks
+//
+// Returns:
+//   - map[int64]struct{}: Set of allowed user IDs (empty if none)
+//
+// The returned map is shared with Config and must not be modified.
+// For configs not created by Load, a new map is built on every call.
+//
+// Usage:
+//
+//	allowed := cfg.AllowedUsersSet()
+//	if _, ok := allowed[userID]; ok {
+//	    // User has access to private functions
+//	}
+func (c *Config) AllowedUsersSet() map[int64]struct{} {
+	if c.allowedUsersSet != nil {
+		return c.allowedUsersSet
+	}
+	return newIDSet(c.AllowedUsers)
+}
+
+// IsChatAllowed checks if the bot may stay in a group chat
+// Parameters:
+//   - chatID: Telegram chat ID (negative for groups)
+//
+// Returns:
+//   - true if AllowedChats is empty (no restriction) or contains chatID
+//   - false otherwise
+func (c *Config) IsChatAllowed(chatID int64) bool {
+	if len(c.AllowedChats) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedChats {
+		if allowed == chatID {
+			return true
+		}
+	}
+	return false
+}
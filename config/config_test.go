@@ -0,0 +1,1184 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestIsUserAllowed tests authorization lookups for both config kinds:
+//   - loaded via Load (map lookup)
+//   - struct literal (linear scan fallback)
+func TestIsUserAllowed(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+	t.Setenv("ALLOWED_USERS", "111, 222,333")
+
+	loaded, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	literal := &Config{AllowedUsers: []int64{111, 222, 333}}
+
+	tests := []struct {
+		name     string
+		userID   int64
+		expected bool
+	}{
+		{name: "first user", userID: 111, expected: true},
+		{name: "last user", userID: 333, expected: true},
+		{name: "unknown user", userID: 444, expected: false},
+		{name: "zero ID", userID: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loaded.IsUserAllowed(tt.userID, ""); got != tt.expected {
+				t.Errorf("loaded.IsUserAllowed(%d) = %v, want %v", tt.userID, got, tt.expected)
+			}
+			if got := literal.IsUserAllowed(tt.userID, ""); got != tt.expected {
+				t.Errorf("literal.IsUserAllowed(%d) = %v, want %v", tt.userID, got, tt.expected)
+			}
+		})
+	}
+
+	// Empty list: nobody is allowed
+	if (&Config{}).IsUserAllowed(111, "") {
+		t.Errorf("IsUserAllowed() with empty AllowedUsers = true, want false")
+	}
+}
+
+// TestAllowedUsersSet tests the set built by Load and by the literal fallback
+func TestAllowedUsersSet(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+	t.Setenv("ALLOWED_USERS", "111,222,111")
+
+	cfg, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	for _, c := range []*Config{cfg, {AllowedUsers: []int64{111, 222, 111}}} {
+		set := c.AllowedUsersSet()
+		if len(set) != 2 {
+			t.Errorf("AllowedUsersSet() has %d entries, want 2 (duplicates collapse)", len(set))
+		}
+		if _, ok := set[222]; !ok {
+			t.Errorf("AllowedUsersSet() missing 222")
+		}
+	}
+}
+
+// TestIsUserAllowed_Usernames tests a mixed allowlist of IDs and @usernames
+//
+// Cases (for a config from Load and a struct literal):
+//   - Match by ID, whatever the username
+//   - Match by username, case-insensitively, whatever the ID
+//   - A user without a username only matches by ID
+//   - Only usernames in the list: IDs alone don't match
+func TestIsUserAllowed_Usernames(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+	t.Setenv("ALLOWED_USERS", "111, @Alice_Admin,@bob_ops")
+
+	loaded, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if want := []string{"alice_admin", "bob_ops"}; !reflect.DeepEqual(loaded.AllowedUsernames, want) {
+		t.Errorf("AllowedUsernames = %v, want %v", loaded.AllowedUsernames, want)
+	}
+	if want := []int64{111}; !reflect.DeepEqual(loaded.AllowedUsers, want) {
+		t.Errorf("AllowedUsers = %v, want %v", loaded.AllowedUsers, want)
+	}
+	literal := &Config{AllowedUsers: []int64{111}, AllowedUsernames: []string{"alice_admin", "bob_ops"}}
+
+	tests := []struct {
+		name     string
+		userID   int64
+		username string
+		expected bool
+	}{
+		{name: "ID match", userID: 111, username: "someone_else", expected: true},
+		{name: "ID match without username", userID: 111, username: "", expected: true},
+		{name: "username match", userID: 999, username: "alice_admin", expected: true},
+		{name: "username different case", userID: 999, username: "ALICE_Admin", expected: true},
+		{name: "username with @", userID: 999, username: "@Bob_Ops", expected: true},
+		{name: "unknown username", userID: 999, username: "mallory", expected: false},
+		{name: "no username", userID: 999, username: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for label, cfg := range map[string]*Config{"loaded": loaded, "literal": literal} {
+				if got := cfg.IsUserAllowed(tt.userID, tt.username); got != tt.expected {
+					t.Errorf("%s.IsUserAllowed(%d, %q) = %v, want %v", label, tt.userID, tt.username, got, tt.expected)
+				}
+			}
+		})
+	}
+
+	if got := loaded.AllowedUsersCount(); got != 3 {
+		t.Errorf("AllowedUsersCount() = %d, want 3", got)
+	}
+	if !(&Config{AllowedUsernames: []string{"alice_admin"}}).IsUserAllowed(1, "Alice_Admin") {
+		t.Errorf("usernames-only allowlist: IsUserAllowed() = false, want true")
+	}
+}
+
+// TestLoad_AllowedUsersInvalid tests ALLOWED_USERS entries that are neither IDs nor usernames
+func TestLoad_AllowedUsersInvalid(t *testing.T) {
+	for _, value := range []string{"111,abc", "@", "@abc", "@bad-name", "@" + strings.Repeat("a", 33)} {
+		t.Run(value, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ALLOWED_USERS", value)
+
+			if _, err := Load(Overrides{}); err == nil || !strings.Contains(err.Error(), "ALLOWED_USERS") {
+				t.Errorf("Load() error = %v, want an ALLOWED_USERS error", err)
+			}
+		})
+	}
+}
+
+// benchmarkUsers is the allowlist size used by BenchmarkIsUserAllowed
+const benchmarkUsers = 1000
+
+// BenchmarkIsUserAllowed compares linear scan with map lookup for 1000 allowed users
+// The looked-up ID is the last one in the list (worst case for the linear scan).
+//
+// Run with: go test -bench=IsUserAllowed ./config
+func BenchmarkIsUserAllowed(b *testing.B) {
+	ids := make([]int64, benchmarkUsers)
+	for i := range ids {
+		ids[i] = int64(100000 + i)
+	}
+	target := ids[len(ids)-1]
+
+	b.Run("linear", func(b *testing.B) {
+		// Struct literal: no set, IsUserAllowed falls back to scanning the slice
+		cfg := &Config{AllowedUsers: ids}
+		for i := 0; i < b.N; i++ {
+			if !cfg.IsUserAllowed(target, "") {
+				b.Fatal("user not found")
+			}
+		}
+	})
+
+	b.Run("map", func(b *testing.B) {
+		cfg := &Config{AllowedUsers: ids, allowedUsersSet: newIDSet(ids)}
+		for i := 0; i < b.N; i++ {
+			if !cfg.IsUserAllowed(target, "") {
+				b.Fatal("user not found")
+			}
+		}
+	})
+}
+
+// TestLoad_WebhookPath tests WEBHOOK_PATH parsing and validation
+func TestLoad_WebhookPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "default", value: "", expected: "/webhook"},
+		{name: "custom", value: "/hook-7f3a9c", expected: "/hook-7f3a9c"},
+		{name: "missing slash", value: "hook", wantErr: true},
+		{name: "root", value: "/", wantErr: true},
+		{name: "pattern syntax", value: "/hook/{id}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("WEBHOOK_PATH", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.WebhookPath != tt.expected {
+				t.Errorf("WebhookPath = %q, want %q", cfg.WebhookPath, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLoad_MorningHour tests MORNING_HOUR parsing and range validation
+func TestLoad_MorningHour(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int
+		wantErr  bool
+	}{
+		{name: "default", value: "", expected: 8},
+		{name: "custom", value: "6", expected: 6},
+		{name: "midnight", value: "0", expected: 0},
+		{name: "out of range", value: "24", wantErr: true},
+		{name: "negative", value: "-1", wantErr: true},
+		{name: "not a number", value: "eight", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("MORNING_HOUR", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.MorningHour != tt.expected {
+				t.Errorf("MorningHour = %d, want %d", cfg.MorningHour, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLoad_AdminSummary tests ADMIN_SUMMARY_TIME and ADMIN_SUMMARY_TZ parsing
+func TestLoad_AdminSummary(t *testing.T) {
+	tests := []struct {
+		name        string
+		time        string
+		tz          string
+		wantEnabled bool
+		wantHour    int
+		wantMinute  int
+		wantZone    string
+		wantErr     bool
+	}{
+		{name: "default", wantZone: "UTC"},
+		{name: "time only", time: "07:30", wantEnabled: true, wantHour: 7, wantMinute: 30, wantZone: "UTC"},
+		{name: "single digit hour", time: "9:05", wantEnabled: true, wantHour: 9, wantMinute: 5, wantZone: "UTC"},
+		{name: "with zone", time: "23:59", tz: "Europe/London", wantEnabled: true, wantHour: 23, wantMinute: 59, wantZone: "Europe/London"},
+		{name: "hour out of range", time: "24:00", wantErr: true},
+		{name: "not a time", time: "morning", wantErr: true},
+		{name: "unknown zone", time: "08:00", tz: "Mars/Olympus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ADMIN_SUMMARY_TIME", tt.time)
+			t.Setenv("ADMIN_SUMMARY_TZ", tt.tz)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.AdminSummaryEnabled != tt.wantEnabled {
+				t.Errorf("AdminSummaryEnabled = %v, want %v", cfg.AdminSummaryEnabled, tt.wantEnabled)
+			}
+			if cfg.AdminSummaryHour != tt.wantHour || cfg.AdminSummaryMinute != tt.wantMinute {
+				t.Errorf("admin summary time = %02d:%02d, want %02d:%02d",
+					cfg.AdminSummaryHour, cfg.AdminSummaryMinute, tt.wantHour, tt.wantMinute)
+			}
+			if got := cfg.AdminSummaryLocation.String(); got != tt.wantZone {
+				t.Errorf("AdminSummaryLocation = %q, want %q", got, tt.wantZone)
+			}
+		})
+	}
+}
+
+// TestLoad_Timezone tests TIMEZONE parsing and the TZ fallback
+func TestLoad_Timezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		tz       string
+		wantZone string
+		wantErr  bool
+	}{
+		{name: "default", wantZone: "UTC"},
+		{name: "TIMEZONE", timezone: "Europe/London", wantZone: "Europe/London"},
+		{name: "TZ fallback", tz: "America/New_York", wantZone: "America/New_York"},
+		{name: "TZ with colon", tz: ":Asia/Tokyo", wantZone: "Asia/Tokyo"},
+		{name: "TIMEZONE wins over TZ", timezone: "Europe/Paris", tz: "Asia/Tokyo", wantZone: "Europe/Paris"},
+		{name: "unknown TIMEZONE", timezone: "Mars/Olympus", wantErr: true},
+		{name: "unknown TZ", tz: "Nowhere", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("TIMEZONE", tt.timezone)
+			t.Setenv("TZ", tt.tz)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := cfg.Location.String(); got != tt.wantZone {
+				t.Errorf("Location = %q, want %q", got, tt.wantZone)
+			}
+		})
+	}
+}
+
+// TestLoad_AllowedUpdates tests ALLOWED_UPDATES parsing and validation
+func TestLoad_AllowedUpdates(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "default (derived from router)", value: "", expected: nil},
+		{name: "custom", value: "message, callback_query", expected: []string{"message", "callback_query"}},
+		{name: "empty entries skipped", value: "message,,poll", expected: []string{"message", "poll"}},
+		{name: "unknown type", value: "message,mesage", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ALLOWED_UPDATES", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !slices.Equal(cfg.AllowedUpdates, tt.expected) {
+				t.Errorf("AllowedUpdates = %v, want %v", cfg.AllowedUpdates, tt.expected)
+			}
+		})
+	}
+}
+
+// TestLoad_Pprof tests that ENABLE_PPROF is refused without a long enough PPROF_TOKEN
+func TestLoad_Pprof(t *testing.T) {
+	tests := []struct {
+		name    string
+		enable  string
+		token   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default off", want: false},
+		{name: "enabled with token", enable: "true", token: "0123456789abcdef", want: true},
+		{name: "enabled without token", enable: "true", wantErr: true},
+		{name: "enabled with short token", enable: "true", token: "secret", wantErr: true},
+		{name: "token alone does nothing", token: "0123456789abcdef", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ENABLE_PPROF", tt.enable)
+			t.Setenv("PPROF_TOKEN", tt.token)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.EnablePprof != tt.want {
+				t.Errorf("EnablePprof = %v, want %v", cfg.EnablePprof, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_RootHealthCheck tests ROOT_HEALTH_CHECK parsing (on by default)
+func TestLoad_RootHealthCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default", value: "", want: true},
+		{name: "disabled", value: "false", want: false},
+		{name: "enabled", value: "true", want: true},
+		{name: "invalid", value: "maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ROOT_HEALTH_CHECK", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.RootHealthCheck != tt.want {
+				t.Errorf("RootHealthCheck = %v, want %v", cfg.RootHealthCheck, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_DropPendingOnStart tests DROP_PENDING_ON_START (off by default)
+func TestLoad_DropPendingOnStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default", value: "", want: false},
+		{name: "enabled", value: "true", want: true},
+		{name: "invalid", value: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("DROP_PENDING_ON_START", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.DropPendingOnStart != tt.want {
+				t.Errorf("DropPendingOnStart = %v, want %v", cfg.DropPendingOnStart, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_ReactToRequests tests REACT_TO_REQUESTS (off by default)
+func TestLoad_ReactToRequests(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+
+	cfg, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.ReactToRequests {
+		t.Errorf("default ReactToRequests = true, want false")
+	}
+
+	t.Setenv("REACT_TO_REQUESTS", "true")
+	cfg, err = Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if !cfg.ReactToRequests {
+		t.Errorf("ReactToRequests with REACT_TO_REQUESTS=true = false, want true")
+	}
+}
+
+// TestLoad_DryRun tests DRY_RUN (off by default, invalid values rejected)
+func TestLoad_DryRun(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+
+	cfg, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.DryRun {
+		t.Errorf("default DryRun = true, want false")
+	}
+
+	t.Setenv("DRY_RUN", "true")
+	cfg, err = Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if !cfg.DryRun {
+		t.Errorf("DryRun with DRY_RUN=true = false, want true")
+	}
+
+	t.Setenv("DRY_RUN", "maybe")
+	if _, err := Load(Overrides{}); err == nil {
+		t.Errorf("Load() with DRY_RUN=maybe: want an error")
+	}
+}
+
+// TestLoad_DiceShowProbability tests DICE_SHOW_PROBABILITY (off by default)
+func TestLoad_DiceShowProbability(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "test-token")
+
+	cfg, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if cfg.DiceShowProbability {
+		t.Errorf("default DiceShowProbability = true, want false")
+	}
+
+	t.Setenv("DICE_SHOW_PROBABILITY", "true")
+	cfg, err = Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if !cfg.DiceShowProbability {
+		t.Errorf("DiceShowProbability with DICE_SHOW_PROBABILITY=true = false, want true")
+	}
+}
+
+// TestLoad_RateLimits tests RATE_LIMIT and WEBHOOK_RATE_LIMIT parsing and validation
+func TestLoad_RateLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        string
+		webhookRate string
+		wantRate    int
+		wantWebhook int
+		wantErr     bool
+	}{
+		{name: "defaults", wantRate: 10, wantWebhook: 50},
+		{name: "custom", rate: "2", webhookRate: "100", wantRate: 2, wantWebhook: 100},
+		{name: "disabled", rate: "0", webhookRate: "0", wantRate: 0, wantWebhook: 0},
+		{name: "negative", rate: "-1", wantErr: true},
+		{name: "not a number", webhookRate: "lots", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("RATE_LIMIT", tt.rate)
+			t.Setenv("WEBHOOK_RATE_LIMIT", tt.webhookRate)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.RateLimit != tt.wantRate || cfg.WebhookRateLimit != tt.wantWebhook) {
+				t.Errorf("RateLimit, WebhookRateLimit = %d, %d, want %d, %d",
+					cfg.RateLimit, cfg.WebhookRateLimit, tt.wantRate, tt.wantWebhook)
+			}
+		})
+	}
+}
+
+// TestLoad_UpdateTimeout tests UPDATE_TIMEOUT parsing (seconds, 0 disables,
+// shorter than ServerWriteTimeout)
+func TestLoad_UpdateTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: 10 * time.Second},
+		{name: "custom", value: "5", want: 5 * time.Second},
+		{name: "just under the write timeout", value: "14", want: 14 * time.Second},
+		{name: "write timeout", value: "15", wantErr: true},
+		{name: "longer than the write timeout", value: "60", wantErr: true},
+		{name: "disabled", value: "0", want: 0},
+		{name: "negative", value: "-5", wantErr: true},
+		{name: "duration syntax", value: "25s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("UPDATE_TIMEOUT", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.UpdateTimeout != tt.want {
+				t.Errorf("UpdateTimeout = %v, want %v", cfg.UpdateTimeout, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_SlowRequestThreshold tests SLOW_REQUEST_THRESHOLD parsing (Go duration)
+func TestLoad_SlowRequestThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: 3 * time.Second},
+		{name: "milliseconds", value: "500ms", want: 500 * time.Millisecond},
+		{name: "zero", value: "0s", wantErr: true},
+		{name: "no unit", value: "3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("SLOW_REQUEST_THRESHOLD", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.SlowRequestThreshold != tt.want {
+				t.Errorf("SlowRequestThreshold = %v, want %v", cfg.SlowRequestThreshold, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_OVHCooldown tests OVH_COOLDOWN parsing (Go duration, 0 disables)
+func TestLoad_OVHCooldown(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: 10 * time.Second},
+		{name: "custom", value: "1m", want: time.Minute},
+		{name: "disabled", value: "0", want: 0},
+		{name: "negative", value: "-5s", wantErr: true},
+		{name: "no unit", value: "10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("OVH_COOLDOWN", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.OVHCooldown != tt.want {
+				t.Errorf("OVHCooldown = %v, want %v", cfg.OVHCooldown, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_OVHTimeout tests OVH_TIMEOUT parsing (Go duration, must be positive)
+func TestLoad_OVHTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", want: 10 * time.Second},
+		{name: "custom", value: "3s", want: 3 * time.Second},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "negative", value: "-5s", wantErr: true},
+		{name: "no unit", value: "10", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("OVH_TIMEOUT", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.OVHTimeout != tt.want {
+				t.Errorf("OVHTimeout = %v, want %v", cfg.OVHTimeout, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_PriceChangeThreshold tests PRICE_CHANGE_THRESHOLD_PCT parsing
+func TestLoad_PriceChangeThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{name: "default", want: 5},
+		{name: "decimal", value: "2.5", want: 2.5},
+		{name: "every change", value: "0", want: 0},
+		{name: "negative", value: "-1", wantErr: true},
+		{name: "percent sign", value: "5%", wantErr: true},
+		{name: "not a number", value: "NaN", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("PRICE_CHANGE_THRESHOLD_PCT", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.PriceChangeThresholdPct != tt.want {
+				t.Errorf("PriceChangeThresholdPct = %v, want %v", cfg.PriceChangeThresholdPct, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoad_Storage tests STORAGE_BACKEND and STORAGE_PATH
+func TestLoad_Storage(t *testing.T) {
+	tests := []struct {
+		name        string
+		backend     string
+		path        string
+		wantBackend string
+		wantErr     bool
+	}{
+		{name: "default", wantBackend: "memory"},
+		{name: "memory ignores path", backend: "memory", path: "state.json", wantBackend: "memory"},
+		{name: "file", backend: "File", path: "/data/state.json", wantBackend: "file"},
+		{name: "file without path", backend: "file", wantErr: true},
+		{name: "firestore", backend: "firestore", wantBackend: "firestore"},
+		{name: "redis without URL", backend: "redis", wantErr: true},
+		{name: "unknown backend", backend: "postgres", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("STORAGE_BACKEND", tt.backend)
+			t.Setenv("STORAGE_PATH", tt.path)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.StorageBackend != tt.wantBackend || cfg.StoragePath != tt.path) {
+				t.Errorf("storage = %q %q, want %q %q", cfg.StorageBackend, cfg.StoragePath, tt.wantBackend, tt.path)
+			}
+		})
+	}
+
+	t.Run("firestore collection", func(t *testing.T) {
+		t.Setenv("BOT_TOKEN", "test-token")
+
+		cfg, err := Load(Overrides{})
+		if err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		if cfg.FirestoreCollection != "run-tbot" {
+			t.Errorf("default FirestoreCollection = %q, want run-tbot", cfg.FirestoreCollection)
+		}
+
+		t.Setenv("FIRESTORE_COLLECTION", "bots/run-tbot")
+		if _, err := Load(Overrides{}); err == nil {
+			t.Errorf("Load() with / in FIRESTORE_COLLECTION error = nil, want error")
+		}
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		t.Setenv("BOT_TOKEN", "test-token")
+		t.Setenv("STORAGE_BACKEND", "redis")
+		t.Setenv("REDIS_URL", "redis://localhost:6379/1")
+
+		cfg, err := Load(Overrides{})
+		if err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		if cfg.StorageBackend != "redis" || cfg.RedisURL != "redis://localhost:6379/1" {
+			t.Errorf("redis storage = %q %q, want redis with REDIS_URL", cfg.StorageBackend, cfg.RedisURL)
+		}
+	})
+}
+
+// TestLoad_MenuButton tests MENU_BUTTON_WEB_APP_URL and MENU_BUTTON_TEXT
+func TestLoad_MenuButton(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		text     string
+		wantURL  string
+		wantText string
+		wantErr  bool
+	}{
+		{name: "default", wantText: "Menu"},
+		{name: "web app", url: " https://example.com/app ", text: "Servers", wantURL: "https://example.com/app", wantText: "Servers"},
+		{name: "plain http", url: "http://example.com/app", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("MENU_BUTTON_WEB_APP_URL", tt.url)
+			t.Setenv("MENU_BUTTON_TEXT", tt.text)
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (cfg.MenuButtonWebAppURL != tt.wantURL || cfg.MenuButtonText != tt.wantText) {
+				t.Errorf("menu button = %q %q, want %q %q", cfg.MenuButtonWebAppURL, cfg.MenuButtonText, tt.wantURL, tt.wantText)
+			}
+		})
+	}
+}
+
+// TestLoad_Features tests ENABLE_* flags (all on by default)
+func TestLoad_Features(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("BOT_TOKEN", "test-token")
+
+		cfg, err := Load(Overrides{})
+		if err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		want := AllFeatures()
+		want.Echo = false // Development only
+		if cfg.Features != want {
+			t.Errorf("Features = %+v, want all enabled but Echo", cfg.Features)
+		}
+	})
+
+	t.Run("echo", func(t *testing.T) {
+		for _, tt := range []struct {
+			environment string
+			value       string
+			want        bool
+		}{
+			{environment: "production", want: false},
+			{environment: "development", want: true},
+			{environment: "production", value: "true", want: true},
+			{environment: "development", value: "false", want: false},
+		} {
+			t.Setenv("BOT_TOKEN", "test-token")
+			t.Setenv("ENVIRONMENT", tt.environment)
+			t.Setenv("ENABLE_ECHO", tt.value)
+
+			cfg, err := Load(Overrides{})
+			if err != nil {
+				t.Fatalf("Load() unexpected error: %v", err)
+			}
+			if cfg.Features.Echo != tt.want || cfg.Features.Enabled(FeatureEcho) != tt.want {
+				t.Errorf("%s, ENABLE_ECHO=%q: Echo = %v, want %v", tt.environment, tt.value, cfg.Features.Echo, tt.want)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv("BOT_TOKEN", "test-token")
+		t.Setenv("ENABLE_TWISTER", "false")
+		t.Setenv("ENABLE_OVH", "0")
+
+		cfg, err := Load(Overrides{})
+		if err != nil {
+			t.Fatalf("Load() unexpected error: %v", err)
+		}
+		want := Features{Dice: true, DoubleDice: true}
+		if cfg.Features != want {
+			t.Errorf("Features = %+v, want %+v", cfg.Features, want)
+		}
+		if cfg.Features.Enabled(FeatureOVH) || !cfg.Features.Enabled(FeatureDice) || !cfg.Features.Enabled("") {
+			t.Errorf("Enabled() disagrees with Features %+v", cfg.Features)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("BOT_TOKEN", "test-token")
+		t.Setenv("ENABLE_DICE", "sometimes")
+
+		if _, err := Load(Overrides{}); err == nil {
+			t.Errorf("Load() expected error for invalid ENABLE_DICE")
+		}
+	})
+}
+
+// TestLoad_BotTokens tests BOT_TOKENS parsing and per-bot ALLOWED_USERS_<NAME>
+func TestLoad_BotTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		tokens   string
+		env      map[string]string
+		expected []BotConfig
+		wantErr  bool
+	}{
+		{name: "unset uses BOT_TOKEN", token: "test-token"},
+		{
+			name:   "two bots share ALLOWED_USERS",
+			tokens: "prod=111:AAA, staging=222:BBB",
+			env:    map[string]string{"ALLOWED_USERS": "1,2"},
+			expected: []BotConfig{
+				{Name: "prod", Token: "111:AAA", AllowedUsers: []int64{1, 2}},
+				{Name: "staging", Token: "222:BBB", AllowedUsers: []int64{1, 2}},
+			},
+		},
+		{
+			name:   "per-bot allowed users",
+			tokens: "prod=111:AAA,staging=222:BBB",
+			env:    map[string]string{"ALLOWED_USERS": "1", "ALLOWED_USERS_STAGING": "3,4"},
+			expected: []BotConfig{
+				{Name: "prod", Token: "111:AAA", AllowedUsers: []int64{1}},
+				{Name: "staging", Token: "222:BBB", AllowedUsers: []int64{3, 4}},
+			},
+		},
+		{
+			name:   "usernames per bot and by default",
+			tokens: "prod=111:AAA,staging=222:BBB",
+			env:    map[string]string{"ALLOWED_USERS": "1,@Alice_Admin", "ALLOWED_USERS_STAGING": "@bob_ops"},
+			expected: []BotConfig{
+				{Name: "prod", Token: "111:AAA", AllowedUsers: []int64{1}, AllowedUsernames: []string{"alice_admin"}},
+				{Name: "staging", Token: "222:BBB", AllowedUsernames: []string{"bob_ops"}},
+			},
+		},
+		{
+			name:   "empty per-bot list means no admins",
+			tokens: "prod=111:AAA",
+			env:    map[string]string{"ALLOWED_USERS": "1", "ALLOWED_USERS_PROD": ""},
+			expected: []BotConfig{
+				{Name: "prod", Token: "111:AAA"},
+			},
+		},
+		{name: "both BOT_TOKEN and BOT_TOKENS", token: "test-token", tokens: "prod=111:AAA", wantErr: true},
+		{name: "neither", wantErr: true},
+		{name: "only separators", tokens: " , ", wantErr: true},
+		{name: "missing name", tokens: "111:AAA", wantErr: true},
+		{name: "empty token", tokens: "prod=", wantErr: true},
+		{name: "invalid name", tokens: "Prod-1=111:AAA", wantErr: true},
+		{name: "duplicate name", tokens: "prod=111:AAA,prod=222:BBB", wantErr: true},
+		{name: "invalid per-bot users", tokens: "prod=111:AAA", env: map[string]string{"ALLOWED_USERS_PROD": "abc"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BOT_TOKEN", tt.token)
+			t.Setenv("BOT_TOKENS", tt.tokens)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(Overrides{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(cfg.Bots, tt.expected) {
+				t.Errorf("Bots = %+v, want %+v", cfg.Bots, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConfig_ForBot tests the per-bot Config copy
+func TestConfig_ForBot(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "")
+	t.Setenv("BOT_TOKENS", "prod=111:AAA,staging=222:BBB")
+	t.Setenv("ALLOWED_USERS", "1")
+	t.Setenv("ALLOWED_USERS_STAGING", "2,@bob_ops")
+	t.Setenv("WEBHOOK_PATH", "/secret-hook")
+
+	cfg, err := Load(Overrides{})
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	staging := cfg.ForBot(cfg.Bots[1])
+	if staging.BotToken != "222:BBB" {
+		t.Errorf("BotToken = %q, want %q", staging.BotToken, "222:BBB")
+	}
+	if staging.WebhookPath != "/secret-hook/staging" {
+		t.Errorf("WebhookPath = %q, want %q", staging.WebhookPath, "/secret-hook/staging")
+	}
+	if !staging.IsUserAllowed(2, "") || staging.IsUserAllowed(1, "") || !staging.IsUserAllowed(3, "Bob_Ops") {
+		t.Errorf("IsUserAllowed: want only user 2 and @bob_ops allowed, AllowedUsers = %v, AllowedUsernames = %v",
+			staging.AllowedUsers, staging.AllowedUsernames)
+	}
+	if staging.Bots != nil {
+		t.Errorf("Bots = %+v, want nil in the per-bot copy", staging.Bots)
+	}
+
+	// The shared config is untouched
+	if cfg.WebhookPath != "/secret-hook" || !cfg.IsUserAllowed(1, "") || cfg.IsUserAllowed(2, "") || cfg.IsUserAllowed(3, "bob_ops") {
+		t.Errorf("ForBot modified the shared config: %+v", cfg)
+	}
+}
+
+// TestMaskToken tests that logged tokens never contain the secret part
+func TestMaskToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "typical", token: "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", want: "1234567...***"},
+		{name: "short id", token: "12345:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", want: "12345...***"},
+		{name: "id of exactly 7 digits", token: "1234567:secret", want: "1234567...***"},
+		{name: "no colon", token: "AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", want: "***"},
+		{name: "leading colon", token: ":AAHdqTcv", want: "***"},
+		{name: "empty", token: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MaskToken(tt.token)
+			if got != tt.want {
+				t.Errorf("MaskToken(%q) = %q, want %q", tt.token, got, tt.want)
+			}
+			if _, secret, ok := strings.Cut(tt.token, ":"); ok && secret != "" && strings.Contains(got, secret) {
+				t.Errorf("MaskToken(%q) = %q contains the secret", tt.token, got)
+			}
+		})
+	}
+}
+
+// TestConfig_String tests that String hides secrets and keeps the rest
+//
+// Cases:
+//   - BOT_TOKEN, BOT_TOKENS tokens, PPROF_TOKEN, a custom WEBHOOK_PATH and
+//     the Redis password don't appear in the output
+//   - Other fields are there, durations and the time zone as text
+//   - The config itself is not modified
+func TestConfig_String(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/London")
+	cfg := &Config{
+		BotToken:             "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw",
+		Port:                 "8080",
+		WebhookPath:          "/webhook-7f3a9c",
+		PprofToken:           "pprof-secret-0123456789",
+		RedisURL:             "redis://:redis-pass@localhost:6379/0",
+		Environment:          "production",
+		AllowedUsers:         []int64{12345},
+		UpdateTimeout:        25 * time.Second,
+		AdminSummaryLocation: loc,
+		Bots:                 []BotConfig{{Name: "staging", Token: "222:BBBsecret"}},
+	}
+
+	got := cfg.String()
+
+	for _, secret := range []string{"AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw", "webhook-7f3a9c", "pprof-secret", "redis-pass", "BBBsecret"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("String() contains secret %q: %s", secret, got)
+		}
+	}
+	for _, want := range []string{`"BotToken":"1234567...***"`, `"Port":"8080"`, `"Environment":"production"`,
+		`"AllowedUsers":[12345]`, `"UpdateTimeout":"25s"`, `"AdminSummaryLocation":"Europe/London"`,
+		`"Token":"222...***"`, `"RedisURL":"redis://:xxxxx@localhost:6379/0"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %s, want it to contain %s", got, want)
+		}
+	}
+
+	if cfg.BotToken != "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw" || cfg.Bots[0].Token != "222:BBBsecret" {
+		t.Errorf("String() modified the config: %+v", cfg)
+	}
+}
+
+// TestLoad_Overrides tests the precedence of command-line overrides,
+// environment variables, the config file and defaults
+func TestLoad_Overrides(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		file         string
+		overrides    Overrides
+		wantPort     string
+		wantEnv      string
+		wantLogLevel slog.Level
+		wantPolling  bool
+		wantErr      bool
+	}{
+		{
+			name:     "defaults",
+			wantPort: "8080", wantEnv: "production", wantLogLevel: slog.LevelInfo,
+		},
+		{
+			name:     "environment",
+			env:      map[string]string{"PORT": "9000", "ENVIRONMENT": "development", "LOG_LEVEL": "warn", "POLLING": "true"},
+			wantPort: "9000", wantEnv: "development", wantLogLevel: slog.LevelWarn, wantPolling: true,
+		},
+		{
+			name:      "flags win over environment",
+			env:       map[string]string{"PORT": "9000", "ENVIRONMENT": "production", "LOG_LEVEL": "warn", "POLLING": "true"},
+			overrides: Overrides{Port: "9090", Environment: "development", LogLevel: "DEBUG", Polling: &disabled},
+			wantPort:  "9090", wantEnv: "development", wantLogLevel: slog.LevelDebug,
+		},
+		{
+			name:     "config file fills unset variables",
+			env:      map[string]string{"PORT": "9000"},
+			file:     "PORT=7000\nLOG_LEVEL=error\n",
+			wantPort: "9000", wantEnv: "production", wantLogLevel: slog.LevelError,
+		},
+		{
+			name:      "flags win over config file",
+			file:      "POLLING=false\nENVIRONMENT=production\n",
+			overrides: Overrides{Polling: &enabled, Environment: "development"},
+			wantPort:  "8080", wantEnv: "development", wantLogLevel: slog.LevelInfo, wantPolling: true,
+		},
+		{name: "invalid log level", overrides: Overrides{LogLevel: "verbose"}, wantErr: true},
+		{name: "invalid polling", env: map[string]string{"POLLING": "sometimes"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Setting every variable (even to "") lets t.Setenv restore
+			// the values loadEnvFile writes when the test ends
+			for _, name := range []string{"PORT", "ENVIRONMENT", "LOG_LEVEL", "POLLING"} {
+				t.Setenv(name, tt.env[name])
+			}
+			t.Setenv("BOT_TOKEN", "test-token")
+
+			overrides := tt.overrides
+			if tt.file != "" {
+				overrides.ConfigFile = filepath.Join(t.TempDir(), ".env")
+				if err := os.WriteFile(overrides.ConfigFile, []byte(tt.file), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(overrides)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Port != tt.wantPort || cfg.Environment != tt.wantEnv ||
+				cfg.LogLevel != tt.wantLogLevel || cfg.Polling != tt.wantPolling {
+				t.Errorf("Port, Environment, LogLevel, Polling = %q, %q, %v, %v; want %q, %q, %v, %v",
+					cfg.Port, cfg.Environment, cfg.LogLevel, cfg.Polling,
+					tt.wantPort, tt.wantEnv, tt.wantLogLevel, tt.wantPolling)
+			}
+		})
+	}
+}
+
+// TestLoadEnvFile tests the .env syntax accepted by the -config flag
+func TestLoadEnvFile(t *testing.T) {
+	writeFile := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), ".env")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("comments, export and quotes", func(t *testing.T) {
+		t.Setenv("RUN_TBOT_TEST_A", "")
+		t.Setenv("RUN_TBOT_TEST_B", "")
+		t.Setenv("RUN_TBOT_TEST_C", "")
+
+		path := writeFile(t, "# local settings\n\nRUN_TBOT_TEST_A=plain\nexport RUN_TBOT_TEST_B = \"two words\"\nRUN_TBOT_TEST_C='a=b'\n")
+		if err := loadEnvFile(path); err != nil {
+			t.Fatalf("loadEnvFile() error = %v", err)
+		}
+
+		want := map[string]string{"RUN_TBOT_TEST_A": "plain", "RUN_TBOT_TEST_B": "two words", "RUN_TBOT_TEST_C": "a=b"}
+		for name, value := range want {
+			if got := os.Getenv(name); got != value {
+				t.Errorf("%s = %q, want %q", name, got, value)
+			}
+		}
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		t.Setenv("RUN_TBOT_TEST_A", "")
+		if err := loadEnvFile(writeFile(t, "RUN_TBOT_TEST_A=1\nnot a setting\n")); err == nil {
+			t.Errorf("loadEnvFile() expected error for a line without =")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if err := loadEnvFile(filepath.Join(t.TempDir(), "missing.env")); err == nil {
+			t.Errorf("loadEnvFile() expected error for a missing file")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package config
+
+// Feature names used by Features.Enabled and handlers.Command.Feature
+const (
+	FeatureDice       = "dice"
+	FeatureDoubleDice = "double_dice"
+	FeatureTwister    = "twister"
+	FeatureOVH        = "ovh"
+	FeatureEcho       = "echo"
+)
+
+// Features holds per-feature enable flags
+// Different deployments may not want every game: a disabled feature's
+// button is left out of the keyboard and its handler is never reached.
+//
+// Each flag is parsed from ENABLE_<NAME> (default true, except the /echo
+// debugging command: on only in development), see loadFeatures.
+// Note: the zero value disables everything - configs built as struct
+// literals (tests) must set Features explicitly, e.g. AllFeatures().
+type Features struct {
+	Dice       bool // ENABLE_DICE: 🎲 Dice button
+	DoubleDice bool // ENABLE_DOUBLE_DICE: 🎲🎲 Double Dice button
+	Twister    bool // ENABLE_TWISTER: 🌀 Twister button
+	OVH        bool // ENABLE_OVH: 🖥️ OVH Servers button, OVH commands and inline queries
+	Echo       bool // ENABLE_ECHO: /echo debugging command (default: development only)
+}
+
+// AllFeatures returns Features with every feature enabled
+// (the Load default in development; production leaves out Echo)
+func AllFeatures() Features {
+	return Features{Dice: true, DoubleDice: true, Twister: true, OVH: true, Echo: true}
+}
+
+// Enabled reports whether the named feature is enabled
+//
+// Parameters:
+//   - name: One of the Feature* constants, or "" for "not a toggleable feature"
+//
+// Returns:
+//   - bool: true if enabled or name is ""; false for disabled or unknown names
+func (f Features) Enabled(name string) bool {
+	switch name {
+	case "":
+		return true
+	case FeatureDice:
+		return f.Dice
+	case FeatureDoubleDice:
+		return f.DoubleDice
+	case FeatureTwister:
+		return f.Twister
+	case FeatureOVH:
+		return f.OVH
+	case FeatureEcho:
+		return f.Echo
+	default:
+		return false
+	}
+}
+
+// loadFeatures reads the ENABLE_* feature flags from the environment
+//
+// Parameters:
+//   - development: Whether ENVIRONMENT is "development" (debugging features default on)
+//
+// Returns:
+//   - Features: Parsed flags (every feature on unless disabled; Echo off in production)
+//   - error: First invalid boolean value
+func loadFeatures(development bool) (Features, error) {
+	var features Features
+
+	flags := []struct {
+		env          string
+		value        *bool
+		defaultValue bool
+	}{
+		{env: "ENABLE_DICE", value: &features.Dice, defaultValue: true},
+		{env: "ENABLE_DOUBLE_DICE", value: &features.DoubleDice, defaultValue: true},
+		{env: "ENABLE_TWISTER", value: &features.Twister, defaultValue: true},
+		{env: "ENABLE_OVH", value: &features.OVH, defaultValue: true},
+		// Debugging tool: echoes anything back, not for production chats
+		{env: "ENABLE_ECHO", value: &features.Echo, defaultValue: development},
+	}
+
+	for _, flag := range flags {
+		enabled, err := parseBoolEnv(flag.env, flag.defaultValue)
+		if err != nil {
+			return Features{}, err
+		}
+		*flag.value = enabled
+	}
+
+	return features, nil
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// maskedSecret replaces secrets that have no safe part to show
+const maskedSecret = "***"
+
+// tokenVisiblePrefix is how many leading characters of a bot token MaskToken keeps
+const tokenVisiblePrefix = 7
+
+// MaskToken hides the secret part of a Telegram bot token for logging
+//
+// A token looks like "123456789:AAHdqTcvCH1vGWJxfSeofSAs0K5PALDsaw":
+// the part before the colon is the bot's numeric ID (public, it's in every
+// getMe response), the part after it is the secret. Only the start of the
+// ID is kept, enough to tell two bots apart in logs.
+//
+// Examples:
+//
+//	MaskToken("123456789:AAHdqTcv...") // "1234567...***"
+//	MaskToken("12345:AAHdqTcv...")     // "12345...***" (never past the colon)
+//	MaskToken("no-colon-here")         // "***" (unknown format: nothing shown)
+//	MaskToken("")                      // ""
+//
+// Parameters:
+//   - token: Bot token (BOT_TOKEN or a BOT_TOKENS entry)
+//
+// Returns:
+//   - string: Loggable form of the token, without any of the secret part
+func MaskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+
+	id, _, found := strings.Cut(token, ":")
+	if !found || id == "" {
+		return maskedSecret
+	}
+	if len(id) > tokenVisiblePrefix {
+		id = id[:tokenVisiblePrefix]
+	}
+	return id + "..." + maskedSecret
+}
+
+// String returns the configuration as JSON, with secrets masked
+//
+// Safe to log anywhere (e.g., slog.Debug("...", "config", cfg.String())):
+//   - BotToken and the BOT_TOKENS tokens: MaskToken
+//   - PprofToken: "***" when set
+//   - WebhookPath: "***" when not the default "/webhook" (the path is a secret)
+//   - RedisURL: password replaced by "xxxxx"
+//
+// Durations and the time zone are written in their text form ("25s", "Europe/London").
+func (c *Config) String() string {
+	// plain has Config's fields but not its methods, so json.Marshal
+	// doesn't call String again
+	type plain Config
+	safe := plain(*c)
+
+	safe.BotToken = MaskToken(c.BotToken)
+	if c.PprofToken != "" {
+		safe.PprofToken = maskedSecret
+	}
+	if c.WebhookPath != "" && c.WebhookPath != "/webhook" {
+		safe.WebhookPath = maskedSecret
+	}
+	if u, err := url.Parse(c.RedisURL); err == nil {
+		safe.RedisURL = u.Redacted()
+	} else if c.RedisURL != "" {
+		safe.RedisURL = maskedSecret
+	}
+	// Cloned: the Bots slice is shared with c
+	safe.Bots = slices.Clone(c.Bots)
+	for i := range safe.Bots {
+		safe.Bots[i].Token = MaskToken(safe.Bots[i].Token)
+	}
+
+	// *time.Location and time.Duration don't encode readably:
+	// same-named outer fields take precedence over plain's
+	view := struct {
+		plain
+		AdminSummaryLocation string `json:",omitempty"`
+		UpdateTimeout        string
+		SlowRequestThreshold string
+		OVHCooldown          string
+		OVHTimeout           string
+	}{
+		plain:                safe,
+		UpdateTimeout:        c.UpdateTimeout.String(),
+		SlowRequestThreshold: c.SlowRequestThreshold.String(),
+		OVHCooldown:          c.OVHCooldown.String(),
+		OVHTimeout:           c.OVHTimeout.String(),
+	}
+	if c.AdminSummaryLocation != nil {
+		view.AdminSummaryLocation = c.AdminSummaryLocation.String()
+	}
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		// Every field is encodable; keep a loggable value just in case
+		return "{}"
+	}
+	return string(data)
+}
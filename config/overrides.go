@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Overrides are configuration values given on the command line (see main.go flags)
+//
+// Precedence, highest first:
+//  1. Overrides (command-line flags)
+//  2. Environment variables
+//  3. ConfigFile (a .env file)
+//  4. Defaults
+//
+// Zero values mean "not given": Load then falls back to the environment.
+type Overrides struct {
+	// Port overrides PORT (flag -port)
+	Port string
+
+	// Environment overrides ENVIRONMENT (flag -env)
+	Environment string
+
+	// LogLevel overrides LOG_LEVEL (flag -log-level)
+	LogLevel string
+
+	// Polling overrides POLLING (flag -polling); nil when the flag wasn't given,
+	// so -polling=false can switch off POLLING=true
+	Polling *bool
+
+	// ConfigFile is a .env file with KEY=VALUE lines (flag -config)
+	// Its values fill in variables that are unset (or empty) in the environment
+	ConfigFile string
+}
+
+// loadEnvFile reads a .env file and sets every variable that isn't set yet,
+// so the rest of Load reads file values exactly like environment variables.
+//
+// Format (the same .env file `make run` reads):
+//
+//	# comment
+//	BOT_TOKEN=123456:ABC
+//	export PORT=9090
+//	WEBHOOK_PATH="/hook-7f3a9c"
+//
+// Parameters:
+//   - path: File path (flag -config)
+//
+// Returns:
+//   - error: If the file can't be read or a line isn't KEY=VALUE
+func loadEnvFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid line %d in config file %s: expected KEY=VALUE", lineNumber, path)
+		}
+		// Quotes are optional: KEY="a b" and KEY=a b are the same
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		// The environment wins over the file
+		if os.Getenv(key) != "" {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("invalid line %d in config file %s: %w", lineNumber, path, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Alrem/run-tbot/config"
+)
+
+// cliFlags holds the parsed command-line flags
+type cliFlags struct {
+	// migrate: apply database schema migrations and exit (see storage package)
+	migrate bool
+
+	// listHandlers: print the commands, buttons and callbacks with their handlers and exit
+	listHandlers bool
+
+	// overrides: configuration flags, applied by config.Load on top of the environment
+	overrides config.Overrides
+}
+
+// parseFlags parses the command line.
+// Configuration comes from environment variables; flags override them for
+// local experiments: go run . -port 9090 -env development -polling
+//
+// Only flags that were actually given end up in Overrides (flag.Visit),
+// so the defaults shown by -help never hide an environment variable.
+//
+// Parameters:
+//   - args: Command-line arguments without the program name (os.Args[1:])
+//   - output: Where usage and errors are printed (os.Stderr)
+//
+// Returns:
+//   - cliFlags: Parsed flags
+//   - error: flag.ErrHelp for -help/-h, or the parse error (already printed to output)
+func parseFlags(args []string, output io.Writer) (cliFlags, error) {
+	fs := flag.NewFlagSet("run-tbot", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	migrate := fs.Bool("migrate", false, "run storage migrations and exit")
+	listHandlers := fs.Bool("list-handlers", false, "print every command, button and callback route (tab-separated) and exit")
+	port := fs.String("port", "8080", "HTTP port to listen on (overrides PORT)")
+	environment := fs.String("env", "production", "environment: development or production (overrides ENVIRONMENT)")
+	logLevel := fs.String("log-level", "info", "minimum log level: debug, info, warn or error (overrides LOG_LEVEL)")
+	polling := fs.Bool("polling", false, "get updates with getUpdates instead of a webhook (overrides POLLING)")
+	configFile := fs.String("config", "", "`.env file` with KEY=VALUE lines for variables unset in the environment")
+
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage: run-tbot [flags]\n\n")
+		fmt.Fprintf(output, "Configuration is read from environment variables (see README).\n")
+		fmt.Fprintf(output, "Flags take precedence over the environment, which takes precedence over -config.\n\n")
+		fmt.Fprintf(output, "Flags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return cliFlags{}, err
+	}
+
+	flags := cliFlags{migrate: *migrate, listHandlers: *listHandlers}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			flags.overrides.Port = *port
+		case "env":
+			flags.overrides.Environment = *environment
+		case "log-level":
+			flags.overrides.LogLevel = *logLevel
+		case "polling":
+			flags.overrides.Polling = polling
+		case "config":
+			flags.overrides.ConfigFile = *configFile
+		}
+	})
+	return flags, nil
+}
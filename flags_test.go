@@ -0,0 +1,71 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/config"
+)
+
+// TestParseFlags tests that only the flags given on the command line become overrides
+func TestParseFlags(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name string
+		args []string
+		want cliFlags
+	}{
+		{name: "no flags", args: nil, want: cliFlags{}},
+		{name: "migrate", args: []string{"-migrate"}, want: cliFlags{migrate: true}},
+		{name: "list handlers", args: []string{"--list-handlers"}, want: cliFlags{listHandlers: true}},
+		{
+			name: "local experiment",
+			args: []string{"-port", "9090", "-env", "development", "-polling"},
+			want: cliFlags{overrides: config.Overrides{Port: "9090", Environment: "development", Polling: &enabled}},
+		},
+		{
+			name: "explicit false overrides POLLING",
+			args: []string{"-polling=false", "--log-level=debug", "-config", ".env.local"},
+			want: cliFlags{overrides: config.Overrides{LogLevel: "debug", Polling: &disabled, ConfigFile: ".env.local"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlags(tt.args, io.Discard)
+			if err != nil {
+				t.Fatalf("parseFlags(%v) error = %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseFlags(%v) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseFlags_Help tests that -help lists every flag with its default
+func TestParseFlags_Help(t *testing.T) {
+	var output strings.Builder
+	if _, err := parseFlags([]string{"-help"}, &output); !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("parseFlags(-help) error = %v, want flag.ErrHelp", err)
+	}
+
+	for _, want := range []string{"Usage: run-tbot", "-port", `(default "8080")`, "-env", `(default "production")`,
+		"-log-level", `(default "info")`, "-polling", "-config", "-migrate"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("usage does not contain %q:\n%s", want, output.String())
+		}
+	}
+}
+
+// TestParseFlags_Unknown tests that unknown flags are rejected
+func TestParseFlags_Unknown(t *testing.T) {
+	if _, err := parseFlags([]string{"-verbose"}, io.Discard); err == nil {
+		t.Errorf("parseFlags(-verbose) expected error")
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// startTime is when the bot process started (used for uptime in stats)
+var startTime = time.Now()
+
+// authorizedKey marks a context whose message already passed requireAuthorized
+// (see authorize); the value is true.
+type authorizedKey struct{}
+
+// requireAuthorized checks that the message author is in ALLOWED_USERS.
+// Sends a "not authorized" reply and returns false otherwise.
+//
+// Every check is recorded in the audit log (/audit), allowed or denied:
+// this is the one place all private features go through.
+//
+// A context returned by authorize has already been checked: the message
+// is allowed without a second audit entry.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for the error reply
+//   - message: Message that triggered a private feature
+//   - cfg: Application configuration with AllowedUsers
+//
+// Returns:
+//   - bool: true if the user may continue
+func requireAuthorized(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) bool {
+	log := logger.FromContext(ctx)
+
+	if checked, _ := ctx.Value(authorizedKey{}).(bool); checked {
+		return true
+	}
+
+	if cfg.IsUserAllowed(message.From.ID, message.From.UserName) {
+		recordMessageAudit(ctx, message, true)
+		return true
+	}
+	recordMessageAudit(ctx, message, false)
+
+	// Log unauthorized access attempt
+	log.Info("Unauthorized access attempt",
+		"username", message.From.UserName,
+		"text", message.Text)
+
+	errorMsg := replyTo(message,
+		tgfmt.EscapeMarkdownV2(translate(userLanguage(ctx, message.From), "unauthorized")))
+
+	if _, err := sendFormattedReply(ctx, bot, message, errorMsg); err != nil {
+		log.Error("Failed to send authorization error message",
+			"error", err,
+			"message_type", messageType(errorMsg))
+	}
+	return false
+}
+
+// authorize is requireAuthorized for code that goes on to other checks
+// (routeMessage for private commands, handlers that parse arguments)
+//
+// Why?
+//   - Authorization must come first: parse errors and usage help would
+//     otherwise reach unauthorized users, unaudited
+//   - The returned context records the check, so the requireAuthorized
+//     calls further down (e.g., in runOVHFetch) neither ask again nor
+//     write a second audit entry
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for the error reply
+//   - message: Message that triggered a private feature
+//   - cfg: Application configuration with AllowedUsers
+//   - feature: Feature flag for handler_invocations_total{result="unauthorized"}
+//     (e.g., config.FeatureOVH; "" = not counted, for commands without a flag)
+//
+// Returns:
+//   - context.Context: ctx, marked as authorized if the user may continue
+//   - bool: true if the user may continue
+func authorize(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config, feature string) (context.Context, bool) {
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		if feature != "" {
+			handlerInvocations.Inc(feature, resultUnauthorized)
+		}
+		return ctx, false
+	}
+	return context.WithValue(ctx, authorizedKey{}, true), true
+}
+
+// HandleAdminStats handles the "📊 Stats" admin button.
+// Shows basic runtime statistics of the bot process.
+//
+// Statistics:
+//   - Uptime since process start
+//   - Number of goroutines (runtime.NumGoroutine)
+//   - Heap memory in use (runtime.ReadMemStats)
+//   - Number of authorized users
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing button click
+//   - cfg: Application configuration (needed for authorization check)
+func HandleAdminStats(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	text := formatAdminStats(userLanguage(ctx, message.From), time.Since(startTime), runtime.NumGoroutine(), mem.HeapAlloc, cfg.AllowedUsersCount())
+
+	msg := replyTo(message, text)
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send stats message",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// formatAdminStats builds the MarkdownV2 stats message.
+//
+// Parameters:
+//   - lang: Language of the text (see userLanguage)
+//   - uptime: Time since process start
+//   - goroutines: Current goroutine count
+//   - heapBytes: Heap memory in use
+//   - allowedUsers: Number of authorized users
+//
+// Returns:
+//   - string: Formatted message with MarkdownV2 escaping
+func formatAdminStats(lang string, uptime time.Duration, goroutines int, heapBytes uint64, allowedUsers int) string {
+	return "📊 " + tgfmt.Bold(translate(lang, "admin_stats_title")) + "\n\n" +
+		tgfmt.EscapeMarkdownV2(translate(lang, "admin_stats",
+			uptime.Truncate(time.Second).String(), goroutines, float64(heapBytes)/(1024*1024), allowedUsers))
+}
+
+// HandleAdminBroadcast handles the "📢 Broadcast" admin button.
+//
+// A button can't carry the text to send, so it explains /broadcast <text>
+// and how many known chats it would reach.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing button click
+//   - cfg: Application configuration (needed for authorization check)
+func HandleAdminBroadcast(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	lang := userLanguage(ctx, message.From)
+	text := "📢 " + tgfmt.Bold(translate(lang, "admin_broadcast_title")) + "\n\n" +
+		tgfmt.EscapeMarkdownV2(translate(lang, "admin_broadcast", len(knownChatIDs())))
+
+	msg := replyTo(message, text)
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send broadcast message",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// HandleAdminSettings handles the "⚙️ Settings" admin button.
+// Shows the current (read-only) configuration flags.
+// Secrets like BOT_TOKEN are never shown.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing button click
+//   - cfg: Application configuration (needed for authorization check)
+func HandleAdminSettings(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	msg := replyTo(message, formatAdminSettings(userLanguage(ctx, message.From), cfg))
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send settings message",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// formatAdminSettings builds the MarkdownV2 settings message.
+//
+// Parameters:
+//   - lang: Language of the text (see userLanguage)
+//   - cfg: Application configuration
+//
+// Returns:
+//   - string: Formatted message with MarkdownV2 escaping
+func formatAdminSettings(lang string, cfg *config.Config) string {
+	return "⚙️ " + tgfmt.Bold(translate(lang, "admin_settings_title")) + "\n\n" +
+		tgfmt.EscapeMarkdownV2(translate(lang, "admin_settings",
+			cfg.Environment, cfg.Location.String(), cfg.UseAnimatedDice, cfg.DiceShowProbability, cfg.StrictMarkdown, cfg.HandleEditedMessages))
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestRouteUpdate_AdminButtons tests routing and authorization of admin buttons.
+//
+// Authorized user (12345): gets the feature response
+// Unauthorized user (99999): gets the "not authorized" message
+func TestRouteUpdate_AdminButtons(t *testing.T) {
+	tests := []struct {
+		button   string
+		wantText string // Expected substring for authorized user
+	}{
+		{button: "📊 Stats", wantText: "Bot Stats"},
+		{button: "📢 Broadcast", wantText: "Broadcast"},
+		{button: "⚙️ Settings", wantText: "Settings"},
+	}
+
+	for _, tt := range tests {
+		for _, userID := range []int64{12345, 99999} {
+			authorized := userID == 12345
+
+			t.Run(tt.button, func(t *testing.T) {
+				sender := &recordingSender{}
+				RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage(tt.button, userID)}, testConfig())
+
+				messages := sender.messages()
+				if len(messages) != 1 {
+					t.Fatalf("%s (authorized=%v) sent %d messages, want 1", tt.button, authorized, len(messages))
+				}
+
+				want := tt.wantText
+				if !authorized {
+					want = "only available to authorized users"
+				}
+				if !strings.Contains(messages[0].Text, want) {
+					t.Errorf("%s (authorized=%v) = %q, want it to contain %q", tt.button, authorized, messages[0].Text, want)
+				}
+			})
+		}
+	}
+}
+
+// TestHandleStart_Keyboard verifies authorized users get the admin keyboard on /start
+func TestHandleStart_Keyboard(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		wantRows int
+	}{
+		{name: "authorized user gets admin keyboard", userID: 12345, wantRows: 4},
+		{name: "public user gets main keyboard", userID: 99999, wantRows: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			HandleStart(context.Background(), sender, createTestMessage("/start", tt.userID), testConfig())
+
+			messages := sender.messages()
+			if len(messages) != 1 {
+				t.Fatalf("HandleStart sent %d messages, want 1", len(messages))
+			}
+			keyboard, ok := messages[0].ReplyMarkup.(tgbotapi.ReplyKeyboardMarkup)
+			if !ok {
+				t.Fatalf("ReplyMarkup type = %T, want ReplyKeyboardMarkup", messages[0].ReplyMarkup)
+			}
+			if len(keyboard.Keyboard) != tt.wantRows {
+				t.Errorf("keyboard has %d rows, want %d", len(keyboard.Keyboard), tt.wantRows)
+			}
+		})
+	}
+}
+
+// TestRequireAuthorized_Username tests access granted by an @username allowlist entry
+// The user's ID isn't allowed; their username is, in a different case.
+func TestRequireAuthorized_Username(t *testing.T) {
+	cfg := testConfig()
+	cfg.AllowedUsernames = []string{"alice_admin"}
+
+	for _, tt := range []struct {
+		username string
+		want     bool
+	}{
+		{username: "Alice_Admin", want: true},
+		{username: "mallory", want: false},
+		{username: "", want: false},
+	} {
+		message := createTestMessage("/echo hi", 777)
+		message.From.UserName = tt.username
+		sender := &recordingSender{}
+
+		if got := requireAuthorized(context.Background(), sender, message, cfg); got != tt.want {
+			t.Errorf("requireAuthorized(@%s) = %v, want %v", tt.username, got, tt.want)
+		}
+		if denied := len(sender.messages()) == 1; denied == tt.want {
+			t.Errorf("@%s: sent %d messages, want the denial only when not allowed", tt.username, len(sender.messages()))
+		}
+	}
+}
+
+// TestAdminMessagesMarkdownV2 verifies admin messages are valid MarkdownV2
+func TestAdminMessagesMarkdownV2(t *testing.T) {
+	stats := formatAdminStats("en", 90*time.Minute+1500*time.Millisecond, 12, 5*1024*1024, 2)
+	if err := tgfmt.ValidateMarkdownV2(stats); err != nil {
+		t.Errorf("formatAdminStats() is not valid MarkdownV2: %v\n\nGot:\n%s", err, stats)
+	}
+	if !strings.Contains(stats, "1h30m1s") || !strings.Contains(stats, "5\\.0 MB") {
+		t.Errorf("formatAdminStats() missing uptime or heap size:\n%s", stats)
+	}
+
+	settings := formatAdminSettings("en", &config.Config{Environment: "production", UseAnimatedDice: true, BotToken: "secret-token"})
+	if err := tgfmt.ValidateMarkdownV2(settings); err != nil {
+		t.Errorf("formatAdminSettings() is not valid MarkdownV2: %v\n\nGot:\n%s", err, settings)
+	}
+	if strings.Contains(settings, "secret-token") {
+		t.Errorf("formatAdminSettings() leaks BOT_TOKEN:\n%s", settings)
+	}
+}
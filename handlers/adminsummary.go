@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// offerSnapshots remembers the offers of the last admin summary (set by main.go via SetOfferSnapshots)
+// nil: summaries are sent without a changelog
+var offerSnapshots *ovh.SnapshotStore
+
+// SetOfferSnapshots gives the admin summary a place to keep the offers it sent,
+// so the next summary can list what changed since
+// Call once at startup, before the scheduler starts (read without locking).
+//
+// Parameters:
+//   - snapshots: Usually ovh.NewSnapshotStore on the application's storage
+func SetOfferSnapshots(snapshots *ovh.SnapshotStore) {
+	offerSnapshots = snapshots
+}
+
+// NextRunDelay returns how long to wait until the next hour:minute wall-clock
+// time in loc. The daily admin summary scheduler sleeps for this long.
+//
+// Rules:
+//   - Target later today: wait until then
+//   - Target already passed (or exactly now): wait until tomorrow's target
+//   - Day boundaries are those of loc, not of now's own time zone
+//   - DST: "tomorrow" is a calendar day, so the delay is 23h or 25h across a change;
+//     a target inside the skipped hour is moved forward by time.Date (02:30 → 03:30)
+//
+// Parameters:
+//   - now: Current time (any time zone)
+//   - hour: Target hour (0-23) in loc
+//   - minute: Target minute (0-59)
+//   - loc: Time zone of the target time (cfg.AdminSummaryLocation)
+//
+// Returns:
+//   - time.Duration: Time until the next run (always > 0)
+func NextRunDelay(now time.Time, hour, minute int, loc *time.Location) time.Duration {
+	local := now.In(loc)
+	year, month, day := local.Date()
+
+	next := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if !next.After(now) {
+		// time.Date normalizes day+1 (Jan 31 → Feb 1, Dec 31 → Jan 1)
+		next = time.Date(year, month, day+1, hour, minute, 0, 0, loc)
+	}
+	return next.Sub(now)
+}
+
+// SendAdminSummary sends the daily OVH summary to every admin (ALLOWED_USERS).
+// Called by the scheduler goroutine in main.go at ADMIN_SUMMARY_TIME.
+//
+// Message: the same top offers list as the "🖥️ OVH Servers" button (formatOVHResults),
+// with its "as of" line in cfg.Location (TIMEZONE)
+//   - Followed by the changes since the previous summary (see summaryChangelog)
+//   - Sent to each admin's private chat (chat ID = user ID), in the
+//     admin's language (see chatLanguage)
+//   - Admins that blocked the bot are skipped (see IsChatBlocked)
+//   - If OVH is unavailable, nothing is sent (the error is logged)
+//
+// Parameters:
+//   - ctx: Context for the OVH call (cancelled on shutdown)
+//   - bot: Bot sender for sending messages
+//   - cfg: Application configuration (admins list)
+//
+// Returns:
+//   - int: Number of admins the summary was delivered to
+func SendAdminSummary(ctx context.Context, bot BotSender, cfg *config.Config) int {
+	log := logger.FromContext(ctx)
+
+	if len(cfg.AllowedUsers) == 0 {
+		return 0
+	}
+
+	// Step 1: Fetch the offers and compare them once for all admins
+	query := defaultOVHQuery()
+	offers, err := getTopOffers(ctx, query.options()...)
+	if err != nil {
+		log.Error("Failed to fetch OVH offers for admin summary",
+			"error", err)
+		return 0
+	}
+	now := time.Now()
+	changes, hasChanges := summaryChangelog(ctx, offers, now)
+
+	// Step 2: Send to every admin that can still receive messages,
+	// formatted once per language
+	texts := make(map[string]string)
+	delivered := 0
+	for _, userID := range cfg.AllowedUsers {
+		if IsChatBlocked(userID) {
+			continue
+		}
+
+		query.lang = chatLanguage(ctx, userID)
+		text, ok := texts[query.lang]
+		if !ok {
+			text = formatOVHResults(offers, query, formatAsOf(query.lang, now, cfg.Location))
+			if hasChanges {
+				text += formatSummaryChanges(query.lang, changes, cfg.AdminSummaryLocation)
+			}
+			texts[query.lang] = text
+		}
+
+		msg := tgbotapi.NewMessage(userID, text)
+		msg.DisableWebPagePreview = true
+		if _, err := sendFormatted(ctx, bot, msg); err != nil {
+			log.Error("Failed to send admin summary",
+				"error", err,
+				"message_type", messageType(msg),
+				"chat_id", userID)
+			continue
+		}
+		delivered++
+	}
+
+	log.Info("Admin summary sent",
+		"admins", len(cfg.AllowedUsers),
+		"offers_count", len(offers),
+		"delivered", delivered)
+	return delivered
+}
+
+// summaryChanges is what changed since the previous admin summary
+type summaryChanges struct {
+	since     time.Time // Time of the previous summary
+	changelog string    // ovh.FormatOfferChangelog lines ("" = no changes)
+}
+
+// summaryChangelog compares offers with the previous summary's snapshot
+// and saves offers as the snapshot for the next summary.
+//
+// The summary shows the top ovhTop offers, so "new" and "gone" mean an offer
+// entered or left that list (it may still be in stock further down).
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - offers: Offers just fetched for the summary
+//   - now: Fetch time (saved with the snapshot)
+//
+// Returns:
+//   - summaryChanges: Changes since the previous snapshot
+//   - bool: false when there is no snapshot store or no previous snapshot
+//     (the first summary): no changes section then
+func summaryChangelog(ctx context.Context, offers []ovh.Offer, now time.Time) (summaryChanges, bool) {
+	log := logger.FromContext(ctx)
+
+	if offerSnapshots == nil {
+		return summaryChanges{}, false
+	}
+
+	previous, found, err := offerSnapshots.Load(ctx, ovhSubsidiary, ovhDatacenter)
+	if err != nil {
+		// A corrupt snapshot is replaced by the Save below
+		log.Warn("Failed to load previous offer snapshot",
+			"error", err)
+	}
+	if err := offerSnapshots.Save(ctx, ovhSubsidiary, ovhDatacenter, offers, now); err != nil {
+		log.Warn("Failed to save offer snapshot",
+			"error", err)
+	}
+	if !found {
+		return summaryChanges{}, false
+	}
+
+	return summaryChanges{
+		since:     previous.FetchedAt,
+		changelog: ovh.FormatOfferChangelog(ovh.DiffOffers(previous.Offers, offers)),
+	}, true
+}
+
+// formatSummaryChanges formats the changes section of the admin summary
+//
+// Parameters:
+//   - lang: Language of the heading (the changelog lines come from the ovh package)
+//   - changes: Result of summaryChangelog
+//   - loc: Time zone for the previous summary's time (nil means UTC)
+//
+// Returns:
+//   - string: MarkdownV2 section starting with a blank line
+func formatSummaryChanges(lang string, changes summaryChanges, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	since := changes.since.In(loc).Format("Jan 2 15:04")
+	if changes.changelog == "" {
+		return "\n\n" + tgfmt.EscapeMarkdownV2(translate(lang, "summary_no_changes", since))
+	}
+	return "\n\n" + tgfmt.Bold(translate(lang, "summary_changes", since)) + "\n" + tgfmt.EscapeMarkdownV2(changes.changelog)
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/storage"
+	"github.com/Alrem/run-tbot/tgfmt"
+)
+
+// TestNextRunDelay tests the time until the next daily admin summary
+//
+// Cases:
+//   - Same day, next day, exactly at the target time
+//   - Month and year boundaries
+//   - Target zone differs from now's zone (local date ahead of / behind UTC)
+//   - DST changes make the next day 23h or 25h long
+func TestNextRunDelay(t *testing.T) {
+	loadLocation := func(name string) *time.Location {
+		t.Helper()
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			t.Fatalf("LoadLocation(%q) error = %v", name, err)
+		}
+		return loc
+	}
+	london := loadLocation("Europe/London")
+	tokyo := loadLocation("Asia/Tokyo")
+	newYork := loadLocation("America/New_York")
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		hour   int
+		minute int
+		loc    *time.Location
+		want   time.Duration
+	}{
+		{"later today", time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC), 8, 30, time.UTC, 2*time.Hour + 30*time.Minute},
+		{"already passed today", time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), 8, 0, time.UTC, 23 * time.Hour},
+		{"exactly now runs tomorrow", time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC), 8, 0, time.UTC, 24 * time.Hour},
+		{"seconds past the minute", time.Date(2025, 6, 1, 7, 59, 30, 0, time.UTC), 8, 0, time.UTC, 30 * time.Second},
+		{"end of month", time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC), 8, 0, time.UTC, 10 * time.Hour},
+		{"end of year", time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC), 0, 15, time.UTC, 1*time.Hour + 15*time.Minute},
+		{"zone already on next day", time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC), 9, 0, tokyo, 30 * time.Minute},
+		{"zone passed target on its next day", time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC), 0, 30, tokyo, 23*time.Hour + 30*time.Minute},
+		{"zone still on previous day", time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC), 22, 0, newYork, time.Hour},
+		{"now given in another zone", time.Date(2025, 6, 1, 9, 0, 0, 0, tokyo), 8, 0, london, 7 * time.Hour},
+		{"spring forward day is 23h", time.Date(2025, 3, 29, 8, 0, 0, 0, london), 8, 0, london, 23 * time.Hour},
+		{"fall back day is 25h", time.Date(2025, 10, 25, 8, 0, 0, 0, london), 8, 0, london, 25 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextRunDelay(tt.now, tt.hour, tt.minute, tt.loc)
+			if got != tt.want {
+				t.Errorf("NextRunDelay(%v, %02d:%02d %s) = %v, want %v",
+					tt.now, tt.hour, tt.minute, tt.loc, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSendAdminSummary tests the daily admin summary delivery
+//
+// Cases:
+//   - Every admin gets the formatted top offers, blocked chats are skipped
+//   - OVH errors send nothing
+//   - No admins: OVH is not called at all
+func TestSendAdminSummary(t *testing.T) {
+	stub := func(t *testing.T, offers []ovh.Offer, err error) *int {
+		t.Helper()
+		calls := 0
+		oldGetTopOffers := getTopOffers
+		getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+			calls++
+			return offers, err
+		}
+		t.Cleanup(func() { getTopOffers = oldGetTopOffers })
+		return &calls
+	}
+
+	t.Run("sends top offers to admins and skips blocked chats", func(t *testing.T) {
+		offers := []ovh.Offer{{InvoiceName: "KS-1", FQN: "24ska01.ram-16g.softraid-2x2000sa", Price: 5.99, Currency: "EUR", Datacenter: "lon"}}
+		stub(t, offers, nil)
+		setChatBlocked(2, true)
+		defer setChatBlocked(2, false)
+
+		cfg := testConfig()
+		cfg.AllowedUsers = []int64{1, 2, 3}
+
+		sender := &recordingSender{}
+		if got := SendAdminSummary(context.Background(), sender, cfg); got != 2 {
+			t.Errorf("SendAdminSummary() = %d, want 2 delivered", got)
+		}
+
+		messages := sender.messages()
+		if len(messages) != 2 || messages[0].ChatID != 1 || messages[1].ChatID != 3 {
+			t.Fatalf("sent %+v, want messages to chats 1 and 3", messages)
+		}
+		// The "as of" time is the current minute (testConfig has no zone: UTC)
+		asOf := regexp.MustCompile(`as of \d{2}:\d{2} UTC`)
+		want := formatOVHResults(offers, defaultOVHQuery(), tgfmt.Italic("as of HH:MM UTC"))
+		for _, msg := range messages {
+			if got := asOf.ReplaceAllString(msg.Text, "as of HH:MM UTC"); got != want {
+				t.Errorf("message text = %q, want formatOVHResults output %q", msg.Text, want)
+			}
+		}
+		if !strings.Contains(messages[0].Text, "KS") {
+			t.Errorf("message %q does not mention the offer", messages[0].Text)
+		}
+	})
+
+	t.Run("OVH error sends nothing", func(t *testing.T) {
+		stub(t, nil, errors.New("ovh down"))
+
+		sender := &recordingSender{}
+		if got := SendAdminSummary(context.Background(), sender, testConfig()); got != 0 {
+			t.Errorf("SendAdminSummary() = %d, want 0", got)
+		}
+		if len(sender.sent) != 0 {
+			t.Errorf("sent %d messages, want none", len(sender.sent))
+		}
+	})
+
+	t.Run("no admins", func(t *testing.T) {
+		calls := stub(t, nil, nil)
+
+		cfg := testConfig()
+		cfg.AllowedUsers = nil
+
+		sender := &recordingSender{}
+		if got := SendAdminSummary(context.Background(), sender, cfg); got != 0 {
+			t.Errorf("SendAdminSummary() = %d, want 0", got)
+		}
+		if *calls != 0 {
+			t.Errorf("getTopOffers called %d times, want 0 without admins", *calls)
+		}
+	})
+}
+
+// TestSendAdminSummary_Changelog tests the changes section of consecutive summaries
+//
+// Cases:
+//   - First summary: no previous snapshot, no changes section
+//   - Price drop and new offer: listed under "Changes since"
+//   - Same offers again: "No changes since"
+func TestSendAdminSummary_Changelog(t *testing.T) {
+	SetOfferSnapshots(ovh.NewSnapshotStore(storage.NewMemoryStore()))
+	defer SetOfferSnapshots(nil)
+
+	oldGetTopOffers := getTopOffers
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	ks1 := ovh.Offer{InvoiceName: "KS-1", FQN: "24ska01.ram-32g", Price: 15.99, Currency: "GBP", Datacenter: "lon"}
+	ks2 := ovh.Offer{InvoiceName: "KS-2", FQN: "24sk20.ram-64g", Price: 35.99, Currency: "GBP", Datacenter: "lon"}
+	cheaperKS1 := ks1
+	cheaperKS1.Price = 13.99
+
+	tests := []struct {
+		name      string
+		offers    []ovh.Offer
+		want      []string
+		wantNotIn []string
+	}{
+		{name: "first summary", offers: []ovh.Offer{ks1}, wantNotIn: []string{"Changes since", "No changes"}},
+		{name: "changes", offers: []ovh.Offer{cheaperKS1, ks2}, want: []string{
+			"*Changes since ",
+			"KS\\-1 dropped from £15\\.99 to £13\\.99",
+			"new: KS\\-2 \\(24sk20\\.ram\\-64g\\) at £35\\.99",
+		}},
+		{name: "no changes", offers: []ovh.Offer{cheaperKS1, ks2}, want: []string{"No changes since "}, wantNotIn: []string{"Changes since"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+				return tt.offers, nil
+			}
+
+			sender := &recordingSender{}
+			if got := SendAdminSummary(context.Background(), sender, testConfig()); got != 1 {
+				t.Fatalf("SendAdminSummary() = %d, want 1 delivered", got)
+			}
+			text := sender.messages()[0].Text
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("summary %q does not contain %q", text, want)
+				}
+			}
+			for _, unwanted := range tt.wantNotIn {
+				if strings.Contains(text, unwanted) {
+					t.Errorf("summary %q contains %q", text, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// auditParamsLimit caps the command arguments kept in an audit entry (runes)
+const auditParamsLimit = 100
+
+// auditWriteTimeout bounds one audit log write
+const auditWriteTimeout = 5 * time.Second
+
+// Limits of the /audit [n] argument
+const (
+	auditDefaultEntries = 10
+	auditMaxEntries     = 50
+)
+
+// auditLog records private-feature access (set by main.go via SetAuditStore)
+// nil: no audit log, /audit explains that
+var auditLog *storage.AuditStore
+
+// SetAuditStore enables the audit log of private features (/audit)
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - store: Usually storage.NewAuditStore on the application's storage
+func SetAuditStore(store *storage.AuditStore) {
+	auditLog = store
+}
+
+// recordMessageAudit writes the audit entry for a private-feature message
+// Called by requireAuthorized for every allowed and denied request, so
+// handlers that stop at the authorization check are recorded too.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - message: Message that triggered the private feature
+//   - allowed: Result of the authorization check
+func recordMessageAudit(ctx context.Context, message *tgbotapi.Message, allowed bool) {
+	recordAudit(ctx, message.From, auditFeature(message), message.CommandArguments(), allowed)
+}
+
+// recordAudit writes an audit entry for a private-feature request
+//
+// The write is synchronous, so the entry exists before the handler goes on,
+// but it doesn't inherit the request's cancellation: a request cancelled
+// right after the check is still recorded. Failures are logged, never shown
+// to the user.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - user: Who made the request (nil: nothing is recorded)
+//   - feature: Private feature, e.g. "/ovh" or "inline_query"
+//   - params: Request arguments (trimmed and truncated to auditParamsLimit)
+//   - allowed: Result of the authorization check
+func recordAudit(ctx context.Context, user *tgbotapi.User, feature, params string, allowed bool) {
+	if auditLog == nil || user == nil {
+		return
+	}
+
+	entry := storage.AuditEntry{
+		Time:     time.Now().UTC(),
+		UserID:   user.ID,
+		Username: user.UserName,
+		Feature:  feature,
+		Allowed:  allowed,
+		Params:   truncateRunes(strings.TrimSpace(params), auditParamsLimit),
+	}
+
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+
+	if err := auditLog.AppendAudit(writeCtx, entry); err != nil {
+		logger.FromContext(ctx).Error("Failed to write audit entry",
+			"error", err,
+			"feature", entry.Feature,
+			"allowed", allowed)
+	}
+}
+
+// auditFeature names the private feature a message asked for
+// Same names as HandlerName ("/ovh", "button:ovh"); admin buttons, which
+// HandlerName lumps together as "message", keep their label ("button:📊 Stats").
+func auditFeature(message *tgbotapi.Message) string {
+	if name := messageHandlerName(message); name != "message" {
+		return name
+	}
+	return "button:" + message.Text
+}
+
+// truncateRunes shortens s to at most limit runes, marking the cut with "…"
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// HandleAudit handles the /audit [n] command (private, for admins).
+// Shows the last n audit entries (default 10, max 50), newest first.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /audit command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleAudit(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	var text string
+	lang := userLanguage(ctx, message.From)
+	n, err := parseAuditCount(message.CommandArguments())
+	switch {
+	case err != nil:
+		text = translate(lang, "audit_usage", auditMaxEntries, auditDefaultEntries)
+	case auditLog == nil:
+		text = translate(lang, "audit_disabled")
+	default:
+		entries, err := auditLog.RecentAudit(ctx, n)
+		if err != nil {
+			log.Error("Failed to load audit entries", "error", err)
+			text = translate(lang, "audit_failed")
+		} else {
+			text = formatAuditEntries(lang, entries)
+		}
+	}
+
+	msg := replyTo(message, text)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /audit report",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// parseAuditCount parses the /audit argument: empty for the default, or 1-auditMaxEntries
+func parseAuditCount(arg string) (int, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return auditDefaultEntries, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > auditMaxEntries {
+		return 0, fmt.Errorf("invalid number of entries: %q", arg)
+	}
+	return n, nil
+}
+
+// formatAuditEntries builds the plain-text /audit message
+//
+// Format (newest first):
+//
+//	🔍 Last 2 audit entries:
+//	03-01 12:05 ⛔ @mallory (666) /ovh
+//	03-01 12:00 ✅ @alice (123) /currency GB
+//
+// Parameters:
+//   - lang: Language of the header (see userLanguage)
+//   - entries: Entries to show, newest first
+//
+// Returns:
+//   - string: Plain text message
+func formatAuditEntries(lang string, entries []storage.AuditEntry) string {
+	if len(entries) == 0 {
+		return translate(lang, "audit_empty")
+	}
+
+	var b strings.Builder
+	b.WriteString(translate(lang, "audit_header", len(entries)))
+	for _, e := range entries {
+		result := "✅"
+		if !e.Allowed {
+			result = "⛔"
+		}
+		who := strconv.FormatInt(e.UserID, 10)
+		if e.Username != "" {
+			who = "@" + e.Username + " (" + who + ")"
+		}
+
+		fmt.Fprintf(&b, "\n%s %s %s %s", e.Time.UTC().Format("01-02 15:04"), result, who, e.Feature)
+		if e.Params != "" {
+			b.WriteString(" " + e.Params)
+		}
+	}
+	return b.String()
+}
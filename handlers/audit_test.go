@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// withAuditLog installs a fresh in-memory audit log for one test
+func withAuditLog(t *testing.T) *storage.AuditStore {
+	t.Helper()
+	old := auditLog
+	t.Cleanup(func() { auditLog = old })
+
+	auditLog = storage.NewAuditStore(storage.NewMemoryStore())
+	return auditLog
+}
+
+// TestRequireAuthorized_Audit tests that allowed and denied requests are both recorded
+//
+// Cases:
+//   - Authorized command: allowed entry with its arguments
+//   - Unauthorized command: denied entry, even though the handler stops early
+//   - Admin button: named after its label
+//   - Unauthorized inline query: denied entry
+func TestRequireAuthorized_Audit(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(sender *recordingSender)
+		want storage.AuditEntry
+	}{
+		{
+			name: "allowed command",
+			run: func(sender *recordingSender) {
+				HandleEcho(context.Background(), sender, createTestMessage("/echo hello", 12345), testConfig())
+			},
+			want: storage.AuditEntry{UserID: 12345, Username: "testuser", Feature: "/echo", Allowed: true, Params: "hello"},
+		},
+		{
+			name: "denied command",
+			run: func(sender *recordingSender) {
+				HandleOVHCheck(context.Background(), sender, createTestMessage("/ovh", 666), testConfig())
+			},
+			want: storage.AuditEntry{UserID: 666, Username: "testuser", Feature: "/ovh", Allowed: false},
+		},
+		{
+			name: "admin button",
+			run: func(sender *recordingSender) {
+				HandleAdminStats(context.Background(), sender, createTestMessage("📊 Stats", 12345), testConfig())
+			},
+			want: storage.AuditEntry{UserID: 12345, Username: "testuser", Feature: "button:📊 Stats", Allowed: true},
+		},
+		{
+			name: "denied inline query",
+			run: func(sender *recordingSender) {
+				query := &tgbotapi.InlineQuery{ID: "1", From: &tgbotapi.User{ID: 666}, Query: "ovh lon"}
+				buildInlineResults(context.Background(), query, testConfig())
+			},
+			want: storage.AuditEntry{UserID: 666, Feature: "inline_query", Allowed: false, Params: "ovh lon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := withAuditLog(t)
+			before := time.Now().UTC()
+
+			tt.run(&recordingSender{})
+
+			entries, err := audit.RecentAudit(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("RecentAudit() error = %v", err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("audit entries = %+v, want exactly one", entries)
+			}
+			got := entries[0]
+			if got.Time.Before(before) {
+				t.Errorf("entry time = %v, want at or after %v", got.Time, before)
+			}
+			got.Time = time.Time{}
+			if got != tt.want {
+				t.Errorf("entry = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatAuditEntries tests the /audit message
+func TestFormatAuditEntries(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []storage.AuditEntry{
+		{Time: at.Add(5 * time.Minute), UserID: 666, Username: "mallory", Feature: "/ovh", Allowed: false},
+		{Time: at, UserID: 123, Feature: "/currency", Allowed: true, Params: "GB"},
+	}
+
+	want := "🔍 Last 2 audit entries:\n" +
+		"03-01 12:05 ⛔ @mallory (666) /ovh\n" +
+		"03-01 12:00 ✅ 123 /currency GB"
+	if got := formatAuditEntries("en", entries); got != want {
+		t.Errorf("formatAuditEntries() =\n%s\nwant\n%s", got, want)
+	}
+	if got := formatAuditEntries("en", nil); !strings.Contains(got, "empty") {
+		t.Errorf("formatAuditEntries(nil) = %q, want an 'empty' message", got)
+	}
+}
+
+// TestTruncateRunes tests cutting long audit parameters
+func TestTruncateRunes(t *testing.T) {
+	if got := truncateRunes("short", 10); got != "short" {
+		t.Errorf("truncateRunes(short) = %q, want unchanged", got)
+	}
+	if got := truncateRunes("ääääää", 4); got != "äää…" {
+		t.Errorf("truncateRunes() = %q, want %q", got, "äää…")
+	}
+}
+
+// TestRouteMessage_PrivateCommandGate tests that routeMessage authorizes
+// private commands before their handler runs
+//
+// Cases:
+//   - Every private command, unauthorized, with arguments that don't parse:
+//     only the denial is sent, one denied entry is recorded, and the denial
+//     is counted under the command's feature flag, not its name
+//   - Authorized /ovh with bad arguments: the usage help, and one allowed
+//     entry (the handler's own check doesn't record a second one)
+func TestRouteMessage_PrivateCommandGate(t *testing.T) {
+	for _, cmd := range RegisteredCommands {
+		if !cmd.IsPrivate {
+			continue
+		}
+		t.Run(cmd.Name, func(t *testing.T) {
+			audit := withAuditLog(t)
+			sender := &recordingSender{}
+			// Counters are global, so compare before/after
+			featureBefore := handlerInvocations.Value(cmd.Feature, resultUnauthorized)
+			nameBefore := handlerInvocations.Value(cmd.Name, resultUnauthorized)
+
+			routeMessage(context.Background(), sender, createTestMessage("/"+cmd.Name+" max=abc foo bar baz", 666), testConfig())
+
+			wantFeature := featureBefore
+			if cmd.Feature != "" {
+				wantFeature++
+			}
+			if got := handlerInvocations.Value(cmd.Feature, resultUnauthorized); got != wantFeature {
+				t.Errorf("handler_invocations_total{feature=%q,result=\"unauthorized\"} = %g, want %g", cmd.Feature, got, wantFeature)
+			}
+			if cmd.Name != cmd.Feature {
+				if got := handlerInvocations.Value(cmd.Name, resultUnauthorized); got != nameBefore {
+					t.Errorf("denial counted under the command name %q", cmd.Name)
+				}
+			}
+
+			messages := sender.messages()
+			if len(messages) != 1 || !strings.Contains(messages[0].Text, "only available to authorized users") {
+				t.Errorf("messages = %+v, want only the denial", messages)
+			}
+			entries, err := audit.RecentAudit(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("RecentAudit() error = %v", err)
+			}
+			if len(entries) != 1 || entries[0].Allowed || entries[0].Feature != "/"+cmd.Name {
+				t.Errorf("audit entries = %+v, want one denied /%s entry", entries, cmd.Name)
+			}
+		})
+	}
+
+	t.Run("authorized", func(t *testing.T) {
+		audit := withAuditLog(t)
+		sender := &recordingSender{}
+
+		routeMessage(context.Background(), sender, createTestMessage("/ovh max=abc", 12345), testConfig())
+
+		if messages := sender.messages(); len(messages) != 1 || !strings.Contains(messages[0].Text, "Usage: /ovh") {
+			t.Errorf("messages = %+v, want the usage help", messages)
+		}
+		entries, err := audit.RecentAudit(context.Background(), 10)
+		if err != nil {
+			t.Fatalf("RecentAudit() error = %v", err)
+		}
+		if len(entries) != 1 || !entries[0].Allowed {
+			t.Errorf("audit entries = %+v, want one allowed entry", entries)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// broadcastInterval is the pause between two broadcast messages
+// Telegram allows about 30 messages per second across chats; 20 per second
+// leaves room for the bot's regular replies during a broadcast.
+const broadcastInterval = 50 * time.Millisecond
+
+// broadcastTimeout bounds a whole broadcast (about 12,000 chats at broadcastInterval)
+const broadcastTimeout = 10 * time.Minute
+
+// knownChatWriteTimeout bounds one background write of a new known chat
+const knownChatWriteTimeout = 5 * time.Second
+
+// knownChats is the set of chats the bot has received a message in.
+// Filled by the router (recordChat), read by /broadcast.
+//
+// The set lives in memory; with SetKnownChatStore it is also persisted
+// (and reloaded by LoadKnownChats), so it survives restarts when
+// STORAGE_BACKEND is file, firestore or redis.
+var knownChats = struct {
+	mu    sync.RWMutex
+	chats map[int64]bool
+}{chats: make(map[int64]bool)}
+
+// knownChatStore persists known chats (set by main.go via SetKnownChatStore)
+// nil: known chats are kept in memory only
+var knownChatStore *storage.ChatStore
+
+// startBackground starts long-running handler work (set by main.go via SetBackgroundTasks)
+// The task's context ends on shutdown. Default: a plain goroutine that is
+// never cancelled (tests, tools).
+var startBackground = func(name string, task func(ctx context.Context)) {
+	go task(context.Background())
+}
+
+// SetBackgroundTasks routes long-running handler work (/broadcast) through start
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - start: Starts task in a goroutine that shutdown waits for; the task's
+//     context is cancelled on shutdown (main.go: tasks.Go on the root context)
+func SetBackgroundTasks(start func(name string, task func(ctx context.Context))) {
+	startBackground = start
+}
+
+// SetKnownChatStore persists the known chats behind /broadcast
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - chats: Usually storage.NewChatStore on the application's storage
+func SetKnownChatStore(chats *storage.ChatStore) {
+	knownChatStore = chats
+}
+
+// LoadKnownChats adds the chats saved by earlier runs to the in-memory set
+// Call at startup, after SetKnownChatStore.
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//
+// Returns:
+//   - int: Number of chats loaded (0 without a store)
+//   - error: If the storage call fails
+func LoadKnownChats(ctx context.Context) (int, error) {
+	if knownChatStore == nil {
+		return 0, nil
+	}
+
+	chats, err := knownChatStore.ListKnownChats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, chatID := range chats {
+		addKnownChat(chatID)
+	}
+	return len(chats), nil
+}
+
+// addKnownChat adds a chat to the in-memory set
+//
+// Returns:
+//   - bool: true if the chat was not known yet
+func addKnownChat(chatID int64) bool {
+	// Fast path: almost every message comes from a chat we already know
+	knownChats.mu.RLock()
+	known := knownChats.chats[chatID]
+	knownChats.mu.RUnlock()
+	if known {
+		return false
+	}
+
+	knownChats.mu.Lock()
+	defer knownChats.mu.Unlock()
+
+	if knownChats.chats[chatID] {
+		return false // Added by another update in the meantime
+	}
+	knownChats.chats[chatID] = true
+	return true
+}
+
+// knownChatIDs returns all known chat IDs (sorted, for stable order)
+func knownChatIDs() []int64 {
+	knownChats.mu.RLock()
+	defer knownChats.mu.RUnlock()
+
+	chats := make([]int64, 0, len(knownChats.chats))
+	for chatID := range knownChats.chats {
+		chats = append(chats, chatID)
+	}
+	slices.Sort(chats)
+	return chats
+}
+
+// recordChat notes that the bot has received a message in a chat
+//
+// Only a chat's first message (per process) costs anything: the chat is
+// added to the set and, with a store, saved in the background.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - message: Incoming message
+func recordChat(ctx context.Context, message *tgbotapi.Message) {
+	if message.Chat == nil || !addKnownChat(message.Chat.ID) {
+		return
+	}
+
+	chats := knownChatStore
+	if chats == nil {
+		return
+	}
+
+	chatID := message.Chat.ID
+	bgCtx := context.WithoutCancel(ctx)
+	runAsync(func() {
+		ctx, cancel := context.WithTimeout(bgCtx, knownChatWriteTimeout)
+		defer cancel()
+
+		if err := chats.SaveKnownChat(ctx, chatID); err != nil {
+			logger.FromContext(ctx).Warn("Failed to save known chat",
+				"error", err,
+				"chat_id", chatID)
+		}
+	})
+}
+
+// broadcastResult counts the outcome of a broadcast
+type broadcastResult struct {
+	sent    int // Delivered
+	failed  int // Telegram returned an error
+	skipped int // Blocked chats (see IsChatBlocked) and chats not reached before ctx ended
+}
+
+// HandleBroadcast handles the /broadcast <text> command (private, for admins).
+// Sends the text to every known chat, then reports how many succeeded and failed.
+//
+// The broadcast runs in the background, paced at broadcastInterval, so the
+// webhook request returns right away; the report is sent when it ends.
+// It is a tracked background task (see SetBackgroundTasks): on shutdown it
+// stops early, reports the chats it skipped, and storage is closed only after.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /broadcast command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleBroadcast(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	text := strings.TrimSpace(message.CommandArguments())
+	chats := knownChatIDs()
+
+	var reply string
+	lang := userLanguage(ctx, message.From)
+	switch {
+	case text == "":
+		reply = translate(lang, "broadcast_usage")
+	case len(chats) == 0:
+		reply = translate(lang, "broadcast_no_chats")
+	default:
+		reply = translate(lang, "broadcast_started", len(chats))
+	}
+
+	msg := replyTo(message, reply)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /broadcast reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+	if text == "" || len(chats) == 0 {
+		return
+	}
+
+	// The broadcast outlives the webhook request: it ends with the
+	// application (taskCtx), but keeps logging with the update's logger
+	startBackground("broadcast", func(taskCtx context.Context) {
+		ctx, cancel := context.WithTimeout(logger.WithContext(taskCtx, log), broadcastTimeout)
+		defer cancel()
+
+		result := broadcast(ctx, bot, chats, text, broadcastInterval)
+		log.Info("Broadcast finished",
+			"chats", len(chats),
+			"sent", result.sent,
+			"failed", result.failed,
+			"skipped", result.skipped)
+
+		// bot.Send doesn't take a context, so the report goes out even
+		// when shutdown cut the broadcast short
+		msg := replyTo(message, formatBroadcastResult(lang, result))
+		if _, err := sendReply(ctx, bot, message, msg); err != nil {
+			log.Error("Failed to send broadcast report",
+				"error", err,
+				"message_type", messageType(msg))
+		}
+	})
+}
+
+// broadcast sends text to every chat, waiting interval between two sends
+//
+// Chats that blocked the bot are skipped without a send. If ctx ends,
+// the remaining chats are counted as skipped.
+//
+// Parameters:
+//   - ctx: Bounds the whole broadcast
+//   - bot: Bot sender for sending messages
+//   - chats: Target chat IDs
+//   - text: Plain text message
+//   - interval: Pause between two sends (0 for none)
+//
+// Returns:
+//   - broadcastResult: Sent, failed and skipped counts (they add up to len(chats))
+func broadcast(ctx context.Context, bot BotSender, chats []int64, text string, interval time.Duration) broadcastResult {
+	log := logger.FromContext(ctx)
+
+	var result broadcastResult
+	attempted := 0
+	for i, chatID := range chats {
+		if IsChatBlocked(chatID) {
+			result.skipped++
+			continue
+		}
+
+		if attempted > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				result.skipped += len(chats) - i
+				return result
+			}
+		}
+		if ctx.Err() != nil {
+			result.skipped += len(chats) - i
+			return result
+		}
+		attempted++
+
+		// Plain text: the admin's text may contain any character
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := bot.Send(msg); err != nil {
+			log.Warn("Failed to send broadcast message",
+				"error", err,
+				"message_type", messageType(msg),
+				"chat_id", chatID)
+			result.failed++
+			continue
+		}
+		result.sent++
+	}
+	return result
+}
+
+// formatBroadcastResult builds the plain-text broadcast report
+//
+// Example: "📢 Broadcast finished: 41 sent, 2 failed, 3 skipped."
+func formatBroadcastResult(lang string, result broadcastResult) string {
+	return translate(lang, "broadcast_done", result.sent, result.failed, result.skipped)
+}
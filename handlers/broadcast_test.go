@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// chatFailingSender is a recordingSender whose sends to some chats fail
+type chatFailingSender struct {
+	recordingSender
+	failChats map[int64]bool
+}
+
+// Send records the Chattable and fails for chats in failChats
+func (s *chatFailingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, err := s.recordingSender.Send(c)
+	if m, ok := c.(tgbotapi.MessageConfig); ok && s.failChats[m.ChatID] {
+		return tgbotapi.Message{}, errors.New("Forbidden: bot was blocked by the user")
+	}
+	return msg, err
+}
+
+// withKnownChats gives a test an empty known-chat set, and synchronous
+// runAsync and startBackground
+func withKnownChats(t *testing.T, chats *storage.ChatStore) {
+	t.Helper()
+
+	knownChats.mu.Lock()
+	oldChats := knownChats.chats
+	knownChats.chats = make(map[int64]bool)
+	knownChats.mu.Unlock()
+	oldStore, oldRunAsync, oldStartBackground := knownChatStore, runAsync, startBackground
+
+	t.Cleanup(func() {
+		knownChats.mu.Lock()
+		knownChats.chats = oldChats
+		knownChats.mu.Unlock()
+		knownChatStore, runAsync, startBackground = oldStore, oldRunAsync, oldStartBackground
+	})
+
+	knownChatStore = chats
+	runAsync = func(f func()) { f() }
+	startBackground = func(name string, task func(ctx context.Context)) { task(context.Background()) }
+}
+
+// TestRecordChat tests that the router's chat recording fills the set and the store
+//
+// Cases:
+//   - Each chat is recorded once, whatever the number of messages
+//   - The store receives the chats, and LoadKnownChats reads them back
+//   - Concurrent recording is safe (go test -race)
+func TestRecordChat(t *testing.T) {
+	chats := storage.NewChatStore(storage.NewMemoryStore())
+	withKnownChats(t, chats)
+	ctx := context.Background()
+
+	for _, chatID := range []int64{12, -100, 12, 7} {
+		msg := createTestMessage("hello", 12345)
+		msg.Chat.ID = chatID
+		recordChat(ctx, msg)
+	}
+
+	if got, want := knownChatIDs(), []int64{-100, 7, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("knownChatIDs() = %v, want %v", got, want)
+	}
+	if stored, _ := chats.ListKnownChats(ctx); len(stored) != 3 {
+		t.Errorf("stored known chats = %v, want 3 chats", stored)
+	}
+
+	// A restart: the set is empty, LoadKnownChats restores it from the store
+	knownChats.mu.Lock()
+	knownChats.chats = make(map[int64]bool)
+	knownChats.mu.Unlock()
+	if n, err := LoadKnownChats(ctx); err != nil || n != 3 {
+		t.Errorf("LoadKnownChats() = %d, %v; want 3, nil", n, err)
+	}
+	if got := knownChatIDs(); len(got) != 3 {
+		t.Errorf("knownChatIDs() after load = %v, want 3 chats", got)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func(chatID int64) {
+			defer wg.Done()
+			msg := createTestMessage("hello", 12345)
+			msg.Chat.ID = chatID
+			recordChat(ctx, msg)
+		}(int64(100 + i%5))
+	}
+	wg.Wait()
+	if got := knownChatIDs(); len(got) != 8 {
+		t.Errorf("knownChatIDs() after concurrent recording = %v, want 8 chats", got)
+	}
+}
+
+// TestBroadcast tests sending to every chat with pacing and counting
+//
+// Cases:
+//   - Every chat gets one message; a failing chat is counted as failed
+//   - Blocked chats are skipped without a send
+//   - A cancelled context skips the remaining chats
+func TestBroadcast(t *testing.T) {
+	setChatBlocked(3, true)
+	defer setChatBlocked(3, false)
+
+	sender := &chatFailingSender{failChats: map[int64]bool{2: true}}
+	start := time.Now()
+	got := broadcast(context.Background(), sender, []int64{1, 2, 3, 4}, "hi", 10*time.Millisecond)
+
+	if want := (broadcastResult{sent: 2, failed: 1, skipped: 1}); got != want {
+		t.Errorf("broadcast() = %+v, want %+v", got, want)
+	}
+	if sends := len(sender.messages()); sends != 3 {
+		t.Errorf("sends = %d, want 3 (blocked chat skipped)", sends)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("broadcast() took %v, want at least 2 intervals between 3 sends", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sender = &chatFailingSender{}
+	got = broadcast(ctx, sender, []int64{1, 2}, "hi", time.Hour)
+	if want := (broadcastResult{skipped: 2}); got != want || len(sender.messages()) != 0 {
+		t.Errorf("broadcast(cancelled) = %+v with %d sends, want %+v and no sends", got, len(sender.messages()), want)
+	}
+}
+
+// TestHandleBroadcast tests the /broadcast command
+//
+// Cases:
+//   - No text: usage, nothing broadcast
+//   - Text: every known chat gets it, then the report
+//   - Unauthorized user: nothing broadcast
+func TestHandleBroadcast(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		userID    int64
+		wantSends int
+		wantLast  string
+	}{
+		{name: "usage", text: "/broadcast", userID: 12345, wantSends: 1, wantLast: "Usage: /broadcast"},
+		{name: "broadcast", text: "/broadcast Maintenance at 18:00", userID: 12345, wantSends: 4, wantLast: "2 sent, 0 failed, 0 skipped"},
+		{name: "unauthorized", text: "/broadcast spam", userID: 999, wantSends: 1, wantLast: "only available to authorized users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withKnownChats(t, nil)
+			addKnownChat(-100)
+			addKnownChat(42)
+
+			sender := &recordingSender{}
+			HandleBroadcast(context.Background(), sender, createTestMessage(tt.text, tt.userID), testConfig())
+
+			msgs := sender.messages()
+			if len(msgs) != tt.wantSends {
+				t.Fatalf("sent %d messages, want %d: %+v", len(msgs), tt.wantSends, msgs)
+			}
+			if last := msgs[len(msgs)-1].Text; !strings.Contains(last, tt.wantLast) {
+				t.Errorf("last message = %q, want it to contain %q", last, tt.wantLast)
+			}
+			if tt.name == "broadcast" {
+				for _, m := range msgs[1:3] {
+					if m.Text != "Maintenance at 18:00" {
+						t.Errorf("broadcast message to %d = %q, want the text alone", m.ChatID, m.Text)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestHandleBroadcast_Shutdown tests a broadcast cut short by shutdown
+// The task's context is already cancelled: no chat is reached, and the
+// report still goes out with every chat counted as skipped.
+func TestHandleBroadcast_Shutdown(t *testing.T) {
+	withKnownChats(t, nil)
+	addKnownChat(-100)
+	addKnownChat(42)
+
+	var started []string
+	startBackground = func(name string, task func(ctx context.Context)) {
+		started = append(started, name)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		task(ctx)
+	}
+
+	sender := &recordingSender{}
+	HandleBroadcast(context.Background(), sender, createTestMessage("/broadcast Maintenance at 18:00", 12345), testConfig())
+
+	if !reflect.DeepEqual(started, []string{"broadcast"}) {
+		t.Errorf("background tasks = %v, want [broadcast]", started)
+	}
+	msgs := sender.messages()
+	if len(msgs) != 2 {
+		t.Fatalf("sent %d messages, want the start notice and the report: %+v", len(msgs), msgs)
+	}
+	if want := "0 sent, 0 failed, 2 skipped"; !strings.Contains(msgs[1].Text, want) {
+		t.Errorf("report = %q, want it to contain %q", msgs[1].Text, want)
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// callbackAction handles a click on an inline keyboard button.
+// Every action must call answerCallback exactly once.
+type callbackAction func(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, cfg *config.Config)
+
+// callbackActions maps the callback_data prefix (text before the first ":")
+// to its handler. Inline buttons set callback_data like "ovh:detail:3:24sk20".
+//
+// To add a new inline button action, add an entry here.
+// The bot uses ReplyKeyboard for its main menu; inline buttons are only
+// attached to results (e.g., "ℹ️ N" offer details under OVH results).
+var callbackActions = map[string]callbackAction{
+	ovhCallbackPrefix: handleOVHCallback, // "ovh:detail:<N>:<planCode>", see ovhdetails.go
+}
+
+// privateCallbacks are the callbackActions prefixes for authorized users only
+// Shown by --list-handlers (see Routes); actions still check authorization themselves.
+var privateCallbacks = map[string]bool{
+	ovhCallbackPrefix: true,
+}
+
+// routeCallbackQuery dispatches inline keyboard clicks by callback_data.
+//
+// Why always answer?
+//   - After a click, Telegram shows a loading spinner on the button
+//   - It stays until the bot calls answerCallbackQuery (or ~30 seconds pass)
+//   - So even unknown or stale buttons (e.g., from an older bot version) get an answer
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for answering the callback
+//   - query: Callback query from update.CallbackQuery
+//   - cfg: Application configuration (passed to actions)
+func routeCallbackQuery(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	prefix, _, _ := strings.Cut(query.Data, ":")
+	if action, ok := callbackActions[prefix]; ok {
+		action(ctx, bot, query, cfg)
+		return
+	}
+
+	// Unknown button: clear the spinner without showing anything
+	log.Warn("Unknown callback data",
+		"data", query.Data)
+	answerCallback(ctx, bot, query, "")
+}
+
+// answerCallback answers a callback query (answerCallbackQuery API method),
+// which removes the loading spinner from the clicked button.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender (answerCallbackQuery returns True, not a Message, so Request is used)
+//   - query: Callback query to answer
+//   - text: Short notification shown at the top of the chat ("" = show nothing)
+func answerCallback(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, text string) {
+	log := logger.FromContext(ctx)
+
+	callback := tgbotapi.NewCallback(query.ID, text)
+	if _, err := bot.Request(callback); err != nil {
+		log.Error("Failed to answer callback query",
+			"error", err,
+			"message_type", messageType(callback),
+			"callback_id", query.ID)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestRouteUpdate_CallbackQuery verifies every callback is answered,
+// so Telegram clears the loading spinner on the button.
+//
+// Cases:
+//   - Unknown callback data: answered with empty text
+//   - Registered prefix: dispatched to its action (which answers itself)
+func TestRouteUpdate_CallbackQuery(t *testing.T) {
+	// Register a test action for the "test:" prefix
+	var gotData string
+	callbackActions["test"] = func(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, _ *config.Config) {
+		gotData = query.Data
+		answerCallback(ctx, bot, query, "done")
+	}
+	defer delete(callbackActions, "test")
+
+	tests := []struct {
+		name     string
+		data     string
+		wantText string
+		wantData string // Data seen by the test action ("" = not called)
+	}{
+		{name: "unknown callback", data: "stale-button", wantText: ""},
+		{name: "registered prefix", data: "test:42", wantText: "done", wantData: "test:42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotData = ""
+			sender := &recordingSender{}
+			update := tgbotapi.Update{
+				CallbackQuery: &tgbotapi.CallbackQuery{
+					ID:   "cb-1",
+					From: &tgbotapi.User{ID: 12345},
+					Data: tt.data,
+				},
+			}
+
+			RouteUpdate(context.Background(), sender, update, testConfig())
+
+			if len(sender.requested) != 1 {
+				t.Fatalf("got %d requests, want 1 answerCallbackQuery", len(sender.requested))
+			}
+			answer, ok := sender.requested[0].(tgbotapi.CallbackConfig)
+			if !ok {
+				t.Fatalf("request is %T, want CallbackConfig", sender.requested[0])
+			}
+			if answer.CallbackQueryID != "cb-1" || answer.Text != tt.wantText {
+				t.Errorf("answer = {id: %q, text: %q}, want {id: %q, text: %q}",
+					answer.CallbackQueryID, answer.Text, "cb-1", tt.wantText)
+			}
+			if gotData != tt.wantData {
+				t.Errorf("action got data %q, want %q", gotData, tt.wantData)
+			}
+			if len(sender.sent) != 0 {
+				t.Errorf("sent %d messages, want none", len(sender.sent))
+			}
+		})
+	}
+}
+
+// TestAnswerCallback_RequestError verifies a failed answer doesn't panic
+func TestAnswerCallback_RequestError(t *testing.T) {
+	sender := &recordingSender{requestErr: errors.New("query is too old")}
+	answerCallback(context.Background(), sender, &tgbotapi.CallbackQuery{ID: "cb-1"}, "")
+
+	if len(sender.requested) != 1 {
+		t.Errorf("got %d requests, want 1", len(sender.requested))
+	}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// operations is the package-level registry of in-flight user operations
+var operations = newOperationRegistry()
+
+// operationRegistry tracks one cancellable operation per user.
+//
+// How cancellation works:
+//   - A long-running handler calls start() and gets a context
+//   - It passes that context to slow calls (e.g., OVH API requests)
+//   - /cancel calls cancel(userID), which cancels that context
+//   - The slow call returns ctx.Err() and the handler stops
+//
+// Concurrency:
+//   - Webhook requests run in parallel, /cancel arrives while the handler is running
+//   - All map access holds the mutex
+type operationRegistry struct {
+	mu     sync.Mutex
+	nextID uint64
+	ops    map[int64]userOperation
+}
+
+// userOperation is a registered operation with its cancel function
+type userOperation struct {
+	id     uint64 // Distinguishes operations of the same user
+	cancel context.CancelFunc
+}
+
+// newOperationRegistry creates an empty registry
+func newOperationRegistry() *operationRegistry {
+	return &operationRegistry{ops: make(map[int64]userOperation)}
+}
+
+// start registers a new operation for a user.
+// If the user already has an operation running, it is cancelled first
+// (only the latest request matters).
+//
+// Parameters:
+//   - parent: Parent context
+//   - userID: Telegram user ID
+//
+// Returns:
+//   - context.Context: Context cancelled by /cancel or when done is called
+//   - func(): done - must be called when the operation finishes (use defer)
+func (r *operationRegistry) start(parent context.Context, userID int64) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+
+	r.mu.Lock()
+	if previous, ok := r.ops[userID]; ok {
+		previous.cancel()
+	}
+	r.nextID++
+	id := r.nextID
+	r.ops[userID] = userOperation{id: id, cancel: cancel}
+	r.mu.Unlock()
+
+	done := func() {
+		r.mu.Lock()
+		// Only remove our own entry - a newer operation may have replaced it
+		if current, ok := r.ops[userID]; ok && current.id == id {
+			delete(r.ops, userID)
+		}
+		r.mu.Unlock()
+		cancel() // Release context resources
+	}
+	return ctx, done
+}
+
+// cancel cancels the user's current operation
+//
+// Parameters:
+//   - userID: Telegram user ID
+//
+// Returns:
+//   - bool: true if an operation was running and has been cancelled
+func (r *operationRegistry) cancel(userID int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	op, ok := r.ops[userID]
+	if !ok {
+		return false
+	}
+	op.cancel()
+	delete(r.ops, userID)
+	return true
+}
+
+// HandleCancel handles the /cancel command.
+// Aborts the user's in-flight operation (e.g., a slow OVH fetch).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /cancel command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleCancel(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	cancelled := operations.cancel(message.From.ID)
+
+	log.Info("/cancel command received",
+		"cancelled", cancelled)
+
+	lang := userLanguage(ctx, message.From)
+	text := translate(lang, "cancel_nothing")
+	if cancelled {
+		text = translate(lang, "cancel_done")
+	}
+
+	msg := replyTo(message, text)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /cancel reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
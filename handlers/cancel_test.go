@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestOperationRegistry tests the per-user context registry.
+//
+// Cases:
+//   - Register + cancel: context is cancelled, cancel returns true
+//   - Cancel with nothing running: returns false (no-op)
+//   - Done: entry removed, later cancel is a no-op
+//   - New operation replaces (and cancels) the previous one of the same user
+//   - Users are independent
+func TestOperationRegistry(t *testing.T) {
+	t.Run("register and cancel", func(t *testing.T) {
+		registry := newOperationRegistry()
+		ctx, done := registry.start(context.Background(), 1)
+		defer done()
+
+		if !registry.cancel(1) {
+			t.Fatalf("cancel() = false, want true for running operation")
+		}
+		if ctx.Err() != context.Canceled {
+			t.Errorf("ctx.Err() = %v, want context.Canceled", ctx.Err())
+		}
+	})
+
+	t.Run("cancel with nothing running", func(t *testing.T) {
+		registry := newOperationRegistry()
+		if registry.cancel(1) {
+			t.Errorf("cancel() = true, want false when nothing is running")
+		}
+	})
+
+	t.Run("done clears the operation", func(t *testing.T) {
+		registry := newOperationRegistry()
+		_, done := registry.start(context.Background(), 1)
+		done()
+
+		if registry.cancel(1) {
+			t.Errorf("cancel() after done = true, want false")
+		}
+	})
+
+	t.Run("new operation replaces previous", func(t *testing.T) {
+		registry := newOperationRegistry()
+		first, firstDone := registry.start(context.Background(), 1)
+		second, secondDone := registry.start(context.Background(), 1)
+		defer secondDone()
+
+		if first.Err() == nil {
+			t.Errorf("first operation not cancelled when replaced")
+		}
+
+		// Finishing the old operation must not unregister the new one
+		firstDone()
+		if !registry.cancel(1) {
+			t.Fatalf("cancel() = false, newer operation was unregistered")
+		}
+		if second.Err() == nil {
+			t.Errorf("second operation not cancelled")
+		}
+	})
+
+	t.Run("users are independent", func(t *testing.T) {
+		registry := newOperationRegistry()
+		ctx1, done1 := registry.start(context.Background(), 1)
+		defer done1()
+		ctx2, done2 := registry.start(context.Background(), 2)
+		defer done2()
+
+		registry.cancel(1)
+		if ctx1.Err() == nil || ctx2.Err() != nil {
+			t.Errorf("cancel(1): ctx1.Err() = %v, ctx2.Err() = %v; want only ctx1 cancelled", ctx1.Err(), ctx2.Err())
+		}
+	})
+}
+
+// TestHandleCancel_NothingRunning verifies the polite reply when there is nothing to cancel
+func TestHandleCancel_NothingRunning(t *testing.T) {
+	sender := &recordingSender{}
+	HandleCancel(context.Background(), sender, createTestMessage("/cancel", 12345), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 1 || !strings.Contains(messages[0].Text, "Nothing to cancel") {
+		t.Errorf("HandleCancel sent %+v, want one \"Nothing to cancel\" message", messages)
+	}
+}
+
+// TestHandleCancel_AbortsOVHFetch verifies /cancel stops a running OVH fetch
+// and that no "failed to fetch" error is sent afterwards.
+func TestHandleCancel_AbortsOVHFetch(t *testing.T) {
+	// Fake slow OVH fetch: blocks until its context is cancelled
+	started := make(chan struct{})
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	sender := &recordingSender{}
+	finished := make(chan struct{})
+	go func() {
+		HandleOVHCheck(context.Background(), sender, createTestMessage("🖥️ OVH Servers", 12345), testConfig())
+		close(finished)
+	}()
+
+	<-started
+	HandleCancel(context.Background(), sender, createTestMessage("/cancel", 12345), testConfig())
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("HandleOVHCheck did not return after /cancel")
+	}
+
+	// Expected: status message + "Cancelled" reply, nothing else
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("sent %d messages, want 2 (status + cancel reply): %+v", len(messages), messages)
+	}
+	if !strings.Contains(messages[1].Text, "Cancelled") {
+		t.Errorf("second message = %q, want cancel confirmation", messages[1].Text)
+	}
+}
+
+// TestHandleOVHCheck_RequestContextCancelled verifies that cancelling the
+// request context (e.g., Cloud Run stopping the instance) aborts the OVH fetch
+// without sending an error message.
+func TestHandleOVHCheck_RequestContextCancelled(t *testing.T) {
+	started := make(chan struct{})
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sender := &recordingSender{}
+	finished := make(chan struct{})
+	go func() {
+		HandleOVHCheck(ctx, sender, createTestMessage("🖥️ OVH Servers", 12345), testConfig())
+		close(finished)
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("HandleOVHCheck did not return after the request context was cancelled")
+	}
+
+	// Only the status message, no "failed to fetch" error
+	if messages := sender.messages(); len(messages) != 1 {
+		t.Errorf("sent %d messages, want 1 (status only): %+v", len(messages), messages)
+	}
+}
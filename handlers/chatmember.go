@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// blockedChats remembers chats where the bot can't send messages anymore
+// (user blocked the bot, or bot was removed from a group).
+// Broadcasts and notifications should skip these chats, see IsChatBlocked.
+//
+// Kept in memory: the set is rebuilt from my_chat_member updates after restart.
+var blockedChats = struct {
+	mu    sync.RWMutex
+	chats map[int64]bool
+}{chats: make(map[int64]bool)}
+
+// IsChatBlocked reports whether the bot can no longer write to a chat
+//
+// Parameters:
+//   - chatID: Telegram chat ID
+//
+// Returns:
+//   - bool: true if the user blocked the bot or the bot left/was removed from the group
+func IsChatBlocked(chatID int64) bool {
+	blockedChats.mu.RLock()
+	defer blockedChats.mu.RUnlock()
+	return blockedChats.chats[chatID]
+}
+
+// setChatBlocked marks or unmarks a chat as blocked
+func setChatBlocked(chatID int64, blocked bool) {
+	blockedChats.mu.Lock()
+	defer blockedChats.mu.Unlock()
+
+	if blocked {
+		blockedChats.chats[chatID] = true
+	} else {
+		delete(blockedChats.chats, chatID)
+	}
+}
+
+// HandleMyChatMember handles my_chat_member updates.
+// Telegram sends them when the BOT's own membership in a chat changes.
+//
+// Chat member statuses:
+//   - "member", "administrator", "creator": bot is in the chat
+//   - "left": bot left or was never there
+//   - "kicked": bot was removed from a group, or (in private chats) the user blocked it
+//   - "restricted": bot is in a group with limited permissions
+//
+// Transitions we react to:
+//   - Private chat → kicked: user blocked the bot, mark chat blocked
+//   - Private chat → member: user unblocked the bot, unmark
+//   - Group → member/administrator (from left/kicked): bot was added
+//   - allowed group: send greeting with keyboard
+//   - group not in ALLOWED_CHATS: leave immediately
+//   - Group → left/kicked: bot was removed, mark chat blocked
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - botAPI: Bot sender for sending messages
+//   - update: Membership change from update.MyChatMember
+//   - cfg: Application configuration (ALLOWED_CHATS)
+func HandleMyChatMember(ctx context.Context, botAPI BotSender, update *tgbotapi.ChatMemberUpdated, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	chatID := update.Chat.ID
+	oldStatus := update.OldChatMember.Status
+	newStatus := update.NewChatMember.Status
+
+	log.Info("Bot membership changed",
+		"chat_id", chatID,
+		"chat_type", update.Chat.Type,
+		"old_status", oldStatus,
+		"new_status", newStatus,
+		"by_user_id", update.From.ID)
+
+	wasIn := isInChatStatus(oldStatus)
+	isIn := isInChatStatus(newStatus)
+
+	switch {
+	// Private chat: blocked / unblocked by the user
+	case update.Chat.IsPrivate():
+		setChatBlocked(chatID, newStatus == "kicked")
+
+	// Group: bot was added
+	case !wasIn && isIn:
+		setChatBlocked(chatID, false)
+		if !cfg.IsChatAllowed(chatID) {
+			leaveChat(ctx, botAPI, chatID)
+			return
+		}
+		sendGroupGreeting(ctx, botAPI, chatID, userLanguage(ctx, &update.From), cfg)
+
+	// Group: bot was removed or left
+	case wasIn && !isIn:
+		setChatBlocked(chatID, true)
+	}
+}
+
+// isInChatStatus reports whether a member status means "is in the chat"
+func isInChatStatus(status string) bool {
+	switch status {
+	case "member", "administrator", "creator", "restricted":
+		return true
+	default:
+		return false
+	}
+}
+
+// leaveChat makes the bot leave a group that is not in ALLOWED_CHATS
+func leaveChat(ctx context.Context, botAPI BotSender, chatID int64) {
+	log := logger.FromContext(ctx)
+
+	log.Warn("Added to a group that is not allowed, leaving",
+		"chat_id", chatID)
+
+	// leaveChat doesn't return a Message, so it goes through Request
+	leave := tgbotapi.LeaveChatConfig{ChatID: chatID}
+	if _, err := botAPI.Request(leave); err != nil {
+		log.Error("Failed to leave chat",
+			"error", err,
+			"message_type", messageType(leave),
+			"chat_id", chatID)
+		return
+	}
+	setChatBlocked(chatID, true)
+}
+
+// sendGroupGreeting introduces the bot after it was added to a group
+// The greeting is in lang, the language of the user who added the bot.
+func sendGroupGreeting(ctx context.Context, botAPI BotSender, chatID int64, lang string, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	msg := tgbotapi.NewMessage(chatID, translate(lang, "group_greeting"))
+	msg.ReplyMarkup = replyKeyboard(bot.GetMainKeyboard(cfg.Features))
+
+	if _, err := botAPI.Send(msg); err != nil {
+		log.Error("Failed to send group greeting",
+			"error", err,
+			"message_type", messageType(msg),
+			"chat_id", chatID)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// createChatMemberUpdate creates a my_chat_member fixture update
+//
+// Parameters:
+//   - chatID: Chat where membership changed
+//   - chatType: "private", "group" or "supergroup"
+//   - oldStatus, newStatus: Bot's member status before and after
+func createChatMemberUpdate(chatID int64, chatType, oldStatus, newStatus string) tgbotapi.Update {
+	botUser := &tgbotapi.User{ID: 1, IsBot: true, UserName: "run_tbot"}
+	return tgbotapi.Update{
+		UpdateID: 1,
+		MyChatMember: &tgbotapi.ChatMemberUpdated{
+			Chat:          tgbotapi.Chat{ID: chatID, Type: chatType},
+			From:          tgbotapi.User{ID: 12345, FirstName: "Test"},
+			Date:          1700000000,
+			OldChatMember: tgbotapi.ChatMember{User: botUser, Status: oldStatus},
+			NewChatMember: tgbotapi.ChatMember{User: botUser, Status: newStatus},
+		},
+	}
+}
+
+// TestRouteUpdate_MyChatMember tests each membership transition.
+//
+// Checks per case:
+//   - Whether the chat ends up marked as blocked
+//   - Whether a greeting was sent (Send) or the bot left (Request LeaveChatConfig)
+func TestRouteUpdate_MyChatMember(t *testing.T) {
+	tests := []struct {
+		name         string
+		chatID       int64
+		chatType     string
+		oldStatus    string
+		newStatus    string
+		allowedChats []int64
+		wantBlocked  bool
+		wantGreeting bool
+		wantLeave    bool
+	}{
+		{name: "user blocked bot", chatID: 12345, chatType: "private", oldStatus: "member", newStatus: "kicked", wantBlocked: true},
+		{name: "user unblocked bot", chatID: 12345, chatType: "private", oldStatus: "kicked", newStatus: "member", wantBlocked: false},
+		{name: "added to group without restrictions", chatID: -100, chatType: "group", oldStatus: "left", newStatus: "member", wantGreeting: true},
+		{name: "added to allowed supergroup as admin", chatID: -200, chatType: "supergroup", oldStatus: "left", newStatus: "administrator", allowedChats: []int64{-200}, wantGreeting: true},
+		{name: "added to non-allowed group", chatID: -300, chatType: "group", oldStatus: "left", newStatus: "member", allowedChats: []int64{-200}, wantBlocked: true, wantLeave: true},
+		{name: "removed from group", chatID: -400, chatType: "group", oldStatus: "member", newStatus: "kicked", wantBlocked: true},
+		{name: "left group", chatID: -500, chatType: "supergroup", oldStatus: "administrator", newStatus: "left", wantBlocked: true},
+		{name: "promoted to admin", chatID: -600, chatType: "group", oldStatus: "member", newStatus: "administrator", wantBlocked: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setChatBlocked(tt.chatID, false)
+			defer setChatBlocked(tt.chatID, false)
+
+			cfg := testConfig()
+			cfg.AllowedChats = tt.allowedChats
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, createChatMemberUpdate(tt.chatID, tt.chatType, tt.oldStatus, tt.newStatus), cfg)
+
+			if blocked := IsChatBlocked(tt.chatID); blocked != tt.wantBlocked {
+				t.Errorf("IsChatBlocked(%d) = %v, want %v", tt.chatID, blocked, tt.wantBlocked)
+			}
+
+			greeted := len(sender.messages()) == 1
+			if greeted != tt.wantGreeting {
+				t.Errorf("greeting sent = %v, want %v", greeted, tt.wantGreeting)
+			}
+			if greeted {
+				if _, ok := sender.messages()[0].ReplyMarkup.(tgbotapi.ReplyKeyboardMarkup); !ok {
+					t.Errorf("greeting has no reply keyboard")
+				}
+			}
+
+			left := false
+			for _, c := range sender.requested {
+				if leave, ok := c.(tgbotapi.LeaveChatConfig); ok && leave.ChatID == tt.chatID {
+					left = true
+				}
+			}
+			if left != tt.wantLeave {
+				t.Errorf("left chat = %v, want %v", left, tt.wantLeave)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// UpdateHandlerFunc handles the message of one update (command or button click).
+// Same signature as the button handlers, so they can be registered directly.
+type UpdateHandlerFunc func(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config)
+
+// Command describes one slash command: everything the router, /help and
+// Telegram's command menu (setMyCommands) need to know about it.
+type Command struct {
+	// Name is the command without "/" (e.g., "ovhcsv")
+	// Telegram allows only lowercase letters, digits and underscores, up to 32 characters
+	Name string
+
+	// Args is the argument syntax shown in /help (e.g., "on|off"), "" for none
+	Args string
+
+	// Description is shown in /help and in Telegram's command menu (3-256 characters)
+	Description string
+
+	// IsPrivate hides the command from unauthorized users in /help and the command menu
+	// routeMessage authorizes (and audits) private commands before calling the handler;
+	// handlers still check themselves, as buttons and tests call them directly
+	// (see requireAuthorized)
+	IsPrivate bool
+
+	// Feature ties the command to a feature flag (config.Feature*), "" for always on
+	// Commands of disabled features are treated as unknown (see routeMessage)
+	Feature string
+
+	// Handler runs the command
+	Handler UpdateHandlerFunc
+}
+
+// RegisteredCommands lists every slash command, in the order shown in /help.
+//
+// To add a new command, add one entry here: routing (routeMessage),
+// /help (formatHelpMessage) and the Telegram command menu (BotCommands)
+// all read this list.
+//
+// Filled in init() rather than in the declaration: HandleHelp reads
+// RegisteredCommands, so a direct initializer would be an initialization cycle.
+var RegisteredCommands []Command
+
+func init() {
+	RegisteredCommands = []Command{
+		// Public commands
+		{Name: "start", Description: "Start the bot and see welcome message", Handler: HandleStart},
+		{Name: "help", Description: "Show this help message", Handler: HandleHelp},
+		{Name: "menu", Description: "Show the button keyboard", Handler: HandleMenu},
+		{Name: "hide", Description: "Hide the button keyboard", Handler: HandleHide},
+		{Name: "cancel", Description: "Stop your current operation", Handler: HandleCancel},
+		{Name: "done", Description: "Confirm you made your Twister move (+1 point)", Feature: config.FeatureTwister, Handler: HandleTwisterDone},
+		{Name: "skip", Description: "Pass your Twister move without a point", Feature: config.FeatureTwister, Handler: HandleTwisterSkip},
+		{Name: "twister_score", Description: "Twister scoreboard of this chat", Feature: config.FeatureTwister, Handler: HandleTwisterScore},
+		{Name: "twister_new", Description: "Start a new Twister game (clears the scoreboard)", Feature: config.FeatureTwister, Handler: HandleTwisterNew},
+		{Name: "language", Args: "[code]", Description: "Choose the bot's language (en, fr, de)", Handler: HandleLanguage},
+		{Name: "server_map", Description: "World map of OVH datacenters", Feature: config.FeatureOVH, Handler: HandleServerMap},
+
+		// Private commands (routeMessage authorizes them before the handler runs;
+		// requireAuthorized inside each handler is the fallback check)
+		{Name: "echo", Args: "<text>", Description: "Send the text back with diagnostic IDs", IsPrivate: true, Feature: config.FeatureEcho, Handler: HandleEcho},
+		{Name: "broadcast", Args: "<text>", Description: "Send a message to every known chat", IsPrivate: true, Handler: HandleBroadcast},
+		{Name: "audit", Args: "[n]", Description: "Last uses of private features, allowed and denied", IsPrivate: true, Handler: HandleAudit},
+		{Name: "usage", Args: "[days]", Description: "Feature usage per day (default 7 days)", IsPrivate: true, Handler: HandleUsage},
+		{Name: "users", Description: "Number of users and the most recently active", IsPrivate: true, Handler: HandleUsers},
+		{Name: "flushupdates", Description: "Drop updates queued by Telegram", IsPrivate: true, Handler: HandleFlushUpdates},
+		{Name: "ovh", Args: "[datacenter] [count] [max=PRICE] [prefix=PLAN] [search KEYWORD] [soon]", Description: "Top 3 cheapest OVH servers in London", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleOVHCommand},
+		{Name: "ovhcsv", Description: "Export OVH offers as a CSV file", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleOVHCSV},
+		{Name: "ovhjson", Description: "Export OVH offers as a JSON file", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleOVHJSON},
+		{Name: "lucky_server", Description: "A random available OVH server", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleLuckyServer},
+		{Name: "plan_codes", Args: "[addons] [page]", Description: "List OVH ECO catalog plan codes", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandlePlanCodes},
+		{Name: "currency", Args: "[subsidiary]", Description: "OVH currency and tax rate for a subsidiary", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleCurrency},
+		{Name: "compare_catalogs", Description: "Compare OVH ECO and Advance servers", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleOVHCompare},
+		{Name: "goodmorning", Args: "on|off", Description: "Daily message with the cheapest OVH server", IsPrivate: true, Feature: config.FeatureOVH, Handler: HandleGoodMorning},
+	}
+}
+
+// findCommand looks up a registered command by name
+//
+// Parameters:
+//   - name: Command without "/" and without @botname (message.Command())
+//
+// Returns:
+//   - Command: The registered command
+//   - bool: false if no command has this name
+func findCommand(name string) (Command, bool) {
+	for _, cmd := range RegisteredCommands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return Command{}, false
+}
+
+// BotCommands converts RegisteredCommands for Telegram's command menu (setMyCommands)
+//
+// Parameters:
+//   - includePrivate: true for authorized users' menu, false for everyone else
+//
+// Returns:
+//   - []tgbotapi.BotCommand: Commands in registry order
+func BotCommands(includePrivate bool) []tgbotapi.BotCommand {
+	var commands []tgbotapi.BotCommand
+	for _, cmd := range RegisteredCommands {
+		if cmd.IsPrivate && !includePrivate {
+			continue
+		}
+		commands = append(commands, tgbotapi.BotCommand{Command: cmd.Name, Description: cmd.Description})
+	}
+	return commands
+}
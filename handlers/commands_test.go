@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestRegisteredCommands checks every entry against Telegram's setMyCommands rules,
+// so a bad entry fails here instead of at bot startup.
+//
+// Rules:
+//   - Name: 1-32 characters, lowercase letters, digits and underscores, unique
+//   - Description: 3-256 characters
+//   - Handler: not nil
+func TestRegisteredCommands(t *testing.T) {
+	validName := regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+	seen := make(map[string]bool)
+
+	for _, cmd := range RegisteredCommands {
+		if !validName.MatchString(cmd.Name) {
+			t.Errorf("command %q: invalid name for Telegram", cmd.Name)
+		}
+		if seen[cmd.Name] {
+			t.Errorf("command %q registered twice", cmd.Name)
+		}
+		seen[cmd.Name] = true
+
+		if n := len([]rune(cmd.Description)); n < 3 || n > 256 {
+			t.Errorf("command %q: description length %d, want 3-256", cmd.Name, n)
+		}
+		if cmd.Handler == nil {
+			t.Errorf("command %q: nil handler", cmd.Name)
+		}
+	}
+}
+
+// TestBotCommands tests that private commands only appear in the authorized menu
+func TestBotCommands(t *testing.T) {
+	names := func(includePrivate bool) string {
+		var list []string
+		for _, cmd := range BotCommands(includePrivate) {
+			list = append(list, cmd.Command)
+		}
+		return "," + strings.Join(list, ",") + ","
+	}
+
+	public, private := names(false), names(true)
+
+	for _, cmd := range RegisteredCommands {
+		inPublic := strings.Contains(public, ","+cmd.Name+",")
+		inPrivate := strings.Contains(private, ","+cmd.Name+",")
+
+		if !inPrivate {
+			t.Errorf("command %q missing from the authorized menu", cmd.Name)
+		}
+		if inPublic == cmd.IsPrivate {
+			t.Errorf("command %q (private=%v): in public menu = %v", cmd.Name, cmd.IsPrivate, inPublic)
+		}
+	}
+}
+
+// TestFormatHelpMessage_ListsRegisteredCommands verifies /help is generated from
+// RegisteredCommands: every public command for everyone, private ones only when authorized
+func TestFormatHelpMessage_ListsRegisteredCommands(t *testing.T) {
+	public, authorized := formatHelpMessage("en", false), formatHelpMessage("en", true)
+
+	for _, cmd := range RegisteredCommands {
+		// Help text is MarkdownV2: underscores in names are escaped
+		name := "/" + strings.ReplaceAll(cmd.Name, "_", "\\_") + " "
+
+		if !strings.Contains(authorized, name) {
+			t.Errorf("authorized help is missing %q", name)
+		}
+		if strings.Contains(public, name) == cmd.IsPrivate {
+			t.Errorf("public help: %q shown = %v, private = %v", name, !cmd.IsPrivate, cmd.IsPrivate)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// cooldownLimiter allows one request per user per window and tells the
+// others how long to wait
+//
+// Unlike floodGuard (which silently drops resends of the same message),
+// throttled users get an answer, so Allow reports the remaining time.
+//
+// Safe for concurrent use: updates are handled in parallel.
+type cooldownLimiter struct {
+	mu        sync.Mutex
+	window    time.Duration
+	last      map[int64]time.Time // When each user's last allowed request started
+	lastSweep time.Time
+	now       func() time.Time // Replaced in tests
+}
+
+// newCooldownLimiter creates a limiter with the given cooldown window
+func newCooldownLimiter(window time.Duration) *cooldownLimiter {
+	return &cooldownLimiter{
+		window:    window,
+		last:      make(map[int64]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// ovhCooldown throttles OVH requests per user (set by main.go via SetOVHCooldown)
+// nil: no cooldown
+var ovhCooldown *cooldownLimiter
+
+// SetOVHCooldown sets the minimum time between two OVH requests of a user
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - window: cfg.OVHCooldown; 0 disables the cooldown
+func SetOVHCooldown(window time.Duration) {
+	if window <= 0 {
+		ovhCooldown = nil
+		return
+	}
+	ovhCooldown = newCooldownLimiter(window)
+}
+
+// Allow reports whether the user may make a request now
+// An allowed request starts a new window; throttled ones don't extend it.
+//
+// Parameters:
+//   - userID: Telegram user ID
+//
+// Returns:
+//   - bool: true if the request may go ahead
+//   - time.Duration: Time left until the next request is allowed (0 when allowed)
+func (l *cooldownLimiter) Allow(userID int64) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	if last, ok := l.last[userID]; ok {
+		if remaining := last.Add(l.window).Sub(now); remaining > 0 {
+			return false, remaining
+		}
+	}
+	l.last[userID] = now
+	return true, 0
+}
+
+// sweep drops users whose window has passed so the map doesn't grow forever
+// Runs at most once per window. Must be called with l.mu held.
+func (l *cooldownLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for userID, last := range l.last {
+		if now.Sub(last) >= l.window {
+			delete(l.last, userID)
+		}
+	}
+	l.lastSweep = now
+}
+
+// formatCooldownWait renders the remaining cooldown for users: "7s", "1m30s"
+// Rounded up to whole seconds, so a wait of 200ms reads "1s", never "0s".
+func formatCooldownWait(remaining time.Duration) string {
+	seconds := (remaining + time.Second - 1) / time.Second
+	return (seconds * time.Second).String()
+}
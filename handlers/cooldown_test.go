@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+)
+
+// TestCooldownLimiter_Allow tests the remaining time at various points of the window
+//
+// Steps (10s window, times relative to the first request):
+//   - 0s: allowed, starts the window
+//   - 0s, 3s, 9.5s: throttled with 10s, 7s, 500ms left (throttled requests don't extend the window)
+//   - 10s: allowed again, new window
+//   - Another user is independent
+func TestCooldownLimiter_Allow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	limiter := newCooldownLimiter(10 * time.Second)
+	limiter.now = func() time.Time { return now }
+
+	steps := []struct {
+		at            time.Duration
+		userID        int64
+		wantAllowed   bool
+		wantRemaining time.Duration
+	}{
+		{at: 0, userID: 1, wantAllowed: true},
+		{at: 0, userID: 1, wantRemaining: 10 * time.Second},
+		{at: 3 * time.Second, userID: 1, wantRemaining: 7 * time.Second},
+		{at: 9500 * time.Millisecond, userID: 1, wantRemaining: 500 * time.Millisecond},
+		{at: 9500 * time.Millisecond, userID: 2, wantAllowed: true},
+		{at: 10 * time.Second, userID: 1, wantAllowed: true},
+		{at: 12 * time.Second, userID: 1, wantRemaining: 8 * time.Second},
+	}
+
+	for _, step := range steps {
+		now = start.Add(step.at)
+		allowed, remaining := limiter.Allow(step.userID)
+		if allowed != step.wantAllowed || remaining != step.wantRemaining {
+			t.Errorf("at %v user %d: Allow() = %v, %v; want %v, %v",
+				step.at, step.userID, allowed, remaining, step.wantAllowed, step.wantRemaining)
+		}
+	}
+}
+
+// TestCooldownLimiter_Sweep tests that expired users are dropped from the map
+func TestCooldownLimiter_Sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newCooldownLimiter(time.Second)
+	limiter.now = func() time.Time { return now }
+	limiter.lastSweep = now
+
+	for userID := range int64(100) {
+		limiter.Allow(userID)
+	}
+
+	now = now.Add(2 * time.Second)
+	limiter.Allow(1000)
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.last) != 1 {
+		t.Errorf("users after sweep = %d, want 1", len(limiter.last))
+	}
+}
+
+// TestFormatCooldownWait tests rounding of the displayed wait
+func TestFormatCooldownWait(t *testing.T) {
+	tests := []struct {
+		remaining time.Duration
+		want      string
+	}{
+		{remaining: 7 * time.Second, want: "7s"},
+		{remaining: 6200 * time.Millisecond, want: "7s"},
+		{remaining: 200 * time.Millisecond, want: "1s"},
+		{remaining: 90 * time.Second, want: "1m30s"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCooldownWait(tt.remaining); got != tt.want {
+			t.Errorf("formatCooldownWait(%v) = %q, want %q", tt.remaining, got, tt.want)
+		}
+	}
+}
+
+// TestHandleOVHCheck_Cooldown tests the reply to a throttled OVH request
+//
+// Cases:
+//   - First request: status message and results
+//   - Second request 3s later: only the wait message, OVH is not called
+func TestHandleOVHCheck_Cooldown(t *testing.T) {
+	oldCooldown, oldGetTopOffers := ovhCooldown, getTopOffers
+	defer func() { ovhCooldown, getTopOffers = oldCooldown, oldGetTopOffers }()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	SetOVHCooldown(10 * time.Second)
+	ovhCooldown.now = func() time.Time { return now }
+
+	fetches := 0
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		fetches++
+		return []ovh.Offer{{FQN: "24sk20", Price: 9.99, Currency: "EUR"}}, nil
+	}
+
+	first := &recordingSender{}
+	HandleOVHCheck(context.Background(), first, createTestMessage("🖥️ OVH Servers", 12345), testConfig())
+	if len(first.messages()) != 2 {
+		t.Fatalf("first request sent %d messages, want status + results", len(first.messages()))
+	}
+
+	now = now.Add(3 * time.Second)
+	second := &recordingSender{}
+	HandleOVHCheck(context.Background(), second, createTestMessage("🖥️ OVH Servers", 12345), testConfig())
+
+	messages := second.messages()
+	want := tgfmt.EscapeMarkdownV2("please wait 7s before trying again")
+	if len(messages) != 1 || !strings.Contains(messages[0].Text, want) {
+		t.Errorf("throttled request sent %+v, want one message containing %q", messages, want)
+	}
+	if fetches != 1 {
+		t.Errorf("OVH fetches = %d, want 1 (throttled request must not call OVH)", fetches)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// getCatalogLocale fetches an OVH catalog's currency and tax rate
+// Declared as var so tests can replace it and avoid real OVH API calls
+var getCatalogLocale = ovh.GetCatalogLocaleContext
+
+// HandleCurrency handles the /currency [subsidiary] command (private, like /ovh).
+// Shows the currency and tax rate OVH applies for a subsidiary, e.g.:
+//
+//	💱 OVH GB: Currency GBP, Tax rate 20.0%
+//
+// A quick check that prices are in the expected currency before buying.
+//
+// Arguments:
+//   - none: the bot's own subsidiary (ovhSubsidiary, the one /ovh prices use)
+//   - subsidiary code (case-insensitive): must be in ovh.ListSubsidiaries()
+//
+// Authorization comes first; unknown codes are then rejected before any API call.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleCurrency(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	ctx, allowed := authorize(ctx, bot, message, cfg, config.FeatureOVH)
+	if !allowed {
+		return
+	}
+
+	subsidiary := strings.ToUpper(strings.TrimSpace(message.CommandArguments()))
+	if subsidiary == "" {
+		subsidiary = ovhSubsidiary
+	}
+
+	lang := userLanguage(ctx, message.From)
+	if !ovh.IsKnownSubsidiary(subsidiary) {
+		msg := replyTo(message, translate(lang, "currency_unknown",
+			subsidiary, strings.Join(ovh.ListSubsidiaries(), ", ")))
+		if _, err := sendReply(ctx, bot, message, msg); err != nil {
+			log.Error("Failed to send unknown subsidiary message",
+				"error", err,
+				"message_type", messageType(msg))
+		}
+		return
+	}
+
+	var locale ovh.Locale
+	ok := runOVHFetch(ctx, bot, message, cfg, "currency", func(ctx context.Context) error {
+		log.Info("Fetching OVH catalog locale",
+			"subsidiary", subsidiary)
+
+		var err error
+		locale, err = getCatalogLocale(ctx, subsidiary)
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	msg := replyTo(message, formatCurrency(lang, subsidiary, locale))
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send currency message",
+			"error", err,
+			"message_type", messageType(msg))
+		return
+	}
+
+	log.Info("Currency sent successfully",
+		"subsidiary", subsidiary,
+		"currency", locale.CurrencyCode)
+}
+
+// formatCurrency builds the plain text /currency reply
+// OVH reports the tax rate in percent (e.g., 20 for 20% VAT)
+func formatCurrency(lang, subsidiary string, locale ovh.Locale) string {
+	return translate(lang, "currency_info", subsidiary, locale.CurrencyCode, locale.TaxRate)
+}
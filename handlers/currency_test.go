@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestHandleCurrency tests argument handling, authorization and the reply
+//
+// Cases:
+//   - No argument: the bot's subsidiary (ovhSubsidiary)
+//   - Lowercase argument: normalized to uppercase
+//   - Unknown subsidiary: rejected without an API call
+//   - Unauthorized user: no API call, not even the subsidiary check
+func TestHandleCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		text           string
+		userID         int64
+		wantSubsidiary string // "" = API must not be called
+		wantLast       string // Expected last message
+	}{
+		{name: "default subsidiary", text: "/currency", userID: 12345, wantSubsidiary: "FR",
+			wantLast: "💱 OVH FR: Currency EUR, Tax rate 20.0%"},
+		{name: "lowercase argument", text: "/currency gb", userID: 12345, wantSubsidiary: "GB",
+			wantLast: "💱 OVH GB: Currency GBP, Tax rate 20.0%"},
+		{name: "unknown subsidiary", text: "/currency UK", userID: 12345,
+			wantLast: "Unknown subsidiary: UK"},
+		{name: "unauthorized", text: "/currency GB", userID: 99999,
+			wantLast: "only available to authorized users"},
+		{name: "unauthorized unknown subsidiary", text: "/currency XX", userID: 99999,
+			wantLast: "only available to authorized users"},
+	}
+
+	locales := map[string]ovh.Locale{
+		"FR": {CurrencyCode: "EUR", Subsidiary: "FR", TaxRate: 20},
+		"GB": {CurrencyCode: "GBP", Subsidiary: "GB", TaxRate: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested string
+			original := getCatalogLocale
+			getCatalogLocale = func(ctx context.Context, subsidiary string) (ovh.Locale, error) {
+				requested = subsidiary
+				return locales[subsidiary], nil
+			}
+			defer func() { getCatalogLocale = original }()
+
+			audit := withAuditLog(t)
+			sender := &recordingSender{}
+			HandleCurrency(context.Background(), sender, createTestMessage(tt.text, tt.userID), testConfig())
+
+			if requested != tt.wantSubsidiary {
+				t.Errorf("getCatalogLocale subsidiary = %q, want %q", requested, tt.wantSubsidiary)
+			}
+			messages := sender.messages()
+			if len(messages) == 0 {
+				t.Fatal("HandleCurrency sent no messages")
+			}
+			if tt.userID != 12345 && len(messages) != 1 {
+				t.Errorf("sent %+v to an unauthorized user, want only the denial", messages)
+			}
+			entries, err := audit.RecentAudit(context.Background(), 10)
+			if err != nil || len(entries) != 1 || entries[0].Allowed != (tt.userID == 12345) {
+				t.Errorf("audit entries = %+v, %v, want one entry", entries, err)
+			}
+			if last := messages[len(messages)-1].Text; !strings.Contains(last, tt.wantLast) {
+				t.Errorf("last message = %q, want it to contain %q", last, tt.wantLast)
+			}
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package handlers
 
 import (
-	"fmt"
-	"log/slog"
+	"context"
 	"math/rand"
 
+	"github.com/Alrem/run-tbot/logger"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -23,50 +23,118 @@ import (
 //  2. Send message with dice result
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing button click
-func HandleDice(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func HandleDice(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+	log := logger.FromContext(ctx)
+
 	// Step 1: Generate random dice number (1-6)
 	result := rollDice()
 
 	// Log the dice roll for debugging/monitoring
 	// In production, this helps track bot usage and debug issues
-	slog.Info("Dice rolled",
-		"user_id", message.From.ID,
+	log.Info("Dice rolled",
 		"username", message.From.UserName,
 		"result", result)
 
 	// Step 2: Send dice result message
-	// Create message text with dice emoji and result
-	// Unicode dice emoji: 🎲 (U+1F3B2)
-	messageText := fmt.Sprintf("🎲 You rolled: %d", result)
+	// Die face plus the number, e.g. "⚃ You rolled a 4" (see formatDiceResult)
+	// in the user's language (see userLanguage)
+	messageText := formatDiceResult(userLanguage(ctx, message.From), result)
 
-	// NewMessage creates a MessageConfig
-	// Parameters: chatID (where to send), text (message content)
-	msg := tgbotapi.NewMessage(message.Chat.ID, messageText)
+	// replyTo creates a MessageConfig (see bot.Reply)
+	// Parameters: message (chat to answer and message to reply to in groups), text
+	msg := replyTo(message, messageText)
 
 	// Send the message
-	// bot.Send() returns Message and error
+	// sendReply quotes the request in group chats (see reply.go)
 	// We ignore the returned Message (don't need it), but check error
-	if _, err := bot.Send(msg); err != nil {
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
 		// If sending fails, user won't see the result
 		// This could happen if:
 		//   - Bot was blocked by user
 		//   - Chat was deleted
 		//   - Network error
 		//   - Telegram API is down
-		slog.Error("Failed to send dice result",
+		log.Error("Failed to send dice result",
 			"error", err,
-			"chat_id", message.Chat.ID,
+			"message_type", messageType(msg),
 			"result", result)
 		return
 	}
 
-	slog.Info("Dice result sent successfully",
-		"chat_id", message.Chat.ID,
+	log.Info("Dice result sent successfully",
 		"result", result)
 }
 
+// HandleAnimatedDice is the animated variant of HandleDice.
+// Instead of generating a number ourselves, we ask Telegram to roll its
+// native animated 🎲 (sendDice API method). Used when USE_ANIMATED_DICE=true.
+//
+// How Telegram dice work:
+//   - tgbotapi.NewDice(chatID) creates a DiceConfig with default emoji "🎲"
+//   - Telegram picks the random value server-side and plays an animation
+//   - The value (1-6) comes back in the Send response: Message.Dice.Value
+//   - Other emojis are supported via NewDiceWithEmoji: 🎯 🏀 ⚽ 🎳 🎰
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing button click
+func HandleAnimatedDice(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+	log := logger.FromContext(ctx)
+
+	dice := tgbotapi.NewDice(message.Chat.ID)
+	sent, err := sendReply(ctx, bot, message, dice)
+	if err != nil {
+		log.Error("Failed to send animated dice",
+			"error", err,
+			"message_type", messageType(dice))
+		return
+	}
+
+	// Dice can be nil if Telegram returned an unexpected message type
+	value := 0
+	if sent.Dice != nil {
+		value = sent.Dice.Value
+	}
+
+	log.Info("Animated dice rolled",
+		"username", message.From.UserName,
+		"result", value)
+}
+
+// dieFaces are the Unicode die faces ⚀-⚅ (U+2680-U+2685), index = value - 1
+//
+// Each face is one rune of 3 bytes in UTF-8 and one UTF-16 code unit,
+// so it counts as one character against Telegram's limits (unlike
+// emoji such as 🎲, which take two UTF-16 units).
+var dieFaces = []string{"⚀", "⚁", "⚂", "⚃", "⚄", "⚅"}
+
+// dieFace returns the Unicode die face for a value
+//
+// Parameters:
+//   - value: Die value (1-6)
+//
+// Returns:
+//   - string: "⚀" to "⚅", or "🎲" for values outside 1-6
+func dieFace(value int) string {
+	if value < 1 || value > len(dieFaces) {
+		return "🎲"
+	}
+	return dieFaces[value-1]
+}
+
+// formatDiceResult builds the plain-text single die result
+// The number stays next to the face: screen readers and some fonts
+// don't render ⚀-⚅ meaningfully.
+//
+// Example: formatDiceResult("en", 4) = "⚃ You rolled a 4"
+func formatDiceResult(lang string, value int) string {
+	return translate(lang, "dice_result", dieFace(value), value)
+}
+
 // rollDice generates a random number between 1 and 6 (inclusive).
 // This simulates a standard 6-sided dice roll.
 //
@@ -1,6 +1,13 @@
 package handlers
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
 
 // TestRollDice tests the rollDice function to ensure it always returns values in range [1, 6].
 //
@@ -72,4 +79,98 @@ func TestRollDice(t *testing.T) {
 // Example of where table-driven tests would be useful:
 //   - Testing parseUserID("123") -> 123, nil
 //   - Testing validateDiceRoll(7) -> false
-//   - Testing formatDiceResult(3) -> "🎲 You rolled: 3"
+//   - Testing formatDiceResult(3) -> "⚂ You rolled a 3" (see TestFormatDiceResult)
+
+// TestRouteUpdate_AnimatedDice verifies that USE_ANIMATED_DICE switches the
+// dice button to Telegram's native dice (DiceConfig instead of a text message).
+func TestRouteUpdate_AnimatedDice(t *testing.T) {
+	tests := []struct {
+		name         string
+		animated     bool
+		wantDiceSent bool
+	}{
+		{name: "text dice by default", animated: false, wantDiceSent: false},
+		{name: "animated dice when enabled", animated: true, wantDiceSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.UseAnimatedDice = tt.animated
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage("🎲 Dice", 12345)}, cfg)
+
+			if len(sender.sent) != 1 {
+				t.Fatalf("RouteUpdate sent %d messages, want 1", len(sender.sent))
+			}
+			_, isDice := sender.sent[0].(tgbotapi.DiceConfig)
+			if isDice != tt.wantDiceSent {
+				t.Errorf("sent %T, want DiceConfig = %v", sender.sent[0], tt.wantDiceSent)
+			}
+		})
+	}
+}
+
+// TestFormatDiceResult tests the die face and text for all six values
+//
+// Checks:
+//   - Each value gets its own face (⚀-⚅) and keeps the number
+//   - Each face is a single rune and a single UTF-16 unit, so length
+//     limits counted in runes or UTF-16 units see one character
+//   - Out-of-range values fall back to 🎲 instead of panicking
+func TestFormatDiceResult(t *testing.T) {
+	tests := []struct {
+		value int
+		want  string
+	}{
+		{value: 1, want: "⚀ You rolled a 1"},
+		{value: 2, want: "⚁ You rolled a 2"},
+		{value: 3, want: "⚂ You rolled a 3"},
+		{value: 4, want: "⚃ You rolled a 4"},
+		{value: 5, want: "⚄ You rolled a 5"},
+		{value: 6, want: "⚅ You rolled a 6"},
+		{value: 0, want: "🎲 You rolled a 0"},
+		{value: 7, want: "🎲 You rolled a 7"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDiceResult("en", tt.value); got != tt.want {
+			t.Errorf("formatDiceResult(%d) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	for value := 1; value <= 6; value++ {
+		face := dieFace(value)
+		if utf8.RuneCountInString(face) != 1 || utf16Len(face) != 1 {
+			t.Errorf("dieFace(%d) = %q: %d runes, %d UTF-16 units; want 1 and 1",
+				value, face, utf8.RuneCountInString(face), utf16Len(face))
+		}
+	}
+}
+
+// TestHandleDice_Text tests the message HandleDice sends
+// The value is random, so the text is checked against the face of the number it shows.
+func TestHandleDice_Text(t *testing.T) {
+	for range 20 {
+		sender := &recordingSender{}
+		HandleDice(context.Background(), sender, createTestMessage("🎲 Dice", 12345))
+
+		messages := sender.messages()
+		if len(messages) != 1 {
+			t.Fatalf("HandleDice() sent %d messages, want 1", len(messages))
+		}
+
+		var face string
+		var value int
+		if _, err := fmt.Sscanf(messages[0].Text, "%s You rolled a %d", &face, &value); err != nil {
+			t.Fatalf("text %q doesn't match \"<face> You rolled a N\": %v", messages[0].Text, err)
+		}
+		if face != dieFace(value) || value < 1 || value > 6 {
+			t.Errorf("text %q: face %q doesn't match value %d", messages[0].Text, face, value)
+		}
+		if messages[0].ParseMode != "" {
+			t.Errorf("ParseMode = %q, want plain text", messages[0].ParseMode)
+		}
+	}
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"math/rand"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -23,47 +29,216 @@ import (
 //  1. Roll two dice (each 1-6)
 //  2. Calculate sum
 //  3. Send message with both dice values and sum
+//     (plus the sum's probability with DICE_SHOW_PROBABILITY)
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing button click
-func HandleDoubleDice(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+//   - cfg: Application configuration (DiceShowProbability)
+func HandleDoubleDice(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
 	// Step 1: Roll two dice
 	dice1, dice2, sum := rollDoubleDice()
 
 	// Log the roll for debugging/monitoring
-	slog.Info("Double dice rolled",
-		"user_id", message.From.ID,
+	log.Info("Double dice rolled",
 		"username", message.From.UserName,
 		"dice1", dice1,
 		"dice2", dice2,
 		"sum", sum)
 
 	// Step 2: Create result message
-	// Show both dice values and their sum
-	// Format: "🎲🎲 You rolled: 3 + 5 = 8"
-	messageText := fmt.Sprintf("🎲🎲 You rolled: %d + %d = *%d*", dice1, dice2, sum)
-
-	// NewMessage creates a MessageConfig
-	msg := tgbotapi.NewMessage(message.Chat.ID, messageText)
+	// Show both dice faces with their values and the sum (see formatDoubleDiceResult)
+	messageText := formatDoubleDiceResult(dice1, dice2, sum)
+	if cfg.DiceShowProbability {
+		messageText += "\n" + tgfmt.EscapeMarkdownV2(formatDoubleDiceProbability(userLanguage(ctx, message.From), sum))
+	}
 
-	// Enable Markdown formatting for bold sum
-	msg.ParseMode = "Markdown"
+	// replyTo creates a MessageConfig (see bot.Reply)
+	msg := replyTo(message, messageText)
 
 	// Step 3: Send the message
-	if _, err := bot.Send(msg); err != nil {
-		slog.Error("Failed to send double dice result",
+	// sendFormattedReply enables MarkdownV2 (bold sum) with plain text fallback
+	// and quotes the request in group chats
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send double dice result",
 			"error", err,
-			"chat_id", message.Chat.ID,
+			"message_type", messageType(msg),
 			"dice1", dice1,
 			"dice2", dice2,
 			"sum", sum)
 		return
 	}
 
-	slog.Info("Double dice result sent successfully",
-		"chat_id", message.Chat.ID,
+	log.Info("Double dice result sent successfully",
+		"sum", sum)
+}
+
+// animatedDiceDelay is the pause between the two animated dice messages
+// A short pause keeps messages in order and looks more natural in the chat
+// Declared as var so tests can set it to zero
+var animatedDiceDelay = 500 * time.Millisecond
+
+// HandleAnimatedDoubleDice is the animated variant of HandleDoubleDice.
+// Sends two native Telegram 🎲 dice and then a message with their sum.
+// Used when USE_ANIMATED_DICE=true.
+//
+// The tricky part:
+//   - Telegram decides the dice value, not us
+//   - The value is NOT delivered as a separate update
+//   - It is returned in the Send response: Message.Dice.Value
+//
+// Flow:
+//  1. Send first dice, read value from response
+//  2. Wait animatedDiceDelay
+//  3. Send second dice, read value from response
+//  4. Send "Sum: N!"
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing button click
+func HandleAnimatedDoubleDice(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+	log := logger.FromContext(ctx)
+
+	// Step 1: First dice
+	dice1, ok := sendAnimatedDice(ctx, bot, message)
+	if !ok {
+		return
+	}
+
+	// Step 2: Short pause between rolls
+	time.Sleep(animatedDiceDelay)
+
+	// Step 3: Second dice
+	dice2, ok := sendAnimatedDice(ctx, bot, message)
+	if !ok {
+		return
+	}
+
+	sum := dice1 + dice2
+
+	log.Info("Animated double dice rolled",
+		"username", message.From.UserName,
+		"dice1", dice1,
+		"dice2", dice2,
 		"sum", sum)
+
+	// Step 4: Send sum as a regular text message
+	msg := replyTo(message, translate(userLanguage(ctx, message.From), "double_dice_sum", sum))
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send animated double dice sum",
+			"error", err,
+			"message_type", messageType(msg),
+			"sum", sum)
+	}
+}
+
+// sendAnimatedDice sends one native 🎲 and returns the value Telegram rolled.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message that triggered the roll (dice reply to it in groups)
+//
+// Returns:
+//   - int: Dice value (1-6)
+//   - bool: false if sending failed or the response had no dice value
+func sendAnimatedDice(ctx context.Context, bot BotSender, message *tgbotapi.Message) (int, bool) {
+	log := logger.FromContext(ctx)
+
+	chatID := message.Chat.ID
+	dice := tgbotapi.NewDice(chatID)
+	sent, err := sendReply(ctx, bot, message, dice)
+	if err != nil {
+		log.Error("Failed to send animated dice",
+			"error", err,
+			"message_type", messageType(dice),
+			"chat_id", chatID)
+		return 0, false
+	}
+
+	if sent.Dice == nil {
+		log.Error("Animated dice response has no dice value",
+			"chat_id", chatID,
+			"message_id", sent.MessageID)
+		return 0, false
+	}
+
+	return sent.Dice.Value, true
+}
+
+// formatDoubleDiceResult builds the MarkdownV2 double dice result
+// Each face keeps its number for accessibility; the sum is bold.
+//
+// Example: formatDoubleDiceResult(3, 5, 8) = `⚂ 3 \+ ⚄ 5 \= *8*`,
+// displayed as "⚂ 3 + ⚄ 5 = 8" with a bold 8
+//
+// + and = are reserved in MarkdownV2, so the plain part is escaped;
+// the die faces aren't special characters and pass through unchanged.
+func formatDoubleDiceResult(dice1, dice2, sum int) string {
+	return tgfmt.EscapeMarkdownV2(fmt.Sprintf("%s %d + %s %d = ", dieFace(dice1), dice1, dieFace(dice2), dice2)) +
+		tgfmt.Bold(strconv.Itoa(sum))
+}
+
+// doubleDiceProbability returns the chance of rolling a sum with two dice,
+// with a label for the notable sums.
+//
+// With two six-sided dice there are 6 × 6 = 36 equally likely outcomes;
+// a sum s can be made in 6 - |s - 7| of them (1 way for 2 and 12, 6 ways
+// for 7), so P(s) = (6 - |s - 7|) / 36.
+//
+// Labels:
+//   - "lucky" / "unlucky": the tails, at most 5% (12 and 2, 1/36 ≈ 2.8% each)
+//   - "most common": 7 (6/36 ≈ 16.7%)
+//   - "": everything else
+//
+// Parameters:
+//   - sum: Sum of two dice (2-12)
+//
+// Returns:
+//   - float64: Probability from 0 to 1 (0 for impossible sums)
+//   - string: Label, "" if the sum is unremarkable
+func doubleDiceProbability(sum int) (float64, string) {
+	ways := 6 - abs(sum-7)
+	if ways <= 0 {
+		return 0, ""
+	}
+	p := float64(ways) / 36
+
+	switch {
+	case sum == 7:
+		return p, "most common"
+	case p <= 0.05 && sum > 7:
+		return p, "lucky"
+	case p <= 0.05:
+		return p, "unlucky"
+	default:
+		return p, ""
+	}
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// formatDoubleDiceProbability builds the plain-text probability line
+// Labels are translated as "double_dice_" + label ("double_dice_most_common").
+//
+// Examples: "P(sum=12) = 2.8% — lucky!", "P(sum=8) = 13.9%"
+func formatDoubleDiceProbability(lang string, sum int) string {
+	p, label := doubleDiceProbability(sum)
+	line := translate(lang, "double_dice_probability", sum, p*100)
+	if label != "" {
+		line += " — " + translate(lang, "double_dice_"+strings.ReplaceAll(label, " ", "_"))
+	}
+	return line
 }
 
 // rollDoubleDice rolls two dice and returns both values plus their sum.
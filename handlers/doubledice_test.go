@@ -1,7 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // TestRollDoubleDice tests the rollDoubleDice function.
@@ -99,19 +106,196 @@ func TestRollDoubleDice(t *testing.T) {
 //
 // This gives us confidence without flaky tests.
 
-// Note on testing HandleDoubleDice:
-// We don't test HandleDoubleDice directly because it requires:
-//   - Mock BotAPI
-//   - Mock Message structure
-//   - Assertions on bot.Send calls and message content
-//
-// For an educational project, we:
-//   1. Test the core logic (rollDoubleDice)
-//   2. Rely on integration tests for full flow
-//   3. Manual testing with real bot
+// TestFormatDoubleDiceResult tests the double dice text for all six faces
 //
-// In production, you might add:
-//   - Mock-based tests for HandleDoubleDice
-//   - Verify message format (dice + dice = sum)
-//   - Verify Markdown formatting is applied
-//   - Verify logging occurs
+// Checks:
+//   - Both faces and their numbers appear, the sum is bold
+//   - The text is valid MarkdownV2 (+ and = escaped, faces untouched)
+func TestFormatDoubleDiceResult(t *testing.T) {
+	tests := []struct {
+		dice1, dice2 int
+		want         string
+	}{
+		{dice1: 1, dice2: 2, want: `⚀ 1 \+ ⚁ 2 \= *3*`},
+		{dice1: 3, dice2: 5, want: `⚂ 3 \+ ⚄ 5 \= *8*`},
+		{dice1: 4, dice2: 6, want: `⚃ 4 \+ ⚅ 6 \= *10*`},
+		{dice1: 6, dice2: 6, want: `⚅ 6 \+ ⚅ 6 \= *12*`},
+	}
+
+	for _, tt := range tests {
+		got := formatDoubleDiceResult(tt.dice1, tt.dice2, tt.dice1+tt.dice2)
+		if got != tt.want {
+			t.Errorf("formatDoubleDiceResult(%d, %d) = %q, want %q", tt.dice1, tt.dice2, got, tt.want)
+		}
+		if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+			t.Errorf("formatDoubleDiceResult(%d, %d) is not valid MarkdownV2: %v", tt.dice1, tt.dice2, err)
+		}
+	}
+}
+
+// TestDoubleDiceProbability tests the exact 2d6 probabilities and labels for every sum
+func TestDoubleDiceProbability(t *testing.T) {
+	tests := []struct {
+		sum       int
+		ways      int // Outcomes out of 36
+		wantLabel string
+	}{
+		{sum: 2, ways: 1, wantLabel: "unlucky"},
+		{sum: 3, ways: 2},
+		{sum: 4, ways: 3},
+		{sum: 5, ways: 4},
+		{sum: 6, ways: 5},
+		{sum: 7, ways: 6, wantLabel: "most common"},
+		{sum: 8, ways: 5},
+		{sum: 9, ways: 4},
+		{sum: 10, ways: 3},
+		{sum: 11, ways: 2},
+		{sum: 12, ways: 1, wantLabel: "lucky"},
+		{sum: 1, ways: 0},
+		{sum: 13, ways: 0},
+	}
+
+	total := 0.0
+	for _, tt := range tests {
+		p, label := doubleDiceProbability(tt.sum)
+		if want := float64(tt.ways) / 36; p != want {
+			t.Errorf("doubleDiceProbability(%d) = %v, want %d/36 = %v", tt.sum, p, tt.ways, want)
+		}
+		if label != tt.wantLabel {
+			t.Errorf("doubleDiceProbability(%d) label = %q, want %q", tt.sum, label, tt.wantLabel)
+		}
+		total += p
+	}
+
+	// Every outcome is counted exactly once
+	if math.Abs(total-1) > 1e-12 {
+		t.Errorf("probabilities add up to %v, want 1", total)
+	}
+}
+
+// TestFormatDoubleDiceProbability tests the probability line, in English and French
+func TestFormatDoubleDiceProbability(t *testing.T) {
+	tests := []struct {
+		lang string
+		sum  int
+		want string
+	}{
+		{lang: "en", sum: 12, want: "P(sum=12) = 2.8% — lucky!"},
+		{lang: "en", sum: 2, want: "P(sum=2) = 2.8% — unlucky!"},
+		{lang: "en", sum: 7, want: "P(sum=7) = 16.7% — most common!"},
+		{lang: "en", sum: 8, want: "P(sum=8) = 13.9%"},
+		{lang: "fr", sum: 12, want: "P(somme=12) = 2.8 % — chanceux !"},
+	}
+	for _, tt := range tests {
+		if got := formatDoubleDiceProbability(tt.lang, tt.sum); got != tt.want {
+			t.Errorf("formatDoubleDiceProbability(%s, %d) = %q, want %q", tt.lang, tt.sum, got, tt.want)
+		}
+	}
+}
+
+// TestHandleDoubleDice_Probability tests that DICE_SHOW_PROBABILITY adds the line
+func TestHandleDoubleDice_Probability(t *testing.T) {
+	for _, show := range []bool{false, true} {
+		cfg := testConfig()
+		cfg.DiceShowProbability = show
+		sender := &recordingSender{}
+
+		HandleDoubleDice(context.Background(), sender, createTestMessage("🎲🎲 Double Dice", 12345), cfg)
+
+		messages := sender.messages()
+		if len(messages) != 1 {
+			t.Fatalf("HandleDoubleDice() sent %d messages, want 1", len(messages))
+		}
+		text := messages[0].Text
+		if got := strings.Contains(text, "P\\(sum\\="); got != show {
+			t.Errorf("DiceShowProbability=%t: text %q has probability line = %t", show, text, got)
+		}
+		if err := tgfmt.ValidateMarkdownV2(text); err != nil {
+			t.Errorf("DiceShowProbability=%t: text is not valid MarkdownV2: %v", show, err)
+		}
+	}
+}
+
+// TestHandleDoubleDice_Text tests the message HandleDoubleDice sends
+// The values are random, so faces and sum are checked against the numbers shown.
+func TestHandleDoubleDice_Text(t *testing.T) {
+	for range 20 {
+		sender := &recordingSender{}
+		HandleDoubleDice(context.Background(), sender, createTestMessage("🎲🎲 Double Dice", 12345), testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 1 {
+			t.Fatalf("HandleDoubleDice() sent %d messages, want 1", len(messages))
+		}
+		if messages[0].ParseMode != tgbotapi.ModeMarkdownV2 {
+			t.Errorf("ParseMode = %q, want MarkdownV2", messages[0].ParseMode)
+		}
+
+		var face1, face2 string
+		var dice1, dice2, sum int
+		if _, err := fmt.Sscanf(messages[0].Text, `%s %d \+ %s %d \= *%d*`, &face1, &dice1, &face2, &dice2, &sum); err != nil {
+			t.Fatalf("text %q doesn't match the double dice format: %v", messages[0].Text, err)
+		}
+		if face1 != dieFace(dice1) || face2 != dieFace(dice2) || sum != dice1+dice2 {
+			t.Errorf("text %q: faces or sum don't match the values", messages[0].Text)
+		}
+	}
+}
+
+// TestHandleAnimatedDoubleDice verifies the animated variant:
+//   - Sends exactly two DiceConfig messages
+//   - Reads values from the Send responses (Message.Dice.Value)
+//   - Sends a third text message with the correct sum
+func TestHandleAnimatedDoubleDice(t *testing.T) {
+	// No pause between dice in tests
+	oldDelay := animatedDiceDelay
+	animatedDiceDelay = 0
+	defer func() { animatedDiceDelay = oldDelay }()
+
+	// Telegram "rolls" 2 and then 5
+	values := []int{2, 5}
+	sender := &recordingSender{}
+	sender.respond = func(c tgbotapi.Chattable) tgbotapi.Message {
+		if _, ok := c.(tgbotapi.DiceConfig); ok && len(values) > 0 {
+			value := values[0]
+			values = values[1:]
+			return tgbotapi.Message{Dice: &tgbotapi.Dice{Emoji: "🎲", Value: value}}
+		}
+		return tgbotapi.Message{}
+	}
+
+	HandleAnimatedDoubleDice(context.Background(), sender, createTestMessage("🎲🎲 Double Dice", 12345))
+
+	if len(sender.sent) != 3 {
+		t.Fatalf("HandleAnimatedDoubleDice sent %d messages, want 3", len(sender.sent))
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok := sender.sent[i].(tgbotapi.DiceConfig); !ok {
+			t.Errorf("message %d type = %T, want tgbotapi.DiceConfig", i, sender.sent[i])
+		}
+	}
+
+	sumMsg, ok := sender.sent[2].(tgbotapi.MessageConfig)
+	if !ok {
+		t.Fatalf("third message type = %T, want tgbotapi.MessageConfig", sender.sent[2])
+	}
+	if sumMsg.Text != "Sum: 7!" {
+		t.Errorf("sum message = %q, want %q", sumMsg.Text, "Sum: 7!")
+	}
+}
+
+// TestHandleAnimatedDoubleDice_MissingValue verifies that no sum is sent
+// when Telegram's response doesn't contain a dice value.
+func TestHandleAnimatedDoubleDice_MissingValue(t *testing.T) {
+	oldDelay := animatedDiceDelay
+	animatedDiceDelay = 0
+	defer func() { animatedDiceDelay = oldDelay }()
+
+	sender := &recordingSender{} // Default response has Dice == nil
+
+	HandleAnimatedDoubleDice(context.Background(), sender, createTestMessage("🎲🎲 Double Dice", 12345))
+
+	if len(sender.messages()) != 0 {
+		t.Errorf("sum message sent despite missing dice value: %+v", sender.messages())
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"unicode/utf16"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// echoPrefix starts every /echo reply
+const echoPrefix = "📣 Echo: "
+
+// HandleEcho handles the /echo command (private, for admins).
+// Sends the text after /echo back, then a second message with diagnostic IDs.
+//
+// Use cases:
+//   - Check that the bot is alive and can reach this chat
+//   - Test formatting: bold/italic/code/links in the request are echoed as-is
+//   - Debug delivery issues (message, chat and user IDs in the second message)
+//
+// Formatting is preserved by copying the request's entities (Telegram sends
+// formatting as entities, not as Markdown), shifted to the echoed position.
+// The echo is sent as plain text, so MarkdownV2 special characters
+// (_*[]()~>#+-=|{}.!) come back exactly as typed.
+//
+// Debugging tool: only registered while ENABLE_ECHO is on (by default
+// in development only), otherwise /echo is an unknown command.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /echo command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleEcho(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	lang := userLanguage(ctx, message.From)
+	text := message.CommandArguments()
+	if text == "" {
+		msg := replyTo(message, translate(lang, "echo_usage"))
+		if _, err := sendReply(ctx, bot, message, msg); err != nil {
+			log.Error("Failed to send /echo usage",
+				"error", err,
+				"message_type", messageType(msg))
+		}
+		return
+	}
+
+	// Message 1: the echo itself, with the original formatting
+	echo := replyTo(message, echoPrefix+text)
+	echo.Entities = shiftEntities(message.Entities, utf16Len(message.Text)-utf16Len(text), utf16Len(echoPrefix))
+	if _, err := sendReply(ctx, bot, message, echo); err != nil {
+		log.Error("Failed to send /echo reply",
+			"error", err,
+			"message_type", messageType(echo))
+		return
+	}
+
+	// Message 2: diagnostics
+	diagnostics := replyTo(message, formatEchoDiagnostics(lang, message))
+	if _, err := sendReply(ctx, bot, message, diagnostics); err != nil {
+		log.Error("Failed to send /echo diagnostics",
+			"error", err,
+			"message_type", messageType(diagnostics))
+		return
+	}
+
+	log.Info("/echo sent successfully",
+		"entities", len(echo.Entities))
+}
+
+// formatEchoDiagnostics builds the plain text diagnostics message for /echo
+func formatEchoDiagnostics(lang string, message *tgbotapi.Message) string {
+	return translate(lang, "echo_diagnostics",
+		message.MessageID, message.Chat.ID, message.Chat.Type, message.From.ID)
+}
+
+// shiftEntities moves entities from one text position to another
+// Entities starting before from (e.g., the /echo bot_command) are dropped.
+//
+// Telegram measures entity offsets in UTF-16 code units, so both
+// positions must be too (see utf16Len).
+//
+// Parameters:
+//   - entities: Entities of the original message
+//   - from: UTF-16 offset where the copied text starts in the original
+//   - to: UTF-16 offset where it starts in the new message
+//
+// Returns:
+//   - []tgbotapi.MessageEntity: Shifted copies (nil if none apply)
+func shiftEntities(entities []tgbotapi.MessageEntity, from, to int) []tgbotapi.MessageEntity {
+	var shifted []tgbotapi.MessageEntity
+	for _, entity := range entities {
+		if entity.Offset < from {
+			continue
+		}
+		entity.Offset = entity.Offset - from + to
+		shifted = append(shifted, entity)
+	}
+	return shifted
+}
+
+// utf16Len returns the length of s in UTF-16 code units (Telegram's unit for entity offsets)
+// Emoji outside the Basic Multilingual Plane count as 2.
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestHandleEcho tests authorization, the echo and the diagnostics message
+//
+// Cases:
+//   - Unauthorized user: one authorization error, no echo
+//   - Authorized user: echo with prefix + diagnostics with message/chat/user IDs
+//   - No text: usage message only
+func TestHandleEcho(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		userID    int64
+		wantTexts []string // Substring expected in each sent message, in order
+	}{
+		{
+			name:      "unauthorized",
+			text:      "/echo hello",
+			userID:    99999,
+			wantTexts: []string{"only available to authorized users"},
+		},
+		{
+			name:      "authorized",
+			text:      "/echo hello world",
+			userID:    12345,
+			wantTexts: []string{"📣 Echo: hello world", "Message ID: 1\nChat ID: 12345 (private)\nUser ID: 12345"},
+		},
+		{
+			name:      "no text",
+			text:      "/echo",
+			userID:    12345,
+			wantTexts: []string{"Usage: /echo <text>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			HandleEcho(context.Background(), sender, createTestMessage(tt.text, tt.userID), testConfig())
+
+			messages := sender.messages()
+			if len(messages) != len(tt.wantTexts) {
+				t.Fatalf("HandleEcho sent %d messages, want %d", len(messages), len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				if !strings.Contains(messages[i].Text, want) {
+					t.Errorf("message %d = %q, want it to contain %q", i+1, messages[i].Text, want)
+				}
+			}
+		})
+	}
+}
+
+// TestHandleEcho_PreservesFormatting tests that entities are moved to the echoed text
+// "/echo 🎲 *bold*" with a bold entity on "bold": the prefix and the emoji
+// take 2 UTF-16 units each, so offsets must be counted in UTF-16, not bytes.
+func TestHandleEcho_PreservesFormatting(t *testing.T) {
+	message := createTestMessage("/echo 🎲 bold", 12345)
+	// "/echo " = 6, "🎲 " = 3 UTF-16 units -> "bold" starts at 9
+	message.Entities = append(message.Entities, tgbotapi.MessageEntity{Type: "bold", Offset: 9, Length: 4})
+
+	sender := &recordingSender{}
+	HandleEcho(context.Background(), sender, message, testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("HandleEcho sent %d messages, want 2", len(messages))
+	}
+
+	echo := messages[0]
+	if echo.ParseMode != "" {
+		t.Errorf("ParseMode = %q, want none (entities are used instead)", echo.ParseMode)
+	}
+	if len(echo.Entities) != 1 {
+		t.Fatalf("Entities = %+v, want only the bold entity (bot_command dropped)", echo.Entities)
+	}
+
+	// "📣 Echo: " = 9 UTF-16 units, then "🎲 " = 3 -> "bold" starts at 12
+	want := tgbotapi.MessageEntity{Type: "bold", Offset: 12, Length: 4}
+	if echo.Entities[0] != want {
+		t.Errorf("entity = %+v, want %+v", echo.Entities[0], want)
+	}
+}
+
+// TestHandleEcho_SpecialCharacters tests that MarkdownV2 special characters
+// come back exactly as typed: the echo is plain text, nothing to escape or lose
+func TestHandleEcho_SpecialCharacters(t *testing.T) {
+	const text = `_*[]()~>#+-=|{}.! and \ back\slash`
+
+	sender := &recordingSender{}
+	HandleEcho(context.Background(), sender, createTestMessage("/echo "+text, 12345), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("HandleEcho sent %d messages, want 2", len(messages))
+	}
+	if got, want := messages[0].Text, echoPrefix+text; got != want {
+		t.Errorf("echo = %q, want %q", got, want)
+	}
+	if messages[0].ParseMode != "" {
+		t.Errorf("ParseMode = %q, want none (text would be parsed as MarkdownV2)", messages[0].ParseMode)
+	}
+}
+
+// TestRouteUpdate_Echo tests that /echo is only routed while ENABLE_ECHO is on
+//
+// Cases:
+//   - Enabled (development default): the echo and the diagnostics
+//   - Disabled (production default): answered like an unknown command
+func TestRouteUpdate_Echo(t *testing.T) {
+	tests := []struct {
+		name      string
+		enabled   bool
+		wantTexts []string
+	}{
+		{name: "enabled", enabled: true, wantTexts: []string{"📣 Echo: hi", "Message ID: 1"}},
+		{name: "disabled", enabled: false, wantTexts: []string{"Unknown command"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Features.Echo = tt.enabled
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage("/echo hi", 12345)}, cfg)
+
+			messages := sender.messages()
+			if len(messages) != len(tt.wantTexts) {
+				t.Fatalf("RouteUpdate(/echo) sent %d messages, want %d", len(messages), len(tt.wantTexts))
+			}
+			for i, want := range tt.wantTexts {
+				if !strings.Contains(messages[i].Text, want) {
+					t.Errorf("message %d = %q, want it to contain %q", i, messages[i].Text, want)
+				}
+			}
+		})
+	}
+}
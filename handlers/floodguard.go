@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+)
+
+// floodWindow is how long an identical message from the same user is ignored
+// Long enough to swallow client-side network retries, short enough that a
+// user deliberately pressing 🎲 Dice twice is rarely affected.
+const floodWindow = time.Second
+
+// floodKey identifies a message for duplicate detection
+type floodKey struct {
+	userID int64
+	text   string
+}
+
+// floodGuard suppresses identical (user, text) messages within a short window.
+//
+// Why?
+//   - Some clients resend the same message on flaky networks
+//   - Telegram delivers each resend as a new update (new update_id),
+//     so without this guard one "🎲 Dice" tap can produce several rolls
+//
+// Safe for concurrent use: updates are handled in parallel.
+type floodGuard struct {
+	mu        sync.Mutex
+	window    time.Duration
+	seen      map[floodKey]time.Time // Last time each (user, text) was allowed
+	lastSweep time.Time
+	now       func() time.Time // Replaced in tests
+}
+
+// newFloodGuard creates a guard with the given suppression window
+func newFloodGuard(window time.Duration) *floodGuard {
+	return &floodGuard{
+		window:    window,
+		seen:      make(map[floodKey]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// messageFloodGuard is the guard used by RouteUpdate for new messages
+var messageFloodGuard = newFloodGuard(floodWindow)
+
+// SetFloodWindow replaces the duplicate-message window (floodWindow by default)
+// 0 disables the guard: tests outside this package route the same message
+// from the same user several times, e.g. with go test -count=2.
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - window: How long an identical message from the same user is ignored
+func SetFloodWindow(window time.Duration) {
+	messageFloodGuard = newFloodGuard(window)
+}
+
+// allow reports whether a message should be handled
+// The first message is allowed and starts the window; identical messages
+// inside the window are suppressed (and don't extend it).
+//
+// Parameters:
+//   - userID: Sender's Telegram user ID
+//   - text: Message text (button text or command)
+//
+// Returns:
+//   - bool: false if the same user sent the same text less than window ago
+func (g *floodGuard) allow(userID int64, text string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.sweep(now)
+
+	key := floodKey{userID: userID, text: text}
+	if last, ok := g.seen[key]; ok && now.Sub(last) < g.window {
+		return false
+	}
+	g.seen[key] = now
+	return true
+}
+
+// sweep drops entries older than the window so the map doesn't grow forever
+// Runs at most once per window. Must be called with g.mu held.
+func (g *floodGuard) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < g.window {
+		return
+	}
+	for key, last := range g.seen {
+		if now.Sub(last) >= g.window {
+			delete(g.seen, key)
+		}
+	}
+	g.lastSweep = now
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestMain disables the router's flood guard for the whole package:
+// many tests route the same message from the same user within a second
+// on purpose. Flood guard behavior is tested below with its own guards.
+func TestMain(m *testing.M) {
+	SetFloodWindow(0)
+	os.Exit(m.Run())
+}
+
+// TestFloodGuard tests suppression inside the window and expiry after it
+func TestFloodGuard(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := newFloodGuard(time.Second)
+	guard.now = func() time.Time { return now }
+
+	if !guard.allow(1, "🎲 Dice") {
+		t.Fatalf("first message suppressed")
+	}
+
+	// Client resend 200ms later: suppressed
+	now = now.Add(200 * time.Millisecond)
+	if guard.allow(1, "🎲 Dice") {
+		t.Errorf("duplicate within window allowed")
+	}
+
+	// Different text or different user: not a duplicate
+	if !guard.allow(1, "🌀 Twister") {
+		t.Errorf("different text suppressed")
+	}
+	if !guard.allow(2, "🎲 Dice") {
+		t.Errorf("different user suppressed")
+	}
+
+	// Window is measured from the allowed message, not extended by duplicates
+	now = now.Add(800 * time.Millisecond)
+	if !guard.allow(1, "🎲 Dice") {
+		t.Errorf("identical message after the window suppressed")
+	}
+}
+
+// TestFloodGuard_Sweep tests that expired entries are removed
+func TestFloodGuard_Sweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	guard := newFloodGuard(time.Second)
+	guard.now = func() time.Time { return now }
+	guard.lastSweep = now
+
+	for userID := range int64(10) {
+		guard.allow(userID, "/help")
+	}
+
+	now = now.Add(2 * time.Second)
+	guard.allow(99, "/help")
+
+	if len(guard.seen) != 1 {
+		t.Errorf("entries after sweep = %d, want 1", len(guard.seen))
+	}
+}
+
+// TestRouteUpdate_FloodGuard tests that RouteUpdate drops a rapid duplicate
+func TestRouteUpdate_FloodGuard(t *testing.T) {
+	original := messageFloodGuard
+	messageFloodGuard = newFloodGuard(time.Second)
+	defer func() { messageFloodGuard = original }()
+
+	sender := &recordingSender{}
+	for i := range 2 {
+		update := tgbotapi.Update{UpdateID: i + 1, Message: createTestMessage("🎲 Dice", 12345)}
+		RouteUpdate(context.Background(), sender, update, testConfig())
+	}
+
+	if got := len(sender.messages()); got != 1 {
+		t.Errorf("RouteUpdate sent %d messages for a duplicate tap, want 1", got)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// webhookAPIs are the Bot APIs used by /flushupdates, by bot username (set by main.go via SetWebhookAPI)
+// Handlers normally only get a BotSender, which can't call getWebhookInfo
+// Keyed by username so each bot of BOT_TOKENS flushes its own queue (cfg.BotUsername)
+var webhookAPIs = make(map[string]bot.WebhookAPI)
+
+// flushPendingUpdates is bot.FlushPendingUpdates (var for tests)
+var flushPendingUpdates = bot.FlushPendingUpdates
+
+// SetWebhookAPI gives /flushupdates access to the webhook API methods
+// Call once per bot at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - username: Bot username from getMe (the same value as cfg.BotUsername)
+//   - api: Usually the *tgbotapi.BotAPI created in main.go
+func SetWebhookAPI(username string, api bot.WebhookAPI) {
+	webhookAPIs[username] = api
+}
+
+// HandleFlushUpdates handles the /flushupdates command (private, for admins).
+// Drops every update Telegram has queued for the bot, keeping the webhook registered.
+//
+// Use case:
+//   - After the bot was down for a while, Telegram delivers hours of queued
+//     updates at once; /flushupdates throws away the ones still waiting
+//
+// Reports the number of dropped updates (see bot.FlushPendingUpdates).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleFlushUpdates(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	var text string
+	lang := userLanguage(ctx, message.From)
+	webhookAPI := webhookAPIs[cfg.BotUsername]
+	if webhookAPI == nil {
+		log.Error("Webhook API not configured, cannot flush updates")
+		text = translate(lang, "flush_unavailable")
+	} else if dropped, err := flushPendingUpdates(webhookAPI); err != nil {
+		log.Error("Failed to flush pending updates",
+			"error", err,
+			"dropped", dropped)
+		text = translate(lang, "flush_failed")
+	} else {
+		log.Info("Pending updates flushed",
+			"dropped", dropped)
+		text = translate(lang, "flush_done", dropped)
+	}
+
+	msg := replyTo(message, text)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /flushupdates result",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeWebhookAPI satisfies bot.WebhookAPI; flushPendingUpdates is stubbed,
+// so its methods are never called (bot.FlushPendingUpdates has its own tests)
+type fakeWebhookAPI struct {
+	recordingSender
+}
+
+func (f *fakeWebhookAPI) GetWebhookInfo() (tgbotapi.WebhookInfo, error) {
+	return tgbotapi.WebhookInfo{}, nil
+}
+
+// TestHandleFlushUpdates tests authorization and the reported result
+//
+// Cases:
+//   - Unauthorized user: authorization error, nothing flushed
+//   - Success: dropped count reported
+//   - Flush error: failure reported
+//   - No webhook API (SetWebhookAPI not called): "not available"
+//   - Webhook API of another bot only: "not available", nothing flushed
+func TestHandleFlushUpdates(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int64
+		api         bot.WebhookAPI
+		apiUsername string
+		dropped     int
+		flushErr    error
+		wantFlushed bool
+		wantText    string
+	}{
+		{name: "unauthorized", userID: 99999, api: &fakeWebhookAPI{}, wantText: "only available to authorized users"},
+		{name: "success", userID: 12345, api: &fakeWebhookAPI{}, dropped: 1198, wantFlushed: true, wantText: "Dropped 1198 pending update(s)"},
+		{name: "flush error", userID: 12345, api: &fakeWebhookAPI{}, flushErr: errors.New("telegram down"), wantFlushed: true, wantText: "Failed to flush"},
+		{name: "no webhook API", userID: 12345, api: nil, wantText: "not available"},
+		{name: "other bot's webhook API", userID: 12345, api: &fakeWebhookAPI{}, apiUsername: "other_bot", wantText: "not available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldAPIs, oldFlush := webhookAPIs, flushPendingUpdates
+			defer func() { webhookAPIs, flushPendingUpdates = oldAPIs, oldFlush }()
+
+			cfg := testConfig()
+			cfg.BotUsername = "test_bot"
+
+			webhookAPIs = make(map[string]bot.WebhookAPI)
+			if tt.api != nil {
+				username := cfg.BotUsername
+				if tt.apiUsername != "" {
+					username = tt.apiUsername
+				}
+				SetWebhookAPI(username, tt.api)
+			}
+
+			var flushedAPI bot.WebhookAPI
+			flushPendingUpdates = func(api bot.WebhookAPI) (int, error) {
+				flushedAPI = api
+				return tt.dropped, tt.flushErr
+			}
+
+			sender := &recordingSender{}
+			HandleFlushUpdates(context.Background(), sender, createTestMessage("/flushupdates", tt.userID), cfg)
+
+			if flushed := flushedAPI != nil; flushed != tt.wantFlushed {
+				t.Errorf("flushed = %v, want %v", flushed, tt.wantFlushed)
+			}
+			if flushedAPI != nil && flushedAPI != tt.api {
+				t.Errorf("flushed %v, want the bot's own API %v", flushedAPI, tt.api)
+			}
+			messages := sender.messages()
+			if len(messages) != 1 || !strings.Contains(messages[0].Text, tt.wantText) {
+				t.Errorf("sent %+v, want one message containing %q", messages, tt.wantText)
+			}
+		})
+	}
+}
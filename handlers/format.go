@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// sendFormatted sends a MarkdownV2 message and falls back to plain text
+// if Telegram can't parse the markup.
+//
+// Why?
+//   - One unescaped character makes Telegram reject the whole message
+//   - Without a fallback, the user gets nothing and only our logs know why
+//   - With a fallback, the user gets the same text without formatting
+//
+// Flow:
+//  1. Send with ParseMode = MarkdownV2
+//  2. If Telegram answers "can't parse entities": strip markup and send again as plain text
+//  3. Any other error is returned as-is (network, blocked bot, ...)
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - msg: Message with MarkdownV2 text (ReplyMarkup etc. are preserved on retry)
+//
+// Returns:
+//   - tgbotapi.Message: Sent message
+//   - error: Error from the last send attempt
+func sendFormatted(ctx context.Context, bot BotSender, msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	log := logger.FromContext(ctx)
+
+	msg.ParseMode = tgfmt.ParseMode
+
+	sent, err := bot.Send(msg)
+	if err == nil || !isParseError(err) {
+		return sent, err
+	}
+
+	log.Warn("Telegram rejected MarkdownV2, retrying as plain text",
+		"error", err,
+		"chat_id", msg.ChatID)
+
+	msg.ParseMode = ""
+	msg.Text = stripMarkdownV2(msg.Text)
+	return bot.Send(msg)
+}
+
+// isParseError reports whether err is Telegram's "can't parse entities" API error
+// Example: "Bad Request: can't parse entities: Character '.' is reserved and must be escaped"
+func isParseError(err error) bool {
+	return isAPIError(err, "can't parse entities")
+}
+
+// isAPIError reports whether err is a Telegram API error whose description contains text
+// Telegram has no stable error codes for most Bad Request cases, only descriptions.
+func isAPIError(err error, text string) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return strings.Contains(apiErr.Message, text)
+}
+
+// stripMarkdownV2 converts MarkdownV2 text to plain text
+//
+// Rules:
+//   - \x (escaped character) becomes x
+//   - Unescaped entity markers * _ ~ | are removed
+//   - `code` and ```pre``` lose their fences, contents are kept literally
+//   - [text](url) becomes "text (url)"
+//
+// Parameters:
+//   - s: MarkdownV2 text
+//
+// Returns:
+//   - string: Text without markup, suitable for sending with no parse mode
+func stripMarkdownV2(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+
+	inLinkURL := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s):
+			// Escaped character - keep it, drop the backslash
+			i++
+			builder.WriteByte(s[i])
+
+		case c == '`':
+			// Code or pre block - copy contents literally up to the closing fence
+			fence := "`"
+			if strings.HasPrefix(s[i:], "```") {
+				fence = "```"
+			}
+			i += len(fence)
+			for i < len(s) && !strings.HasPrefix(s[i:], fence) {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				builder.WriteByte(s[i])
+				i++
+			}
+			i += len(fence) - 1 // Loop increment skips the last fence character
+
+		case c == '*' || c == '_' || c == '~' || c == '|':
+			// Entity marker - drop it
+
+		case c == '[':
+			// Link text start - drop the bracket
+
+		case c == ']' && i+1 < len(s) && s[i+1] == '(':
+			// Link text end - url follows in parentheses
+			builder.WriteString(" (")
+			i++
+			inLinkURL = true
+
+		case c == ')' && inLinkURL:
+			builder.WriteByte(')')
+			inLinkURL = false
+
+		default:
+			builder.WriteByte(c)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestStripMarkdownV2 tests conversion of MarkdownV2 text to plain text
+func TestStripMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain text", input: "Hello World", expected: "Hello World"},
+		{name: "escaped characters", input: "12\\.99 \\- \\(EUR\\)\\!", expected: "12.99 - (EUR)!"},
+		{name: "bold and italic", input: "*Bold* and _italic_", expected: "Bold and italic"},
+		{name: "underline strike spoiler", input: "__u__ ~s~ ||sp||", expected: "u s sp"},
+		{name: "escaped markers kept", input: "a\\*b\\_c", expected: "a*b_c"},
+		{name: "inline code kept literally", input: "run `go_test ./...`", expected: "run go_test ./..."},
+		{name: "pre block", input: "```\nx := a*b\n```", expected: "\nx := a*b\n"},
+		{name: "link", input: "[docs](https://example.com)", expected: "docs (https://example.com)"},
+		{name: "real helper output", input: tgfmt.Bold("12.99 EUR/mo") + " \\- " + tgfmt.Italic("FQN: a.b"), expected: "12.99 EUR/mo - FQN: a.b"},
+		{name: "empty", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := stripMarkdownV2(tt.input); result != tt.expected {
+				t.Errorf("stripMarkdownV2(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// parseErrorSender fails the first Send with Telegram's parse error, then succeeds
+type parseErrorSender struct {
+	recordingSender
+	failures int // Number of Send calls to fail
+	err      error
+}
+
+func (s *parseErrorSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, _ := s.recordingSender.Send(c)
+	if len(s.sent) <= s.failures {
+		return tgbotapi.Message{}, s.err
+	}
+	return msg, nil
+}
+
+// TestSendFormatted tests the MarkdownV2 → plain text fallback flow
+//
+// Cases:
+//   - Success on first try: one send, MarkdownV2
+//   - Parse error: second send without parse mode and with stripped text
+//   - Other error (e.g., network): no retry, error returned
+func TestSendFormatted(t *testing.T) {
+	parseErr := &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities: Character '.' is reserved"}
+	otherErr := errors.New("network unreachable")
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantSends int
+		wantErr   bool
+	}{
+		{name: "success", failures: 0, err: nil, wantSends: 1},
+		{name: "parse error falls back to plain text", failures: 1, err: parseErr, wantSends: 2},
+		{name: "other error is not retried", failures: 1, err: otherErr, wantSends: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &parseErrorSender{failures: tt.failures, err: tt.err}
+
+			msg := tgbotapi.NewMessage(1, "*Price:* 12\\.99")
+			msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+
+			_, err := sendFormatted(context.Background(), sender, msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendFormatted(context.Background(), ) error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			messages := sender.messages()
+			if len(messages) != tt.wantSends {
+				t.Fatalf("sendFormatted(context.Background(), ) made %d sends, want %d", len(messages), tt.wantSends)
+			}
+			if messages[0].ParseMode != tgfmt.ParseMode {
+				t.Errorf("first send ParseMode = %q, want %q", messages[0].ParseMode, tgfmt.ParseMode)
+			}
+
+			if tt.wantSends == 2 {
+				retry := messages[1]
+				if retry.ParseMode != "" {
+					t.Errorf("retry ParseMode = %q, want empty", retry.ParseMode)
+				}
+				if retry.Text != "Price: 12.99" {
+					t.Errorf("retry Text = %q, want %q", retry.Text, "Price: 12.99")
+				}
+				if retry.ReplyMarkup == nil {
+					t.Errorf("retry lost ReplyMarkup")
+				}
+			}
+		})
+	}
+}
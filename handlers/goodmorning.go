@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// goodMorningChats stores which chats want the daily good-morning message.
+// Changed by /goodmorning on|off, read by the scheduler in main.go.
+//
+// Kept in memory: subscriptions are lost on restart.
+var goodMorningChats = struct {
+	mu    sync.RWMutex
+	chats map[int64]bool
+}{chats: make(map[int64]bool)}
+
+// setGoodMorning enables or disables the daily message for a chat
+func setGoodMorning(chatID int64, enabled bool) {
+	goodMorningChats.mu.Lock()
+	defer goodMorningChats.mu.Unlock()
+
+	if enabled {
+		goodMorningChats.chats[chatID] = true
+	} else {
+		delete(goodMorningChats.chats, chatID)
+	}
+}
+
+// goodMorningSubscribers returns all subscribed chat IDs (sorted, for stable order)
+func goodMorningSubscribers() []int64 {
+	goodMorningChats.mu.RLock()
+	defer goodMorningChats.mu.RUnlock()
+
+	chats := make([]int64, 0, len(goodMorningChats.chats))
+	for chatID := range goodMorningChats.chats {
+		chats = append(chats, chatID)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+	return chats
+}
+
+// HandleGoodMorning handles the /goodmorning command.
+//
+// Usage:
+//   - /goodmorning on: send a daily message to this chat at MORNING_HOUR (UTC)
+//   - /goodmorning off: stop the daily message
+//   - anything else: show usage and current state
+//
+// Authorization:
+//   - Only users in ALLOWED_USERS, since the message includes OVH offers
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (authorization, MORNING_HOUR)
+func HandleGoodMorning(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	var text string
+	lang := userLanguage(ctx, message.From)
+	switch strings.ToLower(strings.TrimSpace(message.CommandArguments())) {
+	case "on":
+		setGoodMorning(message.Chat.ID, true)
+		text = translate(lang, "good_morning_on", cfg.MorningHour)
+		log.Info("Good morning enabled")
+	case "off":
+		setGoodMorning(message.Chat.ID, false)
+		text = translate(lang, "good_morning_off")
+		log.Info("Good morning disabled")
+	default:
+		text = translate(lang, "good_morning_usage", cfg.MorningHour)
+	}
+
+	msg := replyTo(message, tgfmt.EscapeMarkdownV2(text))
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /goodmorning reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// IsGoodMorningTime reports whether now is the minute the daily message is due
+// (MORNING_HOUR:00 UTC). The scheduler checks once per minute.
+//
+// Parameters:
+//   - now: Current time (any time zone, compared in UTC)
+//   - hour: Target hour in UTC (cfg.MorningHour)
+//
+// Returns:
+//   - bool: true during the target minute
+func IsGoodMorningTime(now time.Time, hour int) bool {
+	now = now.UTC()
+	return now.Hour() == hour && now.Minute() == 0
+}
+
+// SendGoodMorning sends the daily message to every subscribed chat.
+// Called by the scheduler goroutine in main.go.
+//
+// Message: "☀️ Good morning! Today's cheapest OVH server: <best offer>"
+//   - The offer comes from the cached OVH data (one offer, WithTop(1))
+//   - If OVH is unavailable, the greeting is sent without the offer
+//   - Chats that blocked the bot are skipped (see IsChatBlocked)
+//
+// Parameters:
+//   - ctx: Context for the OVH call (cancelled on shutdown)
+//   - bot: Bot sender for sending messages
+//
+// Returns:
+//   - int: Number of chats the message was delivered to
+func SendGoodMorning(ctx context.Context, bot BotSender) int {
+	log := logger.FromContext(ctx)
+
+	chats := goodMorningSubscribers()
+	if len(chats) == 0 {
+		return 0
+	}
+
+	// Step 1: Fetch the best offer once for all chats
+	offers, err := getTopOffers(ctx,
+		ovh.WithSubsidiary(ovhSubsidiary),
+		ovh.WithDatacenter(ovhDatacenter),
+		ovh.WithTop(1),
+	)
+	if err != nil {
+		log.Error("Failed to fetch OVH offer for good morning message",
+			"error", err)
+	}
+
+	// Step 2: Send to every subscribed chat that can still receive messages,
+	// in its language (see chatLanguage)
+	delivered := 0
+	for _, chatID := range chats {
+		if IsChatBlocked(chatID) {
+			continue
+		}
+
+		text := formatGoodMorning(chatLanguage(ctx, chatID), offers)
+
+		// Plain text: offer names come from OVH and may contain any character
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := bot.Send(msg); err != nil {
+			log.Error("Failed to send good morning message",
+				"error", err,
+				"message_type", messageType(msg),
+				"chat_id", chatID)
+			continue
+		}
+		delivered++
+	}
+
+	log.Info("Good morning messages sent",
+		"subscribers", len(chats),
+		"delivered", delivered)
+	return delivered
+}
+
+// formatGoodMorning builds the plain-text daily message
+//
+// Parameters:
+//   - lang: Language of the message
+//   - offers: The cheapest offer first (none if OVH was unavailable)
+//
+// Returns:
+//   - string: The greeting, followed by the cheapest offer when there is one
+func formatGoodMorning(lang string, offers []ovh.Offer) string {
+	text := translate(lang, "good_morning")
+	if len(offers) > 0 {
+		best := offers[0]
+		text += " " + translate(lang, "good_morning_offer",
+			best.InvoiceName, translate(lang, "ovh_detail_monthly", strconv.FormatFloat(best.Price, 'f', 2, 64), best.Currency),
+			ovh.DatacenterName(best.Datacenter))
+	}
+	return text
+}
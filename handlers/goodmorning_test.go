@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestHandleGoodMorning tests /goodmorning on|off and the usage reply
+//
+// Cases:
+//   - "on" subscribes the chat, "off" unsubscribes it
+//   - Unknown or missing argument shows usage and changes nothing
+//   - Unauthorized users are rejected and not subscribed
+func TestHandleGoodMorning(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		userID     int64
+		wantText   string
+		wantActive bool
+	}{
+		{"on", "/goodmorning on", 12345, "enabled", true},
+		{"on uppercase", "/goodmorning ON", 12345, "enabled", true},
+		{"off", "/goodmorning off", 12345, "disabled", false},
+		{"no argument", "/goodmorning", 12345, "Usage", false},
+		{"unknown argument", "/goodmorning maybe", 12345, "Usage", false},
+		{"unauthorized", "/goodmorning on", 99999, "authorized users", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := createTestMessage(tt.text, tt.userID)
+			setGoodMorning(message.Chat.ID, false)
+			defer setGoodMorning(message.Chat.ID, false)
+
+			sender := &recordingSender{}
+			HandleGoodMorning(context.Background(), sender, message, testConfig())
+
+			messages := sender.messages()
+			if len(messages) != 1 || !strings.Contains(messages[0].Text, tt.wantText) {
+				t.Fatalf("sent %+v, want one message containing %q", messages, tt.wantText)
+			}
+
+			active := len(goodMorningSubscribers()) == 1
+			if active != tt.wantActive {
+				t.Errorf("subscribed = %v, want %v", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+// TestIsGoodMorningTime tests the scheduler's minute check (always in UTC)
+func TestIsGoodMorningTime(t *testing.T) {
+	paris := time.FixedZone("CET", 3600)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{"target minute", time.Date(2025, 1, 1, 8, 0, 30, 0, time.UTC), true},
+		{"one minute later", time.Date(2025, 1, 1, 8, 1, 0, 0, time.UTC), false},
+		{"other hour", time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC), false},
+		{"other zone converted to UTC", time.Date(2025, 1, 1, 9, 0, 0, 0, paris), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsGoodMorningTime(tt.now, 8); got != tt.want {
+				t.Errorf("IsGoodMorningTime(%v, 8) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSendGoodMorning tests the daily message delivery
+//
+// Cases:
+//   - Subscribed chats get the greeting with the best offer, blocked chats are skipped
+//   - OVH errors still send the greeting, without the offer
+//   - No subscribers: OVH is not called at all
+func TestSendGoodMorning(t *testing.T) {
+	subscribe := func(t *testing.T, chats ...int64) {
+		t.Helper()
+		for _, chatID := range chats {
+			setGoodMorning(chatID, true)
+			t.Cleanup(func() { setGoodMorning(chatID, false) })
+		}
+	}
+	stub := func(t *testing.T, offers []ovh.Offer, err error) *int {
+		t.Helper()
+		calls := 0
+		oldGetTopOffers := getTopOffers
+		getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+			calls++
+			return offers, err
+		}
+		t.Cleanup(func() { getTopOffers = oldGetTopOffers })
+		return &calls
+	}
+
+	t.Run("sends best offer and skips blocked chats", func(t *testing.T) {
+		stub(t, []ovh.Offer{{InvoiceName: "KS-1", Price: 5.99, Currency: "EUR", Datacenter: "lon"}}, nil)
+		subscribe(t, 1, 2)
+		setChatBlocked(2, true)
+		defer setChatBlocked(2, false)
+
+		sender := &recordingSender{}
+		if got := SendGoodMorning(context.Background(), sender); got != 1 {
+			t.Errorf("SendGoodMorning() = %d, want 1 delivered", got)
+		}
+
+		messages := sender.messages()
+		if len(messages) != 1 || messages[0].ChatID != 1 {
+			t.Fatalf("sent %+v, want one message to chat 1", messages)
+		}
+		for _, want := range []string{"☀️ Good morning!", "KS-1", "5.99 EUR"} {
+			if !strings.Contains(messages[0].Text, want) {
+				t.Errorf("message %q does not contain %q", messages[0].Text, want)
+			}
+		}
+	})
+
+	t.Run("OVH error sends greeting only", func(t *testing.T) {
+		stub(t, nil, errors.New("ovh down"))
+		subscribe(t, 1)
+
+		sender := &recordingSender{}
+		SendGoodMorning(context.Background(), sender)
+
+		messages := sender.messages()
+		if len(messages) != 1 || messages[0].Text != "☀️ Good morning!" {
+			t.Errorf("sent %+v, want plain greeting", messages)
+		}
+	})
+
+	t.Run("no subscribers", func(t *testing.T) {
+		calls := stub(t, nil, nil)
+
+		sender := &recordingSender{}
+		if got := SendGoodMorning(context.Background(), sender); got != 0 {
+			t.Errorf("SendGoodMorning() = %d, want 0", got)
+		}
+		if *calls != 0 {
+			t.Errorf("getTopOffers called %d times, want 0 without subscribers", *calls)
+		}
+	})
+}
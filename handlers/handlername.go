@@ -0,0 +1,56 @@
+package handlers
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+// HandlerName names the handler an update is routed to, for logs and metrics
+// (e.g., middleware.TimeoutMiddleware's update_timeouts_total{handler=...}).
+//
+// Names mirror RouteUpdate's routes:
+//   - Registered commands: "/ovh", "/help", ... (bot mentions are dropped)
+//   - Other commands: "unknown_command"
+//   - Feature buttons: "button:ovh", "button:dice", ...
+//   - Other text (admin buttons, chat text): "message"
+//   - Other updates: "inline_query", "callback_query", "my_chat_member", "unknown"
+//
+// Names are a small fixed set on purpose: user text never becomes a
+// metric label, so the number of series stays bounded.
+//
+// Parameters:
+//   - update: Update from Telegram
+//
+// Returns:
+//   - string: Handler name
+func HandlerName(update tgbotapi.Update) string {
+	message := update.Message
+	if message == nil {
+		message = update.EditedMessage
+	}
+
+	switch {
+	case message != nil:
+		return messageHandlerName(message)
+	case update.MyChatMember != nil:
+		return "my_chat_member"
+	case update.InlineQuery != nil:
+		return "inline_query"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	default:
+		return "unknown"
+	}
+}
+
+// messageHandlerName names the handler for a message or edited message (see HandlerName)
+func messageHandlerName(message *tgbotapi.Message) string {
+	if message.IsCommand() {
+		if cmd, ok := findCommand(message.Command()); ok {
+			return "/" + cmd.Name
+		}
+		return "unknown_command"
+	}
+
+	if feature, ok := buttonFeatures[message.Text]; ok {
+		return "button:" + feature
+	}
+	return "message"
+}
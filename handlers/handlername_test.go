@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestHandlerName tests handler names for each kind of update
+func TestHandlerName(t *testing.T) {
+	tests := []struct {
+		name   string
+		update tgbotapi.Update
+		want   string
+	}{
+		{name: "registered command", update: tgbotapi.Update{Message: createTestMessage("/ovh", 12345)}, want: "/ovh"},
+		{name: "command with mention", update: tgbotapi.Update{Message: createTestMessage("/help@run_tbot", 12345)}, want: "/help"},
+		{name: "unknown command", update: tgbotapi.Update{Message: createTestMessage("/nope", 12345)}, want: "unknown_command"},
+		{name: "edited command", update: tgbotapi.Update{EditedMessage: createTestMessage("/start", 12345)}, want: "/start"},
+		{name: "feature button", update: tgbotapi.Update{Message: createTestMessage("🖥️ OVH Servers", 12345)}, want: "button:ovh"},
+		{name: "plain text", update: tgbotapi.Update{Message: createTestMessage("hello", 12345)}, want: "message"},
+		{name: "inline query", update: tgbotapi.Update{InlineQuery: &tgbotapi.InlineQuery{ID: "1"}}, want: "inline_query"},
+		{name: "callback query", update: tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{ID: "1"}}, want: "callback_query"},
+		{name: "my chat member", update: tgbotapi.Update{MyChatMember: &tgbotapi.ChatMemberUpdated{}}, want: "my_chat_member"},
+		{name: "empty update", update: tgbotapi.Update{}, want: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HandlerName(tt.update); got != tt.want {
+				t.Errorf("HandlerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
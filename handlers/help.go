@@ -1,9 +1,12 @@
 package handlers
 
 import (
-	"log/slog"
+	"context"
+	"strings"
 
 	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -13,7 +16,7 @@ import (
 // Authorization logic:
 //   - All users see public commands (/start, /help, dice button)
 //   - Only users in ALLOWED_USERS see private commands section
-//   - Authorization is checked via cfg.IsUserAllowed(userID)
+//   - Authorization is checked via cfg.IsUserAllowed(userID, username)
 //
 // Security note:
 //   - We don't reveal that private commands exist to unauthorized users
@@ -21,92 +24,114 @@ import (
 //   - Authorized users see "🔐 Private Commands" section
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - botAPI: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing the /help command
 //   - cfg: Application configuration (contains AllowedUsers list)
-func HandleHelp(botAPI *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.Config) {
+func HandleHelp(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
 	// Check if user is authorized to see private commands
 	// message.From.ID is the Telegram user ID
 	// This is a unique int64 number assigned by Telegram
-	isAuthorized := cfg.IsUserAllowed(message.From.ID)
+	isAuthorized := cfg.IsUserAllowed(message.From.ID, message.From.UserName)
 
 	// Log the help command with authorization status
 	// This helps track who is using the bot and whether they have access
-	slog.Info("/help command received",
-		"user_id", message.From.ID,
+	log.Info("/help command received",
 		"username", message.From.UserName,
-		"chat_id", message.Chat.ID,
 		"is_authorized", isAuthorized)
 
 	// Step 1: Create help message text
 	// Different content for authorized vs unauthorized users
-	helpText := formatHelpMessage(isAuthorized)
+	helpText := formatHelpMessage(userLanguage(ctx, message.From), isAuthorized)
 
 	// Step 2: Create and send message
-	msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
-
-	// ParseMode enables Markdown formatting in message text
-	// This allows us to use **bold**, *italic*, `code`, etc.
-	// Available modes: "Markdown" (legacy), "MarkdownV2" (recommended), "HTML"
-	// We use MarkdownV2 for better control and escaping
-	msg.ParseMode = "MarkdownV2"
+	msg := replyTo(message, helpText)
 
 	// Step 3: Send the message
-	if _, err := botAPI.Send(msg); err != nil {
+	// sendFormatted sets ParseMode to MarkdownV2 (see format.go)
+	// This allows us to use *bold*, _italic_, `code`, etc. (see tgfmt)
+	// Available modes: "Markdown" (legacy), "MarkdownV2" (recommended), "HTML"
+	// If Telegram can't parse the markup, the text is resent without formatting
+	if _, err := sendFormattedReply(ctx, botAPI, message, msg); err != nil {
 		// If sending fails, log the error
-		slog.Error("Failed to send /help message",
+		log.Error("Failed to send /help message",
 			"error", err,
-			"chat_id", message.Chat.ID,
-			"user_id", message.From.ID,
+			"message_type", messageType(msg),
 			"is_authorized", isAuthorized)
 		return
 	}
 
 	// Log successful send
-	slog.Info("/help message sent successfully",
-		"chat_id", message.Chat.ID,
-		"user_id", message.From.ID,
+	log.Info("/help message sent successfully",
 		"is_authorized", isAuthorized)
 }
 
 // formatHelpMessage creates the help message text with command list.
 // Returns different content based on user authorization status.
 //
-// MarkdownV2 formatting rules:
-//   - *text* = italic
-//   - **text** = bold
-//   - `text` = monospace (code)
-//   - Special characters must be escaped: _ * [ ] ( ) ~ ` > # + - = | { } . !
-//   - Use \\ for literal backslash
+// MarkdownV2 formatting is built with the tgfmt package:
+//   - tgfmt.Bold / tgfmt.Italic wrap and escape headers and footer
+//   - tgfmt.EscapeMarkdownV2 escapes plain lines (characters like . - ( ) are reserved)
+//   - Lines below are written as plain text, escaping happens in one place
 //
 // Parameters:
+//   - lang: Language of the text (see userLanguage)
 //   - isAuthorized: true if user is in AllowedUsers list
 //
 // Returns:
 //   - string: Formatted help message with MarkdownV2 markup
-func formatHelpMessage(isAuthorized bool) string {
+func formatHelpMessage(lang string, isAuthorized bool) string {
 	// Base message with public commands
-	// Using MarkdownV2 for formatting
-	// Note: Special characters like . - need escaping with \
-	message := "*📖 Available Commands*\n\n" +
-		"*Public Commands:*\n" +
-		"/start \\- Start the bot and see welcome message\n" +
-		"/help \\- Show this help message\n\n" +
-		"*Button Features:*\n" +
-		"🎲 Dice \\- Roll a single die \\(1\\-6\\)\n" +
-		"🎲🎲 Double Dice \\- Roll two dice \\(2\\-12\\)\n" +
-		"🌀 Twister \\- Get a random Twister game move\n"
+	// Button labels stay as they are: they are the texts the keyboard sends
+	message := tgfmt.Bold(translate(lang, "help_title")) + "\n\n" +
+		tgfmt.Bold(translate(lang, "help_public_commands")) + "\n" +
+		tgfmt.EscapeMarkdownV2(formatCommandList(lang, false)+"\n") +
+		tgfmt.Bold(translate(lang, "help_button_features")) + "\n" +
+		tgfmt.EscapeMarkdownV2(translate(lang, "help_public_buttons")+"\n")
 
 	// Add private commands section only for authorized users
 	if isAuthorized {
-		message += "\n*🔐 Private Features:*\n" +
-			"🖥️ OVH Servers \\- Check OVH server availability in London\n"
+		message += "\n" + tgfmt.Bold(translate(lang, "help_private_features")) + "\n" +
+			tgfmt.EscapeMarkdownV2(formatCommandList(lang, true)+translate(lang, "help_private_buttons")+"\n")
 	}
 
 	// Add footer with project info
 	message += "\n" +
-		"_This is an educational bot built with Go\\._\n" +
-		"_Source code demonstrates best practices for Telegram bots\\._"
+		tgfmt.Italic(translate(lang, "help_footer_about")) + "\n" +
+		tgfmt.Italic(translate(lang, "help_footer_source"))
 
 	return message
 }
+
+// commandDescription returns a command's description in a language
+// The i18n key is "command_<name>"; Command.Description is the English text,
+// used as is for Telegram's command menu (BotCommands).
+func commandDescription(lang string, cmd Command) string {
+	return translate(lang, "command_"+cmd.Name)
+}
+
+// formatCommandList lists public or private commands from RegisteredCommands
+// as plain text ("/name args - description" per line, not escaped).
+//
+// Parameters:
+//   - lang: Language of the descriptions (see commandDescription)
+//   - private: true for private commands, false for public ones
+//
+// Returns:
+//   - string: One line per command
+func formatCommandList(lang string, private bool) string {
+	var list strings.Builder
+	for _, cmd := range RegisteredCommands {
+		if cmd.IsPrivate != private {
+			continue
+		}
+		list.WriteString("/" + cmd.Name)
+		if cmd.Args != "" {
+			list.WriteString(" " + cmd.Args)
+		}
+		list.WriteString(" - " + commandDescription(lang, cmd) + "\n")
+	}
+	return list.String()
+}
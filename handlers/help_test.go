@@ -3,6 +3,8 @@ package handlers
 import (
 	"strings"
 	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
 )
 
 // TestFormatHelpMessage tests the formatHelpMessage function with different authorization states.
@@ -41,9 +43,11 @@ func TestFormatHelpMessage(t *testing.T) {
 				"educational bot",    // Footer
 			},
 			expectedNotContains: []string{
-				"Private Features", // Should not see private section
-				"🔐",                // Lock emoji (private section marker)
-				"OVH Servers",      // Private feature
+				"Private Features",    // Should not see private section
+				"🔐",                   // Lock emoji (private section marker)
+				"OVH Servers",         // Private feature
+				"/ovh",                // Private commands (/ovh, /ovhcsv, /ovhjson)
+				"/compare\\_catalogs", // Underscore is escaped in MarkdownV2
 			},
 		},
 		{
@@ -61,7 +65,11 @@ func TestFormatHelpMessage(t *testing.T) {
 				"Private Features",   // Private section (KEY DIFFERENCE)
 				"🔐",                  // Lock emoji
 				"🖥️ OVH Servers",     // Private feature
-				"educational bot",    // Footer
+				"Same as /ovh",       // OVH button points to the command
+				"/ovhcsv",            // OVH export commands
+				"/ovhjson",
+				"/compare\\_catalogs", // Underscore is escaped in MarkdownV2
+				"educational bot",     // Footer
 			},
 			expectedNotContains: []string{
 				// Nothing should be hidden from authorized users
@@ -73,7 +81,7 @@ func TestFormatHelpMessage(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call the function being tested
-			result := formatHelpMessage(tt.isAuthorized)
+			result := formatHelpMessage("en", tt.isAuthorized)
 
 			// Verify result is not empty
 			if result == "" {
@@ -107,21 +115,16 @@ func TestFormatHelpMessage(t *testing.T) {
 	}
 }
 
-// Example of additional tests you could add:
-
-// TestFormatHelpMessageMarkdownV2Validity could verify that:
-//   - All special characters are properly escaped
-//   - Message can be parsed by Telegram's MarkdownV2 parser
-//   - No unescaped characters that would break formatting
-//
-// This would require either:
-//   1. A MarkdownV2 parser/validator library
-//   2. Integration test with real Telegram API
-//   3. Manual regex validation of escape sequences
-//
-// For an educational project, we rely on:
-//   - Manual testing with real bot
-//   - Telegram API will return error if markdown is invalid
+// TestFormatHelpMessageMarkdownV2Validity verifies that the help message
+// passes tgfmt.ValidateMarkdownV2 for both authorized and public users.
+// Catches unescaped characters before Telegram rejects the message at runtime.
+func TestFormatHelpMessageMarkdownV2Validity(t *testing.T) {
+	for _, isAuthorized := range []bool{false, true} {
+		if err := tgfmt.ValidateMarkdownV2(formatHelpMessage("en", isAuthorized)); err != nil {
+			t.Errorf("formatHelpMessage(%v) is not valid MarkdownV2: %v", isAuthorized, err)
+		}
+	}
+}
 
 // Note on security testing:
 // The key security test here is verifying that unauthorized users
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Inline mode settings
+const (
+	// inlineTop is the max number of offers returned as inline results
+	inlineTop = 5
+
+	// inlineCacheTime is how long (seconds) Telegram may reuse our answer for the same query
+	// OVH data is cached for 5 minutes anyway, so a short Telegram-side cache is safe
+	inlineCacheTime = 60
+)
+
+// HandleInlineQuery answers inline queries like "@run_tbot ovh lon".
+//
+// How inline mode works:
+//   - User types "@bot_username <query>" in ANY chat (the bot doesn't need to be a member)
+//   - Telegram sends us an InlineQuery update with the query text
+//   - We answer with a list of results (answerInlineQuery)
+//   - User picks one, and its message content is sent to the chat as the user
+//
+// Inline mode must be enabled in @BotFather (/setinline), and "inline_query"
+// must be in the webhook's allowed_updates.
+//
+// Supported queries:
+//   - "ovh" or "ovh <datacenter>": top offers (default datacenter: lon)
+//   - anything else (including empty): a single help article
+//
+// Authorization:
+//   - OVH results only for users in ALLOWED_USERS (checked via query.From.ID)
+//   - Answers are marked personal, so Telegram never shows one user's results to another
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for answering the query
+//   - query: Inline query from Telegram
+//   - cfg: Application configuration (needed for authorization check)
+func HandleInlineQuery(ctx context.Context, bot BotSender, query *tgbotapi.InlineQuery, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	results := buildInlineResults(ctx, query, cfg)
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheTime,
+		IsPersonal:    true,
+	}
+
+	// answerInlineQuery returns True, not a Message, so Request is used instead of Send
+	if _, err := bot.Request(answer); err != nil {
+		log.Error("Failed to answer inline query",
+			"error", err,
+			"message_type", messageType(answer),
+			"query", query.Query)
+		return
+	}
+
+	log.Info("Inline query answered",
+		"query", query.Query,
+		"results", len(results))
+}
+
+// buildInlineResults turns an inline query into a list of result articles.
+// Separated from HandleInlineQuery so result construction can be tested directly.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - query: Inline query from Telegram
+//   - cfg: Application configuration (needed for authorization check)
+//
+// Returns:
+//   - []interface{}: InlineQueryResultArticle values (InlineConfig.Results type)
+func buildInlineResults(ctx context.Context, query *tgbotapi.InlineQuery, cfg *config.Config) []interface{} {
+	log := logger.FromContext(ctx)
+	lang := userLanguage(ctx, query.From)
+
+	// Step 1: Parse query: "ovh [datacenter]"
+	fields := strings.Fields(strings.ToLower(query.Query))
+	if len(fields) == 0 || fields[0] != "ovh" || len(fields) > 2 {
+		return []interface{}{inlineHelpArticle(lang)}
+	}
+
+	if !cfg.Features.OVH {
+		return []interface{}{inlineTextArticle("disabled",
+			translate(lang, "inline_disabled_title"),
+			translate(lang, "inline_disabled"))}
+	}
+
+	datacenter := ovhDatacenter
+	if len(fields) == 2 {
+		datacenter = fields[1]
+	}
+	if !isKnownDatacenter(datacenter) {
+		return []interface{}{inlineTextArticle("unknown-dc",
+			translate(lang, "inline_unknown_dc_title", datacenter),
+			translate(lang, "inline_unknown_dc", knownDatacenterCodes()))}
+	}
+
+	// Step 2: Check authorization (recorded in the audit log, see /audit)
+	allowed := query.From != nil && cfg.IsUserAllowed(query.From.ID, query.From.UserName)
+	recordAudit(ctx, query.From, "inline_query", query.Query, allowed)
+	if !allowed {
+		log.Warn("Unauthorized inline OVH query",
+			"query", query.Query)
+		return []interface{}{inlineTextArticle("unauthorized",
+			translate(lang, "inline_unauthorized_title"),
+			translate(lang, "inline_unauthorized"))}
+	}
+
+	// Step 3: Fetch offers (served from the ovh package cache when fresh)
+	offers, err := getTopOffers(ctx,
+		ovh.WithSubsidiary(ovhSubsidiary),
+		ovh.WithDatacenter(datacenter),
+		ovh.WithTop(inlineTop),
+	)
+	if err != nil {
+		log.Error("Failed to fetch OVH offers for inline query",
+			"error", err,
+			"datacenter", datacenter)
+		return []interface{}{inlineTextArticle("error",
+			translate(lang, "inline_error_title"),
+			translate(lang, "inline_error"))}
+	}
+
+	if len(offers) == 0 {
+		return []interface{}{inlineTextArticle("empty",
+			translate(lang, "inline_empty_title"),
+			translate(lang, "ovh_no_servers", ovh.DatacenterName(datacenter)))}
+	}
+
+	// Step 4: One article per offer
+	// Title/description are shown in the result list, message content is what gets sent
+	results := make([]interface{}, 0, len(offers))
+	for i, offer := range offers {
+		article := tgbotapi.NewInlineQueryResultArticleMarkdownV2(
+			fmt.Sprintf("ovh-%s-%d", datacenter, i+1),
+			translate(lang, "ovh_detail_monthly", strconv.FormatFloat(offer.Price, 'f', 2, 64), offer.Currency)+" - "+offer.InvoiceName,
+			ovh.FormatOfferForTelegram(offer, i+1),
+		)
+		article.Description = offer.FQN + " · " + ovh.DatacenterName(offer.Datacenter)
+		results = append(results, article)
+	}
+
+	return results
+}
+
+// inlineHelpArticle explains the supported inline query syntax (in lang)
+func inlineHelpArticle(lang string) tgbotapi.InlineQueryResultArticle {
+	article := tgbotapi.NewInlineQueryResultArticleMarkdownV2("help",
+		translate(lang, "inline_help_title"),
+		tgfmt.Bold(translate(lang, "inline_help_heading"))+"\n"+
+			tgfmt.EscapeMarkdownV2(translate(lang, "inline_help_intro")+"\n")+
+			tgfmt.Code("ovh")+tgfmt.EscapeMarkdownV2(" - "+translate(lang, "inline_help_default")+"\n")+
+			tgfmt.Code("ovh rbx")+tgfmt.EscapeMarkdownV2(" - "+translate(lang, "inline_help_other")))
+	article.Description = translate(lang, "inline_help_try")
+	return article
+}
+
+// inlineTextArticle creates a plain text article (no parse mode, no escaping needed)
+func inlineTextArticle(id, title, text string) tgbotapi.InlineQueryResultArticle {
+	article := tgbotapi.NewInlineQueryResultArticle(id, title, text)
+	article.Description = text
+	return article
+}
+
+// isKnownDatacenter reports whether code is in the ovh datacenter list
+func isKnownDatacenter(code string) bool {
+	for _, dc := range ovh.ListDatacenters() {
+		if dc.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// knownDatacenterCodes returns all datacenter codes as "gra, lon, rbx, ..."
+func knownDatacenterCodes() string {
+	datacenters := ovh.ListDatacenters()
+	codes := make([]string, 0, len(datacenters))
+	for _, dc := range datacenters {
+		codes = append(codes, dc.Code)
+	}
+	return strings.Join(codes, ", ")
+}
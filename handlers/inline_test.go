@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// stubInlineOffers replaces getTopOffers for the duration of a test
+// and records the datacenter it was called with.
+func stubInlineOffers(t *testing.T, offers []ovh.Offer, err error) *string {
+	t.Helper()
+
+	var calledWith string
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		var options ovh.Options
+		for _, opt := range opts {
+			opt(&options)
+		}
+		calledWith = options.Datacenter
+		return offers, err
+	}
+	t.Cleanup(func() { getTopOffers = oldGetTopOffers })
+
+	return &calledWith
+}
+
+// TestBuildInlineResults tests inline result construction
+//
+// Cases:
+//   - Empty and unknown queries return the help article
+//   - "ovh" uses the default datacenter, "ovh rbx" a custom one
+//   - Unknown datacenters are reported instead of calling OVH
+//   - Unauthorized users get a single rejection article and OVH is never called
+//   - OVH errors become an error article
+func TestBuildInlineResults(t *testing.T) {
+	offers := []ovh.Offer{
+		{FQN: "a.fqn", Price: 9.99, Currency: "EUR", InvoiceName: "KS-1", Datacenter: "lon"},
+		{FQN: "b.fqn", Price: 19.99, Currency: "EUR", InvoiceName: "KS-2", Datacenter: "lon"},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		userID         int64
+		fetchErr       error
+		wantIDs        []string
+		wantDatacenter string // "" = OVH must not be called
+	}{
+		{name: "empty query shows help", query: "", userID: 12345, wantIDs: []string{"help"}},
+		{name: "unknown query shows help", query: "weather", userID: 12345, wantIDs: []string{"help"}},
+		{name: "default datacenter", query: "ovh", userID: 12345, wantIDs: []string{"ovh-lon-1", "ovh-lon-2"}, wantDatacenter: "lon"},
+		{name: "custom datacenter", query: " OVH rbx ", userID: 12345, wantIDs: []string{"ovh-rbx-1", "ovh-rbx-2"}, wantDatacenter: "rbx"},
+		{name: "unknown datacenter", query: "ovh xyz", userID: 12345, wantIDs: []string{"unknown-dc"}},
+		{name: "unauthorized user", query: "ovh lon", userID: 99999, wantIDs: []string{"unauthorized"}},
+		{name: "fetch error", query: "ovh", userID: 12345, fetchErr: errors.New("boom"), wantIDs: []string{"error"}, wantDatacenter: "lon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calledWith := stubInlineOffers(t, offers, tt.fetchErr)
+
+			query := &tgbotapi.InlineQuery{ID: "q1", From: &tgbotapi.User{ID: tt.userID}, Query: tt.query}
+			results := buildInlineResults(context.Background(), query, testConfig())
+
+			if len(results) != len(tt.wantIDs) {
+				t.Fatalf("got %d results, want %d: %+v", len(results), len(tt.wantIDs), results)
+			}
+			for i, result := range results {
+				article, ok := result.(tgbotapi.InlineQueryResultArticle)
+				if !ok {
+					t.Fatalf("result %d is %T, want InlineQueryResultArticle", i, result)
+				}
+				if article.ID != tt.wantIDs[i] {
+					t.Errorf("result %d ID = %q, want %q", i, article.ID, tt.wantIDs[i])
+				}
+
+				// MarkdownV2 content must be valid, or Telegram rejects the whole answer
+				content := article.InputMessageContent.(tgbotapi.InputTextMessageContent)
+				if content.ParseMode == tgfmt.ParseMode {
+					if err := tgfmt.ValidateMarkdownV2(content.Text); err != nil {
+						t.Errorf("result %d has invalid MarkdownV2: %v\n%s", i, err, content.Text)
+					}
+				}
+			}
+
+			if *calledWith != tt.wantDatacenter {
+				t.Errorf("OVH called with datacenter %q, want %q", *calledWith, tt.wantDatacenter)
+			}
+		})
+	}
+}
+
+// TestRouteUpdate_InlineQuery tests that inline queries are answered via answerInlineQuery
+func TestRouteUpdate_InlineQuery(t *testing.T) {
+	stubInlineOffers(t, nil, nil)
+
+	sender := &recordingSender{}
+	update := tgbotapi.Update{
+		UpdateID:    1,
+		InlineQuery: &tgbotapi.InlineQuery{ID: "q1", From: &tgbotapi.User{ID: 99999}, Query: "ovh"},
+	}
+	RouteUpdate(context.Background(), sender, update, testConfig())
+
+	if len(sender.sent) != 0 {
+		t.Errorf("inline query should not send messages, sent %d", len(sender.sent))
+	}
+	if len(sender.requested) != 1 {
+		t.Fatalf("got %d requests, want 1 answerInlineQuery", len(sender.requested))
+	}
+
+	answer, ok := sender.requested[0].(tgbotapi.InlineConfig)
+	if !ok {
+		t.Fatalf("request is %T, want InlineConfig", sender.requested[0])
+	}
+	if answer.InlineQueryID != "q1" || !answer.IsPersonal {
+		t.Errorf("answer = %+v, want query q1 marked personal", answer)
+	}
+	if len(answer.Results) != 1 || answer.Results[0].(tgbotapi.InlineQueryResultArticle).ID != "unauthorized" {
+		t.Errorf("unauthorized user got results %+v, want single rejection", answer.Results)
+	}
+	if strings.Contains(answer.Results[0].(tgbotapi.InlineQueryResultArticle).Title, "EUR") {
+		t.Errorf("unauthorized user must not see offers")
+	}
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/ovh"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -37,6 +41,7 @@ func TestRouteUpdate_DoesNotPanic(t *testing.T) {
 	// Create test config with allowed users
 	cfg := &config.Config{
 		AllowedUsers: []int64{12345}, // Test user ID
+		Features:     config.AllFeatures(),
 	}
 
 	// Define test cases with different update types
@@ -116,7 +121,7 @@ func TestRouteUpdate_DoesNotPanic(t *testing.T) {
 			// We expect it to handle all cases gracefully
 			// Even with nil bot, routing logic should execute without panic
 			// (only message sending would fail, which we're not testing here)
-			RouteUpdate(bot, tt.update, cfg)
+			RouteUpdate(context.Background(), bot, tt.update, cfg)
 
 			// If we get here, no panic occurred (success!)
 		})
@@ -158,6 +163,7 @@ func TestRouteUpdate_Authorization(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &config.Config{
 				AllowedUsers: tt.allowedUsers,
+				Features:     config.AllFeatures(),
 			}
 
 			update := tgbotapi.Update{
@@ -172,7 +178,7 @@ func TestRouteUpdate_Authorization(t *testing.T) {
 				}
 			}()
 
-			RouteUpdate(bot, update, cfg)
+			RouteUpdate(context.Background(), bot, update, cfg)
 
 			// Note: We can't verify the actual message content without mocking
 			// But we've verified:
@@ -189,6 +195,7 @@ func TestRouteUpdate_ButtonMessages(t *testing.T) {
 	bot := createStubBot(t)
 	cfg := &config.Config{
 		AllowedUsers: []int64{12345}, // Authorized user for OVH test
+		Features:     config.AllFeatures(),
 	}
 
 	tests := []struct {
@@ -232,7 +239,7 @@ func TestRouteUpdate_ButtonMessages(t *testing.T) {
 				}
 			}()
 
-			RouteUpdate(bot, update, cfg)
+			RouteUpdate(context.Background(), bot, update, cfg)
 
 			// Note: We can't verify actual message content without mocking
 			// But we've verified:
@@ -278,6 +285,7 @@ func TestRouteUpdate_OVHAuthorization(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &config.Config{
 				AllowedUsers: tt.allowedUsers,
+				Features:     config.AllFeatures(),
 			}
 
 			update := tgbotapi.Update{
@@ -292,7 +300,7 @@ func TestRouteUpdate_OVHAuthorization(t *testing.T) {
 				}
 			}()
 
-			RouteUpdate(bot, update, cfg)
+			RouteUpdate(context.Background(), bot, update, cfg)
 
 			// Note: Without mocking, we can't verify the exact message content
 			// But we've verified:
@@ -304,6 +312,103 @@ func TestRouteUpdate_OVHAuthorization(t *testing.T) {
 	}
 }
 
+// TestRouteUpdate_EditedMessages tests routing of update.EditedMessage.
+//
+// Cases:
+//   - Edited /help within the window: routed, help message sent
+//   - Edited plain text / button label: ignored
+//   - Edited /help long after the original: ignored
+//   - HANDLE_EDITED_MESSAGES disabled: ignored
+func TestRouteUpdate_EditedMessages(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		text       string
+		sentAgo    time.Duration // Original message age at edit time
+		disabled   bool          // HandleEditedMessages = false
+		wantRouted bool
+	}{
+		{name: "recent edited command", text: "/help", sentAgo: 10 * time.Second, wantRouted: true},
+		{name: "edited plain message", text: "hello there", sentAgo: 10 * time.Second, wantRouted: false},
+		{name: "edited button label", text: "🎲 Dice", sentAgo: 10 * time.Second, wantRouted: false},
+		{name: "old edited command", text: "/help", sentAgo: 24 * time.Hour, wantRouted: false},
+		{name: "feature disabled", text: "/help", sentAgo: 10 * time.Second, disabled: true, wantRouted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.HandleEditedMessages = !tt.disabled
+
+			message := createTestMessage(tt.text, 12345)
+			message.Date = int(now.Add(-tt.sentAgo).Unix())
+			message.EditDate = int(now.Unix())
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, EditedMessage: message}, cfg)
+
+			routed := len(sender.sent) > 0
+			if routed != tt.wantRouted {
+				t.Errorf("edited %q (sent %v ago): routed = %v, want %v", tt.text, tt.sentAgo, routed, tt.wantRouted)
+			}
+		})
+	}
+}
+
+// TestRouteUpdate_GroupChats tests routing rules for group and supergroup chats.
+//
+// Cases:
+//   - Command without @mention: handled
+//   - Command with our @mention (any case): handled
+//   - Command for another bot: ignored
+//   - Unknown command: no "unknown command" reply in groups (but still in private chats)
+//   - Button text: ignored unless it's a reply to our message
+func TestRouteUpdate_GroupChats(t *testing.T) {
+	ourBot := &tgbotapi.User{ID: 1, IsBot: true, UserName: "run_tbot"}
+	otherBot := &tgbotapi.User{ID: 2, IsBot: true, UserName: "other_bot"}
+
+	tests := []struct {
+		name     string
+		chatType string
+		text     string
+		replyTo  *tgbotapi.User // Author of the replied-to message (nil = not a reply)
+		wantSent bool
+	}{
+		{name: "command without mention", chatType: "group", text: "/help", wantSent: true},
+		{name: "command with our mention", chatType: "supergroup", text: "/help@run_tbot", wantSent: true},
+		{name: "command with our mention different case", chatType: "group", text: "/help@Run_TBot", wantSent: true},
+		{name: "command for another bot", chatType: "group", text: "/help@other_bot", wantSent: false},
+		{name: "start for another bot", chatType: "supergroup", text: "/start@other_bot", wantSent: false},
+		{name: "unknown command in group", chatType: "group", text: "/weather", wantSent: false},
+		{name: "unknown command in private chat", chatType: "private", text: "/weather", wantSent: true},
+		{name: "button text not a reply", chatType: "group", text: "🎲 Dice", wantSent: false},
+		{name: "button text replying to us", chatType: "group", text: "🎲 Dice", replyTo: ourBot, wantSent: true},
+		{name: "button text replying to another bot", chatType: "group", text: "🎲 Dice", replyTo: otherBot, wantSent: false},
+		{name: "button text in private chat", chatType: "private", text: "🎲 Dice", wantSent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.BotUsername = "run_tbot"
+
+			message := createTestMessage(tt.text, 12345)
+			message.Chat = &tgbotapi.Chat{ID: -100123, Type: tt.chatType}
+			if tt.replyTo != nil {
+				message.ReplyToMessage = &tgbotapi.Message{MessageID: 7, From: tt.replyTo}
+			}
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: message}, cfg)
+
+			if sent := len(sender.sent) > 0; sent != tt.wantSent {
+				t.Errorf("%s %q: sent = %v, want %v", tt.chatType, tt.text, sent, tt.wantSent)
+			}
+		})
+	}
+}
+
 // createTestMessage creates a test Message for integration testing.
 // This is a helper function to reduce boilerplate in tests.
 //
@@ -332,6 +437,15 @@ func createTestMessage(text string, userID int64) *tgbotapi.Message {
 	}
 }
 
+// testConfig returns a minimal Config where user 12345 is authorized.
+// Used by tests that don't care about specific config values.
+func testConfig() *config.Config {
+	return &config.Config{
+		AllowedUsers: []int64{12345},
+		Features:     config.AllFeatures(),
+	}
+}
+
 // createEntitiesForText creates MessageEntity slice for command detection.
 // Telegram uses entities to mark special text types (commands, mentions, URLs, etc.)
 //
@@ -343,11 +457,17 @@ func createTestMessage(text string, userID int64) *tgbotapi.Message {
 func createEntitiesForText(text string) []tgbotapi.MessageEntity {
 	if len(text) > 0 && text[0] == '/' {
 		// Text is a command - create bot_command entity
+		// Like Telegram, the entity covers only the command itself,
+		// so "/start ovh" has command "start" and arguments "ovh"
+		length := len(text)
+		if i := strings.IndexByte(text, ' '); i > 0 {
+			length = i
+		}
 		return []tgbotapi.MessageEntity{
 			{
 				Type:   "bot_command",
 				Offset: 0,
-				Length: len(text),
+				Length: length,
 			},
 		}
 	}
@@ -402,7 +522,7 @@ func createTestCallback(data string, userID int64) *tgbotapi.CallbackQuery {
 //         Message: createTestMessage("/start", 12345),
 //     }
 //
-//     RouteUpdate(mock, update, cfg)
+//     RouteUpdate(context.Background(), mock, update, cfg)
 //
 //     // Verify message was sent
 //     if len(mock.sentMessages) != 1 {
@@ -460,3 +580,50 @@ func createStubBot(t *testing.T) *tgbotapi.BotAPI {
 	t.Logf("Created stub bot for testing (token: %s...)", fakeToken[:10])
 	return bot
 }
+
+// TestRouteUpdate_DisabledFeatures tests that disabled features short-circuit routing
+//
+// Cases:
+//   - Button of a disabled feature: nothing is sent
+//   - Command of a disabled feature: answered like an unknown command
+//   - Enabled features next to a disabled one still work
+func TestRouteUpdate_DisabledFeatures(t *testing.T) {
+	cfg := testConfig()
+	cfg.Features.Twister = false
+	cfg.Features.OVH = false
+
+	tests := []struct {
+		name      string
+		text      string
+		wantSends int
+		wantText  string
+	}{
+		{name: "disabled button", text: "🌀 Twister", wantSends: 0},
+		{name: "disabled OVH button", text: "🖥️ OVH Servers", wantSends: 0},
+		{name: "disabled command", text: "/ovhcsv", wantSends: 1, wantText: "Unknown command"},
+		{name: "enabled button", text: "🎲 Dice", wantSends: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Fail loudly if a disabled OVH handler tries to fetch
+			original := getTopOffers
+			getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+				t.Errorf("getTopOffers called for disabled feature")
+				return nil, nil
+			}
+			defer func() { getTopOffers = original }()
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage(tt.text, 12345)}, cfg)
+
+			messages := sender.messages()
+			if len(messages) != tt.wantSends {
+				t.Fatalf("RouteUpdate(%q) sent %d messages, want %d", tt.text, len(messages), tt.wantSends)
+			}
+			if tt.wantText != "" && !strings.Contains(messages[0].Text, tt.wantText) {
+				t.Errorf("RouteUpdate(%q) text = %q, want it to contain %q", tt.text, messages[0].Text, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/i18n"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// translate returns a message in a language (see i18n.Translator.T)
+// Handlers get lang from userLanguage.
+func translate(lang, key string, args ...any) string {
+	return i18n.Default().T(lang, key, args...)
+}
+
+// userError is an error whose message is shown to users, in their language
+// Parsers return it (newUserError) so handlers can translate the reason
+// (userErrorText); Error() is the English message, for logs and tests.
+type userError struct {
+	key  string // i18n key
+	args []any  // Values for the message's verbs
+}
+
+// newUserError creates an error with the message of an i18n key
+func newUserError(key string, args ...any) error {
+	return &userError{key: key, args: args}
+}
+
+// Error returns the English message
+func (e *userError) Error() string {
+	return translate(i18n.DefaultLanguage, e.key, e.args...)
+}
+
+// userErrorText returns err's message in a language
+// Errors that are not a userError keep their (English) Error() text.
+func userErrorText(lang string, err error) string {
+	var userErr *userError
+	if errors.As(err, &userErr) {
+		return translate(lang, userErr.key, userErr.args...)
+	}
+	return err.Error()
+}
+
+// userLanguages caches the language each user chose with /language
+// ("" = none chosen), so the preference is read from storage once per process
+//
+// Safe for concurrent use: updates are handled in parallel.
+var userLanguages = struct {
+	mu    sync.Mutex
+	users map[int64]string
+}{users: make(map[int64]string)}
+
+// userLanguage returns the language to answer a user in
+//
+// Order:
+//  1. The language chosen with /language (cached, else loaded from the
+//     user registry, see SetUserStore)
+//  2. The language of the user's Telegram app (From.LanguageCode, e.g. "fr-CA")
+//  3. English
+//
+// Parameters:
+//   - ctx: Request context (storage call, per-update logger)
+//   - user: Telegram user (message.From; nil = English)
+//
+// Returns:
+//   - string: An available language code (see i18n.Translator.Match)
+func userLanguage(ctx context.Context, user *tgbotapi.User) string {
+	if user == nil {
+		return i18n.DefaultLanguage
+	}
+
+	userLanguages.mu.Lock()
+	lang, cached := userLanguages.users[user.ID]
+	userLanguages.mu.Unlock()
+
+	if users := userRegistry; !cached && users != nil {
+		prefs, err := users.LoadUserPreferences(ctx, user.ID)
+		if err != nil {
+			// Not cached, so the next message tries again
+			logger.FromContext(ctx).Warn("Failed to load user preferences",
+				"error", err)
+		} else {
+			lang = prefs.Language
+			setUserLanguage(user.ID, lang)
+		}
+	}
+
+	if lang == "" {
+		lang = user.LanguageCode
+	}
+	return i18n.Default().Match(lang)
+}
+
+// chatLanguage returns the language for a message the bot sends on its own
+// (scheduled messages: good morning, admin summary, price changes)
+//
+// There is no user to answer, but a private chat's ID is its user's ID, so
+// the /language choice still applies there; groups get English.
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//   - chatID: Target chat
+//
+// Returns:
+//   - string: An available language code
+func chatLanguage(ctx context.Context, chatID int64) string {
+	if chatID < 0 {
+		// Group and channel IDs are negative: no user behind them
+		return i18n.DefaultLanguage
+	}
+	return userLanguage(ctx, &tgbotapi.User{ID: chatID})
+}
+
+// setUserLanguage caches a user's chosen language ("" = none chosen)
+func setUserLanguage(userID int64, lang string) {
+	userLanguages.mu.Lock()
+	defer userLanguages.mu.Unlock()
+
+	userLanguages.users[userID] = lang
+}
+
+// languageList describes the available languages, e.g. "de (Deutsch), en (English), fr (Français)"
+func languageList() string {
+	tr := i18n.Default()
+
+	var parts []string
+	for _, lang := range tr.Languages() {
+		parts = append(parts, lang+" ("+tr.T(lang, "language_name")+")")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// HandleLanguage handles the /language [code] command: shows or sets the
+// language the bot answers the user in.
+//
+// Usage:
+//   - /language: current language and the available ones
+//   - /language fr: answer in French from now on (saved in the user
+//     registry, so it survives restarts; in memory only without one)
+//
+// Codes are validated against the available translations (en, fr, de).
+// The choice is per user, in every chat.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /language command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleLanguage(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	lang := userLanguage(ctx, message.From)
+	code := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+
+	var reply string
+	switch {
+	case code == "":
+		reply = translate(lang, "language_current", translate(lang, "language_name"), languageList())
+
+	case !i18n.Default().Has(code):
+		log.Info("Unknown /language code",
+			"code", code)
+		reply = translate(lang, "language_unknown", code, languageList())
+
+	default:
+		if users := userRegistry; users != nil {
+			if err := users.SaveUserPreferences(ctx, message.From.ID, storage.UserPreferences{Language: code}); err != nil {
+				log.Error("Failed to save user language",
+					"error", err,
+					"language", code)
+				reply = translate(lang, "language_save_failed")
+				break
+			}
+		}
+		setUserLanguage(message.From.ID, code)
+		log.Info("User language set",
+			"language", code,
+			"previous", lang)
+		// Confirmed in the new language
+		reply = translate(code, "language_set")
+	}
+
+	msg := replyTo(message, reply)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /language reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
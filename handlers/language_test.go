@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/i18n"
+	"github.com/Alrem/run-tbot/storage"
+)
+
+// resetUserLanguages empties the language cache for one test
+func resetUserLanguages(t *testing.T) {
+	t.Helper()
+	userLanguages.mu.Lock()
+	old := userLanguages.users
+	userLanguages.users = make(map[int64]string)
+	userLanguages.mu.Unlock()
+
+	t.Cleanup(func() {
+		userLanguages.mu.Lock()
+		userLanguages.users = old
+		userLanguages.mu.Unlock()
+	})
+}
+
+// TestHandleLanguage tests /language without a user registry (memory only)
+//
+// Steps (same user, in order):
+//   - No code: current language (English) and the list
+//   - Unknown code: error in English, language unchanged
+//   - "FR": confirmed in French
+//   - No code again: now answered in French
+func TestHandleLanguage(t *testing.T) {
+	oldRegistry := userRegistry
+	defer func() { userRegistry = oldRegistry }()
+	userRegistry = nil
+	resetUserLanguages(t)
+
+	steps := []struct {
+		text     string
+		wantText []string
+		wantLang string
+	}{
+		{text: "/language", wantText: []string{"Your language: English", "de (Deutsch), en (English), fr (Français)"}, wantLang: "en"},
+		{text: "/language xx", wantText: []string{"Unknown language: xx", "de (Deutsch)"}, wantLang: "en"},
+		{text: "/language FR", wantText: []string{"en français"}, wantLang: "fr"},
+		{text: "/language", wantText: []string{"Votre langue : Français"}, wantLang: "fr"},
+	}
+
+	ctx := context.Background()
+	for _, step := range steps {
+		sender := &recordingSender{}
+		HandleLanguage(ctx, sender, createTestMessage(step.text, 777), testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 1 {
+			t.Fatalf("%s: sent %d messages, want 1", step.text, len(messages))
+		}
+		for _, want := range step.wantText {
+			if !strings.Contains(messages[0].Text, want) {
+				t.Errorf("%s: reply = %q, want it to contain %q", step.text, messages[0].Text, want)
+			}
+		}
+		if got := userLanguage(ctx, createTestMessage("", 777).From); got != step.wantLang {
+			t.Errorf("%s: userLanguage() = %q, want %q", step.text, got, step.wantLang)
+		}
+	}
+}
+
+// TestHandleLanguage_Persistence tests that the choice is saved in the
+// user registry and read back after a restart (empty cache)
+func TestHandleLanguage_Persistence(t *testing.T) {
+	oldRegistry := userRegistry
+	defer func() { userRegistry = oldRegistry }()
+	backend := &failingStore{Store: storage.NewMemoryStore()}
+	userRegistry = storage.NewUserStore(backend)
+	resetUserLanguages(t)
+
+	ctx := context.Background()
+	HandleLanguage(ctx, &recordingSender{}, createTestMessage("/language de", 777), testConfig())
+
+	prefs, err := userRegistry.LoadUserPreferences(ctx, 777)
+	if err != nil || prefs.Language != "de" {
+		t.Fatalf("saved preferences = %+v, %v, want language de", prefs, err)
+	}
+
+	// Restart: the cache is empty, the language comes from storage
+	resetUserLanguages(t)
+	if got := userLanguage(ctx, createTestMessage("", 777).From); got != "de" {
+		t.Errorf("userLanguage() after restart = %q, want de", got)
+	}
+
+	// Storage outage: the language isn't changed and the user is told
+	backend.fail = true
+	sender := &recordingSender{}
+	HandleLanguage(ctx, sender, createTestMessage("/language fr", 777), testConfig())
+	if messages := sender.messages(); len(messages) != 1 || !strings.Contains(messages[0].Text, "❌") {
+		t.Errorf("reply on save failure = %v, want the ❌ error", messages)
+	}
+	if got := userLanguage(ctx, createTestMessage("", 777).From); got != "de" {
+		t.Errorf("userLanguage() after failed save = %q, want de (unchanged)", got)
+	}
+}
+
+// TestUserLanguage_TelegramFallback tests the app language fallback
+func TestUserLanguage_TelegramFallback(t *testing.T) {
+	oldRegistry := userRegistry
+	defer func() { userRegistry = oldRegistry }()
+	userRegistry = nil
+	resetUserLanguages(t)
+
+	tests := []struct {
+		name         string
+		languageCode string
+		want         string
+	}{
+		{name: "no app language", languageCode: "", want: "en"},
+		{name: "region", languageCode: "fr-CA", want: "fr"},
+		{name: "german", languageCode: "de", want: "de"},
+		{name: "untranslated", languageCode: "ja", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := createTestMessage("", 888).From
+			user.LanguageCode = tt.languageCode
+			if got := userLanguage(context.Background(), user); got != tt.want {
+				t.Errorf("userLanguage(%q) = %q, want %q", tt.languageCode, got, tt.want)
+			}
+		})
+	}
+
+	if got := userLanguage(context.Background(), nil); got != "en" {
+		t.Errorf("userLanguage(nil) = %q, want en", got)
+	}
+}
+
+// TestTranslatedReplies tests the migrated texts in French and German
+func TestTranslatedReplies(t *testing.T) {
+	buttons := bot.UserButtons(config.AllFeatures(), false)
+
+	if got := formatStartMessage("fr", "", buttons); !strings.HasPrefix(got, "👋 Bonjour, à vous !") || !strings.Contains(got, "Essayez ces fonctions") {
+		t.Errorf("French welcome = %q", got)
+	}
+	if got := formatStartMessage("fr", "", nil); !strings.Contains(got, "Aucune fonction") {
+		t.Errorf("French welcome without features = %q", got)
+	}
+	if got, want := formatDiceResult("fr", 4), "⚃ Vous avez fait 4"; got != want {
+		t.Errorf("formatDiceResult(fr, 4) = %q, want %q", got, want)
+	}
+
+	query := defaultOVHQuery()
+	query.lang = "de"
+	if got := formatOVHMessages(longOffers(1), query, "", 0)[0]; !strings.Contains(got, "Verfügbare OVH") {
+		t.Errorf("German OVH header = %q", got)
+	}
+
+	_, err := parseOVHArgs("lon lon")
+	if err == nil {
+		t.Fatal("parseOVHArgs(lon lon) succeeded, want an error")
+	}
+	if got, want := userErrorText("fr", err), "datacenter indiqué deux fois : \"lon\""; got != want {
+		t.Errorf("userErrorText(fr) = %q, want %q", got, want)
+	}
+	if got, want := err.Error(), "datacenter given twice: \"lon\""; got != want {
+		t.Errorf("Error() = %q, want the English message %q", got, want)
+	}
+}
+
+// usedTranslationKeys returns the literal i18n keys in the package source:
+// translate(lang, "key", ...) and newUserError("key", ...)
+func usedTranslationKeys(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("listing the handlers source: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	var keys []string
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatalf("parsing %s: %v", name, err)
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fun, ok := call.Fun.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			arg := -1
+			switch fun.Name {
+			case "translate":
+				arg = 1
+			case "newUserError":
+				arg = 0
+			}
+			if arg < 0 || len(call.Args) <= arg {
+				return true
+			}
+			if lit, ok := call.Args[arg].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				key, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("%s: %v", fset.Position(lit.Pos()), err)
+				}
+				keys = append(keys, key)
+			}
+			return true
+		})
+	}
+	return keys
+}
+
+// TestTranslationKeys checks that every key the handlers use is in the locales
+// (i18n.TestDefault_Locales checks that every language has the English keys)
+//
+// Checks:
+//   - Literal keys found in the source (see usedTranslationKeys)
+//   - Built keys: command_<name>, button_<Key>, Twister limbs and colors,
+//     double dice labels
+//   - The English command and button descriptions match Command.Description
+//     and bot.Button.Description (Telegram's menu and tests use those)
+func TestTranslationKeys(t *testing.T) {
+	tr := i18n.Default()
+	english := tr.Keys(i18n.DefaultLanguage)
+
+	keys := usedTranslationKeys(t)
+	if len(keys) < 50 {
+		t.Fatalf("found only %d keys in the source, is the scan broken?", len(keys))
+	}
+	for _, key := range keys {
+		if !slices.Contains(english, key) {
+			t.Errorf("key %q is used but missing from the locales", key)
+		}
+	}
+
+	for _, cmd := range RegisteredCommands {
+		if got := commandDescription(i18n.DefaultLanguage, cmd); got != cmd.Description {
+			t.Errorf("command_%s = %q, want %q (Command.Description)", cmd.Name, got, cmd.Description)
+		}
+	}
+	for _, row := range bot.UserButtons(config.AllFeatures(), true) {
+		for _, button := range row {
+			if got := buttonDescription(i18n.DefaultLanguage, button); got != button.Description {
+				t.Errorf("button_%s = %q, want %q (Button.Description)", button.Key, got, button.Description)
+			}
+		}
+	}
+
+	// A missing key is returned as is, so its prefix shows up in the text
+	for _, lang := range tr.Languages() {
+		for _, limb := range []string{"Left Hand", "Right Hand", "Left Foot", "Right Foot"} {
+			for _, color := range []string{"Red", "Blue", "Green", "Yellow"} {
+				if got := twisterMoveText(lang, limb, color); strings.Contains(got, "twister_") {
+					t.Errorf("twisterMoveText(%s, %s, %s) = %q, missing key", lang, limb, color, got)
+				}
+			}
+		}
+		for sum := 2; sum <= 12; sum++ {
+			if got := formatDoubleDiceProbability(lang, sum); strings.Contains(got, "double_dice_") {
+				t.Errorf("formatDoubleDiceProbability(%s, %d) = %q, missing key", lang, sum, got)
+			}
+		}
+	}
+}
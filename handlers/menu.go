@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleMenu handles the /menu command.
+// Re-attaches the main reply keyboard without repeating the whole /start welcome text.
+//
+// When is this needed?
+//   - User closed the keyboard with the keyboard icon in their Telegram client
+//   - Another bot in a group replaced our keyboard with its own
+//   - User ran /hide and now wants the buttons back
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - botAPI: Bot sender for sending messages
+//   - message: Message from Telegram containing the /menu command
+//   - cfg: Application configuration (authorized users get the admin keyboard)
+func HandleMenu(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	log.Info("/menu command received")
+
+	// Reply keyboards can only be attached to a message,
+	// so we send a short text together with the keyboard
+	msg := replyTo(message, translate(userLanguage(ctx, message.From), "menu_shown"))
+	msg.ReplyMarkup = keyboardForUser(message.From, cfg)
+
+	if _, err := sendReply(ctx, botAPI, message, msg); err != nil {
+		log.Error("Failed to send /menu message",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// HandleHide handles the /hide command.
+// Removes the reply keyboard from the user's screen.
+//
+// How keyboard removal works:
+//   - tgbotapi.NewRemoveKeyboard(true) creates ReplyKeyboardRemove markup
+//   - Like showing a keyboard, removing one also requires sending a message
+//   - selective=true: in groups, only the user who sent /hide loses the keyboard
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - botAPI: Bot sender for sending messages
+//   - message: Message from Telegram containing the /hide command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleHide(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	log.Info("/hide command received")
+
+	msg := replyTo(message, translate(userLanguage(ctx, message.From), "menu_hidden"))
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+
+	if _, err := sendReply(ctx, botAPI, message, msg); err != nil {
+		log.Error("Failed to send /hide message",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestHandleMenu verifies that /menu sends one message with the main reply keyboard attached.
+func TestHandleMenu(t *testing.T) {
+	sender := &recordingSender{}
+
+	HandleMenu(context.Background(), sender, createTestMessage("/menu", 12345), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 1 {
+		t.Fatalf("HandleMenu sent %d messages, want 1", len(messages))
+	}
+
+	keyboard, ok := messages[0].ReplyMarkup.(tgbotapi.ReplyKeyboardMarkup)
+	if !ok {
+		t.Fatalf("ReplyMarkup type = %T, want tgbotapi.ReplyKeyboardMarkup", messages[0].ReplyMarkup)
+	}
+	if len(keyboard.Keyboard) == 0 {
+		t.Errorf("ReplyKeyboardMarkup has no rows")
+	}
+}
+
+// TestHandleMenu_Features verifies that disabled features are missing from the keyboard,
+// and that the keyboard is removed instead of sent empty when nothing is left
+func TestHandleMenu_Features(t *testing.T) {
+	t.Run("twister disabled", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Features.Twister = false
+		sender := &recordingSender{}
+
+		// User 99999 is not authorized: main keyboard only
+		HandleMenu(context.Background(), sender, createTestMessage("/menu", 99999), cfg)
+
+		keyboard, ok := sender.messages()[0].ReplyMarkup.(tgbotapi.ReplyKeyboardMarkup)
+		if !ok {
+			t.Fatalf("ReplyMarkup type = %T, want tgbotapi.ReplyKeyboardMarkup", sender.messages()[0].ReplyMarkup)
+		}
+		for _, row := range keyboard.Keyboard {
+			for _, button := range row {
+				if button.Text == "🌀 Twister" {
+					t.Errorf("keyboard contains disabled Twister button")
+				}
+			}
+		}
+	})
+
+	t.Run("everything disabled", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Features = config.Features{}
+		sender := &recordingSender{}
+
+		HandleMenu(context.Background(), sender, createTestMessage("/menu", 99999), cfg)
+
+		if _, ok := sender.messages()[0].ReplyMarkup.(tgbotapi.ReplyKeyboardRemove); !ok {
+			t.Errorf("ReplyMarkup type = %T, want tgbotapi.ReplyKeyboardRemove", sender.messages()[0].ReplyMarkup)
+		}
+	})
+}
+
+// TestHandleHide verifies that /hide sends one message that removes the reply keyboard.
+func TestHandleHide(t *testing.T) {
+	sender := &recordingSender{}
+
+	HandleHide(context.Background(), sender, createTestMessage("/hide", 12345), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 1 {
+		t.Fatalf("HandleHide sent %d messages, want 1", len(messages))
+	}
+
+	remove, ok := messages[0].ReplyMarkup.(tgbotapi.ReplyKeyboardRemove)
+	if !ok {
+		t.Fatalf("ReplyMarkup type = %T, want tgbotapi.ReplyKeyboardRemove", messages[0].ReplyMarkup)
+	}
+	if !remove.RemoveKeyboard {
+		t.Errorf("ReplyKeyboardRemove.RemoveKeyboard = false, want true")
+	}
+}
+
+// TestRouteUpdate_MenuCommands verifies /menu and /hide are routed (not answered as unknown).
+func TestRouteUpdate_MenuCommands(t *testing.T) {
+	tests := []struct {
+		command    string
+		wantMarkup string
+	}{
+		{command: "/menu", wantMarkup: "keyboard"},
+		{command: "/hide", wantMarkup: "remove"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage(tt.command, 12345)}, testConfig())
+
+			messages := sender.messages()
+			if len(messages) != 1 {
+				t.Fatalf("RouteUpdate(context.Background(), %s) sent %d messages, want 1", tt.command, len(messages))
+			}
+
+			switch tt.wantMarkup {
+			case "keyboard":
+				if _, ok := messages[0].ReplyMarkup.(tgbotapi.ReplyKeyboardMarkup); !ok {
+					t.Errorf("ReplyMarkup type = %T, want ReplyKeyboardMarkup", messages[0].ReplyMarkup)
+				}
+			case "remove":
+				if _, ok := messages[0].ReplyMarkup.(tgbotapi.ReplyKeyboardRemove); !ok {
+					t.Errorf("ReplyMarkup type = %T, want ReplyKeyboardRemove", messages[0].ReplyMarkup)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import "github.com/Alrem/run-tbot/metrics"
+
+// handlerInvocations counts feature uses by outcome, exported at GET /metrics:
+//
+//	handler_invocations_total{feature="ovh",result="unauthorized"} 3
+//
+// Separate results tell "users are denied" apart from "OVH is failing".
+var handlerInvocations = metrics.NewCounterVec("handler_invocations_total",
+	"Handler invocations by feature and result", "feature", "result")
+
+// Values of the "result" label
+const (
+	resultSuccess      = "success"      // Feature completed
+	resultError        = "error"        // External call or Telegram send failed
+	resultUnauthorized = "unauthorized" // User not in ALLOWED_USERS
+	resultCancelled    = "cancelled"    // Aborted via /cancel or request context
+	resultThrottled    = "throttled"    // Refused by a per-user cooldown (e.g., OVH_COOLDOWN)
+)
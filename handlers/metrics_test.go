@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestHandleOVHCheck_Metrics verifies that each branch of the OVH handler
+// increments exactly one handler_invocations_total{feature="ovh"} series.
+//
+// Cases:
+//   - User not in ALLOWED_USERS: result="unauthorized" (OVH never called)
+//   - OVH fetch fails: result="error"
+//   - Offers fetched: result="success"
+func TestHandleOVHCheck_Metrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int64
+		fetchErr   error
+		wantResult string
+	}{
+		{name: "unauthorized", userID: 99999, wantResult: resultUnauthorized},
+		{name: "fetch error", userID: 12345, fetchErr: errors.New("ovh down"), wantResult: resultError},
+		{name: "success", userID: 12345, wantResult: resultSuccess},
+	}
+
+	results := []string{resultSuccess, resultError, resultUnauthorized, resultCancelled, resultThrottled}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldGetTopOffers := getTopOffers
+			getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+				return []ovh.Offer{{FQN: "24sk20", Price: 9.99, Currency: "EUR"}}, tt.fetchErr
+			}
+			defer func() { getTopOffers = oldGetTopOffers }()
+
+			// Counters are global, so compare before/after
+			before := make(map[string]float64)
+			for _, result := range results {
+				before[result] = handlerInvocations.Value("ovh", result)
+			}
+
+			HandleOVHCheck(context.Background(), &recordingSender{}, createTestMessage("🖥️ OVH Servers", tt.userID), testConfig())
+
+			for _, result := range results {
+				want := before[result]
+				if result == tt.wantResult {
+					want++
+				}
+				if got := handlerInvocations.Value("ovh", result); got != want {
+					t.Errorf("handler_invocations_total{feature=\"ovh\",result=%q} = %g, want %g", result, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,13 +1,44 @@
 package handlers
 
 import (
-	"log/slog"
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
 	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// OVH query used by all OVH features
+// FR subsidiary gives EUR prices, lon is the London datacenter
+const (
+	ovhSubsidiary = "FR"
+	ovhDatacenter = "lon"
+	ovhTop        = 3
+
+	// ovhLuckyPool is how many offers /lucky_server picks from
+	// (large enough to cover every available server in practice)
+	ovhLuckyPool = 50
+
+	// ovhMessageLimit is the maximum length of one OVH results message
+	// Telegram's limit is 4096 characters; the margin covers counting
+	// differences (Telegram counts UTF-16 code units, we count runes)
+	ovhMessageLimit = 4000
+)
+
+// getTopOffers fetches OVH offers
+// Declared as var so tests can replace it and avoid real OVH API calls
+var getTopOffers = ovh.GetTopOffersContext
+
+// getTopDedicatedOffers fetches offers from the dedicated catalog (Advance and up)
+// Declared as var for the same reason as getTopOffers
+var getTopDedicatedOffers = ovh.GetTopDedicatedOffersContext
+
 // HandleOVHCheck handles the "🖥️ OVH Servers" button click from reply keyboard.
 // Shows available OVH servers (private feature, only for authorized users).
 //
@@ -22,88 +53,409 @@ import (
 //   - Includes FQN (Fully Qualified Name) for each server
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing button click
 //   - cfg: Application configuration (needed for authorization check)
-func HandleOVHCheck(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.Config) {
-	// Step 1: Check authorization
-	if !cfg.IsUserAllowed(message.From.ID) {
-		// Log unauthorized access attempt
-		slog.Info("Unauthorized OVH check attempt",
-			"user_id", message.From.ID,
-			"username", message.From.UserName,
-			"chat_id", message.Chat.ID)
-
-		// Send error message
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID,
-			"⛔ This feature is only available to authorized users\\.")
-		errorMsg.ParseMode = "MarkdownV2"
-
-		if _, err := bot.Send(errorMsg); err != nil {
-			slog.Error("Failed to send authorization error message",
-				"error", err, "chat_id", message.Chat.ID)
-		}
+func HandleOVHCheck(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	runOVHCheck(ctx, bot, message, cfg, defaultOVHQuery())
+}
+
+// HandleOVHCommand handles the /ovh [datacenter] [count] [max=PRICE] [prefix=PLAN] [search KEYWORD] [soon] command.
+// Without arguments it is the "🖥️ OVH Servers" button (see HandleOVHCheck).
+//
+// Examples:
+//   - /ovh gra: top 3 in Gravelines
+//   - /ovh lon 5 max=25: top 5 in London at or under 25 EUR/month
+//   - /ovh prefix=25skle: only the KS-LE range (plan codes 25skle...)
+//   - /ovh search nvme: only servers with NVMe disks (keyword in the FQN)
+//
+// Arguments that don't parse get the usage help (see parseOVHArgs),
+// authorized users only: authorization comes before parsing.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleOVHCommand(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	ctx, allowed := authorize(ctx, bot, message, cfg, config.FeatureOVH)
+	if !allowed {
 		return
 	}
 
-	// Step 2: Send status message
-	statusMsg := tgbotapi.NewMessage(message.Chat.ID,
-		"🖥️ Checking OVH server availability\\.\\.\\.\\nThis may take a few seconds\\.")
-	statusMsg.ParseMode = "MarkdownV2"
+	query, err := parseOVHArgs(message.CommandArguments())
+	if err != nil {
+		logger.FromContext(ctx).Info("Invalid /ovh arguments",
+			"error", err)
+		lang := userLanguage(ctx, message.From)
+		sendOVHFetchReply(ctx, bot, message, translate(lang, "ovh_invalid_args", userErrorText(lang, err))+"\n\n"+ovhUsage(lang))
+		return
+	}
 
-	if _, err := bot.Send(statusMsg); err != nil {
-		slog.Error("Failed to send OVH status message",
-			"error", err, "chat_id", message.Chat.ID)
+	runOVHCheck(ctx, bot, message, cfg, query)
+}
+
+// runOVHCheck fetches the offers of query and sends them
+func runOVHCheck(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config, query ovhQuery) {
+	// Steps 1-3: Authorization, status message and OVH fetch
+	// Shared with the export commands (/ovhcsv), see fetchOVHOffers
+	offers, ok := fetchOVHOffers(ctx, bot, message, cfg, "ovh", query)
+	if !ok {
 		return
 	}
 
-	// Step 3: Fetch OVH data
-	// Parameters: FR (France subsidiary for EUR), lon (London), top 3 servers
-	slog.Info("Fetching OVH server availability",
-		"user_id", message.From.ID,
-		"subsidiary", "FR",
-		"datacenter", "lon",
-		"top", 3)
-
-	offers, err := ovh.GetTopOffers("FR", "lon", 3)
-	if err != nil {
-		// Log error
-		slog.Error("Failed to fetch OVH offers",
-			"error", err,
-			"user_id", message.From.ID,
-			"chat_id", message.Chat.ID)
+	// Step 4: Format and send results (split into several messages if long)
+	query.lang = userLanguage(ctx, message.From)
+	sendOVHResults(ctx, bot, message.Chat.ID, offers, query, formatAsOf(query.lang, time.Now(), cfg.Location))
+}
 
-		// Send user-friendly error message
-		errMsg := tgbotapi.NewMessage(message.Chat.ID,
-			"❌ Failed to fetch server availability\\. Please try again later\\.")
-		errMsg.ParseMode = "MarkdownV2"
+// HandleDedicatedOVHCheck handles the "🔵 Dedicated Servers" admin button.
+// Same as HandleOVHCheck, but prices the dedicated catalog (Advance and up)
+// instead of the ECO one (Kimsufi, So You Start, Rise).
+//
+// Only authorized users get the button (see bot.UserButtons); the handler
+// checks authorization anyway, because anyone can type the button text.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing button click
+//   - cfg: Application configuration (needed for authorization check)
+func HandleDedicatedOVHCheck(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	var offers []ovh.Offer
+	ok := runOVHFetch(ctx, bot, message, cfg, "ovh_dedicated", func(ctx context.Context) error {
+		log.Info("Fetching OVH dedicated server availability",
+			"subsidiary", ovhSubsidiary,
+			"datacenter", ovhDatacenter,
+			"top", ovhTop)
 
-		if _, err := bot.Send(errMsg); err != nil {
-			slog.Error("Failed to send OVH error message",
-				"error", err, "chat_id", message.Chat.ID)
+		var err error
+		offers, err = getTopDedicatedOffers(ctx, ovhSubsidiary, ovhDatacenter, ovhTop)
+		if err == nil && len(offers) == 0 {
+			// runOVHFetch sends the "nothing in stock" reply
+			return ovh.ErrNoOffers
 		}
+		return err
+	})
+	if !ok {
 		return
 	}
 
-	// Step 4: Format and send results
-	messageText := formatOVHResults(offers)
+	query := defaultOVHQuery()
+	query.lang = userLanguage(ctx, message.From)
+	sendOVHResults(ctx, bot, message.Chat.ID, offers, query, formatAsOf(query.lang, time.Now(), cfg.Location))
+}
+
+// HandleLuckyServer handles the /lucky_server command.
+// Instead of the cheapest servers, shows one random available server
+// in the configured datacenter (private feature, like /ovh).
+//
+// Flow:
+//  1. Authorization, status message and fetch of up to ovhLuckyPool offers (runOVHFetch)
+//  2. ovh.PickRandomOffer picks one of them
+//  3. The single offer is sent under a "🎰 Your lucky server today:" header
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleLuckyServer(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	var offers []ovh.Offer
+	ok := runOVHFetch(ctx, bot, message, cfg, "lucky_server", func(ctx context.Context) error {
+		log.Info("Fetching OVH offers for lucky server",
+			"subsidiary", ovhSubsidiary,
+			"datacenter", ovhDatacenter,
+			"top", ovhLuckyPool)
+
+		var err error
+		offers, err = getTopOffers(ctx,
+			ovh.WithSubsidiary(ovhSubsidiary),
+			ovh.WithDatacenter(ovhDatacenter),
+			ovh.WithTop(ovhLuckyPool),
+		)
+		if err == nil && len(offers) == 0 {
+			// Nothing to pick from: runOVHFetch sends the "nothing in stock" reply
+			return ovh.ErrNoOffers
+		}
+		return err
+	})
+	if !ok {
+		return
+	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, messageText)
-	msg.ParseMode = "MarkdownV2"
+	offer := ovh.PickRandomOffer(offers)
+	msg := replyTo(message, formatLuckyServer(userLanguage(ctx, message.From), offer))
 	msg.DisableWebPagePreview = true
 
-	if _, err := bot.Send(msg); err != nil {
-		slog.Error("Failed to send OVH results",
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send lucky server",
 			"error", err,
-			"chat_id", message.Chat.ID,
-			"offers_count", len(offers))
+			"message_type", messageType(msg),
+			"plan_code", offer.PlanCode)
 		return
 	}
 
-	slog.Info("OVH results sent successfully",
-		"user_id", message.From.ID,
-		"chat_id", message.Chat.ID,
-		"offers_count", len(offers))
+	log.Info("Lucky server sent successfully",
+		"plan_code", offer.PlanCode,
+		"pool_size", len(offers))
+}
+
+// formatLuckyServer formats the /lucky_server result (MarkdownV2)
+//
+// Parameters:
+//   - lang: Language of the header and footer (see userLanguage)
+//   - offer: The randomly picked offer
+//
+// Returns:
+//   - string: Header, the offer (as in /ovh results) and the /start footer
+func formatLuckyServer(lang string, offer ovh.Offer) string {
+	return "🎰 " + tgfmt.Bold(translate(lang, "ovh_lucky_title")) + "\n\n" +
+		ovh.FormatOfferForTelegram(offer, 1) + "\n\n" +
+		tgfmt.Italic(translate(lang, "ovh_footer"))
+}
+
+// sendOVHResults formats offers and sends them as one or more messages.
+//
+// Telegram rejects messages longer than 4096 characters, so long lists are
+// split between offers (see formatOVHMessages). Sending stops at the first
+// error: the rest of the list would arrive with a gap in the numbering.
+//
+// The last message carries the "ℹ️ N" details buttons, and the offers are
+// remembered for them (see ovhDetailsKeyboard, rememberOVHResults).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - chatID: Chat to send the results to
+//   - offers: Offers to show (may be empty)
+//   - query: What was asked for (datacenter, count and filters for the header)
+//   - asOf: "as of" footer line (formatAsOf), "" for none
+func sendOVHResults(ctx context.Context, bot BotSender, chatID int64, offers []ovh.Offer, query ovhQuery, asOf string) {
+	log := logger.FromContext(ctx)
+
+	messages := formatOVHMessages(offers, query, asOf, ovhMessageLimit)
+	rememberOVHResults(chatID, offers)
+	for i, text := range messages {
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.DisableWebPagePreview = true
+		if i == len(messages)-1 {
+			// A nil *InlineKeyboardMarkup in the interface would be sent as "null"
+			if keyboard := ovhDetailsKeyboard(offers); keyboard != nil {
+				msg.ReplyMarkup = keyboard
+			}
+		}
+
+		if _, err := sendFormatted(ctx, bot, msg); err != nil {
+			log.Error("Failed to send OVH results",
+				"error", err,
+				"message_type", messageType(msg),
+				"offers_count", len(offers),
+				"part", i+1,
+				"parts", len(messages))
+			return
+		}
+	}
+
+	log.Info("OVH results sent successfully",
+		"offers_count", len(offers),
+		"parts", len(messages))
+}
+
+// fetchOVHOffers runs the common part of every OVH feature:
+// authorization check, "please wait" status message and the OVH API call.
+// All user-facing error messages are sent here, so callers only need to
+// format and deliver the offers in their own way (text, CSV, ...).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram that triggered the feature
+//   - cfg: Application configuration (needed for authorization check)
+//   - feature: Feature name for the handler_invocations_total metric (e.g., "ovh")
+//   - query: What to fetch (defaultOVHQuery: FR, lon, top 3, no filters)
+//
+// Returns:
+//   - []ovh.Offer: Top offers (never empty when bool is true)
+//   - bool: false if the caller should stop (unauthorized, nothing in stock, send or fetch failure)
+func fetchOVHOffers(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config, feature string, query ovhQuery) ([]ovh.Offer, bool) {
+	log := logger.FromContext(ctx)
+
+	var offers []ovh.Offer
+
+	ok := runOVHFetch(ctx, bot, message, cfg, feature, func(ctx context.Context) error {
+		log.Info("Fetching OVH server availability",
+			"subsidiary", ovhSubsidiary,
+			"datacenter", query.datacenter,
+			"top", query.top,
+			"max_price", query.maxPrice,
+			"plan_prefix", query.planPrefix)
+
+		var err error
+		offers, err = getTopOffers(ctx, query.options()...)
+		if err == nil && len(offers) == 0 {
+			// runOVHFetch sends the "nothing in stock" reply
+			return noOffersError{query: query}
+		}
+		return err
+	})
+
+	return offers, ok
+}
+
+// runOVHFetch wraps a slow OVH API call with everything around it:
+// authorization, status message, /cancel support and the error reply.
+// Used by fetchOVHOffers and the catalog comparison (/compare_catalogs).
+//
+// Every outcome is counted in handler_invocations_total{feature=...}:
+// unauthorized, throttled (OVH_COOLDOWN), error (status send or fetch failed,
+// including OVH_TIMEOUT), cancelled or success.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger; cancelling it aborts the fetch)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram that triggered the feature
+//   - cfg: Application configuration (authorization, OVHTimeout bounds the fetch)
+//   - feature: Feature name for the metric label (e.g., "ovh", "ovhcsv")
+//   - fetch: The OVH call; must respect ctx so /cancel can abort it.
+//     Returning ovh.ErrNoOffers gets the "nothing in stock" reply instead of the error reply
+//     (a noOffersError names the datacenter and filters in it)
+//
+// Returns:
+//   - bool: false if the caller should stop (unauthorized, throttled, cancelled, no offers, send or fetch failure)
+func runOVHFetch(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config, feature string, fetch func(ctx context.Context) error) bool {
+	log := logger.FromContext(ctx)
+	lang := userLanguage(ctx, message.From)
+
+	// Step 1: Check authorization (sends "not authorized" reply on failure)
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		handlerInvocations.Inc(feature, resultUnauthorized)
+		return false
+	}
+
+	// Step 1b: Per-user cooldown (OVH_COOLDOWN), checked after authorization
+	// so unauthorized users keep getting the same answer
+	if ovhCooldown != nil {
+		if allowed, remaining := ovhCooldown.Allow(message.From.ID); !allowed {
+			log.Info("OVH request throttled",
+				"remaining", remaining)
+			handlerInvocations.Inc(feature, resultThrottled)
+			sendOVHFetchReply(ctx, bot, message, translate(lang, "ovh_throttled", formatCooldownWait(remaining)))
+			return false
+		}
+	}
+
+	// Step 2: Send status message
+	statusMsg := replyTo(message,
+		tgfmt.EscapeMarkdownV2(translate(lang, "ovh_checking")))
+
+	if _, err := sendFormattedReply(ctx, bot, message, statusMsg); err != nil {
+		log.Error("Failed to send OVH status message",
+			"error", err,
+			"message_type", messageType(statusMsg))
+		handlerInvocations.Inc(feature, resultError)
+		return false
+	}
+
+	// Step 3: Fetch OVH data
+	// Register the fetch as the user's current operation so /cancel can abort it
+	// Derived from the request context: if Cloud Run stops the instance
+	// (or the request is cancelled), the OVH call is aborted too
+	opCtx, done := operations.start(ctx, message.From.ID)
+	defer done()
+
+	// The whole fetch is bounded by OVH_TIMEOUT, on top of the per-request
+	// bound in the ovh package: the user waits at most this long for an answer
+	fetchCtx := opCtx
+	if cfg.OVHTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(opCtx, cfg.OVHTimeout)
+		defer cancel()
+	}
+
+	err := fetch(fetchCtx)
+	if err != nil && ctx.Err() != nil {
+		// Request context done (shutdown, deadline) - nobody is waiting for a reply
+		log.Warn("OVH fetch aborted: request context done",
+			"error", ctx.Err())
+		handlerInvocations.Inc(feature, resultCancelled)
+		return false
+	}
+	if err != nil && opCtx.Err() != nil {
+		// Cancelled via /cancel - HandleCancel already replied to the user
+		log.Info("OVH fetch cancelled by user")
+		handlerInvocations.Inc(feature, resultCancelled)
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		// OVH_TIMEOUT elapsed (whole fetch or a single request)
+		log.Error("OVH fetch timed out",
+			"error", err,
+			"timeout", cfg.OVHTimeout)
+		handlerInvocations.Inc(feature, resultError)
+		sendOVHFetchReply(ctx, bot, message, translate(lang, "ovh_timeout"))
+		return false
+	}
+	if errors.Is(err, ovh.ErrNoOffers) {
+		// OVH answered, everything is sold out: normal, not an error
+		query := defaultOVHQuery()
+		var noOffers noOffersError
+		if errors.As(err, &noOffers) {
+			query = noOffers.query
+		}
+		log.Info("No OVH offers available",
+			"datacenter", query.datacenter)
+		handlerInvocations.Inc(feature, resultSuccess)
+		sendOVHFetchReply(ctx, bot, message, formatNoOffers(lang, query))
+		return false
+	}
+	if err != nil {
+		// Log error
+		log.Error("Failed to fetch OVH offers",
+			"error", err)
+		handlerInvocations.Inc(feature, resultError)
+
+		// Send user-friendly error message
+		sendOVHFetchReply(ctx, bot, message, translate(lang, "ovh_unavailable"))
+		return false
+	}
+
+	handlerInvocations.Inc(feature, resultSuccess)
+	return true
+}
+
+// noOffersError is ovh.ErrNoOffers for one query, so the "nothing in stock"
+// reply can name the datacenter and filters that were asked for
+type noOffersError struct {
+	query ovhQuery
+}
+
+func (e noOffersError) Error() string { return ovh.ErrNoOffers.Error() }
+func (e noOffersError) Unwrap() error { return ovh.ErrNoOffers }
+
+// formatNoOffers is the plain-text reply when a query finds no offers
+func formatNoOffers(lang string, query ovhQuery) string {
+	location := ovh.DatacenterName(query.datacenter)
+	if filters := query.filters(lang); filters != "" {
+		return translate(lang, "ovh_no_match", location, filters)
+	}
+	return translate(lang, "ovh_no_stock", location)
+}
+
+// sendOVHFetchReply sends the plain-text outcome of a throttled, failed or empty OVH fetch
+func sendOVHFetchReply(ctx context.Context, bot BotSender, message *tgbotapi.Message, text string) {
+	msg := replyTo(message, tgfmt.EscapeMarkdownV2(text))
+
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		logger.FromContext(ctx).Error("Failed to send OVH fetch reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
 }
 
 // formatOVHResults formats OVH offers for display in Telegram.
@@ -111,24 +463,112 @@ func HandleOVHCheck(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config
 //
 // Parameters:
 //   - offers: List of OVH Offer structs with pricing and availability
+//   - query: What was asked for (datacenter shown as full name, e.g., "London, UK")
+//   - asOf: "as of" footer line (formatAsOf), "" for none
 //
 // Returns:
 //   - string: Formatted message with MarkdownV2 escaping
-func formatOVHResults(offers []ovh.Offer) string {
+func formatOVHResults(offers []ovh.Offer, query ovhQuery, asOf string) string {
+	// No limit: everything fits in one message
+	return formatOVHMessages(offers, query, asOf, 0)[0]
+}
+
+// formatAsOf formats the "as of" footer line of OVH results (MarkdownV2)
+// Results are read later (or forwarded), so the line says when the check
+// ran; the zone name says which clock it is.
+//
+// Parameters:
+//   - lang: Language of the line (see userLanguage)
+//   - t: Time of the check (time.Now() once the offers are loaded)
+//   - loc: Display time zone (cfg.Location; nil = UTC)
+//
+// Returns:
+//   - string: e.g. "_as of 14:32 Europe/London_"
+func formatAsOf(lang string, t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return tgfmt.Italic(translate(lang, "ovh_as_of", t.In(loc).Format("15:04"), loc.String()))
+}
+
+// formatOVHMessages formats OVH offers as one or more messages of at most maxLen characters.
+//
+// Layout:
+//   - First message: header + as many offers as fit
+//   - Next messages: the numbered list continues (numbers are never restarted)
+//   - Last message: ends with the "as of" line (if any) and the /start footer
+//
+// Messages are only split between offers, so MarkdownV2 entities
+// (bold prices, italic FQN lines) are never cut in half.
+//
+// Parameters:
+//   - offers: List of OVH Offer structs with pricing and availability
+//   - query: What was asked for (datacenter shown as full name, count and filters in the header;
+//     every text in query.lang)
+//   - asOf: "as of" footer line (formatAsOf), "" for none
+//   - maxLen: Maximum characters per message (0 = no limit)
+//
+// Returns:
+//   - []string: Formatted messages with MarkdownV2 escaping (at least one)
+func formatOVHMessages(offers []ovh.Offer, query ovhQuery, asOf string, maxLen int) []string {
+	location := ovh.DatacenterName(query.datacenter)
+
 	// Handle empty results
 	if len(offers) == 0 {
-		return "No available servers found in London datacenter\\."
+		return []string{tgfmt.EscapeMarkdownV2(translate(query.lang, "ovh_no_servers", location))}
+	}
+
+	// The current message is built with strings.Builder: "+=" copies the whole
+	// message on every append, which adds up for long lists (see BenchmarkFormatOVHResults)
+	// currentRunes tracks its length, so fits doesn't recount the message each time
+	var messages []string
+	var current strings.Builder
+	currentRunes := 0
+
+	// fits reports whether the current message may grow by next
+	fits := func(next string) bool {
+		return maxLen <= 0 || currentRunes+utf8.RuneCountInString(next) <= maxLen
+	}
+	write := func(text string) {
+		current.WriteString(text)
+		currentRunes += utf8.RuneCountInString(text)
+	}
+	flush := func() {
+		messages = append(messages, current.String())
+		current.Reset()
+		currentRunes = 0
 	}
 
-	// Build message
-	message := "🖥️ *Available OVH Servers*\n"
-	message += "_Top 3 cheapest in London \\(EUR\\)_\n\n"
+	// Build first message header
+	write("🖥️ " + tgfmt.Bold(translate(query.lang, "ovh_header")) + "\n")
+	subtitle := translate(query.lang, "ovh_subtitle", query.top, location)
+	if filters := query.filters(query.lang); filters != "" {
+		subtitle += ", " + filters
+	}
+	write(tgfmt.Italic(subtitle) + "\n\n")
+	currentOffers := 0
 
 	for i, offer := range offers {
-		message += ovh.FormatOfferForTelegram(offer, i+1) + "\n"
+		line := ovh.FormatOfferForTelegram(offer, i+1) + "\n"
+
+		// Start a new message, but never leave one without offers
+		if currentOffers > 0 && !fits(line) {
+			flush()
+			currentOffers = 0
+		}
+		write(line)
+		currentOffers++
 	}
 
-	message += "\n_Use /start to return to main menu_"
+	footer := "\n" + tgfmt.Italic(translate(query.lang, "ovh_footer"))
+	if asOf != "" {
+		footer = "\n" + asOf + footer
+	}
+	if !fits(footer) {
+		flush()
+	}
+	write(footer)
+	flush()
 
-	return message
+	return messages
 }
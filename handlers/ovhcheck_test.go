@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // TestFormatOVHResults tests the formatOVHResults function.
@@ -33,6 +40,7 @@ func TestFormatOVHResults(t *testing.T) {
 			offers: []ovh.Offer{},
 			expectedMust: []string{
 				"No available servers found",
+				"London, UK datacenter", // Full datacenter name, not "lon"
 			},
 			expectedMustNot: "",
 		},
@@ -46,12 +54,14 @@ func TestFormatOVHResults(t *testing.T) {
 					Price:       12.99,
 					Currency:    "EUR",
 					InvoiceName: "ECO 1",
+					Datacenter:  "lon",
 					Addons:      map[string]string{},
 				},
 			},
 			expectedMust: []string{
 				"Available OVH Servers",
-				"Top 3 cheapest in London",
+				"Top 3 cheapest in London, UK",
+				"· London, UK", // Datacenter name on the FQN line
 				"1\\.",
 				"12\\.99", // Price period is escaped in MarkdownV2
 				"EUR",
@@ -112,7 +122,11 @@ func TestFormatOVHResults(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatOVHResults(tt.offers)
+			result := formatOVHResults(tt.offers, defaultOVHQuery(), "")
+
+			if err := tgfmt.ValidateMarkdownV2(result); err != nil {
+				t.Errorf("formatOVHResults() is not valid MarkdownV2: %v\n\nGot:\n%s", err, result)
+			}
 
 			// Check that all required strings are present
 			for _, required := range tt.expectedMust {
@@ -129,6 +143,145 @@ func TestFormatOVHResults(t *testing.T) {
 	}
 }
 
+// longOffers returns n offers with long names and FQNs, so the formatted
+// list quickly exceeds one Telegram message
+func longOffers(n int) []ovh.Offer {
+	offers := make([]ovh.Offer, n)
+	for i := range offers {
+		offers[i] = ovh.Offer{
+			FQN:         fmt.Sprintf("24sk%02d.ram-64g-ecc-2133.softraid-2x2000sa.lon", i),
+			Price:       float64(10 + i),
+			Currency:    "EUR",
+			InvoiceName: "KS-" + strings.Repeat("X", 100),
+			Datacenter:  "lon",
+		}
+	}
+	return offers
+}
+
+// TestSendOVHResults_SplitsLongLists verifies that a ~5000 character list
+// is sent as exactly two messages, each under the limit.
+//
+// Expected layout:
+//   - First message: header + first offers
+//   - Second message: numbered list continues + /start footer
+//   - Every message is valid MarkdownV2 on its own
+func TestSendOVHResults_SplitsLongLists(t *testing.T) {
+	// Find the number of offers whose single-message rendering is ~5000 characters
+	n := 1
+	for utf8.RuneCountInString(formatOVHResults(longOffers(n), defaultOVHQuery(), "")) < 5000 {
+		n++
+	}
+	offers := longOffers(n)
+
+	sender := &recordingSender{}
+	sendOVHResults(context.Background(), sender, 42, offers, defaultOVHQuery(), "")
+
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("sent %d messages for %d offers, want 2", len(messages), n)
+	}
+
+	for i, msg := range messages {
+		if length := utf8.RuneCountInString(msg.Text); length > ovhMessageLimit {
+			t.Errorf("message %d is %d characters, limit %d", i+1, length, ovhMessageLimit)
+		}
+		if err := tgfmt.ValidateMarkdownV2(msg.Text); err != nil {
+			t.Errorf("message %d is not valid MarkdownV2: %v", i+1, err)
+		}
+		if msg.ChatID != 42 {
+			t.Errorf("message %d ChatID = %d, want 42", i+1, msg.ChatID)
+		}
+	}
+
+	first, second := messages[0].Text, messages[1].Text
+	if !strings.Contains(first, "Available OVH Servers") || strings.Contains(second, "Available OVH Servers") {
+		t.Errorf("header must be in the first message only")
+	}
+	if strings.Contains(first, "/start") || !strings.Contains(second, "/start") {
+		t.Errorf("footer must be in the last message only")
+	}
+
+	// Numbering continues: every offer appears exactly once across both messages
+	// (the first message ends with a newline, so every number starts a line)
+	all := first + second
+	for i := 1; i <= n; i++ {
+		if count := strings.Count(all, fmt.Sprintf("\n%d\\. ", i)); count != 1 {
+			t.Errorf("offer %d appears %d times, want once", i, count)
+		}
+	}
+}
+
+// TestFormatOVHMessages_ShortListSingleMessage verifies short lists are not split
+func TestFormatOVHMessages_ShortListSingleMessage(t *testing.T) {
+	messages := formatOVHMessages(longOffers(3), defaultOVHQuery(), "", ovhMessageLimit)
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	if messages[0] != formatOVHResults(longOffers(3), defaultOVHQuery(), "") {
+		t.Errorf("single message differs from formatOVHResults output")
+	}
+}
+
+// TestFormatAsOf tests the "as of" footer line
+//
+// Cases:
+//   - The time is converted to the display zone, named by its IANA name
+//   - nil zone: UTC
+//   - The line is valid MarkdownV2 and ends up above the /start footer
+func TestFormatAsOf(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetched := time.Date(2025, time.July, 1, 13, 32, 59, 0, time.UTC) // 14:32 BST
+
+	tests := []struct {
+		name string
+		loc  *time.Location
+		want string
+	}{
+		{name: "London summer time", loc: london, want: "_as of 14:32 Europe/London_"},
+		{name: "UTC", loc: time.UTC, want: "_as of 13:32 UTC_"},
+		{name: "no zone", loc: nil, want: "_as of 13:32 UTC_"},
+		{name: "fixed offset", loc: time.FixedZone("UTC+5", 5*60*60), want: "_as of 18:32 UTC\\+5_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatAsOf("en", fetched, tt.loc)
+			if got != tt.want {
+				t.Errorf("formatAsOf() = %q, want %q", got, tt.want)
+			}
+			if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+				t.Errorf("formatAsOf() is not valid MarkdownV2: %v", err)
+			}
+		})
+	}
+
+	result := formatOVHResults(longOffers(2), defaultOVHQuery(), formatAsOf("en", fetched, london))
+	if !strings.HasSuffix(result, "\n_as of 14:32 Europe/London_\n_Use /start to return to main menu_") {
+		t.Errorf("formatOVHResults() footer = %q, want the as of line above the /start line", result[len(result)-80:])
+	}
+}
+
+// BenchmarkFormatOVHResults measures formatting cost for growing offer lists
+// longOffers gives ~200 character offers (long names and FQNs), the worst case
+// for building the message piece by piece.
+//
+// Run with: go test -bench=FormatOVHResults -benchmem ./handlers
+func BenchmarkFormatOVHResults(b *testing.B) {
+	for _, n := range []int{5, 10, 20, 50} {
+		offers := longOffers(n)
+		b.Run(fmt.Sprintf("offers=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				formatOVHResults(offers, defaultOVHQuery(), "")
+			}
+		})
+	}
+}
+
 // Example of what we DON'T test:
 //
 // ❌ Don't test HandleOVHCheck directly:
@@ -150,3 +303,185 @@ func TestFormatOVHResults(t *testing.T) {
 //   - Integration tests that hit real OVH API (optional, slow)
 //   - Mock-based tests for HandleOVHCheck with stubbed OVH calls
 //   - Tests for authorization behavior (with mocked config)
+
+// TestHandleLuckyServer verifies /lucky_server asks for a large pool
+// and sends one of its offers under the lucky header
+func TestHandleLuckyServer(t *testing.T) {
+	pool := []ovh.Offer{
+		{PlanCode: "ks-a", InvoiceName: "KS-A", Price: 10, Currency: "EUR", FQN: "ks-a.fqn"},
+		{PlanCode: "ks-b", InvoiceName: "KS-B", Price: 20, Currency: "EUR", FQN: "ks-b.fqn"},
+	}
+
+	var requestedTop int
+	original := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		var options ovh.Options
+		for _, opt := range opts {
+			opt(&options)
+		}
+		requestedTop = options.Top
+		return pool, nil
+	}
+	defer func() { getTopOffers = original }()
+
+	sender := &recordingSender{}
+	HandleLuckyServer(context.Background(), sender, createTestMessage("/lucky_server", 12345), testConfig())
+
+	if requestedTop != ovhLuckyPool {
+		t.Errorf("getTopOffers top = %d, want %d", requestedTop, ovhLuckyPool)
+	}
+
+	// Status message + result
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(messages))
+	}
+	result := messages[1].Text
+	if !strings.Contains(result, "Your lucky server today:") {
+		t.Errorf("result missing lucky header: %q", result)
+	}
+	if !strings.Contains(result, "KS\\-A") && !strings.Contains(result, "KS\\-B") {
+		t.Errorf("result contains no offer from the pool: %q", result)
+	}
+	if err := tgfmt.ValidateMarkdownV2(result); err != nil {
+		t.Errorf("result is not valid MarkdownV2: %v", err)
+	}
+}
+
+// TestHandleLuckyServer_Unauthorized verifies the OVH API isn't called for unknown users
+func TestHandleLuckyServer_Unauthorized(t *testing.T) {
+	original := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		t.Errorf("getTopOffers called for unauthorized user")
+		return nil, nil
+	}
+	defer func() { getTopOffers = original }()
+
+	sender := &recordingSender{}
+	HandleLuckyServer(context.Background(), sender, createTestMessage("/lucky_server", 99999), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 1 || !strings.Contains(messages[0].Text, "only available to authorized users") {
+		t.Errorf("unauthorized reply = %+v, want one authorization error", messages)
+	}
+}
+
+// TestHandleDedicatedOVHCheck tests the "🔵 Dedicated Servers" button
+//
+// Cases:
+//   - Authorized: the dedicated fetch (not the ECO one) gets the usual query,
+//     its offers are sent after the status message
+//   - Unauthorized: no fetch, one authorization error
+func TestHandleDedicatedOVHCheck(t *testing.T) {
+	oldDedicated, oldECO := getTopDedicatedOffers, getTopOffers
+	defer func() { getTopDedicatedOffers, getTopOffers = oldDedicated, oldECO }()
+
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		t.Errorf("ECO offers fetched for the dedicated button")
+		return nil, nil
+	}
+	var calls int
+	var query string
+	getTopDedicatedOffers = func(ctx context.Context, subsidiary, datacenter string, top int) ([]ovh.Offer, error) {
+		calls++
+		query = fmt.Sprintf("%s/%s/%d", subsidiary, datacenter, top)
+		return []ovh.Offer{{PlanCode: "adv-1", InvoiceName: "ADVANCE-1", Price: 80, Currency: "EUR", FQN: "adv-1.fqn"}}, nil
+	}
+
+	sender := &recordingSender{}
+	RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage("🔵 Dedicated Servers", 12345)}, testConfig())
+
+	if want := fmt.Sprintf("%s/%s/%d", ovhSubsidiary, ovhDatacenter, ovhTop); calls != 1 || query != want {
+		t.Errorf("dedicated fetch: %d calls with %q, want 1 with %q", calls, query, want)
+	}
+	messages := sender.messages()
+	if len(messages) != 2 || !strings.Contains(messages[1].Text, "ADVANCE") {
+		t.Fatalf("messages = %+v, want status + results with ADVANCE-1", messages)
+	}
+
+	sender = &recordingSender{}
+	HandleDedicatedOVHCheck(context.Background(), sender, createTestMessage("🔵 Dedicated Servers", 99999), testConfig())
+	messages = sender.messages()
+	if calls != 1 || len(messages) != 1 || !strings.Contains(messages[0].Text, "only available to authorized users") {
+		t.Errorf("unauthorized: %d fetches, replies %+v; want no fetch and one authorization error", calls-1, messages)
+	}
+}
+
+// TestHandleOVHCheck_NoOffersVsError tests that "nothing in stock" and "OVH down" get different replies
+//
+// Cases:
+//   - Fetcher returns an empty list: friendly "check back later" (ovh.ErrNoOffers)
+//   - Fetcher returns an error: "service unavailable"
+//
+// Both: status message + exactly one reply, no (empty) results list.
+func TestHandleOVHCheck_NoOffersVsError(t *testing.T) {
+	tests := []struct {
+		name     string
+		offers   []ovh.Offer
+		err      error
+		wantText string
+		notText  string
+	}{
+		{
+			name:     "no offers",
+			offers:   []ovh.Offer{},
+			wantText: "Nothing in stock in London, UK right now. Check back later!",
+			notText:  "unavailable",
+		},
+		{
+			name:     "upstream failure",
+			err:      errors.New("ovh: 503 Service Unavailable"),
+			wantText: "OVH service unavailable. Please try again later.",
+			notText:  "Check back later",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := getTopOffers
+			getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+				return tt.offers, tt.err
+			}
+			defer func() { getTopOffers = original }()
+
+			sender := &recordingSender{}
+			HandleOVHCheck(context.Background(), sender, createTestMessage("🖥️ OVH Servers", 12345), testConfig())
+
+			messages := sender.messages()
+			if len(messages) != 2 {
+				t.Fatalf("sent %d messages, want status + one reply", len(messages))
+			}
+			reply := messages[1].Text
+			if !strings.Contains(reply, tgfmt.EscapeMarkdownV2(tt.wantText)) || strings.Contains(reply, tt.notText) {
+				t.Errorf("reply = %q, want %q", reply, tt.wantText)
+			}
+		})
+	}
+}
+
+// TestHandleOVHCheck_Timeout tests that OVH_TIMEOUT bounds the fetch
+// A fetcher that only returns when its context ends gets the "too long" reply
+// within the configured timeout.
+func TestHandleOVHCheck_Timeout(t *testing.T) {
+	original := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		<-ctx.Done()
+		return nil, fmt.Errorf("request failed: %w", ctx.Err())
+	}
+	defer func() { getTopOffers = original }()
+
+	cfg := testConfig()
+	cfg.OVHTimeout = 20 * time.Millisecond
+
+	sender := &recordingSender{}
+	start := time.Now()
+	HandleOVHCheck(context.Background(), sender, createTestMessage("/ovh", 12345), cfg)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("HandleOVHCheck() took %v, want about %v", elapsed, cfg.OVHTimeout)
+	}
+	messages := sender.messages()
+	if len(messages) != 2 || !strings.Contains(messages[1].Text, "taking too long to answer") {
+		t.Errorf("messages = %+v, want status + timeout reply", messages)
+	}
+}
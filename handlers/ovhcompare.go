@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// compareCatalogs fetches ECO and Advance offers side by side
+// Declared as var so tests can replace it and avoid real OVH API calls
+var compareCatalogs = ovh.CompareEcoAdvanceContext
+
+// HandleOVHCompare handles the /compare_catalogs command.
+// Shows the cheapest ECO and Advance servers in two sections, so users can
+// see the price/performance trade-off between the two OVH product lines.
+//
+// Why compare_catalogs and not compare-catalogs?
+//   - Telegram commands may only contain letters, digits and underscores
+//   - "/compare-catalogs" would be parsed as the command "/compare"
+//
+// Authorization:
+//   - Same rules as HandleOVHCheck (only users in ALLOWED_USERS)
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleOVHCompare(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	var eco, advance []ovh.Offer
+
+	// Steps 1-3: Authorization, status message and OVH fetch (cancellable)
+	ok := runOVHFetch(ctx, bot, message, cfg, "compare_catalogs", func(ctx context.Context) error {
+		log.Info("Comparing OVH catalogs",
+			"subsidiary", ovhSubsidiary,
+			"datacenter", ovhDatacenter,
+			"top", ovhTop)
+
+		var err error
+		eco, advance, err = compareCatalogs(ctx, ovhSubsidiary, ovhDatacenter, ovhTop)
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	// Step 4: Format and send both sections in one message
+	msg := replyTo(message, formatCatalogComparison(userLanguage(ctx, message.From), eco, advance, ovhDatacenter))
+	msg.DisableWebPagePreview = true
+
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send OVH catalog comparison",
+			"error", err,
+			"message_type", messageType(msg))
+		return
+	}
+
+	log.Info("OVH catalog comparison sent successfully",
+		"eco_count", len(eco),
+		"advance_count", len(advance))
+}
+
+// formatCatalogComparison formats ECO and Advance offers as two sections
+//
+// Parameters:
+//   - lang: Language of the titles and footer (see userLanguage)
+//   - eco: Cheapest ECO offers
+//   - advance: Cheapest Advance offers
+//   - datacenter: Datacenter code that was queried
+//
+// Returns:
+//   - string: Formatted message with MarkdownV2 escaping
+func formatCatalogComparison(lang string, eco, advance []ovh.Offer, datacenter string) string {
+	location := ovh.DatacenterName(datacenter)
+
+	var builder strings.Builder
+	builder.WriteString("⚖️ " + tgfmt.Bold(translate(lang, "ovh_compare_title")) + "\n")
+	builder.WriteString(tgfmt.Italic(translate(lang, "ovh_compare_subtitle", ovhTop, location)) + "\n\n")
+
+	writeOfferSection(&builder, lang, "💚 "+translate(lang, "ovh_compare_eco"), eco)
+	builder.WriteString("\n")
+	writeOfferSection(&builder, lang, "💙 "+translate(lang, "ovh_compare_advance"), advance)
+
+	builder.WriteString("\n" + tgfmt.Italic(translate(lang, "ovh_footer")))
+
+	return builder.String()
+}
+
+// writeOfferSection writes a bold title followed by numbered offers
+// Empty sections get a short "none available" line (in lang) instead of a list
+func writeOfferSection(builder *strings.Builder, lang, title string, offers []ovh.Offer) {
+	builder.WriteString(tgfmt.Bold(title) + "\n")
+
+	if len(offers) == 0 {
+		builder.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "ovh_compare_none")) + "\n")
+		return
+	}
+
+	for i, offer := range offers {
+		builder.WriteString(ovh.FormatOfferForTelegram(offer, i+1) + "\n")
+	}
+}
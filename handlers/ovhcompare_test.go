@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestFormatCatalogComparison tests the two-section comparison message
+//
+// What we're testing:
+//   - Both section headers are present, ECO before Advance
+//   - Each section lists its own offers
+//   - Empty sections say so instead of showing an empty list
+//   - Output is valid MarkdownV2
+func TestFormatCatalogComparison(t *testing.T) {
+	ecoOffer := ovh.Offer{FQN: "eco.fqn", Price: 9.99, Currency: "EUR", InvoiceName: "KS-1", Datacenter: "lon"}
+	advOffer := ovh.Offer{FQN: "adv.fqn", Price: 79.99, Currency: "EUR", InvoiceName: "ADV-1", Datacenter: "lon"}
+
+	tests := []struct {
+		name        string
+		eco         []ovh.Offer
+		advance     []ovh.Offer
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:     "both catalogs",
+			eco:      []ovh.Offer{ecoOffer},
+			advance:  []ovh.Offer{advOffer},
+			contains: []string{"💚 ECO Servers", "💙 Advance Servers", "KS\\-1", "ADV\\-1"},
+		},
+		{
+			name:        "empty advance",
+			eco:         []ovh.Offer{ecoOffer},
+			advance:     nil,
+			contains:    []string{"KS\\-1", "No servers available\\."},
+			notContains: []string{"ADV\\-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatCatalogComparison("en", tt.eco, tt.advance, "lon")
+
+			for _, s := range tt.contains {
+				if !strings.Contains(result, s) {
+					t.Errorf("result missing %q\n\nGot:\n%s", s, result)
+				}
+			}
+			for _, s := range tt.notContains {
+				if strings.Contains(result, s) {
+					t.Errorf("result should not contain %q\n\nGot:\n%s", s, result)
+				}
+			}
+
+			if strings.Index(result, "ECO Servers") > strings.Index(result, "Advance Servers") {
+				t.Errorf("ECO section should come before Advance section")
+			}
+
+			if err := tgfmt.ValidateMarkdownV2(result); err != nil {
+				t.Errorf("formatCatalogComparison() produced invalid MarkdownV2: %v", err)
+			}
+		})
+	}
+}
+
+// TestRouteUpdate_CompareCatalogs tests /compare_catalogs end to end with a stubbed fetch
+func TestRouteUpdate_CompareCatalogs(t *testing.T) {
+	oldCompare := compareCatalogs
+	compareCatalogs = func(ctx context.Context, subsidiary, datacenter string, top int) ([]ovh.Offer, []ovh.Offer, error) {
+		return []ovh.Offer{{InvoiceName: "KS-1", Price: 9.99, Currency: "EUR"}},
+			[]ovh.Offer{{InvoiceName: "ADV-1", Price: 79.99, Currency: "EUR"}}, nil
+	}
+	defer func() { compareCatalogs = oldCompare }()
+
+	t.Run("authorized user gets both sections", func(t *testing.T) {
+		sender := &recordingSender{}
+		RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage("/compare_catalogs", 12345)}, testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 2 {
+			t.Fatalf("sent %d messages, want 2 (status + comparison)", len(messages))
+		}
+		if !strings.Contains(messages[1].Text, "ADV\\-1") {
+			t.Errorf("comparison message missing Advance offer:\n%s", messages[1].Text)
+		}
+	})
+
+	t.Run("unauthorized user is rejected", func(t *testing.T) {
+		sender := &recordingSender{}
+		RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: createTestMessage("/compare_catalogs", 99999)}, testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 1 || strings.Contains(messages[0].Text, "ECO") {
+			t.Errorf("unauthorized user got %+v, want a single rejection", messages)
+		}
+	})
+}
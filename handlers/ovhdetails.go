@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Offer details: OVH results carry one "ℹ️ N" inline button per offer,
+// with callback_data "ovh:detail:<N>:<planCode>" (see callbackActions).
+// N picks the listed configuration: a plan code can be listed several times.
+const (
+	ovhCallbackPrefix = "ovh"
+	ovhDetailAction   = "detail"
+
+	// ovhDetailButtonsPerRow is the width of the "ℹ️ N" button rows (4 rows for 20 offers)
+	ovhDetailButtonsPerRow = 5
+
+	// callbackDataLimit is Telegram's maximum callback_data size in bytes
+	callbackDataLimit = 64
+
+	// Caps of the "Options" section: some plans have a dozen families with
+	// many options each, which would push the message past Telegram's limit
+	ovhDetailMaxFamilies = 6
+	ovhDetailMaxOptions  = 5 // per family
+)
+
+// getOfferByPlanCode fetches one plan's price breakdown
+// Declared as var so tests can replace it and avoid real OVH API calls
+var getOfferByPlanCode = ovh.GetOfferByPlanCodeContext
+
+// ovhResultTTL is how long listed offers are remembered for their "ℹ️ N" buttons
+// After that, a button still works, but shows the plan's cheapest configuration
+// instead of the one that was listed (a plan code can have several).
+// Buttons from before N was added to the callback_data behave the same way.
+var ovhResultTTL = 30 * time.Minute
+
+// ovhResultSet is the offers last listed in one chat
+type ovhResultSet struct {
+	offers []ovh.Offer
+	at     time.Time // When they were listed
+}
+
+// ovhResults remembers the last OVH results of every chat
+// Safe for concurrent use: updates are handled in parallel.
+var ovhResults = struct {
+	mu    sync.Mutex
+	chats map[int64]ovhResultSet
+	now   func() time.Time // Replaced in tests
+}{chats: make(map[int64]ovhResultSet), now: time.Now}
+
+// rememberOVHResults stores the offers just listed in a chat,
+// replacing the chat's previous results
+func rememberOVHResults(chatID int64, offers []ovh.Offer) {
+	ovhResults.mu.Lock()
+	defer ovhResults.mu.Unlock()
+
+	now := ovhResults.now()
+	// Drop expired chats so the map doesn't grow forever
+	for id, set := range ovhResults.chats {
+		if now.Sub(set.at) >= ovhResultTTL {
+			delete(ovhResults.chats, id)
+		}
+	}
+	ovhResults.chats[chatID] = ovhResultSet{offers: offers, at: now}
+}
+
+// lookupOVHResult finds a listed offer by its number in the list
+//
+// Parameters:
+//   - chatID: Chat the results were listed in
+//   - number: The offer's number in the list (1-based, as on its button)
+//   - planCode: Plan code from the button, checked against the listed offer
+//
+// Returns:
+//   - ovh.Offer: The listed offer
+//   - bool: false if the chat's results don't have it, have expired (ovhResultTTL)
+//     or have been replaced by a list where offer N is another plan
+func lookupOVHResult(chatID int64, number int, planCode string) (ovh.Offer, bool) {
+	ovhResults.mu.Lock()
+	defer ovhResults.mu.Unlock()
+
+	set, ok := ovhResults.chats[chatID]
+	if !ok || ovhResults.now().Sub(set.at) >= ovhResultTTL {
+		return ovh.Offer{}, false
+	}
+	if number < 1 || number > len(set.offers) || set.offers[number-1].PlanCode != planCode {
+		return ovh.Offer{}, false
+	}
+	return set.offers[number-1], true
+}
+
+// ovhDetailData is the callback_data of the "ℹ️ N" button of offer number N
+func ovhDetailData(number int, planCode string) string {
+	return ovhCallbackPrefix + ":" + ovhDetailAction + ":" + strconv.Itoa(number) + ":" + planCode
+}
+
+// parseOVHDetailData splits the argument of "ovh:detail:<N>:<planCode>"
+//
+// Returns:
+//   - int: Offer number N (0 for buttons without one, sent before N was added)
+//   - string: Plan code
+func parseOVHDetailData(argument string) (int, string) {
+	if numberText, planCode, ok := strings.Cut(argument, ":"); ok {
+		if number, err := strconv.Atoi(numberText); err == nil {
+			return number, planCode
+		}
+	}
+	return 0, argument
+}
+
+// ovhDetailsKeyboard builds the "ℹ️ N" buttons of a results list
+// Button N opens the details of offer N, ovhDetailButtonsPerRow per row.
+//
+// Parameters:
+//   - offers: Listed offers, in list order
+//
+// Returns:
+//   - *tgbotapi.InlineKeyboardMarkup: The keyboard (nil if no offer gets a button;
+//     plan codes too long for callback_data are skipped)
+func ovhDetailsKeyboard(offers []ovh.Offer) *tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, offer := range offers {
+		data := ovhDetailData(i+1, offer.PlanCode)
+		if offer.PlanCode == "" || len(data) > callbackDataLimit {
+			continue
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("ℹ️ "+strconv.Itoa(i+1), data))
+		if len(row) == ovhDetailButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// handleOVHCallback dispatches "ovh:<action>:<argument>" callbacks
+// Only "detail" exists; other actions (e.g., from a newer bot version) just clear the spinner.
+func handleOVHCallback(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, cfg *config.Config) {
+	_, rest, _ := strings.Cut(query.Data, ":")
+	action, argument, _ := strings.Cut(rest, ":")
+
+	switch action {
+	case ovhDetailAction:
+		number, planCode := parseOVHDetailData(argument)
+		handleOVHDetail(ctx, bot, query, cfg, number, planCode)
+	default:
+		logger.FromContext(ctx).Warn("Unknown OVH callback action",
+			"data", query.Data)
+		answerCallback(ctx, bot, query, "")
+	}
+}
+
+// handleOVHDetail answers an "ℹ️ N" click with the offer's details:
+// base price, each mandatory addon with its price, availability in every
+// datacenter and the raw FQN.
+//
+// Lookup:
+//   - The configuration (FQN) is that of offer N in the chat's remembered results
+//   - Once those have expired (ovhResultTTL), the plan's cheapest configuration is shown
+//   - Prices and availability are current: OVH data comes from the ovh package
+//     cache while fresh (5 minutes), otherwise it is fetched again
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for the answer and the details message
+//   - query: Callback query of the click
+//   - cfg: Application configuration (authorization, OVH feature flag, OVHTimeout)
+//   - number: Offer number from the callback_data (0 if the button has none)
+//   - planCode: Plan code from the callback_data
+func handleOVHDetail(ctx context.Context, bot BotSender, query *tgbotapi.CallbackQuery, cfg *config.Config, number int, planCode string) {
+	log := logger.FromContext(ctx).With("plan_code", planCode)
+	const feature = "ovh_detail"
+	lang := userLanguage(ctx, query.From)
+
+	// Same gates as the list: feature flag and authorization
+	if !cfg.Features.Enabled(config.FeatureOVH) {
+		answerCallback(ctx, bot, query, "")
+		return
+	}
+	allowed := query.From != nil && cfg.IsUserAllowed(query.From.ID, query.From.UserName)
+	recordAudit(ctx, query.From, feature, planCode, allowed)
+	if !allowed {
+		log.Info("Unauthorized OVH details click")
+		handlerInvocations.Inc(feature, resultUnauthorized)
+		answerCallback(ctx, bot, query, translate(lang, "unauthorized_short"))
+		return
+	}
+	if query.Message == nil || planCode == "" {
+		// No chat to reply in (button of an inline message) or a malformed button
+		answerCallback(ctx, bot, query, translate(lang, "ovh_detail_expired"))
+		return
+	}
+
+	// Answer right away: a fetch can take seconds, the spinner shouldn't wait for it
+	answerCallback(ctx, bot, query, "")
+
+	chatID := query.Message.Chat.ID
+	fqn := ""
+	if offer, ok := lookupOVHResult(chatID, number, planCode); ok {
+		fqn = offer.FQN
+	}
+
+	fetchCtx := ctx
+	if cfg.OVHTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, cfg.OVHTimeout)
+		defer cancel()
+	}
+
+	details, err := getOfferByPlanCode(fetchCtx, ovhSubsidiary, planCode, fqn)
+	var text string
+	switch {
+	case errors.Is(err, ovh.ErrOfferNotFound):
+		log.Info("OVH offer for details no longer in the catalog")
+		handlerInvocations.Inc(feature, resultSuccess)
+		text = tgfmt.EscapeMarkdownV2(translate(lang, "ovh_detail_gone", planCode))
+	case err != nil:
+		log.Error("Failed to fetch OVH offer details",
+			"error", err)
+		handlerInvocations.Inc(feature, resultError)
+		text = tgfmt.EscapeMarkdownV2(translate(lang, "ovh_unavailable"))
+	default:
+		handlerInvocations.Inc(feature, resultSuccess)
+		text = formatOfferDetails(lang, details)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyToMessageID = query.Message.MessageID
+	msg.DisableWebPagePreview = true
+	if _, err := sendFormatted(ctx, bot, msg); err != nil {
+		log.Error("Failed to send OVH offer details",
+			"error", err,
+			"message_type", messageType(msg))
+		return
+	}
+
+	log.Info("OVH offer details sent",
+		"fqn", details.FQN,
+		"remembered", fqn != "")
+}
+
+// formatOfferDetails formats an offer's details (MarkdownV2)
+//
+// Example:
+//
+//	ℹ️ KS-LE-1
+//	Plan code: 25skle01 (eco catalog)
+//
+//	Base price: 15.99 EUR/mo
+//	+ bandwidth: 300 Mbps (bandwidth-300-25skle) 0.00 EUR/mo
+//	+ memory: 32GB DDR4 (ram-32g-25skle) 4.00 EUR/mo
+//	Total: 19.99 EUR/mo
+//
+//	Options:
+//	memory (required):
+//	• 32GB DDR4 (ram-32g-25skle) 4.00 EUR/mo ✓
+//	• 64GB DDR4 (ram-64g-25skle) 12.00 EUR/mo
+//	bandwidth (optional):
+//	• 1 Gbps (bandwidth-1000-25skle) price unavailable
+//
+//	Availability:
+//	• London, UK: delivery 1h
+//	• Roubaix, France: unavailable
+//
+//	FQN: 25skle01.ram-32g-25skle.softraid-2x2000sa
+//
+// Labels are in lang (see userLanguage); OVH names and codes are kept as is.
+func formatOfferDetails(lang string, details ovh.OfferDetails) string {
+	price := func(p float64) string {
+		return translate(lang, "ovh_detail_monthly", strconv.FormatFloat(p, 'f', 2, 64), details.Currency)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("ℹ️ " + tgfmt.Bold(details.InvoiceName) + "\n")
+	sb.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "ovh_detail_plan", details.PlanCode, details.CatalogName)) + "\n\n")
+
+	sb.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "ovh_detail_base_price", price(details.BasePrice))) + "\n")
+	for _, addon := range details.AddonPrices {
+		name := addon.PlanCode
+		if addon.Name != "" {
+			name = addon.Name + " (" + addon.PlanCode + ")"
+		}
+		sb.WriteString(tgfmt.EscapeMarkdownV2(fmt.Sprintf("+ %s: %s %s", addon.Family, name, price(addon.Price))) + "\n")
+	}
+	sb.WriteString(tgfmt.Bold(translate(lang, "ovh_detail_total", price(details.Price))) + "\n\n")
+	sb.WriteString(formatAddonOptions(lang, details, price))
+
+	sb.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "ovh_detail_availability")) + "\n")
+	if len(details.Datacenters) == 0 {
+		sb.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "ovh_detail_not_listed")) + "\n")
+	}
+	for _, dc := range details.Datacenters {
+		sb.WriteString(tgfmt.EscapeMarkdownV2(fmt.Sprintf("• %s: %s", ovh.DatacenterName(dc.Datacenter), ovh.AvailabilityLabel(dc.Availability))) + "\n")
+	}
+
+	sb.WriteString("\n" + tgfmt.EscapeMarkdownV2("FQN: ") + tgfmt.Code(details.FQN))
+	return sb.String()
+}
+
+// formatAddonOptions formats the "Options" section of the details (MarkdownV2)
+// See formatOfferDetails for an example; "" if the plan has no addon families.
+//
+// At most ovhDetailMaxFamilies families with ovhDetailMaxOptions options each
+// are shown, followed by "… N more". The options of the shown configuration
+// are marked with ✓.
+//
+// Parameters:
+//   - lang: Language of the labels (see userLanguage)
+//   - details: Offer details (AddonOptions, and Addons for the ✓ marks)
+//   - price: Formats a monthly price ("4.00 EUR/mo")
+func formatAddonOptions(lang string, details ovh.OfferDetails, price func(float64) string) string {
+	if len(details.AddonOptions) == 0 {
+		return ""
+	}
+
+	var lines []string
+	lines = append(lines, translate(lang, "ovh_detail_options"))
+	for i, family := range details.AddonOptions {
+		if i == ovhDetailMaxFamilies {
+			lines = append(lines, translate(lang, "ovh_detail_more_families", len(details.AddonOptions)-i))
+			break
+		}
+
+		kind := translate(lang, "ovh_detail_optional")
+		if family.Mandatory {
+			kind = translate(lang, "ovh_detail_required")
+		}
+		lines = append(lines, family.Family+" ("+kind+"):")
+
+		for j, option := range family.Options {
+			if j == ovhDetailMaxOptions {
+				lines = append(lines, translate(lang, "ovh_detail_more_options", len(family.Options)-j))
+				break
+			}
+
+			line := "• " + option.PlanCode
+			if option.Name != "" {
+				line = "• " + option.Name + " (" + option.PlanCode + ")"
+			}
+			if option.Priced {
+				line += " " + price(option.Price)
+			} else {
+				line += " " + translate(lang, "ovh_detail_no_price")
+			}
+			if details.Addons[family.Family] == option.PlanCode {
+				line += " ✓"
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return tgfmt.EscapeMarkdownV2(strings.Join(lines, "\n")) + "\n\n"
+}
@@ -0,0 +1,411 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// withOVHResults gives a test empty remembered results and a settable clock
+func withOVHResults(t *testing.T) *time.Time {
+	t.Helper()
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	ovhResults.mu.Lock()
+	oldChats, oldNow := ovhResults.chats, ovhResults.now
+	ovhResults.chats = make(map[int64]ovhResultSet)
+	ovhResults.now = func() time.Time { return now }
+	ovhResults.mu.Unlock()
+
+	t.Cleanup(func() {
+		ovhResults.mu.Lock()
+		ovhResults.chats, ovhResults.now = oldChats, oldNow
+		ovhResults.mu.Unlock()
+	})
+	return &now
+}
+
+// detailClick is a click on an "ℹ️ N" button under a results message
+func detailClick(data string, userID int64) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "cb-1",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: 100, Type: "private"}},
+		Data:    data,
+	}
+}
+
+// TestOVHDetailsKeyboard tests the "ℹ️ N" buttons under OVH results
+//
+// Cases:
+//   - One button per offer, numbered like the list, 5 per row
+//   - Plan codes too long for callback_data: no button (numbers stay aligned)
+//   - No offers: no keyboard
+func TestOVHDetailsKeyboard(t *testing.T) {
+	var offers []ovh.Offer
+	for i := range 7 {
+		offers = append(offers, ovh.Offer{PlanCode: fmt.Sprintf("24sk%02d", i+1)})
+	}
+	offers[5].PlanCode = strings.Repeat("x", callbackDataLimit)
+
+	keyboard := ovhDetailsKeyboard(offers)
+	if keyboard == nil || len(keyboard.InlineKeyboard) != 2 {
+		t.Fatalf("keyboard = %+v, want 2 rows", keyboard)
+	}
+	if first := keyboard.InlineKeyboard[0]; len(first) != ovhDetailButtonsPerRow {
+		t.Errorf("first row has %d buttons, want %d", len(first), ovhDetailButtonsPerRow)
+	}
+	last := keyboard.InlineKeyboard[1]
+	if len(last) != 1 || last[0].Text != "ℹ️ 7" || *last[0].CallbackData != "ovh:detail:7:24sk07" {
+		t.Errorf("last row = %+v, want [ℹ️ 7 -> ovh:detail:7:24sk07]", last)
+	}
+
+	if keyboard := ovhDetailsKeyboard(nil); keyboard != nil {
+		t.Errorf("ovhDetailsKeyboard(nil) = %+v, want nil", keyboard)
+	}
+}
+
+// TestOVHResultsLookup tests the lookup of listed offers by number
+//
+// Cases:
+//   - The same plan listed twice: each number finds its own configuration
+//   - Another chat, another plan at that number, a number out of range or
+//     a button without a number: not found
+//   - Remembered results expire after ovhResultTTL
+func TestOVHResultsLookup(t *testing.T) {
+	now := withOVHResults(t)
+
+	rememberOVHResults(1, []ovh.Offer{
+		{PlanCode: "24sk10", FQN: "24sk10.ram-32g"},
+		{PlanCode: "24sk10", FQN: "24sk10.ram-64g"},
+	})
+
+	if offer, ok := lookupOVHResult(1, 1, "24sk10"); !ok || offer.FQN != "24sk10.ram-32g" {
+		t.Errorf("lookupOVHResult(1, 1, 24sk10) = %+v, %v, want the first listed offer", offer, ok)
+	}
+	if offer, ok := lookupOVHResult(1, 2, "24sk10"); !ok || offer.FQN != "24sk10.ram-64g" {
+		t.Errorf("lookupOVHResult(1, 2, 24sk10) = %+v, %v, want the second listed offer", offer, ok)
+	}
+	if _, ok := lookupOVHResult(2, 1, "24sk10"); ok {
+		t.Error("lookupOVHResult found another chat's offer")
+	}
+	if _, ok := lookupOVHResult(1, 1, "24sk99"); ok {
+		t.Error("lookupOVHResult found an offer that wasn't listed")
+	}
+	for _, number := range []int{0, 3} {
+		if _, ok := lookupOVHResult(1, number, "24sk10"); ok {
+			t.Errorf("lookupOVHResult found offer number %d of a 2 offer list", number)
+		}
+	}
+
+	*now = now.Add(ovhResultTTL)
+	if _, ok := lookupOVHResult(1, 1, "24sk10"); ok {
+		t.Error("lookupOVHResult found an expired offer")
+	}
+
+	// The next results sweep the expired chat
+	rememberOVHResults(2, nil)
+	ovhResults.mu.Lock()
+	_, kept := ovhResults.chats[1]
+	ovhResults.mu.Unlock()
+	if kept {
+		t.Error("expired results were not swept")
+	}
+}
+
+// TestRouteUpdate_OVHDetail tests "ovh:detail:<N>:<planCode>" clicks through the router
+//
+// Cases:
+//   - Listed offer: its FQN is looked up, details reply to the results message
+//   - Same plan listed twice: the clicked configuration is shown
+//   - Button without a number (older bot version): cheapest configuration
+//   - Expired results: looked up by plan code only (cheapest configuration)
+//   - Plan withdrawn: "no longer in the catalog"
+//   - OVH failing: error reply
+//   - Unauthorized: answered with an error notice, nothing fetched
+//   - Unknown OVH action: spinner cleared, nothing sent
+func TestRouteUpdate_OVHDetail(t *testing.T) {
+	now := withOVHResults(t)
+
+	type lookup struct{ planCode, fqn string }
+	var got []lookup
+	var fetchErr error
+	oldGetOffer := getOfferByPlanCode
+	getOfferByPlanCode = func(ctx context.Context, subsidiary, planCode, fqn string) (ovh.OfferDetails, error) {
+		got = append(got, lookup{planCode, fqn})
+		if fetchErr != nil {
+			return ovh.OfferDetails{}, fetchErr
+		}
+		return ovh.OfferDetails{
+			Offer:       ovh.Offer{PlanCode: planCode, FQN: planCode + ".fqn", InvoiceName: "KS-LE-1", Price: 19.99, Currency: "EUR"},
+			CatalogName: "eco",
+			BasePrice:   19.99,
+		}, nil
+	}
+	defer func() { getOfferByPlanCode = oldGetOffer }()
+
+	tests := []struct {
+		name       string
+		data       string
+		userID     int64
+		expire     bool
+		fetchErr   error
+		wantAnswer string
+		wantLookup []lookup
+		wantText   string // Substring of the details message ("" = nothing sent)
+	}{
+		{
+			name: "listed offer", data: "ovh:detail:1:25skle01", userID: 12345,
+			wantLookup: []lookup{{"25skle01", "25skle01.ram-32g"}}, wantText: "*KS\\-LE\\-1*",
+		},
+		{
+			name: "expired results", data: "ovh:detail:1:25skle01", userID: 12345, expire: true,
+			wantLookup: []lookup{{"25skle01", ""}}, wantText: "`25skle01.fqn`",
+		},
+		{
+			name: "plan withdrawn", data: "ovh:detail:1:25skle01", userID: 12345,
+			fetchErr:   fmt.Errorf("%w: 25skle01", ovh.ErrOfferNotFound),
+			wantLookup: []lookup{{"25skle01", "25skle01.ram-32g"}}, wantText: "no longer in OVH's catalog",
+		},
+		{
+			name: "OVH failing", data: "ovh:detail:1:25skle01", userID: 12345, fetchErr: errors.New("boom"),
+			wantLookup: []lookup{{"25skle01", "25skle01.ram-32g"}}, wantText: "OVH service unavailable",
+		},
+		{name: "unauthorized", data: "ovh:detail:1:25skle01", userID: 99999, wantAnswer: "⛔ Only available to authorized users."},
+		{
+			name: "same plan listed twice", data: "ovh:detail:2:25skle01", userID: 12345,
+			wantLookup: []lookup{{"25skle01", "25skle01.ram-64g"}}, wantText: "*KS\\-LE\\-1*",
+		},
+		{
+			name: "button without number", data: "ovh:detail:25skle01", userID: 12345,
+			wantLookup: []lookup{{"25skle01", ""}}, wantText: "`25skle01.fqn`",
+		},
+		{name: "unknown action", data: "ovh:refresh:25skle01", userID: 12345},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fetchErr = nil, tt.fetchErr
+			rememberOVHResults(100, []ovh.Offer{
+				{PlanCode: "25skle01", FQN: "25skle01.ram-32g"},
+				{PlanCode: "25skle01", FQN: "25skle01.ram-64g"},
+			})
+			if tt.expire {
+				*now = now.Add(ovhResultTTL)
+			}
+
+			sender := &recordingSender{}
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, CallbackQuery: detailClick(tt.data, tt.userID)}, testConfig())
+
+			if len(sender.requested) != 1 {
+				t.Fatalf("got %d requests, want 1 answerCallbackQuery", len(sender.requested))
+			}
+			if answer := sender.requested[0].(tgbotapi.CallbackConfig); answer.Text != tt.wantAnswer {
+				t.Errorf("answer = %q, want %q", answer.Text, tt.wantAnswer)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantLookup) {
+				t.Errorf("lookups = %v, want %v", got, tt.wantLookup)
+			}
+
+			messages := sender.messages()
+			if tt.wantText == "" {
+				if len(messages) != 0 {
+					t.Errorf("sent %+v, want nothing", messages)
+				}
+				return
+			}
+			if len(messages) != 1 {
+				t.Fatalf("sent %d messages, want 1", len(messages))
+			}
+			msg := messages[0]
+			if msg.ChatID != 100 || msg.ReplyToMessageID != 7 || !strings.Contains(msg.Text, tt.wantText) {
+				t.Errorf("message = {chat %d, reply to %d, %q}, want chat 100, reply to 7, containing %q",
+					msg.ChatID, msg.ReplyToMessageID, msg.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+// TestFormatOfferDetails tests the details message
+//
+// Cases:
+//   - Addons: name, plan code and price each, sorted by family; total in bold
+//   - Every datacenter with its availability, full names
+//   - No availability: says so
+//   - MarkdownV2 characters in names and FQN are escaped
+func TestFormatOfferDetails(t *testing.T) {
+	details := ovh.OfferDetails{
+		Offer: ovh.Offer{
+			PlanCode: "25skle01", InvoiceName: "KS-LE-1 (2025)", FQN: "25skle01.ram-32g_ecc.softraid-2x2000sa",
+			Price: 21.49, Currency: "EUR",
+		},
+		CatalogName: "eco",
+		BasePrice:   15.99,
+		AddonPrices: []ovh.AddonPrice{
+			{Family: "bandwidth", PlanCode: "bandwidth-300-25skle", Name: "300 Mbps", Price: 1.5},
+			{Family: "memory", PlanCode: "ram-32g-25skle", Price: 4},
+		},
+		Datacenters: []ovh.Datacenter{{Datacenter: "lon", Availability: "1H"}, {Datacenter: "rbx", Availability: "unavailable"}},
+	}
+
+	tests := []struct {
+		name        string
+		datacenters []ovh.Datacenter
+		want        []string
+	}{
+		{
+			name:        "available",
+			datacenters: details.Datacenters,
+			want: []string{
+				"ℹ️ *KS\\-LE\\-1 \\(2025\\)*",
+				"Plan code: 25skle01 \\(eco catalog\\)",
+				"Base price: 15\\.99 EUR/mo\n\\+ bandwidth: 300 Mbps \\(bandwidth\\-300\\-25skle\\) 1\\.50 EUR/mo\n\\+ memory: ram\\-32g\\-25skle 4\\.00 EUR/mo\n*Total: 21\\.49 EUR/mo*",
+				"• London, UK: delivery 1h\n• Roubaix, France: unavailable",
+				"FQN: `25skle01.ram-32g_ecc.softraid-2x2000sa`",
+			},
+		},
+		{
+			name:        "not available",
+			datacenters: nil,
+			want:        []string{"Availability:\nNot listed in any datacenter right now\\."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := details
+			d.Datacenters = tt.datacenters
+
+			got := formatOfferDetails("en", d)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatOfferDetails() = %q, want it to contain %q", got, want)
+				}
+			}
+			if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+				t.Errorf("formatOfferDetails() is not valid MarkdownV2: %v", err)
+			}
+		})
+	}
+}
+
+// TestFormatOfferDetails_AddonOptions tests the "Options" section
+//
+// Cases:
+//   - Required and optional families, current options marked with ✓
+//   - Options without a price: "price unavailable"
+//   - More options or families than the caps: "… N more"
+//   - No addon families: no section
+func TestFormatOfferDetails_AddonOptions(t *testing.T) {
+	manyOptions := make([]ovh.AddonOption, ovhDetailMaxOptions+2)
+	for i := range manyOptions {
+		manyOptions[i] = ovh.AddonOption{PlanCode: fmt.Sprintf("disk-%d", i), Price: float64(i), Priced: true}
+	}
+	manyFamilies := make([]ovh.AddonFamilyOptions, ovhDetailMaxFamilies+3)
+	for i := range manyFamilies {
+		manyFamilies[i] = ovh.AddonFamilyOptions{Family: fmt.Sprintf("family-%d", i)}
+	}
+
+	tests := []struct {
+		name     string
+		options  []ovh.AddonFamilyOptions
+		want     []string
+		dontWant []string
+	}{
+		{
+			name: "families",
+			options: []ovh.AddonFamilyOptions{
+				{Family: "memory", Mandatory: true, Exclusive: true, Options: []ovh.AddonOption{
+					{PlanCode: "ram-32g-25skle", Name: "32GB", Price: 4, Priced: true},
+					{PlanCode: "ram-64g-25skle", Name: "64GB", Price: 12, Priced: true},
+				}},
+				{Family: "bandwidth", Exclusive: true, Options: []ovh.AddonOption{
+					{PlanCode: "bandwidth-1000-25skle"},
+				}},
+			},
+			want: []string{
+				"Options:\nmemory \\(required\\):\n• 32GB \\(ram\\-32g\\-25skle\\) 4\\.00 EUR/mo ✓\n• 64GB \\(ram\\-64g\\-25skle\\) 12\\.00 EUR/mo\n",
+				"bandwidth \\(optional\\):\n• bandwidth\\-1000\\-25skle price unavailable\n\nAvailability:",
+			},
+		},
+		{
+			name:     "too many options",
+			options:  []ovh.AddonFamilyOptions{{Family: "storage", Options: manyOptions}},
+			want:     []string{"• disk\\-4 4\\.00 EUR/mo\n… 2 more\n"},
+			dontWant: []string{"disk\\-5"},
+		},
+		{
+			name:     "too many families",
+			options:  manyFamilies,
+			want:     []string{"family\\-5 \\(optional\\):\n… 3 more families\n"},
+			dontWant: []string{"family\\-6"},
+		},
+		{
+			name:     "no families",
+			options:  nil,
+			dontWant: []string{"Options:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := ovh.OfferDetails{
+				Offer: ovh.Offer{
+					PlanCode: "25skle01", InvoiceName: "KS-LE-1", FQN: "25skle01.ram-32g-25skle",
+					Price: 19.99, Currency: "EUR", Addons: map[string]string{"memory": "ram-32g-25skle"},
+				},
+				CatalogName:  "eco",
+				BasePrice:    15.99,
+				AddonOptions: tt.options,
+			}
+
+			got := formatOfferDetails("en", details)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatOfferDetails() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(got, dontWant) {
+					t.Errorf("formatOfferDetails() = %q, want it not to contain %q", got, dontWant)
+				}
+			}
+			if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+				t.Errorf("formatOfferDetails() is not valid MarkdownV2: %v", err)
+			}
+		})
+	}
+}
+
+// TestHandleOVHCommand_DetailsButtons tests that results carry the details
+// buttons and remember the listed offers for them
+func TestHandleOVHCommand_DetailsButtons(t *testing.T) {
+	withOVHResults(t)
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		return []ovh.Offer{{PlanCode: "25skle01", FQN: "25skle01.ram-32g", InvoiceName: "KS-LE-1", Price: 19.99, Currency: "EUR"}}, nil
+	}
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	sender := &recordingSender{}
+	HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh", 12345), testConfig())
+
+	messages := sender.messages()
+	if len(messages) != 2 {
+		t.Fatalf("sent %d messages, want status + results", len(messages))
+	}
+	keyboard, ok := messages[1].ReplyMarkup.(*tgbotapi.InlineKeyboardMarkup)
+	if !ok || len(keyboard.InlineKeyboard) != 1 || *keyboard.InlineKeyboard[0][0].CallbackData != "ovh:detail:1:25skle01" {
+		t.Errorf("results ReplyMarkup = %+v, want one ℹ️ 1 button", messages[1].ReplyMarkup)
+	}
+	if offer, ok := lookupOVHResult(12345, 1, "25skle01"); !ok || offer.FQN != "25skle01.ram-32g" {
+		t.Errorf("lookupOVHResult() = %+v, %v, want the listed offer", offer, ok)
+	}
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HandleOVHCSV handles the /ovhcsv command.
+// Sends the same OVH offers as the "🖥️ OVH Servers" button, but as a CSV file
+// attachment that can be opened in any spreadsheet application.
+//
+// Authorization:
+//   - Same rules as HandleOVHCheck (only users in ALLOWED_USERS)
+//
+// How file upload works:
+//   - tgbotapi.FileBytes wraps an in-memory []byte as an uploadable file
+//   - tgbotapi.NewDocument sends it as a document (not a photo/video)
+//   - No temporary file on disk is needed (Cloud Run filesystem is in-memory anyway)
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the /ovhcsv command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleOVHCSV(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	// Steps 1-3: Authorization, status message and OVH fetch
+	offers, ok := fetchOVHOffers(ctx, bot, message, cfg, "ovhcsv", defaultOVHQuery())
+	if !ok {
+		return
+	}
+
+	// Step 4: Build CSV document and send it
+	file := tgbotapi.FileBytes{
+		Name:  "ovh-offers.csv",
+		Bytes: offersToCSV(offers),
+	}
+
+	doc := tgbotapi.NewDocument(message.Chat.ID, file)
+	doc.Caption = translate(userLanguage(ctx, message.From), "ovh_export_caption", len(offers))
+
+	if _, err := bot.Send(doc); err != nil {
+		log.Error("Failed to send OVH CSV export",
+			"error", err,
+			"message_type", messageType(doc),
+			"offers_count", len(offers))
+		return
+	}
+
+	log.Info("OVH CSV export sent successfully",
+		"offers_count", len(offers))
+}
+
+// offersToCSV converts OVH offers to CSV bytes.
+// Columns: rank, price, currency, invoice name, FQN, plan code
+//
+// Why encoding/csv instead of fmt.Sprintf?
+//   - Invoice names may contain commas or quotes (e.g., "KS-1, 2023 edition")
+//   - csv.Writer quotes such fields automatically: "KS-1, 2023 edition"
+//   - Hand-written joining would silently produce broken rows
+//
+// Parameters:
+//   - offers: List of OVH offers (already sorted, rank = position + 1)
+//
+// Returns:
+//   - []byte: CSV document with header row, one row per offer
+func offersToCSV(offers []ovh.Offer) []byte {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	// Header row
+	// Errors from Write are reported by Error() after Flush, and writing
+	// to a bytes.Buffer cannot fail, so we don't check each call
+	_ = writer.Write([]string{"rank", "price", "currency", "invoice_name", "fqn", "plan_code"})
+
+	for i, offer := range offers {
+		_ = writer.Write([]string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(offer.Price, 'f', 2, 64),
+			offer.Currency,
+			offer.InvoiceName,
+			offer.FQN,
+			offer.PlanCode,
+		})
+	}
+
+	// Flush writes any buffered data to the underlying buffer
+	writer.Flush()
+
+	return buf.Bytes()
+}
+
+// HandleOVHJSON handles the /ovhjson command.
+// Sends the OVH offers as a pretty-printed JSON file, handy for piping into
+// tools like jq or scripts.
+//
+// Authorization:
+//   - Same rules as HandleOVHCheck (only users in ALLOWED_USERS)
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the /ovhjson command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleOVHJSON(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	// Steps 1-3: Authorization, status message and OVH fetch
+	offers, ok := fetchOVHOffers(ctx, bot, message, cfg, "ovhjson", defaultOVHQuery())
+	if !ok {
+		return
+	}
+
+	// Step 4: Build JSON document
+	data, err := offersToJSON(offers)
+	if err != nil {
+		// Marshalling plain strings and numbers should never fail,
+		// but if it does the user still deserves an answer
+		log.Error("Failed to build OVH JSON export",
+			"error", err)
+
+		errMsg := replyTo(message, translate(userLanguage(ctx, message.From), "ovh_export_failed"))
+		if _, err := sendReply(ctx, bot, message, errMsg); err != nil {
+			log.Error("Failed to send OVH JSON error message",
+				"error", err,
+				"message_type", messageType(errMsg))
+		}
+		return
+	}
+
+	// Step 5: Send JSON document
+	file := tgbotapi.FileBytes{
+		Name:  "ovh-offers.json",
+		Bytes: data,
+	}
+
+	doc := tgbotapi.NewDocument(message.Chat.ID, file)
+	doc.Caption = translate(userLanguage(ctx, message.From), "ovh_export_caption", len(offers))
+
+	if _, err := bot.Send(doc); err != nil {
+		log.Error("Failed to send OVH JSON export",
+			"error", err,
+			"message_type", messageType(doc),
+			"offers_count", len(offers))
+		return
+	}
+
+	log.Info("OVH JSON export sent successfully",
+		"offers_count", len(offers))
+}
+
+// offersExport is the documented top-level shape of the /ovhjson export.
+//
+// Example:
+//
+//	{
+//	  "count": 1,
+//	  "offers": [
+//	    {
+//	      "rank": 1,
+//	      "fqn": "1801sk12.lon.1",
+//	      "plan_code": "eco.eco-1",
+//	      "invoice_name": "ECO 1",
+//	      "price": 12.99,
+//	      "currency": "EUR",
+//	      "addons": {"bandwidth": "bandwidth-100"}
+//	    }
+//	  ]
+//	}
+//
+// Why a separate struct instead of marshalling ovh.Offer directly?
+//   - The export format is a contract with external tools
+//   - Renaming a Go field in the ovh package must not silently change the file format
+type offersExport struct {
+	Count  int           `json:"count"`
+	Offers []offerExport `json:"offers"`
+}
+
+// offerExport is one offer inside offersExport
+type offerExport struct {
+	Rank        int               `json:"rank"`         // 1-based position (cheapest first)
+	FQN         string            `json:"fqn"`          // Fully qualified server name
+	PlanCode    string            `json:"plan_code"`    // OVH catalog plan code
+	InvoiceName string            `json:"invoice_name"` // Human-readable name
+	Price       float64           `json:"price"`        // Monthly price incl. mandatory addons
+	Currency    string            `json:"currency"`     // Currency code (e.g., "EUR")
+	Addons      map[string]string `json:"addons"`       // Mandatory addons (family -> addon code)
+}
+
+// offersToJSON converts OVH offers to a pretty-printed JSON document.
+// See offersExport for the exact shape.
+//
+// Parameters:
+//   - offers: List of OVH offers (already sorted, rank = position + 1)
+//
+// Returns:
+//   - []byte: Indented JSON document
+//   - error: Marshalling error (not expected for these field types)
+func offersToJSON(offers []ovh.Offer) ([]byte, error) {
+	export := offersExport{
+		Count:  len(offers),
+		Offers: make([]offerExport, 0, len(offers)), // Empty list is [] in JSON, not null
+	}
+
+	for i, offer := range offers {
+		addons := offer.Addons
+		if addons == nil {
+			addons = map[string]string{} // Always an object, never null
+		}
+
+		export.Offers = append(export.Offers, offerExport{
+			Rank:        i + 1,
+			FQN:         offer.FQN,
+			PlanCode:    offer.PlanCode,
+			InvoiceName: offer.InvoiceName,
+			Price:       offer.Price,
+			Currency:    offer.Currency,
+			Addons:      addons,
+		})
+	}
+
+	// MarshalIndent produces human-readable output (2-space indentation)
+	return json.MarshalIndent(export, "", "  ")
+}
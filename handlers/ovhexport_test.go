@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestOffersToCSV tests the offersToCSV function.
+//
+// Testing strategy:
+//   - Parse the generated CSV back with encoding/csv
+//   - Verify header row and column order
+//   - Verify row content (rank, formatted price, names)
+//   - Verify names with commas and quotes survive the round trip
+//
+// Why parse instead of comparing raw strings?
+//   - We care that spreadsheet tools read the right values
+//   - Exact quoting style is an implementation detail of csv.Writer
+func TestOffersToCSV(t *testing.T) {
+	tests := []struct {
+		name         string
+		offers       []ovh.Offer
+		expectedRows [][]string // Expected rows after header
+	}{
+		{
+			name:         "empty offers - header only",
+			offers:       []ovh.Offer{},
+			expectedRows: [][]string{},
+		},
+		{
+			name: "single offer",
+			offers: []ovh.Offer{
+				{
+					FQN:         "1801sk12.lon.1",
+					PlanCode:    "eco.eco-1",
+					Price:       12.99,
+					Currency:    "EUR",
+					InvoiceName: "ECO 1",
+				},
+			},
+			expectedRows: [][]string{
+				{"1", "12.99", "EUR", "ECO 1", "1801sk12.lon.1", "eco.eco-1"},
+			},
+		},
+		{
+			name: "names with commas and quotes",
+			offers: []ovh.Offer{
+				{
+					FQN:         "a.fqn",
+					PlanCode:    "plan-a",
+					Price:       10,
+					Currency:    "EUR",
+					InvoiceName: "KS-1, 2023 edition",
+				},
+				{
+					FQN:         "b.fqn",
+					PlanCode:    "plan-b",
+					Price:       20.5,
+					Currency:    "EUR",
+					InvoiceName: `Server "Pro"`,
+				},
+			},
+			expectedRows: [][]string{
+				{"1", "10.00", "EUR", "KS-1, 2023 edition", "a.fqn", "plan-a"},
+				{"2", "20.50", "EUR", `Server "Pro"`, "b.fqn", "plan-b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := offersToCSV(tt.offers)
+
+			records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+			if err != nil {
+				t.Fatalf("offersToCSV() produced invalid CSV: %v\n\nGot:\n%s", err, data)
+			}
+
+			// Verify header row
+			expectedHeader := []string{"rank", "price", "currency", "invoice_name", "fqn", "plan_code"}
+			if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(expectedHeader, ",") {
+				t.Fatalf("offersToCSV() header = %v, want %v", records, expectedHeader)
+			}
+
+			// Verify data rows
+			rows := records[1:]
+			if len(rows) != len(tt.expectedRows) {
+				t.Fatalf("offersToCSV() returned %d rows, want %d", len(rows), len(tt.expectedRows))
+			}
+			for i, expected := range tt.expectedRows {
+				for j := range expected {
+					if rows[i][j] != expected[j] {
+						t.Errorf("row %d column %d = %q, want %q", i+1, j, rows[i][j], expected[j])
+					}
+				}
+			}
+		})
+	}
+
+	// The raw output must quote fields containing commas
+	raw := string(offersToCSV([]ovh.Offer{{InvoiceName: "a, b"}}))
+	if !strings.Contains(raw, `"a, b"`) {
+		t.Errorf("offersToCSV() did not quote field with comma\nGot:\n%s", raw)
+	}
+}
+
+// TestOffersToJSON tests the offersToJSON function.
+//
+// Testing strategy:
+//   - Round trip: marshal offers, unmarshal into the export struct, compare fields
+//   - Verify documented snake_case keys are present in the raw output
+//   - Verify empty input produces an empty list (not null)
+func TestOffersToJSON(t *testing.T) {
+	offers := []ovh.Offer{
+		{
+			FQN:         "1801sk12.lon.1",
+			PlanCode:    "eco.eco-1",
+			Price:       12.99,
+			Currency:    "EUR",
+			InvoiceName: "ECO 1",
+			Addons:      map[string]string{"bandwidth": "bandwidth-100"},
+		},
+		{
+			FQN:         "1801sk13.lon.1",
+			PlanCode:    "eco.eco-2",
+			Price:       19.99,
+			Currency:    "EUR",
+			InvoiceName: "ECO 2",
+			Addons:      nil, // Must become {} in JSON
+		},
+	}
+
+	data, err := offersToJSON(offers)
+	if err != nil {
+		t.Fatalf("offersToJSON() unexpected error: %v", err)
+	}
+
+	// Round trip
+	var export offersExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("offersToJSON() produced invalid JSON: %v\n\nGot:\n%s", err, data)
+	}
+
+	if export.Count != 2 || len(export.Offers) != 2 {
+		t.Fatalf("export count = %d, offers = %d; want 2, 2", export.Count, len(export.Offers))
+	}
+
+	for i, offer := range offers {
+		got := export.Offers[i]
+		if got.Rank != i+1 || got.FQN != offer.FQN || got.PlanCode != offer.PlanCode ||
+			got.InvoiceName != offer.InvoiceName || got.Price != offer.Price || got.Currency != offer.Currency {
+			t.Errorf("offer %d round trip = %+v, want fields of %+v", i, got, offer)
+		}
+	}
+	if export.Offers[0].Addons["bandwidth"] != "bandwidth-100" {
+		t.Errorf("addons not preserved: %v", export.Offers[0].Addons)
+	}
+
+	// Documented keys must be present
+	raw := string(data)
+	for _, key := range []string{`"count"`, `"offers"`, `"rank"`, `"fqn"`, `"plan_code"`,
+		`"invoice_name"`, `"price"`, `"currency"`, `"addons": {}`} {
+		if !strings.Contains(raw, key) {
+			t.Errorf("offersToJSON() missing %s\n\nGot:\n%s", key, raw)
+		}
+	}
+
+	// Empty input - empty list, not null
+	empty, err := offersToJSON(nil)
+	if err != nil {
+		t.Fatalf("offersToJSON(nil) unexpected error: %v", err)
+	}
+	if !strings.Contains(string(empty), `"offers": []`) {
+		t.Errorf("offersToJSON(nil) = %s, want empty offers list", empty)
+	}
+}
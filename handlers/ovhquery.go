@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// ovhMaxTop is the largest offer count /ovh accepts
+// Longer lists are split into several messages (see formatOVHMessages)
+const ovhMaxTop = 20
+
+// ovhQuery is what an /ovh request asks for
+// The button and the other OVH features use defaultOVHQuery.
+type ovhQuery struct {
+	datacenter string  // Datacenter code, e.g. "lon"
+	top        int     // Number of offers to show
+	maxPrice   float64 // Highest monthly price in the catalog currency (0 = no limit)
+	planPrefix string  // Only plan codes starting with it, e.g. "25skle" ("" = all)
+	comingSoon bool    // Also list servers OVH marks "comingSoon" (the "soon" argument)
+	keyword    string  // Only FQNs or plan codes containing it, e.g. "nvme" ("" = all)
+	lang       string  // Language of the header (see userLanguage; "" = English)
+}
+
+// defaultOVHQuery is the query of the "🖥️ OVH Servers" button and a bare /ovh
+func defaultOVHQuery() ovhQuery {
+	return ovhQuery{datacenter: ovhDatacenter, top: ovhTop}
+}
+
+// options turns the query into ovh.GetTopOffers options
+func (q ovhQuery) options() []ovh.Option {
+	return []ovh.Option{
+		ovh.WithSubsidiary(ovhSubsidiary),
+		ovh.WithDatacenter(q.datacenter),
+		ovh.WithTop(q.top),
+		ovh.WithMaxPrice(q.maxPrice),
+		ovh.WithPlanPrefix(q.planPrefix),
+		ovh.WithComingSoon(q.comingSoon),
+		ovh.WithKeyword(q.keyword),
+	}
+}
+
+// filters describes the user's filters for the results header
+//
+// Parameters:
+//   - lang: Language of the description (see userLanguage)
+//
+// Returns:
+//   - string: e.g. "max 25, plan codes 25skle*, matching nvme, incl. coming soon" ("" without filters)
+func (q ovhQuery) filters(lang string) string {
+	var parts []string
+	if q.maxPrice > 0 {
+		parts = append(parts, translate(lang, "ovh_filter_max", strconv.FormatFloat(q.maxPrice, 'f', -1, 64)))
+	}
+	if q.planPrefix != "" {
+		parts = append(parts, translate(lang, "ovh_filter_prefix", q.planPrefix))
+	}
+	if q.keyword != "" {
+		parts = append(parts, translate(lang, "ovh_filter_keyword", q.keyword))
+	}
+	if q.comingSoon {
+		parts = append(parts, translate(lang, "ovh_filter_coming_soon"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// planPrefixPattern matches what a plan code prefix may contain
+// Plan codes are lowercase letters, digits and dashes (e.g., "24ska01", "25skle01-v2")
+var planPrefixPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// keywordPattern matches what a search keyword may contain
+// FQNs are plan codes and addon codes joined by dots (e.g., "1801sk12.ram.1.softraid-2x2000sata")
+var keywordPattern = regexp.MustCompile(`^[a-z0-9.-]+$`)
+
+// ovhUsage is the reply to /ovh arguments that don't parse
+func ovhUsage(lang string) string {
+	return translate(lang, "ovh_usage", knownDatacenterCodes())
+}
+
+// parseOVHArgs parses the /ovh arguments
+//
+// Arguments (all optional, any order, separated by spaces):
+//   - datacenter: a known datacenter code (default lon)
+//   - count: number of offers, 1-ovhMaxTop (default 3)
+//   - max=PRICE: highest monthly price in the catalog currency (EUR), e.g. max=25 or max=19.99
+//   - prefix=PLAN: plan code prefix, e.g. prefix=25skle
+//   - search KEYWORD: hardware keyword in the FQN or plan code, e.g. search nvme
+//   - soon: also list servers OVH marks "comingSoon" (not orderable yet)
+//
+// Keys are case-insensitive; each argument may be given once.
+//
+// Parameters:
+//   - args: message.CommandArguments()
+//
+// Returns:
+//   - ovhQuery: The query (defaultOVHQuery for no arguments)
+//   - error: Unknown key or datacenter, duplicate, malformed value, search without keyword
+//     (a userError: the reply translates it, see userErrorText)
+func parseOVHArgs(args string) (ovhQuery, error) {
+	query := defaultOVHQuery()
+	seen := make(map[string]bool)
+
+	// once rejects the second occurrence of an argument
+	once := func(name, token string) error {
+		if seen[name] {
+			return newUserError("ovh_arg_twice", name, strconv.Quote(token))
+		}
+		seen[name] = true
+		return nil
+	}
+
+	tokens := strings.Fields(args)
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		key, value, isOption := strings.Cut(token, "=")
+		if !isOption {
+			// Positional: a number is the count, "soon" the coming-soon flag,
+			// "search" takes the next token as keyword, anything else a datacenter
+			// ("-3" is a bad count, not a datacenter)
+			if top, err := strconv.Atoi(token); err == nil || strings.HasPrefix(token, "-") {
+				if err := once("count", token); err != nil {
+					return ovhQuery{}, err
+				}
+				if err != nil || top < 1 || top > ovhMaxTop {
+					return ovhQuery{}, newUserError("ovh_arg_count", strconv.Quote(token), ovhMaxTop)
+				}
+				query.top = top
+				continue
+			}
+
+			if strings.EqualFold(token, "soon") {
+				if err := once("soon", token); err != nil {
+					return ovhQuery{}, err
+				}
+				query.comingSoon = true
+				continue
+			}
+
+			if strings.EqualFold(token, "search") {
+				if err := once("search", token); err != nil {
+					return ovhQuery{}, err
+				}
+				if i+1 == len(tokens) {
+					return ovhQuery{}, newUserError("ovh_arg_search_keyword")
+				}
+				i++
+				keyword := strings.ToLower(tokens[i])
+				if !keywordPattern.MatchString(keyword) {
+					return ovhQuery{}, newUserError("ovh_arg_keyword", strconv.Quote(tokens[i]))
+				}
+				query.keyword = keyword
+				continue
+			}
+
+			if err := once("datacenter", token); err != nil {
+				return ovhQuery{}, err
+			}
+			datacenter := strings.ToLower(token)
+			if !isKnownDatacenter(datacenter) {
+				return ovhQuery{}, newUserError("ovh_arg_datacenter", strconv.Quote(token))
+			}
+			query.datacenter = datacenter
+			continue
+		}
+
+		key = strings.ToLower(key)
+		switch key {
+		case "max":
+			if err := once(key, token); err != nil {
+				return ovhQuery{}, err
+			}
+			// ParseFloat accepts "NaN" and "Inf": not prices
+			price, err := strconv.ParseFloat(value, 64)
+			if err != nil || price <= 0 || math.IsInf(price, 0) || math.IsNaN(price) {
+				return ovhQuery{}, newUserError("ovh_arg_max", strconv.Quote(value))
+			}
+			query.maxPrice = price
+
+		case "prefix":
+			if err := once(key, token); err != nil {
+				return ovhQuery{}, err
+			}
+			prefix := strings.ToLower(value)
+			if !planPrefixPattern.MatchString(prefix) {
+				return ovhQuery{}, newUserError("ovh_arg_prefix", strconv.Quote(value))
+			}
+			query.planPrefix = prefix
+
+		default:
+			return ovhQuery{}, newUserError("ovh_arg_unknown", strconv.Quote(key))
+		}
+	}
+
+	return query, nil
+}
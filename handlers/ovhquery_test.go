@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/tgfmt"
+)
+
+// TestParseOVHArgs tests /ovh argument parsing
+//
+// Cases:
+//   - No arguments: the button's query
+//   - Positional datacenter and count, key=value filters, in any order and case
+//   - Duplicates, unknown keys and datacenters, malformed numbers: errors
+func TestParseOVHArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    ovhQuery
+		wantErr string // Substring of the error, "" for success
+	}{
+		{name: "empty", args: "", want: defaultOVHQuery()},
+		{name: "spaces only", args: "   ", want: defaultOVHQuery()},
+		{name: "datacenter", args: "gra", want: ovhQuery{datacenter: "gra", top: 3}},
+		{name: "datacenter upper case", args: "RBX", want: ovhQuery{datacenter: "rbx", top: 3}},
+		{name: "count", args: "5", want: ovhQuery{datacenter: "lon", top: 5}},
+		{name: "max", args: "lon max=25", want: ovhQuery{datacenter: "lon", top: 3, maxPrice: 25}},
+		{name: "max with decimals", args: "max=19.99", want: ovhQuery{datacenter: "lon", top: 3, maxPrice: 19.99}},
+		{name: "prefix", args: "prefix=25skle", want: ovhQuery{datacenter: "lon", top: 3, planPrefix: "25skle"}},
+		{name: "prefix upper case", args: "PREFIX=25SKLE", want: ovhQuery{datacenter: "lon", top: 3, planPrefix: "25skle"}},
+		{
+			name: "everything",
+			args: "gra 10 max=30 prefix=24ska",
+			want: ovhQuery{datacenter: "gra", top: 10, maxPrice: 30, planPrefix: "24ska"},
+		},
+		{
+			name: "mixed order",
+			args: "prefix=24ska 10 max=30 gra",
+			want: ovhQuery{datacenter: "gra", top: 10, maxPrice: 30, planPrefix: "24ska"},
+		},
+		{name: "largest count", args: "20", want: ovhQuery{datacenter: "lon", top: 20}},
+		{name: "coming soon", args: "gra SOON", want: ovhQuery{datacenter: "gra", top: 3, comingSoon: true}},
+		{name: "search", args: "search nvme", want: ovhQuery{datacenter: "lon", top: 3, keyword: "nvme"}},
+		{name: "search in FQN syntax", args: "gra Search .RAM.1 5", want: ovhQuery{datacenter: "gra", top: 5, keyword: ".ram.1"}},
+		{name: "search keyword looks like a count", args: "search 2x480", want: ovhQuery{datacenter: "lon", top: 3, keyword: "2x480"}},
+
+		{name: "two datacenters", args: "lon gra", wantErr: "datacenter given twice"},
+		{name: "two counts", args: "3 5", wantErr: "count given twice"},
+		{name: "two max", args: "max=10 max=20", wantErr: "max given twice"},
+		{name: "two prefixes", args: "prefix=a Prefix=b", wantErr: "prefix given twice"},
+		{name: "two soon", args: "soon soon", wantErr: "soon given twice"},
+		{name: "two searches", args: "search nvme search sata", wantErr: "search given twice"},
+		{name: "search without keyword", args: "lon search", wantErr: "search needs a keyword"},
+		{name: "search keyword with symbols", args: "search nvme*", wantErr: "invalid search keyword"},
+		{name: "unknown key", args: "min=5", wantErr: `unknown option: "min"`},
+		{name: "empty key", args: "=5", wantErr: `unknown option: ""`},
+		{name: "unknown datacenter", args: "mars", wantErr: `unknown datacenter: "mars"`},
+		{name: "count zero", args: "0", wantErr: "invalid count"},
+		{name: "count negative", args: "-3", wantErr: "invalid count"},
+		{name: "count too large", args: "21", wantErr: "invalid count"},
+		{name: "max not a number", args: "max=cheap", wantErr: "invalid max price"},
+		{name: "max empty", args: "max=", wantErr: "invalid max price"},
+		{name: "max zero", args: "max=0", wantErr: "invalid max price"},
+		{name: "max negative", args: "max=-5", wantErr: "invalid max price"},
+		{name: "max NaN", args: "max=NaN", wantErr: "invalid max price"},
+		{name: "max infinite", args: "max=Inf", wantErr: "invalid max price"},
+		{name: "max with comma", args: "max=19,99", wantErr: "invalid max price"},
+		{name: "prefix empty", args: "prefix=", wantErr: "invalid plan prefix"},
+		{name: "prefix with symbols", args: "prefix=ks*", wantErr: "invalid plan prefix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOVHArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseOVHArgs(%q) error = %v, want it to contain %q", tt.args, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseOVHArgs(%q) unexpected error: %v", tt.args, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseOVHArgs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandleOVHCommand tests that /ovh arguments reach the OVH query and the reply
+//
+// Cases:
+//   - Filters: passed as options, shown in the results header
+//   - Filters matching nothing: the "nothing in stock" reply names them
+//   - Bad arguments: usage help, no fetch
+//   - Bad arguments from an unauthorized user: only the denial, audited
+func TestHandleOVHCommand(t *testing.T) {
+	var got ovh.Options
+	var offers []ovh.Offer
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		got = ovh.Options{}
+		for _, opt := range opts {
+			opt(&got)
+		}
+		return offers, nil
+	}
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	t.Run("filters", func(t *testing.T) {
+		offers = []ovh.Offer{{PlanCode: "25skle01", InvoiceName: "KS-LE-1", Price: 19.99, Currency: "EUR", FQN: "25skle01.fqn", Datacenter: "gra"}}
+		sender := &recordingSender{}
+		HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh gra 5 max=25 prefix=25skle soon", 12345), testConfig())
+
+		if got.Datacenter != "gra" || got.Top != 5 || got.MaxPrice != 25 || got.PlanPrefix != "25skle" || !got.ComingSoon {
+			t.Errorf("options = %+v, want gra, top 5, max 25, prefix 25skle, coming soon", got)
+		}
+		messages := sender.messages()
+		if len(messages) != 2 {
+			t.Fatalf("sent %d messages, want status + results", len(messages))
+		}
+		if want := "Top 5 cheapest in Gravelines, France \\(EUR\\), max 25, plan codes 25skle\\*, incl\\. coming soon"; !strings.Contains(messages[1].Text, want) {
+			t.Errorf("results = %q, want header %q", messages[1].Text, want)
+		}
+		if err := tgfmt.ValidateMarkdownV2(messages[1].Text); err != nil {
+			t.Errorf("results are not valid MarkdownV2: %v", err)
+		}
+	})
+
+	t.Run("search", func(t *testing.T) {
+		offers = []ovh.Offer{{PlanCode: "24sk50", InvoiceName: "KS-5", Price: 29.99, Currency: "EUR", FQN: "24sk50.softraid-2x480nvme", Datacenter: "lon"}}
+		sender := &recordingSender{}
+		HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh search NVMe", 12345), testConfig())
+
+		if got.Keyword != "nvme" || got.Datacenter != "lon" {
+			t.Errorf("options = %+v, want keyword nvme in lon", got)
+		}
+		messages := sender.messages()
+		if len(messages) != 2 || !strings.Contains(messages[1].Text, "matching nvme") {
+			t.Errorf("messages = %+v, want status + results matching nvme", messages)
+		}
+	})
+
+	t.Run("nothing matches", func(t *testing.T) {
+		offers = nil
+		sender := &recordingSender{}
+		HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh max=5", 12345), testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 2 || !strings.Contains(messages[1].Text, "No servers in London, UK match max 5") {
+			t.Errorf("messages = %+v, want status + no match reply", messages)
+		}
+	})
+
+	t.Run("bad arguments", func(t *testing.T) {
+		got = ovh.Options{}
+		sender := &recordingSender{}
+		HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh max=abc", 12345), testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 1 || !strings.Contains(messages[0].Text, "Usage: /ovh") || !strings.Contains(messages[0].Text, "invalid max price") {
+			t.Errorf("messages = %+v, want one usage reply with the reason", messages)
+		}
+		if got.Datacenter != "" {
+			t.Errorf("OVH fetched for bad arguments: %+v", got)
+		}
+	})
+
+	t.Run("bad arguments unauthorized", func(t *testing.T) {
+		audit := withAuditLog(t)
+		got = ovh.Options{}
+		sender := &recordingSender{}
+		HandleOVHCommand(context.Background(), sender, createTestMessage("/ovh max=abc", 666), testConfig())
+
+		messages := sender.messages()
+		if len(messages) != 1 || !strings.Contains(messages[0].Text, "only available to authorized users") {
+			t.Errorf("messages = %+v, want only the denial", messages)
+		}
+		entries, err := audit.RecentAudit(context.Background(), 10)
+		if err != nil || len(entries) != 1 || entries[0].Allowed {
+			t.Errorf("audit entries = %+v, %v, want one denied entry", entries, err)
+		}
+		if got.Datacenter != "" {
+			t.Errorf("OVH fetched for an unauthorized user: %+v", got)
+		}
+	})
+}
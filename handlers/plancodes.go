@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// planCodesPageSize is how many codes one /plan_codes page shows
+// The ECO catalog has hundreds of addon codes; 100 short codes per page
+// stay well under Telegram's 4096-character limit.
+const planCodesPageSize = 100
+
+// getPlanCodes and getAddonCodes list ECO catalog codes
+// Declared as vars so tests can replace them and avoid real OVH API calls
+var (
+	getPlanCodes  = ovh.PlanCodesContext
+	getAddonCodes = ovh.AddonCodesContext
+)
+
+// planCodesRequest is a parsed /plan_codes command
+type planCodesRequest struct {
+	addons bool // List addon codes instead of plan codes
+	page   int  // 1-based
+}
+
+// HandlePlanCodes handles the /plan_codes [addons] [page] command (private, like /ovh).
+// Lists the plan codes of the ECO catalog, for writing subscriptions and filters.
+//
+// Authorization comes first: arguments of unauthorized users are not parsed,
+// they only get the denial.
+//
+// Usage:
+//   - /plan_codes: first page of server plan codes
+//   - /plan_codes 2: second page
+//   - /plan_codes addons [page]: addon codes (RAM, storage, bandwidth) instead
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the command
+//   - cfg: Application configuration (needed for authorization check)
+func HandlePlanCodes(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	ctx, allowed := authorize(ctx, bot, message, cfg, config.FeatureOVH)
+	if !allowed {
+		return
+	}
+
+	req, err := parsePlanCodesArgs(message.CommandArguments())
+	if err != nil {
+		msg := replyTo(message, translate(userLanguage(ctx, message.From), "plan_codes_usage"))
+		if _, err := sendReply(ctx, bot, message, msg); err != nil {
+			log.Error("Failed to send /plan_codes usage",
+				"error", err,
+				"message_type", messageType(msg))
+		}
+		return
+	}
+
+	var codes []string
+	ok := runOVHFetch(ctx, bot, message, cfg, "plan_codes", func(ctx context.Context) error {
+		log.Info("Fetching OVH catalog codes",
+			"subsidiary", ovhSubsidiary,
+			"addons", req.addons)
+
+		var err error
+		if req.addons {
+			codes, err = getAddonCodes(ctx, ovhSubsidiary)
+		} else {
+			codes, err = getPlanCodes(ctx, ovhSubsidiary)
+		}
+		return err
+	})
+	if !ok {
+		return
+	}
+
+	msg := replyTo(message, formatPlanCodesPage(userLanguage(ctx, message.From), codes, req, planCodesPageSize))
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send plan codes",
+			"error", err,
+			"message_type", messageType(msg))
+		return
+	}
+
+	log.Info("Plan codes sent successfully",
+		"codes", len(codes),
+		"page", req.page)
+}
+
+// parsePlanCodesArgs parses "[addons] [page]" (both optional, in this order)
+func parsePlanCodesArgs(args string) (planCodesRequest, error) {
+	req := planCodesRequest{page: 1}
+	fields := strings.Fields(args)
+
+	if len(fields) > 0 && strings.EqualFold(fields[0], "addons") {
+		req.addons = true
+		fields = fields[1:]
+	}
+	switch len(fields) {
+	case 0:
+	case 1:
+		page, err := strconv.Atoi(fields[0])
+		if err != nil || page < 1 {
+			return planCodesRequest{}, fmt.Errorf("invalid page: %q", fields[0])
+		}
+		req.page = page
+	default:
+		return planCodesRequest{}, fmt.Errorf("too many arguments: %q", args)
+	}
+	return req, nil
+}
+
+// formatPlanCodesPage builds the plain-text reply for one page of codes
+//
+// Format:
+//
+//	📋 OVH plan codes (FR), page 1/3, 250 codes:
+//	24sk10
+//	24sk20
+//	...
+//	Next: /plan_codes 2
+//
+// A page past the end shows the last page number instead of an empty list.
+//
+// Parameters:
+//   - lang: Language of the texts around the codes (see userLanguage)
+//   - codes: All codes, sorted
+//   - req: Which list and page
+//   - pageSize: Codes per page
+//
+// Returns:
+//   - string: Plain text message
+func formatPlanCodesPage(lang string, codes []string, req planCodesRequest, pageSize int) string {
+	kind, command := translate(lang, "plan_codes_kind_plans"), "/plan_codes "
+	if req.addons {
+		kind, command = translate(lang, "plan_codes_kind_addons"), "/plan_codes addons "
+	}
+	if len(codes) == 0 {
+		return translate(lang, "plan_codes_none", kind, ovhSubsidiary)
+	}
+
+	pages := (len(codes) + pageSize - 1) / pageSize
+	if req.page > pages {
+		return translate(lang, "plan_codes_past_end", pages, kind, command+strconv.Itoa(pages))
+	}
+
+	start := (req.page - 1) * pageSize
+	end := min(start+pageSize, len(codes))
+
+	var b strings.Builder
+	b.WriteString(translate(lang, "plan_codes_page", kind, ovhSubsidiary, req.page, pages, len(codes)) + "\n")
+	b.WriteString(strings.Join(codes[start:end], "\n"))
+	if req.page < pages {
+		b.WriteString("\n\n" + translate(lang, "plan_codes_next", command+strconv.Itoa(req.page+1)))
+	}
+	return b.String()
+}
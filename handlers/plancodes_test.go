@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestParsePlanCodesArgs tests the [addons] [page] arguments
+func TestParsePlanCodesArgs(t *testing.T) {
+	tests := []struct {
+		args    string
+		want    planCodesRequest
+		wantErr bool
+	}{
+		{args: "", want: planCodesRequest{page: 1}},
+		{args: "3", want: planCodesRequest{page: 3}},
+		{args: "Addons", want: planCodesRequest{addons: true, page: 1}},
+		{args: "addons 2", want: planCodesRequest{addons: true, page: 2}},
+		{args: "0", wantErr: true},
+		{args: "2 addons", wantErr: true},
+		{args: "addons 2 3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parsePlanCodesArgs(tt.args)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePlanCodesArgs(%q) = %+v, %v; want %+v, error %v", tt.args, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestFormatPlanCodesPage tests pagination of the code list
+//
+// Cases:
+//   - First page: header with page count, "Next" hint
+//   - Last (partial) page: no "Next" hint
+//   - Page past the end: points to the last page
+//   - No codes at all
+func TestFormatPlanCodesPage(t *testing.T) {
+	codes := make([]string, 5)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("24sk%d0", i+1)
+	}
+
+	tests := []struct {
+		name string
+		req  planCodesRequest
+		want string
+	}{
+		{name: "first page", req: planCodesRequest{page: 1},
+			want: "📋 OVH plan codes (FR), page 1/3, 5 codes:\n24sk10\n24sk20\n\nNext: /plan_codes 2"},
+		{name: "last page", req: planCodesRequest{addons: true, page: 3},
+			want: "📋 OVH addon codes (FR), page 3/3, 5 codes:\n24sk50"},
+		{name: "past the end", req: planCodesRequest{addons: true, page: 9},
+			want: "📋 There are only 3 pages of OVH addon codes. Try /plan_codes addons 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatPlanCodesPage("en", codes, tt.req, 2); got != tt.want {
+				t.Errorf("formatPlanCodesPage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got := formatPlanCodesPage("en", nil, planCodesRequest{page: 1}, 2); !strings.Contains(got, "No OVH plan codes") {
+		t.Errorf("formatPlanCodesPage(nil) = %q, want a 'no codes' message", got)
+	}
+}
+
+// TestHandlePlanCodes tests which list is fetched and the reply
+func TestHandlePlanCodes(t *testing.T) {
+	oldPlans, oldAddons := getPlanCodes, getAddonCodes
+	defer func() { getPlanCodes, getAddonCodes = oldPlans, oldAddons }()
+	getPlanCodes = func(ctx context.Context, subsidiary string) ([]string, error) {
+		return []string{"24sk10", "24sk20"}, nil
+	}
+	getAddonCodes = func(ctx context.Context, subsidiary string) ([]string, error) {
+		return []string{"bandwidth-300-24sk"}, nil
+	}
+
+	tests := []struct {
+		name      string
+		text      string
+		userID    int64
+		wantLast  string
+		wantAudit bool // Expected Allowed of the single audit entry
+	}{
+		{name: "plans", text: "/plan_codes", userID: 12345, wantLast: "24sk10\n24sk20", wantAudit: true},
+		{name: "addons", text: "/plan_codes addons", userID: 12345, wantLast: "bandwidth-300-24sk", wantAudit: true},
+		{name: "bad arguments", text: "/plan_codes next", userID: 12345, wantLast: "Usage: /plan_codes [addons] [page]", wantAudit: true},
+		{name: "unauthorized bad arguments", text: "/plan_codes foo bar baz", userID: 99999, wantLast: "only available to authorized users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := withAuditLog(t)
+			sender := &recordingSender{}
+			HandlePlanCodes(context.Background(), sender, createTestMessage(tt.text, tt.userID), testConfig())
+
+			messages := sender.messages()
+			if len(messages) == 0 {
+				t.Fatal("HandlePlanCodes sent no messages")
+			}
+			if last := messages[len(messages)-1].Text; !strings.Contains(last, tt.wantLast) {
+				t.Errorf("last message = %q, want it to contain %q", last, tt.wantLast)
+			}
+			if !tt.wantAudit && len(messages) != 1 {
+				t.Errorf("sent %d messages to an unauthorized user, want only the denial", len(messages))
+			}
+			entries, err := audit.RecentAudit(context.Background(), 10)
+			if err != nil || len(entries) != 1 || entries[0].Allowed != tt.wantAudit {
+				t.Errorf("audit entries = %+v, %v, want one with Allowed %v", entries, err, tt.wantAudit)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// priceWatcher detects OVH price changes (set by main.go via SetPriceWatcher)
+// nil: prices are not watched
+var priceWatcher *ovh.PriceWatcher
+
+// SetPriceWatcher enables price-change notifications for FQN subscribers
+// Call once at startup, before the first CheckPriceChanges (read without locking).
+//
+// Parameters:
+//   - watcher: Usually ovh.NewPriceWatcher(cfg.PriceChangeThresholdPct)
+func SetPriceWatcher(watcher *ovh.PriceWatcher) {
+	priceWatcher = watcher
+}
+
+// CheckPriceChanges fetches the OVH offers and notifies the subscribers of
+// every FQN whose price changed past the threshold (called by a scheduler in main.go)
+//
+// How it works:
+//   - Subscriptions come from the known-chat store (storage.ChatStore)
+//   - Without subscriptions, OVH is not called at all
+//   - All offers of the default datacenter are fetched (no top-N limit),
+//     so a subscribed server is watched wherever it ranks
+//   - ovh.PriceWatcher compares them with the previous check; the first
+//     check only records the prices
+//   - Each change is sent as plain text (ovh.FormatPriceChange) to the chats
+//     subscribed to its FQN; chats that blocked the bot are skipped
+//
+// Parameters:
+//   - ctx: Context for the storage and OVH calls (cancelled on shutdown)
+//   - bot: Bot sender for sending messages
+//
+// Returns:
+//   - int: Number of notifications delivered
+func CheckPriceChanges(ctx context.Context, bot BotSender) int {
+	log := logger.FromContext(ctx)
+
+	watcher, chats := priceWatcher, knownChatStore
+	if watcher == nil || chats == nil {
+		return 0
+	}
+
+	// Step 1: Who wants to know?
+	subscribers, err := chats.ListSubscribers(ctx)
+	if err != nil {
+		log.Error("Failed to list subscriptions for price check",
+			"error", err)
+		return 0
+	}
+	if len(subscribers) == 0 {
+		return 0
+	}
+
+	// Step 2: Current prices against the ones of the previous check
+	query := defaultOVHQuery()
+	query.top = 0
+	offers, err := getTopOffers(ctx, query.options()...)
+	if err != nil {
+		log.Error("Failed to fetch OVH offers for price check",
+			"error", err)
+		return 0
+	}
+	changes := watcher.Observe(offers, time.Now())
+
+	// Step 3: Notify the subscribers of each changed FQN
+	delivered := 0
+	for _, change := range changes {
+		text := ovh.FormatPriceChange(change)
+		for _, chatID := range subscribers[change.New.FQN] {
+			if IsChatBlocked(chatID) {
+				continue
+			}
+
+			msg := tgbotapi.NewMessage(chatID, text)
+			if _, err := bot.Send(msg); err != nil {
+				log.Error("Failed to send price change notification",
+					"error", err,
+					"message_type", messageType(msg),
+					"chat_id", chatID,
+					"fqn", change.New.FQN)
+				continue
+			}
+			delivered++
+		}
+	}
+
+	log.Info("OVH price check done",
+		"subscribed_fqns", len(subscribers),
+		"offers_count", len(offers),
+		"changes", len(changes),
+		"delivered", delivered)
+	return delivered
+}
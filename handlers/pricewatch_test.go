@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/storage"
+)
+
+// TestCheckPriceChanges tests price-change notifications for FQN subscribers
+//
+// Flow (threshold 5%):
+//   - First check: prices recorded, nothing sent
+//   - Subscribed FQN drops 15.8%: its subscribers are notified, blocked chats skipped
+//   - Unsubscribed FQN changes too: nobody is notified about it
+//   - Change below the threshold: nothing sent
+//   - OVH failing: nothing sent
+//   - Every check asks for all offers (no top-N limit)
+func TestCheckPriceChanges(t *testing.T) {
+	chats := storage.NewChatStore(storage.NewMemoryStore())
+	withKnownChats(t, chats)
+	oldWatcher, oldGetTopOffers := priceWatcher, getTopOffers
+	defer func() { priceWatcher, getTopOffers = oldWatcher, oldGetTopOffers }()
+	SetPriceWatcher(ovh.NewPriceWatcher(5))
+
+	ctx := context.Background()
+	_ = chats.SaveSubscription(ctx, 1, "1801sk12.ram.1")
+	_ = chats.SaveSubscription(ctx, 2, "1801sk12.ram.1")
+	_ = chats.SaveSubscription(ctx, 3, "1801sk12.ram.1")
+	setChatBlocked(2, true)
+	defer setChatBlocked(2, false)
+
+	var prices map[string]float64
+	var fetchErr error
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		var o ovh.Options
+		for _, opt := range opts {
+			opt(&o)
+		}
+		if o.Top != 0 {
+			t.Errorf("price check asked for the top %d offers, want all of them", o.Top)
+		}
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		var offers []ovh.Offer
+		for _, fqn := range []string{"1801sk12.ram.1", "24sk20.ram-32g"} {
+			offers = append(offers, ovh.Offer{FQN: fqn, Price: prices[fqn], Currency: "GBP"})
+		}
+		return offers, nil
+	}
+
+	steps := []struct {
+		name   string
+		prices map[string]float64
+		err    error
+		want   []string
+	}{
+		{name: "first check", prices: map[string]float64{"1801sk12.ram.1": 18.99, "24sk20.ram-32g": 30}},
+		{
+			name:   "subscribed price drop",
+			prices: map[string]float64{"1801sk12.ram.1": 15.99, "24sk20.ram-32g": 40},
+			want: []string{
+				"💰 Price change: 1801sk12.ram.1 dropped from £18.99 to £15.99 (−15.8%)",
+				"💰 Price change: 1801sk12.ram.1 dropped from £18.99 to £15.99 (−15.8%)",
+			},
+		},
+		{name: "below threshold", prices: map[string]float64{"1801sk12.ram.1": 16.49, "24sk20.ram-32g": 40}},
+		{name: "OVH failing", err: errors.New("boom")},
+	}
+
+	for _, step := range steps {
+		prices, fetchErr = step.prices, step.err
+		sender := &recordingSender{}
+
+		delivered := CheckPriceChanges(ctx, sender)
+
+		var got []string
+		var chatIDs []int64
+		for _, m := range sender.messages() {
+			got = append(got, m.Text)
+			chatIDs = append(chatIDs, m.ChatID)
+		}
+		if !reflect.DeepEqual(got, step.want) || delivered != len(step.want) {
+			t.Errorf("%s: sent %q (delivered %d), want %q", step.name, got, delivered, step.want)
+		}
+		if len(step.want) > 0 && !reflect.DeepEqual(chatIDs, []int64{1, 3}) {
+			t.Errorf("%s: notified chats %v, want [1 3] (2 blocked the bot)", step.name, chatIDs)
+		}
+	}
+}
+
+// TestCheckPriceChanges_NoSubscribers tests that OVH isn't called for nobody
+func TestCheckPriceChanges_NoSubscribers(t *testing.T) {
+	withKnownChats(t, storage.NewChatStore(storage.NewMemoryStore()))
+	oldWatcher, oldGetTopOffers := priceWatcher, getTopOffers
+	defer func() { priceWatcher, getTopOffers = oldWatcher, oldGetTopOffers }()
+	SetPriceWatcher(ovh.NewPriceWatcher(5))
+
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		t.Error("OVH called without subscriptions")
+		return nil, nil
+	}
+
+	if delivered := CheckPriceChanges(context.Background(), &recordingSender{}); delivered != 0 {
+		t.Errorf("CheckPriceChanges() = %d, want 0", delivered)
+	}
+}
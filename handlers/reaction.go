@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// reactionAPIs are the Bot APIs used for reactions, by bot username (set by main.go via SetReactionAPI)
+// BotSender can't send setMessageReaction (see bot.RawRequester)
+var reactionAPIs = make(map[string]bot.RawRequester)
+
+// buttonReactions is the emoji the bot puts on a button press (REACT_TO_REQUESTS)
+//
+// Only emoji from Telegram's reaction set work: 🎲 and 🌀 are not in it,
+// so the dice and Twister buttons get 👌 ("got it").
+// 👀 ("looking") suits the OVH buttons, whose answers take a few seconds.
+var buttonReactions = map[string]string{
+	"🎲 Dice":              "👌",
+	"🎲🎲 Double Dice":      "👌",
+	"🌀 Twister":           "👌",
+	"🖥️ OVH Servers":      "👀",
+	"🔵 Dedicated Servers": "👀",
+}
+
+// SetReactionAPI gives the handlers access to setMessageReaction
+// Call once per bot at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - username: Bot username from getMe (the same value as cfg.BotUsername)
+//   - api: Usually the *tgbotapi.BotAPI created in main.go
+func SetReactionAPI(username string, api bot.RawRequester) {
+	reactionAPIs[username] = api
+}
+
+// reactToRequest acknowledges message with emoji, if REACT_TO_REQUESTS is on
+//
+// Failures are logged as warnings and otherwise ignored: the reaction is a
+// courtesy, the actual answer still follows.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - message: Message to react to (the button press)
+//   - cfg: Application configuration (ReactToRequests, BotUsername)
+//   - emoji: Reaction from Telegram's reaction set
+func reactToRequest(ctx context.Context, message *tgbotapi.Message, cfg *config.Config, emoji string) {
+	if !cfg.ReactToRequests || emoji == "" {
+		return
+	}
+	api := reactionAPIs[cfg.BotUsername]
+	if api == nil {
+		return
+	}
+
+	if err := bot.React(api, message.Chat.ID, message.MessageID, emoji); err != nil {
+		logger.FromContext(ctx).Warn("Failed to react to message",
+			"error", err,
+			"emoji", emoji,
+			"message_id", message.MessageID)
+	}
+}
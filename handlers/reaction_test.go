@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// reactionRecorder is a fake bot.RawRequester that records setMessageReaction calls
+type reactionRecorder struct {
+	params []tgbotapi.Params
+	err    error
+}
+
+func (r *reactionRecorder) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	if endpoint == "setMessageReaction" {
+		r.params = append(r.params, params)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// TestReactToRequest tests reactions on button presses
+//
+// Cases:
+//   - REACT_TO_REQUESTS off (default): no reaction
+//   - On: the button's emoji on the pressed message, then the normal answer
+//   - Reaction rejected by Telegram: the answer is still sent
+//   - Text that isn't a button: no reaction
+func TestReactToRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		react     bool
+		text      string
+		apiErr    error
+		wantEmoji string // "" = no reaction expected
+	}{
+		{name: "disabled", react: false, text: "🎲 Dice"},
+		{name: "dice", react: true, text: "🎲 Dice", wantEmoji: "👌"},
+		{name: "rejected", react: true, text: "🌀 Twister", apiErr: errors.New("Bad Request: REACTION_INVALID"), wantEmoji: "👌"},
+		{name: "not a button", react: true, text: "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldAPIs := reactionAPIs
+			defer func() { reactionAPIs = oldAPIs }()
+			reactionAPIs = make(map[string]bot.RawRequester)
+
+			cfg := testConfig()
+			cfg.BotUsername = "run_tbot"
+			cfg.ReactToRequests = tt.react
+			recorder := &reactionRecorder{err: tt.apiErr}
+			SetReactionAPI(cfg.BotUsername, recorder)
+
+			sender := &recordingSender{}
+			message := createTestMessage(tt.text, 12345)
+			message.MessageID = 7
+			RouteUpdate(context.Background(), sender, tgbotapi.Update{UpdateID: 1, Message: message}, cfg)
+
+			if tt.wantEmoji == "" {
+				if len(recorder.params) != 0 {
+					t.Errorf("reacted %v, want no reaction", recorder.params)
+				}
+				return
+			}
+			if len(recorder.params) != 1 {
+				t.Fatalf("reactions = %d, want 1", len(recorder.params))
+			}
+			params := recorder.params[0]
+			if params["message_id"] != "7" || params["reaction"] != `[{"type":"emoji","emoji":"`+tt.wantEmoji+`"}]` {
+				t.Errorf("reaction params = %v, want %s on message 7", params, tt.wantEmoji)
+			}
+			if len(sender.sent) != 1 {
+				t.Errorf("sent %d answers, want 1 (the reaction doesn't replace it)", len(sender.sent))
+			}
+		})
+	}
+}
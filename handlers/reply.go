@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// replyTo creates a text response to message (see bot.Reply)
+// In groups and supergroups it replies to message; in private chats it's a plain message.
+//
+// A package-level helper because inside handlers the bot parameter shadows the bot package.
+func replyTo(message *tgbotapi.Message, text string) tgbotapi.MessageConfig {
+	return bot.Reply(message.Chat.ID, int64(message.MessageID), text)
+}
+
+// sendReply sends c as a reply to message in group chats, and as a plain message in private chats.
+//
+// Why reply in groups?
+//   - Busy groups have many messages between a request and the bot's answer
+//   - A reply quotes the request, so everyone sees whose roll it was
+//   - In private chats there's only one user, so quoting is just noise
+//
+// If the triggering message was deleted in the meantime, Telegram rejects the
+// reply ("message to be replied not found"). The message is then sent again
+// without the reply reference, so the result isn't lost.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message that triggered the response
+//   - c: Response to send (MessageConfig, DiceConfig or PhotoConfig; other types are sent unchanged)
+//
+// Returns:
+//   - tgbotapi.Message: Sent message
+//   - error: Error from the last send attempt
+func sendReply(ctx context.Context, bot BotSender, message *tgbotapi.Message, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return sendWithReply(ctx, message, c, bot.Send)
+}
+
+// sendFormattedReply is sendReply for MarkdownV2 messages (see sendFormatted)
+// Both fallbacks apply: plain text on parse errors, no reply on missing reply target.
+func sendFormattedReply(ctx context.Context, bot BotSender, message *tgbotapi.Message, msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	return sendWithReply(ctx, message, msg, func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+		return sendFormatted(ctx, bot, c.(tgbotapi.MessageConfig))
+	})
+}
+
+// sendWithReply implements sendReply on top of any send function
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - message: Message that triggered the response
+//   - c: Response to send
+//   - send: Function that actually sends (bot.Send or sendFormatted)
+//
+// Returns:
+//   - tgbotapi.Message: Sent message
+//   - error: Error from the last send attempt
+func sendWithReply(ctx context.Context, message *tgbotapi.Message, c tgbotapi.Chattable, send func(tgbotapi.Chattable) (tgbotapi.Message, error)) (tgbotapi.Message, error) {
+	log := logger.FromContext(ctx)
+
+	if !isGroupChat(message.Chat) {
+		return send(c)
+	}
+
+	sent, err := send(withReplyTo(c, message.MessageID))
+	if err == nil || !isReplyNotFoundError(err) {
+		return sent, err
+	}
+
+	log.Warn("Reply target not found, sending without reply",
+		"error", err,
+		"message_id", message.MessageID)
+
+	// Strip the reply reference: c may already carry one (see replyTo)
+	return send(withReplyTo(c, 0))
+}
+
+// withReplyTo returns a copy of c that replies to messageID
+// Only the config types our handlers send are supported; others are returned unchanged.
+func withReplyTo(c tgbotapi.Chattable, messageID int) tgbotapi.Chattable {
+	switch config := c.(type) {
+	case tgbotapi.MessageConfig:
+		config.ReplyToMessageID = messageID
+		return config
+	case tgbotapi.DiceConfig:
+		config.ReplyToMessageID = messageID
+		return config
+	case tgbotapi.PhotoConfig:
+		config.ReplyToMessageID = messageID
+		return config
+	default:
+		return c
+	}
+}
+
+// isReplyNotFoundError reports whether err means the message we replied to no longer exists
+// Example: "Bad Request: message to be replied not found"
+func isReplyNotFoundError(err error) bool {
+	return isAPIError(err, "message to be replied not found")
+}
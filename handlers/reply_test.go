@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// replyToID extracts ReplyToMessageID from the config types our handlers send
+func replyToID(t *testing.T, c tgbotapi.Chattable) int {
+	t.Helper()
+
+	switch config := c.(type) {
+	case tgbotapi.MessageConfig:
+		return config.ReplyToMessageID
+	case tgbotapi.DiceConfig:
+		return config.ReplyToMessageID
+	default:
+		t.Fatalf("unexpected config type %T", c)
+		return 0
+	}
+}
+
+// TestHandlers_ReplyInGroups tests that game handlers reply to the request in groups
+// and send plain messages in private chats.
+func TestHandlers_ReplyInGroups(t *testing.T) {
+	oldDelay := animatedDiceDelay
+	animatedDiceDelay = 0
+	defer func() { animatedDiceDelay = oldDelay }()
+
+	handlers := []struct {
+		name   string
+		handle func(ctx context.Context, bot BotSender, message *tgbotapi.Message)
+	}{
+		{name: "dice", handle: HandleDice},
+		{name: "animated dice", handle: HandleAnimatedDice},
+		{name: "double dice", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleDoubleDice(ctx, bot, message, testConfig())
+		}},
+		{name: "animated double dice", handle: HandleAnimatedDoubleDice},
+		{name: "twister", handle: HandleTwister},
+	}
+
+	chats := []struct {
+		chatType    string
+		wantReplyTo int
+	}{
+		{chatType: "private", wantReplyTo: 0},
+		{chatType: "group", wantReplyTo: 42},
+		{chatType: "supergroup", wantReplyTo: 42},
+	}
+
+	for _, h := range handlers {
+		for _, chat := range chats {
+			t.Run(h.name+"/"+chat.chatType, func(t *testing.T) {
+				sender := &recordingSender{
+					// Animated dice need a value in the response
+					respond: func(c tgbotapi.Chattable) tgbotapi.Message {
+						return tgbotapi.Message{Dice: &tgbotapi.Dice{Emoji: "🎲", Value: 3}}
+					},
+				}
+
+				message := createTestMessage("🎲 Dice", 12345)
+				message.MessageID = 42
+				message.Chat.Type = chat.chatType
+
+				h.handle(context.Background(), sender, message)
+
+				if len(sender.sent) == 0 {
+					t.Fatal("handler sent nothing")
+				}
+				for i, c := range sender.sent {
+					if got := replyToID(t, c); got != chat.wantReplyTo {
+						t.Errorf("send %d: ReplyToMessageID = %d, want %d", i, got, chat.wantReplyTo)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestHandlers_ReplyInGroups_TextCommands tests that command replies are threaded like game results
+//
+// Group chats use real (negative) chat IDs here, so both bot.Reply and sendReply take part.
+func TestHandlers_ReplyInGroups_TextCommands(t *testing.T) {
+	cfg := testConfig()
+
+	handlers := []struct {
+		name   string
+		text   string
+		handle func(ctx context.Context, bot BotSender, message *tgbotapi.Message)
+	}{
+		{name: "start", text: "/start", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleStart(ctx, bot, message, cfg)
+		}},
+		{name: "help", text: "/help", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleHelp(ctx, bot, message, cfg)
+		}},
+		{name: "menu", text: "/menu", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleMenu(ctx, bot, message, cfg)
+		}},
+		{name: "hide", text: "/hide", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleHide(ctx, bot, message, cfg)
+		}},
+		{name: "cancel", text: "/cancel", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleCancel(ctx, bot, message, cfg)
+		}},
+		{name: "admin stats", text: "📊 Stats", handle: func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			HandleAdminStats(ctx, bot, message, cfg)
+		}},
+	}
+
+	chats := []struct {
+		chatType    string
+		chatID      int64
+		wantReplyTo int
+	}{
+		{chatType: "private", chatID: 12345, wantReplyTo: 0},
+		{chatType: "group", chatID: -4567, wantReplyTo: 42},
+		{chatType: "supergroup", chatID: -1001234567890, wantReplyTo: 42},
+	}
+
+	for _, h := range handlers {
+		for _, chat := range chats {
+			t.Run(h.name+"/"+chat.chatType, func(t *testing.T) {
+				sender := &recordingSender{}
+
+				message := createTestMessage(h.text, 12345)
+				message.MessageID = 42
+				message.Chat.ID = chat.chatID
+				message.Chat.Type = chat.chatType
+
+				h.handle(context.Background(), sender, message)
+
+				if len(sender.sent) == 0 {
+					t.Fatal("handler sent nothing")
+				}
+				for i, c := range sender.sent {
+					if got := replyToID(t, c); got != chat.wantReplyTo {
+						t.Errorf("send %d: ReplyToMessageID = %d, want %d", i, got, chat.wantReplyTo)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestSendReply_ReplyNotFound tests the retry without reply reference
+//
+// Cases:
+//   - Reply target deleted: second send has no ReplyToMessageID
+//   - Other error: no retry, error returned
+func TestSendReply_ReplyNotFound(t *testing.T) {
+	notFoundErr := &tgbotapi.Error{Code: 400, Message: "Bad Request: message to be replied not found"}
+	otherErr := errors.New("network unreachable")
+
+	tests := []struct {
+		name      string
+		err       error
+		wantSends int
+		wantErr   bool
+	}{
+		{name: "reply target deleted", err: notFoundErr, wantSends: 2},
+		{name: "other error", err: otherErr, wantSends: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// parseErrorSender (format_test.go) fails the first Send with any error
+			sender := &parseErrorSender{failures: 1, err: tt.err}
+
+			message := createTestMessage("🎲 Dice", 12345)
+			message.MessageID = 42
+			message.Chat.ID = -4567
+			message.Chat.Type = "group"
+
+			// replyTo already sets the reply reference: the retry must strip it
+			_, err := sendReply(context.Background(), sender, message, replyTo(message, "🎲 You rolled: 4"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendReply(context.Background(), ) error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(sender.sent) != tt.wantSends {
+				t.Fatalf("got %d sends, want %d", len(sender.sent), tt.wantSends)
+			}
+
+			if replyToID(t, sender.sent[0]) != 42 {
+				t.Errorf("first send should reply to message 42")
+			}
+			if tt.wantSends == 2 && replyToID(t, sender.sent[1]) != 0 {
+				t.Errorf("retry should not reply to any message")
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
-	"log/slog"
+	"context"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -13,65 +17,217 @@ import (
 // Telegram Update structure can contain different types of updates:
 //   - Message: regular message from user
 //   - EditedMessage: user edited their previous message
-//   - CallbackQuery: user clicked inline keyboard button (not used - we use ReplyKeyboard)
+//   - CallbackQuery: user clicked inline keyboard button (see routeCallbackQuery)
 //   - InlineQuery: user typed @botname in any chat
 //   - ChosenInlineResult: user selected inline query result
+//   - MyChatMember: bot's own membership changed (blocked, added to group, ...)
 //   - ... and many more (see Telegram Bot API docs)
 //
 // Our routing strategy:
-//  1. Check which field in Update is non-nil
-//  2. Route based on update type
+//  1. Find the first route in updateRoutes whose field in Update is non-nil
+//  2. Hand the update to that route's handler
 //  3. For messages: route by command or button text
 //  4. Log and ignore unknown/unhandled updates
 //
 // Why this approach?
 //   - Simple and explicit (easy to understand)
-//   - Easy to extend (add new routes)
+//   - Easy to extend (add a route to updateRoutes)
 //   - Centralized routing logic (single source of truth)
 //   - Good logging for debugging
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance for sending responses
 //   - update: Update from Telegram (contains message, callback, etc.)
 //   - cfg: Application configuration (needed for authorization checks)
-func RouteUpdate(bot *tgbotapi.BotAPI, update tgbotapi.Update, cfg *config.Config) {
+func RouteUpdate(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
 	// Log incoming update for debugging
 	// update.UpdateID is unique identifier for each update
 	// Helps track update flow through the system
-	slog.Debug("Routing update",
-		"update_id", update.UpdateID,
+	log.Debug("Routing update",
 		"has_message", update.Message != nil,
 		"has_edited_message", update.EditedMessage != nil)
 
+	for _, route := range updateRoutes {
+		if route.matches(update) {
+			route.handle(ctx, bot, update, cfg)
+			return
+		}
+	}
+
+	// Unknown/unhandled update type
+	// This could be: ChosenInlineResult, Poll, etc.
+	// Telegram only sends these if they are in allowed_updates (see AllowedUpdates)
+	// Log for debugging but don't crash
+	log.Warn("Received unhandled update type")
+}
+
+// updateRoute connects one Telegram update type to its handler
+type updateRoute struct {
+	// updateType is the type's name in the Bot API (allowed_updates entry)
+	updateType string
+
+	// matches reports whether the update is of this type (its field is non-nil)
+	matches func(update tgbotapi.Update) bool
+
+	// handle processes the update
+	handle func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config)
+}
+
+// updateRoutes lists every update type the bot handles, checked in order.
+// AllowedUpdates derives the webhook's allowed_updates from this list, so a
+// new route here is automatically delivered by Telegram - no config change needed.
+var updateRoutes = []updateRoute{
 	// Route 1: Handle regular messages (commands, button clicks, text)
 	// update.Message is non-nil when user sends a message
 	// This includes:
 	//   - Commands (/start, /help)
 	//   - ReplyKeyboard button clicks (sends Message with button text)
 	//   - Regular text messages
-	if update.Message != nil {
-		routeMessage(bot, update.Message, cfg)
+	{
+		updateType: "message",
+		matches:    func(update tgbotapi.Update) bool { return update.Message != nil },
+		handle: func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+			// Identical message from the same user within floodWindow:
+			// a client resend, not a new request (see floodguard.go)
+			if from := update.Message.From; from != nil && !messageFloodGuard.allow(from.ID, update.Message.Text) {
+				logger.FromContext(ctx).Info("Ignoring duplicate message",
+					"text", update.Message.Text)
+				return
+			}
+			// "Last seen" registry for /users: throttled, written in the background
+			recordUser(ctx, update.Message)
+			// Known chats for /broadcast: only a chat's first message writes
+			recordChat(ctx, update.Message)
+			routeMessage(ctx, bot, update.Message, cfg)
+		},
+	},
+
+	// Route 2: Handle edited messages
+	// update.EditedMessage is non-nil when user edits their message
+	// Recently edited commands are routed like new ones (e.g., /hep fixed to /help)
+	// Everything else is logged and ignored, see routeEditedMessage
+	{
+		updateType: "edited_message",
+		matches:    func(update tgbotapi.Update) bool { return update.EditedMessage != nil },
+		handle: func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+			routeEditedMessage(ctx, bot, update.EditedMessage, cfg)
+		},
+	},
+
+	// Route 3: Handle changes of the bot's own membership in a chat
+	// (user blocked/unblocked the bot, bot added to/removed from a group)
+	{
+		updateType: "my_chat_member",
+		matches:    func(update tgbotapi.Update) bool { return update.MyChatMember != nil },
+		handle: func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+			HandleMyChatMember(ctx, bot, update.MyChatMember, cfg)
+		},
+	},
+
+	// Route 4: Handle inline queries ("@bot_username ovh lon" typed in any chat)
+	{
+		updateType: "inline_query",
+		matches:    func(update tgbotapi.Update) bool { return update.InlineQuery != nil },
+		handle: func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+			HandleInlineQuery(ctx, bot, update.InlineQuery, cfg)
+		},
+	},
+
+	// Route 5: Handle inline keyboard button clicks
+	// The main menu uses ReplyKeyboard, but every callback must be answered
+	// so the button's loading spinner clears, see routeCallbackQuery
+	{
+		updateType: "callback_query",
+		matches:    func(update tgbotapi.Update) bool { return update.CallbackQuery != nil },
+		handle: func(ctx context.Context, bot BotSender, update tgbotapi.Update, cfg *config.Config) {
+			routeCallbackQuery(ctx, bot, update.CallbackQuery, cfg)
+		},
+	},
+}
+
+// AllowedUpdates returns the update types RouteUpdate handles, in route order
+// Passed as allowed_updates when registering the webhook, so Telegram never
+// delivers updates the router would drop (polls, reactions, ...).
+//
+// Returns:
+//   - []string: Update type names (e.g., ["message", "edited_message", ...])
+func AllowedUpdates() []string {
+	return allowedUpdatesFor(updateRoutes)
+}
+
+// allowedUpdatesFor lists the update types of routes without duplicates
+// Separated from AllowedUpdates so tests can pass a registry fixture
+func allowedUpdatesFor(routes []updateRoute) []string {
+	types := make([]string, 0, len(routes))
+	for _, route := range routes {
+		if !slices.Contains(types, route.updateType) {
+			types = append(types, route.updateType)
+		}
+	}
+	return types
+}
+
+// startPayloadAction is an action triggered by a /start deep-link payload.
+// Same signature as button handlers, so existing handlers can be reused directly.
+type startPayloadAction func(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config)
+
+// startPayloads maps deep-link payloads to actions run after the /start welcome.
+// Link format: https://t.me/<bot_username>?start=<payload>
+//
+// To add a new deep link, add an entry here - no other changes needed.
+// Actions must do their own authorization checks (HandleOVHCheck already does).
+var startPayloads = map[string]startPayloadAction{
+	"ovh":  HandleOVHCheck,
+	"dice": handleDiceButton,
+}
+
+// editedMessageWindow is how long after sending a message an edit still counts as a command
+// Older edits are ignored, so fixing a typo in yesterday's message doesn't trigger anything
+var editedMessageWindow = 2 * time.Minute
+
+// routeEditedMessage routes an edited message through routeMessage when appropriate.
+//
+// Edits are routed only if ALL of these are true:
+//   - HANDLE_EDITED_MESSAGES is enabled
+//   - The edited text is a command (button labels and plain text are never re-run)
+//   - The edit happened within editedMessageWindow of the original message
+//
+// Telegram fields:
+//   - message.Date: when the original message was sent (Unix seconds)
+//   - message.EditDate: when it was last edited (Unix seconds)
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance
+//   - message: Edited message from Telegram
+//   - cfg: Application configuration
+func routeEditedMessage(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !cfg.HandleEditedMessages || !message.IsCommand() {
+		log.Debug("Ignoring edited message",
+			"is_command", message.IsCommand())
 		return
 	}
 
-	// Route 2: Handle edited messages (optional)
-	// update.EditedMessage is non-nil when user edits their message
-	// For most bots, edited messages can be ignored or treated same as new messages
-	// We log and ignore them for now
-	if update.EditedMessage != nil {
-		slog.Debug("Ignoring edited message",
-			"update_id", update.UpdateID,
-			"user_id", update.EditedMessage.From.ID,
-			"chat_id", update.EditedMessage.Chat.ID)
+	editDate := time.Unix(int64(message.EditDate), 0)
+	if message.EditDate == 0 {
+		editDate = time.Now()
+	}
+	if editDate.Sub(message.Time()) > editedMessageWindow {
+		log.Debug("Ignoring old edited command",
+			"command", message.Command(),
+			"edit_delay", editDate.Sub(message.Time()).String())
 		return
 	}
 
-	// Unknown/unhandled update type
-	// This could be: InlineQuery, ChosenInlineResult, Poll, CallbackQuery, etc.
-	// Note: We don't handle CallbackQuery anymore since we use ReplyKeyboard
-	// Log for debugging but don't crash
-	slog.Warn("Received unhandled update type",
-		"update_id", update.UpdateID)
+	log.Info("Routing edited command",
+		"command", message.Command())
+
+	routeMessage(ctx, bot, message, cfg)
 }
 
 // routeMessage routes Message updates to appropriate handlers.
@@ -83,51 +239,145 @@ func RouteUpdate(bot *tgbotapi.BotAPI, update tgbotapi.Update, cfg *config.Confi
 //   - If button: route to button handler
 //   - Otherwise: log and ignore
 //
+// Group chats (group/supergroup) are stricter, since many bots share the chat:
+//   - Commands with @mention are handled only if the mention is our username
+//   - Unknown commands are silently ignored (they're probably for another bot)
+//   - Button text is handled only in replies to our own messages
+//
 // ReplyKeyboard vs InlineKeyboard:
 //   - ReplyKeyboard: sends regular Message with button text
 //   - InlineKeyboard: sends CallbackQuery with callback_data
 //   - We use ReplyKeyboard, so button clicks arrive as Messages
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance
 //   - message: Message from Telegram
 //   - cfg: Application configuration
-func routeMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.Config) {
+func routeMessage(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	inGroup := isGroupChat(message.Chat)
+
 	// Route 1: Handle commands (messages starting with /)
 	if message.IsCommand() {
+		// In groups, /command@other_bot is not for us
+		if inGroup && !isCommandForBot(message, cfg.BotUsername) {
+			log.Debug("Ignoring group command addressed to another bot",
+				"command", message.CommandWithAt())
+			return
+		}
+
 		// Extract command text
 		// message.Command() returns command without / prefix
 		// Also removes bot username if present (/start@botname -> start)
 		command := message.Command()
 
 		// Log command for monitoring
-		slog.Info("Routing command",
+		log.Info("Routing command",
 			"command", command,
-			"user_id", message.From.ID,
-			"username", message.From.UserName,
-			"chat_id", message.Chat.ID)
-
-		// Route to appropriate handler based on command
-		switch command {
-		case "start":
-			// /start command - welcome message + keyboard
-			HandleStart(bot, message)
+			"username", message.From.UserName)
 
-		case "help":
-			// /help command - show available commands (with authorization)
-			HandleHelp(bot, message, cfg)
-
-		default:
+		// Route to the registered handler (see RegisteredCommands in commands.go)
+		cmd, ok := findCommand(command)
+		if ok && !cfg.Features.Enabled(cmd.Feature) {
+			// Disabled feature (e.g., ENABLE_OVH=false): behave as if the command didn't exist
+			log.Debug("Command of disabled feature",
+				"command", command,
+				"feature", cmd.Feature)
+			ok = false
+		}
+		if !ok {
 			// Unknown command - send friendly error message
-			sendUnknownCommandMessage(bot, message)
+			// Not in groups: other bots' commands without @mention land here too
+			if inGroup {
+				log.Debug("Ignoring unknown command in group chat",
+					"command", command)
+				return
+			}
+			countUsage(usageUnknown)
+			sendUnknownCommandMessage(ctx, bot, message)
+			return
+		}
+		countUsage(commandUsageFeature(cmd))
+
+		// Private commands are authorized (and audited) here, before their
+		// handler parses anything: new commands can't forget it
+		// Denials are counted per feature flag (/ovhcsv as "ovh"), like /usage
+		if cmd.IsPrivate {
+			var allowed bool
+			if ctx, allowed = authorize(ctx, bot, message, cfg, cmd.Feature); !allowed {
+				return
+			}
 		}
+		cmd.Handler(ctx, bot, message, cfg)
+		return
+	}
+
+	// In groups, ordinary chat text may match a button label by accident,
+	// so button text only counts when replying to one of our messages
+	if inGroup && !isReplyToBot(message, cfg.BotUsername) {
+		log.Debug("Ignoring group message that is not a reply to the bot")
 		return
 	}
 
 	// Route 2: Handle button clicks from ReplyKeyboard
 	// ReplyKeyboard buttons send regular messages with button text
 	// We check if message text matches any of our button labels
-	routeButtonMessage(bot, message, cfg)
+	routeButtonMessage(ctx, bot, message, cfg)
+}
+
+// commandUsageFeature is the /usage feature a command counts towards:
+// its feature flag (/ovhcsv counts as "ovh"), "help" for /help, "" (not counted) otherwise
+func commandUsageFeature(cmd Command) string {
+	if cmd.Name == "help" {
+		return usageHelp
+	}
+	return cmd.Feature
+}
+
+// isGroupChat reports whether the chat is a group or supergroup
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// isCommandForBot reports whether a command is addressed to this bot.
+//
+// Telegram command forms:
+//   - /start - no mention, addressed to every bot in the chat
+//   - /start@run_tbot - addressed to @run_tbot only
+//
+// Usernames are case-insensitive in Telegram, so comparison uses strings.EqualFold.
+// If botUsername is unknown (empty), only commands without a mention are accepted.
+//
+// Parameters:
+//   - message: Command message
+//   - botUsername: Our username without @
+//
+// Returns:
+//   - bool: true if we should handle the command
+func isCommandForBot(message *tgbotapi.Message, botUsername string) bool {
+	_, mention, hasMention := strings.Cut(message.CommandWithAt(), "@")
+	if !hasMention {
+		return true
+	}
+	return botUsername != "" && strings.EqualFold(mention, botUsername)
+}
+
+// isReplyToBot reports whether a message replies to one of this bot's messages
+//
+// Parameters:
+//   - message: Incoming message
+//   - botUsername: Our username without @
+//
+// Returns:
+//   - bool: true if message.ReplyToMessage was sent by us
+func isReplyToBot(message *tgbotapi.Message, botUsername string) bool {
+	reply := message.ReplyToMessage
+	if reply == nil || reply.From == nil || !reply.From.IsBot || botUsername == "" {
+		return false
+	}
+	return strings.EqualFold(reply.From.UserName, botUsername)
 }
 
 // routeButtonMessage routes ReplyKeyboard button clicks to appropriate handlers.
@@ -155,75 +405,141 @@ func routeMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.C
 //   - But: this is explicit and easy to maintain
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance
 //   - message: Message from Telegram containing button click
 //   - cfg: Application configuration (needed for authorization in OVH handler)
-func routeButtonMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message, cfg *config.Config) {
+func routeButtonMessage(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
 	// Extract and trim button text
 	// strings.TrimSpace removes any accidental whitespace
 	buttonText := message.Text
 
 	// Log button click for monitoring
-	slog.Info("Routing button click",
+	log.Info("Routing button click",
 		"button_text", buttonText,
-		"user_id", message.From.ID,
-		"username", message.From.UserName,
-		"chat_id", message.Chat.ID)
+		"username", message.From.UserName)
 
 	// Route to appropriate handler based on button text
 	// IMPORTANT: These strings must match button text in bot.GetMainKeyboard()
-	switch buttonText {
-	case "🎲 Dice":
-		// Single dice roll (1-6)
-		HandleDice(bot, message)
-
-	case "🎲🎲 Double Dice":
-		// Double dice roll (2-12)
-		HandleDoubleDice(bot, message)
+	// Disabled features (ENABLE_* flags) have no button, but the text can
+	// still be typed by hand or come from an old keyboard: ignore it
+	if feature := buttonFeatures[buttonText]; !cfg.Features.Enabled(feature) {
+		log.Info("Ignoring button of disabled feature",
+			"button_text", buttonText,
+			"feature", feature)
+		return
+	}
 
-	case "🌀 Twister":
-		// Twister game move
-		HandleTwister(bot, message)
+	countUsage(buttonFeatures[buttonText])
 
-	case "🖥️ OVH Servers":
-		// OVH server availability check (private)
-		HandleOVHCheck(bot, message, cfg)
+	// Acknowledge the press before answering (REACT_TO_REQUESTS)
+	reactToRequest(ctx, message, cfg, buttonReactions[buttonText])
 
-	default:
+	handler, ok := buttonRoutes[buttonText]
+	if !ok {
 		// Unknown button or regular text message
 		// Log but don't send error (could be user typing normally)
-		slog.Debug("Ignoring unknown button text or regular message",
-			"text", buttonText,
-			"user_id", message.From.ID,
-			"chat_id", message.Chat.ID)
+		log.Debug("Ignoring unknown button text or regular message",
+			"text", buttonText)
+		return
+	}
+	handler(ctx, bot, message, cfg)
+}
+
+// buttonRoutes maps button labels to their handlers
+// IMPORTANT: These strings must match button text in bot.GetMainKeyboard()
+// Admin buttons (Dedicated Servers, Stats, Broadcast, Settings) are only on
+// bot.GetAdminKeyboard; their handlers check authorization.
+//
+// A table rather than a switch, so --list-handlers can print the routes
+// (see Routes).
+var buttonRoutes = map[string]UpdateHandlerFunc{
+	"🎲 Dice":              handleDiceButton,
+	"🎲🎲 Double Dice":      handleDoubleDiceButton,
+	"🌀 Twister":           handleTwisterButton,
+	"🖥️ OVH Servers":      HandleOVHCheck,
+	"🔵 Dedicated Servers": HandleDedicatedOVHCheck,
+	"📊 Stats":             HandleAdminStats,
+	"📢 Broadcast":         HandleAdminBroadcast,
+	"⚙️ Settings":         HandleAdminSettings,
+}
+
+// buttonFeatures maps feature buttons to their feature flag (config.Feature*)
+// Buttons not listed here (Stats, Broadcast, Settings) are always routed.
+var buttonFeatures = map[string]string{
+	"🎲 Dice":              config.FeatureDice,
+	"🎲🎲 Double Dice":      config.FeatureDoubleDice,
+	"🌀 Twister":           config.FeatureTwister,
+	"🖥️ OVH Servers":      config.FeatureOVH,
+	"🔵 Dedicated Servers": config.FeatureOVH,
+}
+
+// handleDiceButton rolls a single die using the configured dice style.
+// USE_ANIMATED_DICE switches to Telegram's native animated dice.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance
+//   - message: Message that triggered the roll
+//   - cfg: Application configuration
+func handleDiceButton(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	if cfg.UseAnimatedDice {
+		HandleAnimatedDice(ctx, bot, message)
+		return
 	}
+	HandleDice(ctx, bot, message)
+}
+
+// handleDoubleDiceButton rolls two dice using the configured dice style.
+// USE_ANIMATED_DICE switches to Telegram's native animated dice.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Telegram Bot API instance
+//   - message: Message that triggered the roll
+//   - cfg: Application configuration
+func handleDoubleDiceButton(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	if cfg.UseAnimatedDice {
+		HandleAnimatedDoubleDice(ctx, bot, message)
+		return
+	}
+	HandleDoubleDice(ctx, bot, message, cfg)
+}
+
+// handleTwisterButton adapts HandleTwister (which needs no config) to
+// UpdateHandlerFunc for buttonRoutes
+func handleTwisterButton(ctx context.Context, bot BotSender, message *tgbotapi.Message, _ *config.Config) {
+	HandleTwister(ctx, bot, message)
 }
 
 // sendUnknownCommandMessage sends a friendly error message for unknown commands.
 // Helps users discover available commands without frustration.
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance
 //   - message: Original message with unknown command
-func sendUnknownCommandMessage(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func sendUnknownCommandMessage(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+	log := logger.FromContext(ctx)
+
 	// Log unknown command for analytics
 	// Helps identify which commands users expect but aren't implemented
-	slog.Info("Unknown command received",
-		"command", message.Command(),
-		"user_id", message.From.ID,
-		"chat_id", message.Chat.ID)
+	log.Info("Unknown command received",
+		"command", message.Command())
 
 	// Create friendly error message
 	// Don't just say "error" - guide user to /help
-	errorText := "❓ Unknown command. Use /help to see available commands."
+	errorText := translate(userLanguage(ctx, message.From), "unknown_command")
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, errorText)
+	msg := replyTo(message, errorText)
 
 	// Send error message
-	if _, err := bot.Send(msg); err != nil {
-		slog.Error("Failed to send unknown command message",
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send unknown command message",
 			"error", err,
-			"chat_id", message.Chat.ID,
+			"message_type", messageType(msg),
 			"command", message.Command())
 	}
 }
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestAllowedUpdatesFor tests deriving allowed_updates from a route registry
+//
+// Testing strategy:
+//   - Fixture registries instead of updateRoutes, so the test doesn't change
+//     every time a route is added
+//   - Order follows the registry, duplicates are listed once
+func TestAllowedUpdatesFor(t *testing.T) {
+	route := func(updateType string) updateRoute {
+		return updateRoute{
+			updateType: updateType,
+			matches:    func(tgbotapi.Update) bool { return false },
+			handle:     func(context.Context, BotSender, tgbotapi.Update, *config.Config) {},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		routes []updateRoute
+		want   []string
+	}{
+		{name: "messages only", routes: []updateRoute{route("message"), route("edited_message")},
+			want: []string{"message", "edited_message"}},
+		{name: "callback handler registered", routes: []updateRoute{route("message"), route("edited_message"), route("callback_query")},
+			want: []string{"message", "edited_message", "callback_query"}},
+		{name: "duplicate type", routes: []updateRoute{route("message"), route("inline_query"), route("message")},
+			want: []string{"message", "inline_query"}},
+		{name: "empty registry", routes: nil, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allowedUpdatesFor(tt.routes); !slices.Equal(got, tt.want) {
+				t.Errorf("allowedUpdatesFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAllowedUpdates tests that every routed update type is requested from Telegram
+func TestAllowedUpdates(t *testing.T) {
+	got := AllowedUpdates()
+	for _, want := range []string{"message", "edited_message", "my_chat_member", "inline_query", "callback_query"} {
+		if !slices.Contains(got, want) {
+			t.Errorf("AllowedUpdates() = %v, missing %q", got, want)
+		}
+	}
+}
+
+// TestRouteMessage pins which handler routeMessage dispatches to
+//
+// Testing strategy:
+//   - recordingSender stands in for Telegram; each handler is recognized
+//     by what it sends (dice result, welcome text, unknown command hint)
+//   - Private chat of an authorized user, all features enabled (testConfig)
+//
+// Cases:
+//   - "🎲 Dice" button: HandleDice ("⚃ You rolled a N")
+//   - /start: HandleStart (welcome text with the keyboard)
+//   - Unknown command: sendUnknownCommandMessage
+//   - Unknown text: ignored, nothing is sent (users may just be chatting)
+//   - Button of a disabled feature: ignored
+func TestRouteMessage(t *testing.T) {
+	diceResult := regexp.MustCompile(`^[⚀-⚅] You rolled a [1-6]$`)
+
+	tests := []struct {
+		name     string
+		text     string
+		features config.Features
+		check    func(t *testing.T, messages []tgbotapi.MessageConfig)
+	}{
+		{
+			name:     "dice button",
+			text:     "🎲 Dice",
+			features: config.AllFeatures(),
+			check: func(t *testing.T, messages []tgbotapi.MessageConfig) {
+				if len(messages) != 1 || !diceResult.MatchString(messages[0].Text) {
+					t.Errorf("messages = %+v, want one dice result", messages)
+				}
+			},
+		},
+		{
+			name:     "start command",
+			text:     "/start",
+			features: config.AllFeatures(),
+			check: func(t *testing.T, messages []tgbotapi.MessageConfig) {
+				if len(messages) != 1 || messages[0].Text != formatStartMessage("en", "Test", bot.UserButtons(config.AllFeatures(), true)) || messages[0].ReplyMarkup == nil {
+					t.Errorf("messages = %+v, want the welcome text with a keyboard", messages)
+				}
+			},
+		},
+		{
+			name:     "unknown command",
+			text:     "/nope",
+			features: config.AllFeatures(),
+			check: func(t *testing.T, messages []tgbotapi.MessageConfig) {
+				if len(messages) != 1 || !strings.HasPrefix(messages[0].Text, "❓ Unknown command") {
+					t.Errorf("messages = %+v, want the unknown command hint", messages)
+				}
+			},
+		},
+		{
+			name:     "unknown text",
+			text:     "hello bot",
+			features: config.AllFeatures(),
+			check: func(t *testing.T, messages []tgbotapi.MessageConfig) {
+				if len(messages) != 0 {
+					t.Errorf("messages = %+v, want none for plain text", messages)
+				}
+			},
+		},
+		{
+			name:     "button of disabled feature",
+			text:     "🎲 Dice",
+			features: config.Features{Twister: true},
+			check: func(t *testing.T, messages []tgbotapi.MessageConfig) {
+				if len(messages) != 0 {
+					t.Errorf("messages = %+v, want none with dice disabled", messages)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Features = tt.features
+			sender := &recordingSender{}
+
+			routeMessage(context.Background(), sender, createTestMessage(tt.text, 12345), cfg)
+
+			tt.check(t, sender.messages())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+)
+
+// Route types listed by Routes
+const (
+	RouteCommand  = "command"
+	RouteButton   = "button"
+	RouteCallback = "callback"
+)
+
+// Route is one entry point of the bot, as printed by --list-handlers
+type Route struct {
+	Type    string // RouteCommand, RouteButton or RouteCallback
+	Name    string // "/ovh", "🎲 Dice" or a callback_data pattern ("ovh:*")
+	Handler string // Handler function, e.g. "handlers.HandleOVHCheck"
+	Private bool   // Only for authorized users (ALLOWED_USERS)
+}
+
+// Routes lists every registered command, keyboard button and inline
+// button action
+//
+// Sources (the same tables the router reads):
+//   - Commands: RegisteredCommands, in /help order
+//   - Buttons: bot.UserButtons with every feature enabled, as an authorized
+//     user sees them (keyboard order); handlers from buttonRoutes
+//   - Callbacks: callbackActions, sorted by prefix
+//
+// Needs no configuration or bot token, so it runs before config.Load.
+//
+// Returns:
+//   - []Route: Commands, then buttons, then callbacks
+func Routes() []Route {
+	var routes []Route
+
+	for _, cmd := range RegisteredCommands {
+		routes = append(routes, Route{
+			Type:    RouteCommand,
+			Name:    "/" + cmd.Name,
+			Handler: funcName(cmd.Handler),
+			Private: cmd.IsPrivate,
+		})
+	}
+
+	for _, row := range bot.UserButtons(config.AllFeatures(), true) {
+		for _, button := range row {
+			routes = append(routes, Route{
+				Type:    RouteButton,
+				Name:    button.Text,
+				Handler: funcName(buttonRoutes[button.Text]),
+				Private: button.Private,
+			})
+		}
+	}
+
+	prefixes := make([]string, 0, len(callbackActions))
+	for prefix := range callbackActions {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		routes = append(routes, Route{
+			Type:    RouteCallback,
+			Name:    prefix + ":*",
+			Handler: funcName(callbackActions[prefix]),
+			Private: privateCallbacks[prefix],
+		})
+	}
+
+	return routes
+}
+
+// funcName returns the name of a function, e.g. "handlers.HandleOVHCheck"
+//
+// runtime.FuncForPC gives the full import path
+// ("github.com/Alrem/run-tbot/handlers.HandleOVHCheck"); only the last
+// path element is kept. A nil function (button without a route) is "-".
+func funcName(f any) string {
+	v := reflect.ValueOf(f)
+	if !v.IsValid() || v.Kind() != reflect.Func || v.IsNil() {
+		return "-"
+	}
+
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return "-"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
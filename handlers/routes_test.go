@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+)
+
+// TestRoutes tests that every command, button and callback is listed with
+// a named handler
+func TestRoutes(t *testing.T) {
+	routes := Routes()
+
+	counts := make(map[string]int)
+	for _, route := range routes {
+		counts[route.Type]++
+		if route.Handler == "-" || route.Handler == "" {
+			t.Errorf("%s %q has no handler", route.Type, route.Name)
+		}
+	}
+
+	buttons := 0
+	for _, row := range bot.UserButtons(config.AllFeatures(), true) {
+		buttons += len(row)
+	}
+	if counts[RouteCommand] != len(RegisteredCommands) {
+		t.Errorf("listed %d commands, want %d", counts[RouteCommand], len(RegisteredCommands))
+	}
+	if counts[RouteButton] != buttons {
+		t.Errorf("listed %d buttons, want %d", counts[RouteButton], buttons)
+	}
+	if counts[RouteCallback] != len(callbackActions) {
+		t.Errorf("listed %d callbacks, want %d", counts[RouteCallback], len(callbackActions))
+	}
+
+	want := map[string]Route{
+		"/start":         {Type: RouteCommand, Name: "/start", Handler: "handlers.HandleStart"},
+		"/ovh":           {Type: RouteCommand, Name: "/ovh", Handler: "handlers.HandleOVHCommand", Private: true},
+		"/hide":          {Type: RouteCommand, Name: "/hide", Handler: "handlers.HandleHide"},
+		"🎲🎲 Double Dice": {Type: RouteButton, Name: "🎲🎲 Double Dice", Handler: "handlers.handleDoubleDiceButton"},
+		"📊 Stats":        {Type: RouteButton, Name: "📊 Stats", Handler: "handlers.HandleAdminStats", Private: true},
+		"ovh:*":          {Type: RouteCallback, Name: "ovh:*", Handler: "handlers.handleOVHCallback", Private: true},
+	}
+	for _, route := range routes {
+		if w, ok := want[route.Name]; ok {
+			if route != w {
+				t.Errorf("route = %+v, want %+v", route, w)
+			}
+			delete(want, route.Name)
+		}
+	}
+	for name := range want {
+		t.Errorf("route %q not listed", name)
+	}
+}
+
+// TestFuncName tests handler names, including missing handlers
+func TestFuncName(t *testing.T) {
+	var missing UpdateHandlerFunc
+
+	tests := []struct {
+		name string
+		f    any
+		want string
+	}{
+		{name: "exported", f: HandleHelp, want: "handlers.HandleHelp"},
+		{name: "typed", f: UpdateHandlerFunc(handleDiceButton), want: "handlers.handleDiceButton"},
+		{name: "nil func", f: missing, want: "-"},
+		{name: "nil", f: nil, want: "-"},
+		{name: "not a func", f: "HandleHelp", want: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := funcName(tt.f); got != tt.want {
+				t.Errorf("funcName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"github.com/Alrem/run-tbot/bot"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// BotSender is the interface all handlers use to talk to Telegram.
+// It is a type alias for bot.BotSender, so both names refer to the same type.
+//
+// Why an alias here?
+//   - Handler signatures read naturally: HandleDice(ctx context.Context, bot BotSender, ...)
+//   - Most handler files name their parameter "bot", which would shadow
+//     the bot package if we wrote bot.BotSender in every signature
+//
+// In production the sender is *tgbotapi.BotAPI; in tests it is
+// a recording fake (see sender_test.go).
+type BotSender = bot.BotSender
+
+// messageType is bot.MessageType, for the "message_type" field of send failure logs
+// Needed because handler parameters named "bot" shadow the bot package.
+func messageType(c tgbotapi.Chattable) string {
+	return bot.MessageType(c)
+}
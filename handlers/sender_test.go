@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// recordingSender is a fake BotSender for tests.
+// Instead of calling Telegram, it records every Chattable passed to Send/Request
+// so tests can assert on what a handler would have sent.
+//
+// Usage:
+//
+//	sender := &recordingSender{}
+//	HandleMenu(context.Background(), sender, createTestMessage("/menu", 12345), testConfig())
+//	msg := sender.sent[0].(tgbotapi.MessageConfig)
+//
+// Behavior can be tweaked per test:
+//   - sendErr: error returned by every Send call
+//   - requestErr: error returned by every Request call
+//   - respond: builds the Message returned by Send (e.g., to fill in Dice values)
+type recordingSender struct {
+	mu        sync.Mutex
+	sent      []tgbotapi.Chattable // Everything passed to Send, in order
+	requested []tgbotapi.Chattable // Everything passed to Request, in order
+
+	sendErr    error
+	requestErr error
+	respond    func(c tgbotapi.Chattable) tgbotapi.Message
+}
+
+// Send records the Chattable and returns an empty Message (or sendErr)
+func (s *recordingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sent = append(s.sent, c)
+	if s.sendErr != nil {
+		return tgbotapi.Message{}, s.sendErr
+	}
+	if s.respond != nil {
+		return s.respond(c), nil
+	}
+	return tgbotapi.Message{MessageID: len(s.sent)}, nil
+}
+
+// Request records the Chattable and returns a successful response (or requestErr)
+func (s *recordingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requested = append(s.requested, c)
+	if s.requestErr != nil {
+		return nil, s.requestErr
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// messages returns all sent MessageConfig values (skips documents, dice, etc.)
+func (s *recordingSender) messages() []tgbotapi.MessageConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []tgbotapi.MessageConfig
+	for _, c := range s.sent {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// TestSendFailureLogsMessageType tests that send failures are logged
+// with the concrete Chattable type in the "message_type" field
+func TestSendFailureLogsMessageType(t *testing.T) {
+	sendErr := errors.New("telegram down")
+
+	tests := []struct {
+		name     string
+		run      func(ctx context.Context, sender *recordingSender)
+		wantType string
+	}{
+		{
+			name: "text message",
+			run: func(ctx context.Context, sender *recordingSender) {
+				HandleStart(ctx, sender, createTestMessage("/start", 12345), testConfig())
+			},
+			wantType: "MessageConfig",
+		},
+		{
+			name: "animated dice",
+			run: func(ctx context.Context, sender *recordingSender) {
+				HandleAnimatedDice(ctx, sender, createTestMessage("🎲 Dice", 12345))
+			},
+			wantType: "DiceConfig",
+		},
+		{
+			name: "callback answer",
+			run: func(ctx context.Context, sender *recordingSender) {
+				answerCallback(ctx, sender, &tgbotapi.CallbackQuery{ID: "cb-1"}, "")
+			},
+			wantType: "CallbackConfig",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := logger.WithContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+			tt.run(ctx, &recordingSender{sendErr: sendErr, requestErr: sendErr})
+
+			want := `"message_type":"` + tt.wantType + `"`
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("log output missing %s:\n%s", want, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// serverMapBaseURL renders OpenStreetMap tiles with markers into one PNG
+// (no API key needed); serverMapURL adds the center, size and markers
+const serverMapBaseURL = "https://staticmap.openstreetmap.de/staticmap.php"
+
+// HandleServerMap handles the /server_map command.
+// Sends a world map with every OVH datacenter marked, captioned with the codes and names.
+//
+// The image is not generated by the bot: the photo is sent as a URL
+// (bot.NewPhotoFromURL) and Telegram downloads it from the static map service.
+// If Telegram can't fetch it (service down, timeout), the caption is sent
+// as a text message, so the user still gets the list.
+//
+// Public: the datacenter list is no secret (same data as ovh.ListDatacenters).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - botAPI: Bot sender for sending messages
+//   - message: Message from Telegram containing the /server_map command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleServerMap(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	log.Info("/server_map command received")
+
+	datacenters := ovh.ListDatacenters()
+	caption := formatServerMapCaption(userLanguage(ctx, message.From), datacenters)
+
+	photo := bot.NewPhotoFromURL(message.Chat.ID, serverMapURL(datacenters), caption)
+	_, err := sendReply(ctx, botAPI, message, photo)
+	if err == nil {
+		return
+	}
+	log.Warn("Failed to send server map photo, sending the list as text",
+		"error", err,
+		"message_type", messageType(photo))
+
+	msg := replyTo(message, caption)
+	if _, err := sendReply(ctx, botAPI, message, msg); err != nil {
+		log.Error("Failed to send server map text",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// serverMapURL builds the static map URL with one marker per datacenter
+//
+// Example (shortened):
+//
+//	https://staticmap.openstreetmap.de/staticmap.php?center=25,0&zoom=1&size=1024x512&markers=50.69,3.17,red-pushpin|...
+//
+// Parameters:
+//   - datacenters: Datacenters to mark (Latitude/Longitude)
+//
+// Returns:
+//   - string: Image URL
+func serverMapURL(datacenters []ovh.DatacenterInfo) string {
+	markers := make([]string, 0, len(datacenters))
+	for _, dc := range datacenters {
+		markers = append(markers, fmt.Sprintf("%.2f,%.2f,red-pushpin", dc.Latitude, dc.Longitude))
+	}
+
+	query := url.Values{}
+	query.Set("center", "25,0") // slightly north: most datacenters are in Europe and North America
+	query.Set("zoom", "1")      // whole world
+	query.Set("size", "1024x512")
+	query.Set("markers", strings.Join(markers, "|"))
+	return serverMapBaseURL + "?" + query.Encode()
+}
+
+// formatServerMapCaption lists the datacenters for the photo caption (plain text)
+//
+// Example:
+//
+//	🗺️ OVH datacenters
+//
+//	BHS · Beauharnois, Canada
+//	ERI · Erith, UK
+//	...
+//
+// 17 datacenters take ~500 characters, well below the 1024-character caption limit.
+// Only the title is translated (lang); datacenter names come from the ovh package.
+func formatServerMapCaption(lang string, datacenters []ovh.DatacenterInfo) string {
+	var sb strings.Builder
+	sb.WriteString("🗺️ " + translate(lang, "server_map_title") + "\n")
+	for _, dc := range datacenters {
+		fmt.Fprintf(&sb, "\n%s · %s", strings.ToUpper(dc.Code), dc.Name)
+	}
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// photoFailingSender fails every PhotoConfig (Telegram couldn't fetch the URL)
+// and records everything like recordingSender
+type photoFailingSender struct {
+	recordingSender
+}
+
+func (s *photoFailingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, err := s.recordingSender.Send(c)
+	if _, ok := c.(tgbotapi.PhotoConfig); ok {
+		return tgbotapi.Message{}, errors.New("Bad Request: failed to get HTTP URL content")
+	}
+	return msg, err
+}
+
+// TestHandleServerMap tests the /server_map photo
+//
+// Checks:
+//   - One PhotoConfig to the user's chat, with the map URL as FileURL
+//   - The caption lists every datacenter code and name
+//   - The URL has one marker per datacenter
+//   - Works for users outside ALLOWED_USERS (public command)
+func TestHandleServerMap(t *testing.T) {
+	sender := &recordingSender{}
+	message := createTestMessage("/server_map", 99999)
+
+	HandleServerMap(context.Background(), sender, message, testConfig())
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d Chattables, want 1", len(sender.sent))
+	}
+	photo, ok := sender.sent[0].(tgbotapi.PhotoConfig)
+	if !ok {
+		t.Fatalf("sent %T, want tgbotapi.PhotoConfig", sender.sent[0])
+	}
+	if photo.ChatID != message.Chat.ID {
+		t.Errorf("ChatID = %d, want %d", photo.ChatID, message.Chat.ID)
+	}
+
+	datacenters := ovh.ListDatacenters()
+	for _, dc := range datacenters {
+		if !strings.Contains(photo.Caption, strings.ToUpper(dc.Code)+" · "+dc.Name) {
+			t.Errorf("caption doesn't list %s (%s):\n%s", dc.Code, dc.Name, photo.Caption)
+		}
+	}
+	if len(photo.Caption) > 1024 {
+		t.Errorf("caption is %d characters, Telegram allows 1024", len(photo.Caption))
+	}
+
+	file, ok := photo.File.(tgbotapi.FileURL)
+	if !ok {
+		t.Fatalf("File = %T, want tgbotapi.FileURL", photo.File)
+	}
+	mapURL, err := url.Parse(string(file))
+	if err != nil || !strings.HasPrefix(string(file), serverMapBaseURL+"?") {
+		t.Fatalf("File = %q, want a %s URL", file, serverMapBaseURL)
+	}
+	if markers := strings.Split(mapURL.Query().Get("markers"), "|"); len(markers) != len(datacenters) {
+		t.Errorf("map has %d markers, want %d", len(markers), len(datacenters))
+	}
+}
+
+// TestHandleServerMap_Group tests that the photo replies to the command in groups
+func TestHandleServerMap_Group(t *testing.T) {
+	sender := &recordingSender{}
+	message := createTestMessage("/server_map", 99999)
+	message.Chat = &tgbotapi.Chat{ID: -100123, Type: "supergroup"}
+	message.MessageID = 42
+
+	HandleServerMap(context.Background(), sender, message, testConfig())
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d Chattables, want 1", len(sender.sent))
+	}
+	if photo := sender.sent[0].(tgbotapi.PhotoConfig); photo.ReplyToMessageID != 42 {
+		t.Errorf("ReplyToMessageID = %d, want 42", photo.ReplyToMessageID)
+	}
+}
+
+// TestHandleServerMap_PhotoFails tests the text fallback when Telegram can't fetch the image
+func TestHandleServerMap_PhotoFails(t *testing.T) {
+	sender := &photoFailingSender{}
+
+	HandleServerMap(context.Background(), sender, createTestMessage("/server_map", 99999), testConfig())
+
+	messages := sender.messages()
+	if len(sender.sent) != 2 || len(messages) != 1 {
+		t.Fatalf("sent %d Chattables (%d messages), want the photo then one text message", len(sender.sent), len(messages))
+	}
+	if want := formatServerMapCaption("en", ovh.ListDatacenters()); messages[0].Text != want {
+		t.Errorf("fallback text = %q, want the caption %q", messages[0].Text, want)
+	}
+}
@@ -1,9 +1,13 @@
 package handlers
 
 import (
-	"log/slog"
+	"context"
+	"regexp"
+	"strings"
 
 	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -17,59 +21,148 @@ import (
 //
 // Our implementation:
 //  1. Sends welcome message explaining what the bot does
-//  2. Attaches reply keyboard with all bot features (persistent buttons at bottom)
-//  3. User can immediately try any feature via keyboard buttons
+//  2. Attaches reply keyboard with the features this user can use
+//     (persistent buttons at bottom; see bot.UserButtons)
+//  3. User can immediately try any listed feature via keyboard buttons
+//  4. If the command carries a deep-link payload, runs the matching action
+//
+// Deep links:
+//   - Link t.me/<bot>?start=ovh makes the client send "/start ovh"
+//   - message.CommandArguments() returns the payload ("ovh")
+//   - Payload → action mapping lives in router.go (startPayloads)
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - botAPI: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing the /start command
-func HandleStart(botAPI *tgbotapi.BotAPI, message *tgbotapi.Message) {
+//   - cfg: Application configuration (features, authorization for the keyboard and payload actions)
+func HandleStart(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
 	// Log the start command for monitoring
 	// Track user_id to understand bot adoption
 	// Track username (may be empty if user hasn't set it)
-	slog.Info("/start command received",
-		"user_id", message.From.ID,
-		"username", message.From.UserName,
-		"chat_id", message.Chat.ID)
+	log.Info("/start command received",
+		"username", message.From.UserName)
+
+	// Step 1: Pick the buttons this user can use
+	// Disabled features and, for unauthorized users, private features are
+	// left out; the welcome text and the keyboard are both built from these
+	// rows, so the text never mentions a button the user doesn't have
+	buttons := bot.UserButtons(cfg.Features, cfg.IsUserAllowed(message.From.ID, message.From.UserName))
 
-	// Step 1: Create welcome message text
+	// Step 2: Create welcome message text
 	// message.From.FirstName is user's first name from their Telegram profile
 	// Using FirstName makes the message more personal and friendly
-	welcomeText := formatStartMessage(message.From.FirstName)
+	// The text is in the user's language (see userLanguage); button labels stay English
+	welcomeText := formatStartMessage(userLanguage(ctx, message.From), message.From.FirstName, buttons)
 
-	// Step 2: Create message configuration
-	// NewMessage creates a MessageConfig structure
-	// Parameters: chatID (where to send), text (message content)
-	msg := tgbotapi.NewMessage(message.Chat.ID, welcomeText)
+	// Step 3: Create message configuration
+	// replyTo creates a MessageConfig (see bot.Reply)
+	// In groups it replies to the /start message, in private chats it's a plain message
+	msg := replyTo(message, welcomeText)
 
-	// Step 3: Attach reply keyboard with all bot features
-	// bot.GetMainKeyboard() returns ReplyKeyboardMarkup with 4 buttons:
-	//   - 🎲 Dice, 🎲🎲 Double Dice, 🌀 Twister, 🖥️ OVH Servers
+	// Step 4: Attach the reply keyboard with the same buttons
+	//   - Everyone: 🎲 Dice, 🎲🎲 Double Dice, 🌀 Twister (if enabled)
+	//   - Authorized users: also 🖥️ OVH Servers and the admin row
 	// When user clicks button, we'll receive regular Message with button text
 	// These messages will be routed by router.go to appropriate handlers
-	msg.ReplyMarkup = bot.GetMainKeyboard()
+	msg.ReplyMarkup = replyKeyboard(bot.ButtonKeyboard(buttons))
 
-	// Step 4: Send the message
-	// bot.Send() returns (Message, error)
+	// Step 5: Send the message
+	// sendReply returns (Message, error)
 	// We ignore the returned Message (we don't need message_id for anything)
-	if _, err := botAPI.Send(msg); err != nil {
+	if _, err := sendReply(ctx, botAPI, message, msg); err != nil {
 		// If sending fails, log the error
 		// Possible causes:
 		//   - Bot was blocked by user
 		//   - Chat doesn't exist
 		//   - Network/API error
-		slog.Error("Failed to send /start message",
+		log.Error("Failed to send /start message",
 			"error", err,
-			"chat_id", message.Chat.ID,
-			"user_id", message.From.ID)
+			"message_type", messageType(msg))
 		return
 	}
 
 	// Log successful send for monitoring
 	// This helps track bot usage and successful interactions
-	slog.Info("/start message sent successfully",
-		"chat_id", message.Chat.ID,
-		"user_id", message.From.ID)
+	log.Info("/start message sent successfully")
+
+	// Step 6: Run deep-link action (if any) after the welcome
+	handleStartPayload(ctx, botAPI, message, cfg)
+}
+
+// keyboardForUser picks the reply keyboard for a user
+// Authorized users see private features and the admin row, everyone else
+// only the public features (see bot.UserButtons)
+//
+// Parameters:
+//   - user: Telegram user (message.From)
+//   - cfg: Application configuration with the allowlist and Features
+//
+// Returns:
+//   - interface{}: Markup to attach to the message (see replyKeyboard)
+func keyboardForUser(user *tgbotapi.User, cfg *config.Config) interface{} {
+	return replyKeyboard(bot.GetUserKeyboard(cfg.Features, cfg.IsUserAllowed(user.ID, user.UserName)))
+}
+
+// replyKeyboard returns keyboard as message markup, or removes the keyboard
+// if it has no buttons (every feature disabled): Telegram rejects empty keyboards.
+//
+// Parameters:
+//   - keyboard: Keyboard from bot.ButtonKeyboard or bot.GetUserKeyboard
+//
+// Returns:
+//   - interface{}: ReplyKeyboardMarkup, or ReplyKeyboardRemove if empty
+func replyKeyboard(keyboard tgbotapi.ReplyKeyboardMarkup) interface{} {
+	if len(keyboard.Keyboard) == 0 {
+		return tgbotapi.NewRemoveKeyboard(false)
+	}
+	return keyboard
+}
+
+// startPayloadPattern matches payloads Telegram allows in deep links
+// Per Bot API docs: up to 64 characters, only A-Z, a-z, 0-9, _ and -
+var startPayloadPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// handleStartPayload runs the action registered for a /start deep-link payload.
+//
+// Cases:
+//   - No payload: plain /start, nothing to do
+//   - Invalid characters: someone typed "/start <junk>" by hand, log and ignore
+//   - Unknown payload: log and ignore (the welcome was already sent)
+//   - Known payload: run its action from startPayloads
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - botAPI: Telegram Bot API instance for sending messages
+//   - message: Message from Telegram containing the /start command
+//   - cfg: Application configuration
+func handleStartPayload(ctx context.Context, botAPI BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	payload := strings.TrimSpace(message.CommandArguments())
+	if payload == "" {
+		return
+	}
+
+	if !startPayloadPattern.MatchString(payload) {
+		log.Warn("Ignoring /start payload with invalid characters",
+			"payload", payload)
+		return
+	}
+
+	action, ok := startPayloads[payload]
+	if !ok {
+		log.Info("Unknown /start payload, showing plain welcome",
+			"payload", payload)
+		return
+	}
+
+	log.Info("Running /start payload action",
+		"payload", payload)
+
+	action(ctx, botAPI, message, cfg)
 }
 
 // formatStartMessage creates the welcome message text for /start command.
@@ -78,33 +171,50 @@ func HandleStart(botAPI *tgbotapi.BotAPI, message *tgbotapi.Message) {
 // The message should:
 //   - Be friendly and welcoming
 //   - Explain what the bot does
-//   - List available features
+//   - List the features the user can use (exactly the keyboard buttons)
 //   - Encourage user to try the features
 //
 // Parameters:
+//   - lang: Language of the text (see userLanguage; button labels stay English,
+//     they are what the keyboard sends)
 //   - firstName: User's first name from Telegram profile
+//   - buttons: The user's keyboard rows (bot.UserButtons)
 //
 // Returns:
 //   - string: Formatted welcome message
-func formatStartMessage(firstName string) string {
+func formatStartMessage(lang, firstName string, buttons [][]bot.Button) string {
 	// Fallback to "there" if firstName is empty
 	// This can happen if user hasn't set their first name in Telegram
 	// (rare, but possible)
 	name := firstName
 	if name == "" {
-		name = "there"
+		name = translate(lang, "welcome_default_name")
 	}
 
-	// Use multiline string for better readability
 	// The message explains:
 	//   1. What the bot does (educational project)
-	//   2. Available features (4 buttons)
+	//   2. Available features (one line per keyboard button)
 	//   3. Call to action (use the keyboard)
-	return "👋 Hello, " + name + "!\n\n" +
-		"Welcome to Run-Tbot - an educational Telegram bot built with Go.\n\n" +
-		"Try these features using the keyboard below:\n" +
-		"🎲 Dice - Roll a single die (1-6)\n" +
-		"🎲🎲 Double Dice - Roll two dice (2-12)\n" +
-		"🌀 Twister - Get a random Twister move\n" +
-		"🖥️ OVH Servers - Check server availability"
+	var sb strings.Builder
+	sb.WriteString(translate(lang, "welcome_message", name) + "\n\n")
+
+	// Every feature disabled: there's no keyboard to point at
+	if len(buttons) == 0 {
+		sb.WriteString(translate(lang, "welcome_no_features"))
+		return sb.String()
+	}
+
+	sb.WriteString(translate(lang, "welcome_try_features"))
+	for _, row := range buttons {
+		for _, button := range row {
+			sb.WriteString("\n" + button.Text + " - " + buttonDescription(lang, button))
+		}
+	}
+	return sb.String()
+}
+
+// buttonDescription returns a button's /start description in a language
+// (i18n key button_<Key>; its English text is button.Description)
+func buttonDescription(lang string, button bot.Button) string {
+	return translate(lang, "button_"+button.Key)
 }
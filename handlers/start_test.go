@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"strings"
 	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/ovh"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // TestFormatStartMessage tests the formatStartMessage function with various inputs.
@@ -82,7 +88,7 @@ func TestFormatStartMessage(t *testing.T) {
 		// Subtest name appears in output: TestFormatStartMessage/normal_user_with_first_name
 		t.Run(tt.name, func(t *testing.T) {
 			// Call the function being tested
-			result := formatStartMessage(tt.input)
+			result := formatStartMessage("en", tt.input, bot.UserButtons(config.AllFeatures(), true))
 
 			// Verify result contains all expected strings
 			for _, expected := range tt.expectedContains {
@@ -122,21 +128,174 @@ func TestFormatStartMessage(t *testing.T) {
 //   - Flexibility to improve wording without breaking tests
 //   - Focus on behavior, not implementation details
 
-// Note on testing HandleStart:
-// We don't test HandleStart directly because it requires:
-//   - Mock BotAPI (complex setup)
-//   - Mock Message structure
-//   - Assertions on bot.Send calls
+// TestHandleStart_KeyboardMatchesText tests that /start lists exactly the keyboard buttons
+//
+// Checks, per scenario:
+//   - Keyboard buttons are the expected ones (no private buttons for unauthorized users)
+//   - Every keyboard button has a line in the welcome text, and every
+//     feature line in the text is a keyboard button
 //
-// For an educational project, this is overkill.
-// In production, you might use:
-//   - testify/mock package for mocking
-//   - Dependency injection for bot interface
-//   - Integration tests with real bot in test mode
+// Cases:
+//   - Authorized user, all features: 4 feature buttons + admin row
+//   - Unauthorized user, all features: no 🖥️ OVH Servers, no admin row
+//   - Authorized user, Twister and OVH disabled
+//   - Unauthorized user, only OVH enabled: no buttons, keyboard removed
+func TestHandleStart_KeyboardMatchesText(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      int64
+		features    config.Features
+		wantButtons []string
+	}{
+		{
+			name:        "authorized, all features",
+			userID:      12345,
+			features:    config.AllFeatures(),
+			wantButtons: []string{"🎲 Dice", "🎲🎲 Double Dice", "🌀 Twister", "🖥️ OVH Servers", "🔵 Dedicated Servers", "📊 Stats", "📢 Broadcast", "⚙️ Settings"},
+		},
+		{
+			name:        "unauthorized, all features",
+			userID:      99999,
+			features:    config.AllFeatures(),
+			wantButtons: []string{"🎲 Dice", "🎲🎲 Double Dice", "🌀 Twister"},
+		},
+		{
+			name:        "authorized, features disabled",
+			userID:      12345,
+			features:    config.Features{Dice: true, DoubleDice: true},
+			wantButtons: []string{"🎲 Dice", "🎲🎲 Double Dice", "📊 Stats", "📢 Broadcast", "⚙️ Settings"},
+		},
+		{
+			name:        "unauthorized, only private features",
+			userID:      99999,
+			features:    config.Features{OVH: true},
+			wantButtons: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Features = tt.features
+			sender := &recordingSender{}
+
+			HandleStart(context.Background(), sender, createTestMessage("/start", tt.userID), cfg)
+
+			messages := sender.messages()
+			if len(messages) != 1 {
+				t.Fatalf("HandleStart() sent %d messages, want 1", len(messages))
+			}
+			text := messages[0].Text
+
+			var got []string
+			switch markup := messages[0].ReplyMarkup.(type) {
+			case tgbotapi.ReplyKeyboardMarkup:
+				for _, row := range markup.Keyboard {
+					for _, button := range row {
+						got = append(got, button.Text)
+					}
+				}
+			case tgbotapi.ReplyKeyboardRemove:
+				// No buttons: the keyboard is removed
+			default:
+				t.Fatalf("ReplyMarkup = %T, want a reply keyboard or its removal", markup)
+			}
+
+			if strings.Join(got, ",") != strings.Join(tt.wantButtons, ",") {
+				t.Errorf("keyboard buttons = %v, want %v", got, tt.wantButtons)
+			}
+
+			// Text → keyboard: each feature line ("<button> - <description>") is a button
+			var listed []string
+			for _, line := range strings.Split(text, "\n") {
+				if label, _, ok := strings.Cut(line, " - "); ok && !strings.HasPrefix(line, "Welcome") {
+					listed = append(listed, label)
+				}
+			}
+			if strings.Join(listed, ",") != strings.Join(got, ",") {
+				t.Errorf("welcome text lists %v, keyboard has %v\nText: %s", listed, got, text)
+			}
+
+			if len(got) == 0 && !strings.Contains(text, "/help") {
+				t.Errorf("welcome text without buttons should point to /help: %q", text)
+			}
+		})
+	}
+}
+
+// TestHandleStart_DeepLinkPayloads tests /start deep-link payload handling.
 //
-// Instead, we:
-//   1. Test the message formatting logic (formatStartMessage)
-//   2. Rely on integration tests for full flow
-//   3. Manual testing with real bot
+// Testing strategy:
+//   - recordingSender captures everything HandleStart sends
+//   - getTopOffers is replaced so the "ovh" payload never calls the real OVH API
+//   - First sent message is always the welcome; payload actions follow it
 //
-// This balances test coverage with simplicity.
+// Cases:
+//   - Plain /start: welcome only
+//   - "dice": welcome + dice result
+//   - "ovh" (authorized): welcome + status + OVH results
+//   - "ovh" (unauthorized): welcome + "not authorized" message, no OVH call
+//   - Unknown payload: welcome only
+//   - Invalid characters: welcome only
+func TestHandleStart_DeepLinkPayloads(t *testing.T) {
+	// Fake OVH fetch - counts calls and returns one offer
+	ovhCalls := 0
+	oldGetTopOffers := getTopOffers
+	getTopOffers = func(ctx context.Context, opts ...ovh.Option) ([]ovh.Offer, error) {
+		ovhCalls++
+		return []ovh.Offer{{FQN: "a.lon.1", Price: 9.99, Currency: "EUR", InvoiceName: "KS-1"}}, nil
+	}
+	defer func() { getTopOffers = oldGetTopOffers }()
+
+	tests := []struct {
+		name         string
+		text         string
+		userID       int64
+		wantMessages int    // Total MessageConfig values sent
+		wantLastText string // Substring of the last message ("" = skip check)
+		wantOVHCalls int
+	}{
+		{name: "no payload", text: "/start", userID: 12345, wantMessages: 1, wantLastText: "Welcome"},
+		{name: "dice payload", text: "/start dice", userID: 12345, wantMessages: 2, wantLastText: "You rolled"},
+		{name: "ovh payload authorized", text: "/start ovh", userID: 12345, wantMessages: 3, wantLastText: "Available OVH Servers", wantOVHCalls: 1},
+		{name: "ovh payload unauthorized", text: "/start ovh", userID: 99999, wantMessages: 2, wantLastText: "only available to authorized users"},
+		{name: "unknown payload", text: "/start promo2024", userID: 12345, wantMessages: 1, wantLastText: "Welcome"},
+		{name: "invalid characters", text: "/start ovh;rm -rf", userID: 12345, wantMessages: 1, wantLastText: "Welcome"},
+		{name: "payload too long", text: "/start " + strings.Repeat("a", 65), userID: 12345, wantMessages: 1, wantLastText: "Welcome"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ovhCalls = 0
+			sender := &recordingSender{}
+
+			HandleStart(context.Background(), sender, createTestMessage(tt.text, tt.userID), testConfig())
+
+			messages := sender.messages()
+			if len(messages) != tt.wantMessages {
+				t.Fatalf("HandleStart(context.Background(), %q) sent %d messages, want %d", tt.text, len(messages), tt.wantMessages)
+			}
+			if !strings.Contains(messages[0].Text, "Welcome") {
+				t.Errorf("first message is not the welcome: %q", messages[0].Text)
+			}
+			if last := messages[len(messages)-1].Text; !strings.Contains(last, tt.wantLastText) {
+				t.Errorf("last message = %q, want it to contain %q", last, tt.wantLastText)
+			}
+			if ovhCalls != tt.wantOVHCalls {
+				t.Errorf("OVH fetched %d times, want %d", ovhCalls, tt.wantOVHCalls)
+			}
+		})
+	}
+}
+
+// TestStartPayloadsRegistered verifies every documented deep link has an action.
+func TestStartPayloadsRegistered(t *testing.T) {
+	for _, payload := range []string{"ovh", "dice"} {
+		if _, ok := startPayloads[payload]; !ok {
+			t.Errorf("startPayloads missing %q", payload)
+		}
+		if !startPayloadPattern.MatchString(payload) {
+			t.Errorf("payload %q does not match startPayloadPattern", payload)
+		}
+	}
+}
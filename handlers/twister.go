@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"math/rand"
+	"strings"
 
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -20,48 +23,70 @@ import (
 // Flow:
 //  1. Generate random limb (hand or foot, left or right)
 //  2. Generate random color with matching emoji
-//  3. Send formatted message with move instruction
+//  3. Start a new round of the chat's game: the move is the sender's,
+//     the chat awaits /done or /skip (see twisterscore.go)
+//  4. Send formatted message with move instruction
 //
 // Parameters:
+//   - ctx: Request context (carries the per-update logger)
 //   - bot: Telegram Bot API instance for sending messages
 //   - message: Message from Telegram containing button click
-func HandleTwister(bot *tgbotapi.BotAPI, message *tgbotapi.Message) {
+func HandleTwister(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+	log := logger.FromContext(ctx)
+
 	// Step 1: Generate random Twister move
 	limb, color, emoji := generateTwisterMove()
 
 	// Log the move for debugging/monitoring
-	slog.Info("Twister move generated",
-		"user_id", message.From.ID,
+	log.Info("Twister move generated",
 		"username", message.From.UserName,
 		"limb", limb,
 		"color", color)
 
-	// Step 2: Create result message
-	// Format: "🌀 Twister Move
-	//
-	//          🔴 Right Hand Red"
-	messageText := fmt.Sprintf("🌀 *Twister Move*\n\n%s %s %s", emoji, limb, color)
+	// Step 2: New round for the player who pressed the button
+	player := twisterPlayerName(message.From)
+	round := startTwisterRound(message.Chat.ID, player, limb+" "+color)
 
-	// NewMessage creates a MessageConfig
-	msg := tgbotapi.NewMessage(message.Chat.ID, messageText)
+	// Step 3: Create result message
+	// Format: "🌀 Twister Move (round 3)
+	//
+	//          🔴 Right Hand Red
+	//
+	//          Alice: send /done once you've made it, or /skip."
+	lang := userLanguage(ctx, message.From)
+	messageText := fmt.Sprintf("🌀 %s%s\n\n%s %s\n\n%s",
+		tgfmt.Bold(translate(lang, "twister_move_title")), tgfmt.EscapeMarkdownV2(translate(lang, "twister_round", round)),
+		emoji, tgfmt.EscapeMarkdownV2(twisterMoveText(lang, limb, color)),
+		tgfmt.EscapeMarkdownV2(translate(lang, "twister_move_prompt", player)))
 
-	// Enable Markdown formatting for bold header
-	msg.ParseMode = "Markdown"
+	// replyTo creates a MessageConfig (see bot.Reply)
+	msg := replyTo(message, messageText)
 
-	// Step 3: Send the message
-	if _, err := bot.Send(msg); err != nil {
-		slog.Error("Failed to send Twister move",
+	// Step 4: Send the message
+	// sendFormattedReply enables MarkdownV2 (bold header) with plain text fallback
+	// and quotes the request in group chats
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send Twister move",
 			"error", err,
-			"chat_id", message.Chat.ID,
+			"message_type", messageType(msg),
 			"limb", limb,
 			"color", color)
 		return
 	}
 
-	slog.Info("Twister move sent successfully",
-		"chat_id", message.Chat.ID,
+	log.Info("Twister move sent successfully",
 		"limb", limb,
-		"color", color)
+		"color", color,
+		"round", round)
+}
+
+// twisterMoveText translates a move, e.g. "Right Hand Red" or "Main droite rouge"
+// Limbs and colors are looked up as "twister_right_hand" and "twister_red".
+func twisterMoveText(lang, limb, color string) string {
+	key := func(name string) string {
+		return "twister_" + strings.ToLower(strings.ReplaceAll(name, " ", "_"))
+	}
+	return translate(lang, "twister_move", translate(lang, key(limb)), translate(lang, key(color)))
 }
 
 // generateTwisterMove generates a random Twister game move.
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TwisterRound is the Twister game of one chat
+//
+// Each "🌀 Twister" press starts a new round: the move goes to the player
+// who pressed the button, and the chat then awaits /done (the player made
+// the move, +1 point) or /skip (no point). Pressing the button again before
+// that simply replaces the pending move.
+type TwisterRound struct {
+	Round      int            // Moves dealt since the last /twister_new
+	Scoreboard map[string]int // Player name -> moves completed
+
+	awaiting bool   // A move waits for /done or /skip
+	player   string // Player of the pending move
+	move     string // Pending move, e.g. "Right Hand Red"
+}
+
+// twisterGames holds the Twister game of every chat, for the lifetime
+// of the process (a restart resets all scoreboards)
+var twisterGames = struct {
+	mu    sync.Mutex
+	chats map[int64]*TwisterRound
+}{chats: make(map[int64]*TwisterRound)}
+
+// twisterGame returns the chat's game, creating it on first use
+// twisterGames.mu must be held.
+func twisterGame(chatID int64) *TwisterRound {
+	game, ok := twisterGames.chats[chatID]
+	if !ok {
+		game = &TwisterRound{Scoreboard: make(map[string]int)}
+		twisterGames.chats[chatID] = game
+	}
+	return game
+}
+
+// startTwisterRound records a dealt move; the chat now awaits /done or /skip
+//
+// Returns:
+//   - int: The new round number (1 for the first move)
+func startTwisterRound(chatID int64, player, move string) int {
+	twisterGames.mu.Lock()
+	defer twisterGames.mu.Unlock()
+
+	game := twisterGame(chatID)
+	game.Round++
+	game.awaiting = true
+	game.player = player
+	game.move = move
+	return game.Round
+}
+
+// finishTwisterRound ends the pending round of a chat
+//
+// Parameters:
+//   - chatID: Chat of the game
+//   - completed: true for /done (the player scores), false for /skip
+//
+// Returns:
+//   - player: Player of the round
+//   - score: The player's score after the round
+//   - ok: false if no move was awaiting confirmation
+func finishTwisterRound(chatID int64, completed bool) (player string, score int, ok bool) {
+	twisterGames.mu.Lock()
+	defer twisterGames.mu.Unlock()
+
+	game, exists := twisterGames.chats[chatID]
+	if !exists || !game.awaiting {
+		return "", 0, false
+	}
+
+	game.awaiting = false
+	if completed {
+		game.Scoreboard[game.player]++
+	}
+	return game.player, game.Scoreboard[game.player], true
+}
+
+// resetTwisterGame forgets a chat's round number and scoreboard
+func resetTwisterGame(chatID int64) {
+	twisterGames.mu.Lock()
+	defer twisterGames.mu.Unlock()
+
+	delete(twisterGames.chats, chatID)
+}
+
+// playerScore is one scoreboard line
+type playerScore struct {
+	player string
+	score  int
+}
+
+// twisterScores returns a chat's round number and scoreboard,
+// best score first (ties by name, for a stable order)
+func twisterScores(chatID int64) (int, []playerScore) {
+	twisterGames.mu.Lock()
+	defer twisterGames.mu.Unlock()
+
+	game, ok := twisterGames.chats[chatID]
+	if !ok {
+		return 0, nil
+	}
+
+	scores := make([]playerScore, 0, len(game.Scoreboard))
+	for player, score := range game.Scoreboard {
+		scores = append(scores, playerScore{player: player, score: score})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].score != scores[j].score {
+			return scores[i].score > scores[j].score
+		}
+		return scores[i].player < scores[j].player
+	})
+	return game.Round, scores
+}
+
+// twisterPlayerName is the scoreboard name of a user: first name,
+// else @username, else the user ID
+func twisterPlayerName(user *tgbotapi.User) string {
+	switch {
+	case user == nil:
+		return "Someone"
+	case user.FirstName != "":
+		return user.FirstName
+	case user.UserName != "":
+		return "@" + user.UserName
+	default:
+		return "Player " + strconv.FormatInt(user.ID, 10)
+	}
+}
+
+// HandleTwisterDone handles the /done command: the player of the pending
+// Twister move made it and scores a point.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /done command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleTwisterDone(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	player, score, ok := finishTwisterRound(message.Chat.ID, true)
+
+	lang := userLanguage(ctx, message.From)
+	reply := translate(lang, "twister_no_move")
+	if ok {
+		logger.FromContext(ctx).Info("Twister move completed",
+			"player", player,
+			"score", score)
+		reply = translate(lang, "twister_done", player, score)
+	}
+	sendTwisterReply(ctx, bot, message, reply)
+}
+
+// HandleTwisterSkip handles the /skip command: the pending Twister move
+// is passed without a point.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /skip command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleTwisterSkip(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	player, _, ok := finishTwisterRound(message.Chat.ID, false)
+
+	lang := userLanguage(ctx, message.From)
+	reply := translate(lang, "twister_no_move")
+	if ok {
+		logger.FromContext(ctx).Info("Twister move skipped",
+			"player", player)
+		reply = translate(lang, "twister_skipped", player)
+	}
+	sendTwisterReply(ctx, bot, message, reply)
+}
+
+// HandleTwisterScore handles the /twister_score command: the chat's
+// round number and scoreboard.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /twister_score command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleTwisterScore(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	round, scores := twisterScores(message.Chat.ID)
+
+	msg := replyTo(message, formatTwisterScoreboard(userLanguage(ctx, message.From), round, scores))
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		logger.FromContext(ctx).Error("Failed to send Twister scoreboard",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// HandleTwisterNew handles the /twister_new command: resets the chat's
+// round number and scoreboard.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /twister_new command
+//   - cfg: Application configuration (unused, UpdateHandlerFunc signature)
+func HandleTwisterNew(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	resetTwisterGame(message.Chat.ID)
+	logger.FromContext(ctx).Info("Twister game reset")
+
+	sendTwisterReply(ctx, bot, message, translate(userLanguage(ctx, message.From), "twister_new_game"))
+}
+
+// sendTwisterReply sends a plain-text Twister game reply
+func sendTwisterReply(ctx context.Context, bot BotSender, message *tgbotapi.Message, text string) {
+	msg := replyTo(message, text)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		logger.FromContext(ctx).Error("Failed to send Twister game reply",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// formatTwisterScoreboard builds the MarkdownV2 scoreboard
+//
+// Example:
+//
+//	🏆 Twister scoreboard (round 5)
+//
+//	1. Alice - 3
+//	2. Bob - 1
+func formatTwisterScoreboard(lang string, round int, scores []playerScore) string {
+	if round == 0 {
+		return tgfmt.EscapeMarkdownV2(translate(lang, "twister_no_game"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🏆 " + tgfmt.Bold(translate(lang, "twister_scoreboard")) + tgfmt.EscapeMarkdownV2(translate(lang, "twister_round", round)) + "\n\n")
+	if len(scores) == 0 {
+		sb.WriteString(tgfmt.EscapeMarkdownV2(translate(lang, "twister_no_scores")))
+		return sb.String()
+	}
+	for i, s := range scores {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(tgfmt.EscapeMarkdownV2(fmt.Sprintf("%d. %s - %d", i+1, s.player, s.score)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// withTwisterGames gives a test empty Twister games
+func withTwisterGames(t *testing.T) {
+	t.Helper()
+
+	twisterGames.mu.Lock()
+	old := twisterGames.chats
+	twisterGames.chats = make(map[int64]*TwisterRound)
+	twisterGames.mu.Unlock()
+
+	t.Cleanup(func() {
+		twisterGames.mu.Lock()
+		twisterGames.chats = old
+		twisterGames.mu.Unlock()
+	})
+}
+
+// twisterMessage is a message from a player in a chat
+func twisterMessage(text string, chatID int64, firstName string) *tgbotapi.Message {
+	msg := createTestMessage(text, 12345)
+	msg.Chat.ID = chatID
+	msg.From.FirstName = firstName
+	return msg
+}
+
+// TestTwisterGame tests a game through the handlers
+//
+// Steps:
+//   - /done and /skip without a pending move: hint, no score
+//   - Move for Alice (round 1), /done: Alice 1
+//   - Move for Bob (round 2), /skip: Bob not on the scoreboard
+//   - Move for Bob (round 3), /done; a second /done: nothing pending
+//   - /twister_score: round 3, Alice and Bob with 1 point each
+//   - Another chat has its own game
+//   - /twister_new: back to no game
+func TestTwisterGame(t *testing.T) {
+	withTwisterGames(t)
+	ctx := context.Background()
+	cfg := testConfig()
+
+	// send runs one handler and returns the text it sent
+	send := func(handler func(context.Context, BotSender, *tgbotapi.Message), text string, chatID int64, player string) string {
+		t.Helper()
+		sender := &recordingSender{}
+		handler(ctx, sender, twisterMessage(text, chatID, player))
+		messages := sender.messages()
+		if len(messages) != 1 {
+			t.Fatalf("%s sent %d messages, want 1", text, len(messages))
+		}
+		return messages[0].Text
+	}
+	withCfg := func(handler UpdateHandlerFunc) func(context.Context, BotSender, *tgbotapi.Message) {
+		return func(ctx context.Context, bot BotSender, message *tgbotapi.Message) {
+			handler(ctx, bot, message, cfg)
+		}
+	}
+	done, skip := withCfg(HandleTwisterDone), withCfg(HandleTwisterSkip)
+	score, newGame := withCfg(HandleTwisterScore), withCfg(HandleTwisterNew)
+
+	steps := []struct {
+		name    string
+		handler func(context.Context, BotSender, *tgbotapi.Message)
+		text    string
+		chatID  int64
+		player  string
+		want    string
+	}{
+		{name: "done without move", handler: done, text: "/done", chatID: 1, player: "Alice", want: "no Twister move to confirm"},
+		{name: "skip without move", handler: skip, text: "/skip", chatID: 1, player: "Alice", want: "no Twister move to confirm"},
+		{name: "move for Alice", handler: HandleTwister, text: "🌀 Twister", chatID: 1, player: "Alice", want: `\(round 1\)`},
+		{name: "Alice done", handler: done, text: "/done", chatID: 1, player: "Alice", want: "Nice one, Alice! Moves completed: 1."},
+		{name: "move for Bob", handler: HandleTwister, text: "🌀 Twister", chatID: 1, player: "Bob", want: `Bob: send /done`},
+		{name: "Bob skips", handler: skip, text: "/skip", chatID: 1, player: "Bob", want: "Bob skipped this move"},
+		{name: "move for Bob again", handler: HandleTwister, text: "🌀 Twister", chatID: 1, player: "Bob", want: `\(round 3\)`},
+		{name: "Bob done", handler: done, text: "/done", chatID: 1, player: "Bob", want: "Bob! Moves completed: 1."},
+		{name: "done twice", handler: done, text: "/done", chatID: 1, player: "Bob", want: "no Twister move to confirm"},
+		{name: "scoreboard", handler: score, text: "/twister_score", chatID: 1, player: "Bob", want: "\\(round 3\\)\n\n1\\. Alice \\- 1\n2\\. Bob \\- 1"},
+		{name: "other chat", handler: score, text: "/twister_score", chatID: 2, player: "Carol", want: "No Twister game yet"},
+		{name: "new game", handler: newGame, text: "/twister_new", chatID: 1, player: "Alice", want: "scoreboard cleared"},
+		{name: "scoreboard after reset", handler: score, text: "/twister_score", chatID: 1, player: "Alice", want: "No Twister game yet"},
+	}
+
+	for _, step := range steps {
+		if got := send(step.handler, step.text, step.chatID, step.player); !strings.Contains(got, step.want) {
+			t.Errorf("%s: reply = %q, want it to contain %q", step.name, got, step.want)
+		}
+	}
+}
+
+// TestFormatTwisterScoreboard tests the scoreboard text
+//
+// Cases:
+//   - No game: hint to start one
+//   - Rounds but no completed move: hint to send /done
+//   - Players in order, names with MarkdownV2 characters escaped
+func TestFormatTwisterScoreboard(t *testing.T) {
+	tests := []struct {
+		name   string
+		round  int
+		scores []playerScore
+		want   []string
+	}{
+		{name: "no game", round: 0, want: []string{"No Twister game yet"}},
+		{name: "no points", round: 2, want: []string{"*Twister scoreboard*", `\(round 2\)`, "No completed moves yet"}},
+		{
+			name:   "players",
+			round:  7,
+			scores: []playerScore{{player: "Ann_Marie", score: 4}, {player: "@bob", score: 2}},
+			want:   []string{"1\\. Ann\\_Marie \\- 4", "2\\. @bob \\- 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatTwisterScoreboard("en", tt.round, tt.scores)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("formatTwisterScoreboard() = %q, want it to contain %q", got, want)
+				}
+			}
+			if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+				t.Errorf("formatTwisterScoreboard() is not valid MarkdownV2: %v", err)
+			}
+		})
+	}
+}
+
+// TestTwisterScores_Order tests scoreboard order and concurrent scoring
+//
+// Checks:
+//   - Best score first, ties by name
+//   - Concurrent rounds in one chat are safe (go test -race) and each
+//     /done after a move counts once
+func TestTwisterScores_Order(t *testing.T) {
+	withTwisterGames(t)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			startTwisterRound(5, "Zed", "Left Hand Red")
+			finishTwisterRound(5, true)
+		}()
+	}
+	wg.Wait()
+
+	startTwisterRound(5, "Amy", "Right Foot Blue")
+	finishTwisterRound(5, true)
+	startTwisterRound(5, "Bea", "Right Foot Blue")
+	finishTwisterRound(5, true)
+
+	round, scores := twisterScores(5)
+	if round != 22 {
+		t.Errorf("round = %d, want 22", round)
+	}
+	if len(scores) != 3 || scores[0].player != "Zed" || scores[1].player != "Amy" || scores[2].player != "Bea" {
+		t.Fatalf("scores = %+v, want Zed, Amy, Bea", scores)
+	}
+	// Goroutines may interleave start/finish pairs, so Zed has at most 20
+	if scores[0].score < 1 || scores[0].score > 20 {
+		t.Errorf("Zed score = %d, want 1-20", scores[0].score)
+	}
+}
+
+// TestTwisterPlayerName tests the scoreboard name fallbacks
+func TestTwisterPlayerName(t *testing.T) {
+	tests := []struct {
+		name string
+		user *tgbotapi.User
+		want string
+	}{
+		{name: "first name", user: &tgbotapi.User{ID: 1, FirstName: "Alice", UserName: "alice"}, want: "Alice"},
+		{name: "username", user: &tgbotapi.User{ID: 1, UserName: "alice"}, want: "@alice"},
+		{name: "ID only", user: &tgbotapi.User{ID: 42}, want: "Player 42"},
+		{name: "no user", user: nil, want: "Someone"},
+	}
+
+	for _, tt := range tests {
+		if got := twisterPlayerName(tt.user); got != tt.want {
+			t.Errorf("%s: twisterPlayerName() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/storage"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Usage features besides the config.Feature* ones
+const (
+	usageHelp    = "help"    // /help
+	usageUnknown = "unknown" // Unknown commands in private chats
+)
+
+// usageColumns are the features /usage shows, in column order, with their header
+var usageColumns = []struct {
+	feature string
+	label   string
+}{
+	{config.FeatureDice, "dice"},
+	{config.FeatureDoubleDice, "2dice"},
+	{config.FeatureTwister, "twist"},
+	{config.FeatureOVH, "ovh"},
+	{usageHelp, "help"},
+	{usageUnknown, "unkn"},
+}
+
+// Limits of the /usage [days] argument
+const (
+	usageDefaultDays = 7
+	usageMaxDays     = 30
+)
+
+// usageFlushTimeout bounds one flush of the usage counters
+const usageFlushTimeout = 10 * time.Second
+
+// usageKey identifies one pending counter
+type usageKey struct {
+	day     string // UTC date, time.DateOnly
+	feature string
+}
+
+// usageCounter buffers feature counts in memory until they are flushed to storage
+//
+// Why buffer?
+//   - Handlers only take a mutex and increment a map entry, never wait for storage
+//   - One storage write per (day, feature) and flush instead of one per update
+//
+// Safe for concurrent use: updates are handled in parallel.
+type usageCounter struct {
+	mu      sync.Mutex
+	pending map[usageKey]int64
+	now     func() time.Time // Replaced in tests
+}
+
+// newUsageCounter creates an empty counter
+func newUsageCounter() *usageCounter {
+	return &usageCounter{pending: make(map[usageKey]int64), now: time.Now}
+}
+
+// usage is the counter behind countUsage and FlushUsage
+var usage = newUsageCounter()
+
+// usageStore keeps the flushed counters (set by main.go via SetUsageStore)
+// nil: counts stay in memory and /usage explains that analytics are off
+var usageStore *storage.UsageStore
+
+// SetUsageStore enables usage analytics (/usage)
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - store: Usually storage.NewUsageStore on the application's storage
+func SetUsageStore(store *storage.UsageStore) {
+	usageStore = store
+}
+
+// countUsage records one use of a feature (non-blocking, see usageCounter)
+func countUsage(feature string) {
+	if usageStore == nil || feature == "" {
+		return
+	}
+	usage.inc(feature)
+}
+
+// inc adds one use of feature today (UTC)
+func (c *usageCounter) inc(feature string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	day := c.now().UTC().Format(time.DateOnly)
+	c.pending[usageKey{day: day, feature: feature}]++
+}
+
+// flush writes the pending counts to store
+//
+// The pending map is swapped out first, so handlers can keep counting while
+// the writes run. Counts that fail to write are put back for the next flush.
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - store: Where the counts go
+//
+// Returns:
+//   - error: The first write error (the other counters are still attempted)
+func (c *usageCounter) flush(ctx context.Context, store *storage.UsageStore) error {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[usageKey]int64)
+	c.mu.Unlock()
+
+	var firstErr error
+	for key, n := range pending {
+		day, err := time.Parse(time.DateOnly, key.day)
+		if err == nil {
+			err = store.AddUsage(ctx, day, key.feature, n)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			c.mu.Lock()
+			c.pending[key] += n
+			c.mu.Unlock()
+		}
+	}
+	return firstErr
+}
+
+// FlushUsage writes the buffered usage counts to storage
+// Called by RunUsageFlusher and once more by main.go during graceful
+// shutdown, after the last update has been handled.
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//
+// Returns:
+//   - error: If a write failed (the counts are kept for the next flush)
+func FlushUsage(ctx context.Context) error {
+	if usageStore == nil {
+		return nil
+	}
+	return usage.flush(ctx, usageStore)
+}
+
+// RunUsageFlusher flushes the usage counters every interval until ctx is done,
+// then one last time
+//
+// Parameters:
+//   - ctx: Cancelled on shutdown; the flusher returns when it's done
+//   - interval: Time between flushes (counts of a crash are lost for at most this long)
+func RunUsageFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	runUsageFlusher(ctx, ticker.C)
+}
+
+// runUsageFlusher is RunUsageFlusher with the ticks passed in (tests send their own)
+func runUsageFlusher(ctx context.Context, ticks <-chan time.Time) {
+	flush := func(ctx context.Context) {
+		ctx, cancel := context.WithTimeout(ctx, usageFlushTimeout)
+		defer cancel()
+
+		if err := FlushUsage(ctx); err != nil {
+			logger.FromContext(ctx).Warn("Failed to flush usage counters", "error", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Shutdown: ctx is already cancelled, but the final flush still needs one
+			flush(context.WithoutCancel(ctx))
+			return
+		case <-ticks:
+			flush(ctx)
+		}
+	}
+}
+
+// HandleUsage handles the /usage [days] command (private, for admins).
+// Shows a table of feature uses per day for the last N days (default 7, max 30).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /usage command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleUsage(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	var text string
+	lang := userLanguage(ctx, message.From)
+	days, err := parseUsageDays(message.CommandArguments())
+	switch {
+	case err != nil:
+		text = tgfmt.EscapeMarkdownV2(translate(lang, "usage_usage", usageMaxDays, usageDefaultDays))
+	case usageStore == nil:
+		text = tgfmt.EscapeMarkdownV2(translate(lang, "usage_disabled"))
+	default:
+		// Include what hasn't been flushed yet, so the table is up to date
+		if err := FlushUsage(ctx); err != nil {
+			log.Warn("Failed to flush usage counters before report", "error", err)
+		}
+		rows, err := loadUsageRows(ctx, usageStore, time.Now(), days)
+		if err != nil {
+			log.Error("Failed to load usage counters", "error", err)
+			text = tgfmt.EscapeMarkdownV2(translate(lang, "usage_failed"))
+		} else {
+			text = formatUsageReport(lang, rows)
+		}
+	}
+
+	msg := replyTo(message, text)
+	if _, err := sendFormattedReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /usage report",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// parseUsageDays parses the /usage argument: empty for the default, or 1-usageMaxDays
+func parseUsageDays(arg string) (int, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return usageDefaultDays, nil
+	}
+	days, err := strconv.Atoi(arg)
+	if err != nil || days < 1 || days > usageMaxDays {
+		return 0, fmt.Errorf("invalid number of days: %q", arg)
+	}
+	return days, nil
+}
+
+// usageRow is one day of the /usage table
+type usageRow struct {
+	day    time.Time
+	counts map[string]int64
+}
+
+// loadUsageRows loads the counters of the last days, oldest first
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - store: Usage counters
+//   - now: Today is the last row (UTC)
+//   - days: Number of rows
+//
+// Returns:
+//   - []usageRow: One row per day, also for days without usage
+//   - error: If loading a day failed
+func loadUsageRows(ctx context.Context, store *storage.UsageStore, now time.Time, days int) ([]usageRow, error) {
+	today := now.UTC()
+	rows := make([]usageRow, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		counts, err := store.LoadDayUsage(ctx, day)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, usageRow{day: day, counts: counts})
+	}
+	return rows, nil
+}
+
+// usageTotals sums the counts of all rows per feature
+func usageTotals(rows []usageRow) map[string]int64 {
+	totals := make(map[string]int64)
+	for _, row := range rows {
+		for feature, n := range row.counts {
+			totals[feature] += n
+		}
+	}
+	return totals
+}
+
+// formatUsageReport builds the MarkdownV2 /usage message
+//
+// Format (the table is a monospace block, one row per day, totals last):
+//
+//	📈 Usage per day (UTC)
+//
+//	date   dice 2dice twist   ovh  help  unkn
+//	03-01     5     0     1     2     1     0
+//	03-02     3     1     0     0     0     1
+//	total     8     1     1     2     1     1
+//
+// The title is in lang; the table keeps its short English column labels
+// so the columns stay aligned in every language.
+//
+// Parameters:
+//   - lang: Language of the title (see userLanguage)
+//   - rows: Days to show, oldest first
+//
+// Returns:
+//   - string: Formatted message with MarkdownV2 escaping
+func formatUsageReport(lang string, rows []usageRow) string {
+	var table strings.Builder
+	writeRow := func(first string, value func(feature string) string) {
+		fmt.Fprintf(&table, "%-5s", first)
+		for _, col := range usageColumns {
+			fmt.Fprintf(&table, " %5s", value(col.feature))
+		}
+		table.WriteString("\n")
+	}
+
+	labels := make(map[string]string, len(usageColumns))
+	for _, col := range usageColumns {
+		labels[col.feature] = col.label
+	}
+	writeRow("date", func(feature string) string { return labels[feature] })
+	for _, row := range rows {
+		writeRow(row.day.Format("01-02"), func(feature string) string {
+			return strconv.FormatInt(row.counts[feature], 10)
+		})
+	}
+	totals := usageTotals(rows)
+	writeRow("total", func(feature string) string {
+		return strconv.FormatInt(totals[feature], 10)
+	})
+
+	// Inside ``` only ` and \ would need escaping; the table has neither
+	return "📈 " + tgfmt.Bold(translate(lang, "usage_title")) + "\n\n" +
+		"```\n" + table.String() + "```"
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+	"github.com/Alrem/run-tbot/tgfmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// withUsageStore installs a fresh usage store and counter for one test
+func withUsageStore(t *testing.T) *storage.UsageStore {
+	t.Helper()
+	oldStore, oldUsage := usageStore, usage
+	t.Cleanup(func() { usageStore, usage = oldStore, oldUsage })
+
+	usageStore = storage.NewUsageStore(storage.NewMemoryStore())
+	usage = newUsageCounter()
+	return usageStore
+}
+
+// TestUsageFlusher tests buffering, periodic flushes and the final flush on shutdown
+//
+// Steps (fake clock: the test sends the ticks and sets the counter's time):
+//   - Counts before a tick stay in memory
+//   - A tick writes them, per UTC day
+//   - Counts after the last tick are written when ctx is cancelled
+func TestUsageFlusher(t *testing.T) {
+	store := withUsageStore(t)
+	day1 := time.Date(2026, 3, 1, 23, 59, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Minute)
+	now := day1
+	usage.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan time.Time)
+	done := make(chan struct{})
+	go func() {
+		runUsageFlusher(ctx, ticks)
+		close(done)
+	}()
+
+	countUsage("dice")
+	countUsage("dice")
+	if got, _ := store.LoadDayUsage(context.Background(), day1); len(got) != 0 {
+		t.Errorf("stored before the first tick: %v, want nothing", got)
+	}
+
+	// Unbuffered channel: the send returns once the flusher took the tick;
+	// the second send returns only after the first flush has finished
+	ticks <- now
+	now = day2
+	countUsage("ovh")
+	ticks <- now
+
+	if got, _ := store.LoadDayUsage(context.Background(), day1); !reflect.DeepEqual(got, map[string]int64{"dice": 2}) {
+		t.Errorf("day 1 after ticks = %v, want dice: 2", got)
+	}
+
+	countUsage("ovh")
+	countUsage("help")
+	cancel()
+	<-done
+
+	want := map[string]int64{"ovh": 2, "help": 1}
+	if got, _ := store.LoadDayUsage(context.Background(), day2); !reflect.DeepEqual(got, want) {
+		t.Errorf("day 2 after shutdown = %v, want %v", got, want)
+	}
+}
+
+// failingStore fails every Set (a storage outage)
+type failingStore struct {
+	storage.Store
+	fail bool
+}
+
+func (s *failingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if s.fail {
+		return context.DeadlineExceeded
+	}
+	return s.Store.Set(ctx, key, value, ttl)
+}
+
+// TestUsageCounter_FlushRetry tests that counts survive a failed flush
+func TestUsageCounter_FlushRetry(t *testing.T) {
+	backend := &failingStore{Store: storage.NewMemoryStore(), fail: true}
+	store := storage.NewUsageStore(backend)
+	counter := newUsageCounter()
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	counter.now = func() time.Time { return day }
+	ctx := context.Background()
+
+	counter.inc("dice")
+	if err := counter.flush(ctx, store); err == nil {
+		t.Fatalf("flush() during outage error = nil, want error")
+	}
+
+	backend.fail = false
+	counter.inc("dice")
+	if err := counter.flush(ctx, store); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	if got, _ := store.LoadDayUsage(ctx, day); got["dice"] != 2 {
+		t.Errorf("dice = %d, want 2 (the failed flush's count is kept)", got["dice"])
+	}
+}
+
+// TestLoadUsageRows tests the aggregation of the last N days
+//
+// Cases:
+//   - Rows run oldest to today, including days without usage
+//   - Totals sum every day per feature
+func TestLoadUsageRows(t *testing.T) {
+	store := storage.NewUsageStore(storage.NewMemoryStore())
+	ctx := context.Background()
+	today := time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)
+
+	_ = store.AddUsage(ctx, today, "dice", 4)
+	_ = store.AddUsage(ctx, today.AddDate(0, 0, -2), "dice", 1)
+	_ = store.AddUsage(ctx, today.AddDate(0, 0, -2), "ovh", 2)
+	_ = store.AddUsage(ctx, today.AddDate(0, 0, -5), "dice", 100) // Outside the window
+
+	rows, err := loadUsageRows(ctx, store, today, 3)
+	if err != nil {
+		t.Fatalf("loadUsageRows() error = %v", err)
+	}
+
+	var days []string
+	for _, row := range rows {
+		days = append(days, row.day.Format(time.DateOnly))
+	}
+	if want := []string{"2026-03-01", "2026-03-02", "2026-03-03"}; !reflect.DeepEqual(days, want) {
+		t.Errorf("days = %v, want %v", days, want)
+	}
+	if len(rows[1].counts) != 0 {
+		t.Errorf("unused day counts = %v, want empty", rows[1].counts)
+	}
+
+	if got, want := usageTotals(rows), map[string]int64{"dice": 5, "ovh": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("usageTotals() = %v, want %v", got, want)
+	}
+}
+
+// TestFormatUsageReport tests the /usage table
+func TestFormatUsageReport(t *testing.T) {
+	rows := []usageRow{
+		{day: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), counts: map[string]int64{"dice": 5, "twister": 1, "ovh": 2, "help": 1}},
+		{day: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), counts: map[string]int64{"dice": 3, "double_dice": 1, "unknown": 1}},
+	}
+
+	want := "📈 " + tgfmt.Bold("Usage per day (UTC)") + "\n\n" +
+		"```\n" +
+		"date   dice 2dice twist   ovh  help  unkn\n" +
+		"03-01     5     0     1     2     1     0\n" +
+		"03-02     3     1     0     0     0     1\n" +
+		"total     8     1     1     2     1     1\n" +
+		"```"
+	got := formatUsageReport("en", rows)
+	if got != want {
+		t.Errorf("formatUsageReport() =\n%s\nwant\n%s", got, want)
+	}
+	if err := tgfmt.ValidateMarkdownV2(got); err != nil {
+		t.Errorf("formatUsageReport() is invalid MarkdownV2: %v", err)
+	}
+}
+
+// TestParseUsageDays tests the /usage argument
+func TestParseUsageDays(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    int
+		wantErr bool
+	}{
+		{arg: "", want: usageDefaultDays},
+		{arg: " 14 ", want: 14},
+		{arg: "30", want: 30},
+		{arg: "0", wantErr: true},
+		{arg: "31", wantErr: true},
+		{arg: "week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseUsageDays(tt.arg)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseUsageDays(%q) = %d, %v; want %d, error %v", tt.arg, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestRouteMessage_CountsUsage tests which routed messages are counted
+func TestRouteMessage_CountsUsage(t *testing.T) {
+	withUsageStore(t)
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	usage.now = func() time.Time { return day }
+
+	cfg := testConfig()
+	for i, text := range []string{"🎲 Dice", "/help", "/nosuchcommand", "/start", "hello"} {
+		RouteUpdate(context.Background(), &recordingSender{}, tgbotapi.Update{UpdateID: i, Message: createTestMessage(text, 12345)}, cfg)
+	}
+
+	usage.mu.Lock()
+	defer usage.mu.Unlock()
+	want := map[usageKey]int64{
+		{day: "2026-03-01", feature: "dice"}:    1,
+		{day: "2026-03-01", feature: "help"}:    1,
+		{day: "2026-03-01", feature: "unknown"}: 1,
+	}
+	if !reflect.DeepEqual(usage.pending, want) {
+		t.Errorf("pending counts = %v, want %v", usage.pending, want)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// userTouchInterval is how often a user's "last seen" record is written at most
+// A minute is precise enough for "recently active" and keeps a busy user
+// from costing a storage write per message.
+const userTouchInterval = time.Minute
+
+// userTouchTimeout bounds one background registry write
+const userTouchTimeout = 5 * time.Second
+
+// usersReportLimit is how many users /users lists
+const usersReportLimit = 10
+
+// userRegistry is the "last seen" registry (set by main.go via SetUserStore)
+// nil: users are not tracked and /users explains that
+var userRegistry *storage.UserStore
+
+// userTouchGuard throttles registry writes to one per user per userTouchInterval
+// The text part of the floodGuard key is always "": only the user counts.
+var userTouchGuard = newFloodGuard(userTouchInterval)
+
+// runAsync starts background work (replaced in tests to run synchronously)
+var runAsync = func(f func()) { go f() }
+
+// SetUserStore enables the "last seen" registry behind /users
+// Call once at startup, before the HTTP server starts (read without locking).
+//
+// Parameters:
+//   - users: Usually storage.NewUserStore on the application's storage
+func SetUserStore(users *storage.UserStore) {
+	userRegistry = users
+}
+
+// recordUser notes that the message's author was active
+//
+// Cheap on the request path:
+//   - At most one write per user per userTouchInterval (userTouchGuard)
+//   - The write runs in the background, so a slow backend never delays the answer
+//
+// Privacy: the record holds the user's IDs, names and timestamps only,
+// never the message text (see storage.UserRecord).
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - message: Incoming message
+func recordUser(ctx context.Context, message *tgbotapi.Message) {
+	users := userRegistry
+	if users == nil || message.From == nil || message.From.IsBot {
+		return
+	}
+	if !userTouchGuard.allow(message.From.ID, "") {
+		return
+	}
+
+	rec := storage.UserRecord{
+		UserID:    message.From.ID,
+		Username:  message.From.UserName,
+		FirstName: message.From.FirstName,
+		ChatID:    message.Chat.ID,
+		LastSeen:  userTouchGuard.now().UTC(),
+	}
+
+	// The update's context ends with the webhook request; keep its values
+	// (logger) but not its cancellation
+	bgCtx := context.WithoutCancel(ctx)
+	runAsync(func() {
+		ctx, cancel := context.WithTimeout(bgCtx, userTouchTimeout)
+		defer cancel()
+
+		if err := users.TouchUser(ctx, rec); err != nil {
+			logger.FromContext(ctx).Warn("Failed to record user activity",
+				"error", err,
+				"user_id", rec.UserID)
+		}
+	})
+}
+
+// HandleUsers handles the /users command (private, for admins).
+// Shows how many users the bot has seen and the most recently active ones.
+//
+// Parameters:
+//   - ctx: Request context (carries the per-update logger)
+//   - bot: Bot sender for sending messages
+//   - message: Message from Telegram containing the /users command
+//   - cfg: Application configuration (needed for authorization check)
+func HandleUsers(ctx context.Context, bot BotSender, message *tgbotapi.Message, cfg *config.Config) {
+	log := logger.FromContext(ctx)
+
+	if !requireAuthorized(ctx, bot, message, cfg) {
+		return
+	}
+
+	lang := userLanguage(ctx, message.From)
+	text := translate(lang, "users_disabled")
+	if userRegistry != nil {
+		users, err := userRegistry.ListUsers(ctx)
+		if err != nil {
+			log.Error("Failed to list users", "error", err)
+			text = translate(lang, "users_failed")
+		} else {
+			text = formatUsersReport(lang, users, time.Now())
+		}
+	}
+
+	msg := replyTo(message, text)
+	if _, err := sendReply(ctx, bot, message, msg); err != nil {
+		log.Error("Failed to send /users report",
+			"error", err,
+			"message_type", messageType(msg))
+	}
+}
+
+// formatUsersReport builds the plain-text /users message
+//
+// Format:
+//
+//	👥 Users: 42
+//
+//	Most recently active:
+//	1. @alice (Alice) - 5m ago
+//	2. Bob (id 123) - 2h ago
+//
+// Parameters:
+//   - lang: Language of the labels (see userLanguage)
+//   - users: All known users, in any order
+//   - now: Reference time for "ago"
+//
+// Returns:
+//   - string: Total count and up to usersReportLimit users, most recent first
+func formatUsersReport(lang string, users []storage.UserRecord, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(translate(lang, "users_count", len(users)))
+	if len(users) == 0 {
+		return b.String()
+	}
+
+	recent := slices.Clone(users)
+	slices.SortFunc(recent, func(a, b storage.UserRecord) int {
+		return b.LastSeen.Compare(a.LastSeen)
+	})
+	if len(recent) > usersReportLimit {
+		recent = recent[:usersReportLimit]
+	}
+
+	b.WriteString("\n\n" + translate(lang, "users_recent"))
+	for i, u := range recent {
+		fmt.Fprintf(&b, "\n%d. %s - %s", i+1, formatUserName(u), formatAgo(lang, now.Sub(u.LastSeen)))
+	}
+	return b.String()
+}
+
+// formatUserName names a user: "@alice (Alice)", "Bob (id 123)" or "id 123"
+func formatUserName(u storage.UserRecord) string {
+	switch {
+	case u.Username != "" && u.FirstName != "":
+		return "@" + u.Username + " (" + u.FirstName + ")"
+	case u.Username != "":
+		return "@" + u.Username
+	case u.FirstName != "":
+		return fmt.Sprintf("%s (id %d)", u.FirstName, u.UserID)
+	default:
+		return fmt.Sprintf("id %d", u.UserID)
+	}
+}
+
+// formatAgo renders a duration coarsely in lang: "just now", "5m ago", "3h ago", "2d ago"
+func formatAgo(lang string, d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return translate(lang, "ago_now")
+	case d < time.Hour:
+		return translate(lang, "ago_minutes", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return translate(lang, "ago_hours", int(d/time.Hour))
+	default:
+		return translate(lang, "ago_days", int(d/(24*time.Hour)))
+	}
+}
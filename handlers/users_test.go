@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+)
+
+// TestRecordUser_Throttling tests that activity is written at most once per user per minute
+//
+// Steps:
+//   - Three messages from user 1 within a minute: one write
+//   - A message from user 2 meanwhile: its own write
+//   - User 1 again after a minute: second write, LastSeen updated, StartedAt kept
+func TestRecordUser_Throttling(t *testing.T) {
+	oldRegistry, oldGuard, oldRunAsync := userRegistry, userTouchGuard, runAsync
+	defer func() { userRegistry, userTouchGuard, runAsync = oldRegistry, oldGuard, oldRunAsync }()
+
+	backend := &countingStore{Store: storage.NewMemoryStore()}
+	userRegistry = storage.NewUserStore(backend)
+	runAsync = func(f func()) { f() }
+
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	now := start
+	userTouchGuard = newFloodGuard(userTouchInterval)
+	userTouchGuard.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for range 3 {
+		recordUser(ctx, createTestMessage("🎲 Dice", 1))
+		now = now.Add(10 * time.Second)
+	}
+	if backend.sets != 1 {
+		t.Fatalf("writes after 3 messages in a minute = %d, want 1", backend.sets)
+	}
+
+	recordUser(ctx, createTestMessage("/start", 2))
+	if backend.sets != 2 {
+		t.Fatalf("writes after another user's message = %d, want 2", backend.sets)
+	}
+
+	now = start.Add(userTouchInterval)
+	recordUser(ctx, createTestMessage("🎲 Dice", 1))
+	if backend.sets != 3 {
+		t.Fatalf("writes after a minute = %d, want 3", backend.sets)
+	}
+
+	users, _ := userRegistry.ListUsers(ctx)
+	if len(users) != 2 {
+		t.Fatalf("users = %d, want 2", len(users))
+	}
+	if u := users[0]; !u.LastSeen.Equal(now) || !u.StartedAt.Equal(start) || u.Username != "testuser" {
+		t.Errorf("user 1 = %+v, want last seen %v, started %v", u, now, start)
+	}
+}
+
+// countingStore counts Set calls on a Store
+type countingStore struct {
+	storage.Store
+	sets int
+}
+
+func (s *countingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.sets++
+	return s.Store.Set(ctx, key, value, ttl)
+}
+
+// TestFormatUsersReport tests the /users message
+//
+// Cases:
+//   - No users: count only
+//   - Most recent first, names with and without username
+//   - More than usersReportLimit users: total counts all, list is capped
+func TestFormatUsersReport(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if got, want := formatUsersReport("en", nil, now), "👥 Users: 0"; got != want {
+		t.Errorf("formatUsersReport(nil) = %q, want %q", got, want)
+	}
+
+	users := []storage.UserRecord{
+		{UserID: 1, Username: "alice", FirstName: "Alice", LastSeen: now.Add(-3 * time.Hour)},
+		{UserID: 2, FirstName: "Bob", LastSeen: now.Add(-5 * time.Minute)},
+		{UserID: 3, LastSeen: now.Add(-50 * time.Hour)},
+		{UserID: 4, Username: "dave", LastSeen: now.Add(-10 * time.Second)},
+	}
+	want := "👥 Users: 4\n\nMost recently active:\n" +
+		"1. @dave - just now\n" +
+		"2. Bob (id 2) - 5m ago\n" +
+		"3. @alice (Alice) - 3h ago\n" +
+		"4. id 3 - 2d ago"
+	if got := formatUsersReport("en", users, now); got != want {
+		t.Errorf("formatUsersReport() =\n%s\nwant\n%s", got, want)
+	}
+	if users[0].UserID != 1 {
+		t.Errorf("formatUsersReport() reordered its input")
+	}
+
+	many := make([]storage.UserRecord, 25)
+	for i := range many {
+		many[i] = storage.UserRecord{UserID: int64(i), LastSeen: now.Add(-time.Duration(i) * time.Hour)}
+	}
+	got := formatUsersReport("en", many, now)
+	if !strings.HasPrefix(got, "👥 Users: 25") {
+		t.Errorf("total line = %q, want 25 users", strings.SplitN(got, "\n", 2)[0])
+	}
+	if lines := strings.Count(got, " ago") + strings.Count(got, "just now"); lines != usersReportLimit {
+		t.Errorf("listed %d users, want %d", lines, usersReportLimit)
+	}
+}
+
+// TestHandleUsers tests /users authorization and the reply without a registry
+func TestHandleUsers(t *testing.T) {
+	oldRegistry := userRegistry
+	defer func() { userRegistry = oldRegistry }()
+	userRegistry = nil
+
+	tests := []struct {
+		name     string
+		userID   int64
+		wantText string
+	}{
+		{name: "unauthorized", userID: 999, wantText: "only available to authorized users"},
+		{name: "no registry", userID: 12345, wantText: "User tracking is not enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			HandleUsers(context.Background(), sender, createTestMessage("/users", tt.userID), testConfig())
+
+			msgs := sender.messages()
+			if len(msgs) != 1 || !strings.Contains(msgs[0].Text, tt.wantText) {
+				t.Fatalf("sent %+v, want one message containing %q", msgs, tt.wantText)
+			}
+		})
+	}
+}
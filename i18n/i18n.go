@@ -0,0 +1,196 @@
+// Package i18n translates the bot's replies.
+//
+// Messages live in embedded YAML files, one per language (locales/en.yaml,
+// locales/fr.yaml, ...), as flat "key: value" pairs:
+//
+//	dice_result: "%s You rolled a %d"
+//
+// Values are fmt format strings; Translator.T fills them in.
+//
+// Why embedded?
+//   - The binary is self-contained: no locale directory to ship to Cloud Run
+//   - A broken file fails the tests, not a user's request
+//
+// The files are read with gopkg.in/yaml.v3, so any YAML string syntax works
+// (quoted, plain or block scalars); nested maps and lists are rejected.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLanguage is the fallback for missing languages and keys
+// Every key must exist in its file.
+const DefaultLanguage = "en"
+
+// locales holds the translation files, one per language code
+//
+//go:embed locales/*.yaml
+var locales embed.FS
+
+// Translator looks up translated messages
+// Read-only after creation, so safe for concurrent use.
+type Translator struct {
+	messages map[string]map[string]string // Language -> key -> format string
+}
+
+// New loads every "<language>.yaml" file of a directory
+//
+// Parameters:
+//   - fsys: File system with the files
+//   - dir: Directory inside fsys (e.g., "locales")
+//
+// Returns:
+//   - *Translator: Translator for every language found
+//   - error: A file that doesn't parse, or no DefaultLanguage file
+func New(fsys fs.FS, dir string) (*Translator, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Translator{messages: make(map[string]map[string]string)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		messages, err := parseMessages(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		t.messages[strings.TrimSuffix(path.Base(file), ".yaml")] = messages
+	}
+
+	if _, ok := t.messages[DefaultLanguage]; !ok {
+		return nil, fmt.Errorf("no %s.yaml in %s", DefaultLanguage, dir)
+	}
+	return t, nil
+}
+
+// defaultTranslator is created on first use from the embedded files
+var defaultTranslator = sync.OnceValue(func() *Translator {
+	t, err := New(locales, "locales")
+	if err != nil {
+		// The files are compiled in: a parse error is a bug (caught by the tests)
+		panic("i18n: embedded locales: " + err.Error())
+	}
+	return t
+})
+
+// Default returns the translator of the embedded locales (one per process)
+func Default() *Translator {
+	return defaultTranslator()
+}
+
+// T returns the message of key in a language, formatted with args
+//
+// Fallbacks:
+//   - Unknown language (or "fr-CA" without fr-CA.yaml): the base language ("fr"), else English
+//   - Key missing in the language: the English message
+//   - Key missing everywhere: the key itself, so the gap is visible but harmless
+//
+// Parameters:
+//   - lang: Language code (e.g., "fr"; case-insensitive, "" = English)
+//   - key: Message key (e.g., "dice_result")
+//   - args: Values for the format string's verbs (none: the message as-is)
+//
+// Returns:
+//   - string: The translated message
+func (t *Translator) T(lang, key string, args ...any) string {
+	format, ok := t.messages[t.Match(lang)][key]
+	if !ok {
+		format, ok = t.messages[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Match returns the available language closest to a code
+// "fr", "FR" and "fr-CA" all match fr.yaml; unknown codes match DefaultLanguage.
+func (t *Translator) Match(lang string) string {
+	lang = strings.ToLower(lang)
+	if _, ok := t.messages[lang]; ok {
+		return lang
+	}
+	base, _, _ := strings.Cut(lang, "-")
+	if _, ok := t.messages[base]; ok {
+		return base
+	}
+	return DefaultLanguage
+}
+
+// Has reports whether a language has its own file (exact code, case-insensitive)
+func (t *Translator) Has(lang string) bool {
+	_, ok := t.messages[strings.ToLower(lang)]
+	return ok
+}
+
+// Languages returns the available language codes, sorted
+func (t *Translator) Languages() []string {
+	langs := make([]string, 0, len(t.messages))
+	for lang := range t.messages {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// Keys returns the message keys of a language, sorted (nil if it doesn't exist)
+func (t *Translator) Keys(lang string) []string {
+	messages, ok := t.messages[lang]
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// parseMessages reads one locale file: a YAML mapping of keys to messages
+//
+// Rejected (an error rather than a silently wrong message):
+//   - Values that are not strings (nested mappings, lists)
+//   - Duplicate keys (yaml.v3 refuses them)
+//   - Empty keys and empty messages
+//
+// Parameters:
+//   - data: File contents
+//
+// Returns:
+//   - map[string]string: Key -> message
+//   - error: The YAML error (with its line), or the first empty key or message
+func parseMessages(data []byte) (map[string]string, error) {
+	messages := make(map[string]string)
+	if err := yaml.Unmarshal(data, &messages); err != nil {
+		return nil, err
+	}
+
+	// Sorted, so the same file always reports the same error
+	for _, key := range slices.Sorted(maps.Keys(messages)) {
+		if key == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+		if messages[key] == "" {
+			return nil, fmt.Errorf("%s: empty message", key)
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,166 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// formatVerb matches fmt verbs in a message (%% is not a verb)
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// verbs returns the fmt verbs of a message in order, without %%
+func verbs(message string) []string {
+	var found []string
+	for _, verb := range formatVerb.FindAllString(message, -1) {
+		if verb != "%%" {
+			found = append(found, verb)
+		}
+	}
+	return found
+}
+
+// TestDefault_Locales checks the embedded files against English
+//
+// Checks:
+//   - en, fr and de load
+//   - Every language has exactly the English keys
+//   - Each translation takes the same fmt verbs in the same order
+//     (a missing %d would print "%!d(MISSING)" to users)
+func TestDefault_Locales(t *testing.T) {
+	tr := Default()
+
+	if got, want := tr.Languages(), []string{"de", "en", "fr"}; !slices.Equal(got, want) {
+		t.Fatalf("Languages() = %v, want %v", got, want)
+	}
+
+	english := tr.Keys(DefaultLanguage)
+	for _, lang := range tr.Languages() {
+		if got := tr.Keys(lang); !slices.Equal(got, english) {
+			t.Errorf("%s keys = %v, want the English keys %v", lang, got, english)
+		}
+		for _, key := range english {
+			want := verbs(tr.messages[DefaultLanguage][key])
+			if got := verbs(tr.messages[lang][key]); !slices.Equal(got, want) {
+				t.Errorf("%s %s verbs = %v, want %v (as in English)", lang, key, got, want)
+			}
+		}
+	}
+}
+
+// TestTranslator_T tests lookup, formatting and fallbacks
+func TestTranslator_T(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.yaml": {Data: []byte("greeting: \"Hello, %s!\"\nonly_en: Only English\n")},
+		"locales/fr.yaml": {Data: []byte("greeting: \"Bonjour, %s !\"\n")},
+	}
+	tr, err := New(fsys, "locales")
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		lang string
+		key  string
+		args []any
+		want string
+	}{
+		{name: "english", lang: "en", key: "greeting", args: []any{"Ann"}, want: "Hello, Ann!"},
+		{name: "french", lang: "fr", key: "greeting", args: []any{"Ann"}, want: "Bonjour, Ann !"},
+		{name: "upper case code", lang: "FR", key: "greeting", args: []any{"Ann"}, want: "Bonjour, Ann !"},
+		{name: "region falls back to base", lang: "fr-CA", key: "greeting", args: []any{"Ann"}, want: "Bonjour, Ann !"},
+		{name: "unknown language", lang: "xx", key: "greeting", args: []any{"Ann"}, want: "Hello, Ann!"},
+		{name: "empty language", lang: "", key: "greeting", args: []any{"Ann"}, want: "Hello, Ann!"},
+		{name: "key missing in language", lang: "fr", key: "only_en", want: "Only English"},
+		{name: "key missing everywhere", lang: "fr", key: "nope", want: "nope"},
+		{name: "no args keeps verbs", lang: "en", key: "greeting", want: "Hello, %s!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tr.T(tt.lang, tt.key, tt.args...); got != tt.want {
+				t.Errorf("T(%q, %q) = %q, want %q", tt.lang, tt.key, got, tt.want)
+			}
+		})
+	}
+
+	if !tr.Has("FR") || tr.Has("fr-CA") || tr.Has("de") {
+		t.Errorf("Has(FR, fr-CA, de) = %v, %v, %v, want true, false, false", tr.Has("FR"), tr.Has("fr-CA"), tr.Has("de"))
+	}
+}
+
+// TestNew_Errors tests that broken locale directories are rejected
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr string
+	}{
+		{name: "no english", files: fstest.MapFS{"locales/fr.yaml": {Data: []byte("a: b\n")}}, wantErr: "no en.yaml"},
+		{name: "bad file", files: fstest.MapFS{"locales/en.yaml": {Data: []byte("a b\n")}}, wantErr: "locales/en.yaml: yaml: unmarshal errors:\n  line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.files, "locales"); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParseMessages tests reading locale files
+func TestParseMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "values",
+			data: "# comment\n\nplain: Hello there  \ndouble: \"Line 1\\nLine 2: \\\"quoted\\\"\"\nsingle: 'It''s: fine'\nunicode: \"caf\\u00e9 ☕\"\r\nblock: |-\n  Line 1\n  Line 2\n",
+			want: map[string]string{
+				"plain":   "Hello there",
+				"double":  "Line 1\nLine 2: \"quoted\"",
+				"single":  "It's: fine",
+				"unicode": "café ☕",
+				"block":   "Line 1\nLine 2",
+			},
+		},
+		{name: "empty file", data: "", want: map[string]string{}},
+		{name: "not a mapping", data: "just text\n", wantErr: "line 1: cannot unmarshal"},
+		{name: "empty key", data: "\"\": value\n", wantErr: "empty key"},
+		{name: "empty message", data: "a: b\nc:\n", wantErr: "c: empty message"},
+		{name: "nested", data: "parent:\n  child: x\n", wantErr: "line 2: cannot unmarshal !!map"},
+		{name: "duplicate", data: "a: b\na: c\n", wantErr: `mapping key "a" already defined`},
+		{name: "unterminated double", data: "a: \"open\n", wantErr: "yaml:"},
+		{name: "list", data: "a: [1, 2]\n", wantErr: "cannot unmarshal !!seq"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMessages([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseMessages() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseMessages() unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("parseMessages() = %q, want %q", got, tt.want)
+			}
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
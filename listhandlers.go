@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Alrem/run-tbot/handlers"
+)
+
+// printRoutes writes the routes for --list-handlers, one per line
+//
+// Format (tab-separated, with a header line, for cut/awk/column -t):
+//
+//	TYPE	NAME	HANDLER	AUTH
+//	command	/ovh	handlers.HandleOVHCheck	yes
+//	button	🎲 Dice	handlers.handleDiceButton	no
+//	callback	ovh:*	handlers.handleOVHCallback	yes
+//
+// AUTH is "yes" for routes only authorized users (ALLOWED_USERS) can use.
+//
+// Parameters:
+//   - w: Output (os.Stdout)
+//   - routes: Routes to print (handlers.Routes)
+//
+// Returns:
+//   - error: Write error, if any
+func printRoutes(w io.Writer, routes []handlers.Route) error {
+	if _, err := fmt.Fprintln(w, "TYPE\tNAME\tHANDLER\tAUTH"); err != nil {
+		return err
+	}
+	for _, route := range routes {
+		auth := "no"
+		if route.Private {
+			auth = "yes"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", route.Type, route.Name, route.Handler, auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/handlers"
+)
+
+// TestPrintRoutes tests the tab-separated --list-handlers output
+func TestPrintRoutes(t *testing.T) {
+	routes := []handlers.Route{
+		{Type: handlers.RouteCommand, Name: "/start", Handler: "handlers.HandleStart"},
+		{Type: handlers.RouteButton, Name: "📊 Stats", Handler: "handlers.HandleAdminStats", Private: true},
+	}
+
+	var out strings.Builder
+	if err := printRoutes(&out, routes); err != nil {
+		t.Fatalf("printRoutes() error = %v", err)
+	}
+
+	want := "TYPE\tNAME\tHANDLER\tAUTH\n" +
+		"command\t/start\thandlers.HandleStart\tno\n" +
+		"button\t📊 Stats\thandlers.HandleAdminStats\tyes\n"
+	if out.String() != want {
+		t.Errorf("printRoutes() output = %q, want %q", out.String(), want)
+	}
+}
+
+// TestPrintRoutes_AllRoutes tests that every real route prints as four columns
+func TestPrintRoutes_AllRoutes(t *testing.T) {
+	var out strings.Builder
+	if err := printRoutes(&out, handlers.Routes()); err != nil {
+		t.Fatalf("printRoutes() error = %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if fields := strings.Split(line, "\t"); len(fields) != 4 {
+			t.Errorf("line %q has %d fields, want 4", line, len(fields))
+		}
+	}
+}
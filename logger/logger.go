@@ -0,0 +1,91 @@
+// Package logger carries a per-update *slog.Logger through context.Context
+//
+// Why?
+//   - One webhook request = one Telegram update, handled by several functions
+//   - Bare slog calls in each handler don't share any fields, so matching the
+//     "Received update" line with handler logs means comparing timestamps
+//   - A logger pre-configured with update_id/user_id/chat_id, stored in the
+//     context, gives every log line of one update the same fields
+//
+// Usage:
+//
+//	ctx = logger.WithContext(ctx, logger.ForUpdate(slog.Default(), update))
+//	...
+//	log := logger.FromContext(ctx)
+//	log.Info("Dice rolled", "result", 4) // includes update_id, user_id, chat_id
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// contextKey is an unexported type for context keys
+// A private type guarantees no other package can read or overwrite our value
+// (context keys are compared by type AND value)
+type contextKey struct{}
+
+// WithContext returns a copy of ctx that carries l
+//
+// Parameters:
+//   - ctx: Parent context
+//   - l: Logger to attach
+//
+// Returns:
+//   - context.Context: Child context with the logger
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by WithContext
+// Falls back to slog.Default(), so callers never need a nil check
+// (e.g., tests or background jobs that don't process an update)
+//
+// Parameters:
+//   - ctx: Context (may have no logger)
+//
+// Returns:
+//   - *slog.Logger: Stored logger or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// ForUpdate returns base with fields identifying the update
+//
+// Fields:
+//   - update_id: always (unique per update, also used as request ID)
+//   - user_id: if the update has a sender
+//   - chat_id: if the update belongs to a chat
+//
+// Parameters:
+//   - base: Logger to extend (usually slog.Default())
+//   - update: Telegram update being processed
+//
+// Returns:
+//   - *slog.Logger: Logger with the update fields attached
+func ForUpdate(base *slog.Logger, update tgbotapi.Update) *slog.Logger {
+	attrs := []any{"update_id", update.UpdateID}
+
+	// SentFrom/FromChat cover messages, callbacks, inline queries, ...
+	// but not my_chat_member, which is handled separately
+	user := update.SentFrom()
+	chat := update.FromChat()
+	if update.MyChatMember != nil {
+		user = &update.MyChatMember.From
+		chat = &update.MyChatMember.Chat
+	}
+
+	if user != nil {
+		attrs = append(attrs, "user_id", user.ID)
+	}
+	if chat != nil {
+		attrs = append(attrs, "chat_id", chat.ID)
+	}
+
+	return base.With(attrs...)
+}
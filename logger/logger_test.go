@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestFromContext tests storing and falling back to the default logger
+func TestFromContext(t *testing.T) {
+	if FromContext(context.Background()) != slog.Default() {
+		t.Errorf("FromContext() without logger should return slog.Default()")
+	}
+
+	l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(WithContext(context.Background(), l)) != l {
+		t.Errorf("FromContext() should return the logger stored by WithContext")
+	}
+}
+
+// TestForUpdate tests which fields are attached for different update types
+func TestForUpdate(t *testing.T) {
+	user := &tgbotapi.User{ID: 7}
+	chat := &tgbotapi.Chat{ID: -100}
+
+	tests := []struct {
+		name   string
+		update tgbotapi.Update
+		want   []string
+		absent []string
+	}{
+		{
+			name:   "message",
+			update: tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{From: user, Chat: chat}},
+			want:   []string{"update_id=1", "user_id=7", "chat_id=-100"},
+		},
+		{
+			name:   "inline query has no chat",
+			update: tgbotapi.Update{UpdateID: 2, InlineQuery: &tgbotapi.InlineQuery{From: user}},
+			want:   []string{"update_id=2", "user_id=7"},
+			absent: []string{"chat_id"},
+		},
+		{
+			name:   "my_chat_member",
+			update: tgbotapi.Update{UpdateID: 3, MyChatMember: &tgbotapi.ChatMemberUpdated{From: *user, Chat: *chat}},
+			want:   []string{"update_id=3", "user_id=7", "chat_id=-100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ForUpdate(slog.New(slog.NewTextHandler(&buf, nil)), tt.update).Info("test")
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("log line missing %q: %s", want, out)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(out, absent) {
+					t.Errorf("log line should not contain %q: %s", absent, out)
+				}
+			}
+		})
+	}
+}
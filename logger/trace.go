@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// TraceHeader is the header Google's front end adds to every request
+// reaching Cloud Run, e.g. "105445aa7843bc8bf206b12000100000/1;o=1"
+const TraceHeader = "X-Cloud-Trace-Context"
+
+// Special fields recognized by Cloud Logging in JSON log lines
+// Entries with the same trace are grouped under one request in the console
+const (
+	traceKey        = "logging.googleapis.com/trace"
+	spanIDKey       = "logging.googleapis.com/spanId"
+	traceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// TraceContext is a parsed X-Cloud-Trace-Context header
+type TraceContext struct {
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 hex characters (Cloud Logging format), "" if absent
+	Sampled bool   // ";o=1": the request is recorded by Cloud Trace
+}
+
+// ParseTraceContext parses an X-Cloud-Trace-Context header value
+//
+// Format: TRACE_ID[/SPAN_ID][;o=OPTIONS]
+//   - TRACE_ID: 32 hex characters
+//   - SPAN_ID: decimal unsigned 64-bit integer (converted to hex for Cloud Logging)
+//   - OPTIONS: "1" if the request is traced, "0" otherwise
+//
+// Parameters:
+//   - header: Header value (may be empty)
+//
+// Returns:
+//   - TraceContext: Parsed values
+//   - bool: false if the header is empty or malformed
+func ParseTraceContext(header string) (TraceContext, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return TraceContext{}, false
+	}
+
+	ids, options, hasOptions := strings.Cut(header, ";")
+	traceID, spanID, hasSpan := strings.Cut(ids, "/")
+
+	if len(traceID) != 32 || !isHex(traceID) {
+		return TraceContext{}, false
+	}
+	tc := TraceContext{TraceID: strings.ToLower(traceID)}
+
+	if hasSpan {
+		span, err := strconv.ParseUint(spanID, 10, 64)
+		if err != nil {
+			return TraceContext{}, false
+		}
+		tc.SpanID = fmt.Sprintf("%016x", span)
+	}
+
+	if hasOptions {
+		switch options {
+		case "o=1":
+			tc.Sampled = true
+		case "o=0":
+		default:
+			return TraceContext{}, false
+		}
+	}
+
+	return tc, true
+}
+
+// isHex reports whether s consists only of hex digits
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithTrace returns base with Cloud Logging trace fields attached
+//
+// Fields:
+//   - logging.googleapis.com/trace: "projects/<projectID>/traces/<traceID>"
+//   - logging.googleapis.com/spanId: span in hex (if present)
+//   - logging.googleapis.com/trace_sampled: true if Cloud Trace records the request
+//
+// If projectID is empty or the header is missing/malformed, base is
+// returned unchanged (logs just aren't grouped by request).
+//
+// Parameters:
+//   - base: Logger to extend
+//   - header: X-Cloud-Trace-Context header value
+//   - projectID: Google Cloud project ID (GOOGLE_CLOUD_PROJECT)
+//
+// Returns:
+//   - *slog.Logger: Logger with trace fields, or base
+func WithTrace(base *slog.Logger, header, projectID string) *slog.Logger {
+	if projectID == "" {
+		return base
+	}
+	tc, ok := ParseTraceContext(header)
+	if !ok {
+		return base
+	}
+
+	attrs := []any{traceKey, "projects/" + projectID + "/traces/" + tc.TraceID}
+	if tc.SpanID != "" {
+		attrs = append(attrs, spanIDKey, tc.SpanID)
+	}
+	if tc.Sampled {
+		attrs = append(attrs, traceSampledKey, true)
+	}
+	return base.With(attrs...)
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestParseTraceContext tests X-Cloud-Trace-Context parsing
+//
+// Cases:
+//   - Full header, trace only, trace + span, unsampled
+//   - Malformed: empty, short trace, non-hex trace, bad span, bad options
+func TestParseTraceContext(t *testing.T) {
+	const traceID = "105445aa7843bc8bf206b12000100000"
+
+	tests := []struct {
+		name   string
+		header string
+		want   TraceContext
+		wantOK bool
+	}{
+		{name: "full", header: traceID + "/1;o=1", want: TraceContext{TraceID: traceID, SpanID: "0000000000000001", Sampled: true}, wantOK: true},
+		{name: "trace only", header: traceID, want: TraceContext{TraceID: traceID}, wantOK: true},
+		{name: "not sampled", header: traceID + "/255;o=0", want: TraceContext{TraceID: traceID, SpanID: "00000000000000ff"}, wantOK: true},
+		{name: "uppercase trace", header: strings.ToUpper(traceID), want: TraceContext{TraceID: traceID}, wantOK: true},
+		{name: "max span", header: traceID + "/18446744073709551615", want: TraceContext{TraceID: traceID, SpanID: "ffffffffffffffff"}, wantOK: true},
+		{name: "empty", header: ""},
+		{name: "short trace", header: "105445aa/1;o=1"},
+		{name: "non-hex trace", header: "zz5445aa7843bc8bf206b12000100000/1"},
+		{name: "span not a number", header: traceID + "/abc"},
+		{name: "empty span", header: traceID + "/;o=1"},
+		{name: "span overflow", header: traceID + "/18446744073709551616"},
+		{name: "bad options", header: traceID + "/1;sampled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseTraceContext(tt.header)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseTraceContext(%q) ok = %v, want %v", tt.header, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTraceContext(%q) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWithTrace tests that trace fields are attached only with a project and a valid header
+func TestWithTrace(t *testing.T) {
+	const header = "105445aa7843bc8bf206b12000100000/1;o=1"
+
+	tests := []struct {
+		name      string
+		header    string
+		projectID string
+		want      []string
+		absent    []string
+	}{
+		{
+			name:      "header and project",
+			header:    header,
+			projectID: "my-project",
+			want: []string{
+				`"logging.googleapis.com/trace":"projects/my-project/traces/105445aa7843bc8bf206b12000100000"`,
+				`"logging.googleapis.com/spanId":"0000000000000001"`,
+				`"logging.googleapis.com/trace_sampled":true`,
+			},
+		},
+		{name: "no project", header: header, absent: []string{"logging.googleapis.com"}},
+		{name: "no header", projectID: "my-project", absent: []string{"logging.googleapis.com"}},
+		{name: "malformed header", header: "garbage", projectID: "my-project", absent: []string{"logging.googleapis.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			base := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			WithTrace(base, tt.header, tt.projectID).Info("test")
+
+			logs := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(logs, want) {
+					t.Errorf("log missing %s\n\nGot:\n%s", want, logs)
+				}
+			}
+			for _, absent := range tt.absent {
+				if strings.Contains(logs, absent) {
+					t.Errorf("log unexpectedly contains %s\n\nGot:\n%s", absent, logs)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -13,76 +16,229 @@ import (
 	"github.com/Alrem/run-tbot/bot"
 	"github.com/Alrem/run-tbot/config"
 	"github.com/Alrem/run-tbot/handlers"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/metrics"
+	"github.com/Alrem/run-tbot/middleware"
+	"github.com/Alrem/run-tbot/ovh"
+	"github.com/Alrem/run-tbot/status"
+	"github.com/Alrem/run-tbot/storage"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 func main() {
+	// Command-line flags (configuration itself comes from environment variables,
+	// flags override it for local experiments), see parseFlags
+	flags, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return // -help: usage was printed
+	}
+	if err != nil {
+		os.Exit(2) // the flag package already printed the error and usage
+	}
+
+	if flags.migrate {
+		// Placeholder: the storage backends (memory, file, Firestore, Redis) have no schema
+		// A database-backed storage.Store will run its schema migrations here
+		fmt.Println("No migrations to run.")
+		return
+	}
+
+	if flags.listHandlers {
+		// Reads the routing tables only: no config or BOT_TOKEN needed
+		if err := printRoutes(os.Stdout, handlers.Routes()); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Step 1: Initialize structured logger with JSON output
 	// slog is Go's standard structured logging library (since Go 1.21)
 	// JSON format is perfect for Cloud Run - Google Cloud Logging parses it automatically
 	// NewJSONHandler writes logs as JSON to stdout
 	// Each log entry will have: time, level, msg, and any additional fields
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	// The level is a LevelVar: LOG_LEVEL is only known after config.Load,
+	// but startup errors before that should be logged too (at info level)
+	var logLevel slog.LevelVar
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevel}))
 
 	// Set as default logger so slog.Info(), slog.Error() work globally
 	slog.SetDefault(logger)
 
 	slog.Info("Starting Telegram bot application")
 
+	// Root context for the whole application lifetime
+	// signal.NotifyContext cancels it on SIGINT (Ctrl+C) or SIGTERM (Cloud Run stop)
+	// Every long-running goroutine selects on ctx.Done() to know when to stop
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Background goroutines (watchers, schedulers, ...) are started with tasks.Go
+	// so shutdown can wait for them to finish, see background.go
+	var tasks backgroundTasks
+
 	// Step 2: Load configuration from environment variables
 	// Config contains: BotToken, Port, Environment, AllowedUsers
-	cfg, err := config.Load()
+	cfg, err := config.Load(flags.overrides)
 	if err != nil {
 		// Fatal error - can't proceed without valid config
 		// This will log and exit with status code 1
 		slog.Error("Failed to load configuration", "error", err)
 		os.Exit(1)
 	}
+	logLevel.Set(cfg.LogLevel)
 
 	// Log config (but never log the actual BOT_TOKEN for security!)
+	// WEBHOOK_PATH is a secret too, so only log whether a custom one is set
 	slog.Info("Configuration loaded",
 		"port", cfg.Port,
 		"environment", cfg.Environment,
-		"allowed_users_count", len(cfg.AllowedUsers))
+		"webhook_path_custom", cfg.WebhookPath != "/webhook",
+		"allowed_users_count", len(cfg.AllowedUsers),
+		"allowed_usernames_count", len(cfg.AllowedUsernames),
+		"log_level", cfg.LogLevel.String(),
+		"polling", cfg.Polling,
+		"bots", max(len(cfg.Bots), 1))
+	// Everything else at debug level; cfg.String() masks the secrets
+	slog.Debug("Full configuration", "config", cfg.String())
 
-	// Step 3: Initialize Telegram bot
-	// cfg.IsDevelopment() enables debug mode which logs all HTTP requests/responses
-	// Useful for learning and debugging, but disable in production (verbose)
-	botAPI, err := bot.NewBot(cfg.BotToken, cfg.IsDevelopment())
+	// Profiling exposes internals and costs CPU while a profile runs
+	if cfg.EnablePprof {
+		if cfg.IsDevelopment() {
+			slog.Info("pprof endpoints enabled", "path", pprofPrefix)
+		} else {
+			slog.Warn("pprof endpoints ENABLED IN PRODUCTION - disable ENABLE_PPROF when done profiling",
+				"path", pprofPrefix,
+				"environment", cfg.Environment)
+		}
+	}
+
+	// Persistent state (chat preferences, subscriptions, ...), see storage.Open
+	// STORAGE_BACKEND=file loads STORAGE_PATH here, so a corrupt file stops startup
+	store, err := storage.Open(ctx, storage.Options{
+		Backend:    cfg.StorageBackend,
+		Path:       cfg.StoragePath,
+		Project:    cfg.GoogleCloudProject,
+		Collection: cfg.FirestoreCollection,
+		RedisURL:   cfg.RedisURL,
+	})
 	if err != nil {
-		slog.Error("Failed to create bot", "error", err)
+		slog.Error("Failed to open storage", "backend", cfg.StorageBackend, "error", err)
 		os.Exit(1)
 	}
+	slog.Info("Storage opened", "backend", cfg.StorageBackend)
 
-	// Log bot info (bot.Self contains bot's username, ID, etc.)
-	slog.Info("Bot authorized successfully",
-		"bot_username", botAPI.Self.UserName,
-		"bot_id", botAPI.Self.ID)
+	// The daily admin summary lists changes since the previous one
+	handlers.SetOfferSnapshots(ovh.NewSnapshotStore(store))
 
-	// Step 4: Setup HTTP routes
-	// http.ServeMux is Go's built-in HTTP request router
-	mux := http.NewServeMux()
+	// /users reports who used the bot recently (IDs and names, no message content)
+	handlers.SetUserStore(storage.NewUserStore(store))
 
-	// Route 1: Health check endpoint for Cloud Run
-	// Cloud Run pings this to verify service is alive
-	// Simply returns 200 OK
-	mux.HandleFunc("/", healthCheckHandler)
+	// /broadcast: every chat the bot has seen, reloaded from storage.
+	// A load failure only shrinks the broadcast audience, so it doesn't stop startup
+	handlers.SetKnownChatStore(storage.NewChatStore(store))
+	// Broadcasts run as background tasks: SIGTERM stops them, shutdown waits for them
+	handlers.SetBackgroundTasks(func(name string, task func(ctx context.Context)) {
+		tasks.Go(ctx, name, task)
+	})
+	if n, err := handlers.LoadKnownChats(ctx); err != nil {
+		slog.Error("Failed to load known chats", "error", err)
+	} else {
+		slog.Info("Known chats loaded", "chats", n)
+	}
 
-	// Route 2: Telegram webhook endpoint
-	// Telegram sends POST requests with Update JSON to this endpoint
-	// We'll pass botAPI and cfg to the handler via closure
-	mux.HandleFunc("/webhook", webhookHandler(botAPI, cfg))
+	// /audit: who used (or tried to use) private features
+	handlers.SetAuditStore(storage.NewAuditStore(store))
+
+	// /usage: feature counters, buffered in memory and flushed every minute
+	handlers.SetUsageStore(storage.NewUsageStore(store))
+	tasks.Go(ctx, "usage", func(ctx context.Context) {
+		handlers.RunUsageFlusher(ctx, time.Minute)
+	})
+
+	// OVH requests per user are spaced by OVH_COOLDOWN (0 disables)
+	handlers.SetOVHCooldown(cfg.OVHCooldown)
+
+	// Each OVH HTTP request is bounded by OVH_TIMEOUT (set before the first request)
+	ovh.RequestTimeout = cfg.OVHTimeout
+
+	// OVH client metrics (ovh_* at GET /metrics) and a summary line per offers fetch
+	ovh.SetHooks(ovhMetricsHooks{})
+
+	// Update types Telegram should deliver: ALLOWED_UPDATES if set,
+	// otherwise exactly the types the router handles (see handlers.updateRoutes)
+	allowedUpdates := cfg.AllowedUpdates
+	if allowedUpdates == nil {
+		allowedUpdates = handlers.AllowedUpdates()
+	}
+	slog.Info("Allowed update types", "allowed_updates", allowedUpdates)
+
+	// Step 3: Initialize Telegram bots (one for BOT_TOKEN, several for BOT_TOKENS)
+	// getMe, webhook registration and command menus, see startBot in bots.go
+	bots := botInstances(cfg)
+	for i := range bots {
+		bots[i], err = startBot(bots[i], allowedUpdates)
+		if err != nil {
+			slog.Error("Failed to start bot", "bot", bots[i].label(), "error", err)
+			os.Exit(1)
+		}
+	}
+	// /goodmorning subscriptions are shared (keyed by chat ID), so the
+	// messages go out through the first bot
+	sender := bots[0].sender
+
+	// Daily /goodmorning messages at MORNING_HOUR:00 UTC
+	// Note: on Cloud Run the scheduler only runs while an instance is alive
+	tasks.Go(ctx, "goodmorning", func(ctx context.Context) {
+		runGoodMorningScheduler(ctx, sender, cfg.MorningHour)
+	})
+
+	// OVH price changes for FQN subscribers, past PRICE_CHANGE_THRESHOLD_PCT
+	handlers.SetPriceWatcher(ovh.NewPriceWatcher(cfg.PriceChangeThresholdPct))
+	tasks.Go(ctx, "pricewatch", func(ctx context.Context) {
+		runPriceWatchScheduler(ctx, sender, priceWatchInterval)
+	})
+
+	// Daily OVH summary to admins at ADMIN_SUMMARY_TIME (off unless configured)
+	// Every bot has its own admins, so every bot gets its own scheduler
+	if cfg.AdminSummaryEnabled {
+		for _, b := range bots {
+			tasks.Go(ctx, "adminsummary:"+b.label(), func(ctx context.Context) {
+				runAdminSummaryScheduler(ctx, b.sender, b.cfg)
+			})
+		}
+	}
+
+	// POLLING: fetch updates with getUpdates instead of waiting for the webhook
+	// (the HTTP server still runs for health checks and /metrics)
+	if cfg.Polling {
+		for _, b := range bots {
+			tasks.Go(ctx, "polling:"+b.label(), func(ctx context.Context) {
+				runPolling(ctx, b.api, b, allowedUpdates)
+			})
+		}
+	}
+
+	// Step 4: Setup HTTP routes (see newBotsMux)
+	mux := newBotsMux(cfg, bots)
 
 	// Step 5: Create HTTP server with timeouts
 	// Timeouts prevent hanging connections and DoS attacks
 	server := &http.Server{
-		Addr:    ":" + cfg.Port, // Listen on all interfaces, port from config
-		Handler: mux,
+		Addr: ":" + cfg.Port, // Listen on all interfaces, port from config
+		// AccessLog writes one entry per request (status, duration, ...)
+		// Successful health checks are skipped: Cloud Run probes them constantly
+		// WEBHOOK_PATH is secret, so it is logged as "<webhook>" ("<webhook:name>" per bot of BOT_TOKENS)
+		// Rate limiting sits inside AccessLog, so 429s are logged too
+		Handler: middleware.AccessLog(newRateLimiter(mux, cfg), middleware.AccessLogOptions{
+			SkipPaths:  []string{"/", "/healthz"},
+			PathLabels: webhookPathLabels(cfg),
+		}),
 		// ReadTimeout: max time to read request (headers + body)
 		ReadTimeout: 15 * time.Second,
 		// WriteTimeout: max time to write response
-		WriteTimeout: 15 * time.Second,
+		// UPDATE_TIMEOUT is kept below it, see config.ServerWriteTimeout
+		WriteTimeout: config.ServerWriteTimeout,
 		// IdleTimeout: max time to keep connection open between requests
 		IdleTimeout: 60 * time.Second,
 	}
@@ -105,36 +261,238 @@ func main() {
 	// Step 7: Wait for interrupt signal for graceful shutdown
 	// Graceful shutdown = finish processing current requests before stopping
 	// This is important for Cloud Run deployments
-
-	// Create channel to receive OS signals
-	// Buffered channel with size 1 prevents blocking
-	quit := make(chan os.Signal, 1)
-
-	// Notify channel on SIGINT (Ctrl+C) or SIGTERM (Cloud Run stop)
-	// SIGINT = interrupt signal (Ctrl+C in terminal)
-	// SIGTERM = termination signal (sent by Cloud Run on shutdown)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// Block here until we receive a signal
-	sig := <-quit
-	slog.Info("Received shutdown signal", "signal", sig.String())
+	// ctx.Done() is closed when SIGINT/SIGTERM arrives (background tasks see it too)
+	<-ctx.Done()
+	stop() // Restore default signal handling: a second Ctrl+C kills immediately
+	slog.Info("Received shutdown signal")
 
 	// Step 8: Graceful shutdown
-	// Give server 30 seconds to finish existing requests
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// Give server and background tasks 30 seconds in total to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel() // Ensure context is cancelled to free resources
 
-	// Shutdown gracefully
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop accepting new webhook requests, finish in-flight ones
+	if err := server.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Server forced to shutdown", "error", err)
 		os.Exit(1)
 	}
 
+	// Wait for background goroutines (already notified via ctx cancellation)
+	if err := tasks.Wait(shutdownCtx); err != nil {
+		slog.Error("Background tasks forced to stop", "error", err)
+		os.Exit(1)
+	}
+
+	// Updates handled after the usage flusher stopped (server.Shutdown drains
+	// in-flight requests) are still counted: flush them once more
+	if err := handlers.FlushUsage(shutdownCtx); err != nil {
+		slog.Error("Failed to flush usage counters", "error", err)
+	}
+
+	// Close storage last: background tasks may still have been writing to it
+	if err := store.Close(); err != nil {
+		slog.Error("Failed to close storage", "error", err)
+	}
+
 	slog.Info("Server stopped gracefully")
 }
 
-// healthCheckHandler handles GET / requests for Cloud Run health checks
-// Returns 200 OK to indicate service is alive and ready
+// newMux builds the HTTP router for a single bot (see newBotsMux)
+//
+// Parameters:
+//   - sender: Bot sender passed to the webhook handler
+//   - cfg: Application configuration (webhook path, authorization, ...)
+//
+// Returns:
+//   - *http.ServeMux: Router ready to be used as http.Server.Handler
+func newMux(sender bot.BotSender, cfg *config.Config) *http.ServeMux {
+	return newBotsMux(cfg, []botInstance{{sender: sender, cfg: cfg}})
+}
+
+// newBotsMux builds the HTTP router with all endpoints
+// Extracted from main so tests can send requests through the real routing
+//
+// Routes:
+//   - "/healthz": health check for Cloud Run (JSON status)
+//   - "/{$}" (exactly "/"): the same health check, only with ROOT_HEALTH_CHECK
+//   - Each bot's cfg.WebhookPath: Telegram webhook (WEBHOOK_PATH, default "/webhook";
+//     WEBHOOK_PATH/<name> for every bot of BOT_TOKENS)
+//   - "/metrics": Prometheus metrics
+//   - pprofPrefix: profiling endpoints, only with ENABLE_PPROF
+//   - "/": every other path, a JSON 404 error (notFoundHandler)
+//
+// Parameters:
+//   - cfg: Shared application configuration (health check, pprof, ...)
+//   - bots: Bots to serve, each with its own sender and webhook path
+//
+// Returns:
+//   - *http.ServeMux: Router ready to be used as http.Server.Handler
+func newBotsMux(cfg *config.Config, bots []botInstance) *http.ServeMux {
+	// http.ServeMux is Go's built-in HTTP request router
+	mux := http.NewServeMux()
+
+	// Route 1: Health check endpoint
+	// Cloud Run and monitoring ping this to verify service is alive
+	// Simply returns 200 OK
+	mux.HandleFunc("/healthz", healthCheckHandler)
+
+	// "/{$}" matches only "/" itself (without {$}, "/" would match every path)
+	// Optional, see ROOT_HEALTH_CHECK
+	if cfg.RootHealthCheck {
+		mux.HandleFunc("/{$}", healthCheckHandler)
+	}
+
+	// Everything not matched below gets a 404 JSON error
+	// (previously every unknown path answered "OK", hiding misrouted requests)
+	mux.HandleFunc("/", notFoundHandler)
+
+	// Route 2: Telegram webhook endpoints, one per bot
+	// Telegram sends POST requests with Update JSON to this endpoint
+	// The path comes from config, so it can be made hard to guess
+	// We'll pass each bot's sender and cfg to its handler via closure,
+	// so an update is always answered by the bot it was sent to
+	for _, b := range bots {
+		mux.HandleFunc(b.cfg.WebhookPath, webhookHandler(b))
+	}
+
+	// Route 3: Prometheus metrics (handler_invocations_total, ...), see metrics package
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Route 4: Profiling endpoints (ENABLE_PPROF), protected by PPROF_TOKEN
+	// When disabled, /debug/pprof/ falls through to notFoundHandler
+	if cfg.EnablePprof {
+		mux.Handle(pprofPrefix, newPprofHandler(cfg.PprofToken))
+	}
+
+	return mux
+}
+
+// newRateLimiter wraps the mux with per-IP rate limiting (RATE_LIMIT, WEBHOOK_RATE_LIMIT)
+// Burst is twice the per-second rate, so short spikes pass without a 429.
+//
+// Parameters:
+//   - next: Handler to protect (the mux from newMux)
+//   - cfg: Application configuration (limits and webhook path)
+//
+// Returns:
+//   - http.Handler: Rate-limited handler
+func newRateLimiter(next http.Handler, cfg *config.Config) http.Handler {
+	// Every bot's webhook gets the webhook limit (each path has its own bucket)
+	webhookLimit := middleware.Limit{Rate: float64(cfg.WebhookRateLimit), Burst: 2 * cfg.WebhookRateLimit}
+	pathLimits := make(map[string]middleware.Limit)
+	for _, path := range webhookPaths(cfg) {
+		pathLimits[path] = webhookLimit
+	}
+
+	return middleware.RateLimit(next, middleware.RateLimitOptions{
+		Default:    middleware.Limit{Rate: float64(cfg.RateLimit), Burst: 2 * cfg.RateLimit},
+		PathLimits: pathLimits,
+	})
+}
+
+// runGoodMorningScheduler sends the /goodmorning message once a day.
+// Ticks every minute and checks whether the current minute is MORNING_HOUR:00 UTC.
+//
+// The lastSent date guards against sending twice on the same day
+// (e.g., if a tick is delayed and two ticks land in the same minute).
+//
+// Parameters:
+//   - ctx: Cancelled on shutdown; the scheduler returns when it's done
+//   - sender: Bot sender for delivering messages
+//   - hour: Target hour in UTC (cfg.MorningHour)
+func runGoodMorningScheduler(ctx context.Context, sender bot.BotSender, hour int) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	var lastSent string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			today := now.UTC().Format(time.DateOnly)
+			if !handlers.IsGoodMorningTime(now, hour) || lastSent == today {
+				continue
+			}
+			lastSent = today
+			handlers.SendGoodMorning(ctx, sender)
+		}
+	}
+}
+
+// priceWatchInterval is the time between two OVH price checks
+// OVH data is cached for 5 minutes, so checks never hit the cache twice.
+const priceWatchInterval = 15 * time.Minute
+
+// runPriceWatchScheduler checks OVH prices for FQN subscribers every interval
+// (see handlers.CheckPriceChanges). The first check runs after one interval.
+//
+// Parameters:
+//   - ctx: Cancelled on shutdown; the scheduler returns when it's done
+//   - sender: Bot sender for delivering notifications
+//   - interval: Time between two checks (priceWatchInterval)
+func runPriceWatchScheduler(ctx context.Context, sender bot.BotSender, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			handlers.CheckPriceChanges(ctx, sender)
+		}
+	}
+}
+
+// runAdminSummaryScheduler sends the daily OVH summary to admins.
+// Unlike runGoodMorningScheduler it doesn't poll: it sleeps until the next
+// ADMIN_SUMMARY_TIME (handlers.NextRunDelay), sends, and computes the next delay.
+// The delay is recomputed after every run, so DST changes and a slow send
+// never shift later runs. As in runGoodMorningScheduler, the lastSent date
+// guards against sending twice on the same day (e.g., if the wall clock is
+// adjusted backwards and the timer fires just before the target minute).
+//
+// Parameters:
+//   - ctx: Cancelled on shutdown; the scheduler returns when it's done
+//   - sender: Bot sender for delivering messages
+//   - cfg: Application configuration (summary time, time zone and admins)
+func runAdminSummaryScheduler(ctx context.Context, sender bot.BotSender, cfg *config.Config) {
+	var lastSent string
+	for {
+		delay := handlers.NextRunDelay(time.Now(), cfg.AdminSummaryHour, cfg.AdminSummaryMinute, cfg.AdminSummaryLocation)
+		slog.Info("Next admin summary scheduled",
+			"at", time.Now().Add(delay).In(cfg.AdminSummaryLocation).Format(time.RFC3339),
+			"in", delay.Round(time.Second).String())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case now := <-timer.C:
+			today := now.In(cfg.AdminSummaryLocation).Format(time.DateOnly)
+			if lastSent == today {
+				continue
+			}
+			lastSent = today
+			handlers.SendAdminSummary(ctx, sender, cfg)
+		}
+	}
+}
+
+// notFoundHandler answers unknown paths with 404 and a small JSON body
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	// Explicitly ignore write error - nothing useful to do if it fails
+	_, _ = w.Write([]byte(`{"error":"not found"}` + "\n"))
+}
+
+// healthCheckHandler handles GET /healthz (and GET /, see ROOT_HEALTH_CHECK) health checks
+// Returns 200 OK with a JSON status document (see status.Report):
+// uptime, version/commit and when Telegram and OVH last answered successfully.
+// Cheap: only reads status.Default, never calls the upstreams.
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	// Only accept GET requests (health checks should be GET)
 	if r.Method != http.MethodGet {
@@ -142,16 +500,72 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Return 200 OK with simple message
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	// Explicitly ignore write error - nothing useful to do if health check write fails
-	_, _ = w.Write([]byte("OK"))
+	// Explicitly ignore encode error - nothing useful to do if health check write fails
+	_ = json.NewEncoder(w).Encode(status.Default.Snapshot(time.Now()))
 }
 
-// webhookHandler creates a handler for POST /webhook requests from Telegram
-// Uses closure to pass botAPI and cfg to the handler
+// webhookDuration tracks webhook processing time (body decode to handler return),
+// exported at GET /metrics as webhook_request_duration_seconds_bucket{le="..."}
+var webhookDuration = metrics.NewHistogram("webhook_request_duration_seconds",
+	"Webhook request processing time in seconds", metrics.DefaultDurationBuckets)
+
+// webhookUpdates counts updates received per bot (label "default" for a single BOT_TOKEN bot),
+// exported at GET /metrics as webhook_updates_total{bot="..."}
+var webhookUpdates = metrics.NewCounterVec("webhook_updates_total",
+	"Telegram updates received by the webhook", "bot")
+
+// newUpdateRouter wraps handlers.RouteUpdate with the update middlewares.
+// Shared by the webhook and polling, so both process updates the same way.
+//
+// A panic in any handler is logged instead of crashing, so the webhook still
+// answers 200 OK and Telegram doesn't retry the update forever.
+// The timeout wraps recovery (not the other way around): the router runs in
+// the timeout middleware's goroutine, where an unrecovered panic is fatal
+//
+// Parameters:
+//   - cfg: The bot's configuration (UPDATE_TIMEOUT)
+//
+// Returns:
+//   - middleware.UpdateHandler: Router to call once per update
+func newUpdateRouter(cfg *config.Config) middleware.UpdateHandler {
+	return middleware.TimeoutMiddleware(
+		middleware.RecoveryMiddleware(handlers.RouteUpdate),
+		cfg.UpdateTimeout, handlers.HandlerName)
+}
+
+// updateLogger returns the per-update logger: every log line while processing
+// an update carries the same update_id (Telegram's unique ID doubles as request ID),
+// plus user_id and chat_id.
+// On Cloud Run, the trace fields also group these lines with the request log
+// (only if GOOGLE_CLOUD_PROJECT is set and the trace header is valid).
+// Logs of BOT_TOKENS bots also carry the bot name.
+//
+// Parameters:
+//   - instance: Bot that received the update
+//   - update: The update being processed
+//   - traceHeader: X-Cloud-Trace-Context of the webhook request ("" when polling)
+//
+// Returns:
+//   - *slog.Logger: Logger to store in the update's context
+func updateLogger(instance botInstance, update tgbotapi.Update, traceHeader string) *slog.Logger {
+	baseLog := slog.Default()
+	if instance.name != "" {
+		baseLog = baseLog.With("bot", instance.name)
+	}
+	return logger.ForUpdate(logger.WithTrace(baseLog, traceHeader, instance.cfg.GoogleCloudProject), update)
+}
+
+// webhookHandler creates a handler for POST requests from Telegram (at the bot's cfg.WebhookPath)
+// Uses closure to pass the bot sender and cfg to the handler
 // Returns http.HandlerFunc which can be registered with http.HandleFunc
-func webhookHandler(botAPI *tgbotapi.BotAPI, cfg *config.Config) http.HandlerFunc {
+func webhookHandler(instance botInstance) http.HandlerFunc {
+	botAPI, cfg := instance.sender, instance.cfg
+
+	// Wrap router once (see newUpdateRouter)
+	routeUpdate := newUpdateRouter(cfg)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Only accept POST requests (Telegram sends POST)
 		if r.Method != http.MethodPost {
@@ -159,6 +573,9 @@ func webhookHandler(botAPI *tgbotapi.BotAPI, cfg *config.Config) http.HandlerFun
 			return
 		}
 
+		// Timing starts before decoding, so slow request bodies count too
+		start := time.Now()
+
 		// Parse JSON body into Update struct
 		// Update contains message, callback_query, etc.
 		var update tgbotapi.Update
@@ -174,10 +591,13 @@ func webhookHandler(botAPI *tgbotapi.BotAPI, cfg *config.Config) http.HandlerFun
 			return
 		}
 
+		// Per-update logger (see updateLogger), read by handlers with logger.FromContext(ctx)
+		log := updateLogger(instance, update, r.Header.Get(logger.TraceHeader))
+		ctx := logger.WithContext(r.Context(), log)
+
 		// Log the update (helpful for debugging)
-		// update.UpdateID is unique identifier for each update
-		slog.Info("Received update",
-			"update_id", update.UpdateID,
+		webhookUpdates.Inc(instance.label())
+		log.Info("Received update",
 			"has_message", update.Message != nil,
 			"has_callback", update.CallbackQuery != nil)
 
@@ -186,7 +606,22 @@ func webhookHandler(botAPI *tgbotapi.BotAPI, cfg *config.Config) http.HandlerFun
 		// and delegates to appropriate handler functions
 		// Router implementation: handlers/router.go
 		// Handler implementations: handlers/dice.go, handlers/start.go, handlers/help.go
-		handlers.RouteUpdate(botAPI, update, cfg)
+		// ctx derives from r.Context(): it is cancelled when the client disconnects
+		// or the server shuts down, which aborts in-flight OVH API calls
+		routeUpdate(ctx, botAPI, update, cfg)
+
+		// Record processing time (update_id comes from the per-update logger)
+		// Slow requests are warnings with slow_request=true, easy to alert on
+		duration := time.Since(start)
+		webhookDuration.Observe(duration.Seconds())
+		if cfg.SlowRequestThreshold > 0 && duration > cfg.SlowRequestThreshold {
+			log.Warn("Request processed",
+				"duration_ms", duration.Milliseconds(),
+				"slow_request", true)
+		} else {
+			log.Info("Request processed",
+				"duration_ms", duration.Milliseconds())
+		}
 
 		// ALWAYS return 200 OK to Telegram
 		// Even if processing failed, we don't want Telegram to retry
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/handlers"
+	"github.com/Alrem/run-tbot/status"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestMain disables the router's flood guard for the whole package:
+// tests send the same update from the same user, and -count=2 repeats them
+// within the guard's window (see handlers.SetFloodWindow).
+func TestMain(m *testing.M) {
+	handlers.SetFloodWindow(0)
+	os.Exit(m.Run())
+}
+
+// countingSender is a fake bot.BotSender that counts Send calls
+// Lets tests check that a webhook request actually reached the router
+type countingSender struct {
+	mu    sync.Mutex
+	sends int
+}
+
+func (s *countingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sends++
+	return tgbotapi.Message{}, nil
+}
+
+func (s *countingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// helpUpdate returns a minimal Telegram update with a /help command in a private chat
+// userID is used for both the sender and the chat. Tests use different IDs:
+// the router ignores an identical message from the same user within a second
+// (handlers.floodWindow), which would swallow updates shared between tests.
+func helpUpdate(userID int64) string {
+	return fmt.Sprintf(`{"update_id":1,"message":{"message_id":1,"from":{"id":%d,"first_name":"Test"},`+
+		`"chat":{"id":%d,"type":"private"},"date":0,"text":"/help",`+
+		`"entities":[{"type":"bot_command","offset":0,"length":5}]}}`, userID, userID)
+}
+
+// TestNewMux_WebhookPath tests that the webhook is mounted only at the configured path
+//
+// Cases:
+//   - Default config: /webhook routes updates
+//   - Custom WEBHOOK_PATH: custom path routes updates, /webhook falls through
+//     to the 404 catch-all and nothing is sent
+func TestNewMux_WebhookPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		webhookPath string
+		requestPath string
+		wantStatus  int
+		wantRouted  bool
+	}{
+		{name: "default path", webhookPath: "/webhook", requestPath: "/webhook", wantStatus: http.StatusOK, wantRouted: true},
+		{name: "custom path", webhookPath: "/hook-7f3a9c", requestPath: "/hook-7f3a9c", wantStatus: http.StatusOK, wantRouted: true},
+		{name: "default path with custom config", webhookPath: "/hook-7f3a9c", requestPath: "/webhook", wantStatus: http.StatusNotFound, wantRouted: false},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &countingSender{}
+			mux := newMux(sender, &config.Config{WebhookPath: tt.webhookPath})
+
+			req := httptest.NewRequest(http.MethodPost, tt.requestPath, strings.NewReader(helpUpdate(int64(100+i))))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("POST %s status = %d, want %d", tt.requestPath, rec.Code, tt.wantStatus)
+			}
+			if routed := sender.sends > 0; routed != tt.wantRouted {
+				t.Errorf("POST %s routed = %v, want %v", tt.requestPath, routed, tt.wantRouted)
+			}
+		})
+	}
+}
+
+// newWebhookServer serves newMux at the default webhook path over real HTTP
+// Unlike httptest.NewRecorder, requests go through net/http's server,
+// the way Telegram's requests do.
+func newWebhookServer(t *testing.T, sender bot.BotSender) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(newMux(sender, &config.Config{WebhookPath: "/webhook"}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestWebhookHandler_MalformedJSON tests that a body that isn't an update
+// is acknowledged with 200 (so Telegram doesn't retry it) and not routed
+func TestWebhookHandler_MalformedJSON(t *testing.T) {
+	sender := &countingSender{}
+	server := newWebhookServer(t, sender)
+
+	resp, err := http.Post(server.URL+"/webhook", "application/json", strings.NewReader("not json"))
+	if err != nil {
+		t.Fatalf("POST /webhook error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("POST /webhook with malformed JSON status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if sender.sends != 0 {
+		t.Errorf("malformed update sent %d messages, want 0", sender.sends)
+	}
+}
+
+// TestWebhookHandler_ValidUpdate tests that a minimal valid update gets 200 and is routed
+func TestWebhookHandler_ValidUpdate(t *testing.T) {
+	sender := &countingSender{}
+	server := newWebhookServer(t, sender)
+
+	resp, err := http.Post(server.URL+"/webhook", "application/json", strings.NewReader(helpUpdate(800)))
+	if err != nil {
+		t.Fatalf("POST /webhook error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("POST /webhook status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if sender.sends == 0 {
+		t.Errorf("valid /help update sent no message, want the router to reply")
+	}
+}
+
+// TestWebhookHandler_MethodNotAllowed tests that only POST is accepted at the webhook path
+func TestWebhookHandler_MethodNotAllowed(t *testing.T) {
+	server := newWebhookServer(t, &countingSender{})
+
+	resp, err := http.Get(server.URL + "/webhook")
+	if err != nil {
+		t.Fatalf("GET /webhook error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /webhook status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+// recordingHandler is a slog.Handler that keeps every record's attributes in memory
+// Attributes added with Logger.With are included, so tests see exactly
+// what a JSON handler would print for each line.
+type recordingHandler struct {
+	mu      *sync.Mutex
+	records *[]map[string]any
+	attrs   []slog.Attr
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{mu: &sync.Mutex{}, records: &[]map[string]any{}}
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := map[string]any{"msg": r.Message}
+	for _, a := range h.attrs {
+		fields[a.Key] = a.Value.Any()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fields[a.Key] = a.Value.Any()
+		return true
+	})
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.records = append(*h.records, fields)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// TestWebhookHandler_LogsShareUpdateID verifies that every log line written while
+// processing one update carries that update's update_id, user_id and chat_id
+func TestWebhookHandler_LogsShareUpdateID(t *testing.T) {
+	handler := newRecordingHandler()
+	oldLogger := slog.Default()
+	slog.SetDefault(slog.New(handler))
+	defer slog.SetDefault(oldLogger)
+
+	mux := newMux(&countingSender{}, &config.Config{WebhookPath: "/webhook"})
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(helpUpdate(1)))
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	records := *handler.records
+	// At least: "Received update", "Routing command", "/help" handler logs
+	if len(records) < 3 {
+		t.Fatalf("got %d log lines, want at least 3: %v", len(records), records)
+	}
+
+	for _, record := range records {
+		if record["update_id"] != int64(1) || record["user_id"] != int64(1) || record["chat_id"] != int64(1) {
+			t.Errorf("log line %q has update_id=%v user_id=%v chat_id=%v, want 1/1/1",
+				record["msg"], record["update_id"], record["user_id"], record["chat_id"])
+		}
+	}
+}
+
+// TestWebhookHandler_RequestTiming verifies the "Request processed" log line,
+// the slow_request flag and the duration histogram
+func TestWebhookHandler_RequestTiming(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		wantSlow  bool
+	}{
+		{name: "fast request", threshold: time.Hour, wantSlow: false},
+		{name: "slow request", threshold: time.Nanosecond, wantSlow: true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newRecordingHandler()
+			oldLogger := slog.Default()
+			slog.SetDefault(slog.New(handler))
+			defer slog.SetDefault(oldLogger)
+
+			before := webhookDuration.Count()
+			mux := newMux(&countingSender{}, &config.Config{WebhookPath: "/webhook", SlowRequestThreshold: tt.threshold})
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(helpUpdate(int64(500+i))))
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got := webhookDuration.Count() - before; got != 1 {
+				t.Errorf("webhook_request_duration_seconds count increased by %d, want 1", got)
+			}
+
+			var processed map[string]any
+			for _, record := range *handler.records {
+				if record["msg"] == "Request processed" {
+					processed = record
+				}
+			}
+			if processed == nil {
+				t.Fatalf("no \"Request processed\" log line: %v", *handler.records)
+			}
+			if _, ok := processed["duration_ms"]; !ok || processed["update_id"] != int64(1) {
+				t.Errorf("Request processed = %v, want duration_ms and update_id", processed)
+			}
+			if slow := processed["slow_request"] == true; slow != tt.wantSlow {
+				t.Errorf("slow_request = %v, want %v", slow, tt.wantSlow)
+			}
+		})
+	}
+}
+
+// TestNewMux_Pprof tests that profiling endpoints are off by default
+// and require the bearer token when enabled
+func TestNewMux_Pprof(t *testing.T) {
+	const token = "0123456789abcdef-test"
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		authHeader string
+		path       string
+		wantStatus int
+	}{
+		{name: "disabled", path: "/debug/pprof/", wantStatus: http.StatusNotFound},
+		{name: "disabled with token", authHeader: "Bearer " + token, path: "/debug/pprof/cmdline", wantStatus: http.StatusNotFound},
+		{name: "enabled without token", enabled: true, path: "/debug/pprof/", wantStatus: http.StatusUnauthorized},
+		{name: "enabled wrong token", enabled: true, authHeader: "Bearer wrong", path: "/debug/pprof/", wantStatus: http.StatusUnauthorized},
+		{name: "enabled token without Bearer", enabled: true, authHeader: token, path: "/debug/pprof/", wantStatus: http.StatusUnauthorized},
+		{name: "enabled valid token", enabled: true, authHeader: "Bearer " + token, path: "/debug/pprof/cmdline", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{WebhookPath: "/webhook", EnablePprof: tt.enabled, PprofToken: token}
+			mux := newMux(&countingSender{}, cfg)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestNewMux_HealthCheck tests the health check routes and the 404 catch-all
+//
+// Cases:
+//   - /healthz always answers GET with 200 and the JSON status document
+//   - / answers only while ROOT_HEALTH_CHECK is on
+//   - Unknown paths get 404 with a JSON error body
+//   - Health check rejects non-GET methods with 405
+func TestNewMux_HealthCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		rootHealth bool
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "healthz", method: http.MethodGet, path: "/healthz", wantStatus: http.StatusOK},
+		{name: "root with flag", rootHealth: true, method: http.MethodGet, path: "/", wantStatus: http.StatusOK},
+		{name: "root without flag", method: http.MethodGet, path: "/", wantStatus: http.StatusNotFound, wantBody: `{"error":"not found"}`},
+		{name: "unknown path", rootHealth: true, method: http.MethodGet, path: "/unknown", wantStatus: http.StatusNotFound, wantBody: `{"error":"not found"}`},
+		{name: "POST healthz", method: http.MethodPost, path: "/healthz", wantStatus: http.StatusMethodNotAllowed},
+		{name: "POST root with flag", rootHealth: true, method: http.MethodPost, path: "/", wantStatus: http.StatusMethodNotAllowed},
+		{name: "GET webhook", method: http.MethodGet, path: "/webhook", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{WebhookPath: "/webhook", RootHealthCheck: tt.rootHealth}
+			mux := newMux(&countingSender{}, cfg)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("%s %s body = %q, want %q", tt.method, tt.path, rec.Body.String(), tt.wantBody)
+			}
+			if tt.wantStatus == http.StatusNotFound || tt.wantStatus == http.StatusOK {
+				if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+					t.Errorf("%s %s Content-Type = %q, want application/json", tt.method, tt.path, ct)
+				}
+			}
+		})
+	}
+}
+
+// TestHealthCheck_ReportsActivity tests the health JSON schema and that the
+// Telegram timestamp updates after an update is handled through StatusSender
+func TestHealthCheck_ReportsActivity(t *testing.T) {
+	sender := bot.NewStatusSender(&countingSender{}, status.Default)
+	mux := newMux(sender, &config.Config{WebhookPath: "/webhook"})
+
+	getReport := func() status.Report {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		var report status.Report
+		decoder := json.NewDecoder(rec.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&report); err != nil {
+			t.Fatalf("GET /healthz body is not a status.Report: %v", err)
+		}
+		return report
+	}
+
+	report := getReport()
+	if report.Status != "ok" || report.Version == "" || report.Commit == "" || report.UptimeSeconds < 0 {
+		t.Errorf("GET /healthz = %+v, want status ok with version and commit", report)
+	}
+
+	// Simulate activity: the /help reply goes through StatusSender
+	before := time.Now().Truncate(time.Second)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(helpUpdate(300))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /webhook status = %d, want 200", rec.Code)
+	}
+
+	report = getReport()
+	if report.LastTelegramSuccess == nil || report.LastTelegramSuccess.Before(before) {
+		t.Errorf("last_telegram_success = %v, want >= %v", report.LastTelegramSuccess, before)
+	}
+}
+
+// TestNewRateLimiter tests that the default and webhook limits are separate
+func TestNewRateLimiter(t *testing.T) {
+	cfg := &config.Config{WebhookPath: "/webhook", RateLimit: 1, WebhookRateLimit: 5}
+	handler := newRateLimiter(newMux(&countingSender{}, cfg), cfg)
+
+	request := func(method, path string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(helpUpdate(400)))
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Burst is 2 * RATE_LIMIT
+	for range 2 {
+		if code := request(http.MethodGet, "/healthz"); code != http.StatusOK {
+			t.Fatalf("GET /healthz status = %d, want 200", code)
+		}
+	}
+	if code := request(http.MethodGet, "/healthz"); code != http.StatusTooManyRequests {
+		t.Errorf("GET /healthz over limit status = %d, want 429", code)
+	}
+
+	// Webhook has its own bucket
+	if code := request(http.MethodPost, "/webhook"); code != http.StatusOK {
+		t.Errorf("POST /webhook status = %d, want 200", code)
+	}
+}
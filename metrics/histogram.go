@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDurationBuckets are upper bounds (seconds) for request duration histograms
+// Webhook updates usually finish in milliseconds; OVH lookups take seconds
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Histogram counts observations in cumulative buckets, Prometheus style
+//
+// Each bucket counts observations <= its upper bound ("le"), plus an implicit
+// +Inf bucket equal to the total count. Sum and count allow computing the mean,
+// buckets allow estimating quantiles (histogram_quantile in PromQL).
+type Histogram struct {
+	name    string
+	help    string
+	buckets []float64 // sorted upper bounds, without +Inf
+
+	mu     sync.Mutex
+	counts []uint64 // counts[i] = observations in (buckets[i-1], buckets[i]], last = above all bounds
+	sum    float64
+	count  uint64
+}
+
+// NewHistogram creates a histogram and registers it in Default
+//
+// Parameters:
+//   - name: Metric name (by convention ends in a unit, e.g., _seconds)
+//   - help: Description shown in the # HELP line
+//   - buckets: Upper bounds (e.g., DefaultDurationBuckets); sorted by NewHistogram,
+//     +Inf is always added and must not be listed
+//
+// Returns:
+//   - *Histogram: Ready-to-use histogram
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := newHistogram(name, help, buckets)
+	Default.register(h)
+	return h
+}
+
+// newHistogram creates an unregistered histogram (used by tests)
+func newHistogram(name, help string, buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{name: name, help: help, buckets: sorted, counts: make([]uint64, len(sorted)+1)}
+}
+
+// Observe records one value (e.g., a duration in seconds)
+func (h *Histogram) Observe(value float64) {
+	// First bucket whose upper bound is >= value (len(buckets) = +Inf bucket)
+	i := sort.SearchFloat64s(h.buckets, value)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.sum += value
+	h.count++
+}
+
+// Count returns the number of observations
+// Mainly for tests: compare the value before and after an action
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) metricName() string {
+	return h.name
+}
+
+// writeTo writes the histogram in the text format:
+//
+//	# HELP name help
+//	# TYPE name histogram
+//	name_bucket{le="0.05"} 3
+//	name_bucket{le="+Inf"} 4
+//	name_sum 1.27
+//	name_count 4
+func (h *Histogram) writeTo(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", h.name, h.help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", h.name)
+	h.writeSeries(w, h.name, "")
+}
+
+// writeSeries writes the bucket, sum and count lines of one series
+//
+// Parameters:
+//   - w: Destination
+//   - name: Metric name (a HistogramVec's series share its name)
+//   - labels: Label pairs without braces (`endpoint="catalog"`), "" for none;
+//     "le" is appended to them on bucket lines
+func (h *Histogram) writeSeries(w io.Writer, name, labels string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	le := "{le="
+	braced := ""
+	if labels != "" {
+		le = "{" + labels + ",le="
+		braced = "{" + labels + "}"
+	}
+
+	// Bucket counts are cumulative in the text format
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket%s\"%s\"} %d\n", name, le, formatBound(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket%s\"+Inf\"} %d\n", name, le, h.count)
+	fmt.Fprintf(w, "%s_sum%s %g\n", name, braced, h.sum)
+	fmt.Fprintf(w, "%s_count%s %d\n", name, braced, h.count)
+}
+
+// HistogramVec is a family of histograms that differ only by label values
+// (e.g., ovh_request_duration_seconds{endpoint="catalog"})
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*Histogram // key: label values joined by labelSeparator
+}
+
+// NewHistogramVec creates a histogram family and registers it in Default
+//
+// Parameters:
+//   - name: Metric name (by convention ends in a unit, e.g., _seconds)
+//   - help: Description shown in the # HELP line
+//   - buckets: Upper bounds shared by every series (see NewHistogram)
+//   - labels: Label names, in the order values are passed to Observe
+//
+// Returns:
+//   - *HistogramVec: Ready-to-use histogram family
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := newHistogramVec(name, help, buckets, labels...)
+	Default.register(h)
+	return h
+}
+
+// newHistogramVec creates an unregistered histogram family (used by tests)
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*Histogram)}
+}
+
+// Observe records one value in the series with the given label values
+//
+// Parameters:
+//   - value: Observed value (e.g., a duration in seconds)
+//   - labelValues: One value per label name, in the same order
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.with(labelValues).Observe(value)
+}
+
+// Count returns the number of observations of one series (0 if never observed)
+// Mainly for tests: compare the value before and after an action
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	return h.with(labelValues).Count()
+}
+
+// with returns the series for labelValues, creating it on first use
+func (h *HistogramVec) with(labelValues []string) *Histogram {
+	if len(labelValues) != len(h.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", h.name, len(h.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, labelSeparator)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = newHistogram(h.name, h.help, h.buckets)
+		h.series[key] = series
+	}
+	return series
+}
+
+func (h *HistogramVec) metricName() string {
+	return h.name
+}
+
+// writeTo writes the family in the text format, one block of lines per series
+// (sorted by label values, like CounterVec)
+func (h *HistogramVec) writeTo(w io.Writer) {
+	h.mu.Lock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	series := make(map[string]*Histogram, len(h.series))
+	maps.Copy(series, h.series)
+	h.mu.Unlock()
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "# HELP %s %s\n", h.name, h.help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", h.name)
+	for _, key := range keys {
+		labels := formatLabels(h.labels, strings.Split(key, labelSeparator))
+		series[key].writeSeries(w, h.name, strings.TrimSuffix(strings.TrimPrefix(labels, "{"), "}"))
+	}
+}
+
+// formatBound renders a bucket bound as Prometheus clients do ("0.05", "1", "30")
+func formatBound(bound float64) string {
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
@@ -0,0 +1,204 @@
+// Package metrics implements labeled counters exported in the Prometheus
+// text format, using only the standard library.
+//
+// Why not the official Prometheus client?
+//   - The bot needs a handful of counters, not the full client (and its dependencies)
+//   - The text exposition format is simple: one "name{labels} value" line per series
+//
+// Usage:
+//
+//	var invocations = metrics.NewCounterVec("handler_invocations_total",
+//		"Handler invocations by feature and result", "feature", "result")
+//
+//	invocations.Inc("ovh", "success")
+//
+// Exposed by main.go at GET /metrics (see Handler).
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// collector is anything the registry can write in the text format
+type collector interface {
+	writeTo(w io.Writer)
+	metricName() string
+}
+
+// Registry holds all metrics exposed on one endpoint
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default is the registry used by NewCounterVec and Handler
+var Default = NewRegistry()
+
+// register adds a collector; panics on duplicate names (a programming error,
+// caught at startup because metrics are package-level vars)
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.collectors {
+		if existing.metricName() == c.metricName() {
+			panic("metrics: duplicate metric " + c.metricName())
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+// Metrics are sorted by name, so the output is stable
+//
+// Parameters:
+//   - w: Destination (usually the HTTP response)
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool {
+		return collectors[i].metricName() < collectors[j].metricName()
+	})
+	for _, c := range collectors {
+		c.writeTo(w)
+	}
+}
+
+// Handler serves the Default registry (GET /metrics)
+//
+// Returns:
+//   - http.Handler: Handler writing text/plain; version=0.0.4 (Prometheus format)
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Default.WriteText(w)
+	})
+}
+
+// CounterVec is a family of counters that differ only by label values
+// (e.g., handler_invocations_total{feature="ovh",result="error"})
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64 // key: label values joined by labelSeparator
+}
+
+// labelSeparator joins label values into a map key
+// A byte that can't appear in valid UTF-8 text, so keys never collide
+const labelSeparator = "\xff"
+
+// NewCounterVec creates a counter family and registers it in Default
+//
+// Parameters:
+//   - name: Metric name (by convention ends in _total)
+//   - help: Description shown in the # HELP line
+//   - labels: Label names, in the order values are passed to Inc
+//
+// Returns:
+//   - *CounterVec: Ready-to-use counter family
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := newCounterVec(name, help, labels...)
+	Default.register(c)
+	return c
+}
+
+// newCounterVec creates an unregistered counter family (used by tests)
+func newCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+}
+
+// Inc adds 1 to the counter with the given label values
+//
+// Parameters:
+//   - labelValues: One value per label name, in the same order
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds delta (must not be negative: counters only go up) to a counter
+//
+// Parameters:
+//   - delta: Amount to add
+//   - labelValues: One value per label name, in the same order
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labels), len(labelValues)))
+	}
+	if delta < 0 {
+		panic("metrics: counter " + c.name + " cannot decrease")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[strings.Join(labelValues, labelSeparator)] += delta
+}
+
+// Value returns the current value of one counter (0 if never incremented)
+// Mainly for tests: compare the value before and after an action
+//
+// Parameters:
+//   - labelValues: One value per label name, in the same order
+//
+// Returns:
+//   - float64: Current counter value
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[strings.Join(labelValues, labelSeparator)]
+}
+
+func (c *CounterVec) metricName() string {
+	return c.name
+}
+
+// writeTo writes the counter family in the text format:
+//
+//	# HELP name help
+//	# TYPE name counter
+//	name{label="value",...} 3
+func (c *CounterVec) writeTo(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", c.name, c.help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", c.name)
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s%s %g\n", c.name, formatLabels(c.labels, strings.Split(key, labelSeparator)), c.values[key])
+	}
+}
+
+// formatLabels renders {name="value",...} with values escaped per the text format
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCounterVec tests incrementing and reading labeled counters
+func TestCounterVec(t *testing.T) {
+	c := newCounterVec("test_total", "Test counter", "feature", "result")
+
+	c.Inc("ovh", "success")
+	c.Inc("ovh", "success")
+	c.Inc("ovh", "error")
+	c.Add(2.5, "dice", "success")
+
+	tests := []struct {
+		labels []string
+		want   float64
+	}{
+		{[]string{"ovh", "success"}, 2},
+		{[]string{"ovh", "error"}, 1},
+		{[]string{"dice", "success"}, 2.5},
+		{[]string{"ovh", "unauthorized"}, 0},
+	}
+	for _, tt := range tests {
+		if got := c.Value(tt.labels...); got != tt.want {
+			t.Errorf("Value(%v) = %g, want %g", tt.labels, got, tt.want)
+		}
+	}
+}
+
+// TestCounterVec_WrongLabelCount verifies that a wrong number of label values panics
+func TestCounterVec_WrongLabelCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Inc with missing label value did not panic")
+		}
+	}()
+	newCounterVec("test_total", "Test counter", "feature", "result").Inc("ovh")
+}
+
+// TestRegistry_WriteText tests the Prometheus text exposition output
+//
+// Checks:
+//   - HELP and TYPE lines per metric
+//   - Series sorted, label values escaped
+//   - Metrics sorted by name
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	b := newCounterVec("b_total", "Second", "result")
+	a := newCounterVec("a_total", "First")
+	r.register(b)
+	r.register(a)
+
+	b.Inc("success")
+	b.Inc(`say "hi"`)
+	a.Inc()
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+
+	want := `# HELP a_total First
+# TYPE a_total counter
+a_total 1
+# HELP b_total Second
+# TYPE b_total counter
+b_total{result="say \"hi\""} 1
+b_total{result="success"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestHandler tests the /metrics HTTP handler
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+}
+
+// TestHistogram_WriteText tests bucketing and the histogram text format
+//
+// Checks:
+//   - Bucket counts are cumulative, +Inf equals the total count
+//   - A value equal to a bound falls into that bucket (le = "less or equal")
+//   - Buckets are sorted even if passed unsorted
+func TestHistogram_WriteText(t *testing.T) {
+	r := NewRegistry()
+	h := newHistogram("request_seconds", "Request duration", []float64{1, 0.1})
+	r.register(h)
+
+	for _, v := range []float64{0.05, 0.1, 0.5, 2} {
+		h.Observe(v)
+	}
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+
+	want := `# HELP request_seconds Request duration
+# TYPE request_seconds histogram
+request_seconds_bucket{le="0.1"} 2
+request_seconds_bucket{le="1"} 3
+request_seconds_bucket{le="+Inf"} 4
+request_seconds_sum 2.65
+request_seconds_count 4
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant:\n%s", got, want)
+	}
+	if got := h.Count(); got != 4 {
+		t.Errorf("Count() = %d, want 4", got)
+	}
+}
+
+// TestHistogramVec_WriteText tests the text format of a labeled histogram family
+//
+// Cases:
+//   - One HELP/TYPE header, then the series sorted by label values
+//   - Labels come before "le" on bucket lines and alone on sum/count lines
+//   - Count reads one series
+func TestHistogramVec_WriteText(t *testing.T) {
+	r := NewRegistry()
+	h := newHistogramVec("fetch_seconds", "Fetch duration", []float64{1}, "endpoint")
+	r.register(h)
+
+	h.Observe(2, "catalog")
+	h.Observe(0.5, "availabilities")
+	h.Observe(0.25, "availabilities")
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+
+	want := `# HELP fetch_seconds Fetch duration
+# TYPE fetch_seconds histogram
+fetch_seconds_bucket{endpoint="availabilities",le="1"} 2
+fetch_seconds_bucket{endpoint="availabilities",le="+Inf"} 2
+fetch_seconds_sum{endpoint="availabilities"} 0.75
+fetch_seconds_count{endpoint="availabilities"} 2
+fetch_seconds_bucket{endpoint="catalog",le="1"} 0
+fetch_seconds_bucket{endpoint="catalog",le="+Inf"} 1
+fetch_seconds_sum{endpoint="catalog"} 2
+fetch_seconds_count{endpoint="catalog"} 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteText() =\n%s\nwant:\n%s", got, want)
+	}
+	if got := h.Count("availabilities"); got != 2 {
+		t.Errorf("Count(availabilities) = %d, want 2", got)
+	}
+}
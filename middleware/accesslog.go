@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// secretTokenHeader is the header Telegram sets when setWebhook was called
+// with a secret_token; only its presence is logged, never the value
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// AccessLogOptions configures AccessLog
+type AccessLogOptions struct {
+	// SkipPaths are not logged when they succeed (status < 400)
+	// Used for the health check: Cloud Run probes it constantly
+	SkipPaths []string
+
+	// PathLabels replaces secret paths with a label in the log
+	// (e.g., WEBHOOK_PATH -> "<webhook>"), so the log never reveals them
+	PathLabels map[string]string
+}
+
+// AccessLog wraps an HTTP handler and logs one structured entry per request.
+//
+// Logged fields:
+//   - method, path, status, bytes (response body size), duration_ms
+//   - remote_addr: client address (on Cloud Run: Google's front end)
+//   - secret_token: whether the Telegram secret-token header was present
+//
+// Level depends on status: 5xx = Error, 4xx = Warn, otherwise Info,
+// so 405s hitting the endpoints stand out in Cloud Logging.
+//
+// Unlike RecoveryMiddleware, this wraps http.Handler (the whole mux),
+// not an UpdateHandler: it sees every request, including rejected ones.
+//
+// Parameters:
+//   - next: Handler to wrap (usually the *http.ServeMux from newMux)
+//   - opts: Paths to skip or relabel
+//
+// Returns:
+//   - http.Handler: Handler that logs after next returns
+func AccessLog(next http.Handler, opts AccessLogOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		status := rec.statusCode()
+		if status < http.StatusBadRequest && slices.Contains(opts.SkipPaths, r.URL.Path) {
+			return
+		}
+
+		path := r.URL.Path
+		if label, ok := opts.PathLabels[path]; ok {
+			path = label
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		slog.Log(r.Context(), level, "HTTP request",
+			"method", r.Method,
+			"path", path,
+			"status", status,
+			"bytes", rec.bytes,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote_addr", r.RemoteAddr,
+			"secret_token", r.Header.Get(secretTokenHeader) != "")
+	})
+}
+
+// statusRecorder is an http.ResponseWriter that remembers the status code
+// and counts written bytes. http.ResponseWriter has no getter for either,
+// so we wrap it and intercept WriteHeader and Write.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// WriteHeader records the status code before passing it on
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts bytes; a Write without WriteHeader means 200 OK
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the original writer (Flush, deadlines)
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode returns the recorded status
+// A handler that writes nothing is answered with 200 OK by net/http
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
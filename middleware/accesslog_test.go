@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAccessLog verifies one log entry per request with the captured status.
+//
+// Cases:
+//   - 200 via plain Write (implicit status), with byte count
+//   - 405 via http.Error, logged at WARN
+//   - Successful health check is skipped, a failing one is logged
+//   - Secret path is replaced by its label, token presence is a boolean
+func TestAccessLog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/hook-secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write([]byte("done"))
+	})
+
+	handler := AccessLog(mux, AccessLogOptions{
+		SkipPaths:  []string{"/"},
+		PathLabels: map[string]string{"/hook-secret": "<webhook>"},
+	})
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		secretToken string
+		wantLogged  bool
+		wantStatus  int
+		wantBytes   int
+		wantLevel   string
+		wantPath    string
+		wantToken   bool
+	}{
+		{
+			name: "webhook 200", method: http.MethodPost, path: "/hook-secret", secretToken: "s3cr3t",
+			wantLogged: true, wantStatus: 200, wantBytes: 4, wantLevel: "INFO", wantPath: "<webhook>", wantToken: true,
+		},
+		{
+			name: "webhook 405", method: http.MethodGet, path: "/hook-secret",
+			wantLogged: true, wantStatus: 405, wantBytes: len("Method not allowed\n"), wantLevel: "WARN", wantPath: "<webhook>",
+		},
+		{
+			name: "health check skipped", method: http.MethodGet, path: "/",
+			wantLogged: false,
+		},
+		{
+			name: "health check 405 logged", method: http.MethodPost, path: "/",
+			wantLogged: true, wantStatus: 405, wantBytes: len("Method not allowed\n"), wantLevel: "WARN", wantPath: "/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Capture logs
+			var buf bytes.Buffer
+			oldLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+			defer slog.SetDefault(oldLogger)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.secretToken != "" {
+				req.Header.Set(secretTokenHeader, tt.secretToken)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if !tt.wantLogged {
+				if buf.Len() != 0 {
+					t.Errorf("unexpected log output: %s", buf.String())
+				}
+				return
+			}
+
+			var entry struct {
+				Level       string `json:"level"`
+				Msg         string `json:"msg"`
+				Method      string `json:"method"`
+				Path        string `json:"path"`
+				Status      int    `json:"status"`
+				Bytes       int    `json:"bytes"`
+				SecretToken bool   `json:"secret_token"`
+				RemoteAddr  string `json:"remote_addr"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("log is not one JSON entry: %v\n%s", err, buf.String())
+			}
+
+			if entry.Status != tt.wantStatus || rr.Code != tt.wantStatus {
+				t.Errorf("logged status = %d, response = %d, want %d", entry.Status, rr.Code, tt.wantStatus)
+			}
+			if entry.Bytes != tt.wantBytes {
+				t.Errorf("bytes = %d, want %d", entry.Bytes, tt.wantBytes)
+			}
+			if entry.Level != tt.wantLevel {
+				t.Errorf("level = %q, want %q", entry.Level, tt.wantLevel)
+			}
+			if entry.Path != tt.wantPath || entry.Method != tt.method {
+				t.Errorf("logged %s %s, want %s %s", entry.Method, entry.Path, tt.method, tt.wantPath)
+			}
+			if entry.SecretToken != tt.wantToken {
+				t.Errorf("secret_token = %v, want %v", entry.SecretToken, tt.wantToken)
+			}
+			if entry.RemoteAddr == "" {
+				t.Errorf("remote_addr missing")
+			}
+			if bytes.Contains(buf.Bytes(), []byte("s3cr3t")) || bytes.Contains(buf.Bytes(), []byte("/hook-secret")) {
+				t.Errorf("log leaks a secret: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// RequireBearerToken wraps an HTTP handler so it only runs for requests
+// with the header "Authorization: Bearer <token>".
+// Other requests get 401 Unauthorized and never reach next.
+//
+// Why subtle.ConstantTimeCompare?
+//   - A plain == stops at the first different byte
+//   - Response times then leak how much of a guess was right
+//   - Constant-time comparison takes the same time for any guess
+//
+// Parameters:
+//   - token: Expected token (must not be empty, see config PPROF_TOKEN)
+//   - next: Protected handler
+//
+// Returns:
+//   - http.Handler: Handler that checks the token first
+func RequireBearerToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
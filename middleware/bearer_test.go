@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRequireBearerToken tests the Authorization header check
+func TestRequireBearerToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{name: "valid", token: "s3cr3t", header: "Bearer s3cr3t", wantStatus: http.StatusNoContent},
+		{name: "missing header", token: "s3cr3t", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "s3cr3t", header: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", token: "s3cr3t", header: "Basic s3cr3t", wantStatus: http.StatusUnauthorized},
+		{name: "empty configured token", token: "", header: "Bearer ", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			RequireBearerToken(tt.token, next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Rate tokens per second are added, up to Burst
+// Each request takes one token; an empty bucket means 429.
+// A zero Rate disables limiting for that limit.
+type Limit struct {
+	Rate  float64 // Sustained requests per second
+	Burst int     // Requests allowed at once after being idle
+}
+
+// RateLimitOptions configures RateLimit
+type RateLimitOptions struct {
+	// Default applies to every path without an entry in PathLimits
+	Default Limit
+
+	// PathLimits gives exact paths their own bucket and limit
+	// Used for the webhook: Telegram legitimately sends bursts of updates
+	PathLimits map[string]Limit
+
+	// IdleTTL is how long an IP's buckets are kept after its last request
+	// (default 10 minutes). Should be at least Burst/Rate, so evicting
+	// a bucket never hands out more tokens than waiting would have.
+	IdleTTL time.Duration
+
+	// MaxBuckets caps the number of buckets kept (default 10000)
+	// When full, idle buckets are evicted first, then the least recently used.
+	MaxBuckets int
+
+	// now returns the current time (tests replace it to simulate bursts)
+	now func() time.Time
+}
+
+// Defaults used when RateLimitOptions.IdleTTL or MaxBuckets is zero
+const (
+	defaultIdleTTL    = 10 * time.Minute
+	defaultMaxBuckets = 10000
+)
+
+// RateLimit wraps an HTTP handler with a per-IP token bucket limiter.
+//
+// Why?
+//   - The webhook and health endpoints are on the public internet
+//   - A scanner hammering them costs CPU and log volume for nothing
+//
+// Behavior:
+//   - Client IP is the last X-Forwarded-For hop, the one Google's front end
+//     appends in front of Cloud Run (see clientIP), falling back to RemoteAddr
+//   - Each IP gets one bucket per limit class (PathLimits path or default)
+//   - Over the limit: 429 Too Many Requests with Retry-After (seconds)
+//   - Buckets idle longer than IdleTTL are evicted, and there are never more
+//     than MaxBuckets, so memory stays bounded
+//
+// Note: the limiter stops naive scanners, not a DDoS from many real IPs.
+//
+// Parameters:
+//   - next: Handler to wrap (usually the *http.ServeMux from newMux)
+//   - opts: Limits for the default class and per-path overrides
+//
+// Returns:
+//   - http.Handler: Handler that rejects requests over the limit
+func RateLimit(next http.Handler, opts RateLimitOptions) http.Handler {
+	limiter := newRateLimiter(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, class := opts.Default, ""
+		if pathLimit, ok := opts.PathLimits[r.URL.Path]; ok {
+			limit, class = pathLimit, r.URL.Path
+		}
+
+		if limit.Rate <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := clientIP(r)
+		allowed, retryAfter := limiter.allow(ip+"|"+class, limit)
+		if !allowed {
+			// Path is not logged: it may be the secret webhook path
+			slog.Warn("Rate limit exceeded",
+				"client_ip", ip,
+				"retry_after_seconds", retryAfter)
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bucket is one IP's token bucket for one limit class
+type bucket struct {
+	tokens float64
+	last   time.Time // Last refill (= last request)
+}
+
+// rateLimiter holds all buckets, keyed by "ip|class"
+type rateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	idleTTL    time.Duration
+	maxBuckets int
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// newRateLimiter creates a limiter from options (filling in defaults)
+func newRateLimiter(opts RateLimitOptions) *rateLimiter {
+	l := &rateLimiter{
+		buckets:    make(map[string]*bucket),
+		idleTTL:    opts.IdleTTL,
+		maxBuckets: opts.MaxBuckets,
+		now:        opts.now,
+	}
+	if l.idleTTL <= 0 {
+		l.idleTTL = defaultIdleTTL
+	}
+	if l.maxBuckets <= 0 {
+		l.maxBuckets = defaultMaxBuckets
+	}
+	if l.now == nil {
+		l.now = time.Now
+	}
+	l.lastSweep = l.now()
+	return l
+}
+
+// allow takes one token from key's bucket
+//
+// Parameters:
+//   - key: Bucket key ("ip|class")
+//   - limit: Rate and burst for this bucket
+//
+// Returns:
+//   - bool: true if the request may proceed
+//   - int: Seconds until a token is available (only meaningful when false)
+func (l *rateLimiter) allow(key string, limit Limit) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	burst := float64(max(limit.Burst, 1))
+	b, ok := l.buckets[key]
+	if !ok {
+		// New IP starts with a full bucket
+		if len(l.buckets) >= l.maxBuckets {
+			l.makeRoom(now)
+		}
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+
+	// Refill for the time since the last request, capped at burst
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	// Time until the bucket holds one full token again (at least 1s for the header)
+	wait := (1 - b.tokens) / limit.Rate
+	return false, max(1, int(math.Ceil(wait)))
+}
+
+// sweep evicts buckets idle for longer than idleTTL
+// Runs at most once per idleTTL, so the cost is amortized across requests.
+// Must be called with l.mu held.
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.idleTTL {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+// makeRoom frees one bucket slot when the limiter is full
+// Evicts idle buckets first (a sweep ahead of schedule); if every bucket is
+// still active, the least recently used one goes. That costs a scan of all
+// buckets, but only while the limiter is full.
+// Must be called with l.mu held.
+func (l *rateLimiter) makeRoom(now time.Time) {
+	l.lastSweep = time.Time{}
+	l.sweep(now)
+	if len(l.buckets) < l.maxBuckets {
+		return
+	}
+
+	oldestKey := ""
+	var oldest time.Time
+	for key, b := range l.buckets {
+		if oldestKey == "" || b.last.Before(oldest) {
+			oldestKey, oldest = key, b.last
+		}
+	}
+	delete(l.buckets, oldestKey)
+}
+
+// clientIP returns the client's IP address for rate limiting
+//
+// Order:
+//  1. Last entry of X-Forwarded-For, if it is an IP address: the proxy in
+//     front of us (Google's front end on Cloud Run) appends the address it
+//     saw. Earlier entries come from the client and can be made up, so a
+//     client could otherwise get a fresh bucket with every request.
+//  2. Host part of RemoteAddr (direct connections, local development)
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		last := forwarded[strings.LastIndex(forwarded, ",")+1:]
+		if ip := net.ParseIP(strings.TrimSpace(last)); ip != nil {
+			return ip.String()
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
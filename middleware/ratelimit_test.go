@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for RateLimitOptions.now
+type fakeClock struct {
+	t time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// okHandler answers every request with 200 OK
+func okHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte("OK"))
+}
+
+// serve sends one GET request from ip to path and returns the recorder
+// ip is the last X-Forwarded-For hop, after a made-up one from the client.
+func serve(handler http.Handler, ip, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-Forwarded-For", "198.51.100.66, "+ip)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestRateLimit_Burst tests a simulated burst from one IP
+//
+// Flow:
+//   - Burst of 3 passes, 4th request gets 429 with Retry-After
+//   - Another IP is unaffected
+//   - After one second (rate 1/s) one more request passes
+func TestRateLimit_Burst(t *testing.T) {
+	clock := newFakeClock()
+	handler := RateLimit(http.HandlerFunc(okHandler), RateLimitOptions{
+		Default: Limit{Rate: 1, Burst: 3},
+		now:     clock.now,
+	})
+
+	for i := range 3 {
+		if rec := serve(handler, "203.0.113.1", "/healthz"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, rec.Code)
+		}
+	}
+
+	rec := serve(handler, "203.0.113.1", "/healthz")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("Retry-After = %q, want %q", got, "1")
+	}
+
+	if rec := serve(handler, "203.0.113.2", "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("other IP status = %d, want 200", rec.Code)
+	}
+
+	clock.advance(time.Second)
+	if rec := serve(handler, "203.0.113.1", "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("after refill status = %d, want 200", rec.Code)
+	}
+	if rec := serve(handler, "203.0.113.1", "/healthz"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("second request after refill status = %d, want 429", rec.Code)
+	}
+}
+
+// TestRateLimit_PathLimits tests that the webhook has its own, larger bucket
+func TestRateLimit_PathLimits(t *testing.T) {
+	clock := newFakeClock()
+	handler := RateLimit(http.HandlerFunc(okHandler), RateLimitOptions{
+		Default:    Limit{Rate: 1, Burst: 1},
+		PathLimits: map[string]Limit{"/webhook": {Rate: 10, Burst: 20}},
+		now:        clock.now,
+	})
+
+	// Exhaust the default bucket
+	serve(handler, "149.154.160.1", "/")
+	if rec := serve(handler, "149.154.160.1", "/"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("default bucket status = %d, want 429", rec.Code)
+	}
+
+	// Webhook burst from the same IP still passes
+	for i := range 20 {
+		if rec := serve(handler, "149.154.160.1", "/webhook"); rec.Code != http.StatusOK {
+			t.Fatalf("webhook request %d status = %d, want 200", i+1, rec.Code)
+		}
+	}
+	if rec := serve(handler, "149.154.160.1", "/webhook"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("webhook over burst status = %d, want 429", rec.Code)
+	}
+}
+
+// TestRateLimit_Disabled tests that a zero rate never limits
+func TestRateLimit_Disabled(t *testing.T) {
+	handler := RateLimit(http.HandlerFunc(okHandler), RateLimitOptions{})
+
+	for i := range 100 {
+		if rec := serve(handler, "203.0.113.1", "/"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i+1, rec.Code)
+		}
+	}
+}
+
+// TestRateLimiter_EvictsIdleIPs tests that buckets of idle IPs are removed
+func TestRateLimiter_EvictsIdleIPs(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newRateLimiter(RateLimitOptions{IdleTTL: time.Minute, now: clock.now})
+	limit := Limit{Rate: 1, Burst: 1}
+
+	for _, ip := range []string{"a", "b", "c"} {
+		limiter.allow(ip+"|", limit)
+	}
+	if len(limiter.buckets) != 3 {
+		t.Fatalf("buckets = %d, want 3", len(limiter.buckets))
+	}
+
+	// "c" stays active, the others go idle
+	clock.advance(40 * time.Second)
+	limiter.allow("c|", limit)
+	clock.advance(30 * time.Second)
+	limiter.allow("d|", limit)
+
+	if len(limiter.buckets) != 2 {
+		t.Errorf("buckets after sweep = %d, want 2 (c and d)", len(limiter.buckets))
+	}
+	for _, key := range []string{"a|", "b|"} {
+		if _, ok := limiter.buckets[key]; ok {
+			t.Errorf("idle bucket %q not evicted", key)
+		}
+	}
+}
+
+// TestRateLimiter_MaxBuckets tests the bucket cap
+//
+// Flow:
+//   - Cap of 3: a 4th active IP evicts the least recently used bucket
+//   - Idle buckets are evicted before active ones when the cap is reached
+func TestRateLimiter_MaxBuckets(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newRateLimiter(RateLimitOptions{IdleTTL: time.Minute, MaxBuckets: 3, now: clock.now})
+	limit := Limit{Rate: 1, Burst: 1}
+
+	for _, ip := range []string{"a", "b", "c"} {
+		limiter.allow(ip+"|", limit)
+		clock.advance(time.Second)
+	}
+	limiter.allow("a|", limit) // "b" is now the least recently used
+	limiter.allow("d|", limit)
+
+	if len(limiter.buckets) != 3 {
+		t.Errorf("buckets = %d, want 3 (the cap)", len(limiter.buckets))
+	}
+	if _, ok := limiter.buckets["b|"]; ok {
+		t.Error("least recently used bucket b not evicted")
+	}
+
+	// Everything but "e" goes idle: reaching the cap again sweeps them all
+	clock.advance(30 * time.Second)
+	limiter.allow("e|", limit)
+	clock.advance(40 * time.Second)
+	limiter.allow("f|", limit)
+	if _, ok := limiter.buckets["e|"]; !ok || len(limiter.buckets) != 2 {
+		t.Errorf("buckets = %v, want e and f (idle ones swept first)", limiter.buckets)
+	}
+
+	// Many made-up IPs never grow the map past the cap
+	for i := range 100 {
+		limiter.allow(strconv.Itoa(i)+"|", limit)
+	}
+	if len(limiter.buckets) > 3 {
+		t.Errorf("buckets = %d, want at most 3", len(limiter.buckets))
+	}
+}
+
+// TestClientIP tests X-Forwarded-For handling (last hop, must be an IP)
+// and the RemoteAddr fallback
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwarded  string
+		remoteAddr string
+		expected   string
+	}{
+		{name: "last hop", forwarded: "198.51.100.66, 203.0.113.7", remoteAddr: "10.0.0.2:1234", expected: "203.0.113.7"},
+		{name: "spoofed first hops ignored", forwarded: "1.2.3.4, 5.6.7.8, 203.0.113.7", remoteAddr: "10.0.0.2:1234", expected: "203.0.113.7"},
+		{name: "single hop with spaces", forwarded: "  203.0.113.7 ", remoteAddr: "10.0.0.2:1234", expected: "203.0.113.7"},
+		{name: "IPv6 hop", forwarded: "2001:DB8::7", remoteAddr: "10.0.0.2:1234", expected: "2001:db8::7"},
+		{name: "no header", remoteAddr: "192.0.2.1:5678", expected: "192.0.2.1"},
+		{name: "empty last hop", forwarded: "203.0.113.7, ", remoteAddr: "192.0.2.1:5678", expected: "192.0.2.1"},
+		{name: "not an IP", forwarded: "random-1234", remoteAddr: "192.0.2.1:5678", expected: "192.0.2.1"},
+		{name: "IP with port", forwarded: "203.0.113.7:80", remoteAddr: "192.0.2.1:5678", expected: "192.0.2.1"},
+		{name: "IPv6 remote", remoteAddr: "[2001:db8::1]:443", expected: "2001:db8::1"},
+		{name: "remote without port", remoteAddr: "192.0.2.1", expected: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := clientIP(req); got != tt.expected {
+				t.Errorf("clientIP() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package middleware contains wrappers that add cross-cutting behavior
+// (panic recovery, logging, ...) around update handlers without touching them.
+//
+// Middleware pattern:
+//
+//	handler := middleware.RecoveryMiddleware(handlers.RouteUpdate)
+//	handler(ctx, botAPI, update, cfg)
+//
+// Each middleware takes an UpdateHandler and returns a new UpdateHandler,
+// so several middlewares can be chained.
+package middleware
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// UpdateHandler processes one Telegram update
+// handlers.RouteUpdate has exactly this signature
+type UpdateHandler func(ctx context.Context, botAPI bot.BotSender, update tgbotapi.Update, cfg *config.Config)
+
+// RecoveryMiddleware catches panics in the wrapped handler and logs them.
+//
+// Why?
+//   - A panic in a handler (e.g., nil message.From) would crash the webhook goroutine
+//   - Telegram gets no 200 response and retries the same update
+//   - The retry panics again - the update is stuck in a crash loop
+//
+// How it works:
+//   - defer runs when the handler returns OR panics
+//   - recover() stops the panic and returns the panic value (nil if no panic)
+//   - debug.Stack() captures where the panic happened
+//   - We log and return normally, so the webhook still answers 200 OK
+//   - The logger comes from ctx, so the panic line carries the update's
+//     update_id/user_id/chat_id (see logger.ForUpdate)
+//
+// Parameters:
+//   - next: Handler to protect
+//
+// Returns:
+//   - UpdateHandler: Handler that never panics
+func RecoveryMiddleware(next UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, botAPI bot.BotSender, update tgbotapi.Update, cfg *config.Config) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(ctx).Error("Recovered from panic while handling update",
+					"panic", r,
+					"stack", string(debug.Stack()))
+			}
+		}()
+
+		next(ctx, botAPI, update, cfg)
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestRecoveryMiddleware verifies that panics are recovered and logged.
+//
+// Testing strategy:
+//   - Redirect slog to a buffer to inspect log output
+//   - Wrap handlers that panic in different ways
+//   - If recovery fails, the panic crashes the test itself
+func TestRecoveryMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   UpdateHandler
+		wantPanic string // Expected panic value in logs ("" = no panic)
+	}{
+		{
+			name: "panic with string",
+			handler: func(context.Context, bot.BotSender, tgbotapi.Update, *config.Config) {
+				panic("boom")
+			},
+			wantPanic: "boom",
+		},
+		{
+			name: "nil pointer dereference",
+			handler: func(_ context.Context, _ bot.BotSender, update tgbotapi.Update, _ *config.Config) {
+				_ = update.Message.From.ID // Message is nil
+			},
+			wantPanic: "nil pointer dereference",
+		},
+		{
+			name:      "no panic",
+			handler:   func(context.Context, bot.BotSender, tgbotapi.Update, *config.Config) {},
+			wantPanic: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Capture logs
+			var buf bytes.Buffer
+			oldLogger := slog.Default()
+			slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+			defer slog.SetDefault(oldLogger)
+
+			// Per-update logger in context, as webhookHandler sets it up
+			update := tgbotapi.Update{UpdateID: 42}
+			ctx := logger.WithContext(context.Background(), logger.ForUpdate(slog.Default(), update))
+
+			// Must return normally even if the handler panics
+			RecoveryMiddleware(tt.handler)(ctx, nil, update, &config.Config{})
+
+			logs := buf.String()
+			if tt.wantPanic == "" {
+				if logs != "" {
+					t.Errorf("unexpected log output: %s", logs)
+				}
+				return
+			}
+
+			for _, want := range []string{`"panic"`, tt.wantPanic, `"stack"`, "recovery_test.go", `"update_id":42`} {
+				if !strings.Contains(logs, want) {
+					t.Errorf("log output missing %q\n\nGot:\n%s", want, logs)
+				}
+			}
+		})
+	}
+}
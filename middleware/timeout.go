@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/metrics"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// timeoutMessage is sent to the user when their update exceeds the deadline
+const timeoutMessage = "⏳ That took too long. Please try again in a moment."
+
+// updateTimeouts counts updates that hit the deadline, exported at GET /metrics:
+//
+//	update_timeouts_total{handler="/ovh"} 2
+var updateTimeouts = metrics.NewCounterVec("update_timeouts_total",
+	"Updates that exceeded UPDATE_TIMEOUT by handler", "handler")
+
+// TimeoutMiddleware gives every update a processing deadline.
+//
+// Why?
+//   - A hanging external call (e.g., the OVH API) would keep the update's
+//     goroutine alive indefinitely, and the user would never get an answer
+//   - With a deadline, the handler's context is cancelled after timeout:
+//     OVH requests abort, and the user is told to retry
+//
+// How it works:
+//   - next runs in its own goroutine with a context.WithTimeout context
+//   - If next returns first, nothing else happens
+//   - If the deadline fires first, the timeout is logged with the handler name,
+//     counted in update_timeouts_total, and a "took too long" message is sent
+//     to the update's chat (if the update has one, e.g., not inline queries)
+//   - We return without waiting: next sees the cancelled context and stops on
+//     its own (handlers must respect ctx, see handlers.runOVHFetch)
+//   - If the parent context is cancelled instead (shutdown, client disconnect),
+//     there is nobody to notify, so we just return
+//
+// IMPORTANT: next runs in a separate goroutine, where a panic would crash the
+// process. Wrap RecoveryMiddleware inside this middleware, not outside:
+//
+//	middleware.TimeoutMiddleware(middleware.RecoveryMiddleware(handlers.RouteUpdate), ...)
+//
+// Parameters:
+//   - next: Handler to run under the deadline
+//   - timeout: Processing deadline per update (0 or negative disables the middleware)
+//   - handlerName: Names the handler an update is routed to (for logs and metrics)
+//
+// Returns:
+//   - UpdateHandler: Handler that returns at most timeout after it is called
+func TimeoutMiddleware(next UpdateHandler, timeout time.Duration, handlerName func(tgbotapi.Update) string) UpdateHandler {
+	if timeout <= 0 {
+		return next
+	}
+
+	return func(ctx context.Context, botAPI bot.BotSender, update tgbotapi.Update, cfg *config.Config) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			next(ctx, botAPI, update, cfg)
+		}()
+
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		// Cancelled by the parent (shutdown, client disconnect): nothing to report
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+
+		log := logger.FromContext(ctx)
+		name := handlerName(update)
+		updateTimeouts.Inc(name)
+		log.Error("Update processing timed out",
+			"handler", name,
+			"timeout", timeout.String())
+
+		chat := update.FromChat()
+		if chat == nil {
+			return
+		}
+		msg := tgbotapi.NewMessage(chat.ID, timeoutMessage)
+		if _, err := botAPI.Send(msg); err != nil {
+			log.Error("Failed to send timeout message",
+				"error", err,
+				"message_type", bot.MessageType(msg))
+		}
+	}
+}
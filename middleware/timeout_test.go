@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/bot"
+	"github.com/Alrem/run-tbot/config"
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// recordingSender records sent messages (safe for concurrent use)
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []tgbotapi.Chattable
+}
+
+func (r *recordingSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, c)
+	return tgbotapi.Message{}, nil
+}
+
+func (r *recordingSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (r *recordingSender) messages() []tgbotapi.Chattable {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]tgbotapi.Chattable(nil), r.sent...)
+}
+
+// sleepingHandler waits for d or until ctx is cancelled, recording whether it saw the cancellation
+func sleepingHandler(d time.Duration, cancelled chan<- bool) UpdateHandler {
+	return func(ctx context.Context, _ bot.BotSender, _ tgbotapi.Update, _ *config.Config) {
+		select {
+		case <-time.After(d):
+			cancelled <- false
+		case <-ctx.Done():
+			cancelled <- true
+		}
+	}
+}
+
+// TestTimeoutMiddleware tests the deadline, the user notification and the metric
+//
+// Testing strategy:
+//   - A handler sleeping past a short deadline: notified, counted, ctx cancelled
+//   - A fast handler: no notification, no count
+//   - An update without a chat (inline query): counted but nobody to notify
+//   - Timeout 0: middleware disabled, handler runs to completion
+func TestTimeoutMiddleware(t *testing.T) {
+	chatUpdate := tgbotapi.Update{UpdateID: 1, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 777}}}
+	inlineUpdate := tgbotapi.Update{UpdateID: 2, InlineQuery: &tgbotapi.InlineQuery{ID: "q"}}
+
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		sleep         time.Duration
+		update        tgbotapi.Update
+		wantTimeout   bool
+		wantNotified  bool
+		wantCancelled bool
+	}{
+		{name: "slow handler", timeout: 20 * time.Millisecond, sleep: time.Minute, update: chatUpdate,
+			wantTimeout: true, wantNotified: true, wantCancelled: true},
+		{name: "fast handler", timeout: time.Minute, sleep: 0, update: chatUpdate},
+		{name: "slow handler without chat", timeout: 20 * time.Millisecond, sleep: time.Minute, update: inlineUpdate,
+			wantTimeout: true, wantCancelled: true},
+		{name: "disabled", timeout: 0, sleep: 20 * time.Millisecond, update: chatUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Unique handler name per case, so counters don't leak between cases
+			handlerName := "test_" + strings.ReplaceAll(tt.name, " ", "_")
+			before := updateTimeouts.Value(handlerName)
+
+			var buf bytes.Buffer
+			ctx := logger.WithContext(context.Background(), slog.New(slog.NewJSONHandler(&buf, nil)))
+
+			sender := &recordingSender{}
+			cancelled := make(chan bool, 1)
+			handler := TimeoutMiddleware(sleepingHandler(tt.sleep, cancelled), tt.timeout,
+				func(tgbotapi.Update) string { return handlerName })
+
+			handler(ctx, sender, tt.update, &config.Config{})
+
+			// The wrapped handler must observe the cancelled context and exit
+			select {
+			case got := <-cancelled:
+				if got != tt.wantCancelled {
+					t.Errorf("handler saw cancellation = %v, want %v", got, tt.wantCancelled)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("wrapped handler did not return")
+			}
+
+			timeouts := updateTimeouts.Value(handlerName) - before
+			if tt.wantTimeout != (timeouts == 1) {
+				t.Errorf("update_timeouts_total{handler=%q} increased by %v, want timeout=%v", handlerName, timeouts, tt.wantTimeout)
+			}
+			if logged := strings.Contains(buf.String(), `"handler":"`+handlerName+`"`); logged != tt.wantTimeout {
+				t.Errorf("timeout logged with handler name = %v, want %v; logs: %s", logged, tt.wantTimeout, buf.String())
+			}
+
+			sent := sender.messages()
+			if !tt.wantNotified {
+				if len(sent) != 0 {
+					t.Errorf("sent %d messages, want none", len(sent))
+				}
+				return
+			}
+			if len(sent) != 1 {
+				t.Fatalf("sent %d messages, want 1 timeout notification", len(sent))
+			}
+			msg, ok := sent[0].(tgbotapi.MessageConfig)
+			if !ok || msg.ChatID != 777 || msg.Text != timeoutMessage {
+				t.Errorf("notification = %+v, want %q to chat 777", sent[0], timeoutMessage)
+			}
+		})
+	}
+}
+
+// TestTimeoutMiddleware_ParentCancelled tests that shutdown cancellation is not reported as a timeout
+func TestTimeoutMiddleware_ParentCancelled(t *testing.T) {
+	before := updateTimeouts.Value("test_parent_cancelled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sender := &recordingSender{}
+	cancelled := make(chan bool, 1)
+	handler := TimeoutMiddleware(sleepingHandler(time.Minute, cancelled), time.Minute,
+		func(tgbotapi.Update) string { return "test_parent_cancelled" })
+	handler(ctx, sender, tgbotapi.Update{Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 1}}}, &config.Config{})
+	<-cancelled
+
+	if got := updateTimeouts.Value("test_parent_cancelled") - before; got != 0 {
+		t.Errorf("update_timeouts_total increased by %v, want 0", got)
+	}
+	if sent := sender.messages(); len(sent) != 0 {
+		t.Errorf("sent %d messages, want none", len(sent))
+	}
+}
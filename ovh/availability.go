@@ -0,0 +1,137 @@
+package ovh
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StockStatus is what an availability value means for ordering a server
+type StockStatus int
+
+const (
+	// StockUnavailable: can't be ordered ("unavailable", "", or a value we don't know)
+	StockUnavailable StockStatus = iota
+	// StockComingSoon: listed, but not orderable yet ("comingSoon")
+	StockComingSoon
+	// StockInStock: orderable, delivered within the bucket's time ("1H", "72H", "available")
+	StockInStock
+)
+
+// deliveryBucketPattern matches OVH's delivery-time buckets
+// Examples: "1H", "24H", "72H", "240H", "480H", "1H-low", "1H-high"
+// (-low/-high tell how many servers are left, not how fast they ship)
+var deliveryBucketPattern = regexp.MustCompile(`(?i)^(\d+)H(?:-low|-high)?$`)
+
+// AvailabilityBucket is a parsed Datacenter.Availability value
+type AvailabilityBucket struct {
+	Status        StockStatus
+	DeliveryHours int // Delivery time of StockInStock buckets (0 if OVH doesn't say, e.g. "available")
+}
+
+// ParseAvailability classifies a raw availability value
+//
+// Known values:
+//   - "1H", "1H-low", "1H-high", "24H", "72H", "240H", "480H": in stock, delivery time in hours
+//   - "available": in stock, no delivery time
+//   - "comingSoon": not orderable yet
+//   - "unavailable", "unknown", "": unavailable
+//
+// Anything else is treated as unavailable: listing a server that can't
+// be ordered is worse than missing one for a new value OVH introduces.
+//
+// Parameters:
+//   - value: Datacenter.Availability (case-insensitive)
+//
+// Returns:
+//   - AvailabilityBucket: Status and delivery time
+func ParseAvailability(value string) AvailabilityBucket {
+	value = strings.TrimSpace(value)
+	switch strings.ToLower(value) {
+	case "available":
+		return AvailabilityBucket{Status: StockInStock}
+	case "comingsoon":
+		return AvailabilityBucket{Status: StockComingSoon}
+	}
+
+	if m := deliveryBucketPattern.FindStringSubmatch(value); m != nil {
+		hours, err := strconv.Atoi(m[1])
+		if err == nil && hours > 0 {
+			return AvailabilityBucket{Status: StockInStock, DeliveryHours: hours}
+		}
+	}
+	return AvailabilityBucket{Status: StockUnavailable}
+}
+
+// Listed reports whether an offer in this bucket is shown
+//
+// Parameters:
+//   - includeComingSoon: Also list "comingSoon" servers (see WithComingSoon)
+func (b AvailabilityBucket) Listed(includeComingSoon bool) bool {
+	switch b.Status {
+	case StockInStock:
+		return true
+	case StockComingSoon:
+		return includeComingSoon
+	default:
+		return false
+	}
+}
+
+// AvailabilityLabel describes a raw availability value for users
+//
+// Examples:
+//   - "1H-low" -> "delivery 1h"
+//   - "72H" -> "delivery 3 days"
+//   - "comingSoon" -> "coming soon"
+//   - "available" -> "in stock"
+//   - "unavailable" -> "unavailable"
+//
+// Values ParseAvailability doesn't know are returned unchanged.
+func AvailabilityLabel(value string) string {
+	bucket := ParseAvailability(value)
+	switch {
+	case bucket.Status == StockComingSoon:
+		return "coming soon"
+	case bucket.Status == StockInStock && bucket.DeliveryHours == 0:
+		return "in stock"
+	case bucket.Status == StockInStock && bucket.DeliveryHours < 48:
+		return "delivery " + strconv.Itoa(bucket.DeliveryHours) + "h"
+	case bucket.Status == StockInStock:
+		// 72H and longer read better in days (rounded up: 100H is 5 days, not 4)
+		return "delivery " + strconv.Itoa((bucket.DeliveryHours+23)/24) + " days"
+	case value == "":
+		return "unavailable"
+	default:
+		return value
+	}
+}
+
+// FilterAvailabilitiesByKeyword keeps the entries whose FQN or plan code
+// contains a keyword
+//
+// FQNs encode the hardware: "24sk50.ram-64g-ecc-2133.softraid-2x480nvme"
+// names the plan, the RAM and the disks. So the keyword "nvme" keeps
+// NVMe-equipped servers, "ram-64g" those with 64 GB of RAM.
+//
+// Parameters:
+//   - availabilities: Server availabilities
+//   - keyword: Text to look for, case-insensitive ("" keeps everything)
+//
+// Returns:
+//   - []Availability: Matching entries in their original order
+//     (availabilities itself for an empty keyword)
+func FilterAvailabilitiesByKeyword(availabilities []Availability, keyword string) []Availability {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	if keyword == "" {
+		return availabilities
+	}
+
+	var matched []Availability
+	for _, item := range availabilities {
+		if strings.Contains(strings.ToLower(item.FQN), keyword) || strings.Contains(strings.ToLower(item.PlanCode), keyword) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
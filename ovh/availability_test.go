@@ -0,0 +1,100 @@
+package ovh
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseAvailability tests the classification of every known availability value
+//
+// Testing strategy:
+//   - Delivery-time buckets are in stock, with their hours
+//   - "comingSoon" is listed only when asked for
+//   - "unavailable", "unknown", "" and values OVH never sent are never listed
+func TestParseAvailability(t *testing.T) {
+	tests := []struct {
+		value        string
+		wantStatus   StockStatus
+		wantHours    int
+		wantListed   bool // Default options
+		wantWithSoon bool // WithComingSoon(true)
+		wantLabel    string
+	}{
+		{value: "1H", wantStatus: StockInStock, wantHours: 1, wantListed: true, wantWithSoon: true, wantLabel: "delivery 1h"},
+		{value: "1H-low", wantStatus: StockInStock, wantHours: 1, wantListed: true, wantWithSoon: true, wantLabel: "delivery 1h"},
+		{value: "1H-high", wantStatus: StockInStock, wantHours: 1, wantListed: true, wantWithSoon: true, wantLabel: "delivery 1h"},
+		{value: "24H", wantStatus: StockInStock, wantHours: 24, wantListed: true, wantWithSoon: true, wantLabel: "delivery 24h"},
+		{value: "72H", wantStatus: StockInStock, wantHours: 72, wantListed: true, wantWithSoon: true, wantLabel: "delivery 3 days"},
+		{value: "240H", wantStatus: StockInStock, wantHours: 240, wantListed: true, wantWithSoon: true, wantLabel: "delivery 10 days"},
+		{value: "480H", wantStatus: StockInStock, wantHours: 480, wantListed: true, wantWithSoon: true, wantLabel: "delivery 20 days"},
+		{value: "72h", wantStatus: StockInStock, wantHours: 72, wantListed: true, wantWithSoon: true, wantLabel: "delivery 3 days"},
+		{value: "available", wantStatus: StockInStock, wantListed: true, wantWithSoon: true, wantLabel: "in stock"},
+		{value: "comingSoon", wantStatus: StockComingSoon, wantListed: false, wantWithSoon: true, wantLabel: "coming soon"},
+		{value: "unavailable", wantStatus: StockUnavailable, wantLabel: "unavailable"},
+		{value: "unknown", wantStatus: StockUnavailable, wantLabel: "unknown"},
+		{value: "", wantStatus: StockUnavailable, wantLabel: "unavailable"},
+		{value: "0H", wantStatus: StockUnavailable, wantLabel: "0H"},
+		{value: "H", wantStatus: StockUnavailable, wantLabel: "H"},
+		{value: "restocking", wantStatus: StockUnavailable, wantLabel: "restocking"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			bucket := ParseAvailability(tt.value)
+			if bucket.Status != tt.wantStatus || bucket.DeliveryHours != tt.wantHours {
+				t.Errorf("ParseAvailability(%q) = %+v, want status %d, %d hours", tt.value, bucket, tt.wantStatus, tt.wantHours)
+			}
+			if got := bucket.Listed(false); got != tt.wantListed {
+				t.Errorf("Listed(false) = %v, want %v", got, tt.wantListed)
+			}
+			if got := bucket.Listed(true); got != tt.wantWithSoon {
+				t.Errorf("Listed(true) = %v, want %v", got, tt.wantWithSoon)
+			}
+			if got := AvailabilityLabel(tt.value); got != tt.wantLabel {
+				t.Errorf("AvailabilityLabel(%q) = %q, want %q", tt.value, got, tt.wantLabel)
+			}
+		})
+	}
+}
+
+// TestFilterAvailabilitiesByKeyword tests the keyword filter on sample FQNs
+//
+// Testing strategy:
+//   - Matches in the FQN (disks, RAM) and in the plan code
+//   - Case-insensitive, surrounding spaces ignored
+//   - Empty keyword keeps everything, no match keeps nothing
+func TestFilterAvailabilitiesByKeyword(t *testing.T) {
+	availabilities := []Availability{
+		{FQN: "24sk50.ram-64g-ecc-2133.softraid-2x480nvme", PlanCode: "24sk50"},
+		{FQN: "24sk20.ram-32g-ecc-2133.softraid-2x2000sa", PlanCode: "24sk20"},
+		{FQN: "1801sk12.ram.1.softraid-2x2000sata", PlanCode: "1801sk12"},
+		{FQN: "25skle01.ram-32g-25skle.softraid-2x512NVMe", PlanCode: "25skle01"},
+	}
+
+	tests := []struct {
+		name    string
+		keyword string
+		want    []string // Plan codes of the kept entries, in order
+	}{
+		{name: "nvme disks", keyword: "nvme", want: []string{"24sk50", "25skle01"}},
+		{name: "upper case keyword", keyword: "NVME", want: []string{"24sk50", "25skle01"}},
+		{name: "ram size", keyword: "ram-32g", want: []string{"24sk20", "25skle01"}},
+		{name: "ram variant", keyword: ".ram.1", want: []string{"1801sk12"}},
+		{name: "model in plan code", keyword: "1801sk", want: []string{"1801sk12"}},
+		{name: "spaces trimmed", keyword: " sata ", want: []string{"1801sk12"}},
+		{name: "empty keeps all", keyword: "", want: []string{"24sk50", "24sk20", "1801sk12", "25skle01"}},
+		{name: "no match", keyword: "gpu", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range FilterAvailabilitiesByKeyword(availabilities, tt.keyword) {
+				got = append(got, item.PlanCode)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FilterAvailabilitiesByKeyword(%q) = %v, want %v", tt.keyword, got, tt.want)
+			}
+		})
+	}
+}
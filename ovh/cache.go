@@ -0,0 +1,132 @@
+package ovh
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cacheTTL is how long fetched OVH data is reused before fetching again
+// Availability changes slowly enough that a few minutes of staleness is fine,
+// and repeated button clicks no longer hit the OVH API every time
+var cacheTTL = 5 * time.Minute
+
+// dataCache is the package-level cache shared by all GetTopOffers calls
+var dataCache = newOVHCache()
+
+// ovhCache stores raw OVH API responses with a time-to-live
+//
+// What is cached:
+//   - Availabilities: one list for all subsidiaries (same endpoint)
+//   - Catalogs: one per catalog and subsidiary (different currency/tax per
+//     subsidiary), so the ECO and dedicated catalogs expire independently
+//
+// Concurrency:
+//   - Webhook requests may run concurrently, so every access holds the mutex
+type ovhCache struct {
+	mu sync.Mutex
+
+	availabilities   []Availability
+	availabilitiesAt time.Time
+
+	catalogs map[catalogKey]cachedCatalog
+}
+
+// catalogKey identifies one cached catalog
+type catalogKey struct {
+	name       string // Catalog name ("eco" or "dedicated")
+	subsidiary string // OVH subsidiary code
+}
+
+// cachedCatalog is a catalog together with its fetch time
+type cachedCatalog struct {
+	catalog   *Catalog
+	fetchedAt time.Time
+}
+
+// newOVHCache creates an empty cache
+func newOVHCache() *ovhCache {
+	return &ovhCache{
+		catalogs: make(map[catalogKey]cachedCatalog),
+	}
+}
+
+// get returns cached availabilities and one catalog for a subsidiary
+// Each return value is nil if missing or expired, so callers fetch only what they need
+//
+// Parameters:
+//   - name: Catalog name ("eco" or "dedicated")
+//   - subsidiary: OVH subsidiary code
+//
+// Returns:
+//   - []Availability: Cached availabilities or nil
+//   - *Catalog: Cached catalog or nil
+func (c *ovhCache) get(name, subsidiary string) ([]Availability, *Catalog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	var availabilities []Availability
+	if c.availabilities != nil && now.Sub(c.availabilitiesAt) < cacheTTL {
+		availabilities = c.availabilities
+	}
+
+	var catalog *Catalog
+	if entry, ok := c.catalogs[catalogKey{name, subsidiary}]; ok && now.Sub(entry.fetchedAt) < cacheTTL {
+		catalog = entry.catalog
+	}
+
+	return availabilities, catalog
+}
+
+// putAvailabilities stores freshly fetched availabilities
+func (c *ovhCache) putAvailabilities(availabilities []Availability) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.availabilities = availabilities
+	c.availabilitiesAt = time.Now()
+}
+
+// putCatalog stores a freshly fetched catalog (by name) for a subsidiary
+func (c *ovhCache) putCatalog(name, subsidiary string, catalog *Catalog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.catalogs[catalogKey{name, subsidiary}] = cachedCatalog{catalog: catalog, fetchedAt: time.Now()}
+}
+
+// loadCachedCatalog returns a catalog from the package cache, fetching
+// (and caching) it when missing or stale
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Catalog name (catalogEco or catalogDedicated)
+//   - subsidiary: OVH subsidiary code (e.g., "GB")
+//
+// Returns:
+//   - *Catalog: The catalog (shared with the cache, must not be modified)
+//   - error: Any errors during fetch or parse (ctx.Err() if cancelled)
+func loadCachedCatalog(ctx context.Context, name, subsidiary string) (*Catalog, error) {
+	if _, catalog := dataCache.get(name, subsidiary); catalog != nil {
+		return catalog, nil
+	}
+
+	catalog, err := loadCatalog(ctx, name, subsidiary)
+	if err != nil {
+		return nil, err
+	}
+	dataCache.putCatalog(name, subsidiary, catalog)
+	return catalog, nil
+}
+
+// reset clears all cached data (used by tests)
+func (c *ovhCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.availabilities = nil
+	c.availabilitiesAt = time.Time{}
+	c.catalogs = make(map[catalogKey]cachedCatalog)
+}
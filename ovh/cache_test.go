@@ -0,0 +1,50 @@
+package ovh
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOVHCache tests cache hits, misses and expiry
+func TestOVHCache(t *testing.T) {
+	cache := newOVHCache()
+
+	// Empty cache - both values missing
+	if avail, catalog := cache.get(catalogEco, "FR"); avail != nil || catalog != nil {
+		t.Fatalf("empty cache returned data: %v, %v", avail, catalog)
+	}
+
+	cache.putAvailabilities([]Availability{{FQN: "a"}})
+	cache.putCatalog(catalogEco, "FR", &Catalog{CatalogID: 1})
+
+	// Catalog is cached per subsidiary
+	avail, catalog := cache.get(catalogEco, "FR")
+	if len(avail) != 1 || catalog == nil || catalog.CatalogID != 1 {
+		t.Errorf("cache.get(FR) = %v, %v; want cached data", avail, catalog)
+	}
+	if _, catalog := cache.get(catalogEco, "GB"); catalog != nil {
+		t.Errorf("cache.get(GB) returned catalog cached for FR")
+	}
+
+	// ECO and dedicated catalogs are cached independently
+	if _, catalog := cache.get(catalogDedicated, "FR"); catalog != nil {
+		t.Errorf("cache.get(dedicated, FR) returned the ECO catalog")
+	}
+	cache.putCatalog(catalogDedicated, "FR", &Catalog{CatalogID: 2})
+	if _, catalog := cache.get(catalogDedicated, "FR"); catalog == nil || catalog.CatalogID != 2 {
+		t.Errorf("cache.get(dedicated, FR) = %v, want the dedicated catalog", catalog)
+	}
+	if _, catalog := cache.get(catalogEco, "FR"); catalog == nil || catalog.CatalogID != 1 {
+		t.Errorf("cache.get(eco, FR) = %v, want the ECO catalog", catalog)
+	}
+
+	// Expired entries are treated as missing
+	oldTTL := cacheTTL
+	cacheTTL = time.Nanosecond
+	defer func() { cacheTTL = oldTTL }()
+	time.Sleep(time.Millisecond)
+
+	if avail, catalog := cache.get(catalogEco, "FR"); avail != nil || catalog != nil {
+		t.Errorf("expired cache returned data: %v, %v", avail, catalog)
+	}
+}
@@ -1,19 +1,51 @@
 package ovh
 
 import (
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Alrem/run-tbot/status"
+	"github.com/Alrem/run-tbot/tgfmt"
+	"golang.org/x/sync/errgroup"
 )
 
 // apiBase is the OVH API endpoint for EU region
 // This is a public API - no authentication required
-const apiBase = "https://eu.api.ovh.com/v1"
+// Declared as var (not const) so tests can point it at a local httptest server
+var apiBase = "https://eu.api.ovh.com/v1"
+
+// UserAgent is sent with every OVH API request
+// A descriptive User-Agent is polite (OVH can tell who is calling) and keeps
+// WAFs from blocking the default "Go-http-client/1.1" as an anonymous scraper.
+// Exported so it can be overridden at startup or in tests (set it before the
+// first request: it is read without locking).
+var UserAgent = defaultUserAgent()
+
+// RequestTimeout bounds each OVH API request (see httpGet)
+// Applied as a context deadline, so a caller's shorter deadline or
+// cancellation still wins. 10 seconds: OVH data backs interactive chat
+// features, where a longer wait is worse than an error.
+// Exported so it can be overridden at startup (OVH_TIMEOUT) or in tests
+// (set it before the first request: it is read without locking).
+var RequestTimeout = 10 * time.Second
+
+// defaultUserAgent builds "run-tbot/<version> (+https://github.com/Alrem/run-tbot)"
+// The version comes from the build info, see status.BuildVersion
+func defaultUserAgent() string {
+	version, _ := status.BuildVersion()
+	return "run-tbot/" + version + " (+https://github.com/Alrem/run-tbot)"
+}
 
 // Availability represents server availability data from OVH API
 // Contains information about which datacenters have servers in stock
@@ -26,7 +58,7 @@ type Availability struct {
 // Datacenter represents availability in a specific datacenter
 type Datacenter struct {
 	Datacenter   string `json:"datacenter"`   // Datacenter code (e.g., "lon", "rbx")
-	Availability string `json:"availability"` // "available", "unavailable", or numeric string
+	Availability string `json:"availability"` // "1H", "72H", "comingSoon", "unavailable", ... (see ParseAvailability)
 }
 
 // Catalog represents the OVH catalog response
@@ -68,14 +100,19 @@ type AddonFamily struct {
 
 // Pricing represents a pricing tier for a plan
 // OVH prices are in micro-units (1 GBP = 100000000 micro-units)
+//
+// A plan usually has two kinds of pricings:
+//   - Phase 0, interval 0 ("none"), capacity "installation": one-time setup fee
+//   - Interval 1 month, capacity "renew": the monthly rental
 type Pricing struct {
-	Phase        int    `json:"phase"`
-	Interval     int    `json:"interval"`
-	IntervalUnit string `json:"intervalUnit"` // "month", "year", etc.
-	Duration     string `json:"duration"`     // ISO 8601 duration (e.g., "P1M")
-	Price        int64  `json:"price"`        // Price in micro-units
-	Tax          int64  `json:"tax"`          // Tax in micro-units
-	Description  string `json:"description"`
+	Phase        int      `json:"phase"`
+	Capacities   []string `json:"capacities"` // "installation", "renew", ...
+	Interval     int      `json:"interval"`
+	IntervalUnit string   `json:"intervalUnit"` // "month", "year", "none", etc.
+	Duration     string   `json:"duration"`     // ISO 8601 duration (e.g., "P1M")
+	Price        int64    `json:"price"`        // Price in micro-units
+	Tax          int64    `json:"tax"`          // Tax in micro-units
+	Description  string   `json:"description"`
 }
 
 // Product represents a product in the catalog
@@ -85,108 +122,286 @@ type Product struct {
 
 // Offer represents a complete server offer with computed price
 // This is our aggregated view combining availability, catalog, and pricing
+//
+// JSON keys are snake_case ("plan_code", "invoice_name", ...): offers are
+// kept in snapshots (see SnapshotStore) and may be served to other programs.
 type Offer struct {
-	FQN         string            // Fully qualified name
-	PlanCode    string            // Plan code
-	Price       float64           // Total monthly price (base + mandatory addons)
-	Currency    string            // Currency code
-	InvoiceName string            // Display name
-	Addons      map[string]string // Mandatory addons (family -> addon code)
+	FQN          string            `json:"fqn"`                    // Fully qualified name
+	PlanCode     string            `json:"plan_code"`              // Plan code
+	Price        float64           `json:"price"`                  // Total monthly price (base + mandatory addons)
+	SetupFee     float64           `json:"setup_fee,omitempty"`    // One-time setup fee (base + mandatory addons, 0 = none)
+	Currency     string            `json:"currency"`               // Currency code
+	InvoiceName  string            `json:"invoice_name"`           // Display name
+	Datacenter   string            `json:"datacenter"`             // Datacenter code the offer is available in (e.g., "lon")
+	Availability string            `json:"availability,omitempty"` // Raw availability there (e.g., "72H", see AvailabilityLabel)
+	Addons       map[string]string `json:"addons"`                 // Mandatory addons (family -> addon code)
+	Specs        PlanSpecs         `json:"specs"`                  // Hardware parsed from the names (see ParsePlanSpecs)
+}
+
+// UnmarshalJSON decodes an Offer, also accepting the PascalCase keys
+// written before Offer had json tags ("PlanCode", "InvoiceName", ...)
+//
+// Why?
+//   - Snapshots saved by older versions use the Go field names as keys
+//   - encoding/json matches keys case-insensitively, so "FQN" or "Price"
+//     still find their field, but "PlanCode" doesn't match "plan_code"
+//   - Without this, the first snapshot loaded after an upgrade would have
+//     offers without plan code and name
+func (o *Offer) UnmarshalJSON(data []byte) error {
+	// offerJSON has Offer's fields and tags, but not this method (no recursion)
+	type offerJSON Offer
+	var offer offerJSON
+	if err := json.Unmarshal(data, &offer); err != nil {
+		return err
+	}
+
+	// Old keys, only used where the new ones were missing
+	var legacy struct {
+		PlanCode    string
+		InvoiceName string
+		Specs       struct {
+			RAMGB    int
+			CPUCores int
+		}
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	if offer.PlanCode == "" {
+		offer.PlanCode = legacy.PlanCode
+	}
+	if offer.InvoiceName == "" {
+		offer.InvoiceName = legacy.InvoiceName
+	}
+	if offer.Specs.RAMGB == 0 {
+		offer.Specs.RAMGB = legacy.Specs.RAMGB
+	}
+	if offer.Specs.CPUCores == 0 {
+		offer.Specs.CPUCores = legacy.Specs.CPUCores
+	}
+
+	*o = Offer(offer)
+	return nil
 }
 
+// ErrNoOffers means OVH answered, but no server is available for the query
+// (e.g., everything in the datacenter is sold out)
+//
+// GetTopOffers returns an empty list in that case, not this error: callers
+// such as the price watcher treat "nothing in stock" as normal data.
+// Handlers that show offers to users return it to tell "nothing in stock"
+// apart from "OVH is down" (errors.Is(err, ovh.ErrNoOffers)).
+var ErrNoOffers = errors.New("no OVH offers available")
+
 // GetTopOffers fetches available OVH servers and returns top N cheapest
 // This is the main entry point for the bot to get server information
 //
+// Options are passed using the functional options pattern (see options.go):
+// every parameter has a sensible default, and new filters can be added
+// later without changing this function's signature.
+//
 // Parameters:
-//   - subsidiary: OVH subsidiary (e.g., "GB", "FR", "DE")
-//   - datacenter: Datacenter code (e.g., "lon", "rbx", "gra")
-//   - top: Number of offers to return (sorted by price, ascending)
+//   - opts: Zero or more options (WithSubsidiary, WithDatacenter, WithTop, ...)
 //
 // Returns:
-//   - []Offer: Sorted list of offers (cheapest first)
+//   - []Offer: Sorted list of offers (cheapest first by default)
 //   - error: Any errors during API calls or processing
 //
 // Example:
 //
-//	offers, err := GetTopOffers("GB", "lon", 5)
-func GetTopOffers(subsidiary, datacenter string, top int) ([]Offer, error) {
-	// Step 1: Load server availability data
-	availabilities, err := loadAvailabilities()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load availabilities: %w", err)
-	}
+//	offers, err := GetTopOffers(WithSubsidiary("GB"), WithDatacenter("lon"), WithTop(5))
+func GetTopOffers(opts ...Option) ([]Offer, error) {
+	return GetTopOffersContext(context.Background(), opts...)
+}
 
-	// Step 2: Load pricing catalog for subsidiary
-	catalog, err := loadEcoCatalog(subsidiary)
+// GetTopOffersContext is GetTopOffers with a context for cancellation
+// Cancelling ctx aborts in-flight OVH API requests (e.g., user sent /cancel)
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - opts: Zero or more options (WithSubsidiary, WithDatacenter, WithTop, ...)
+//
+// Returns:
+//   - []Offer: Sorted list of offers (cheapest first by default)
+//   - error: Any errors during API calls or processing (ctx.Err() if cancelled)
+func GetTopOffersContext(ctx context.Context, opts ...Option) ([]Offer, error) {
+	return getTopOffers(ctx, catalogEco, newOptions(opts...))
+}
+
+// GetTopDedicatedOffers is GetTopOffers for the dedicated catalog (Advance and up)
+// The availabilities are the same for both product lines; only the catalog
+// used for pricing differs, so plans missing from it (e.g., Kimsufi) are skipped.
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (determines currency, e.g., "FR" for EUR)
+//   - datacenter: Datacenter code (e.g., "lon"); offers must be available in it,
+//     so "" matches no offers (there is no "all datacenters" value)
+//   - top: Max offers to return (0 = no limit)
+//
+// Returns:
+//   - []Offer: Cheapest dedicated offers first
+//   - error: Any errors during API calls or processing
+func GetTopDedicatedOffers(subsidiary, datacenter string, top int) ([]Offer, error) {
+	return GetTopDedicatedOffersContext(context.Background(), subsidiary, datacenter, top)
+}
+
+// GetTopDedicatedOffersContext is GetTopDedicatedOffers with a context for cancellation
+func GetTopDedicatedOffersContext(ctx context.Context, subsidiary, datacenter string, top int) ([]Offer, error) {
+	options := newOptions(WithSubsidiary(subsidiary), WithDatacenter(datacenter), WithTop(top))
+	return getTopOffers(ctx, catalogDedicated, options)
+}
+
+// getTopOffers is the shared body of GetTopOffers and GetTopDedicatedOffers
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - catalogName: Catalog to price against (catalogEco or catalogDedicated)
+//   - options: Merged options
+//
+// Returns:
+//   - []Offer: Sorted list of offers
+//   - error: Any errors during API calls or processing (ctx.Err() if cancelled)
+func getTopOffers(ctx context.Context, catalogName string, options Options) ([]Offer, error) {
+	// Steps 1-2: Load server availability data and pricing catalog for subsidiary
+	// Both requests are independent, so loadOVHData runs them in parallel
+	availabilities, catalog, timings, err := loadOVHData(ctx, catalogName, options.Subsidiary)
+	info := OffersInfo{
+		Subsidiary:     options.Subsidiary,
+		CatalogName:    catalogName,
+		Availabilities: timings.availabilities,
+		Catalog:        timings.catalog,
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to load catalog: %w", err)
+		info.Err = err
+		offersDone(ctx, info)
+		return nil, err
 	}
 
-	// Step 3: Index catalog for fast lookups
+	// Steps 3-6: Price available plans, filter, sort and return top N offers
+	// (the same steps as buildOffers, split to count the offers considered)
+	priced := priceOffers(availabilities, catalog, options)
+	offers := filterAndSortOffers(priced, options)
+
+	info.OffersConsidered, info.OffersReturned = len(priced), len(offers)
+	offersDone(ctx, info)
+	return offers, nil
+}
+
+// buildOffers turns raw availabilities into priced offers for one catalog
+// Shared by GetTopOffers (ECO only) and CompareEcoAdvance (ECO + Advance):
+// the pricing logic is the same, only the catalog differs.
+//
+// Parameters:
+//   - availabilities: Server availabilities (all product lines)
+//   - catalog: Catalog to price against; plans missing from it are skipped
+//   - options: Merged options (datacenter, price filters, sort, top)
+//
+// Returns:
+//   - []Offer: Filtered, sorted and truncated offers (never nil)
+func buildOffers(availabilities []Availability, catalog *Catalog, options Options) []Offer {
+	return filterAndSortOffers(priceOffers(availabilities, catalog, options), options)
+}
+
+// priceOffers prices the plans available in options.Datacenter (steps 1-2 of buildOffers)
+//
+// Parameters:
+//   - availabilities: Server availabilities (all product lines)
+//   - catalog: Catalog to price against; plans missing from it are skipped
+//   - options: Merged options (only Datacenter, AddonStrategy, ComingSoon and Keyword are used)
+//
+// Returns:
+//   - []Offer: Every available, priceable offer, unsorted and unfiltered
+func priceOffers(availabilities []Availability, catalog *Catalog, options Options) []Offer {
+	// Step 1: Index catalog for fast lookups
 	plansIdx, addonsIdx := indexCatalog(catalog)
 	catalogCurrency := getCatalogCurrency(catalog)
 
-	// Step 4: Build offers list
+	// Step 2: Build offers list
+	// The keyword filter (WithKeyword) runs first: no need to price what is dropped
 	var offers []Offer
 
-	for _, item := range availabilities {
+	for _, item := range FilterAvailabilitiesByKeyword(availabilities, options.Keyword) {
 		// Skip invalid entries
 		if item.FQN == "" || item.PlanCode == "" {
 			continue
 		}
 
-		// Only include plans that exist in ECO catalog
+		// Only include plans that exist in this catalog
+		// (availabilities cover every product line, catalogs only one)
 		if _, ok := plansIdx[item.PlanCode]; !ok {
 			continue
 		}
 
 		// Check if available in requested datacenter
-		available := false
+		// "comingSoon" only counts with WithComingSoon, unknown values never do
+		availability := ""
 		for _, dcInfo := range item.Datacenters {
-			if dcInfo.Datacenter == datacenter && dcInfo.Availability != "unavailable" {
-				available = true
+			if dcInfo.Datacenter == options.Datacenter && ParseAvailability(dcInfo.Availability).Listed(options.ComingSoon) {
+				availability = dcInfo.Availability
 				break
 			}
 		}
-		if !available {
+		if availability == "" {
 			continue
 		}
 
-		// Compute total price (base + mandatory addons)
-		total, currency, invoiceName, addons, err := computeTotalMonthly(
-			plansIdx, addonsIdx, item.PlanCode, item.FQN, catalogCurrency,
+		// Compute total price (base + mandatory addons) and hardware specs
+		offer, err := computeTotalMonthly(
+			plansIdx, addonsIdx, item.PlanCode, item.FQN, catalogCurrency, options.AddonStrategy,
 		)
 		if err != nil {
 			// Skip offers we can't price
 			continue
 		}
+		offer.Datacenter = options.Datacenter
+		offer.Availability = availability
 
-		offers = append(offers, Offer{
-			FQN:         item.FQN,
-			PlanCode:    item.PlanCode,
-			Price:       total,
-			Currency:    currency,
-			InvoiceName: invoiceName,
-			Addons:      addons,
-		})
+		offers = append(offers, offer)
 	}
+	return offers
+}
 
-	// Step 5: Sort by price (cheapest first)
-	sort.Slice(offers, func(i, j int) bool {
-		return offers[i].Price < offers[j].Price
-	})
-
-	// Step 6: Return top N offers
-	if len(offers) == 0 {
-		return []Offer{}, nil
+// filterAndSortOffers applies price filters, sort order and top-N limit
+// Extracted from GetTopOffers so it can be tested without network calls
+//
+// Parameters:
+//   - offers: Priced offers in any order
+//   - options: Merged options (see newOptions)
+//
+// Returns:
+//   - []Offer: Filtered, sorted and truncated offers (never nil)
+func filterAndSortOffers(offers []Offer, options Options) []Offer {
+	// Step 1: Keep only offers inside [MinPrice, MaxPrice] and of the PlanPrefix family
+	// Zero value means "no limit" for both bounds, "" for the prefix
+	filtered := make([]Offer, 0, len(offers))
+	for _, offer := range offers {
+		if options.MinPrice > 0 && offer.Price < options.MinPrice {
+			continue
+		}
+		if options.MaxPrice > 0 && offer.Price > options.MaxPrice {
+			continue
+		}
+		if !strings.HasPrefix(offer.PlanCode, options.PlanPrefix) {
+			continue
+		}
+		filtered = append(filtered, offer)
 	}
 
-	limit := top
-	if len(offers) < limit {
-		limit = len(offers)
+	// Step 2: Sort by price in requested order
+	// sort.SliceStable keeps API order for offers with equal price
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if options.SortOrder == SortByPriceDesc {
+			return filtered[i].Price > filtered[j].Price
+		}
+		return filtered[i].Price < filtered[j].Price
+	})
+
+	// Step 3: Return top N offers
+	if options.Top > 0 && len(filtered) > options.Top {
+		filtered = filtered[:options.Top]
 	}
 
-	return offers[:limit], nil
+	return filtered
 }
 
 // FormatOfferForTelegram formats an Offer for display in Telegram
@@ -199,65 +414,185 @@ func GetTopOffers(subsidiary, datacenter string, top int) ([]Offer, error) {
 // Returns:
 //   - string: Formatted message with escaped MarkdownV2
 func FormatOfferForTelegram(offer Offer, index int) string {
-	// Format: 1. 15.99 GBP/mo - Server Name
-	//         FQN: server.fqn.code
+	// Format: 1. 15.99 GBP/mo - Server Name  (or "15.99 GBP/mo + 12.00 setup - ...")
+	//         FQN: server.fqn.code · London, UK · delivery 1h
 	var builder strings.Builder
+	// Room for both lines up front, so the builder usually allocates once
+	// (the 64 bytes cover numbers, markup and the datacenter name)
+	builder.Grow(64 + len(offer.InvoiceName) + len(offer.FQN))
 
 	// Line 1: Number, Price, Name
-	builder.WriteString(fmt.Sprintf("%d\\. ", index))
-	// Format price first, then escape it for MarkdownV2 (periods must be escaped)
-	priceStr := fmt.Sprintf("%.2f", offer.Price)
-	builder.WriteString(fmt.Sprintf("*%s %s/mo* \\- ",
-		escapeMarkdownV2(priceStr),
-		escapeMarkdownV2(offer.Currency)))
-	builder.WriteString(escapeMarkdownV2(offer.InvoiceName))
+	// AppendInt formats the number into a stack buffer
+	// (fmt.Sprintf would allocate a temporary string first)
+	var number [20]byte
+	builder.Write(strconv.AppendInt(number[:0], int64(index), 10))
+	builder.WriteString("\\. ")
+	// Format price first; tgfmt.Bold escapes it for MarkdownV2 (periods must be escaped)
+	builder.WriteString(tgfmt.Bold(strconv.FormatFloat(offer.Price, 'f', 2, 64) + " " + offer.Currency + "/mo"))
+	// One-time setup fee, only when the plan has one: "+ 12.00 setup"
+	if offer.SetupFee > 0 {
+		builder.WriteString(tgfmt.EscapeMarkdownV2(" + " + strconv.FormatFloat(offer.SetupFee, 'f', 2, 64) + " setup"))
+	}
+	builder.WriteString(" \\- ")
+	builder.WriteString(tgfmt.EscapeMarkdownV2(offer.InvoiceName))
 	builder.WriteString("\n")
 
-	// Line 2: FQN (smaller text)
-	builder.WriteString("   _FQN: ")
-	builder.WriteString(escapeMarkdownV2(offer.FQN))
-	builder.WriteString("_")
+	// Line 2: FQN, datacenter location and delivery time (smaller text)
+	line2 := "FQN: " + offer.FQN
+	if offer.Datacenter != "" {
+		line2 += " · " + DatacenterName(offer.Datacenter)
+	}
+	if offer.Availability != "" {
+		line2 += " · " + AvailabilityLabel(offer.Availability)
+	}
+	builder.WriteString("   ")
+	builder.WriteString(tgfmt.Italic(line2))
 
 	return builder.String()
 }
 
-// escapeMarkdownV2 escapes special characters for Telegram MarkdownV2
-// MarkdownV2 requires escaping: _ * [ ] ( ) ~ ` > # + - = | { } . !
+// loadOVHData returns availabilities and one catalog for a subsidiary
+// Uses cached data when fresh; anything missing is fetched in parallel
+//
+// Why parallel?
+//   - Availabilities and catalog are independent requests
+//   - Sequential: 3s + 3s = 6s, parallel: max(3s, 3s) = 3s
+//
+// errgroup.WithContext runs each fetch in its own goroutine:
+//   - Wait() returns the first error from any goroutine
+//   - The shared context is cancelled on the first error,
+//     so the other in-flight request is aborted (fail fast)
 //
 // Parameters:
-//   - text: Text to escape
+//   - ctx: Context for cancellation
+//   - catalogName: Catalog to load (catalogEco or catalogDedicated)
+//   - subsidiary: OVH subsidiary code (e.g., "FR")
 //
 // Returns:
-//   - string: Escaped text safe for MarkdownV2
-func escapeMarkdownV2(text string) string {
-	// Characters that need escaping in MarkdownV2
-	specialChars := []string{
-		"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!",
+//   - []Availability: Server availabilities
+//   - *Catalog: The named catalog for the subsidiary
+//   - ovhDataTimings: How long each fetch took (0 for cached data), also set on error
+//   - error: First error from either request
+func loadOVHData(ctx context.Context, catalogName, subsidiary string) ([]Availability, *Catalog, ovhDataTimings, error) {
+	var timings ovhDataTimings
+
+	// Step 1: Check cache before launching any goroutines
+	availabilities, catalog := dataCache.get(catalogName, subsidiary)
+	if availabilities != nil && catalog != nil {
+		return availabilities, catalog, timings, nil
 	}
 
-	result := text
-	for _, char := range specialChars {
-		result = strings.ReplaceAll(result, char, "\\"+char)
+	// Step 2: Fetch whatever is missing in parallel
+	g, gctx := errgroup.WithContext(ctx)
+
+	if availabilities == nil {
+		g.Go(func() error {
+			start := time.Now()
+			avail, err := loadAvailabilities(gctx)
+			timings.availabilities = time.Since(start)
+			if err != nil {
+				return fmt.Errorf("failed to load availabilities: %w", err)
+			}
+			availabilities = avail
+			dataCache.putAvailabilities(avail)
+			return nil
+		})
 	}
-	return result
+
+	if catalog == nil {
+		g.Go(func() error {
+			start := time.Now()
+			cat, err := loadCatalog(gctx, catalogName, subsidiary)
+			timings.catalog = time.Since(start)
+			if err != nil {
+				return fmt.Errorf("failed to load catalog: %w", err)
+			}
+			catalog = cat
+			dataCache.putCatalog(catalogName, subsidiary, cat)
+			return nil
+		})
+	}
+
+	// Wait for both goroutines (each writes only its own variables, so no data race)
+	// Successful fetches are cached inside the goroutines, even if the other one failed
+	if err := g.Wait(); err != nil {
+		return nil, nil, timings, err
+	}
+
+	return availabilities, catalog, timings, nil
+}
+
+// ovhDataTimings is how long loadOVHData spent fetching each input
+// A field stays 0 when the data came from the cache.
+type ovhDataTimings struct {
+	availabilities time.Duration
+	catalog        time.Duration
 }
 
+// httpClient sends every OVH API request
+// Declared as var so tests can stub the transport
+var httpClient = &http.Client{}
+
 // httpGet performs HTTP GET request with query parameters
-// Includes 30-second timeout for reliability
+// Bounded by RequestTimeout through the context (not http.Client.Timeout),
+// so the timeout composes with the caller's deadline and cancellation
+// Sends UserAgent and "Accept: application/json" (all OVH endpoints return JSON)
+// Requests gzip explicitly and decompresses the body itself, see below
+// Successful requests are recorded in status.Default; every request,
+// successful or not, is reported to the Hooks (see SetHooks)
 //
 // Parameters:
+//   - ctx: Context for cancellation (request is aborted when ctx is done)
+//   - endpoint: Endpoint name for the hooks (one of the Endpoint* constants)
 //   - url: Full URL to request
 //   - params: Optional query parameters
 //
 // Returns:
 //   - []byte: Response body
+//   - error: Any errors during request (wraps context.DeadlineExceeded on timeout)
+func httpGet(ctx context.Context, endpoint, url string, params map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	body, statusCode, err := doGet(ctx, url, params)
+	requestDone(ctx, RequestInfo{
+		Endpoint:   endpoint,
+		Duration:   time.Since(start),
+		StatusCode: statusCode,
+		Bytes:      len(body),
+		Err:        err,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Reported by the health endpoint (cache hits don't count, only real fetches)
+	status.Default.RecordOVHFetch(time.Now())
+
+	return body, nil
+}
+
+// doGet sends the request for httpGet and reads the (decompressed) body
+//
+// Returns:
+//   - []byte: Response body (nil on error)
+//   - int: HTTP status code, 0 if no response was received
 //   - error: Any errors during request
-func httpGet(url string, params map[string]string) ([]byte, error) {
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("GET", url, nil)
+func doGet(ctx context.Context, url string, params map[string]string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	// The catalog is several MB of JSON and compresses ~10x.
+	// Go's transport adds Accept-Encoding: gzip and decompresses on its own,
+	// but only when the header is NOT set by us. Setting it explicitly makes
+	// the behavior independent of the transport (custom clients, proxies),
+	// so we decompress manually based on Content-Encoding.
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	// Add query parameters
 	if params != nil {
@@ -268,32 +603,45 @@ func httpGet(url string, params map[string]string) ([]byte, error) {
 		req.URL.RawQuery = q.Encode()
 	}
 
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: status %d", resp.StatusCode)
+		return nil, resp.StatusCode, fmt.Errorf("HTTP error: status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, resp.StatusCode, fmt.Errorf("invalid gzip response: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	return body, nil
+	return body, resp.StatusCode, nil
 }
 
 // loadAvailabilities fetches server availability from OVH API
 // Endpoint: /dedicated/server/datacenter/availabilities
 //
+// Parameters:
+//   - ctx: Context for cancellation
+//
 // Returns:
 //   - []Availability: List of all server availabilities
 //   - error: Any errors during fetch or parse
-func loadAvailabilities() ([]Availability, error) {
-	data, err := httpGet(apiBase+"/dedicated/server/datacenter/availabilities", nil)
+func loadAvailabilities(ctx context.Context) ([]Availability, error) {
+	data, err := httpGet(ctx, EndpointAvailabilities, apiBase+"/dedicated/server/datacenter/availabilities", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -310,13 +658,52 @@ func loadAvailabilities() ([]Availability, error) {
 // Endpoint: /order/catalog/public/eco
 //
 // Parameters:
+//   - ctx: Context for cancellation
+//   - subsidiary: OVH subsidiary code (e.g., "GB")
+//
+// Returns:
+//   - *Catalog: The catalog with plans and pricing
+//   - error: Any errors during fetch or parse
+func loadEcoCatalog(ctx context.Context, subsidiary string) (*Catalog, error) {
+	return loadCatalog(ctx, catalogEco, subsidiary)
+}
+
+// loadDedicatedCatalog fetches the dedicated catalog (Advance and up)
+// Endpoint: /order/catalog/public/dedicated
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - subsidiary: OVH subsidiary code (e.g., "GB")
+//
+// Returns:
+//   - *Catalog: The catalog with plans and pricing
+//   - error: Any errors during fetch or parse
+func loadDedicatedCatalog(ctx context.Context, subsidiary string) (*Catalog, error) {
+	return loadCatalog(ctx, catalogDedicated, subsidiary)
+}
+
+// Public catalog names, as used in /order/catalog/public/{name}
+const (
+	catalogEco       = "eco"       // Kimsufi, So You Start, Rise
+	catalogDedicated = "dedicated" // Advance and up
+)
+
+// loadCatalog fetches one of the public OVH order catalogs
+// Endpoint: /order/catalog/public/{name}
+//
+// All public catalogs share the same JSON layout (plans, addons, pricings),
+// so the same Catalog struct and pricing code work for every product line.
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - name: Catalog name ("eco" for Kimsufi/So You Start/Rise, "dedicated" for Advance and up)
 //   - subsidiary: OVH subsidiary code (e.g., "GB")
 //
 // Returns:
 //   - *Catalog: The catalog with plans and pricing
 //   - error: Any errors during fetch or parse
-func loadEcoCatalog(subsidiary string) (*Catalog, error) {
-	data, err := httpGet(apiBase+"/order/catalog/public/eco", map[string]string{
+func loadCatalog(ctx context.Context, name, subsidiary string) (*Catalog, error) {
+	data, err := httpGet(ctx, "catalog/"+name, apiBase+"/order/catalog/public/"+name, map[string]string{
 		"ovhSubsidiary": subsidiary,
 	})
 	if err != nil {
@@ -325,7 +712,7 @@ func loadEcoCatalog(subsidiary string) (*Catalog, error) {
 
 	var catalog Catalog
 	if err := json.Unmarshal(data, &catalog); err != nil {
-		return nil, fmt.Errorf("failed to parse catalog: %w", err)
+		return nil, fmt.Errorf("failed to parse %s catalog: %w", name, err)
 	}
 
 	return &catalog, nil
@@ -369,7 +756,7 @@ func indexCatalog(catalog *Catalog) (map[string]*Plan, map[string]*Plan) {
 	return plans, addons
 }
 
-// priceForPlan extracts monthly rental price from plan
+// priceForPlan extracts monthly rental price and one-time setup fee from plan
 // OVH prices are in micro-units: divide by 100000000 to get actual price
 //
 // Parameters:
@@ -378,16 +765,26 @@ func indexCatalog(catalog *Catalog) (map[string]*Plan, map[string]*Plan) {
 //
 // Returns:
 //   - float64: Monthly price in actual currency units
+//   - float64: Setup fee in actual currency units (0 if the plan has none, see isSetupPricing)
 //   - string: Currency code
 //   - error: If no monthly price found
-func priceForPlan(plan *Plan, catalogCurrency string) (float64, string, error) {
+func priceForPlan(plan *Plan, catalogCurrency string) (float64, float64, string, error) {
+	// The setup fee is optional: most plans have none
+	setupFee := 0.0
+	for _, pr := range plan.Pricings {
+		if isSetupPricing(pr) {
+			setupFee = float64(pr.Price) / 100000000.0
+			break
+		}
+	}
+
 	// Look for monthly rental pricing (interval=1, intervalUnit="month")
 	for _, pr := range plan.Pricings {
 		if pr.Interval == 1 && pr.IntervalUnit == "month" {
 			// Convert from micro-units to actual currency
 			// For GBP/EUR/USD: divide by 100000000 (100 cents * 1000000 micro)
 			priceActual := float64(pr.Price) / 100000000.0
-			return priceActual, catalogCurrency, nil
+			return priceActual, setupFee, catalogCurrency, nil
 		}
 	}
 
@@ -395,11 +792,56 @@ func priceForPlan(plan *Plan, catalogCurrency string) (float64, string, error) {
 	for _, pr := range plan.Pricings {
 		if pr.Duration == "P1M" {
 			priceActual := float64(pr.Price) / 100000000.0
-			return priceActual, catalogCurrency, nil
+			return priceActual, setupFee, catalogCurrency, nil
 		}
 	}
 
-	return 0, "", fmt.Errorf("cannot extract monthly price for planCode=%s", plan.PlanCode)
+	return 0, 0, "", fmt.Errorf("cannot extract monthly price for planCode=%s", plan.PlanCode)
+}
+
+// isSetupPricing reports whether a pricing is a one-time setup (installation) fee
+// Setup fees are non-recurring phase-0 pricings: interval 0, unit "none" or
+// capacity "installation". The old API's phase-0 "P1M" entries are monthly, not setup.
+func isSetupPricing(pr Pricing) bool {
+	if pr.Phase != 0 || pr.Interval != 0 || pr.Duration == "P1M" {
+		return false
+	}
+	return pr.IntervalUnit == "none" || slices.Contains(pr.Capacities, "installation")
+}
+
+// addonSuffixPattern matches plan-specific addon suffixes like "-24rise" or "-24sk-v2"
+var addonSuffixPattern = regexp.MustCompile(`-\d{2}[a-z]+\d*(-v\d+)?$`)
+
+// addonMatchesFQN reports whether an addon code is compatible with a server FQN
+// Tries an exact match first, then the addon code without its plan-specific suffix
+//
+// Parameters:
+//   - opt: Addon plan code (e.g., "ram-32g-ecc-2400-24rise")
+//   - fqn: Fully qualified name of the server
+//
+// Returns:
+//   - bool: True if the FQN mentions the addon
+func addonMatchesFQN(opt, fqn string) bool {
+	if opt == "" {
+		return false
+	}
+
+	// Try exact match first
+	if contains(fqn, opt) {
+		return true
+	}
+
+	// Try matching without plan-specific suffix
+	optBase := addonSuffixPattern.ReplaceAllString(opt, "")
+	return optBase != opt && contains(fqn, optBase)
+}
+
+// mandatoryFamilyName returns the family name used as a key in Offer.Addons
+func mandatoryFamilyName(fam AddonFamily) string {
+	if fam.Name == "" {
+		return "unknown"
+	}
+	return fam.Name
 }
 
 // pickMandatoryAddonsForFQN selects mandatory addons for a server
@@ -413,37 +855,90 @@ func priceForPlan(plan *Plan, catalogCurrency string) (float64, string, error) {
 //   - map[string]string: Map of family name to selected addon code
 func pickMandatoryAddonsForFQN(plan *Plan, fqn string) map[string]string {
 	result := make(map[string]string)
-	suffixPattern := regexp.MustCompile(`-\d{2}[a-z]+\d*(-v\d+)?$`)
 
 	for _, fam := range plan.AddonFamilies {
 		if !fam.Mandatory {
 			continue
 		}
 
-		famName := fam.Name
-		if famName == "" {
-			famName = "unknown"
-		}
-
 		var chosen string
 
 		// Try to match addon codes to FQN
 		for _, opt := range fam.Addons {
-			if opt == "" {
-				continue
-			}
-
-			// Try exact match first
-			if contains(fqn, opt) {
+			if addonMatchesFQN(opt, fqn) {
 				chosen = opt
 				break
 			}
+		}
+
+		if chosen == "" {
+			chosen = fam.Default
+		}
+
+		if chosen != "" {
+			result[mandatoryFamilyName(fam)] = chosen
+		}
+	}
+
+	return result
+}
+
+// pickCheapestMandatoryAddons selects the cheapest mandatory addon in each family
+//
+// Candidates are the family's FQN-compatible addons (see addonMatchesFQN).
+// If the FQN doesn't mention any of them, every addon in the family is a
+// candidate. Addons missing from the catalog or without a monthly price are
+// skipped; if none can be priced, the family default is used as in
+// pickMandatoryAddonsForFQN.
+//
+// Parameters:
+//   - plan: The plan with addon families
+//   - fqn: Fully qualified name of the server
+//   - addonsIdx: Indexed addons map (for prices)
+//   - catalogCurrency: Currency code
+//
+// Returns:
+//   - map[string]string: Map of family name to selected addon code
+func pickCheapestMandatoryAddons(
+	plan *Plan,
+	fqn string,
+	addonsIdx map[string]*Plan,
+	catalogCurrency string,
+) map[string]string {
+	result := make(map[string]string)
+
+	for _, fam := range plan.AddonFamilies {
+		if !fam.Mandatory {
+			continue
+		}
+
+		// Step 1: Narrow candidates to addons the FQN is compatible with
+		var candidates []string
+		for _, opt := range fam.Addons {
+			if addonMatchesFQN(opt, fqn) {
+				candidates = append(candidates, opt)
+			}
+		}
+		if len(candidates) == 0 {
+			candidates = fam.Addons
+		}
 
-			// Try matching without plan-specific suffix
-			optBase := suffixPattern.ReplaceAllString(opt, "")
-			if optBase != opt && contains(fqn, optBase) {
+		// Step 2: Keep the cheapest priceable candidate
+		// Strict < keeps catalog order for addons with equal price
+		chosen := ""
+		cheapest := 0.0
+		for _, opt := range candidates {
+			addonObj, ok := addonsIdx[opt]
+			if !ok {
+				continue
+			}
+			price, _, _, err := priceForPlan(addonObj, catalogCurrency)
+			if err != nil {
+				continue
+			}
+			if chosen == "" || price < cheapest {
 				chosen = opt
-				break
+				cheapest = price
 			}
 		}
 
@@ -452,7 +947,7 @@ func pickMandatoryAddonsForFQN(plan *Plan, fqn string) map[string]string {
 		}
 
 		if chosen != "" {
-			result[famName] = chosen
+			result[mandatoryFamilyName(fam)] = chosen
 		}
 	}
 
@@ -473,7 +968,9 @@ func contains(s, substr string) bool {
 }
 
 // computeTotalMonthly computes total monthly price for a server offer
-// Includes base price + all mandatory addon prices
+// Includes base price + all mandatory addon prices; the one-time setup fees
+// of the plan and those addons are summed separately into Offer.SetupFee
+// Also parses the hardware specs from the invoice name and FQN (see ParsePlanSpecs)
 //
 // Parameters:
 //   - plansIdx: Indexed plans map
@@ -481,27 +978,26 @@ func contains(s, substr string) bool {
 //   - planCode: Plan code to price
 //   - fqn: Fully qualified name
 //   - catalogCurrency: Currency code
+//   - strategy: How to choose one addon per mandatory family
 //
 // Returns:
-//   - float64: Total monthly price
-//   - string: Currency code
-//   - string: Invoice name
-//   - map[string]string: Selected mandatory addons
+//   - Offer: Priced offer (every field except Datacenter, which the caller knows)
 //   - error: Any errors during pricing
 func computeTotalMonthly(
 	plansIdx map[string]*Plan,
 	addonsIdx map[string]*Plan,
 	planCode, fqn, catalogCurrency string,
-) (float64, string, string, map[string]string, error) {
+	strategy AddonStrategy,
+) (Offer, error) {
 
 	plan, ok := plansIdx[planCode]
 	if !ok {
-		return 0, "", "", nil, fmt.Errorf("planCode not found in catalog: %s", planCode)
+		return Offer{}, fmt.Errorf("planCode not found in catalog: %s", planCode)
 	}
 
-	basePrice, currency, err := priceForPlan(plan, catalogCurrency)
+	basePrice, setupFee, currency, err := priceForPlan(plan, catalogCurrency)
 	if err != nil {
-		return 0, "", "", nil, err
+		return Offer{}, err
 	}
 
 	invoiceName := plan.InvoiceName
@@ -512,7 +1008,13 @@ func computeTotalMonthly(
 		invoiceName = plan.PlanCode
 	}
 
-	mandatoryAddons := pickMandatoryAddonsForFQN(plan, fqn)
+	var mandatoryAddons map[string]string
+	switch strategy {
+	case AddonsCheapest:
+		mandatoryAddons = pickCheapestMandatoryAddons(plan, fqn, addonsIdx, catalogCurrency)
+	default:
+		mandatoryAddons = pickMandatoryAddonsForFQN(plan, fqn)
+	}
 
 	total := basePrice
 	for _, addonCode := range mandatoryAddons {
@@ -521,12 +1023,22 @@ func computeTotalMonthly(
 			continue
 		}
 
-		addonPrice, _, err := priceForPlan(addonObj, catalogCurrency)
+		addonPrice, addonSetupFee, _, err := priceForPlan(addonObj, catalogCurrency)
 		if err != nil {
 			continue
 		}
 		total += addonPrice
+		setupFee += addonSetupFee
 	}
 
-	return total, currency, invoiceName, mandatoryAddons, nil
+	return Offer{
+		FQN:         fqn,
+		PlanCode:    planCode,
+		Price:       total,
+		SetupFee:    setupFee,
+		Currency:    currency,
+		InvoiceName: invoiceName,
+		Addons:      mandatoryAddons,
+		Specs:       ParsePlanSpecs(invoiceName, fqn),
+	}, nil
 }
@@ -1,8 +1,21 @@
 package ovh
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/status"
 )
 
 // TestFormatOfferForTelegram tests the Telegram message formatting
@@ -57,6 +70,37 @@ func TestFormatOfferForTelegram(t *testing.T) {
 				"server\\.test\\-2023\\.v1", // Escaped FQN
 			},
 		},
+		{
+			name: "offer with a setup fee",
+			offer: Offer{
+				FQN:         "25skle01.fqn",
+				PlanCode:    "25skle01",
+				Price:       15.99,
+				SetupFee:    12,
+				Currency:    "EUR",
+				InvoiceName: "KS-LE-1",
+			},
+			index: 2,
+			expectedContains: []string{
+				"*15\\.99 EUR/mo* \\+ 12\\.00 setup \\- KS\\-LE\\-1", // Setup fee after the monthly price, + escaped
+			},
+		},
+		{
+			name: "offer with datacenter and delivery time",
+			offer: Offer{
+				FQN:          "24ska01.fqn",
+				PlanCode:     "24ska01",
+				Price:        9.99,
+				Currency:     "EUR",
+				InvoiceName:  "KS-A",
+				Datacenter:   "lon",
+				Availability: "72H",
+			},
+			index: 1,
+			expectedContains: []string{
+				"24ska01\\.fqn · London, UK · delivery 3 days", // Delivery bucket after the datacenter
+			},
+		},
 		{
 			name: "offer with dots and dashes in name",
 			offer: Offer{
@@ -84,6 +128,11 @@ func TestFormatOfferForTelegram(t *testing.T) {
 				t.Errorf("FormatOfferForTelegram() returned empty string")
 			}
 
+			// Only offers with a setup fee mention it
+			if got := strings.Contains(result, "setup"); got != (tt.offer.SetupFee > 0) {
+				t.Errorf("FormatOfferForTelegram() mentions setup = %v, want %v\nGot: %s", got, tt.offer.SetupFee > 0, result)
+			}
+
 			// Verify all expected strings are present
 			for _, expected := range tt.expectedContains {
 				if !strings.Contains(result, expected) {
@@ -103,84 +152,31 @@ func TestFormatOfferForTelegram(t *testing.T) {
 	}
 }
 
-// TestEscapeMarkdownV2 tests the MarkdownV2 escaping function
-// MarkdownV2 requires escaping many special characters
+// BenchmarkFormatOfferForTelegram measures per-offer formatting cost,
+// including MarkdownV2 escaping of the name and FQN (tgfmt.EscapeMarkdownV2)
 //
-// Testing strategy:
-//   - Test each special character individually
-//   - Test combinations of special characters
-//   - Test normal text (should remain unchanged)
-//   - Test empty string
-func TestEscapeMarkdownV2(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "normal text without special chars",
-			input:    "Hello World",
-			expected: "Hello World",
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: "",
-		},
-		{
-			name:     "dash character",
-			input:    "test-name",
-			expected: "test\\-name",
-		},
-		{
-			name:     "dot character",
-			input:    "test.name",
-			expected: "test\\.name",
-		},
-		{
-			name:     "parentheses",
-			input:    "server (2023)",
-			expected: "server \\(2023\\)",
-		},
-		{
-			name:     "underscore",
-			input:    "test_name",
-			expected: "test\\_name",
-		},
-		{
-			name:     "asterisk",
-			input:    "test*name",
-			expected: "test\\*name",
-		},
-		{
-			name:     "square brackets",
-			input:    "test[name]",
-			expected: "test\\[name\\]",
-		},
-		{
-			name:     "multiple special characters",
-			input:    "server-name.test (v1.0)",
-			expected: "server\\-name\\.test \\(v1\\.0\\)",
-		},
-		{
-			name:     "all special characters",
-			input:    "_*[]()~`>#+-=|{}.!",
-			expected: "\\_\\*\\[\\]\\(\\)\\~\\`\\>\\#\\+\\-\\=\\|\\{\\}\\.\\!",
+// Cases:
+//   - plain: few characters to escape (typical offer)
+//   - escape-heavy: every other character needs a backslash
+//
+// Run with: go test -bench=FormatOfferForTelegram -benchmem ./ovh
+func BenchmarkFormatOfferForTelegram(b *testing.B) {
+	offers := map[string]Offer{
+		"plain": {
+			FQN: "24ska01.ram-64g-ecc-2133.softraid-2x2000sa", Price: 15.99, Currency: "GBP",
+			InvoiceName: "KS-1 | Intel i7-6700k", Datacenter: "lon",
 		},
-		{
-			name:     "FQN with dots and dashes",
-			input:    "1801sk12.ram.1-v2",
-			expected: "1801sk12\\.ram\\.1\\-v2",
+		"escape-heavy": {
+			FQN: "a.b-c.d_e.f-g.h_i.j-k.l_m.n-o.p", Price: 1234.5, Currency: "EUR",
+			InvoiceName: "(KS-1) [x.y] {z!} #1 +2 =3", Datacenter: "rbx",
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := escapeMarkdownV2(tt.input)
-
-			if result != tt.expected {
-				t.Errorf("escapeMarkdownV2(%q) = %q, want %q",
-					tt.input, result, tt.expected)
+	for name, offer := range offers {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				FormatOfferForTelegram(offer, 12)
 			}
 		})
 	}
@@ -344,6 +340,7 @@ func TestPriceForPlan(t *testing.T) {
 		plan          *Plan
 		currency      string
 		expectedPrice float64
+		expectedSetup float64
 		expectError   bool
 	}{
 		{
@@ -377,6 +374,19 @@ func TestPriceForPlan(t *testing.T) {
 			expectedPrice: 9.99,
 			expectError:   false,
 		},
+		{
+			name: "plan with setup and monthly phases",
+			plan: &Plan{
+				PlanCode: "test",
+				Pricings: []Pricing{
+					{Phase: 0, Capacities: []string{"installation"}, IntervalUnit: "none", Price: 1200000000},
+					{Phase: 1, Capacities: []string{"renew"}, Interval: 1, IntervalUnit: "month", Price: 1599000000},
+				},
+			},
+			currency:      "EUR",
+			expectedPrice: 15.99,
+			expectedSetup: 12,
+		},
 		{
 			name: "plan without monthly pricing",
 			plan: &Plan{
@@ -397,7 +407,7 @@ func TestPriceForPlan(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			price, currency, err := priceForPlan(tt.plan, tt.currency)
+			price, setup, currency, err := priceForPlan(tt.plan, tt.currency)
 
 			if tt.expectError {
 				if err == nil {
@@ -413,6 +423,10 @@ func TestPriceForPlan(t *testing.T) {
 					t.Errorf("priceForPlan() = %.2f, want %.2f", price, tt.expectedPrice)
 				}
 
+				if setup != tt.expectedSetup {
+					t.Errorf("priceForPlan() setup = %.2f, want %.2f", setup, tt.expectedSetup)
+				}
+
 				if currency != tt.currency {
 					t.Errorf("currency = %q, want %q", currency, tt.currency)
 				}
@@ -473,6 +487,139 @@ func TestContains(t *testing.T) {
 	}
 }
 
+// newLatencyServer starts a local OVH API stand-in that answers both
+// endpoints used by loadOVHData after a fixed delay.
+// apiBase is pointed at the server and restored when the test finishes.
+//
+// Parameters:
+//   - tb: testing.T or testing.B (both implement testing.TB)
+//   - latency: Delay before each response (simulates a slow network)
+//   - hits: Optional counter incremented on every request (may be nil)
+//
+// Returns:
+//   - *httptest.Server: Running server (closed automatically via Cleanup)
+func newLatencyServer(tb testing.TB, latency time.Duration, hits *int32) *httptest.Server {
+	tb.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dedicated/server/datacenter/availabilities", func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			atomic.AddInt32(hits, 1)
+		}
+		time.Sleep(latency)
+		_, _ = w.Write([]byte(`[{"fqn":"a.fqn","planCode":"plan-a","datacenters":[{"datacenter":"lon","availability":"1H"}]}]`))
+	})
+	mux.HandleFunc("/order/catalog/public/eco", func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			atomic.AddInt32(hits, 1)
+		}
+		time.Sleep(latency)
+		_, _ = w.Write([]byte(`{"locale":{"currencyCode":"EUR"},"plans":[{"planCode":"plan-a","pricings":[{"interval":1,"intervalUnit":"month","price":1000000000}]}]}`))
+	})
+
+	server := httptest.NewServer(mux)
+
+	oldBase := apiBase
+	apiBase = server.URL
+	dataCache.reset()
+
+	tb.Cleanup(func() {
+		server.Close()
+		apiBase = oldBase
+		dataCache.reset()
+	})
+
+	return server
+}
+
+// TestLoadOVHData tests parallel loading and caching
+//
+// What we're testing:
+//   - Both endpoints are fetched and parsed
+//   - Both requests run in parallel (total time ~ one latency, not two)
+//   - Second call is served from cache (no new HTTP requests)
+func TestLoadOVHData(t *testing.T) {
+	var hits int32
+	latency := 100 * time.Millisecond
+	newLatencyServer(t, latency, &hits)
+
+	start := time.Now()
+	availabilities, catalog, _, err := loadOVHData(context.Background(), catalogEco, "FR")
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("loadOVHData() unexpected error: %v", err)
+	}
+	if len(availabilities) != 1 || catalog == nil || len(catalog.Plans) != 1 {
+		t.Fatalf("loadOVHData() = %d availabilities, catalog %+v", len(availabilities), catalog)
+	}
+
+	// Sequential loading would take at least 2 * latency
+	if elapsed >= 2*latency {
+		t.Errorf("loadOVHData() took %v, expected parallel fetch under %v", elapsed, 2*latency)
+	}
+
+	// Second call must hit the cache
+	if _, _, _, err := loadOVHData(context.Background(), catalogEco, "FR"); err != nil {
+		t.Fatalf("loadOVHData() second call error: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("OVH API hit %d times, want 2 (second call should use cache)", got)
+	}
+}
+
+// TestLoadOVHData_Error tests that a failing endpoint returns an error
+func TestLoadOVHData_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	oldBase := apiBase
+	apiBase = server.URL
+	dataCache.reset()
+	defer func() {
+		apiBase = oldBase
+		dataCache.reset()
+	}()
+
+	if _, _, _, err := loadOVHData(context.Background(), catalogEco, "FR"); err == nil {
+		t.Errorf("loadOVHData() expected error for HTTP 500, got nil")
+	}
+}
+
+// BenchmarkLoadOVHData compares sequential and parallel fetching
+// Each simulated endpoint takes 20ms, so:
+//   - sequential: ~40ms per operation
+//   - parallel:   ~20ms per operation
+//
+// Run with: go test -bench=LoadOVHData ./ovh
+func BenchmarkLoadOVHData(b *testing.B) {
+	newLatencyServer(b, 20*time.Millisecond, nil)
+	ctx := context.Background()
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := loadAvailabilities(ctx); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := loadEcoCatalog(ctx, "FR"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			// Reset cache so every iteration performs real requests
+			dataCache.reset()
+			if _, _, _, err := loadOVHData(ctx, catalogEco, "FR"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 // Note on integration testing:
 // We don't test GetTopOffers() directly because it requires:
 //   - Real OVH API calls (network dependency)
@@ -490,3 +637,424 @@ func TestContains(t *testing.T) {
 //   3. Message formatting for Telegram
 //
 // Manual testing with real API is done through the bot's /ovh command.
+
+// TestGetTopOffers_MockServer tests GetTopOffers end-to-end against NewMockServer
+//
+// Data:
+//   - ks-a (10 EUR) and ks-b (5 EUR + 2 EUR mandatory bandwidth addon) in lon
+//   - ks-c (20 EUR) in lon and rbx
+//   - ks-d (1 EUR) unavailable in lon
+//   - ks-e (2 EUR) coming soon in lon (listed only with WithComingSoon)
+//   - ks-f (3 EUR) with an availability value OVH never sent before (never listed)
+//   - ks-x is available but missing from the catalog (skipped)
+func TestGetTopOffers_MockServer(t *testing.T) {
+	availability := []Availability{
+		{FQN: "ks-a.fqn", PlanCode: "ks-a", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "1H"}}},
+		{FQN: "ks-b.bandwidth-300", PlanCode: "ks-b", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "72H"}}},
+		{FQN: "ks-c.fqn", PlanCode: "ks-c", Datacenters: []Datacenter{
+			{Datacenter: "lon", Availability: "1H"},
+			{Datacenter: "rbx", Availability: "1H"},
+		}},
+		{FQN: "ks-d.fqn", PlanCode: "ks-d", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "unavailable"}}},
+		{FQN: "ks-e.fqn", PlanCode: "ks-e", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "comingSoon"}}},
+		{FQN: "ks-f.fqn", PlanCode: "ks-f", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "restocking"}}},
+		{FQN: "ks-x.fqn", PlanCode: "ks-x", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "1H"}}},
+	}
+	catalog := &Catalog{
+		Locale: Locale{CurrencyCode: "EUR", Subsidiary: "FR"},
+		Plans: []Plan{
+			{PlanCode: "ks-a", InvoiceName: "KS-A", Pricings: monthlyPricing(10)},
+			{PlanCode: "ks-b", InvoiceName: "KS-B", Pricings: monthlyPricing(5), AddonFamilies: []AddonFamily{
+				{Name: "bandwidth", Mandatory: true, Addons: []string{"bandwidth-300"}},
+			}},
+			{PlanCode: "ks-c", InvoiceName: "KS-C", Pricings: monthlyPricing(20)},
+			{PlanCode: "ks-d", InvoiceName: "KS-D", Pricings: monthlyPricing(1)},
+			{PlanCode: "ks-e", InvoiceName: "KS-E", Pricings: monthlyPricing(2)},
+			{PlanCode: "ks-f", InvoiceName: "KS-F", Pricings: monthlyPricing(3)},
+		},
+		Addons: []Plan{
+			{PlanCode: "bandwidth-300", Pricings: monthlyPricing(2)},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantPlans []string
+	}{
+		{name: "defaults", wantPlans: []string{"ks-b", "ks-a", "ks-c"}},
+		{name: "top 2", opts: []Option{WithTop(2)}, wantPlans: []string{"ks-b", "ks-a"}},
+		{name: "descending", opts: []Option{WithSortOrder(SortByPriceDesc)}, wantPlans: []string{"ks-c", "ks-a", "ks-b"}},
+		{name: "max price includes addons", opts: []Option{WithMaxPrice(7)}, wantPlans: []string{"ks-b"}},
+		{name: "min price", opts: []Option{WithMinPrice(8)}, wantPlans: []string{"ks-a", "ks-c"}},
+		{name: "other datacenter", opts: []Option{WithDatacenter("rbx")}, wantPlans: []string{"ks-c"}},
+		{name: "no stock", opts: []Option{WithDatacenter("gra")}, wantPlans: []string{}},
+		{name: "coming soon included", opts: []Option{WithComingSoon(true)}, wantPlans: []string{"ks-e", "ks-b", "ks-a"}},
+		{name: "keyword in FQN", opts: []Option{WithKeyword("Bandwidth")}, wantPlans: []string{"ks-b"}},
+		{name: "keyword in plan code", opts: []Option{WithKeyword("ks-c")}, wantPlans: []string{"ks-c"}},
+		{name: "keyword matches nothing", opts: []Option{WithKeyword("nvme")}, wantPlans: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NewMockServer(t, availability, catalog)
+
+			offers, err := GetTopOffersContext(context.Background(), tt.opts...)
+			if err != nil {
+				t.Fatalf("GetTopOffersContext() unexpected error: %v", err)
+			}
+
+			got := make([]string, len(offers))
+			for i, offer := range offers {
+				got[i] = offer.PlanCode
+			}
+			if !slices.Equal(got, tt.wantPlans) {
+				t.Errorf("GetTopOffersContext() plans = %v, want %v", got, tt.wantPlans)
+			}
+		})
+	}
+
+	// Check one offer in full: price includes the mandatory addon
+	NewMockServer(t, availability, catalog)
+	offers, err := GetTopOffers(WithTop(1))
+	if err != nil {
+		t.Fatalf("GetTopOffers() unexpected error: %v", err)
+	}
+	want := Offer{FQN: "ks-b.bandwidth-300", PlanCode: "ks-b", Price: 7, Currency: "EUR", InvoiceName: "KS-B",
+		Datacenter: "lon", Availability: "72H", Addons: map[string]string{"bandwidth": "bandwidth-300"}}
+	if len(offers) != 1 || !reflect.DeepEqual(offers[0], want) {
+		t.Errorf("GetTopOffers(WithTop(1)) = %+v, want [%+v]", offers, want)
+	}
+}
+
+// TestComputeTotalMonthly_AddonStrategy tests both addon selection strategies
+//
+// Data:
+//   - ks-r costs 10 EUR and has a mandatory RAM family with two priceable addons:
+//     ram-32g (6 EUR, family default) and ram-16g (3 EUR)
+//   - A mandatory bandwidth family whose only addon is missing from the catalog
+//
+// Testing strategy:
+//   - FQNMatch picks the addon named in the FQN, else the default
+//   - Cheapest picks the cheapest FQN-compatible addon, else the cheapest overall
+func TestComputeTotalMonthly_AddonStrategy(t *testing.T) {
+	plans, addons := indexCatalog(&Catalog{
+		Plans: []Plan{
+			{PlanCode: "ks-r", InvoiceName: "KS-R", Pricings: monthlyPricing(10), AddonFamilies: []AddonFamily{
+				{Name: "memory", Mandatory: true, Addons: []string{"ram-32g-24ks", "ram-16g-24ks"}, Default: "ram-32g-24ks"},
+				{Name: "bandwidth", Mandatory: true, Addons: []string{"bandwidth-100"}, Default: "bandwidth-100"},
+				{Name: "backup", Addons: []string{"backup-free"}},
+			}},
+		},
+		Addons: []Plan{
+			{PlanCode: "ram-32g-24ks", Pricings: monthlyPricing(6)},
+			{PlanCode: "ram-16g-24ks", Pricings: monthlyPricing(3)},
+		},
+	})
+
+	tests := []struct {
+		name      string
+		fqn       string
+		strategy  AddonStrategy
+		wantPrice float64
+		wantRAM   string
+	}{
+		{name: "fqn match, ram in fqn", fqn: "ks-r.ram-16g", strategy: AddonsFQNMatch, wantPrice: 13, wantRAM: "ram-16g-24ks"},
+		{name: "fqn match, default", fqn: "ks-r.fqn", strategy: AddonsFQNMatch, wantPrice: 16, wantRAM: "ram-32g-24ks"},
+		{name: "cheapest, ram in fqn", fqn: "ks-r.ram-32g", strategy: AddonsCheapest, wantPrice: 16, wantRAM: "ram-32g-24ks"},
+		{name: "cheapest, no ram in fqn", fqn: "ks-r.fqn", strategy: AddonsCheapest, wantPrice: 13, wantRAM: "ram-16g-24ks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offer, err := computeTotalMonthly(plans, addons, "ks-r", tt.fqn, "EUR", tt.strategy)
+			if err != nil {
+				t.Fatalf("computeTotalMonthly() unexpected error: %v", err)
+			}
+			if offer.Price != tt.wantPrice || offer.Currency != "EUR" {
+				t.Errorf("computeTotalMonthly() price = %v %s, want %v EUR", offer.Price, offer.Currency, tt.wantPrice)
+			}
+
+			// Unpriceable bandwidth falls back to the default, optional backup is never picked
+			want := map[string]string{"memory": tt.wantRAM, "bandwidth": "bandwidth-100"}
+			if !reflect.DeepEqual(offer.Addons, want) {
+				t.Errorf("computeTotalMonthly() addons = %v, want %v", offer.Addons, want)
+			}
+		})
+	}
+}
+
+// setupFeeCatalog is an ECO catalog fixture in the API's JSON layout:
+// ks-setup has both phases (12 EUR setup, then 15.99 EUR/month) and a
+// mandatory RAM addon with its own 3 EUR setup fee; ks-plain has no setup pricing.
+const setupFeeCatalog = `{
+	"locale": {"currencyCode": "EUR", "subsidiary": "FR"},
+	"plans": [
+		{"planCode": "ks-setup", "invoiceName": "KS-SETUP",
+		 "addonFamilies": [{"name": "memory", "mandatory": true, "addons": ["ram-32g-ks"], "default": "ram-32g-ks"}],
+		 "pricings": [
+			{"phase": 0, "capacities": ["installation"], "interval": 0, "intervalUnit": "none", "price": 1200000000},
+			{"phase": 1, "capacities": ["renew"], "interval": 1, "intervalUnit": "month", "price": 1599000000}
+		]},
+		{"planCode": "ks-plain", "invoiceName": "KS-PLAIN",
+		 "pricings": [{"phase": 1, "capacities": ["renew"], "interval": 1, "intervalUnit": "month", "price": 900000000}]}
+	],
+	"addons": [
+		{"planCode": "ram-32g-ks",
+		 "pricings": [
+			{"phase": 0, "capacities": ["installation"], "interval": 0, "intervalUnit": "none", "price": 300000000},
+			{"phase": 1, "capacities": ["renew"], "interval": 1, "intervalUnit": "month", "price": 400000000}
+		]}
+	]
+}`
+
+// TestComputeTotalMonthly_SetupFee tests that setup fees are summed apart
+// from the monthly price, plan plus mandatory addons
+func TestComputeTotalMonthly_SetupFee(t *testing.T) {
+	var catalog Catalog
+	if err := json.Unmarshal([]byte(setupFeeCatalog), &catalog); err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	plans, addons := indexCatalog(&catalog)
+
+	tests := []struct {
+		planCode  string
+		wantPrice float64
+		wantSetup float64
+	}{
+		{planCode: "ks-setup", wantPrice: 19.99, wantSetup: 15},
+		{planCode: "ks-plain", wantPrice: 9, wantSetup: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.planCode, func(t *testing.T) {
+			offer, err := computeTotalMonthly(plans, addons, tt.planCode, tt.planCode+".fqn", "EUR", AddonsFQNMatch)
+			if err != nil {
+				t.Fatalf("computeTotalMonthly() unexpected error: %v", err)
+			}
+			if math.Abs(offer.Price-tt.wantPrice) > 0.001 || math.Abs(offer.SetupFee-tt.wantSetup) > 0.001 {
+				t.Errorf("computeTotalMonthly() = %.2f/mo + %.2f setup, want %.2f/mo + %.2f setup",
+					offer.Price, offer.SetupFee, tt.wantPrice, tt.wantSetup)
+			}
+
+			line := FormatOfferForTelegram(offer, 1)
+			if got := strings.Contains(line, "setup"); got != (tt.wantSetup > 0) {
+				t.Errorf("FormatOfferForTelegram() mentions setup = %v, want %v\nGot: %s", got, tt.wantSetup > 0, line)
+			}
+		})
+	}
+}
+
+// TestGetTopOffers_MockServerCatalogError tests that a missing catalog surfaces as an error
+func TestGetTopOffers_MockServerCatalogError(t *testing.T) {
+	NewMockServer(t, []Availability{{FQN: "ks-a.fqn", PlanCode: "ks-a"}}, nil)
+
+	if _, err := GetTopOffers(); err == nil {
+		t.Errorf("GetTopOffers() expected error for missing catalog, got nil")
+	}
+}
+
+// TestGetTopOffers_RecordsOVHFetch tests that a real fetch updates status.Default
+func TestGetTopOffers_RecordsOVHFetch(t *testing.T) {
+	NewMockServer(t, nil, &Catalog{})
+
+	before := time.Now().Truncate(time.Second)
+	if _, err := GetTopOffers(); err != nil {
+		t.Fatalf("GetTopOffers() unexpected error: %v", err)
+	}
+
+	got := status.Default.Snapshot(time.Now()).LastOVHFetch
+	if got == nil || got.Before(before) {
+		t.Errorf("LastOVHFetch = %v, want >= %v", got, before)
+	}
+}
+
+// TestHTTPGet_Headers tests that every OVH request carries User-Agent and Accept headers
+func TestHTTPGet_Headers(t *testing.T) {
+	var gotUA, gotAccept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		gotAccept = r.Header.Get("Accept")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	// Default: descriptive UA naming the bot
+	if !strings.HasPrefix(UserAgent, "run-tbot/") {
+		t.Errorf("default UserAgent = %q, want run-tbot/<version> prefix", UserAgent)
+	}
+
+	oldUA := UserAgent
+	UserAgent = "run-tbot-test/1.0"
+	defer func() { UserAgent = oldUA }()
+
+	if _, err := httpGet(context.Background(), EndpointAvailabilities, server.URL, map[string]string{"planCode": "ks-a"}); err != nil {
+		t.Fatalf("httpGet() unexpected error: %v", err)
+	}
+	if gotUA != "run-tbot-test/1.0" {
+		t.Errorf("User-Agent = %q, want %q", gotUA, "run-tbot-test/1.0")
+	}
+	if gotAccept != "application/json" {
+		t.Errorf("Accept = %q, want %q", gotAccept, "application/json")
+	}
+}
+
+// TestHTTPGet_Gzip tests that gzip-encoded responses are decompressed
+//
+// Cases:
+//   - gzip body with Content-Encoding: gzip - decompressed
+//   - plain body (server ignores Accept-Encoding) - returned as-is
+//   - Content-Encoding: gzip with a non-gzip body - error
+func TestHTTPGet_Gzip(t *testing.T) {
+	const payload = `[{"fqn":"ks-a.fqn","planCode":"ks-a"}]`
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name: "gzipped JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Accept-Encoding") != "gzip" {
+					t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				gz := gzip.NewWriter(w)
+				gz.Write([]byte(payload))
+				gz.Close()
+			},
+		},
+		{
+			name: "plain JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(payload))
+			},
+		},
+		{
+			name: "corrupt gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write([]byte(payload))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			body, err := httpGet(context.Background(), EndpointAvailabilities, server.URL, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("httpGet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(body) != payload {
+				t.Errorf("httpGet() body = %q, want %q", body, payload)
+			}
+		})
+	}
+}
+
+// TestHTTPGet_Timeout tests that a slow server fails the request within RequestTimeout
+//
+// Cases:
+//   - RequestTimeout alone: the request fails with context.DeadlineExceeded
+//   - A caller's shorter deadline wins over RequestTimeout
+func TestHTTPGet_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		requestTimeout time.Duration
+		callerTimeout  time.Duration // 0: no caller deadline
+	}{
+		{name: "request timeout", requestTimeout: 50 * time.Millisecond},
+		{name: "caller deadline", requestTimeout: time.Minute, callerTimeout: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldTimeout := RequestTimeout
+			RequestTimeout = tt.requestTimeout
+			defer func() { RequestTimeout = oldTimeout }()
+
+			ctx := context.Background()
+			if tt.callerTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.callerTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			_, err := httpGet(ctx, EndpointAvailabilities, server.URL, nil)
+			elapsed := time.Since(start)
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("httpGet() error = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed > time.Second {
+				t.Errorf("httpGet() returned after %v, want about 50ms", elapsed)
+			}
+		})
+	}
+}
+
+// TestOffer_JSON tests the JSON keys of an Offer
+//
+// Checks:
+//   - Every field uses its snake_case key, none the Go field name
+//   - Addons is a JSON object (family -> addon code)
+//   - Marshal + Unmarshal returns the same offer
+func TestOffer_JSON(t *testing.T) {
+	offer := Offer{
+		FQN:         "24ska01.ram-32g",
+		PlanCode:    "24ska01",
+		Price:       15.99,
+		Currency:    "GBP",
+		InvoiceName: "KS-1",
+		Datacenter:  "lon",
+		Addons:      map[string]string{"memory": "ram-32g-24ska01", "storage": "softraid-2x2000sa-24ska01"},
+		Specs:       PlanSpecs{RAMGB: 32, CPUCores: 4, Storage: StorageSATA},
+	}
+
+	data, err := json.Marshal(offer)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		t.Fatalf("output is not a JSON object: %v", err)
+	}
+	for _, key := range []string{"fqn", "plan_code", "price", "currency", "invoice_name", "datacenter", "addons", "specs"} {
+		if _, ok := keys[key]; !ok {
+			t.Errorf("JSON %s is missing key %q", data, key)
+		}
+	}
+	for _, key := range []string{"FQN", "PlanCode", "InvoiceName", "Addons"} {
+		if _, ok := keys[key]; ok {
+			t.Errorf("JSON %s still has Go field name %q", data, key)
+		}
+	}
+
+	var addons map[string]string
+	if err := json.Unmarshal(keys["addons"], &addons); err != nil || !reflect.DeepEqual(addons, offer.Addons) {
+		t.Errorf("addons = %s, want the object %v", keys["addons"], offer.Addons)
+	}
+	if got, want := string(keys["specs"]), `{"ram_gb":32,"cpu_cores":4,"storage":"sata"}`; got != want {
+		t.Errorf("specs = %s, want %s", got, want)
+	}
+
+	var decoded Offer
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, offer) {
+		t.Errorf("round trip = %+v, %v; want %+v", decoded, err, offer)
+	}
+}
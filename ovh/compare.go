@@ -0,0 +1,99 @@
+package ovh
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// LoadAdvanceCatalog fetches the dedicated (Advance) catalog for a subsidiary
+// Endpoint: /order/catalog/public/dedicated
+//
+// OVH sells dedicated servers in two product lines with separate catalogs:
+//   - ECO (/order/catalog/public/eco): Kimsufi, So You Start, Rise - cheap, fewer guarantees
+//   - Dedicated (/order/catalog/public/dedicated): Advance and up - pricier, better hardware and SLA
+//
+// This always fetches: GetTopDedicatedOffers and CompareEcoAdvance go through
+// the cache instead (see cache.go).
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (e.g., "FR")
+//
+// Returns:
+//   - *Catalog: The catalog with plans and pricing
+//   - error: Any errors during fetch or parse
+func LoadAdvanceCatalog(subsidiary string) (*Catalog, error) {
+	return loadDedicatedCatalog(context.Background(), subsidiary)
+}
+
+// CompareEcoAdvance returns the top N offers from both the ECO and Advance catalogs
+// for one datacenter, so users can compare the two product lines side by side.
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (determines currency, e.g., "FR" for EUR)
+//   - datacenter: Datacenter code (e.g., "lon")
+//   - top: Max offers per catalog (0 = no limit)
+//
+// Returns:
+//   - eco: Cheapest ECO offers
+//   - advance: Cheapest Advance offers
+//   - err: First error from any request
+func CompareEcoAdvance(subsidiary, datacenter string, top int) (eco, advance []Offer, err error) {
+	return CompareEcoAdvanceContext(context.Background(), subsidiary, datacenter, top)
+}
+
+// CompareEcoAdvanceContext is CompareEcoAdvance with a context for cancellation
+//
+// All three requests (availabilities, ECO catalog, Advance catalog) are
+// independent, so they run concurrently in one errgroup:
+//   - Availabilities + ECO catalog come from loadOVHData (cached, see cache.go)
+//   - Advance catalog comes from loadCachedCatalog (cached separately)
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - subsidiary: OVH subsidiary code
+//   - datacenter: Datacenter code
+//   - top: Max offers per catalog (0 = no limit)
+//
+// Returns:
+//   - eco: Cheapest ECO offers
+//   - advance: Cheapest Advance offers
+//   - err: First error from any request (ctx.Err() if cancelled)
+func CompareEcoAdvanceContext(ctx context.Context, subsidiary, datacenter string, top int) (eco, advance []Offer, err error) {
+	options := newOptions(WithSubsidiary(subsidiary), WithDatacenter(datacenter), WithTop(top))
+
+	var (
+		availabilities []Availability
+		ecoCatalog     *Catalog
+		advanceCatalog *Catalog
+	)
+
+	// Step 1: Fetch everything in parallel
+	// Each goroutine writes only its own variables, so no data race
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		availabilities, ecoCatalog, _, err = loadOVHData(gctx, catalogEco, subsidiary)
+		return err
+	})
+
+	g.Go(func() error {
+		cat, err := loadCachedCatalog(gctx, catalogDedicated, subsidiary)
+		if err != nil {
+			return fmt.Errorf("failed to load advance catalog: %w", err)
+		}
+		advanceCatalog = cat
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	// Step 2: Price both catalogs against the same availabilities
+	return buildOffers(availabilities, ecoCatalog, options),
+		buildOffers(availabilities, advanceCatalog, options),
+		nil
+}
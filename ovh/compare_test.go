@@ -0,0 +1,150 @@
+package ovh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newCatalogServer starts a local OVH API stand-in with availabilities,
+// ECO and dedicated catalogs. apiBase is restored when the test finishes.
+//
+// Data:
+//   - eco-a (10 EUR) and eco-b (5 EUR) in the ECO catalog
+//   - adv-a (80 EUR) in the dedicated catalog
+//   - adv-b is in the dedicated catalog but unavailable in lon
+func newCatalogServer(t *testing.T, dedicatedStatus int) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dedicated/server/datacenter/availabilities", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"fqn":"eco-a.fqn","planCode":"eco-a","datacenters":[{"datacenter":"lon","availability":"1H"}]},
+			{"fqn":"eco-b.fqn","planCode":"eco-b","datacenters":[{"datacenter":"lon","availability":"72H"}]},
+			{"fqn":"adv-a.fqn","planCode":"adv-a","datacenters":[{"datacenter":"lon","availability":"1H"}]},
+			{"fqn":"adv-b.fqn","planCode":"adv-b","datacenters":[{"datacenter":"lon","availability":"unavailable"}]}
+		]`))
+	})
+	mux.HandleFunc("/order/catalog/public/eco", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"locale":{"currencyCode":"EUR"},"plans":[
+			{"planCode":"eco-a","invoiceName":"KS-A","pricings":[{"interval":1,"intervalUnit":"month","price":1000000000}]},
+			{"planCode":"eco-b","invoiceName":"KS-B","pricings":[{"interval":1,"intervalUnit":"month","price":500000000}]}
+		]}`))
+	})
+	mux.HandleFunc("/order/catalog/public/dedicated", func(w http.ResponseWriter, r *http.Request) {
+		if dedicatedStatus != http.StatusOK {
+			http.Error(w, "boom", dedicatedStatus)
+			return
+		}
+		_, _ = w.Write([]byte(`{"locale":{"currencyCode":"EUR"},"plans":[
+			{"planCode":"adv-a","invoiceName":"ADV-A","pricings":[{"interval":1,"intervalUnit":"month","price":8000000000}]},
+			{"planCode":"adv-b","invoiceName":"ADV-B","pricings":[{"interval":1,"intervalUnit":"month","price":9000000000}]}
+		]}`))
+	})
+
+	server := httptest.NewServer(mux)
+	oldBase := apiBase
+	apiBase = server.URL
+	dataCache.reset()
+
+	t.Cleanup(func() {
+		server.Close()
+		apiBase = oldBase
+		dataCache.reset()
+	})
+}
+
+// TestCompareEcoAdvance tests that each catalog only prices its own plans
+func TestCompareEcoAdvance(t *testing.T) {
+	newCatalogServer(t, http.StatusOK)
+
+	eco, advance, err := CompareEcoAdvance("FR", "lon", 3)
+	if err != nil {
+		t.Fatalf("CompareEcoAdvance() unexpected error: %v", err)
+	}
+
+	// ECO: both plans, cheapest first
+	if len(eco) != 2 || eco[0].PlanCode != "eco-b" || eco[1].PlanCode != "eco-a" {
+		t.Errorf("eco offers = %+v, want [eco-b eco-a]", eco)
+	}
+
+	// Advance: adv-b is unavailable in lon
+	if len(advance) != 1 || advance[0].PlanCode != "adv-a" || advance[0].Price != 80 {
+		t.Errorf("advance offers = %+v, want [adv-a at 80]", advance)
+	}
+}
+
+// TestCompareEcoAdvance_Error tests that a failing dedicated catalog fails the whole comparison
+func TestCompareEcoAdvance_Error(t *testing.T) {
+	newCatalogServer(t, http.StatusInternalServerError)
+
+	if _, _, err := CompareEcoAdvance("FR", "lon", 3); err == nil {
+		t.Errorf("CompareEcoAdvance() expected error when dedicated catalog fails, got nil")
+	}
+}
+
+// TestLoadAdvanceCatalog tests fetching the dedicated catalog endpoint
+func TestLoadAdvanceCatalog(t *testing.T) {
+	newCatalogServer(t, http.StatusOK)
+
+	catalog, err := LoadAdvanceCatalog("FR")
+	if err != nil {
+		t.Fatalf("LoadAdvanceCatalog() unexpected error: %v", err)
+	}
+	if len(catalog.Plans) != 2 || catalog.Plans[0].PlanCode != "adv-a" {
+		t.Errorf("LoadAdvanceCatalog() plans = %+v, want adv-a and adv-b", catalog.Plans)
+	}
+}
+
+// TestGetTopDedicatedOffers tests pricing against the dedicated catalog
+//
+// Cases:
+//   - Only available dedicated plans are returned (no ECO plans)
+//   - ECO offers are unaffected: each catalog has its own cache entry
+//   - A failing dedicated catalog returns an error
+func TestGetTopDedicatedOffers(t *testing.T) {
+	newCatalogServer(t, http.StatusOK)
+
+	offers, err := GetTopDedicatedOffers("FR", "lon", 3)
+	if err != nil {
+		t.Fatalf("GetTopDedicatedOffers() unexpected error: %v", err)
+	}
+	if len(offers) != 1 || offers[0].PlanCode != "adv-a" || offers[0].Price != 80 {
+		t.Errorf("dedicated offers = %+v, want [adv-a at 80]", offers)
+	}
+
+	eco, err := GetTopOffers(WithDatacenter("lon"))
+	if err != nil {
+		t.Fatalf("GetTopOffers() unexpected error: %v", err)
+	}
+	if len(eco) != 2 || eco[0].PlanCode != "eco-b" {
+		t.Errorf("eco offers = %+v, want [eco-b eco-a]", eco)
+	}
+
+	if _, dedicated := dataCache.get(catalogDedicated, "FR"); dedicated == nil || dedicated.Plans[0].PlanCode != "adv-a" {
+		t.Errorf("dedicated catalog not cached on its own: %+v", dedicated)
+	}
+}
+
+// TestGetTopDedicatedOffers_Error tests that a failing dedicated catalog is reported
+func TestGetTopDedicatedOffers_Error(t *testing.T) {
+	newCatalogServer(t, http.StatusInternalServerError)
+
+	if _, err := GetTopDedicatedOffers("FR", "lon", 3); err == nil {
+		t.Errorf("GetTopDedicatedOffers() expected error when dedicated catalog fails, got nil")
+	}
+}
+
+// TestGetTopDedicatedOffers_EmptyDatacenter tests that "" is not "all datacenters"
+// Availability is checked in exactly the given datacenter, so no offer matches.
+func TestGetTopDedicatedOffers_EmptyDatacenter(t *testing.T) {
+	newCatalogServer(t, http.StatusOK)
+
+	offers, err := GetTopDedicatedOffers("FR", "", 0)
+	if err != nil {
+		t.Fatalf("GetTopDedicatedOffers() unexpected error: %v", err)
+	}
+	if len(offers) != 0 {
+		t.Errorf("GetTopDedicatedOffers(datacenter \"\") = %+v, want no offers", offers)
+	}
+}
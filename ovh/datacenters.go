@@ -0,0 +1,103 @@
+package ovh
+
+import (
+	"sort"
+	"strings"
+)
+
+// DatacenterInfo describes an OVH datacenter in human-readable form
+type DatacenterInfo struct {
+	Code      string  // Datacenter code used by the OVH API (e.g., "lon")
+	Name      string  // Human-readable location (e.g., "London, UK")
+	Latitude  float64 // Approximate location of the city, for maps
+	Longitude float64
+}
+
+// datacenterNames maps OVH datacenter codes to human-readable locations
+// Codes come from Datacenter.Datacenter in the availabilities response
+// When OVH opens a new location, add it here
+var datacenterNames = map[string]string{
+	// Europe
+	"rbx": "Roubaix, France",
+	"gra": "Gravelines, France",
+	"sbg": "Strasbourg, France",
+	"par": "Paris, France",
+	"lon": "London, UK",
+	"eri": "Erith, UK",
+	"fra": "Frankfurt, Germany",
+	"lim": "Limburg, Germany",
+	"waw": "Warsaw, Poland",
+	"mil": "Milan, Italy",
+
+	// North America
+	"bhs": "Beauharnois, Canada",
+	"tor": "Toronto, Canada",
+	"vin": "Vint Hill, USA",
+	"hil": "Hillsboro, USA",
+
+	// Asia-Pacific
+	"sgp": "Singapore",
+	"syd": "Sydney, Australia",
+	"ynm": "Mumbai, India",
+}
+
+// datacenterCoordinates maps datacenter codes to city coordinates (latitude, longitude)
+// City-level precision is enough for a world map; keep in sync with datacenterNames
+var datacenterCoordinates = map[string][2]float64{
+	"rbx": {50.69, 3.17},
+	"gra": {50.99, 2.13},
+	"sbg": {48.58, 7.75},
+	"par": {48.86, 2.35},
+	"lon": {51.51, -0.13},
+	"eri": {51.48, 0.18},
+	"fra": {50.11, 8.68},
+	"lim": {50.39, 8.06},
+	"waw": {52.23, 21.01},
+	"mil": {45.46, 9.19},
+
+	"bhs": {45.31, -73.87},
+	"tor": {43.65, -79.38},
+	"vin": {38.75, -77.67},
+	"hil": {45.52, -122.99},
+
+	"sgp": {1.35, 103.82},
+	"syd": {-33.87, 151.21},
+	"ynm": {19.08, 72.88},
+}
+
+// DatacenterName returns the human-readable location for a datacenter code
+//
+// Parameters:
+//   - code: OVH datacenter code (e.g., "lon", "rbx"), case-insensitive
+//
+// Returns:
+//   - string: Location name (e.g., "London, UK"), or the uppercase code if unknown
+func DatacenterName(code string) string {
+	if name, ok := datacenterNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return strings.ToUpper(code)
+}
+
+// ListDatacenters returns all known datacenters sorted by code
+//
+// Returns:
+//   - []DatacenterInfo: Code, human-readable name and coordinates for each datacenter
+func ListDatacenters() []DatacenterInfo {
+	datacenters := make([]DatacenterInfo, 0, len(datacenterNames))
+	for code, name := range datacenterNames {
+		coordinates := datacenterCoordinates[code]
+		datacenters = append(datacenters, DatacenterInfo{
+			Code:      code,
+			Name:      name,
+			Latitude:  coordinates[0],
+			Longitude: coordinates[1],
+		})
+	}
+
+	// Map iteration order is random in Go, sort for stable output
+	sort.Slice(datacenters, func(i, j int) bool {
+		return datacenters[i].Code < datacenters[j].Code
+	})
+	return datacenters
+}
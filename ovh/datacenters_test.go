@@ -0,0 +1,56 @@
+package ovh
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestDatacenterName tests known codes, case-insensitivity and the unknown-code fallback
+func TestDatacenterName(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{code: "lon", expected: "London, UK"},
+		{code: "rbx", expected: "Roubaix, France"},
+		{code: "gra", expected: "Gravelines, France"},
+		{code: "bhs", expected: "Beauharnois, Canada"},
+		{code: "LON", expected: "London, UK"}, // Case-insensitive
+		{code: "xyz", expected: "XYZ"},        // Unknown - uppercase code
+		{code: "", expected: ""},              // Empty stays empty
+		{code: "rbx-hz", expected: "RBX-HZ"},  // Unknown variant
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if result := DatacenterName(tt.code); result != tt.expected {
+				t.Errorf("DatacenterName(%q) = %q, want %q", tt.code, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestListDatacenters verifies the list covers the lookup table, is sorted and has names and coordinates
+func TestListDatacenters(t *testing.T) {
+	datacenters := ListDatacenters()
+
+	if len(datacenters) != len(datacenterNames) {
+		t.Fatalf("ListDatacenters() returned %d entries, want %d", len(datacenters), len(datacenterNames))
+	}
+
+	if !sort.SliceIsSorted(datacenters, func(i, j int) bool { return datacenters[i].Code < datacenters[j].Code }) {
+		t.Errorf("ListDatacenters() is not sorted by code: %v", datacenters)
+	}
+
+	for _, dc := range datacenters {
+		if dc.Name == "" || dc.Name != DatacenterName(dc.Code) {
+			t.Errorf("datacenter %q has name %q, want %q", dc.Code, dc.Name, DatacenterName(dc.Code))
+		}
+		if _, ok := datacenterCoordinates[dc.Code]; !ok {
+			t.Errorf("datacenter %q has no coordinates", dc.Code)
+		}
+	}
+	if len(datacenterCoordinates) != len(datacenterNames) {
+		t.Errorf("datacenterCoordinates has %d entries, datacenterNames %d", len(datacenterCoordinates), len(datacenterNames))
+	}
+}
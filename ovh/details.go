@@ -0,0 +1,220 @@
+package ovh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrOfferNotFound is returned by GetOfferByPlanCode when neither catalog
+// lists the plan code (e.g., a plan OVH has withdrawn since it was listed)
+var ErrOfferNotFound = errors.New("OVH offer not found")
+
+// AddonPrice is one mandatory addon of an offer with its monthly price
+type AddonPrice struct {
+	Family   string  // Addon family (e.g., "memory", "bandwidth")
+	PlanCode string  // Addon plan code (e.g., "ram-32g-ecc-2400-24sk")
+	Name     string  // Invoice name from the catalog ("" if it has none)
+	Price    float64 // Monthly price (0 if the catalog has no monthly price for it)
+}
+
+// AddonOption is one choice of an addon family with its monthly price
+type AddonOption struct {
+	PlanCode string  // Addon plan code (e.g., "bandwidth-1000-24sk")
+	Name     string  // Invoice name from the catalog ("" if it has none)
+	Price    float64 // Monthly price (0 when Priced is false)
+	Priced   bool    // false if the catalog has no monthly price for it ("price unavailable")
+}
+
+// AddonFamilyOptions lists the choices of one addon family of a plan
+type AddonFamilyOptions struct {
+	Family    string        // Family name (e.g., "memory", "bandwidth")
+	Mandatory bool          // One option must be chosen (see pickMandatoryAddonsForFQN)
+	Exclusive bool          // At most one option can be chosen
+	Options   []AddonOption // Priced options cheapest first, then the unpriced ones in catalog order
+}
+
+// OfferDetails is the full price breakdown of one server configuration,
+// what the terse offer list leaves out
+//
+// Price of the embedded Offer is BasePrice plus every addon price.
+type OfferDetails struct {
+	Offer
+	CatalogName  string               // Catalog the plan was priced from ("eco" or "dedicated")
+	BasePrice    float64              // Monthly price of the plan alone
+	AddonPrices  []AddonPrice         // Mandatory addons, sorted by family
+	AddonOptions []AddonFamilyOptions // Every addon family of the plan with its choices (see ListAddonOptions)
+	Datacenters  []Datacenter         // Availability of the FQN in every datacenter (nil if OVH lists none)
+}
+
+// GetOfferByPlanCode returns the price breakdown and availability of one plan
+// Used by the offer details view: the list shows totals, this shows what
+// they are made of.
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (determines currency, e.g., "FR" for EUR)
+//   - planCode: Plan code (e.g., "24sk20"), looked up in the ECO catalog, then the dedicated one
+//   - fqn: Configuration to price (e.g., "24sk20.ram-32g.softraid-2x2000sa");
+//     "" picks the cheapest configuration OVH lists for the plan
+//
+// Returns:
+//   - OfferDetails: The offer with base price, addon prices and datacenters
+//     (Offer.Datacenter is left empty: the details cover every datacenter)
+//   - error: ErrOfferNotFound if no catalog has the plan, or any fetch error
+func GetOfferByPlanCode(subsidiary, planCode, fqn string) (OfferDetails, error) {
+	return GetOfferByPlanCodeContext(context.Background(), subsidiary, planCode, fqn)
+}
+
+// GetOfferByPlanCodeContext is GetOfferByPlanCode with a context for cancellation
+// Availabilities and catalogs come from the package cache when fresh (see cache.go).
+func GetOfferByPlanCodeContext(ctx context.Context, subsidiary, planCode, fqn string) (OfferDetails, error) {
+	for _, catalogName := range []string{catalogEco, catalogDedicated} {
+		availabilities, catalog, _, err := loadOVHData(ctx, catalogName, subsidiary)
+		if err != nil {
+			return OfferDetails{}, err
+		}
+
+		plansIdx, addonsIdx := indexCatalog(catalog)
+		if _, ok := plansIdx[planCode]; !ok {
+			continue
+		}
+		details, err := priceOfferDetails(availabilities, plansIdx, addonsIdx, getCatalogCurrency(catalog), planCode, fqn)
+		if err != nil {
+			return OfferDetails{}, err
+		}
+		details.CatalogName = catalogName
+		return details, nil
+	}
+
+	return OfferDetails{}, fmt.Errorf("%w: %s", ErrOfferNotFound, planCode)
+}
+
+// priceOfferDetails prices one configuration of a plan the catalog has
+//
+// Configuration:
+//   - fqn given: that FQN, even if OVH no longer lists it (no datacenters then)
+//   - fqn "": the cheapest FQN listed for the plan; the plan code itself if none is listed
+//
+// Parameters:
+//   - availabilities: Server availabilities (all product lines)
+//   - plansIdx, addonsIdx: Indexed catalog (see indexCatalog)
+//   - catalogCurrency: Currency code
+//   - planCode: Plan code (must be in plansIdx)
+//   - fqn: Configuration to price ("" = cheapest listed)
+//
+// Returns:
+//   - OfferDetails: Breakdown (CatalogName is left to the caller)
+//   - error: If the plan can't be priced
+func priceOfferDetails(
+	availabilities []Availability,
+	plansIdx, addonsIdx map[string]*Plan,
+	catalogCurrency, planCode, fqn string,
+) (OfferDetails, error) {
+	var details OfferDetails
+	found := false
+
+	for _, item := range availabilities {
+		if item.PlanCode != planCode || (fqn != "" && item.FQN != fqn) {
+			continue
+		}
+		offer, err := computeTotalMonthly(plansIdx, addonsIdx, planCode, item.FQN, catalogCurrency, AddonsFQNMatch)
+		if err != nil {
+			return OfferDetails{}, err
+		}
+		// Strict < keeps API order for configurations with equal price
+		if !found || offer.Price < details.Price {
+			details = OfferDetails{Offer: offer, Datacenters: item.Datacenters}
+			found = true
+		}
+	}
+
+	if !found {
+		// Not listed (anymore): price the configuration anyway, with no availability
+		if fqn == "" {
+			fqn = planCode
+		}
+		offer, err := computeTotalMonthly(plansIdx, addonsIdx, planCode, fqn, catalogCurrency, AddonsFQNMatch)
+		if err != nil {
+			return OfferDetails{}, err
+		}
+		details = OfferDetails{Offer: offer}
+	}
+
+	// Split the total back into base price and addon prices
+	details.BasePrice, _, _, _ = priceForPlan(plansIdx[planCode], catalogCurrency)
+	for family, addonCode := range details.Addons {
+		addon := AddonPrice{Family: family, PlanCode: addonCode}
+		if addonObj, ok := addonsIdx[addonCode]; ok {
+			addon.Name = addonObj.InvoiceName
+			addon.Price, _, _, _ = priceForPlan(addonObj, catalogCurrency)
+		}
+		details.AddonPrices = append(details.AddonPrices, addon)
+	}
+	sort.Slice(details.AddonPrices, func(i, j int) bool {
+		return details.AddonPrices[i].Family < details.AddonPrices[j].Family
+	})
+	details.AddonOptions = ListAddonOptions(plansIdx[planCode], addonsIdx)
+
+	return details, nil
+}
+
+// ListAddonOptions lists every addon family of a plan with the monthly
+// price of each option
+//
+// pickMandatoryAddonsForFQN only resolves the mandatory families to the
+// addons of one configuration; this shows what else could be chosen
+// (bandwidth, RAM and disk upgrades, ...) and what it costs.
+//
+// Behavior:
+//   - Families keep their catalog order, mandatory and optional alike
+//   - Options are sorted by monthly price, cheapest first (catalog order for equal prices)
+//   - Options missing from the catalog or without a monthly price are kept
+//     with Priced false, after the priced ones: a family is listed even if
+//     none of its options can be priced
+//
+// Parameters:
+//   - plan: The plan with addon families
+//   - addonsIdx: Indexed addons map (for names and prices, see indexCatalog)
+//
+// Returns:
+//   - []AddonFamilyOptions: One entry per family (nil if the plan has none)
+func ListAddonOptions(plan *Plan, addonsIdx map[string]*Plan) []AddonFamilyOptions {
+	if plan == nil {
+		return nil
+	}
+
+	var families []AddonFamilyOptions
+	for _, fam := range plan.AddonFamilies {
+		family := AddonFamilyOptions{
+			Family:    mandatoryFamilyName(fam),
+			Mandatory: fam.Mandatory,
+			Exclusive: fam.Exclusive,
+		}
+
+		for _, code := range fam.Addons {
+			option := AddonOption{PlanCode: code}
+			if addonObj, ok := addonsIdx[code]; ok {
+				option.Name = addonObj.InvoiceName
+				price, _, _, err := priceForPlan(addonObj, "")
+				if err == nil {
+					option.Price, option.Priced = price, true
+				}
+			}
+			family.Options = append(family.Options, option)
+		}
+
+		// Stable: equal prices (and the unpriced options) keep catalog order
+		sort.SliceStable(family.Options, func(i, j int) bool {
+			a, b := family.Options[i], family.Options[j]
+			if a.Priced != b.Priced {
+				return a.Priced
+			}
+			return a.Price < b.Price
+		})
+
+		families = append(families, family)
+	}
+
+	return families
+}
@@ -0,0 +1,204 @@
+package ovh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// TestGetOfferByPlanCode tests the price breakdown of one plan
+//
+// Data:
+//   - ks-b costs 5 EUR with a mandatory bandwidth family, listed in two
+//     configurations: bandwidth-300 (2 EUR, lon) and bandwidth-500 (4 EUR, lon + rbx)
+//
+// Cases:
+//   - No FQN: the cheapest listed configuration
+//   - FQN: that configuration and its datacenters
+//   - FQN OVH no longer lists: priced, no datacenters
+func TestGetOfferByPlanCode(t *testing.T) {
+	availability := []Availability{
+		{FQN: "ks-b.bandwidth-500", PlanCode: "ks-b", Datacenters: []Datacenter{
+			{Datacenter: "lon", Availability: "1H"},
+			{Datacenter: "rbx", Availability: "unavailable"},
+		}},
+		{FQN: "ks-b.bandwidth-300", PlanCode: "ks-b", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "72H"}}},
+	}
+	catalog := &Catalog{
+		Locale: Locale{CurrencyCode: "EUR", Subsidiary: "FR"},
+		Plans: []Plan{
+			{PlanCode: "ks-b", InvoiceName: "KS-B", Pricings: monthlyPricing(5), AddonFamilies: []AddonFamily{
+				{Name: "bandwidth", Mandatory: true, Addons: []string{"bandwidth-300", "bandwidth-500"}, Default: "bandwidth-300"},
+			}},
+		},
+		Addons: []Plan{
+			{PlanCode: "bandwidth-300", InvoiceName: "300 Mbps", Pricings: monthlyPricing(2)},
+			{PlanCode: "bandwidth-500", InvoiceName: "500 Mbps", Pricings: monthlyPricing(4)},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		fqn             string
+		wantFQN         string
+		wantPrice       float64
+		wantAddon       AddonPrice
+		wantDatacenters []Datacenter
+	}{
+		{
+			name:            "cheapest configuration",
+			fqn:             "",
+			wantFQN:         "ks-b.bandwidth-300",
+			wantPrice:       7,
+			wantAddon:       AddonPrice{Family: "bandwidth", PlanCode: "bandwidth-300", Name: "300 Mbps", Price: 2},
+			wantDatacenters: availability[1].Datacenters,
+		},
+		{
+			name:            "given configuration",
+			fqn:             "ks-b.bandwidth-500",
+			wantFQN:         "ks-b.bandwidth-500",
+			wantPrice:       9,
+			wantAddon:       AddonPrice{Family: "bandwidth", PlanCode: "bandwidth-500", Name: "500 Mbps", Price: 4},
+			wantDatacenters: availability[0].Datacenters,
+		},
+		{
+			name:            "configuration no longer listed",
+			fqn:             "ks-b.bandwidth-300.old",
+			wantFQN:         "ks-b.bandwidth-300.old",
+			wantPrice:       7,
+			wantAddon:       AddonPrice{Family: "bandwidth", PlanCode: "bandwidth-300", Name: "300 Mbps", Price: 2},
+			wantDatacenters: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			NewMockServer(t, availability, catalog)
+
+			got, err := GetOfferByPlanCodeContext(context.Background(), "FR", "ks-b", tt.fqn)
+			if err != nil {
+				t.Fatalf("GetOfferByPlanCodeContext() unexpected error: %v", err)
+			}
+			if got.FQN != tt.wantFQN || got.Price != tt.wantPrice || got.BasePrice != 5 || got.Currency != "EUR" {
+				t.Errorf("offer = %s %.2f %s (base %.2f), want %s %.2f EUR (base 5.00)",
+					got.FQN, got.Price, got.Currency, got.BasePrice, tt.wantFQN, tt.wantPrice)
+			}
+			if got.CatalogName != catalogEco {
+				t.Errorf("CatalogName = %q, want %q", got.CatalogName, catalogEco)
+			}
+			if len(got.AddonPrices) != 1 || got.AddonPrices[0] != tt.wantAddon {
+				t.Errorf("AddonPrices = %+v, want [%+v]", got.AddonPrices, tt.wantAddon)
+			}
+			if !reflect.DeepEqual(got.Datacenters, tt.wantDatacenters) {
+				t.Errorf("Datacenters = %+v, want %+v", got.Datacenters, tt.wantDatacenters)
+			}
+			if len(got.AddonOptions) != 1 || len(got.AddonOptions[0].Options) != 2 || !got.AddonOptions[0].Mandatory {
+				t.Errorf("AddonOptions = %+v, want the mandatory bandwidth family with 2 options", got.AddonOptions)
+			}
+		})
+	}
+}
+
+// TestGetOfferByPlanCode_Catalogs tests the catalog fallback
+//
+// Cases:
+//   - Plan in the dedicated catalog only: found there
+//   - Plan in no catalog: ErrOfferNotFound
+//   - Dedicated catalog unavailable: its error
+func TestGetOfferByPlanCode_Catalogs(t *testing.T) {
+	newCatalogServer(t, http.StatusOK)
+
+	got, err := GetOfferByPlanCode("FR", "adv-a", "")
+	if err != nil {
+		t.Fatalf("GetOfferByPlanCode(adv-a) unexpected error: %v", err)
+	}
+	if got.CatalogName != catalogDedicated || got.FQN != "adv-a.fqn" || got.Price != 80 || len(got.Datacenters) != 1 {
+		t.Errorf("GetOfferByPlanCode(adv-a) = %+v, want adv-a.fqn at 80 from the dedicated catalog", got)
+	}
+
+	if _, err := GetOfferByPlanCode("FR", "gone", ""); !errors.Is(err, ErrOfferNotFound) {
+		t.Errorf("GetOfferByPlanCode(gone) error = %v, want ErrOfferNotFound", err)
+	}
+
+	newCatalogServer(t, http.StatusInternalServerError)
+	if _, err := GetOfferByPlanCode("FR", "adv-a", ""); err == nil || errors.Is(err, ErrOfferNotFound) {
+		t.Errorf("GetOfferByPlanCode(adv-a) error = %v, want the catalog error", err)
+	}
+}
+
+// addonOptionsCatalog is a catalog fixture in the API's JSON layout:
+// ks-opt has a mandatory exclusive memory family, an optional exclusive
+// bandwidth family (one option without a monthly price, one missing from the
+// catalog) and an optional non-exclusive family nothing can be priced in.
+const addonOptionsCatalog = `{
+	"locale": {"currencyCode": "EUR", "subsidiary": "FR"},
+	"plans": [
+		{"planCode": "ks-opt", "invoiceName": "KS-OPT",
+		 "addonFamilies": [
+			{"name": "memory", "mandatory": true, "exclusive": true,
+			 "addons": ["ram-64g-ks", "ram-32g-ks", "ram-16g-ks"], "default": "ram-32g-ks"},
+			{"name": "bandwidth", "mandatory": false, "exclusive": true,
+			 "addons": ["bandwidth-1000-ks", "bandwidth-promo-ks", "bandwidth-gone-ks", "bandwidth-500-ks"]},
+			{"name": "backup", "mandatory": false, "exclusive": false,
+			 "addons": ["backup-ks"]}
+		 ],
+		 "pricings": [{"interval": 1, "intervalUnit": "month", "price": 1000000000}]}
+	],
+	"addons": [
+		{"planCode": "ram-16g-ks", "invoiceName": "16GB", "pricings": [{"interval": 1, "intervalUnit": "month", "price": 0}]},
+		{"planCode": "ram-32g-ks", "invoiceName": "32GB", "pricings": [{"interval": 1, "intervalUnit": "month", "price": 400000000}]},
+		{"planCode": "ram-64g-ks", "invoiceName": "64GB", "pricings": [{"interval": 1, "intervalUnit": "month", "price": 1200000000}]},
+		{"planCode": "bandwidth-500-ks", "invoiceName": "500 Mbps", "pricings": [{"interval": 1, "intervalUnit": "month", "price": 0}]},
+		{"planCode": "bandwidth-1000-ks", "invoiceName": "1 Gbps", "pricings": [{"interval": 1, "intervalUnit": "month", "price": 500000000}]},
+		{"planCode": "bandwidth-promo-ks", "invoiceName": "Promo", "pricings": [{"phase": 0, "interval": 0, "intervalUnit": "none", "price": 100000000}]},
+		{"planCode": "backup-ks", "invoiceName": "Backup", "pricings": []}
+	]
+}`
+
+// TestListAddonOptions tests the addon choices of a plan
+//
+// Cases (see addonOptionsCatalog):
+//   - Mandatory family: options sorted by monthly price
+//   - Optional family: listed too; the setup-only option and the one missing
+//     from the catalog come last, unpriced, in catalog order
+//   - Family without any priceable option: listed, every option unpriced
+//   - No addon families, nil plan: nil
+func TestListAddonOptions(t *testing.T) {
+	var catalog Catalog
+	if err := json.Unmarshal([]byte(addonOptionsCatalog), &catalog); err != nil {
+		t.Fatalf("fixture: %v", err)
+	}
+	plans, addons := indexCatalog(&catalog)
+
+	want := []AddonFamilyOptions{
+		{Family: "memory", Mandatory: true, Exclusive: true, Options: []AddonOption{
+			{PlanCode: "ram-16g-ks", Name: "16GB", Price: 0, Priced: true},
+			{PlanCode: "ram-32g-ks", Name: "32GB", Price: 4, Priced: true},
+			{PlanCode: "ram-64g-ks", Name: "64GB", Price: 12, Priced: true},
+		}},
+		{Family: "bandwidth", Exclusive: true, Options: []AddonOption{
+			{PlanCode: "bandwidth-500-ks", Name: "500 Mbps", Price: 0, Priced: true},
+			{PlanCode: "bandwidth-1000-ks", Name: "1 Gbps", Price: 5, Priced: true},
+			{PlanCode: "bandwidth-promo-ks", Name: "Promo"},
+			{PlanCode: "bandwidth-gone-ks"},
+		}},
+		{Family: "backup", Options: []AddonOption{
+			{PlanCode: "backup-ks", Name: "Backup"},
+		}},
+	}
+
+	got := ListAddonOptions(plans["ks-opt"], addons)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAddonOptions() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := ListAddonOptions(&Plan{PlanCode: "bare"}, addons); got != nil {
+		t.Errorf("ListAddonOptions(no families) = %+v, want nil", got)
+	}
+	if got := ListAddonOptions(nil, addons); got != nil {
+		t.Errorf("ListAddonOptions(nil) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,172 @@
+package ovh
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// OfferChangeKind tells what happened to an offer between two snapshots
+type OfferChangeKind int
+
+const (
+	// OfferAdded: the offer is only in the new snapshot
+	OfferAdded OfferChangeKind = iota
+	// OfferRemoved: the offer is only in the old snapshot
+	OfferRemoved
+	// OfferPriceChanged: the offer is in both, at a different price
+	OfferPriceChanged
+)
+
+// OfferChange is one entry of a snapshot diff (see DiffOffers)
+//
+// Old is the zero Offer for additions, New is the zero Offer for removals.
+type OfferChange struct {
+	Kind OfferChangeKind
+	Old  Offer
+	New  Offer
+}
+
+// String formats the change as one changelog line:
+//
+//	new: KS-1 (24ska01.ram-32g) at £15.99
+//	gone: KS-2 (24sk20.ram-64g), was £35.99
+//	KS-3 dropped from £35.99 to £31.99
+func (c OfferChange) String() string {
+	switch c.Kind {
+	case OfferAdded:
+		return fmt.Sprintf("new: %s at %s", offerLabel(c.New), formatMoney(c.New.Price, c.New.Currency))
+	case OfferRemoved:
+		return fmt.Sprintf("gone: %s, was %s", offerLabel(c.Old), formatMoney(c.Old.Price, c.Old.Currency))
+	default:
+		direction := "rose"
+		if c.New.Price < c.Old.Price {
+			direction = "dropped"
+		}
+		return fmt.Sprintf("%s %s from %s to %s", displayName(c.New), direction,
+			formatMoney(c.Old.Price, c.Old.Currency), formatMoney(c.New.Price, c.New.Currency))
+	}
+}
+
+// DiffOffers compares two snapshots of an offer list (e.g., yesterday's and
+// today's GetTopOffers result) and reports what changed.
+//
+// Offers are matched by FQN and datacenter (plan code when the FQN is empty);
+// offers with neither are ignored. Prices are compared in cents, so float
+// rounding noise is not a change. A currency change counts as a price change.
+//
+// Parameters:
+//   - old: Previous snapshot
+//   - new: Current snapshot
+//
+// Returns:
+//   - added: Offers only in new (in new's order)
+//   - removed: Offers only in old (in old's order)
+//   - priceChanged: Offers in both at a different price (in new's order)
+//
+// All three are nil when nothing changed.
+func DiffOffers(old, new []Offer) (added, removed, priceChanged []OfferChange) {
+	oldByKey := indexOffers(old)
+	newByKey := indexOffers(new)
+
+	seen := make(map[string]bool, len(new))
+	for _, offer := range new {
+		key := offerKey(offer)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		previous, ok := oldByKey[key]
+		switch {
+		case !ok:
+			added = append(added, OfferChange{Kind: OfferAdded, New: offer})
+		case priceCents(previous.Price) != priceCents(offer.Price) || previous.Currency != offer.Currency:
+			priceChanged = append(priceChanged, OfferChange{Kind: OfferPriceChanged, Old: previous, New: offer})
+		}
+	}
+
+	seen = make(map[string]bool, len(old))
+	for _, offer := range old {
+		key := offerKey(offer)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if _, ok := newByKey[key]; !ok {
+			removed = append(removed, OfferChange{Kind: OfferRemoved, Old: offer})
+		}
+	}
+
+	return added, removed, priceChanged
+}
+
+// FormatOfferChangelog formats a DiffOffers result as a plain text changelog,
+// one line per change: price changes first (the most interesting), then
+// additions, then removals.
+//
+// Parameters:
+//   - added, removed, priceChanged: DiffOffers result
+//
+// Returns:
+//   - string: Changelog lines joined by "\n" ("" when nothing changed)
+func FormatOfferChangelog(added, removed, priceChanged []OfferChange) string {
+	lines := make([]string, 0, len(added)+len(removed)+len(priceChanged))
+	for _, changes := range [][]OfferChange{priceChanged, added, removed} {
+		for _, change := range changes {
+			lines = append(lines, change.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indexOffers maps offerKey to offer (the first one wins for duplicates)
+func indexOffers(offers []Offer) map[string]Offer {
+	index := make(map[string]Offer, len(offers))
+	for _, offer := range offers {
+		key := offerKey(offer)
+		if _, ok := index[key]; key != "" && !ok {
+			index[key] = offer
+		}
+	}
+	return index
+}
+
+// offerKey identifies an offer across snapshots: FQN (or plan code) and datacenter
+// Returns "" for offers that can't be identified
+func offerKey(offer Offer) string {
+	id := offer.FQN
+	if id == "" {
+		id = offer.PlanCode
+	}
+	if id == "" {
+		return ""
+	}
+	return id + "@" + offer.Datacenter
+}
+
+// priceCents rounds a price to whole cents for comparison
+func priceCents(price float64) int64 {
+	return int64(math.Round(price * 100))
+}
+
+// displayName is the offer's invoice name, or its FQN when OVH gave none
+func displayName(offer Offer) string {
+	if offer.InvoiceName != "" {
+		return offer.InvoiceName
+	}
+	if offer.FQN != "" {
+		return offer.FQN
+	}
+	return offer.PlanCode
+}
+
+// offerLabel is "KS-1 (24ska01.ram-32g)", or just the name when there is no separate FQN
+func offerLabel(offer Offer) string {
+	name := displayName(offer)
+	if offer.FQN == "" || offer.FQN == name {
+		return name
+	}
+	return name + " (" + offer.FQN + ")"
+}
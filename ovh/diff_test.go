@@ -0,0 +1,173 @@
+package ovh
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestDiffOffers tests additions, removals and price changes between snapshots
+//
+// Testing strategy:
+//   - Each case lists two snapshots and the FQNs expected in each result slice
+//   - Unchanged and empty snapshots must give three nil slices
+func TestDiffOffers(t *testing.T) {
+	ks1 := Offer{FQN: "24ska01.ram-32g", PlanCode: "24ska01", Price: 15.99, Currency: "GBP", InvoiceName: "KS-1", Datacenter: "lon"}
+	ks2 := Offer{FQN: "24sk20.ram-64g", PlanCode: "24sk20", Price: 35.99, Currency: "GBP", InvoiceName: "KS-2", Datacenter: "lon"}
+	ks3 := Offer{FQN: "24sk30.ram-128g", PlanCode: "24sk30", Price: 49.99, Currency: "GBP", InvoiceName: "KS-3", Datacenter: "lon"}
+
+	withPrice := func(offer Offer, price float64) Offer {
+		offer.Price = price
+		return offer
+	}
+	withDatacenter := func(offer Offer, datacenter string) Offer {
+		offer.Datacenter = datacenter
+		return offer
+	}
+
+	tests := []struct {
+		name             string
+		old              []Offer
+		new              []Offer
+		wantAdded        []string // FQNs
+		wantRemoved      []string
+		wantPriceChanged []string
+	}{
+		{name: "both empty"},
+		{name: "unchanged", old: []Offer{ks1, ks2}, new: []Offer{ks1, ks2}},
+		{name: "unchanged in another order", old: []Offer{ks1, ks2}, new: []Offer{ks2, ks1}},
+		{name: "float noise is not a change", old: []Offer{ks1}, new: []Offer{withPrice(ks1, 15.99000001)}},
+		{name: "first snapshot: everything added", new: []Offer{ks1, ks2}, wantAdded: []string{"24ska01.ram-32g", "24sk20.ram-64g"}},
+		{name: "everything removed", old: []Offer{ks1, ks2}, wantRemoved: []string{"24ska01.ram-32g", "24sk20.ram-64g"}},
+		{name: "one added", old: []Offer{ks1}, new: []Offer{ks1, ks3}, wantAdded: []string{"24sk30.ram-128g"}},
+		{name: "one removed", old: []Offer{ks1, ks2}, new: []Offer{ks1}, wantRemoved: []string{"24sk20.ram-64g"}},
+		{name: "price drop", old: []Offer{ks2}, new: []Offer{withPrice(ks2, 31.99)}, wantPriceChanged: []string{"24sk20.ram-64g"}},
+		{name: "price rise by one cent", old: []Offer{ks1}, new: []Offer{withPrice(ks1, 16.00)}, wantPriceChanged: []string{"24ska01.ram-32g"}},
+		{
+			name:             "currency change",
+			old:              []Offer{ks1},
+			new:              []Offer{{FQN: ks1.FQN, Price: ks1.Price, Currency: "EUR", Datacenter: "lon"}},
+			wantPriceChanged: []string{"24ska01.ram-32g"},
+		},
+		{
+			name:             "all kinds at once",
+			old:              []Offer{ks1, ks2},
+			new:              []Offer{ks3, withPrice(ks1, 14.99)},
+			wantAdded:        []string{"24sk30.ram-128g"},
+			wantRemoved:      []string{"24sk20.ram-64g"},
+			wantPriceChanged: []string{"24ska01.ram-32g"},
+		},
+		{
+			name:        "same FQN in another datacenter is another offer",
+			old:         []Offer{ks1},
+			new:         []Offer{withDatacenter(ks1, "rbx")},
+			wantAdded:   []string{"24ska01.ram-32g"},
+			wantRemoved: []string{"24ska01.ram-32g"},
+		},
+		{
+			name:             "plan code when FQN is empty",
+			old:              []Offer{{PlanCode: "24ska01", Price: 10, Currency: "EUR"}},
+			new:              []Offer{{PlanCode: "24ska01", Price: 12, Currency: "EUR"}},
+			wantPriceChanged: []string{""},
+		},
+		{name: "offers without FQN or plan code are ignored", old: []Offer{{Price: 10}}, new: []Offer{{Price: 20}}},
+		{name: "duplicates reported once", new: []Offer{ks1, ks1}, wantAdded: []string{"24ska01.ram-32g"}},
+	}
+
+	fqns := func(changes []OfferChange, kind OfferChangeKind) []string {
+		t.Helper()
+		if changes == nil {
+			return nil
+		}
+		result := make([]string, 0, len(changes))
+		for _, change := range changes {
+			if change.Kind != kind {
+				t.Errorf("change %+v has kind %d, want %d", change, change.Kind, kind)
+			}
+			offer := change.New
+			if kind == OfferRemoved {
+				offer = change.Old
+			}
+			result = append(result, offer.FQN)
+		}
+		return result
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, priceChanged := DiffOffers(tt.old, tt.new)
+
+			if got := fqns(added, OfferAdded); !reflect.DeepEqual(got, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", got, tt.wantAdded)
+			}
+			if got := fqns(removed, OfferRemoved); !reflect.DeepEqual(got, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", got, tt.wantRemoved)
+			}
+			if got := fqns(priceChanged, OfferPriceChanged); !reflect.DeepEqual(got, tt.wantPriceChanged) {
+				t.Errorf("priceChanged = %v, want %v", got, tt.wantPriceChanged)
+			}
+		})
+	}
+}
+
+// TestDiffOffers_PriceChangeKeepsBothPrices tests that a price change carries the old and new offer
+func TestDiffOffers_PriceChangeKeepsBothPrices(t *testing.T) {
+	old := []Offer{{FQN: "24sk20.ram-64g", Price: 35.99, Currency: "GBP", InvoiceName: "ECO 3"}}
+	new := []Offer{{FQN: "24sk20.ram-64g", Price: 31.99, Currency: "GBP", InvoiceName: "ECO 3"}}
+
+	_, _, priceChanged := DiffOffers(old, new)
+	if len(priceChanged) != 1 {
+		t.Fatalf("priceChanged = %+v, want one change", priceChanged)
+	}
+	if priceChanged[0].Old.Price != 35.99 || priceChanged[0].New.Price != 31.99 {
+		t.Errorf("change = %+v, want 35.99 -> 31.99", priceChanged[0])
+	}
+}
+
+// TestOfferChange_String tests the changelog line for each kind of change
+func TestOfferChange_String(t *testing.T) {
+	eco3 := Offer{FQN: "24sk20.ram-64g", Price: 35.99, Currency: "GBP", InvoiceName: "ECO 3"}
+	cheaper := Offer{FQN: "24sk20.ram-64g", Price: 31.99, Currency: "GBP", InvoiceName: "ECO 3"}
+
+	tests := []struct {
+		name   string
+		change OfferChange
+		want   string
+	}{
+		{"price drop", OfferChange{Kind: OfferPriceChanged, Old: eco3, New: cheaper}, "ECO 3 dropped from £35.99 to £31.99"},
+		{"price rise", OfferChange{Kind: OfferPriceChanged, Old: cheaper, New: eco3}, "ECO 3 rose from £31.99 to £35.99"},
+		{"added", OfferChange{Kind: OfferAdded, New: eco3}, "new: ECO 3 (24sk20.ram-64g) at £35.99"},
+		{"removed", OfferChange{Kind: OfferRemoved, Old: eco3}, "gone: ECO 3 (24sk20.ram-64g), was £35.99"},
+		{"no invoice name", OfferChange{Kind: OfferAdded, New: Offer{FQN: "x.y", Price: 9, Currency: "PLN"}}, "new: x.y at 9.00 PLN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatOfferChangelog tests line order and the empty changelog
+func TestFormatOfferChangelog(t *testing.T) {
+	old := []Offer{
+		{FQN: "a", Price: 10, Currency: "EUR", InvoiceName: "KS-A"},
+		{FQN: "b", Price: 20, Currency: "EUR", InvoiceName: "KS-B"},
+	}
+	new := []Offer{
+		{FQN: "c", Price: 30, Currency: "EUR", InvoiceName: "KS-C"},
+		{FQN: "a", Price: 9, Currency: "EUR", InvoiceName: "KS-A"},
+	}
+
+	want := "KS-A dropped from €10.00 to €9.00\n" +
+		"new: KS-C (c) at €30.00\n" +
+		"gone: KS-B (b), was €20.00"
+	if got := FormatOfferChangelog(DiffOffers(old, new)); got != want {
+		t.Errorf("FormatOfferChangelog() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := FormatOfferChangelog(DiffOffers(old, old)); got != "" {
+		t.Errorf("FormatOfferChangelog() for unchanged offers = %q, want empty", got)
+	}
+}
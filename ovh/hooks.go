@@ -0,0 +1,81 @@
+package ovh
+
+import (
+	"context"
+	"time"
+)
+
+// Endpoint names passed to Hooks (RequestInfo.Endpoint)
+const (
+	EndpointAvailabilities = "availabilities"    // /dedicated/server/datacenter/availabilities
+	EndpointEcoCatalog     = "catalog/eco"       // /order/catalog/public/eco
+	EndpointAdvanceCatalog = "catalog/dedicated" // /order/catalog/public/dedicated
+)
+
+// RequestInfo describes one finished OVH API request
+type RequestInfo struct {
+	Endpoint   string        // One of the Endpoint* constants
+	Duration   time.Duration // From sending the request to reading the whole body
+	StatusCode int           // HTTP status, 0 if no response was received
+	Bytes      int           // Response body size after decompression, 0 on error
+	Err        error         // nil on success (status 200 and body read)
+}
+
+// OffersInfo summarizes one GetTopOffers (or GetTopDedicatedOffers) call
+type OffersInfo struct {
+	Subsidiary  string
+	CatalogName string // Catalog the offers were priced against: "eco" or "dedicated"
+
+	// Time spent fetching each input; 0 when it came from the cache
+	Availabilities time.Duration
+	Catalog        time.Duration
+
+	OffersConsidered int   // Offers available in the datacenter and priced, before filters
+	OffersReturned   int   // After price filters and the top-N limit
+	Err              error // nil on success
+}
+
+// Hooks receives OVH client events, e.g., to record metrics
+//
+// The package calls hooks but doesn't implement them, so it doesn't depend
+// on any metrics library: main.go installs an implementation with SetHooks.
+// Methods are called from the goroutine that made the request (sometimes
+// two at once, see loadOVHData), so implementations must be safe for
+// concurrent use and fast.
+type Hooks interface {
+	// RequestDone is called after every OVH API request, successful or not
+	// ctx is the request's context (it carries the per-update logger)
+	RequestDone(ctx context.Context, info RequestInfo)
+}
+
+// OffersHooks is an optional extension of Hooks
+// Hooks that also implement it get a summary after every GetTopOffers call.
+type OffersHooks interface {
+	OffersDone(ctx context.Context, info OffersInfo)
+}
+
+// hooks receives client events (nil: none)
+var hooks Hooks
+
+// SetHooks installs the hooks called by the OVH client (nil removes them)
+// Call once at startup, before the first request (read without locking).
+//
+// Parameters:
+//   - h: Hooks implementation; may also implement OffersHooks
+func SetHooks(h Hooks) {
+	hooks = h
+}
+
+// requestDone reports a finished request to the hooks, if any
+func requestDone(ctx context.Context, info RequestInfo) {
+	if hooks != nil {
+		hooks.RequestDone(ctx, info)
+	}
+}
+
+// offersDone reports a GetTopOffers summary to the hooks, if they want it
+func offersDone(ctx context.Context, info OffersInfo) {
+	if h, ok := hooks.(OffersHooks); ok {
+		h.OffersDone(ctx, info)
+	}
+}
@@ -0,0 +1,177 @@
+package ovh
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHooks is a Hooks (and OffersHooks) that keeps every event
+type recordingHooks struct {
+	mu       sync.Mutex
+	requests map[string]RequestInfo // By endpoint
+	offers   []OffersInfo
+}
+
+func (h *recordingHooks) RequestDone(_ context.Context, info RequestInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests[info.Endpoint] = info
+}
+
+func (h *recordingHooks) OffersDone(_ context.Context, info OffersInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.offers = append(h.offers, info)
+}
+
+// stubResponse is what stubTransport answers for one URL path
+type stubResponse struct {
+	delay  time.Duration
+	status int
+	body   string
+}
+
+// stubTransport is an http.RoundTripper answering from a map, without network
+type stubTransport map[string]stubResponse
+
+func (s stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, ok := s[req.URL.Path]
+	if !ok {
+		resp = stubResponse{status: http.StatusNotFound}
+	}
+	select {
+	case <-time.After(resp.delay):
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Body:       io.NopCloser(strings.NewReader(resp.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+// withStubTransport points the client at transport and installs hooks for one test
+func withStubTransport(t *testing.T, transport http.RoundTripper) *recordingHooks {
+	t.Helper()
+
+	oldClient, oldBase, oldHooks := httpClient, apiBase, hooks
+	t.Cleanup(func() {
+		httpClient, apiBase, hooks = oldClient, oldBase, oldHooks
+		dataCache.reset()
+	})
+
+	httpClient = &http.Client{Transport: transport}
+	apiBase = "http://ovh.test/v1"
+	dataCache.reset()
+
+	recorder := &recordingHooks{requests: make(map[string]RequestInfo)}
+	SetHooks(recorder)
+	return recorder
+}
+
+// mustJSON encodes v for a stub response body
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestHooks_GetTopOffers tests the per-request and per-call events of a GetTopOffers call
+//
+// Cases:
+//   - Each endpoint reports its own duration (stub delays differ), status and body size
+//   - The summary carries both fetch durations and the offer counts before/after top-N
+//   - A second call is served from the cache: no requests, zero fetch durations
+func TestHooks_GetTopOffers(t *testing.T) {
+	availability := mustJSON(t, []Availability{
+		{FQN: "ks-a.fqn", PlanCode: "ks-a", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "1H"}}},
+		{FQN: "ks-b.fqn", PlanCode: "ks-b", Datacenters: []Datacenter{{Datacenter: "lon", Availability: "1H"}}},
+	})
+	catalog := mustJSON(t, &Catalog{
+		Locale: Locale{CurrencyCode: "EUR"},
+		Plans: []Plan{
+			{PlanCode: "ks-a", InvoiceName: "KS-A", Pricings: monthlyPricing(10)},
+			{PlanCode: "ks-b", InvoiceName: "KS-B", Pricings: monthlyPricing(5)},
+		},
+	})
+
+	recorder := withStubTransport(t, stubTransport{
+		"/v1/dedicated/server/datacenter/availabilities": {delay: 20 * time.Millisecond, status: http.StatusOK, body: availability},
+		"/v1/order/catalog/public/eco":                   {delay: 60 * time.Millisecond, status: http.StatusOK, body: catalog},
+	})
+
+	offers, err := GetTopOffers(WithDatacenter("lon"), WithTop(1))
+	if err != nil || len(offers) != 1 {
+		t.Fatalf("GetTopOffers() = %v, %v; want one offer", offers, err)
+	}
+
+	avail := recorder.requests[EndpointAvailabilities]
+	if avail.StatusCode != http.StatusOK || avail.Bytes != len(availability) || avail.Err != nil {
+		t.Errorf("availabilities request = %+v, want status 200, %d bytes, no error", avail, len(availability))
+	}
+	cat := recorder.requests[EndpointEcoCatalog]
+	if cat.StatusCode != http.StatusOK || cat.Bytes != len(catalog) || cat.Err != nil {
+		t.Errorf("catalog request = %+v, want status 200, %d bytes, no error", cat, len(catalog))
+	}
+	if avail.Duration < 20*time.Millisecond || cat.Duration < 60*time.Millisecond || cat.Duration > time.Second {
+		t.Errorf("durations: availabilities %v (want >= 20ms), catalog %v (want 60ms-1s)", avail.Duration, cat.Duration)
+	}
+
+	if len(recorder.offers) != 1 {
+		t.Fatalf("OffersDone called %d times, want 1", len(recorder.offers))
+	}
+	summary := recorder.offers[0]
+	if summary.Availabilities < 20*time.Millisecond || summary.Catalog < 60*time.Millisecond {
+		t.Errorf("summary durations = %v / %v, want >= 20ms / 60ms", summary.Availabilities, summary.Catalog)
+	}
+	if summary.OffersConsidered != 2 || summary.OffersReturned != 1 || summary.Subsidiary != "FR" || summary.Err != nil {
+		t.Errorf("summary = %+v, want FR, 2 considered, 1 returned, no error", summary)
+	}
+
+	// Cached: no new requests, zero fetch durations
+	recorder.requests = make(map[string]RequestInfo)
+	if _, err := GetTopOffers(WithDatacenter("lon")); err != nil {
+		t.Fatalf("cached GetTopOffers() error = %v", err)
+	}
+	if len(recorder.requests) != 0 {
+		t.Errorf("cached call made requests: %+v", recorder.requests)
+	}
+	if summary := recorder.offers[1]; summary.Availabilities != 0 || summary.Catalog != 0 || summary.OffersReturned != 2 {
+		t.Errorf("cached summary = %+v, want zero durations and 2 offers", summary)
+	}
+}
+
+// TestHooks_RequestError tests that failed requests are reported with their status
+//
+// Cases:
+//   - HTTP 503: status recorded, error set, no bytes
+//   - The GetTopOffers summary carries the error
+func TestHooks_RequestError(t *testing.T) {
+	recorder := withStubTransport(t, stubTransport{
+		"/v1/dedicated/server/datacenter/availabilities": {status: http.StatusOK, body: "[]"},
+		"/v1/order/catalog/public/eco":                   {status: http.StatusServiceUnavailable, body: "down"},
+	})
+
+	if _, err := GetTopOffers(); err == nil {
+		t.Fatal("GetTopOffers() error = nil, want the catalog failure")
+	}
+
+	cat := recorder.requests[EndpointEcoCatalog]
+	if cat.StatusCode != http.StatusServiceUnavailable || cat.Err == nil || cat.Bytes != 0 {
+		t.Errorf("catalog request = %+v, want status 503 with an error and no bytes", cat)
+	}
+	if len(recorder.offers) != 1 || recorder.offers[0].Err == nil {
+		t.Errorf("summaries = %+v, want one with the error", recorder.offers)
+	}
+}
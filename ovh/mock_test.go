@@ -0,0 +1,68 @@
+package ovh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// NewMockServer starts a local OVH API stand-in that serves canned data
+// for the two endpoints GetTopOffers uses:
+//   - /v1/dedicated/server/datacenter/availabilities -> availability
+//   - /v1/order/catalog/public/eco                   -> catalog
+//
+// apiBase is pointed at the server and the data cache is reset, so the next
+// GetTopOffers call fetches from the mock. Both are restored via t.Cleanup.
+//
+// Parameters:
+//   - t: Test the server belongs to
+//   - availability: Response body for the availabilities endpoint
+//   - catalog: Response body for the ECO catalog endpoint (nil answers 404)
+//
+// Returns:
+//   - *httptest.Server: Running server (closed automatically via Cleanup)
+func NewMockServer(t *testing.T, availability []Availability, catalog *Catalog) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/dedicated/server/datacenter/availabilities", func(w http.ResponseWriter, r *http.Request) {
+		writeMockJSON(t, w, availability)
+	})
+	mux.HandleFunc("GET /v1/order/catalog/public/eco", func(w http.ResponseWriter, r *http.Request) {
+		if catalog == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeMockJSON(t, w, catalog)
+	})
+
+	server := httptest.NewServer(mux)
+
+	oldBase := apiBase
+	apiBase = server.URL + "/v1"
+	dataCache.reset()
+
+	t.Cleanup(func() {
+		server.Close()
+		apiBase = oldBase
+		dataCache.reset()
+	})
+
+	return server
+}
+
+// writeMockJSON encodes v as the JSON response body
+// Encoding errors fail the test: the canned data itself is broken
+func writeMockJSON(t *testing.T, w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("mock server: encode response: %v", err)
+	}
+}
+
+// monthlyPricing builds the monthly Pricing entry for a price in currency units
+// (OVH sends micro-units, see Pricing)
+func monthlyPricing(price float64) []Pricing {
+	return []Pricing{{Interval: 1, IntervalUnit: "month", Price: int64(price * 100000000)}}
+}
@@ -0,0 +1,158 @@
+package ovh
+
+// SortOrder defines how offers are ordered in GetTopOffers results
+type SortOrder int
+
+const (
+	// SortByPriceAsc returns cheapest offers first (default)
+	SortByPriceAsc SortOrder = iota
+	// SortByPriceDesc returns most expensive offers first
+	SortByPriceDesc
+)
+
+// AddonStrategy defines how one addon is chosen from each mandatory addon family
+//
+// Some mandatory families (RAM, storage, bandwidth) list several addons at
+// different prices. The FQN usually names the configuration that is in stock,
+// but not always unambiguously, so the strategy decides what to report.
+type AddonStrategy int
+
+const (
+	// AddonsFQNMatch picks the first addon matching the FQN, else the family default (default)
+	AddonsFQNMatch AddonStrategy = iota
+	// AddonsCheapest picks the cheapest FQN-compatible addon, reporting the true minimum price
+	AddonsCheapest
+)
+
+// Default values used when an option is not provided
+// FR subsidiary gives EUR pricing, lon is the London datacenter
+const (
+	DefaultSubsidiary = "FR"
+	DefaultDatacenter = "lon"
+	DefaultTop        = 3
+)
+
+// Options holds the merged settings for GetTopOffers
+// Built by newOptions from defaults + all Option functions passed by the caller
+type Options struct {
+	Subsidiary    string        // OVH subsidiary (e.g., "GB", "FR", "DE")
+	Datacenter    string        // Datacenter code (e.g., "lon", "rbx", "gra")
+	Top           int           // Max number of offers to return (0 = no limit)
+	MinPrice      float64       // Minimum monthly price (0 = no lower bound)
+	MaxPrice      float64       // Maximum monthly price (0 = no upper bound)
+	PlanPrefix    string        // Only plan codes starting with it, e.g. "25skle" ("" = all)
+	SortOrder     SortOrder     // Price sort direction
+	AddonStrategy AddonStrategy // How one addon is chosen per mandatory family
+	ComingSoon    bool          // Also list "comingSoon" servers (not orderable yet)
+	Keyword       string        // Only FQNs or plan codes containing it, e.g. "nvme" ("" = all)
+}
+
+// Option is a functional option for GetTopOffers
+//
+// Functional options pattern:
+//   - Each option is a function that modifies Options
+//   - Callers pass only the options they care about
+//   - Adding a new option doesn't break existing callers
+//
+// Example:
+//
+//	GetTopOffers(WithDatacenter("rbx"), WithMaxPrice(20))
+type Option func(*Options)
+
+// WithSubsidiary sets the OVH subsidiary (determines currency and tax)
+func WithSubsidiary(s string) Option {
+	return func(o *Options) {
+		o.Subsidiary = s
+	}
+}
+
+// WithDatacenter sets the datacenter code to check availability in
+func WithDatacenter(dc string) Option {
+	return func(o *Options) {
+		o.Datacenter = dc
+	}
+}
+
+// WithTop sets the maximum number of offers returned (0 = no limit)
+func WithTop(n int) Option {
+	return func(o *Options) {
+		o.Top = n
+	}
+}
+
+// WithMinPrice excludes offers cheaper than f
+func WithMinPrice(f float64) Option {
+	return func(o *Options) {
+		o.MinPrice = f
+	}
+}
+
+// WithMaxPrice excludes offers more expensive than f
+func WithMaxPrice(f float64) Option {
+	return func(o *Options) {
+		o.MaxPrice = f
+	}
+}
+
+// WithPlanPrefix keeps only offers whose plan code starts with prefix
+// Plan codes name a server range, so a prefix follows one family (e.g., "25skle" for KS-LE)
+func WithPlanPrefix(prefix string) Option {
+	return func(o *Options) {
+		o.PlanPrefix = prefix
+	}
+}
+
+// WithSortOrder sets the price sort direction
+func WithSortOrder(order SortOrder) Option {
+	return func(o *Options) {
+		o.SortOrder = order
+	}
+}
+
+// WithAddonStrategy sets how mandatory addons are chosen when pricing offers
+func WithAddonStrategy(strategy AddonStrategy) Option {
+	return func(o *Options) {
+		o.AddonStrategy = strategy
+	}
+}
+
+// WithComingSoon also lists servers OVH marks "comingSoon"
+// They are left out by default: they can't be ordered yet (see ParseAvailability).
+func WithComingSoon(include bool) Option {
+	return func(o *Options) {
+		o.ComingSoon = include
+	}
+}
+
+// WithKeyword keeps only servers whose FQN or plan code contains keyword
+// (case-insensitive, see FilterAvailabilitiesByKeyword)
+// FQNs name the hardware, so "nvme" or "ram-64g" finds servers by component.
+func WithKeyword(keyword string) Option {
+	return func(o *Options) {
+		o.Keyword = keyword
+	}
+}
+
+// newOptions builds Options from defaults and applies all options in order
+// Later options override earlier ones (e.g., two WithTop calls - last wins)
+//
+// Parameters:
+//   - opts: Options passed by the caller
+//
+// Returns:
+//   - Options: Merged options
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Subsidiary:    DefaultSubsidiary,
+		Datacenter:    DefaultDatacenter,
+		Top:           DefaultTop,
+		SortOrder:     SortByPriceAsc,
+		AddonStrategy: AddonsFQNMatch,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
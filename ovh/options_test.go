@@ -0,0 +1,134 @@
+package ovh
+
+import "testing"
+
+// TestNewOptions tests that defaults are applied and options override them
+//
+// Testing strategy:
+//   - No options: all defaults
+//   - Each option changes only its own field
+//   - Later options win over earlier ones
+func TestNewOptions(t *testing.T) {
+	// No options - defaults only
+	defaults := newOptions()
+	if defaults.Subsidiary != DefaultSubsidiary || defaults.Datacenter != DefaultDatacenter ||
+		defaults.Top != DefaultTop || defaults.SortOrder != SortByPriceAsc ||
+		defaults.MinPrice != 0 || defaults.MaxPrice != 0 || defaults.PlanPrefix != "" || defaults.AddonStrategy != AddonsFQNMatch || defaults.Keyword != "" {
+		t.Errorf("newOptions() = %+v, want defaults", defaults)
+	}
+
+	// All options set
+	got := newOptions(
+		WithSubsidiary("GB"),
+		WithDatacenter("rbx"),
+		WithTop(10),
+		WithMinPrice(5),
+		WithMaxPrice(50),
+		WithPlanPrefix("25skle"),
+		WithSortOrder(SortByPriceDesc),
+		WithAddonStrategy(AddonsCheapest),
+		WithKeyword("nvme"),
+	)
+	want := Options{
+		Subsidiary:    "GB",
+		Datacenter:    "rbx",
+		Top:           10,
+		MinPrice:      5,
+		MaxPrice:      50,
+		PlanPrefix:    "25skle",
+		SortOrder:     SortByPriceDesc,
+		AddonStrategy: AddonsCheapest,
+		Keyword:       "nvme",
+	}
+	if got != want {
+		t.Errorf("newOptions(...) = %+v, want %+v", got, want)
+	}
+
+	// Last option wins
+	if o := newOptions(WithTop(1), WithTop(7)); o.Top != 7 {
+		t.Errorf("newOptions(WithTop(1), WithTop(7)).Top = %d, want 7", o.Top)
+	}
+}
+
+// TestFilterAndSortOffers tests price and plan filters, sort order and top-N limit
+func TestFilterAndSortOffers(t *testing.T) {
+	offers := []Offer{
+		{PlanCode: "b", Price: 20},
+		{PlanCode: "a", Price: 10},
+		{PlanCode: "d", Price: 40},
+		{PlanCode: "c", Price: 30},
+		{PlanCode: "cc", Price: 15},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string // Expected plan codes in order
+	}{
+		{
+			name:     "defaults - cheapest 3",
+			opts:     nil,
+			expected: []string{"a", "cc", "b"},
+		},
+		{
+			name:     "no limit",
+			opts:     []Option{WithTop(0)},
+			expected: []string{"a", "cc", "b", "c", "d"},
+		},
+		{
+			name:     "descending order",
+			opts:     []Option{WithSortOrder(SortByPriceDesc), WithTop(2)},
+			expected: []string{"d", "c"},
+		},
+		{
+			name:     "min price",
+			opts:     []Option{WithMinPrice(25)},
+			expected: []string{"c", "d"},
+		},
+		{
+			name:     "max price (inclusive)",
+			opts:     []Option{WithMaxPrice(20)},
+			expected: []string{"a", "cc", "b"},
+		},
+		{
+			name:     "price range excludes everything",
+			opts:     []Option{WithMinPrice(21), WithMaxPrice(29)},
+			expected: []string{},
+		},
+		{
+			name:     "plan prefix",
+			opts:     []Option{WithPlanPrefix("c")},
+			expected: []string{"cc", "c"},
+		},
+		{
+			name:     "plan prefix and max price",
+			opts:     []Option{WithPlanPrefix("c"), WithMaxPrice(20)},
+			expected: []string{"cc"},
+		},
+		{
+			name:     "unknown plan prefix",
+			opts:     []Option{WithPlanPrefix("x")},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Copy input so sorting in one case doesn't affect others
+			input := append([]Offer(nil), offers...)
+			result := filterAndSortOffers(input, newOptions(tt.opts...))
+
+			if result == nil {
+				t.Fatalf("filterAndSortOffers() returned nil, want empty slice")
+			}
+			if len(result) != len(tt.expected) {
+				t.Fatalf("filterAndSortOffers() returned %d offers, want %d", len(result), len(tt.expected))
+			}
+			for i, code := range tt.expected {
+				if result[i].PlanCode != code {
+					t.Errorf("result[%d].PlanCode = %q, want %q", i, result[i].PlanCode, code)
+				}
+			}
+		})
+	}
+}
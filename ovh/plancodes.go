@@ -0,0 +1,72 @@
+package ovh
+
+import (
+	"context"
+	"slices"
+)
+
+// PlanCodes lists the plan codes of the ECO catalog for a subsidiary
+// Useful to operators writing FQN subscriptions or filters, who need to
+// know which plan codes exist (e.g., "24sk20", "25rise01").
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (e.g., "FR")
+//
+// Returns:
+//   - []string: Plan codes, sorted, without duplicates
+//   - error: Any errors during fetch or parse
+func PlanCodes(subsidiary string) ([]string, error) {
+	return PlanCodesContext(context.Background(), subsidiary)
+}
+
+// PlanCodesContext is PlanCodes with a context for cancellation
+// The catalog is served from the package cache when fresh (shared with GetTopOffers)
+func PlanCodesContext(ctx context.Context, subsidiary string) ([]string, error) {
+	catalog, err := cachedEcoCatalog(ctx, subsidiary)
+	if err != nil {
+		return nil, err
+	}
+	return sortedPlanCodes(catalog.Plans), nil
+}
+
+// AddonCodes lists the addon plan codes (RAM, storage, bandwidth, ...) of the
+// ECO catalog for a subsidiary
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (e.g., "FR")
+//
+// Returns:
+//   - []string: Addon plan codes, sorted, without duplicates
+//   - error: Any errors during fetch or parse
+func AddonCodes(subsidiary string) ([]string, error) {
+	return AddonCodesContext(context.Background(), subsidiary)
+}
+
+// AddonCodesContext is AddonCodes with a context for cancellation
+// The catalog is served from the package cache when fresh (shared with GetTopOffers)
+func AddonCodesContext(ctx context.Context, subsidiary string) ([]string, error) {
+	catalog, err := cachedEcoCatalog(ctx, subsidiary)
+	if err != nil {
+		return nil, err
+	}
+	return sortedPlanCodes(catalog.Addons), nil
+}
+
+// cachedEcoCatalog returns the ECO catalog from the package cache, fetching
+// (and caching) it when missing or stale (see loadCachedCatalog)
+func cachedEcoCatalog(ctx context.Context, subsidiary string) (*Catalog, error) {
+	return loadCachedCatalog(ctx, catalogEco, subsidiary)
+}
+
+// sortedPlanCodes returns the non-empty plan codes of plans, sorted and deduplicated
+// The catalog lists some codes more than once (e.g., per pricing mode).
+func sortedPlanCodes(plans []Plan) []string {
+	codes := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		if plan.PlanCode != "" {
+			codes = append(codes, plan.PlanCode)
+		}
+	}
+	slices.Sort(codes)
+	return slices.Compact(codes)
+}
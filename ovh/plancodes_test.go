@@ -0,0 +1,59 @@
+package ovh
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestPlanCodes tests plan and addon code extraction from the mock ECO catalog
+//
+// Cases:
+//   - Codes are sorted and deduplicated; empty codes are skipped
+//   - Addon codes come from catalog.Addons, not catalog.Plans
+//   - Both share the cached catalog (one fetch)
+func TestPlanCodes(t *testing.T) {
+	server := NewMockServer(t, nil, &Catalog{
+		Plans: []Plan{
+			{PlanCode: "25rise01"},
+			{PlanCode: "24sk20"},
+			{PlanCode: "24sk10"},
+			{PlanCode: "24sk20"},
+			{PlanCode: ""},
+		},
+		Addons: []Plan{
+			{PlanCode: "ram-64g-ecc-2133-24sk20"},
+			{PlanCode: "bandwidth-300-24sk"},
+			{PlanCode: "ram-64g-ecc-2133-24sk20"},
+		},
+	})
+
+	plans, err := PlanCodes("FR")
+	if err != nil {
+		t.Fatalf("PlanCodes() unexpected error: %v", err)
+	}
+	if want := []string{"24sk10", "24sk20", "25rise01"}; !reflect.DeepEqual(plans, want) {
+		t.Errorf("PlanCodes() = %v, want %v", plans, want)
+	}
+
+	// Served from the cache, even with the API gone
+	server.Close()
+	addons, err := AddonCodes("FR")
+	if err != nil {
+		t.Fatalf("AddonCodes() unexpected error: %v", err)
+	}
+	if want := []string{"bandwidth-300-24sk", "ram-64g-ecc-2133-24sk20"}; !reflect.DeepEqual(addons, want) {
+		t.Errorf("AddonCodes() = %v, want %v", addons, want)
+	}
+}
+
+// TestPlanCodes_CatalogError tests that a missing catalog is an error
+func TestPlanCodes_CatalogError(t *testing.T) {
+	NewMockServer(t, nil, nil)
+
+	if codes, err := PlanCodes("FR"); err == nil {
+		t.Errorf("PlanCodes() = %v, want error for missing catalog", codes)
+	}
+	if codes, err := AddonCodes("FR"); err == nil {
+		t.Errorf("AddonCodes() = %v, want error for missing catalog", codes)
+	}
+}
@@ -0,0 +1,142 @@
+package ovh
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultPriceChangeThresholdPct is the smallest price change (in percent) worth notifying
+const DefaultPriceChangeThresholdPct = 5.0
+
+// PricedOffer is an offer together with when its price was observed
+type PricedOffer struct {
+	Offer
+	FetchedAt time.Time
+}
+
+// PriceChange describes an offer whose price moved past the threshold
+type PriceChange struct {
+	Old PricedOffer // Price subscribers last heard about
+	New PricedOffer // Price just fetched
+}
+
+// Percent returns the relative change from Old to New (negative = cheaper)
+func (c PriceChange) Percent() float64 {
+	if c.Old.Price == 0 {
+		return 0
+	}
+	return (c.New.Price - c.Old.Price) / c.Old.Price * 100
+}
+
+// PriceWatcher remembers the last known price per FQN and reports changes
+//
+// How it works:
+//   - Observe is called with freshly fetched offers (e.g., from GetTopOffers
+//     by a background scheduler)
+//   - The first time an FQN is seen, its price becomes the baseline
+//   - If a later price differs from the baseline by more than the threshold,
+//     a PriceChange is reported and the new price becomes the baseline
+//   - Smaller changes are not reported and do NOT move the baseline, so a
+//     price that creeps up 1% per fetch is still reported once it adds up
+//   - A different currency (another subsidiary) resets the baseline:
+//     18.99 GBP vs 21.99 EUR is not a price change
+//
+// Which changes to deliver (e.g., only subscribed FQNs) is up to the caller.
+//
+// Safe for concurrent use.
+type PriceWatcher struct {
+	thresholdPct float64
+
+	mu   sync.Mutex
+	last map[string]PricedOffer // key: FQN
+}
+
+// NewPriceWatcher creates a watcher with no known prices
+//
+// Parameters:
+//   - thresholdPct: Minimum absolute change in percent (e.g., 5 = 5%) to report
+//
+// Returns:
+//   - *PriceWatcher: Ready-to-use watcher
+func NewPriceWatcher(thresholdPct float64) *PriceWatcher {
+	return &PriceWatcher{
+		thresholdPct: thresholdPct,
+		last:         make(map[string]PricedOffer),
+	}
+}
+
+// Observe records fetched offers and returns those whose price changed past the threshold
+//
+// Parameters:
+//   - offers: Freshly fetched offers (offers without FQN are ignored)
+//   - fetchedAt: When the offers were fetched
+//
+// Returns:
+//   - []PriceChange: Changes in the order of offers (nil if none)
+func (w *PriceWatcher) Observe(offers []Offer, fetchedAt time.Time) []PriceChange {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changes []PriceChange
+	for _, offer := range offers {
+		if offer.FQN == "" {
+			continue
+		}
+		current := PricedOffer{Offer: offer, FetchedAt: fetchedAt}
+
+		previous, ok := w.last[offer.FQN]
+		if !ok || previous.Currency != offer.Currency || previous.Price == 0 {
+			w.last[offer.FQN] = current
+			continue
+		}
+
+		change := PriceChange{Old: previous, New: current}
+		if math.Abs(change.Percent()) <= w.thresholdPct {
+			continue
+		}
+		changes = append(changes, change)
+		w.last[offer.FQN] = current
+	}
+
+	return changes
+}
+
+// FormatPriceChange formats a change as a plain text notification:
+//
+//	💰 Price change: 1801sk12.ram.1 dropped from £18.99 to £15.99 (−15.8%)
+//
+// Parameters:
+//   - change: Change reported by PriceWatcher.Observe
+//
+// Returns:
+//   - string: Notification text (plain text, no parse mode needed)
+func FormatPriceChange(change PriceChange) string {
+	direction, sign := "rose", "+"
+	if change.New.Price < change.Old.Price {
+		// U+2212 MINUS SIGN, typographically matches "+"
+		direction, sign = "dropped", "−"
+	}
+
+	return fmt.Sprintf("💰 Price change: %s %s from %s to %s (%s%.1f%%)",
+		change.New.FQN, direction,
+		formatMoney(change.Old.Price, change.Old.Currency),
+		formatMoney(change.New.Price, change.New.Currency),
+		sign, math.Abs(change.Percent()))
+}
+
+// currencySymbols maps currency codes to symbols written before the amount
+var currencySymbols = map[string]string{
+	"GBP": "£",
+	"EUR": "€",
+	"USD": "$",
+}
+
+// formatMoney formats an amount as "£18.99", or "18.99 PLN" for currencies without a symbol
+func formatMoney(amount float64, currency string) string {
+	if symbol, ok := currencySymbols[currency]; ok {
+		return fmt.Sprintf("%s%.2f", symbol, amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, currency)
+}
@@ -0,0 +1,95 @@
+package ovh
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPriceWatcher_Observe tests baselines, the threshold and currency resets
+//
+// Testing strategy:
+//   - Feed a sequence of fetches for one FQN and check which ones are reported
+//   - The threshold is 5%: exactly 5% is not reported, more is
+func TestPriceWatcher_Observe(t *testing.T) {
+	offer := func(price float64, currency string) []Offer {
+		return []Offer{{FQN: "1801sk12.ram.1", Price: price, Currency: currency}}
+	}
+
+	tests := []struct {
+		name    string
+		offers  []Offer
+		wantOld float64 // 0 = no change reported
+	}{
+		{name: "first fetch sets baseline", offers: offer(20, "GBP")},
+		{name: "same price", offers: offer(20, "GBP")},
+		{name: "exactly threshold", offers: offer(21, "GBP")},
+		{name: "small creep adds up", offers: offer(21.5, "GBP"), wantOld: 20},
+		{name: "drop past threshold", offers: offer(18, "GBP"), wantOld: 21.5},
+		{name: "other currency resets baseline", offers: offer(25, "EUR")},
+		{name: "change in new currency", offers: offer(20, "EUR"), wantOld: 25},
+		{name: "no FQN ignored", offers: []Offer{{Price: 1, Currency: "EUR"}}},
+	}
+
+	watcher := NewPriceWatcher(DefaultPriceChangeThresholdPct)
+	start := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	for i, tt := range tests {
+		fetchedAt := start.Add(time.Duration(i) * time.Hour)
+		changes := watcher.Observe(tt.offers, fetchedAt)
+
+		if tt.wantOld == 0 {
+			if len(changes) != 0 {
+				t.Errorf("%s: Observe() = %+v, want no changes", tt.name, changes)
+			}
+			continue
+		}
+		if len(changes) != 1 {
+			t.Fatalf("%s: Observe() returned %d changes, want 1", tt.name, len(changes))
+		}
+		got := changes[0]
+		if got.Old.Price != tt.wantOld || got.New.Price != tt.offers[0].Price || !got.New.FetchedAt.Equal(fetchedAt) {
+			t.Errorf("%s: change = %v -> %v at %v, want %v -> %v at %v", tt.name,
+				got.Old.Price, got.New.Price, got.New.FetchedAt, tt.wantOld, tt.offers[0].Price, fetchedAt)
+		}
+	}
+}
+
+// TestFormatPriceChange tests the notification text
+func TestFormatPriceChange(t *testing.T) {
+	change := func(oldPrice, newPrice float64, currency string) PriceChange {
+		return PriceChange{
+			Old: PricedOffer{Offer: Offer{FQN: "1801sk12.ram.1", Price: oldPrice, Currency: currency}},
+			New: PricedOffer{Offer: Offer{FQN: "1801sk12.ram.1", Price: newPrice, Currency: currency}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change PriceChange
+		want   string
+	}{
+		{
+			name:   "drop in GBP",
+			change: change(18.99, 15.99, "GBP"),
+			want:   "💰 Price change: 1801sk12.ram.1 dropped from £18.99 to £15.99 (−15.8%)",
+		},
+		{
+			name:   "rise in EUR",
+			change: change(10, 12.5, "EUR"),
+			want:   "💰 Price change: 1801sk12.ram.1 rose from €10.00 to €12.50 (+25.0%)",
+		},
+		{
+			name:   "currency without symbol",
+			change: change(100, 90, "PLN"),
+			want:   "💰 Price change: 1801sk12.ram.1 dropped from 100.00 PLN to 90.00 PLN (−10.0%)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPriceChange(tt.change); got != tt.want {
+				t.Errorf("FormatPriceChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package ovh
+
+import "math/rand"
+
+// PickRandomOffer returns one offer chosen uniformly at random
+// Used by /lucky_server for users who don't care about the cheapest option.
+//
+// Uses math/rand (not crypto/rand): this is a game, not a security decision.
+//
+// Parameters:
+//   - offers: Offers to pick from (usually a large GetTopOffers result)
+//
+// Returns:
+//   - Offer: A random element of offers (zero Offer if offers is empty)
+func PickRandomOffer(offers []Offer) Offer {
+	if len(offers) == 0 {
+		return Offer{}
+	}
+	return offers[rand.Intn(len(offers))]
+}
@@ -0,0 +1,50 @@
+package ovh
+
+import "testing"
+
+// TestPickRandomOffer tests that the pick always comes from the input
+//
+// Cases:
+//   - Several offers: every pick is one of them, and more than one is seen
+//   - Single offer: always returned
+//   - Empty input: zero Offer
+func TestPickRandomOffer(t *testing.T) {
+	offers := []Offer{
+		{PlanCode: "ks-a", Price: 10},
+		{PlanCode: "ks-b", Price: 20},
+		{PlanCode: "ks-c", Price: 30},
+	}
+
+	seen := make(map[string]bool)
+	for range 200 {
+		offer := PickRandomOffer(offers)
+
+		found := false
+		for _, o := range offers {
+			if o.PlanCode == offer.PlanCode && o.Price == offer.Price {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("PickRandomOffer() = %+v, not an element of the input", offer)
+		}
+		seen[offer.PlanCode] = true
+	}
+
+	// 200 picks out of 3: the chance of always getting the same one is negligible
+	if len(seen) < 2 {
+		t.Errorf("PickRandomOffer() returned only %v in 200 picks, want some variety", seen)
+	}
+
+	single := []Offer{{PlanCode: "only", Price: 5}}
+	for range 10 {
+		if got := PickRandomOffer(single); got.PlanCode != "only" {
+			t.Errorf("PickRandomOffer(single) = %+v, want the only offer", got)
+		}
+	}
+
+	if got := PickRandomOffer(nil); got.PlanCode != "" {
+		t.Errorf("PickRandomOffer(nil) = %+v, want zero Offer", got)
+	}
+}
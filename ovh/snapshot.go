@@ -0,0 +1,97 @@
+package ovh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+)
+
+// snapshotPrefix is the storage key prefix of offer snapshots
+// Key layout: "offers/<subsidiary>/<datacenter>", e.g. "offers/FR/lon"
+const snapshotPrefix = "offers/"
+
+// Snapshot is the offer list of one fetch, kept to detect changes on the next one
+type Snapshot struct {
+	Offers    []Offer   `json:"offers"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// SnapshotStore remembers the last fetched offers per (subsidiary, datacenter)
+//
+// How it's used:
+//   - A scheduler fetches offers, loads the previous Snapshot and compares
+//     the two with DiffOffers
+//   - It then saves the new offers, which become the baseline for the next run
+//
+// Persistence follows the storage backend: with STORAGE_BACKEND=file the
+// snapshots survive restarts, so the first run after a deploy still has
+// something to compare with.
+//
+// Safe for concurrent use (each Save replaces one storage key as a whole).
+type SnapshotStore struct {
+	store storage.Store
+}
+
+// NewSnapshotStore creates a SnapshotStore on top of store
+//
+// Parameters:
+//   - store: Storage backend (see storage.Open)
+//
+// Returns:
+//   - *SnapshotStore: Ready-to-use snapshot store
+func NewSnapshotStore(store storage.Store) *SnapshotStore {
+	return &SnapshotStore{store: store}
+}
+
+// Load returns the last saved snapshot for a subsidiary and datacenter
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//   - subsidiary: OVH subsidiary (e.g., "FR"), case-insensitive
+//   - datacenter: Datacenter code (e.g., "lon"), case-insensitive
+//
+// Returns:
+//   - Snapshot: Saved snapshot (zero value if found is false)
+//   - bool: Whether a snapshot was saved before
+//   - error: If the storage call fails or the stored data is corrupt
+func (s *SnapshotStore) Load(ctx context.Context, subsidiary, datacenter string) (Snapshot, bool, error) {
+	data, found, err := s.store.Get(ctx, snapshotKey(subsidiary, datacenter))
+	if err != nil || !found {
+		return Snapshot{}, false, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, false, fmt.Errorf("failed to decode offer snapshot %s/%s: %w", subsidiary, datacenter, err)
+	}
+	return snapshot, true, nil
+}
+
+// Save replaces the snapshot for a subsidiary and datacenter
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//   - subsidiary: OVH subsidiary (e.g., "FR"), case-insensitive
+//   - datacenter: Datacenter code (e.g., "lon"), case-insensitive
+//   - offers: Offers just fetched (an empty list is a valid snapshot: nothing in stock)
+//   - fetchedAt: When the offers were fetched
+//
+// Returns:
+//   - error: If the storage call fails
+func (s *SnapshotStore) Save(ctx context.Context, subsidiary, datacenter string, offers []Offer, fetchedAt time.Time) error {
+	data, err := json.Marshal(Snapshot{Offers: offers, FetchedAt: fetchedAt})
+	if err != nil {
+		return fmt.Errorf("failed to encode offer snapshot: %w", err)
+	}
+	return s.store.Set(ctx, snapshotKey(subsidiary, datacenter), data, 0)
+}
+
+// snapshotKey builds the storage key: subsidiaries are upper case ("FR"),
+// datacenters lower case ("lon"), whatever the caller passed
+func snapshotKey(subsidiary, datacenter string) string {
+	return snapshotPrefix + strings.ToUpper(subsidiary) + "/" + strings.ToLower(datacenter)
+}
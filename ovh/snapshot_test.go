@@ -0,0 +1,146 @@
+package ovh
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/storage"
+)
+
+// TestSnapshotStore_RoundTrip tests save/load with the memory and file backends
+//
+// Cases:
+//   - Nothing saved yet: found is false, no error
+//   - Saved offers come back unchanged, including addons and fetch time
+//   - Subsidiary and datacenter are case-insensitive, other keys are separate
+//   - The file backend keeps snapshots across a restart
+func TestSnapshotStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	offers := []Offer{
+		{FQN: "24ska01.ram-32g", PlanCode: "24ska01", Price: 15.99, Currency: "GBP", InvoiceName: "KS-1", Datacenter: "lon",
+			Addons: map[string]string{"memory": "ram-32g-24ska01"}},
+		{FQN: "24sk20.ram-64g", PlanCode: "24sk20", Price: 35.99, Currency: "GBP", InvoiceName: "KS-2", Datacenter: "lon"},
+	}
+	fetchedAt := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	fileStore, err := storage.NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	backends := []struct {
+		name  string
+		store storage.Store
+	}{
+		{"memory", storage.NewMemoryStore()},
+		{"file", fileStore},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			snapshots := NewSnapshotStore(backend.store)
+
+			if _, found, err := snapshots.Load(ctx, "FR", "lon"); found || err != nil {
+				t.Fatalf("Load() before Save = found %v, error %v; want false, nil", found, err)
+			}
+
+			if err := snapshots.Save(ctx, "fr", "LON", offers, fetchedAt); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			got, found, err := snapshots.Load(ctx, "FR", "lon")
+			if err != nil || !found {
+				t.Fatalf("Load() = found %v, error %v; want true, nil", found, err)
+			}
+			if !reflect.DeepEqual(got.Offers, offers) || !got.FetchedAt.Equal(fetchedAt) {
+				t.Errorf("Load() = %+v, want offers %+v fetched at %v", got, offers, fetchedAt)
+			}
+
+			if _, found, _ := snapshots.Load(ctx, "FR", "rbx"); found {
+				t.Errorf("Load(FR, rbx) found the FR/lon snapshot")
+			}
+			if _, found, _ := snapshots.Load(ctx, "GB", "lon"); found {
+				t.Errorf("Load(GB, lon) found the FR/lon snapshot")
+			}
+		})
+	}
+
+	// Restart: a new FileStore on the same file still has the snapshot
+	reopened, err := storage.NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() on existing file error = %v", err)
+	}
+	got, found, err := NewSnapshotStore(reopened).Load(ctx, "FR", "lon")
+	if err != nil || !found || !reflect.DeepEqual(got.Offers, offers) {
+		t.Errorf("Load() after restart = %+v, found %v, error %v; want the saved offers", got, found, err)
+	}
+}
+
+// TestSnapshotStore_Overwrite tests that Save replaces the previous snapshot as a whole
+//
+// Cases:
+//   - Fewer offers than before: the removed ones are gone
+//   - Empty list (nothing in stock): saved as an empty snapshot, not "never saved"
+//   - Corrupt stored data: Load returns an error
+func TestSnapshotStore_Overwrite(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemoryStore()
+	snapshots := NewSnapshotStore(backend)
+
+	first := []Offer{{FQN: "a", Price: 10, Currency: "EUR"}, {FQN: "b", Price: 20, Currency: "EUR"}}
+	second := []Offer{{FQN: "b", Price: 18, Currency: "EUR"}}
+	day1 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	_ = snapshots.Save(ctx, "FR", "lon", first, day1)
+	_ = snapshots.Save(ctx, "FR", "lon", second, day2)
+
+	got, _, _ := snapshots.Load(ctx, "FR", "lon")
+	if !reflect.DeepEqual(got.Offers, second) || !got.FetchedAt.Equal(day2) {
+		t.Errorf("Load() after overwrite = %+v, want %+v fetched at %v", got, second, day2)
+	}
+
+	_ = snapshots.Save(ctx, "FR", "lon", nil, day2)
+	got, found, err := snapshots.Load(ctx, "FR", "lon")
+	if err != nil || !found || len(got.Offers) != 0 {
+		t.Errorf("Load() after saving no offers = %+v, found %v, error %v; want an empty snapshot", got, found, err)
+	}
+
+	_ = backend.Set(ctx, "offers/FR/lon", []byte("{broken"), 0)
+	if _, _, err := snapshots.Load(ctx, "FR", "lon"); err == nil {
+		t.Errorf("Load() with corrupt data error = nil, want error")
+	}
+}
+
+// TestSnapshotStore_LegacyKeys tests loading a snapshot saved before Offer had json tags
+// Old snapshots use the Go field names as keys ("PlanCode", "InvoiceName", ...).
+func TestSnapshotStore_LegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStore()
+
+	legacy := `{"offers":[{"FQN":"24ska01.ram-32g","PlanCode":"24ska01","Price":15.99,"Currency":"GBP",` +
+		`"InvoiceName":"KS-1","Datacenter":"lon","Addons":{"memory":"ram-32g-24ska01"},` +
+		`"Specs":{"RAMGB":32,"CPUCores":4,"Storage":"sata"}}],"fetched_at":"2025-06-01T09:00:00Z"}`
+	if err := store.Set(ctx, snapshotKey("FR", "lon"), []byte(legacy), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, found, err := NewSnapshotStore(store).Load(ctx, "FR", "lon")
+	if err != nil || !found {
+		t.Fatalf("Load() = found %v, error %v; want true, nil", found, err)
+	}
+
+	want := []Offer{{
+		FQN: "24ska01.ram-32g", PlanCode: "24ska01", Price: 15.99, Currency: "GBP", InvoiceName: "KS-1", Datacenter: "lon",
+		Addons: map[string]string{"memory": "ram-32g-24ska01"},
+		Specs:  PlanSpecs{RAMGB: 32, CPUCores: 4, Storage: StorageSATA},
+	}}
+	if !reflect.DeepEqual(snapshot.Offers, want) {
+		t.Errorf("Load() offers = %+v, want %+v", snapshot.Offers, want)
+	}
+}
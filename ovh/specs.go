@@ -0,0 +1,164 @@
+package ovh
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Storage types reported in PlanSpecs.Storage
+const (
+	StorageNVMe   = "nvme"
+	StorageSATA   = "sata"
+	StorageHybrid = "hybrid" // SATA disks plus an NVMe cache/boot drive
+)
+
+// PlanSpecs is the hardware of an offer, as far as its names tell
+// Zero values mean "unknown": not every plan names every part.
+type PlanSpecs struct {
+	// RAMGB is the memory size in GB (e.g., 64)
+	RAMGB int `json:"ram_gb"`
+
+	// CPUCores is the number of physical CPU cores (e.g., 8)
+	CPUCores int `json:"cpu_cores"`
+
+	// Storage is StorageNVMe, StorageSATA, StorageHybrid or "" (unknown)
+	Storage string `json:"storage"`
+}
+
+// Patterns for ParsePlanSpecs
+//
+// FQN examples (plan code, then one segment per mandatory addon):
+//
+//	24ska01.ram-32g-ecc-2133.softraid-2x2000sa
+//	24rise01.ram-64g-ecc-3200.softraid-2x512nvme
+//	24adv01.ram-64g-ecc-3200.hybridsoftraid-2x4000sa-1x960nvme
+//
+// Invoice name / description examples:
+//
+//	KS-LE-B | Intel Xeon-D 1521 | 32GB
+//	Intel Xeon E3-1245v5 - 4c/8t - 3.5GHz/3.9GHz
+//	AMD Ryzen 7 Pro 8700GE - 8 cores - 64 GB DDR5
+var (
+	// fqnRAMPattern: "ram-64g" segment of the FQN
+	fqnRAMPattern = regexp.MustCompile(`(?:^|\.)ram-(\d+)g`)
+
+	// nameRAMPattern: "64GB" or "64 GB" in a name; group 1 is "x" for disk
+	// sizes ("2x512GB") and group 3 a disk word ("512GB SSD"), see nameRAM
+	nameRAMPattern = regexp.MustCompile(`(?i)(x\s?)?\b(\d+)\s?GB\b(\s+(?:SSD|NVMe|HDD|SATA))?`)
+
+	// nvmeSSDPattern: "SSD NVMe" / "NVMe SSD" is one NVMe disk, not SSD + NVMe
+	nvmeSSDPattern = regexp.MustCompile(`(?i)ssd\s*nvme|nvme\s*ssd`)
+
+	// coresPattern: "4c/8t" or "8 cores" / "6-core"
+	coresPattern = regexp.MustCompile(`(?i)\b(\d+)c/\d+t\b|\b(\d+)[\s-]?cores?\b`)
+
+	// fqnDisksPattern: disk groups of the storage segment, e.g. "2x512nvme", "2x2000sa", "2x480ssd"
+	fqnDisksPattern = regexp.MustCompile(`\d+x\d+(nvme|sa|ssd)\b`)
+)
+
+// ParsePlanSpecs extracts RAM, CPU cores and storage type from an offer's names
+//
+// Sources, in order of preference:
+//   - RAM: the FQN's "ram-<N>g" addon, else "<N>GB" in the invoice name
+//   - CPU cores: the invoice name ("4c/8t", "8 cores"); FQNs don't carry it
+//   - Storage: the FQN's disk addon ("hybridsoftraid-..." is hybrid; nvme, sa
+//     and ssd disks), else NVMe/SATA/SSD in the invoice name
+//
+// SATA SSDs ("ssd" disks) count as StorageSATA: the type tells the disk
+// interface, which is what limits speed. A plan with both SATA and NVMe
+// disks is StorageHybrid.
+//
+// Parameters:
+//   - invoiceName: Offer.InvoiceName (often "Name | CPU | RAM", may be a plan description)
+//   - fqn: Offer.FQN
+//
+// Returns:
+//   - PlanSpecs: Parsed specs (zero fields for anything not found)
+//
+// Example:
+//
+//	ParsePlanSpecs("RISE-1 | Intel Xeon-E 2386G - 6c/12t", "24rise01.ram-32g-ecc-3200.softraid-2x512nvme")
+//	// PlanSpecs{RAMGB: 32, CPUCores: 6, Storage: "nvme"}
+func ParsePlanSpecs(invoiceName, fqn string) PlanSpecs {
+	var specs PlanSpecs
+
+	if m := fqnRAMPattern.FindStringSubmatch(fqn); m != nil {
+		specs.RAMGB, _ = strconv.Atoi(m[1])
+	} else {
+		specs.RAMGB = nameRAM(invoiceName)
+	}
+
+	if m := coresPattern.FindStringSubmatch(invoiceName); m != nil {
+		// Exactly one of the two alternatives matched
+		specs.CPUCores, _ = strconv.Atoi(m[1] + m[2])
+	}
+
+	specs.Storage = storageFromFQN(fqn)
+	if specs.Storage == "" {
+		specs.Storage = storageFromName(invoiceName)
+	}
+
+	return specs
+}
+
+// nameRAM returns the first "<N>GB" in name that isn't a disk size
+// ("2x512GB" and "512GB SSD" are disks), or 0
+func nameRAM(name string) int {
+	for _, m := range nameRAMPattern.FindAllStringSubmatch(name, -1) {
+		if m[1] == "" && m[3] == "" {
+			gb, _ := strconv.Atoi(m[2])
+			return gb
+		}
+	}
+	return 0
+}
+
+// storageFromFQN reads the storage type from the FQN's disk addon segment
+// Returns "" if the FQN names no disks
+func storageFromFQN(fqn string) string {
+	for _, segment := range strings.Split(fqn, ".") {
+		if strings.HasPrefix(segment, "hybrid") {
+			return StorageHybrid
+		}
+
+		nvme, sata := false, false
+		for _, m := range fqnDisksPattern.FindAllStringSubmatch(segment, -1) {
+			if m[1] == "nvme" {
+				nvme = true
+			} else {
+				sata = true
+			}
+		}
+		switch {
+		case nvme && sata:
+			return StorageHybrid
+		case nvme:
+			return StorageNVMe
+		case sata:
+			return StorageSATA
+		}
+	}
+	return ""
+}
+
+// storageFromName reads the storage type from words in an invoice name
+// Returns "" if the name mentions no disk type
+func storageFromName(name string) string {
+	lower := nvmeSSDPattern.ReplaceAllString(strings.ToLower(name), "nvme")
+	if strings.Contains(lower, "hybrid") {
+		return StorageHybrid
+	}
+
+	nvme := strings.Contains(lower, "nvme")
+	sata := strings.Contains(lower, "sata") || strings.Contains(lower, "ssd") || strings.Contains(lower, "hdd")
+	switch {
+	case nvme && sata:
+		return StorageHybrid
+	case nvme:
+		return StorageNVMe
+	case sata:
+		return StorageSATA
+	}
+	return ""
+}
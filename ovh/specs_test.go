@@ -0,0 +1,105 @@
+package ovh
+
+import "testing"
+
+// TestParsePlanSpecs tests hardware extraction from OVH invoice names and FQNs
+//
+// Data: FQNs and names in the formats of the OVH eco catalog
+// (/order/catalog/public/eco and /dedicated/server/datacenter/availabilities)
+//
+// Testing strategy:
+//   - The FQN wins for RAM and storage; the name fills in what the FQN lacks
+//   - Disk sizes in names ("2x 512GB", "480GB SSD") are not RAM
+//   - Unknown parts stay zero
+func TestParsePlanSpecs(t *testing.T) {
+	tests := []struct {
+		name        string
+		invoiceName string
+		fqn         string
+		want        PlanSpecs
+	}{
+		{
+			name:        "kimsufi, SATA HDD",
+			invoiceName: "KS-1 | Intel Atom N2800",
+			fqn:         "24ska01.ram-4g-noecc-1066.softraid-1x2000sa",
+			want:        PlanSpecs{RAMGB: 4, Storage: StorageSATA},
+		},
+		{
+			name:        "rise, NVMe and cores",
+			invoiceName: "RISE-1 | Intel Xeon-E 2386G - 6c/12t",
+			fqn:         "24rise01.ram-32g-ecc-3200.softraid-2x512nvme",
+			want:        PlanSpecs{RAMGB: 32, CPUCores: 6, Storage: StorageNVMe},
+		},
+		{
+			name:        "advance, hybrid raid",
+			invoiceName: "ADVANCE-2 | AMD EPYC 4344P - 8 cores",
+			fqn:         "24adv02.ram-64g-ecc-4800.hybridsoftraid-2x4000sa-1x960nvme",
+			want:        PlanSpecs{RAMGB: 64, CPUCores: 8, Storage: StorageHybrid},
+		},
+		{
+			name:        "SATA SSD counts as SATA",
+			invoiceName: "SYS-1 | Intel Xeon-E 2136",
+			fqn:         "24sys011.ram-32g-ecc-2666.softraid-2x480ssd",
+			want:        PlanSpecs{RAMGB: 32, Storage: StorageSATA},
+		},
+		{
+			name:        "SATA and NVMe disk groups in one segment",
+			invoiceName: "KS-LE-B",
+			fqn:         "24sk50.ram-64g-ecc-2400.softraid-2x2000sa-2x512nvme",
+			want:        PlanSpecs{RAMGB: 64, Storage: StorageHybrid},
+		},
+		{
+			name:        "eco name only",
+			invoiceName: "Eco Server 1801SK-12 | Intel Xeon E3-1245v5 - 4c/8t | 32GB | 2x 480GB SSD",
+			fqn:         "1801sk12",
+			want:        PlanSpecs{RAMGB: 32, CPUCores: 4, Storage: StorageSATA},
+		},
+		{
+			name:        "description with SSD NVMe",
+			invoiceName: "AMD Ryzen 7 Pro 8700GE - 8-core - 64 GB DDR5 - 2x 512GB SSD NVMe",
+			fqn:         "",
+			want:        PlanSpecs{RAMGB: 64, CPUCores: 8, Storage: StorageNVMe},
+		},
+		{
+			name:        "disk size before RAM in name",
+			invoiceName: "KS-5 | 480GB SSD | 16GB",
+			fqn:         "",
+			want:        PlanSpecs{RAMGB: 16, Storage: StorageSATA},
+		},
+		{
+			name:        "FQN RAM wins over name",
+			invoiceName: "KS-A | 16GB",
+			fqn:         "24ska01.ram-32g-noecc-2133",
+			want:        PlanSpecs{RAMGB: 32},
+		},
+		{
+			name:        "nothing to parse",
+			invoiceName: "KS-B",
+			fqn:         "ks-b.bandwidth-300",
+			want:        PlanSpecs{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePlanSpecs(tt.invoiceName, tt.fqn); got != tt.want {
+				t.Errorf("ParsePlanSpecs(%q, %q) = %+v, want %+v", tt.invoiceName, tt.fqn, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestComputeTotalMonthly_Specs tests that priced offers carry their parsed specs
+func TestComputeTotalMonthly_Specs(t *testing.T) {
+	plans, addons := indexCatalog(&Catalog{
+		Plans: []Plan{{PlanCode: "24rise01", InvoiceName: "RISE-1 | Intel Xeon-E 2386G - 6c/12t", Pricings: monthlyPricing(40)}},
+	})
+
+	offer, err := computeTotalMonthly(plans, addons, "24rise01", "24rise01.ram-32g-ecc-3200.softraid-2x512nvme", "EUR", AddonsFQNMatch)
+	if err != nil {
+		t.Fatalf("computeTotalMonthly() unexpected error: %v", err)
+	}
+	if want := (PlanSpecs{RAMGB: 32, CPUCores: 6, Storage: StorageNVMe}); offer.Specs != want {
+		t.Errorf("computeTotalMonthly() specs = %+v, want %+v", offer.Specs, want)
+	}
+}
@@ -0,0 +1,56 @@
+package ovh
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// subsidiaries lists the OVH subsidiary codes accepted by the public catalog API
+// Each subsidiary has its own currency and tax rate (e.g., GB: GBP, FR: EUR)
+// Sorted, so ListSubsidiaries can return it as-is
+var subsidiaries = []string{
+	"ASIA", "AU", "CA", "CZ", "DE", "ES", "FI", "FR", "GB", "IE", "IN", "IT",
+	"LT", "MA", "NL", "PL", "PT", "QC", "SG", "SN", "TN", "US", "WE", "WS",
+}
+
+// IsKnownSubsidiary reports whether code is an OVH subsidiary (case-insensitive)
+func IsKnownSubsidiary(code string) bool {
+	_, found := slices.BinarySearch(subsidiaries, strings.ToUpper(code))
+	return found
+}
+
+// ListSubsidiaries returns all known subsidiary codes, sorted
+func ListSubsidiaries() []string {
+	return slices.Clone(subsidiaries)
+}
+
+// GetCatalogLocale returns the currency and tax rate OVH uses for a subsidiary
+//
+// Parameters:
+//   - subsidiary: OVH subsidiary code (e.g., "GB")
+//
+// Returns:
+//   - Locale: Currency code, subsidiary and tax rate of the ECO catalog
+//   - error: Any errors during fetch or parse
+func GetCatalogLocale(subsidiary string) (Locale, error) {
+	return GetCatalogLocaleContext(context.Background(), subsidiary)
+}
+
+// GetCatalogLocaleContext is GetCatalogLocale with a context for cancellation
+// The catalog is served from the package cache when fresh (shared with GetTopOffers)
+//
+// Parameters:
+//   - ctx: Context for cancellation
+//   - subsidiary: OVH subsidiary code (e.g., "GB")
+//
+// Returns:
+//   - Locale: Currency code, subsidiary and tax rate of the ECO catalog
+//   - error: Any errors during fetch or parse (ctx.Err() if cancelled)
+func GetCatalogLocaleContext(ctx context.Context, subsidiary string) (Locale, error) {
+	catalog, err := cachedEcoCatalog(ctx, subsidiary)
+	if err != nil {
+		return Locale{}, err
+	}
+	return catalog.Locale, nil
+}
@@ -0,0 +1,52 @@
+package ovh
+
+import "testing"
+
+// TestIsKnownSubsidiary tests subsidiary validation (case-insensitive)
+func TestIsKnownSubsidiary(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"GB", true},
+		{"fr", true},
+		{"ASIA", true},
+		{"UK", false}, // OVH uses GB, not UK
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsKnownSubsidiary(tt.code); got != tt.want {
+			t.Errorf("IsKnownSubsidiary(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	// Returned list is a copy: changing it doesn't affect validation
+	list := ListSubsidiaries()
+	list[0] = "XX"
+	if IsKnownSubsidiary("XX") {
+		t.Errorf("ListSubsidiaries() returned the internal slice")
+	}
+}
+
+// TestGetCatalogLocale tests reading the locale from the mock catalog, then from cache
+func TestGetCatalogLocale(t *testing.T) {
+	server := NewMockServer(t, nil, &Catalog{
+		Locale: Locale{CurrencyCode: "GBP", Subsidiary: "GB", TaxRate: 20},
+	})
+
+	want := Locale{CurrencyCode: "GBP", Subsidiary: "GB", TaxRate: 20}
+	got, err := GetCatalogLocale("GB")
+	if err != nil {
+		t.Fatalf("GetCatalogLocale() unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("GetCatalogLocale() = %+v, want %+v", got, want)
+	}
+
+	// Second call is served from the cache, even with the API gone
+	server.Close()
+	if got, err := GetCatalogLocale("GB"); err != nil || got != want {
+		t.Errorf("GetCatalogLocale() from cache = %+v, %v, want %+v", got, err, want)
+	}
+}
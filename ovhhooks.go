@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/metrics"
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// ovhResponseSizeBuckets are upper bounds (bytes) for OVH response sizes
+// Availabilities are a few hundred KB, the ECO catalog several MB
+var ovhResponseSizeBuckets = []float64{1e4, 1e5, 5e5, 1e6, 2.5e6, 5e6, 1e7, 2.5e7}
+
+// OVH client metrics, exported at GET /metrics next to the webhook metrics
+//
+//	ovh_requests_total{endpoint="catalog/eco",status="200"} 12
+//	ovh_request_errors_total{endpoint="availabilities"} 1
+//	ovh_request_duration_seconds_bucket{endpoint="catalog/eco",le="2.5"} 10
+//	ovh_response_size_bytes_bucket{endpoint="catalog/eco",le="5e+06"} 12
+//
+// Per endpoint, so a slow OVH button can be blamed on the availabilities
+// or on the catalog download.
+var (
+	ovhRequests = metrics.NewCounterVec("ovh_requests_total",
+		"OVH API requests by endpoint and HTTP status (\"error\": no response)", "endpoint", "status")
+	ovhRequestErrors = metrics.NewCounterVec("ovh_request_errors_total",
+		"Failed OVH API requests by endpoint (transport errors and non-200 statuses)", "endpoint")
+	ovhRequestDuration = metrics.NewHistogramVec("ovh_request_duration_seconds",
+		"OVH API request duration in seconds, including the body download", metrics.DefaultDurationBuckets, "endpoint")
+	ovhResponseSize = metrics.NewHistogramVec("ovh_response_size_bytes",
+		"OVH API response body size in bytes (decompressed)", ovhResponseSizeBuckets, "endpoint")
+)
+
+// ovhMetricsHooks records OVH client events as metrics and log lines
+// Installed by main.go with ovh.SetHooks; implements ovh.Hooks and ovh.OffersHooks.
+type ovhMetricsHooks struct{}
+
+// RequestDone records one OVH API request in the ovh_* metrics
+func (ovhMetricsHooks) RequestDone(_ context.Context, info ovh.RequestInfo) {
+	status := "error"
+	if info.StatusCode != 0 {
+		status = strconv.Itoa(info.StatusCode)
+	}
+
+	ovhRequests.Inc(info.Endpoint, status)
+	ovhRequestDuration.Observe(info.Duration.Seconds(), info.Endpoint)
+	if info.Err != nil {
+		ovhRequestErrors.Inc(info.Endpoint)
+		return
+	}
+	ovhResponseSize.Observe(float64(info.Bytes), info.Endpoint)
+}
+
+// OffersDone logs one summary line per GetTopOffers (or GetTopDedicatedOffers) call
+// Logged with the caller's logger, so the line carries the update_id of the
+// request that asked for the offers. A 0 duration means the data was cached.
+func (ovhMetricsHooks) OffersDone(ctx context.Context, info ovh.OffersInfo) {
+	args := []any{
+		"subsidiary", info.Subsidiary,
+		"catalog", info.CatalogName,
+		"availabilities_ms", info.Availabilities.Milliseconds(),
+		"catalog_ms", info.Catalog.Milliseconds(),
+		"offers_considered", info.OffersConsidered,
+		"offers_returned", info.OffersReturned,
+	}
+	if info.Err != nil {
+		logger.FromContext(ctx).Warn("OVH offers fetch failed", append(args, "error", info.Err)...)
+		return
+	}
+	logger.FromContext(ctx).Info("OVH offers fetched", args...)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/logger"
+	"github.com/Alrem/run-tbot/ovh"
+)
+
+// TestOVHMetricsHooks_RequestDone tests the ovh_* metrics of one request
+//
+// Cases:
+//   - Success: counted under its status, duration and size observed
+//   - HTTP 503: counted under "503" and as an error, no size
+//   - No response: counted under "error"
+//
+// The metrics are process-wide, so the test checks how much they grew
+// (go test -count=2 runs it twice in one process).
+func TestOVHMetricsHooks_RequestDone(t *testing.T) {
+	const endpoint = "test/endpoint"
+	hooks := ovhMetricsHooks{}
+	ctx := context.Background()
+
+	statuses := []string{"200", "503", "error"}
+	requestsBefore := make(map[string]float64)
+	for _, status := range statuses {
+		requestsBefore[status] = ovhRequests.Value(endpoint, status)
+	}
+	errorsBefore := ovhRequestErrors.Value(endpoint)
+	durationsBefore := ovhRequestDuration.Count(endpoint)
+	sizesBefore := ovhResponseSize.Count(endpoint)
+
+	hooks.RequestDone(ctx, ovh.RequestInfo{Endpoint: endpoint, Duration: time.Second, StatusCode: 200, Bytes: 2048})
+	hooks.RequestDone(ctx, ovh.RequestInfo{Endpoint: endpoint, Duration: time.Second, StatusCode: 503, Err: errors.New("HTTP error: status 503")})
+	hooks.RequestDone(ctx, ovh.RequestInfo{Endpoint: endpoint, Duration: time.Second, Err: context.DeadlineExceeded})
+
+	for _, status := range statuses {
+		if got := ovhRequests.Value(endpoint, status) - requestsBefore[status]; got != 1 {
+			t.Errorf("ovh_requests_total{status=%q} grew by %v, want 1", status, got)
+		}
+	}
+	if got := ovhRequestErrors.Value(endpoint) - errorsBefore; got != 2 {
+		t.Errorf("ovh_request_errors_total grew by %v, want 2", got)
+	}
+	if got := ovhRequestDuration.Count(endpoint) - durationsBefore; got != 3 {
+		t.Errorf("ovh_request_duration_seconds count grew by %d, want 3", got)
+	}
+	if got := ovhResponseSize.Count(endpoint) - sizesBefore; got != 1 {
+		t.Errorf("ovh_response_size_bytes count grew by %d, want 1 (successful requests only)", got)
+	}
+}
+
+// TestOVHMetricsHooks_OffersDone tests the summary line logged per GetTopOffers call
+func TestOVHMetricsHooks_OffersDone(t *testing.T) {
+	handler := newRecordingHandler()
+	ctx := logger.WithContext(context.Background(), slog.New(handler))
+
+	ovhMetricsHooks{}.OffersDone(ctx, ovh.OffersInfo{
+		Subsidiary:       "FR",
+		CatalogName:      "dedicated",
+		Availabilities:   1200 * time.Millisecond,
+		Catalog:          3400 * time.Millisecond,
+		OffersConsidered: 42,
+		OffersReturned:   3,
+	})
+
+	records := *handler.records
+	if len(records) != 1 {
+		t.Fatalf("got %d log lines, want 1: %v", len(records), records)
+	}
+	want := map[string]any{
+		"msg":               "OVH offers fetched",
+		"subsidiary":        "FR",
+		"catalog":           "dedicated",
+		"availabilities_ms": int64(1200),
+		"catalog_ms":        int64(3400),
+		"offers_considered": int64(42),
+		"offers_returned":   int64(3),
+	}
+	for key, value := range want {
+		if records[0][key] != value {
+			t.Errorf("log line %s = %v (%T), want %v", key, records[0][key], records[0][key], value)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Alrem/run-tbot/logger"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Long polling settings (POLLING / -polling)
+const (
+	// pollingTimeout is how long (seconds) Telegram holds a getUpdates request
+	// open when there are no updates. Shorter than the 30s shutdown budget,
+	// so a pending request never delays shutdown past it
+	pollingTimeout = 10
+
+	// pollingRetryDelay is the pause after a failed getUpdates call
+	// (network down, wrong token, webhook still set, ...)
+	pollingRetryDelay = 3 * time.Second
+)
+
+// updatesGetter is the part of *tgbotapi.BotAPI used for long polling
+// (an interface so tests can feed updates without Telegram)
+type updatesGetter interface {
+	GetUpdates(config tgbotapi.UpdateConfig) ([]tgbotapi.Update, error)
+}
+
+// runPolling fetches updates with getUpdates and routes them like the webhook does.
+// Meant for local development: no public URL, ngrok or setWebhook needed.
+//
+// How long polling works:
+//   - getUpdates waits up to pollingTimeout seconds for new updates
+//   - Offset = last update_id + 1 confirms everything before it,
+//     so Telegram never delivers an update twice
+//   - Updates are processed one at a time, in order
+//
+// Parameters:
+//   - ctx: Cancelled on shutdown; the loop returns after the current request
+//   - api: Bot API (usually *tgbotapi.BotAPI)
+//   - instance: Bot the updates belong to (sender, cfg, name for logs)
+//   - allowedUpdates: Update types Telegram should deliver
+func runPolling(ctx context.Context, api updatesGetter, instance botInstance, allowedUpdates []string) {
+	routeUpdate := newUpdateRouter(instance.cfg)
+	log := slog.Default().With("bot", instance.label())
+
+	offset := 0
+	for ctx.Err() == nil {
+		updates, err := api.GetUpdates(tgbotapi.UpdateConfig{
+			Offset:         offset,
+			Timeout:        pollingTimeout,
+			AllowedUpdates: allowedUpdates,
+		})
+		if err != nil {
+			log.Warn("Failed to get updates", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollingRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			// Stop between updates on shutdown: the rest isn't confirmed
+			// by a next getUpdates, so Telegram delivers it again after restart
+			if ctx.Err() != nil {
+				return
+			}
+			offset = update.UpdateID + 1
+
+			updateLog := updateLogger(instance, update, "")
+			updateLog.Info("Received update",
+				"has_message", update.Message != nil,
+				"has_callback", update.CallbackQuery != nil)
+			routeUpdate(logger.WithContext(ctx, updateLog), instance.sender, update, instance.cfg)
+		}
+	}
+}
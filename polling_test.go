@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Alrem/run-tbot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeUpdatesGetter returns the queued batches one per call, then cancels
+// the polling context so runPolling returns
+type fakeUpdatesGetter struct {
+	mu      sync.Mutex
+	batches [][]tgbotapi.Update
+	errs    []error
+	configs []tgbotapi.UpdateConfig
+	cancel  context.CancelFunc
+}
+
+func (f *fakeUpdatesGetter) GetUpdates(c tgbotapi.UpdateConfig) ([]tgbotapi.Update, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs = append(f.configs, c)
+
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	if len(f.batches) == 0 {
+		f.cancel()
+		return nil, nil
+	}
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+	return batch, nil
+}
+
+// helpUpdateValue is helpUpdate decoded, with its own update_id
+func helpUpdateValue(t *testing.T, updateID int, userID int64) tgbotapi.Update {
+	t.Helper()
+	var update tgbotapi.Update
+	if err := json.Unmarshal([]byte(helpUpdate(userID)), &update); err != nil {
+		t.Fatal(err)
+	}
+	update.UpdateID = updateID
+	return update
+}
+
+// TestRunPolling tests that polled updates are routed and confirmed with the next offset
+func TestRunPolling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	getter := &fakeUpdatesGetter{
+		batches: [][]tgbotapi.Update{
+			{helpUpdateValue(t, 41, 700), helpUpdateValue(t, 42, 701)},
+			{helpUpdateValue(t, 43, 702)},
+		},
+		cancel: cancel,
+	}
+	sender := &countingSender{}
+	instance := botInstance{sender: sender, cfg: &config.Config{}}
+
+	runPolling(ctx, getter, instance, []string{"message"})
+
+	if sender.sends != 3 {
+		t.Errorf("sends = %d, want 3 (one /help reply per update)", sender.sends)
+	}
+
+	wantOffsets := []int{0, 43, 44}
+	if len(getter.configs) != len(wantOffsets) {
+		t.Fatalf("getUpdates called %d times, want %d", len(getter.configs), len(wantOffsets))
+	}
+	for i, c := range getter.configs {
+		if c.Offset != wantOffsets[i] {
+			t.Errorf("call %d offset = %d, want %d", i+1, c.Offset, wantOffsets[i])
+		}
+		if c.Timeout != pollingTimeout || len(c.AllowedUpdates) != 1 || c.AllowedUpdates[0] != "message" {
+			t.Errorf("call %d config = %+v, want timeout %d and allowed_updates [message]", i+1, c, pollingTimeout)
+		}
+	}
+}
+
+// TestRunPolling_StopsOnShutdownAfterError tests that a failing getUpdates
+// doesn't keep runPolling from returning when the context is cancelled
+func TestRunPolling_StopsOnShutdownAfterError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	getter := &fakeUpdatesGetter{errs: []error{errors.New("network down")}, cancel: cancel}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runPolling(ctx, getter, botInstance{sender: &countingSender{}, cfg: &config.Config{}}, nil)
+	}()
+
+	// The loop is waiting pollingRetryDelay after the error; shutdown must not wait for it
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runPolling did not return after cancellation")
+	}
+}
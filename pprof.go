@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/Alrem/run-tbot/middleware"
+)
+
+// pprofPrefix is where the profiling endpoints are mounted (ENABLE_PPROF)
+const pprofPrefix = "/debug/pprof/"
+
+// newPprofHandler returns the net/http/pprof endpoints behind a bearer token.
+//
+// Usage (30-second CPU profile from a running instance):
+//
+//	go tool pprof -http=: "https://<service>/debug/pprof/profile?seconds=30" \
+//	  (with header "Authorization: Bearer $PPROF_TOKEN", e.g. via curl -H ... -o cpu.prof)
+//
+// Why a separate mux?
+//   - Importing net/http/pprof registers the same handlers on http.DefaultServeMux
+//   - We never serve DefaultServeMux, so they're only reachable through this handler
+//
+// Parameters:
+//   - token: PPROF_TOKEN required in the Authorization header
+//
+// Returns:
+//   - http.Handler: Protected handler for everything under /debug/pprof/
+func newPprofHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(pprofPrefix, pprof.Index) // Index also serves named profiles (heap, goroutine, ...)
+	mux.HandleFunc(pprofPrefix+"cmdline", pprof.Cmdline)
+	mux.HandleFunc(pprofPrefix+"profile", pprof.Profile)
+	mux.HandleFunc(pprofPrefix+"symbol", pprof.Symbol)
+	mux.HandleFunc(pprofPrefix+"trace", pprof.Trace)
+
+	return middleware.RequireBearerToken(token, mux)
+}
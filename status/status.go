@@ -0,0 +1,110 @@
+// Package status tracks when the bot last talked to its upstreams successfully,
+// so the health endpoint can report more than "OK" without calling them.
+//
+// Why a registry instead of live checks?
+//   - Health checks run every few seconds; pinging Telegram or OVH each time
+//     would be slow and could hit rate limits
+//   - The bot already talks to both upstreams, recording the last success is free
+//
+// Writers:
+//   - bot.StatusSender records successful Telegram API calls
+//   - the ovh client records successful (non-cached) fetches
+//
+// Reader: main.go's health handler (GET /healthz), via Registry.Snapshot.
+package status
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// Registry holds the start time and last-success timestamps
+// Timestamps are stored as Unix nanoseconds in atomics (0 = never),
+// so recording from many goroutines needs no lock.
+type Registry struct {
+	started      time.Time
+	lastTelegram atomic.Int64
+	lastOVH      atomic.Int64
+}
+
+// NewRegistry creates a registry whose uptime starts now
+func NewRegistry() *Registry {
+	return &Registry{started: time.Now()}
+}
+
+// Default is the registry shared by the bot wrapper, the ovh client and main.go
+var Default = NewRegistry()
+
+// RecordTelegramSuccess records a successful Telegram API call at t
+func (r *Registry) RecordTelegramSuccess(t time.Time) {
+	r.lastTelegram.Store(t.UnixNano())
+}
+
+// RecordOVHFetch records a successful OVH API fetch at t
+func (r *Registry) RecordOVHFetch(t time.Time) {
+	r.lastOVH.Store(t.UnixNano())
+}
+
+// Report is the health document served as JSON
+// Timestamps are null until the first success.
+type Report struct {
+	Status              string     `json:"status"`
+	UptimeSeconds       int64      `json:"uptime_seconds"`
+	Version             string     `json:"version"`
+	Commit              string     `json:"commit"`
+	LastTelegramSuccess *time.Time `json:"last_telegram_success"`
+	LastOVHFetch        *time.Time `json:"last_ovh_fetch"`
+}
+
+// Snapshot builds a Report as of now
+// Cheap: reads a few atomics, no upstream calls.
+//
+// Parameters:
+//   - now: Current time (passed in so tests control uptime)
+//
+// Returns:
+//   - Report: Current status (Status is always "ok" - the process is serving)
+func (r *Registry) Snapshot(now time.Time) Report {
+	version, commit := BuildVersion()
+	return Report{
+		Status:              "ok",
+		UptimeSeconds:       int64(now.Sub(r.started) / time.Second),
+		Version:             version,
+		Commit:              commit,
+		LastTelegramSuccess: timestamp(r.lastTelegram.Load()),
+		LastOVHFetch:        timestamp(r.lastOVH.Load()),
+	}
+}
+
+// timestamp converts stored Unix nanoseconds to a UTC time (nil for 0 = never)
+func timestamp(nanos int64) *time.Time {
+	if nanos == 0 {
+		return nil
+	}
+	t := time.Unix(0, nanos).UTC()
+	return &t
+}
+
+// BuildVersion returns the module version and VCS commit embedded by "go build"
+//
+// Returns:
+//   - string: Module version ("(devel)" for local builds, "unknown" without build info)
+//   - string: vcs.revision setting ("unknown" when not built from a git checkout)
+func BuildVersion() (version, commit string) {
+	version, commit = "unknown", "unknown"
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return version, commit
+	}
+	if info.Main.Version != "" {
+		version = info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			commit = setting.Value
+		}
+	}
+	return version, commit
+}
@@ -0,0 +1,67 @@
+package status
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestRegistry_Snapshot tests uptime and that timestamps appear after activity
+func TestRegistry_Snapshot(t *testing.T) {
+	r := NewRegistry()
+
+	report := r.Snapshot(r.started.Add(90 * time.Second))
+	if report.Status != "ok" {
+		t.Errorf("Status = %q, want %q", report.Status, "ok")
+	}
+	if report.UptimeSeconds != 90 {
+		t.Errorf("UptimeSeconds = %d, want 90", report.UptimeSeconds)
+	}
+	if report.LastTelegramSuccess != nil || report.LastOVHFetch != nil {
+		t.Errorf("fresh registry timestamps = %v, %v, want nil", report.LastTelegramSuccess, report.LastOVHFetch)
+	}
+	if report.Version == "" || report.Commit == "" {
+		t.Errorf("Version/Commit empty: %q/%q", report.Version, report.Commit)
+	}
+
+	telegramAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ovhAt := telegramAt.Add(time.Minute)
+	r.RecordTelegramSuccess(telegramAt)
+	r.RecordOVHFetch(ovhAt)
+
+	report = r.Snapshot(time.Now())
+	if report.LastTelegramSuccess == nil || !report.LastTelegramSuccess.Equal(telegramAt) {
+		t.Errorf("LastTelegramSuccess = %v, want %v", report.LastTelegramSuccess, telegramAt)
+	}
+	if report.LastOVHFetch == nil || !report.LastOVHFetch.Equal(ovhAt) {
+		t.Errorf("LastOVHFetch = %v, want %v", report.LastOVHFetch, ovhAt)
+	}
+}
+
+// TestReport_JSON tests the JSON field names (the schema monitoring relies on)
+func TestReport_JSON(t *testing.T) {
+	r := NewRegistry()
+	r.RecordOVHFetch(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+
+	data, err := json.Marshal(r.Snapshot(time.Now()))
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+
+	for _, key := range []string{"status", "uptime_seconds", "version", "commit", "last_telegram_success", "last_ovh_fetch"} {
+		if _, ok := doc[key]; !ok {
+			t.Errorf("JSON missing key %q: %s", key, data)
+		}
+	}
+	if doc["last_telegram_success"] != nil {
+		t.Errorf("last_telegram_success = %v, want null", doc["last_telegram_success"])
+	}
+	if doc["last_ovh_fetch"] != "2024-05-01T10:00:00Z" {
+		t.Errorf("last_ovh_fetch = %v, want 2024-05-01T10:00:00Z", doc["last_ovh_fetch"])
+	}
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Key layout owned by AuditStore
+const (
+	// auditEntriesPrefix + slot (zero-padded, 0 to AuditCapacity-1) -> JSON AuditEntry
+	auditEntriesPrefix = "audit/entries/"
+
+	// auditNextKey -> decimal sequence number of the next entry
+	auditNextKey = "audit/next"
+)
+
+// AuditCapacity is how many audit entries are kept
+// The log is a ring: entry N is written to slot N % AuditCapacity,
+// overwriting the entry AuditCapacity writes before it.
+const AuditCapacity = 1000
+
+// AuditEntry records one use (or attempted use) of a private feature
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	UserID   int64     `json:"user_id"`
+	Username string    `json:"username,omitempty"` // Without "@"
+	Feature  string    `json:"feature"`            // e.g., "/ovh", "button:ovh"
+	Allowed  bool      `json:"allowed"`
+	Params   string    `json:"params,omitempty"` // Command arguments, truncated
+}
+
+// AuditStore keeps the last AuditCapacity audit entries in a Store
+//
+// Appends from one process are serialized by a mutex. Two instances
+// appending at the same moment may write the same slot, losing one entry.
+//
+// Safe for concurrent use.
+type AuditStore struct {
+	store Store
+	mu    sync.Mutex // Serializes the read-modify-write of auditNextKey
+}
+
+// NewAuditStore creates an AuditStore on top of store
+//
+// Parameters:
+//   - store: Backend (see Open)
+//
+// Returns:
+//   - *AuditStore: Ready-to-use audit store
+func NewAuditStore(store Store) *AuditStore {
+	return &AuditStore{store: store}
+}
+
+// AppendAudit adds an entry, overwriting the oldest one once the log is full
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - entry: Entry to record
+//
+// Returns:
+//   - error: If a storage call fails or the stored sequence number is corrupt
+func (s *AuditStore) AppendAudit(ctx context.Context, entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, found, err := s.store.Get(ctx, auditNextKey)
+	if err != nil {
+		return err
+	}
+	var next int64
+	if found {
+		next, err = strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to decode audit sequence number: %w", err)
+		}
+	}
+
+	if err := s.store.Set(ctx, auditSlotKey(next%AuditCapacity), data, 0); err != nil {
+		return err
+	}
+	return s.store.Set(ctx, auditNextKey, []byte(strconv.FormatInt(next+1, 10)), 0)
+}
+
+// RecentAudit returns the newest entries, newest first
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//   - n: Maximum number of entries (at most AuditCapacity are stored)
+//
+// Returns:
+//   - []AuditEntry: Up to n entries
+//   - error: If the storage call fails or an entry is corrupt
+func (s *AuditStore) RecentAudit(ctx context.Context, n int) ([]AuditEntry, error) {
+	entries, err := s.store.List(ctx, auditEntriesPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := make([]AuditEntry, 0, len(entries))
+	for _, e := range entries {
+		var entry AuditEntry
+		if err := json.Unmarshal(e.Value, &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry %s: %w", e.Key, err)
+		}
+		audit = append(audit, entry)
+	}
+
+	// Slots wrap around, so key order isn't time order
+	slices.SortStableFunc(audit, func(a, b AuditEntry) int {
+		return b.Time.Compare(a.Time)
+	})
+	if len(audit) > n {
+		audit = audit[:n]
+	}
+	return audit, nil
+}
+
+// auditSlotKey is the Store key of a ring slot ("audit/entries/0042")
+func auditSlotKey(slot int64) string {
+	return fmt.Sprintf("%s%04d", auditEntriesPrefix, slot)
+}
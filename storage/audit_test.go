@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAuditStore tests appending and reading back audit entries
+//
+// Cases:
+//   - RecentAudit returns the newest first, limited to n
+//   - After more than AuditCapacity appends only the last AuditCapacity remain
+func TestAuditStore(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewAuditStore(backend)
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	total := AuditCapacity + 5
+	for i := range total {
+		entry := AuditEntry{Time: start.Add(time.Duration(i) * time.Second), UserID: int64(i), Feature: "/ovh", Allowed: i%2 == 0}
+		if err := store.AppendAudit(ctx, entry); err != nil {
+			t.Fatalf("AppendAudit(%d) error = %v", i, err)
+		}
+	}
+
+	recent, err := store.RecentAudit(ctx, 3)
+	if err != nil {
+		t.Fatalf("RecentAudit() error = %v", err)
+	}
+	if len(recent) != 3 || recent[0].UserID != int64(total-1) || recent[2].UserID != int64(total-3) {
+		t.Errorf("RecentAudit(3) = %+v, want entries %d, %d, %d", recent, total-1, total-2, total-3)
+	}
+
+	all, _ := store.RecentAudit(ctx, 2*AuditCapacity)
+	if len(all) != AuditCapacity {
+		t.Fatalf("stored entries = %d, want %d (ring)", len(all), AuditCapacity)
+	}
+	if oldest := all[len(all)-1]; oldest.UserID != int64(total-AuditCapacity) {
+		t.Errorf("oldest entry = %d, want %d (the first ones are overwritten)", oldest.UserID, total-AuditCapacity)
+	}
+}
+
+// TestAuditStore_Empty tests reading an empty log
+func TestAuditStore_Empty(t *testing.T) {
+	store := NewAuditStore(NewMemoryStore())
+
+	recent, err := store.RecentAudit(context.Background(), 10)
+	if err != nil || len(recent) != 0 {
+		t.Errorf("RecentAudit() = %v, %v; want empty, nil", recent, err)
+	}
+}
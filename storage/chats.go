@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Preferences are per-chat settings chosen by users
+//
+// The zero value means "use the defaults", so a chat that never changed
+// anything doesn't need a stored key.
+type Preferences struct {
+	// GoodMorning enables the daily /goodmorning message
+	GoodMorning bool `json:"good_morning,omitempty"`
+
+	// Datacenter is the preferred OVH datacenter code (e.g., "rbx")
+	// Empty means the bot's default datacenter
+	Datacenter string `json:"datacenter,omitempty"`
+}
+
+// Key prefixes owned by ChatStore
+const (
+	// preferencesPrefix + chat ID -> JSON Preferences
+	preferencesPrefix = "prefs/"
+
+	// subscriptionsPrefix + chat ID + "/" + FQN -> empty value
+	subscriptionsPrefix = "subs/"
+
+	// knownChatsPrefix + chat ID -> empty value (chats the bot has seen)
+	knownChatsPrefix = "chats/"
+)
+
+// ChatStore keeps chat preferences and subscriptions in a Store
+//
+// Method groups:
+//   - Chat preferences: one Preferences value per chat
+//   - Subscriptions: OVH servers (by FQN) a chat wants to be notified about
+//   - Known chats: every chat the bot has received a message in (for /broadcast)
+//
+// Safe for concurrent use (as safe as the underlying Store).
+type ChatStore struct {
+	store Store
+}
+
+// NewChatStore creates a ChatStore on top of store
+//
+// Parameters:
+//   - store: Backend (see Open)
+//
+// Returns:
+//   - *ChatStore: Ready-to-use chat store
+func NewChatStore(store Store) *ChatStore {
+	return &ChatStore{store: store}
+}
+
+// SaveChatPreferences stores prefs for a chat, replacing previous ones
+func (s *ChatStore) SaveChatPreferences(ctx context.Context, chatID int64, prefs Preferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	return s.store.Set(ctx, preferencesKey(chatID), data, 0)
+}
+
+// LoadChatPreferences returns the chat's preferences
+// Returns zero Preferences (defaults) if the chat never saved any
+func (s *ChatStore) LoadChatPreferences(ctx context.Context, chatID int64) (Preferences, error) {
+	var prefs Preferences
+
+	data, found, err := s.store.Get(ctx, preferencesKey(chatID))
+	if err != nil || !found {
+		return prefs, err
+	}
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return Preferences{}, fmt.Errorf("failed to decode preferences of chat %d: %w", chatID, err)
+	}
+	return prefs, nil
+}
+
+// SaveSubscription subscribes a chat to a server FQN
+// Saving the same subscription twice is not an error
+func (s *ChatStore) SaveSubscription(ctx context.Context, chatID int64, fqn string) error {
+	return s.store.Set(ctx, subscriptionsKey(chatID)+fqn, nil, 0)
+}
+
+// DeleteSubscription removes a subscription
+// Deleting a missing subscription is not an error
+func (s *ChatStore) DeleteSubscription(ctx context.Context, chatID int64, fqn string) error {
+	return s.store.Delete(ctx, subscriptionsKey(chatID)+fqn)
+}
+
+// ListSubscriptions returns a chat's subscribed FQNs (sorted)
+func (s *ChatStore) ListSubscriptions(ctx context.Context, chatID int64) ([]string, error) {
+	prefix := subscriptionsKey(chatID)
+
+	entries, err := s.store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// List is sorted by key, and all keys share the prefix: FQNs come out sorted
+	fqns := make([]string, 0, len(entries))
+	for _, e := range entries {
+		fqns = append(fqns, strings.TrimPrefix(e.Key, prefix))
+	}
+	return fqns, nil
+}
+
+// ListSubscribers returns every subscription, as the chats subscribed to each FQN
+// One List call for all chats, for notifiers that check many FQNs at once.
+//
+// Returns:
+//   - map[string][]int64: Subscribed chat IDs by FQN (empty without subscriptions)
+//   - error: If the storage call fails or a key is corrupt
+func (s *ChatStore) ListSubscribers(ctx context.Context) (map[string][]int64, error) {
+	entries, err := s.store.List(ctx, subscriptionsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribers := make(map[string][]int64)
+	for _, e := range entries {
+		// "subs/12345/24sk20.ram-32g" -> "12345", "24sk20.ram-32g"
+		chatText, fqn, ok := strings.Cut(strings.TrimPrefix(e.Key, subscriptionsPrefix), "/")
+		chatID, err := strconv.ParseInt(chatText, 10, 64)
+		if !ok || err != nil || fqn == "" {
+			return nil, fmt.Errorf("failed to decode subscription key %s", e.Key)
+		}
+		subscribers[fqn] = append(subscribers[fqn], chatID)
+	}
+	return subscribers, nil
+}
+
+// SaveKnownChat records that the bot has seen a chat
+// Saving the same chat twice is not an error
+func (s *ChatStore) SaveKnownChat(ctx context.Context, chatID int64) error {
+	return s.store.Set(ctx, knownChatsPrefix+strconv.FormatInt(chatID, 10), nil, 0)
+}
+
+// ListKnownChats returns the IDs of all chats saved with SaveKnownChat
+// Keys sort as strings ("chats/-100", "chats/12"), so IDs are not in numeric order
+func (s *ChatStore) ListKnownChats(ctx context.Context) ([]int64, error) {
+	entries, err := s.store.List(ctx, knownChatsPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	chats := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		chatID, err := strconv.ParseInt(strings.TrimPrefix(e.Key, knownChatsPrefix), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode known chat key %s: %w", e.Key, err)
+		}
+		chats = append(chats, chatID)
+	}
+	return chats, nil
+}
+
+// preferencesKey is the Store key of a chat's preferences ("prefs/12345")
+func preferencesKey(chatID int64) string {
+	return preferencesPrefix + strconv.FormatInt(chatID, 10)
+}
+
+// subscriptionsKey is the key prefix of a chat's subscriptions ("subs/12345/")
+// The trailing slash keeps chat 1 from listing chat 12's subscriptions
+func subscriptionsKey(chatID int64) string {
+	return subscriptionsPrefix + strconv.FormatInt(chatID, 10) + "/"
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestChatStore_Preferences tests save/load of chat preferences
+func TestChatStore_Preferences(t *testing.T) {
+	store := NewChatStore(NewMemoryStore())
+	ctx := context.Background()
+
+	// Unknown chat: defaults, no error
+	prefs, err := store.LoadChatPreferences(ctx, 1)
+	if err != nil || prefs != (Preferences{}) {
+		t.Fatalf("LoadChatPreferences(unknown) = %+v, %v; want zero value, nil", prefs, err)
+	}
+
+	want := Preferences{GoodMorning: true, Datacenter: "rbx"}
+	if err := store.SaveChatPreferences(ctx, 1, want); err != nil {
+		t.Fatalf("SaveChatPreferences() error = %v", err)
+	}
+	if got, _ := store.LoadChatPreferences(ctx, 1); got != want {
+		t.Errorf("LoadChatPreferences() = %+v, want %+v", got, want)
+	}
+	if got, _ := store.LoadChatPreferences(ctx, 2); got != (Preferences{}) {
+		t.Errorf("other chat LoadChatPreferences() = %+v, want zero value", got)
+	}
+}
+
+// TestChatStore_Subscriptions tests subscribe, unsubscribe and listing
+//
+// Cases:
+//   - List is sorted and duplicate saves are ignored
+//   - Deleting a missing subscription is a no-op
+//   - Chats are independent
+func TestChatStore_Subscriptions(t *testing.T) {
+	store := NewChatStore(NewMemoryStore())
+	ctx := context.Background()
+
+	for _, fqn := range []string{"24sk20.ram-32g", "22sk10.ram-16g", "24sk20.ram-32g"} {
+		if err := store.SaveSubscription(ctx, 1, fqn); err != nil {
+			t.Fatalf("SaveSubscription(%q) error = %v", fqn, err)
+		}
+	}
+	got, _ := store.ListSubscriptions(ctx, 1)
+	if want := []string{"22sk10.ram-16g", "24sk20.ram-32g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSubscriptions() = %v, want %v", got, want)
+	}
+
+	if err := store.DeleteSubscription(ctx, 1, "22sk10.ram-16g"); err != nil {
+		t.Fatalf("DeleteSubscription() error = %v", err)
+	}
+	if err := store.DeleteSubscription(ctx, 1, "missing"); err != nil {
+		t.Errorf("DeleteSubscription(missing) error = %v, want nil", err)
+	}
+	got, _ = store.ListSubscriptions(ctx, 1)
+	if want := []string{"24sk20.ram-32g"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSubscriptions() after delete = %v, want %v", got, want)
+	}
+
+	if got, _ := store.ListSubscriptions(ctx, 2); len(got) != 0 {
+		t.Errorf("ListSubscriptions(other chat) = %v, want empty", got)
+	}
+}
+
+// TestChatStore_ListSubscribers tests listing every subscription by FQN
+//
+// Cases:
+//   - Chats are grouped per FQN, negative (group) IDs round-trip
+//   - A corrupt key is an error
+func TestChatStore_ListSubscribers(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewChatStore(backend)
+	ctx := context.Background()
+
+	if got, err := store.ListSubscribers(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("ListSubscribers(empty) = %v, %v; want empty, nil", got, err)
+	}
+
+	_ = store.SaveSubscription(ctx, 1, "24sk20.ram-32g")
+	_ = store.SaveSubscription(ctx, -100, "24sk20.ram-32g")
+	_ = store.SaveSubscription(ctx, 1, "22sk10.ram-16g")
+
+	got, err := store.ListSubscribers(ctx)
+	if err != nil {
+		t.Fatalf("ListSubscribers() error = %v", err)
+	}
+	want := map[string][]int64{"24sk20.ram-32g": {-100, 1}, "22sk10.ram-16g": {1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSubscribers() = %v, want %v", got, want)
+	}
+
+	_ = backend.Set(ctx, subscriptionsPrefix+"not-a-chat/24sk20.ram-32g", nil, 0)
+	if _, err := store.ListSubscribers(ctx); err == nil {
+		t.Error("ListSubscribers() with a corrupt key: error = nil, want an error")
+	}
+}
+
+// TestChatStore_KnownChats tests recording and listing known chats
+//
+// Cases:
+//   - Duplicate saves are ignored, negative (group) IDs round-trip
+//   - A corrupt key is an error
+func TestChatStore_KnownChats(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewChatStore(backend)
+	ctx := context.Background()
+
+	if got, err := store.ListKnownChats(ctx); err != nil || len(got) != 0 {
+		t.Fatalf("ListKnownChats(empty) = %v, %v; want empty, nil", got, err)
+	}
+
+	for _, chatID := range []int64{12, -100123, 12} {
+		if err := store.SaveKnownChat(ctx, chatID); err != nil {
+			t.Fatalf("SaveKnownChat(%d) error = %v", chatID, err)
+		}
+	}
+	got, _ := store.ListKnownChats(ctx)
+	if want := []int64{-100123, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListKnownChats() = %v, want %v", got, want)
+	}
+
+	_ = backend.Set(ctx, "chats/oops", nil, 0)
+	if _, err := store.ListKnownChats(ctx); err == nil {
+		t.Errorf("ListKnownChats() with a corrupt key error = nil, want error")
+	}
+}
+
+// TestChatStore_Concurrent checks the store under the race detector (go test -race)
+func TestChatStore_Concurrent(t *testing.T) {
+	store := NewChatStore(NewMemoryStore())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func(chatID int64) {
+			defer wg.Done()
+			_ = store.SaveChatPreferences(ctx, chatID, Preferences{GoodMorning: true})
+			_ = store.SaveSubscription(ctx, chatID, "fqn")
+			_, _ = store.LoadChatPreferences(ctx, chatID)
+			_, _ = store.ListSubscriptions(ctx, chatID)
+		}(int64(i))
+	}
+	wg.Wait()
+}
+
+// TestChatStore_KeyLayout tests the keys ChatStore writes to the Store
+//
+// Cases:
+//   - Chat 1 doesn't see chat 12's subscriptions (prefix "subs/1/", not "subs/1")
+//   - Undecodable preferences are an error, not silently reset to defaults
+func TestChatStore_KeyLayout(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewChatStore(backend)
+	ctx := context.Background()
+
+	_ = store.SaveSubscription(ctx, 12, "24sk20.ram-32g")
+	if got, _ := store.ListSubscriptions(ctx, 1); len(got) != 0 {
+		t.Errorf("ListSubscriptions(1) = %v, want chat 12's subscription excluded", got)
+	}
+	if _, found, _ := backend.Get(ctx, "subs/12/24sk20.ram-32g"); !found {
+		t.Errorf("subscription key subs/12/24sk20.ram-32g not found in the store")
+	}
+
+	_ = backend.Set(ctx, "prefs/5", []byte("not json"), 0)
+	if _, err := store.LoadChatPreferences(ctx, 5); err == nil {
+		t.Errorf("LoadChatPreferences() with corrupt data error = nil, want error")
+	}
+}
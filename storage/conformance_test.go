@@ -0,0 +1,235 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// storeFactory creates an empty Store for one conformance subtest
+//
+// Returns:
+//   - Store: The store under test (closed by the suite)
+//   - advance: Moves the store's clock forward, so TTL tests don't sleep
+type storeFactory func(t *testing.T) (store Store, advance func(time.Duration))
+
+// fakeClock is a manually advanced time source for MemoryStore.now
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newFakeClock starts at a fixed time, so failures are reproducible
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+// TestMemoryStore_Conformance runs the Store conformance suite against MemoryStore
+func TestMemoryStore_Conformance(t *testing.T) {
+	testStoreConformance(t, func(t *testing.T) (Store, func(time.Duration)) {
+		clock := newFakeClock()
+		store := NewMemoryStore()
+		store.now = clock.Now
+		return store, clock.Advance
+	})
+}
+
+// TestFileStore_Conformance runs the Store conformance suite against FileStore
+func TestFileStore_Conformance(t *testing.T) {
+	testStoreConformance(t, func(t *testing.T) (Store, func(time.Duration)) {
+		clock := newFakeClock()
+		store, err := newFileStore(filepath.Join(t.TempDir(), "state.json"), clock.Now)
+		if err != nil {
+			t.Fatalf("NewFileStore() error = %v", err)
+		}
+		return store, clock.Advance
+	})
+}
+
+// testStoreConformance checks the behavior every Store implementation must have
+// (see the Store interface). New backends call it from their own test.
+//
+// Parameters:
+//   - t: Parent test
+//   - newStore: Creates an empty store per subtest
+func testStoreConformance(t *testing.T, newStore storeFactory) {
+	ctx := context.Background()
+
+	run := func(name string, test func(t *testing.T, store Store, advance func(time.Duration))) {
+		t.Run(name, func(t *testing.T) {
+			store, advance := newStore(t)
+			defer func() {
+				if err := store.Close(); err != nil {
+					t.Errorf("Close() error = %v", err)
+				}
+			}()
+			test(t, store, advance)
+		})
+	}
+
+	// mustGet fails the test if Get returns an error
+	mustGet := func(t *testing.T, store Store, key string) ([]byte, bool) {
+		t.Helper()
+		value, found, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", key, err)
+		}
+		return value, found
+	}
+
+	// listKeys returns the keys List reports for prefix
+	listKeys := func(t *testing.T, store Store, prefix string) []string {
+		t.Helper()
+		entries, err := store.List(ctx, prefix)
+		if err != nil {
+			t.Fatalf("List(%q) error = %v", prefix, err)
+		}
+		keys := []string{}
+		for _, e := range entries {
+			keys = append(keys, e.Key)
+		}
+		return keys
+	}
+
+	run("missing key", func(t *testing.T, store Store, _ func(time.Duration)) {
+		if value, found := mustGet(t, store, "missing"); found || value != nil {
+			t.Errorf("Get(missing) = %q, %v; want nil, false", value, found)
+		}
+	})
+
+	run("set, overwrite and get", func(t *testing.T, store Store, _ func(time.Duration)) {
+		for _, value := range []string{"first", "second"} {
+			if err := store.Set(ctx, "key", []byte(value), 0); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if got, found := mustGet(t, store, "key"); !found || string(got) != value {
+				t.Errorf("Get() = %q, %v; want %q, true", got, found, value)
+			}
+		}
+	})
+
+	run("empty value is found", func(t *testing.T, store Store, _ func(time.Duration)) {
+		if err := store.Set(ctx, "empty", nil, 0); err != nil {
+			t.Fatalf("Set(nil) error = %v", err)
+		}
+		if got, found := mustGet(t, store, "empty"); !found || len(got) != 0 {
+			t.Errorf("Get(empty) = %q, %v; want empty value, true", got, found)
+		}
+	})
+
+	run("values are copied", func(t *testing.T, store Store, _ func(time.Duration)) {
+		value := []byte("abc")
+		_ = store.Set(ctx, "key", value, 0)
+		value[0] = 'X' // caller reuses its buffer
+
+		got, _ := mustGet(t, store, "key")
+		got[1] = 'Y' // caller modifies the result
+		if again, _ := mustGet(t, store, "key"); string(again) != "abc" {
+			t.Errorf("Get() = %q after modifying the Set and Get slices, want %q", again, "abc")
+		}
+	})
+
+	run("delete", func(t *testing.T, store Store, _ func(time.Duration)) {
+		_ = store.Set(ctx, "key", []byte("value"), 0)
+		if err := store.Delete(ctx, "key"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, found := mustGet(t, store, "key"); found {
+			t.Errorf("Get() after Delete found the key")
+		}
+		if err := store.Delete(ctx, "key"); err != nil {
+			t.Errorf("Delete(missing) error = %v, want nil", err)
+		}
+	})
+
+	run("list by prefix", func(t *testing.T, store Store, _ func(time.Duration)) {
+		for _, key := range []string{"subs/2/b", "prefs/1", "subs/1/b", "subs/1/a", "subs/10/a"} {
+			_ = store.Set(ctx, key, []byte(key), 0)
+		}
+
+		tests := []struct {
+			prefix string
+			want   []string
+		}{
+			{"subs/1/", []string{"subs/1/a", "subs/1/b"}},
+			{"subs/", []string{"subs/1/a", "subs/1/b", "subs/10/a", "subs/2/b"}},
+			{"", []string{"prefs/1", "subs/1/a", "subs/1/b", "subs/10/a", "subs/2/b"}},
+			{"nothing/", []string{}},
+		}
+		for _, tt := range tests {
+			if got := listKeys(t, store, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List(%q) keys = %v, want %v", tt.prefix, got, tt.want)
+			}
+		}
+
+		entries, _ := store.List(ctx, "prefs/")
+		if len(entries) != 1 || string(entries[0].Value) != "prefs/1" {
+			t.Errorf("List(prefs/) = %+v, want the value of prefs/1", entries)
+		}
+	})
+
+	run("ttl", func(t *testing.T, store Store, advance func(time.Duration)) {
+		_ = store.Set(ctx, "dedup/1", []byte("x"), time.Minute)
+		_ = store.Set(ctx, "dedup/2", []byte("x"), 0)
+
+		advance(59 * time.Second)
+		if _, found := mustGet(t, store, "dedup/1"); !found {
+			t.Errorf("Get() before the TTL passed didn't find the key")
+		}
+
+		advance(time.Second)
+		if _, found := mustGet(t, store, "dedup/1"); found {
+			t.Errorf("Get() after the TTL passed found the key")
+		}
+		if got := listKeys(t, store, "dedup/"); !reflect.DeepEqual(got, []string{"dedup/2"}) {
+			t.Errorf("List() after the TTL passed = %v, want [dedup/2]", got)
+		}
+	})
+
+	run("set without ttl clears ttl", func(t *testing.T, store Store, advance func(time.Duration)) {
+		_ = store.Set(ctx, "key", []byte("x"), time.Minute)
+		_ = store.Set(ctx, "key", []byte("y"), 0)
+
+		advance(time.Hour)
+		if got, found := mustGet(t, store, "key"); !found || string(got) != "y" {
+			t.Errorf("Get() = %q, %v; want %q, true", got, found, "y")
+		}
+	})
+
+	run("concurrent use", func(t *testing.T, store Store, _ func(time.Duration)) {
+		// Checked by the race detector (go test -race)
+		var wg sync.WaitGroup
+		for i := range 10 {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := fmt.Sprintf("key/%d", i)
+				_ = store.Set(ctx, key, []byte("value"), 0)
+				_, _, _ = store.Get(ctx, key)
+				_, _ = store.List(ctx, "key/")
+				_ = store.Delete(ctx, key)
+			}(i)
+		}
+		wg.Wait()
+
+		if got := listKeys(t, store, "key/"); len(got) != 0 {
+			t.Errorf("List() after concurrent set+delete = %v, want empty", got)
+		}
+	})
+}
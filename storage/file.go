@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileStore keeps all state in memory and in a JSON file (STORAGE_BACKEND=file)
+//
+// How it works:
+//   - NewFileStore loads the file once at startup
+//   - Reads are served from memory (the file is never read again)
+//   - Every Set/Delete rewrites the whole file before returning
+//
+// Atomic writes:
+//   - The new content goes to a temporary file in the same directory,
+//     which is synced to disk and then renamed over the old file
+//   - rename is atomic on POSIX file systems: a crash leaves either the old
+//     or the new file, never a half-written one
+//
+// Meant for small state on a single instance (local development, a VM).
+// On Cloud Run the file system is in memory and lost with the instance.
+type FileStore struct {
+	// mem holds the data; its mutex also serializes file writes
+	mem  *MemoryStore
+	path string
+}
+
+// fileFormat is the JSON layout of the storage file
+//
+// Example:
+//
+//	{"entries": {"prefs/12345": {"value": "eyJnb29kX21vcm5pbmciOnRydWV9"}}}
+//
+// Values are []byte, which encoding/json writes as base64.
+type fileFormat struct {
+	Entries map[string]fileEntry `json:"entries"`
+}
+
+// fileEntry is one stored value in the file
+type fileEntry struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// Compile-time check that FileStore implements Store
+var _ Store = (*FileStore)(nil)
+
+// NewFileStore opens the store backed by the JSON file at path
+//
+// A missing file is fine (first start): the store starts empty and the
+// file is created by the first write. Its directory is created right away,
+// so a bad STORAGE_PATH fails at startup rather than on the first write.
+//
+// Parameters:
+//   - path: JSON file (STORAGE_PATH)
+//
+// Returns:
+//   - *FileStore: Store with the file's (unexpired) entries loaded
+//   - error: If path is empty or the file can't be read or parsed
+func NewFileStore(path string) (*FileStore, error) {
+	return newFileStore(path, time.Now)
+}
+
+// newFileStore is NewFileStore with a time source (tests use a fake clock)
+func newFileStore(path string, now func() time.Time) (*FileStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file storage needs a path (STORAGE_PATH)")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	store := &FileStore{mem: NewMemoryStore(), path: path}
+	store.mem.now = now
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read storage file: %w", err)
+	}
+
+	var file fileFormat
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse storage file %s: %w", path, err)
+	}
+	loadedAt := now()
+	for key, fe := range file.Entries {
+		e := entry{value: fe.Value, expiresAt: fe.ExpiresAt}
+		if e.value == nil {
+			e.value = []byte{}
+		}
+		if !e.expired(loadedAt) {
+			store.mem.entries[key] = e
+		}
+	}
+	return store, nil
+}
+
+// Get returns a copy of the value stored under key (from memory)
+func (s *FileStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return s.mem.Get(ctx, key)
+}
+
+// Set stores value under key and rewrites the file
+// If the file can't be written, the store keeps its previous value and returns the error.
+func (s *FileStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.update(key, func() { s.mem.set(key, value, ttl) })
+}
+
+// Delete removes key and rewrites the file (no-op if the key doesn't exist)
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	s.mem.mu.RLock()
+	_, ok := s.mem.entries[key]
+	s.mem.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return s.update(key, func() { delete(s.mem.entries, key) })
+}
+
+// List returns copies of all unexpired entries under prefix, sorted by key (from memory)
+func (s *FileStore) List(ctx context.Context, prefix string) ([]Entry, error) {
+	return s.mem.List(ctx, prefix)
+}
+
+// Close is a no-op: every write is already on disk
+func (s *FileStore) Close() error {
+	return nil
+}
+
+// update applies change to key in memory and writes the file,
+// restoring key's previous state if the write fails
+//
+// Parameters:
+//   - key: Key that change modifies
+//   - change: Modification of s.mem.entries (runs with s.mem.mu held)
+//
+// Returns:
+//   - error: If the file can't be written
+func (s *FileStore) update(key string, change func()) error {
+	s.mem.mu.Lock()
+	defer s.mem.mu.Unlock()
+
+	previous, existed := s.mem.entries[key]
+	change()
+
+	if err := s.writeFile(); err != nil {
+		if existed {
+			s.mem.entries[key] = previous
+		} else {
+			delete(s.mem.entries, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// writeFile writes all unexpired entries to the file atomically
+// (temporary file + fsync + rename, see FileStore). The caller holds s.mem.mu.
+func (s *FileStore) writeFile() error {
+	now := s.mem.now()
+	file := fileFormat{Entries: make(map[string]fileEntry, len(s.mem.entries))}
+	for key, e := range s.mem.entries {
+		if !e.expired(now) {
+			file.Entries[key] = fileEntry{Value: e.value, ExpiresAt: e.expiresAt}
+		}
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode storage file: %w", err)
+	}
+
+	// The temporary file must be in the same directory:
+	// rename is only atomic within one file system
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+	// Sync before rename: otherwise a crash could leave the new name
+	// pointing at a file whose content never reached the disk
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write storage file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFileStore_Reopen tests that data survives a restart (a new FileStore on the same file)
+//
+// Cases:
+//   - Values and deletions are persisted
+//   - Keys that expired while the bot was down are not loaded
+//   - No temporary files are left next to the storage file
+func TestFileStore_Reopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data", "state.json") // directory is created by NewFileStore
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	_ = store.Set(ctx, "prefs/1", []byte(`{"good_morning":true}`), 0)
+	_ = store.Set(ctx, "subs/1/a", nil, 0)
+	_ = store.Set(ctx, "subs/1/b", nil, 0)
+	_ = store.Set(ctx, "dedup/1", []byte("x"), time.Hour)
+	_ = store.Delete(ctx, "subs/1/b")
+	_ = store.Close()
+
+	// Restart two hours later
+	clock := &fakeClock{now: time.Now().Add(2 * time.Hour)}
+	reopened, err := newFileStore(path, clock.Now)
+	if err != nil {
+		t.Fatalf("NewFileStore() on existing file error = %v", err)
+	}
+	if got, found, _ := reopened.Get(ctx, "prefs/1"); !found || string(got) != `{"good_morning":true}` {
+		t.Errorf("Get(prefs/1) after reopen = %q, %v; want the saved value", got, found)
+	}
+	entries, _ := reopened.List(ctx, "")
+	if len(entries) != 2 || entries[0].Key != "prefs/1" || entries[1].Key != "subs/1/a" {
+		t.Errorf("List() after reopen = %+v, want prefs/1 and subs/1/a (deleted and expired keys dropped)", entries)
+	}
+
+	files, _ := os.ReadDir(filepath.Dir(path))
+	if len(files) != 1 {
+		t.Errorf("storage directory has %d files, want only state.json", len(files))
+	}
+}
+
+// TestNewFileStore_Errors tests startup failures
+func TestNewFileStore_Errors(t *testing.T) {
+	t.Run("empty path", func(t *testing.T) {
+		if _, err := NewFileStore(""); err == nil {
+			t.Errorf("NewFileStore(\"\") error = nil, want error")
+		}
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewFileStore(path); err == nil {
+			t.Errorf("NewFileStore() with corrupt file error = nil, want error")
+		}
+	})
+}
+
+// TestFileStore_WriteFailure tests that a failed write leaves the store unchanged
+func TestFileStore_WriteFailure(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "data")
+
+	store, err := NewFileStore(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	_ = store.Set(ctx, "key", []byte("old"), 0)
+
+	// Without its directory the temporary file can't be created
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Set(ctx, "key", []byte("new"), 0); err == nil {
+		t.Fatalf("Set() without storage directory error = nil, want error")
+	}
+	if got, _, _ := store.Get(ctx, "key"); string(got) != "old" {
+		t.Errorf("Get() after failed Set = %q, want %q", got, "old")
+	}
+
+	if err := store.Set(ctx, "other", []byte("x"), 0); err == nil {
+		t.Fatalf("Set(other) without storage directory error = nil, want error")
+	}
+	if _, found, _ := store.Get(ctx, "other"); found {
+		t.Errorf("Get(other) after failed Set found the key")
+	}
+}
+
+// TestOpen tests backend selection
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		backend string
+		path    string
+		wantErr bool
+	}{
+		{backend: BackendMemory},
+		{backend: BackendFile, path: filepath.Join(t.TempDir(), "state.json")},
+		{backend: BackendFile, wantErr: true},
+		{backend: "postgres", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			store, err := Open(context.Background(), Options{Backend: tt.backend, Path: tt.path})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open(%q, %q) error = %v, wantErr %v", tt.backend, tt.path, err, tt.wantErr)
+			}
+			if store != nil {
+				_ = store.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FirestoreEmulatorEnv is set to the emulator's host:port (e.g., "localhost:8080")
+// by `gcloud emulators firestore start`; the client library then talks to the
+// emulator without authentication
+const FirestoreEmulatorEnv = "FIRESTORE_EMULATOR_HOST"
+
+// FirestoreStore keeps state in a Firestore collection (STORAGE_BACKEND=firestore)
+//
+// Cloud Run's file system is lost with every instance, so this is the
+// backend for production: state survives deploys and is shared by instances.
+//
+// Layout: one document per key in the collection (FIRESTORE_COLLECTION):
+//
+//	{"key": "prefs/12345", "value": <bytes>, "expiresAt": <timestamp, only with a TTL>}
+//
+// The document ID is the base64 of the key: keys contain "/", which
+// Firestore would read as a subcollection path.
+//
+// The connection is the official client (cloud.google.com/go/firestore):
+// it finds credentials with Application Default Credentials (the service
+// account on Cloud Run, GOOGLE_APPLICATION_CREDENTIALS or
+// `gcloud auth application-default login` elsewhere), refreshes tokens and
+// retries transient errors by itself.
+//
+// TTL: expired documents are hidden on read. Firestore deletes them only
+// with a TTL policy on the expiresAt field (one-time setup):
+//
+//	gcloud firestore fields ttls update expiresAt --collection-group=run-tbot --enable-ttl
+type FirestoreStore struct {
+	client     *firestore.Client
+	collection *firestore.CollectionRef
+
+	// now is the time source for TTLs (replaced in tests)
+	now func() time.Time
+}
+
+// firestoreDocument is the stored document (field names as in the layout above)
+type firestoreDocument struct {
+	Key       string     `firestore:"key"`
+	Value     []byte     `firestore:"value"`
+	ExpiresAt *time.Time `firestore:"expiresAt,omitempty"`
+}
+
+// Compile-time check that FirestoreStore implements Store
+var _ Store = (*FirestoreStore)(nil)
+
+// NewFirestoreStore opens the store backed by a Firestore collection
+//
+// Requirements:
+//   - A Firestore database "(default)" in Native mode in the project
+//   - The service account of the Cloud Run service needs the
+//     "Cloud Datastore User" role (roles/datastore.user) on the project:
+//     gcloud projects add-iam-policy-binding <project> \
+//     --member=serviceAccount:<sa> --role=roles/datastore.user
+//
+// With FIRESTORE_EMULATOR_HOST set, the emulator is used instead (no
+// authentication, project is any name, e.g. "demo-run-tbot").
+//
+// Parameters:
+//   - ctx: Context for finding credentials and the project
+//   - project: Google Cloud project ID (GOOGLE_CLOUD_PROJECT); empty detects
+//     it from the Application Default Credentials (the metadata server on Cloud Run)
+//   - collection: Collection holding the bot's documents (FIRESTORE_COLLECTION)
+//
+// Returns:
+//   - *FirestoreStore: Ready-to-use store; the caller must Close it
+//   - error: If the collection is invalid or the client can't be created
+//     (no credentials, project not detectable)
+func NewFirestoreStore(ctx context.Context, project, collection string) (*FirestoreStore, error) {
+	if collection == "" || strings.Contains(collection, "/") {
+		return nil, fmt.Errorf("invalid Firestore collection: %q (must be non-empty, without /)", collection)
+	}
+	if project == "" {
+		project = firestore.DetectProjectID
+	}
+
+	client, err := firestore.NewClient(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Firestore client (set GOOGLE_CLOUD_PROJECT and credentials): %w", err)
+	}
+
+	return &FirestoreStore{
+		client:     client,
+		collection: client.Collection(collection),
+		now:        time.Now,
+	}, nil
+}
+
+// Get returns the value stored under key
+// Expired documents (not yet deleted by the TTL policy) are not found.
+func (s *FirestoreStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	snapshot, err := s.document(key).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("firestore get: %w", err)
+	}
+
+	var doc firestoreDocument
+	if err := snapshot.DataTo(&doc); err != nil {
+		return nil, false, fmt.Errorf("firestore get: %w", err)
+	}
+	if s.expired(doc) {
+		return nil, false, nil
+	}
+	return valueOf(doc), true, nil
+}
+
+// Set stores value under key, replacing the whole document
+// (a Set without ttl removes the previous expiresAt)
+func (s *FirestoreStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	doc := firestoreDocument{Key: key, Value: value}
+	if doc.Value == nil {
+		doc.Value = []byte{}
+	}
+	if ttl > 0 {
+		expiresAt := s.now().Add(ttl).UTC()
+		doc.ExpiresAt = &expiresAt
+	}
+
+	if _, err := s.document(key).Set(ctx, doc); err != nil {
+		return fmt.Errorf("firestore set: %w", err)
+	}
+	return nil
+}
+
+// Delete removes key (deleting a missing document is not an error in Firestore)
+func (s *FirestoreStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.document(key).Delete(ctx); err != nil {
+		return fmt.Errorf("firestore delete: %w", err)
+	}
+	return nil
+}
+
+// List returns the unexpired entries whose key starts with prefix, sorted by key
+//
+// Firestore has no prefix operator, so the prefix becomes a range on the key
+// field: prefix <= key < prefix+U+10FFFF (the largest code point, so every
+// key starting with prefix is inside). Firestore orders strings by their
+// UTF-8 bytes, like Go, so the query's order is the order Store promises.
+// A range on a single field needs no composite index.
+func (s *FirestoreStore) List(ctx context.Context, prefix string) ([]Entry, error) {
+	query := s.collection.OrderBy("key", firestore.Asc)
+	if prefix != "" {
+		query = query.Where("key", ">=", prefix).Where("key", "<", prefix+"\U0010FFFF")
+	}
+
+	entries := []Entry{}
+	docs := query.Documents(ctx)
+	defer docs.Stop()
+	for {
+		snapshot, err := docs.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("firestore list: %w", err)
+		}
+
+		var doc firestoreDocument
+		if err := snapshot.DataTo(&doc); err != nil {
+			continue // not written by this store
+		}
+		if s.expired(doc) {
+			continue
+		}
+		entries = append(entries, Entry{Key: doc.Key, Value: valueOf(doc)})
+	}
+	return entries, nil
+}
+
+// Close closes the client's connections
+func (s *FirestoreStore) Close() error {
+	return s.client.Close()
+}
+
+// document returns the reference of key's document
+// The "k" prefix keeps IDs valid for the empty key (IDs can't be empty).
+func (s *FirestoreStore) document(key string) *firestore.DocumentRef {
+	return s.collection.Doc("k" + base64.RawURLEncoding.EncodeToString([]byte(key)))
+}
+
+// expired reports whether a document's TTL has passed
+func (s *FirestoreStore) expired(doc firestoreDocument) bool {
+	return doc.ExpiresAt != nil && !s.now().Before(*doc.ExpiresAt)
+}
+
+// valueOf returns a document's value, never nil (an empty value was stored as [])
+func valueOf(doc firestoreDocument) []byte {
+	if doc.Value == nil {
+		return []byte{}
+	}
+	return doc.Value
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestFirestoreStore_Conformance runs the Store conformance suite against the Firestore emulator
+//
+// Skipped unless FIRESTORE_EMULATOR_HOST is set:
+//
+//	gcloud emulators firestore start --host-port=localhost:8080
+//	FIRESTORE_EMULATOR_HOST=localhost:8080 go test ./storage
+//
+// Each subtest gets its own collection, so runs don't see each other's keys.
+func TestFirestoreStore_Conformance(t *testing.T) {
+	if os.Getenv(FirestoreEmulatorEnv) == "" {
+		t.Skipf("%s not set, skipping Firestore emulator tests", FirestoreEmulatorEnv)
+	}
+
+	var collections atomic.Int64
+	testStoreConformance(t, func(t *testing.T) (Store, func(time.Duration)) {
+		collection := fmt.Sprintf("conformance-%d-%d", time.Now().UnixNano(), collections.Add(1))
+		store, err := NewFirestoreStore(context.Background(), "demo-run-tbot", collection)
+		if err != nil {
+			t.Fatalf("NewFirestoreStore() error = %v", err)
+		}
+		clock := newFakeClock()
+		store.now = clock.Now
+		return store, clock.Advance
+	})
+}
+
+// TestFirestoreStore_Layout tests the documents written to the emulator
+//
+// Cases:
+//   - The document ID is "k" + base64 of the key, fields key/value/expiresAt
+//   - Set without a TTL removes the previous expiresAt
+//   - Documents not written by the store are skipped by List
+func TestFirestoreStore_Layout(t *testing.T) {
+	if os.Getenv(FirestoreEmulatorEnv) == "" {
+		t.Skipf("%s not set, skipping Firestore emulator tests", FirestoreEmulatorEnv)
+	}
+
+	// The client retries while the emulator is unreachable: fail instead of hanging
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	store, err := NewFirestoreStore(ctx, "demo-run-tbot", fmt.Sprintf("layout-%d", time.Now().UnixNano()))
+	if err != nil {
+		t.Fatalf("NewFirestoreStore() error = %v", err)
+	}
+	defer store.Close()
+
+	_ = store.Set(ctx, "prefs/1", []byte("fr"), time.Hour)
+	snapshot, err := store.collection.Doc("kcHJlZnMvMQ").Get(ctx)
+	if err != nil {
+		t.Fatalf("document kcHJlZnMvMQ: %v", err)
+	}
+	data := snapshot.Data()
+	if data["key"] != "prefs/1" || string(data["value"].([]byte)) != "fr" || data["expiresAt"] == nil {
+		t.Errorf("document = %v, want key, value and expiresAt", data)
+	}
+
+	_ = store.Set(ctx, "prefs/1", []byte("de"), 0)
+	snapshot, _ = store.collection.Doc("kcHJlZnMvMQ").Get(ctx)
+	if _, ok := snapshot.Data()["expiresAt"]; ok {
+		t.Errorf("expiresAt kept after Set without TTL: %v", snapshot.Data())
+	}
+
+	// A document of someone else in the same collection
+	if _, err := store.collection.Doc("other").Set(ctx, map[string]any{"key": "prefs/2", "value": 42}); err != nil {
+		t.Fatalf("writing a foreign document: %v", err)
+	}
+	entries, err := store.List(ctx, "prefs/")
+	if err != nil || len(entries) != 1 || entries[0].Key != "prefs/1" {
+		t.Errorf("List(prefs/) = %+v, %v; want only prefs/1", entries, err)
+	}
+}
+
+// TestNewFirestoreStore_InvalidCollection tests that bad collection names fail before connecting
+func TestNewFirestoreStore_InvalidCollection(t *testing.T) {
+	for _, collection := range []string{"", "a/b"} {
+		if store, err := NewFirestoreStore(context.Background(), "demo-run-tbot", collection); err == nil {
+			_ = store.Close()
+			t.Errorf("NewFirestoreStore(collection %q) error = nil, want error", collection)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps all state in a map (STORAGE_BACKEND=memory, the default)
+//
+// Limitations:
+//   - State is lost on restart
+//   - Not shared between Cloud Run instances
+//
+// Safe for concurrent use: one RWMutex guards the map.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+
+	// writes counts Set calls; every pruneEvery-th one removes expired keys
+	writes int
+
+	// now returns the current time (replaced in tests to expire keys without sleeping)
+	now func() time.Time
+}
+
+// pruneEvery is how many Set calls pass between removals of expired keys
+// (Get and List skip expired keys anyway; pruning only frees memory)
+const pruneEvery = 256
+
+// entry is a stored value with its optional expiry
+type entry struct {
+	value []byte
+
+	// expiresAt is the zero time for keys without TTL
+	expiresAt time.Time
+}
+
+// expired reports whether the entry's TTL has passed at now
+func (e entry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// Compile-time check that MemoryStore implements Store
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store
+//
+// Returns:
+//   - *MemoryStore: Ready-to-use store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]entry),
+		now:     time.Now,
+	}
+}
+
+// Get returns a copy of the value stored under key
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.expired(s.now()) {
+		return nil, false, nil
+	}
+	return slices.Clone(e.value), true, nil
+}
+
+// Set stores a copy of value under key (the caller may reuse its slice)
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(key, value, ttl)
+	return nil
+}
+
+// set is Set without locking (the caller holds s.mu)
+func (s *MemoryStore) set(key string, value []byte, ttl time.Duration) {
+	e := entry{value: slices.Clone(value)}
+	if e.value == nil {
+		e.value = []byte{} // Set(key, nil) stores an empty value, Get still finds it
+	}
+	if ttl > 0 {
+		e.expiresAt = s.now().Add(ttl)
+	}
+	s.entries[key] = e
+
+	s.writes++
+	if s.writes%pruneEvery == 0 {
+		s.removeExpired()
+	}
+}
+
+// Delete removes key (no-op if it doesn't exist)
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// List returns copies of all unexpired entries under prefix, sorted by key
+func (s *MemoryStore) List(_ context.Context, prefix string) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	var result []Entry
+	for key, e := range s.entries {
+		if strings.HasPrefix(key, prefix) && !e.expired(now) {
+			result = append(result, Entry{Key: key, Value: slices.Clone(e.value)})
+		}
+	}
+	slices.SortFunc(result, func(a, b Entry) int { return strings.Compare(a.Key, b.Key) })
+	return result, nil
+}
+
+// Close is a no-op: there is nothing to release
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// removeExpired deletes expired entries (the caller holds s.mu for writing)
+func (s *MemoryStore) removeExpired() {
+	now := s.now()
+	for key, e := range s.entries {
+		if e.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+}
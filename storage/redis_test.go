@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newMiniredis starts an in-process Redis server (stopped by t.Cleanup)
+//
+// miniredis speaks the real protocol, so the go-redis client runs
+// unchanged; its TTLs only move with FastForward, so TTL tests don't sleep.
+//
+// Parameters:
+//   - password: Required password ("" accepts every connection)
+//
+// Returns:
+//   - *miniredis.Miniredis: The server
+//   - string: Its REDIS_URL (database 2 when a password is set, to exercise SELECT)
+func newMiniredis(t *testing.T, password string) (*miniredis.Miniredis, string) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	if password == "" {
+		return server, "redis://" + server.Addr()
+	}
+	server.RequireAuth(password)
+	return server, "redis://:" + password + "@" + server.Addr() + "/2"
+}
+
+// TestRedisStore_Conformance runs the Store conformance suite against miniredis
+func TestRedisStore_Conformance(t *testing.T) {
+	testStoreConformance(t, func(t *testing.T) (Store, func(time.Duration)) {
+		server, url := newMiniredis(t, "secret")
+		store, err := NewRedisStore(context.Background(), url)
+		if err != nil {
+			t.Fatalf("NewRedisStore() error = %v", err)
+		}
+		return store, server.FastForward
+	})
+}
+
+// TestRedisStore_Commands tests the Redis side of TTLs and prefix listing
+//
+// Cases:
+//   - Set with a TTL sets the key's expiry; Set without one clears it
+//   - Glob characters in a prefix are matched literally
+//   - Keys written by another client are listed
+func TestRedisStore_Commands(t *testing.T) {
+	ctx := context.Background()
+	server, url := newMiniredis(t, "")
+	store, err := NewRedisStore(ctx, url)
+	if err != nil {
+		t.Fatalf("NewRedisStore() error = %v", err)
+	}
+	defer store.Close()
+
+	_ = store.Set(ctx, "dedup/1", []byte("x"), 1500*time.Millisecond)
+	if got := server.TTL("dedup/1"); got != 1500*time.Millisecond {
+		t.Errorf("TTL after Set(1.5s) = %v, want 1.5s", got)
+	}
+	_ = store.Set(ctx, "dedup/1", []byte("y"), 0)
+	if got := server.TTL("dedup/1"); got != 0 {
+		t.Errorf("TTL after Set(0) = %v, want none", got)
+	}
+
+	_ = store.Set(ctx, "a*/1", []byte("x"), 0)
+	_ = store.Set(ctx, "ab/1", []byte("x"), 0)
+	entries, err := store.List(ctx, "a*/")
+	if err != nil || len(entries) != 1 || entries[0].Key != "a*/1" {
+		t.Errorf("List(a*/) = %+v, %v; want only a*/1", entries, err)
+	}
+
+	// Another instance's write, straight into Redis
+	_ = server.Set("ab/2", "from another instance")
+	entries, err = store.List(ctx, "ab/")
+	if err != nil || len(entries) != 2 || string(entries[1].Value) != "from another instance" {
+		t.Errorf("List(ab/) = %+v, %v; want ab/1 and ab/2", entries, err)
+	}
+}
+
+// TestNewRedisStore_Errors tests fast failures at startup
+//
+// Cases:
+//   - Invalid URLs (scheme, database number)
+//   - Nothing listening on the address
+//   - Wrong password
+func TestNewRedisStore_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	// A port that was free a moment ago: connecting is refused
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	downAddr := listener.Addr().String()
+	_ = listener.Close()
+
+	server, _ := newMiniredis(t, "secret")
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "http scheme", url: "http://localhost:6379", wantErr: "scheme"},
+		{name: "bad database", url: "redis://localhost:6379/x", wantErr: "database"},
+		{name: "redis down", url: "redis://" + downAddr, wantErr: "not reachable"},
+		{name: "wrong password", url: "redis://:wrong@" + server.Addr(), wantErr: "WRONGPASS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := NewRedisStore(ctx, tt.url)
+			if err == nil {
+				_ = store.Close()
+				t.Fatalf("NewRedisStore(%q) error = nil, want error", tt.url)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewRedisStore(%q) error = %v, want it to mention %q", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParseRedisURL tests REDIS_URL parsing
+func TestParseRedisURL(t *testing.T) {
+	tests := []struct {
+		url                          string
+		wantAddr, wantUser, wantPass string
+		wantDB                       int
+		wantTLS                      bool
+	}{
+		{url: "redis://localhost", wantAddr: "localhost:6379"},
+		{url: "redis://:pw@redis:6380/3", wantAddr: "redis:6380", wantPass: "pw", wantDB: 3},
+		{url: "rediss://bot:pw@cache.example.com:6380", wantAddr: "cache.example.com:6380", wantUser: "bot", wantPass: "pw", wantTLS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			opts, err := parseRedisURL(tt.url)
+			if err != nil {
+				t.Fatalf("parseRedisURL() error = %v", err)
+			}
+			if opts.Addr != tt.wantAddr || opts.Username != tt.wantUser || opts.Password != tt.wantPass ||
+				opts.DB != tt.wantDB || (opts.TLSConfig != nil) != tt.wantTLS || opts.DialTimeout != redisDialTimeout {
+				t.Errorf("parseRedisURL() = addr %q user %q pass %q db %d tls %v dial timeout %v",
+					opts.Addr, opts.Username, opts.Password, opts.DB, opts.TLSConfig != nil, opts.DialTimeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package storage defines where the bot keeps its state between updates
+//
+// Store is a small key-value interface: every feature that needs persistence
+// (chat preferences, subscriptions, stats, dedup, ...) stores its values under
+// its own key prefix instead of inventing its own storage.
+//
+// Backends (selected by STORAGE_BACKEND, see Open):
+//   - MemoryStore: in memory, lost on restart (default)
+//   - FileStore: a JSON file (STORAGE_PATH), rewritten atomically on every change
+//   - FirestoreStore: a Firestore collection (FIRESTORE_COLLECTION), for Cloud Run
+//   - RedisStore: a Redis server (REDIS_URL), shared by several instances
+//
+// Implementing a new backend:
+//   - Implement every Store method; all methods must be safe for concurrent use
+//     (each webhook request runs in its own goroutine)
+//   - Missing keys are not an error: Get returns found=false
+//   - Run the conformance suite (testStoreConformance in conformance_test.go)
+//   - Add its schema changes to the --migrate flag in main.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backend names accepted by STORAGE_BACKEND
+const (
+	BackendMemory    = "memory"
+	BackendFile      = "file"
+	BackendFirestore = "firestore"
+	BackendRedis     = "redis"
+)
+
+// Entry is one key-value pair returned by Store.List
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Store is the interface for all persistent bot state
+//
+// Keys are plain strings; by convention the first path segment names the
+// feature that owns the key ("prefs/12345", "subs/12345/24sk20.ram-32g"),
+// so List can return one feature's keys with a prefix.
+type Store interface {
+	// Get returns the value stored under key
+	// found is false (and err nil) if the key doesn't exist or has expired
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+
+	// Set stores value under key, replacing any previous value
+	// ttl > 0 makes the key expire after ttl; ttl == 0 keeps it forever
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key
+	// Deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+
+	// List returns all (unexpired) entries whose key starts with prefix,
+	// sorted by key; "" lists everything
+	List(ctx context.Context, prefix string) ([]Entry, error)
+
+	// Close releases resources (database connections, files)
+	Close() error
+}
+
+// Options selects and configures a backend (see Open)
+type Options struct {
+	// Backend is BackendMemory, BackendFile, BackendFirestore or BackendRedis (STORAGE_BACKEND)
+	Backend string
+
+	// Path is the JSON file of BackendFile (STORAGE_PATH)
+	Path string
+
+	// Project is the Google Cloud project of BackendFirestore (GOOGLE_CLOUD_PROJECT);
+	// empty detects it from Application Default Credentials
+	Project string
+
+	// Collection is the Firestore collection of BackendFirestore (FIRESTORE_COLLECTION)
+	Collection string
+
+	// RedisURL is the server of BackendRedis (REDIS_URL)
+	RedisURL string
+}
+
+// Open creates the Store for a backend (STORAGE_BACKEND)
+//
+// Parameters:
+//   - ctx: Context for connecting to the backend (Firestore project lookup, Redis PING)
+//   - opts: Backend name and its settings; settings of other backends are ignored
+//
+// Returns:
+//   - Store: Ready-to-use store; the caller must Close it
+//   - error: If the backend is unknown or can't be opened
+func Open(ctx context.Context, opts Options) (Store, error) {
+	switch opts.Backend {
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	case BackendFile:
+		return NewFileStore(opts.Path)
+	case BackendFirestore:
+		return NewFirestoreStore(ctx, opts.Project, opts.Collection)
+	case BackendRedis:
+		return NewRedisStore(ctx, opts.RedisURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %q", opts.Backend)
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// usagePrefix + day + "/" + feature -> decimal count
+// Example: "usage/2026-03-01/dice" -> "42"
+const usagePrefix = "usage/"
+
+// UsageRetention is how long daily usage counters are kept
+// Each write renews the TTL, so a day disappears UsageRetention after its last use.
+const UsageRetention = 90 * 24 * time.Hour
+
+// UsageStore keeps per-day, per-feature usage counters in a Store
+//
+// Counters are read-modify-write: two processes adding to the same counter
+// at the same moment may lose one of the additions. Good enough for
+// "which features get used", not for billing.
+//
+// Safe for concurrent use (as safe as the underlying Store).
+type UsageStore struct {
+	store Store
+}
+
+// NewUsageStore creates a UsageStore on top of store
+//
+// Parameters:
+//   - store: Backend (see Open)
+//
+// Returns:
+//   - *UsageStore: Ready-to-use usage store
+func NewUsageStore(store Store) *UsageStore {
+	return &UsageStore{store: store}
+}
+
+// AddUsage adds n to a feature's counter for a day
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - day: Any time of the day (days are UTC)
+//   - feature: Feature name (e.g., "dice"), must not contain "/"
+//   - n: Amount to add
+//
+// Returns:
+//   - error: If a storage call fails or the stored counter is corrupt
+func (s *UsageStore) AddUsage(ctx context.Context, day time.Time, feature string, n int64) error {
+	key := usageDayPrefix(day) + feature
+
+	data, found, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	var count int64
+	if found {
+		count, err = strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to decode usage counter %s: %w", key, err)
+		}
+	}
+
+	return s.store.Set(ctx, key, []byte(strconv.FormatInt(count+n, 10)), UsageRetention)
+}
+
+// LoadDayUsage returns all feature counters of a day
+//
+// Parameters:
+//   - ctx: Context for the storage call
+//   - day: Any time of the day (days are UTC)
+//
+// Returns:
+//   - map[string]int64: Count per feature (empty if nothing was used that day)
+//   - error: If the storage call fails or a stored counter is corrupt
+func (s *UsageStore) LoadDayUsage(ctx context.Context, day time.Time) (map[string]int64, error) {
+	prefix := usageDayPrefix(day)
+
+	entries, err := s.store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(entries))
+	for _, e := range entries {
+		count, err := strconv.ParseInt(string(e.Value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode usage counter %s: %w", e.Key, err)
+		}
+		counts[strings.TrimPrefix(e.Key, prefix)] = count
+	}
+	return counts, nil
+}
+
+// usageDayPrefix is the key prefix of a day's counters ("usage/2026-03-01/")
+func usageDayPrefix(day time.Time) string {
+	return usagePrefix + day.UTC().Format(time.DateOnly) + "/"
+}
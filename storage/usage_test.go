@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestUsageStore tests adding to and loading daily counters
+//
+// Cases:
+//   - Additions to the same day and feature are summed
+//   - Days are UTC: 23:30 in UTC-5 is the next day
+//   - A day without usage loads as an empty map
+func TestUsageStore(t *testing.T) {
+	store := NewUsageStore(NewMemoryStore())
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	_ = store.AddUsage(ctx, day, "dice", 2)
+	_ = store.AddUsage(ctx, day.Add(time.Hour), "dice", 3)
+	_ = store.AddUsage(ctx, day, "ovh", 1)
+	_ = store.AddUsage(ctx, time.Date(2026, 3, 1, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)), "help", 1)
+
+	got, err := store.LoadDayUsage(ctx, day)
+	if err != nil {
+		t.Fatalf("LoadDayUsage() error = %v", err)
+	}
+	if want := map[string]int64{"dice": 5, "ovh": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadDayUsage(03-01) = %v, want %v", got, want)
+	}
+
+	next, _ := store.LoadDayUsage(ctx, day.AddDate(0, 0, 1))
+	if want := map[string]int64{"help": 1}; !reflect.DeepEqual(next, want) {
+		t.Errorf("LoadDayUsage(03-02) = %v, want %v", next, want)
+	}
+
+	empty, err := store.LoadDayUsage(ctx, day.AddDate(0, 0, -1))
+	if err != nil || len(empty) != 0 {
+		t.Errorf("LoadDayUsage(unused day) = %v, %v; want empty, nil", empty, err)
+	}
+}
+
+// TestUsageStore_CorruptCounter tests that a corrupt counter is an error, not a reset
+func TestUsageStore_CorruptCounter(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewUsageStore(backend)
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	_ = backend.Set(ctx, "usage/2026-03-01/dice", []byte("many"), 0)
+	if err := store.AddUsage(ctx, day, "dice", 1); err == nil {
+		t.Errorf("AddUsage() on corrupt counter error = nil, want error")
+	}
+	if _, err := store.LoadDayUsage(ctx, day); err == nil {
+		t.Errorf("LoadDayUsage() with corrupt counter error = nil, want error")
+	}
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Key prefixes owned by UserStore
+const (
+	// usersPrefix + user ID -> JSON UserRecord
+	usersPrefix = "users/"
+
+	// userPreferencesPrefix + user ID -> JSON UserPreferences
+	// (not under "users/", so ListUsers only sees records)
+	userPreferencesPrefix = "userprefs/"
+)
+
+// UserRecord describes a user who has talked to the bot
+//
+// Privacy: only who and when - never what. No message text, commands or
+// button presses are stored.
+type UserRecord struct {
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username,omitempty"` // Without "@"; empty if the user has none
+	FirstName string    `json:"first_name,omitempty"`
+	ChatID    int64     `json:"chat_id"` // Chat of the latest message (private chat or group)
+	LastSeen  time.Time `json:"last_seen"`
+	StartedAt time.Time `json:"started_at"` // First message the bot saw from this user
+}
+
+// UserPreferences are per-user settings chosen by the user
+//
+// Unlike Preferences (per chat), they follow the user into every chat.
+// The zero value means "use the defaults".
+type UserPreferences struct {
+	// Language is the i18n language code chosen with /language (e.g., "fr")
+	// Empty means the language of the user's Telegram app
+	Language string `json:"language,omitempty"`
+}
+
+// UserStore keeps one UserRecord per user in a Store (the "last seen" registry)
+//
+// Safe for concurrent use (as safe as the underlying Store).
+type UserStore struct {
+	store Store
+}
+
+// NewUserStore creates a UserStore on top of store
+//
+// Parameters:
+//   - store: Backend (see Open)
+//
+// Returns:
+//   - *UserStore: Ready-to-use user store
+func NewUserStore(store Store) *UserStore {
+	return &UserStore{store: store}
+}
+
+// TouchUser inserts or updates a user's record
+//
+// StartedAt of an existing record is kept, so it stays the first time the
+// user was seen; every other field is replaced by rec. For a new user,
+// StartedAt defaults to rec.LastSeen.
+//
+// Read-modify-write without locking: two concurrent touches of the same user
+// may both keep the older StartedAt, which is the value we want anyway.
+//
+// Parameters:
+//   - ctx: Context for the storage calls
+//   - rec: Current data of the user (StartedAt may be left zero)
+//
+// Returns:
+//   - error: If a storage call fails
+func (s *UserStore) TouchUser(ctx context.Context, rec UserRecord) error {
+	key := userKey(rec.UserID)
+
+	data, found, err := s.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if found {
+		var existing UserRecord
+		// A corrupt record is overwritten rather than blocking the user forever
+		if json.Unmarshal(data, &existing) == nil && !existing.StartedAt.IsZero() {
+			rec.StartedAt = existing.StartedAt
+		}
+	}
+	if rec.StartedAt.IsZero() {
+		rec.StartedAt = rec.LastSeen
+	}
+
+	data, err = json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode user record: %w", err)
+	}
+	return s.store.Set(ctx, key, data, 0)
+}
+
+// ListUsers returns all known users (sorted by user ID key, not by activity)
+func (s *UserStore) ListUsers(ctx context.Context) ([]UserRecord, error) {
+	entries, err := s.store.List(ctx, usersPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]UserRecord, 0, len(entries))
+	for _, e := range entries {
+		var rec UserRecord
+		if err := json.Unmarshal(e.Value, &rec); err != nil {
+			return nil, fmt.Errorf("failed to decode user record %s: %w", e.Key, err)
+		}
+		users = append(users, rec)
+	}
+	return users, nil
+}
+
+// SaveUserPreferences stores prefs for a user, replacing previous ones
+func (s *UserStore) SaveUserPreferences(ctx context.Context, userID int64, prefs UserPreferences) error {
+	data, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to encode user preferences: %w", err)
+	}
+	return s.store.Set(ctx, userPreferencesPrefix+strconv.FormatInt(userID, 10), data, 0)
+}
+
+// LoadUserPreferences returns the user's preferences
+// Returns zero UserPreferences (defaults) if the user never saved any
+func (s *UserStore) LoadUserPreferences(ctx context.Context, userID int64) (UserPreferences, error) {
+	var prefs UserPreferences
+
+	data, found, err := s.store.Get(ctx, userPreferencesPrefix+strconv.FormatInt(userID, 10))
+	if err != nil || !found {
+		return prefs, err
+	}
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return UserPreferences{}, fmt.Errorf("failed to decode preferences of user %d: %w", userID, err)
+	}
+	return prefs, nil
+}
+
+// userKey is the Store key of a user's record ("users/12345")
+func userKey(userID int64) string {
+	return usersPrefix + strconv.FormatInt(userID, 10)
+}
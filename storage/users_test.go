@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestUserStore_TouchUser tests the upsert of user records
+//
+// Cases:
+//   - First touch: StartedAt = LastSeen
+//   - Later touch: LastSeen, names and chat updated, StartedAt kept
+//   - ListUsers returns every user once
+func TestUserStore_TouchUser(t *testing.T) {
+	store := NewUserStore(NewMemoryStore())
+	ctx := context.Background()
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	later := first.Add(48 * time.Hour)
+
+	if err := store.TouchUser(ctx, UserRecord{UserID: 1, Username: "alice", ChatID: 1, LastSeen: first}); err != nil {
+		t.Fatalf("TouchUser() error = %v", err)
+	}
+	if err := store.TouchUser(ctx, UserRecord{UserID: 1, Username: "alice_new", ChatID: -100, LastSeen: later}); err != nil {
+		t.Fatalf("TouchUser() error = %v", err)
+	}
+	_ = store.TouchUser(ctx, UserRecord{UserID: 2, FirstName: "Bob", ChatID: 2, LastSeen: later})
+
+	users, err := store.ListUsers(ctx)
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("ListUsers() = %d users, want 2", len(users))
+	}
+
+	want := UserRecord{UserID: 1, Username: "alice_new", ChatID: -100, LastSeen: later, StartedAt: first}
+	if got := users[0]; got != want {
+		t.Errorf("user 1 = %+v, want %+v", got, want)
+	}
+	if got := users[1]; !got.StartedAt.Equal(later) {
+		t.Errorf("new user StartedAt = %v, want %v (its LastSeen)", got.StartedAt, later)
+	}
+}
+
+// TestUserStore_CorruptRecord tests undecodable records
+//
+// Cases:
+//   - TouchUser overwrites them (the user isn't stuck)
+//   - ListUsers reports them as an error
+func TestUserStore_CorruptRecord(t *testing.T) {
+	backend := NewMemoryStore()
+	store := NewUserStore(backend)
+	ctx := context.Background()
+
+	_ = backend.Set(ctx, "users/7", []byte("not json"), 0)
+	if _, err := store.ListUsers(ctx); err == nil {
+		t.Errorf("ListUsers() with corrupt data error = nil, want error")
+	}
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := store.TouchUser(ctx, UserRecord{UserID: 7, LastSeen: now}); err != nil {
+		t.Fatalf("TouchUser() over corrupt data error = %v", err)
+	}
+	users, err := store.ListUsers(ctx)
+	if err != nil || len(users) != 1 || !users[0].StartedAt.Equal(now) {
+		t.Errorf("ListUsers() = %+v, %v; want user 7 started at %v", users, err, now)
+	}
+}
+
+// TestUserStore_Preferences tests saving and loading user preferences
+//
+// Cases:
+//   - Never saved: zero value, no error
+//   - Saved: loaded back, per user
+//   - Preferences don't show up as users in ListUsers
+func TestUserStore_Preferences(t *testing.T) {
+	store := NewUserStore(NewMemoryStore())
+	ctx := context.Background()
+
+	prefs, err := store.LoadUserPreferences(ctx, 1)
+	if err != nil || prefs != (UserPreferences{}) {
+		t.Fatalf("LoadUserPreferences() = %+v, %v, want defaults", prefs, err)
+	}
+
+	if err := store.SaveUserPreferences(ctx, 1, UserPreferences{Language: "fr"}); err != nil {
+		t.Fatalf("SaveUserPreferences() error = %v", err)
+	}
+	if prefs, _ := store.LoadUserPreferences(ctx, 1); prefs.Language != "fr" {
+		t.Errorf("user 1 Language = %q, want fr", prefs.Language)
+	}
+	if prefs, _ := store.LoadUserPreferences(ctx, 2); prefs.Language != "" {
+		t.Errorf("user 2 Language = %q, want none", prefs.Language)
+	}
+
+	if users, err := store.ListUsers(ctx); err != nil || len(users) != 0 {
+		t.Errorf("ListUsers() = %+v, %v, want no users", users, err)
+	}
+}
@@ -0,0 +1,60 @@
+// Package tgfmt provides helpers for building Telegram MarkdownV2 text.
+//
+// Why a separate package?
+//   - Both handlers and ovh need escaping, and ovh must not import handlers
+//   - One implementation means one set of escaping rules for the whole bot
+//
+// Every helper escapes its argument, so callers pass plain text:
+//
+//	text := tgfmt.Bold("Price:") + " " + tgfmt.EscapeMarkdownV2("12.99 EUR")
+//	msg.ParseMode = tgfmt.ParseMode
+package tgfmt
+
+import "strings"
+
+// ParseMode is the Telegram parse mode matching the text produced by this package
+const ParseMode = "MarkdownV2"
+
+// markdownV2Replacer escapes every character reserved by MarkdownV2
+// Backslash is escaped too, so user text can't start its own escape sequence
+//
+// strings.NewReplacer does a single pass over the input,
+// so already inserted backslashes are never escaped twice
+var markdownV2Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
+	"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
+
+// codeReplacer escapes the characters that are special inside `code` entities
+// Inside code only ` and \ have meaning, everything else is shown as-is
+var codeReplacer = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+
+// EscapeMarkdownV2 escapes special characters for Telegram MarkdownV2
+// MarkdownV2 requires escaping: _ * [ ] ( ) ~ ` > # + - = | { } . ! and \
+//
+// Parameters:
+//   - text: Text to escape
+//
+// Returns:
+//   - string: Escaped text safe for MarkdownV2
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Replacer.Replace(text)
+}
+
+// Bold returns text wrapped in *bold* markers with its contents escaped
+func Bold(text string) string {
+	return "*" + EscapeMarkdownV2(text) + "*"
+}
+
+// Italic returns text wrapped in _italic_ markers with its contents escaped
+func Italic(text string) string {
+	return "_" + EscapeMarkdownV2(text) + "_"
+}
+
+// Code returns text wrapped in `monospace` markers
+// Only ` and \ are escaped, since other characters are literal inside code
+func Code(text string) string {
+	return "`" + codeReplacer.Replace(text) + "`"
+}
@@ -0,0 +1,121 @@
+package tgfmt
+
+import "testing"
+
+// TestEscapeMarkdownV2 tests the MarkdownV2 escaping function
+// MarkdownV2 requires escaping many special characters
+//
+// Testing strategy:
+//   - Test each special character individually
+//   - Test combinations of special characters
+//   - Test normal text (should remain unchanged)
+//   - Test empty string
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "normal text without special chars",
+			input:    "Hello World",
+			expected: "Hello World",
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "dash character",
+			input:    "test-name",
+			expected: "test\\-name",
+		},
+		{
+			name:     "dot character",
+			input:    "test.name",
+			expected: "test\\.name",
+		},
+		{
+			name:     "parentheses",
+			input:    "server (2023)",
+			expected: "server \\(2023\\)",
+		},
+		{
+			name:     "underscore",
+			input:    "test_name",
+			expected: "test\\_name",
+		},
+		{
+			name:     "asterisk",
+			input:    "test*name",
+			expected: "test\\*name",
+		},
+		{
+			name:     "square brackets",
+			input:    "test[name]",
+			expected: "test\\[name\\]",
+		},
+		{
+			name:     "multiple special characters",
+			input:    "server-name.test (v1.0)",
+			expected: "server\\-name\\.test \\(v1\\.0\\)",
+		},
+		{
+			name:     "all special characters",
+			input:    "_*[]()~`>#+-=|{}.!",
+			expected: "\\_\\*\\[\\]\\(\\)\\~\\`\\>\\#\\+\\-\\=\\|\\{\\}\\.\\!",
+		},
+		{
+			name:     "backslash",
+			input:    "C:\\path",
+			expected: "C:\\\\path",
+		},
+		{
+			name:     "FQN with dots and dashes",
+			input:    "1801sk12.ram.1-v2",
+			expected: "1801sk12\\.ram\\.1\\-v2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := EscapeMarkdownV2(tt.input)
+
+			if result != tt.expected {
+				t.Errorf("EscapeMarkdownV2(%q) = %q, want %q",
+					tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWrappers tests Bold, Italic and Code
+//
+// Testing strategy:
+//   - Verify the markers are added around the text
+//   - Verify contents are escaped (Bold/Italic: full MarkdownV2, Code: only ` and \)
+func TestWrappers(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   func(string) string
+		input    string
+		expected string
+	}{
+		{name: "bold plain", format: Bold, input: "Hello", expected: "*Hello*"},
+		{name: "bold escaped", format: Bold, input: "Top 3 (EUR).", expected: "*Top 3 \\(EUR\\)\\.*"},
+		{name: "bold empty", format: Bold, input: "", expected: "**"},
+		{name: "italic plain", format: Italic, input: "note", expected: "_note_"},
+		{name: "italic escaped", format: Italic, input: "FQN: a.b-c", expected: "_FQN: a\\.b\\-c_"},
+		{name: "code keeps dots and dashes", format: Code, input: "eco.eco-1", expected: "`eco.eco-1`"},
+		{name: "code escapes backtick and backslash", format: Code, input: "a`b\\c", expected: "`a\\`b\\\\c`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.format(tt.input); result != tt.expected {
+				t.Errorf("format(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
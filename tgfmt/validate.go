@@ -0,0 +1,157 @@
+package tgfmt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMarkdownV2 is returned (wrapped) by ValidateMarkdownV2
+// Use errors.Is(err, ErrInvalidMarkdownV2) to detect validation failures
+var ErrInvalidMarkdownV2 = errors.New("invalid MarkdownV2")
+
+// entityMarkers are the markers that open and close formatting entities
+// Longer markers come first so "__" (underline) wins over "_" (italic)
+var entityMarkers = []string{"||", "__", "*", "_", "~"}
+
+// ValidateMarkdownV2 checks that text will be accepted by Telegram's MarkdownV2 parser
+//
+// Why validate before sending?
+//   - Telegram rejects the whole message on a single unescaped character
+//   - Error is only visible at runtime ("can't parse entities: Character '.' is reserved")
+//   - Catching it before Send gives a clear error with the exact position
+//
+// What is checked:
+//   - Reserved characters ( _ * [ ] ( ) ~ ` > # + - = | { } . ! ) outside entities must be escaped
+//   - Entities (*bold*, _italic_, __underline__, ~strike~, ||spoiler||) must be closed
+//   - `code` and ```pre``` blocks must be closed (their contents are not checked)
+//   - [links](url) must have both parts
+//   - > is allowed at the start of a line (blockquote)
+//
+// This is a practical subset of Telegram's parser, not a full reimplementation.
+//
+// Parameters:
+//   - text: MarkdownV2 text to validate
+//
+// Returns:
+//   - error: nil if valid, otherwise wraps ErrInvalidMarkdownV2 with position info
+func ValidateMarkdownV2(text string) error {
+	var open []string // Stack of currently open entity markers
+	inLinkText := false
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch {
+		// Escaped character - always valid, skip backslash and the character
+		case rest[0] == '\\':
+			if len(rest) == 1 {
+				return invalidAt(i, "trailing backslash")
+			}
+			i += 2
+			continue
+
+		// Pre block: ```...``` - contents are literal
+		case strings.HasPrefix(rest, "```"):
+			end := strings.Index(rest[3:], "```")
+			if end < 0 {
+				return invalidAt(i, "unclosed ``` block")
+			}
+			i += 3 + end + 3
+			continue
+
+		// Inline code: `...` - contents are literal
+		case rest[0] == '`':
+			end := indexUnescaped(rest[1:], '`')
+			if end < 0 {
+				return invalidAt(i, "unclosed ` code")
+			}
+			i += 1 + end + 1
+			continue
+
+		// Link text start
+		case rest[0] == '[':
+			if inLinkText {
+				return invalidAt(i, "nested [ in link text")
+			}
+			inLinkText = true
+			i++
+			continue
+
+		// Link text end - must be followed by (url)
+		case rest[0] == ']':
+			if !inLinkText {
+				return invalidAt(i, "unescaped ']'")
+			}
+			inLinkText = false
+			if len(rest) < 2 || rest[1] != '(' {
+				return invalidAt(i, "link text without (url)")
+			}
+			end := indexUnescaped(rest[2:], ')')
+			if end < 0 {
+				return invalidAt(i, "unclosed link url")
+			}
+			i += 2 + end + 1
+			continue
+
+		// Blockquote marker at start of line
+		case rest[0] == '>' && (i == 0 || text[i-1] == '\n'):
+			i++
+			continue
+		}
+
+		// Entity markers: close if it matches the innermost open entity, otherwise open
+		if marker := matchMarker(rest); marker != "" {
+			if len(open) > 0 && open[len(open)-1] == marker {
+				open = open[:len(open)-1]
+			} else {
+				open = append(open, marker)
+			}
+			i += len(marker)
+			continue
+		}
+
+		// Any other reserved character must be escaped
+		if strings.IndexByte("()>#+-=|{}.!", rest[0]) >= 0 {
+			return invalidAt(i, fmt.Sprintf("unescaped %q", rest[0]))
+		}
+		i++
+	}
+
+	if inLinkText {
+		return fmt.Errorf("%w: unclosed [ link text", ErrInvalidMarkdownV2)
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("%w: unclosed %q entity", ErrInvalidMarkdownV2, open[len(open)-1])
+	}
+	return nil
+}
+
+// matchMarker returns the entity marker at the start of s, or "" if none
+func matchMarker(s string) string {
+	for _, marker := range entityMarkers {
+		if strings.HasPrefix(s, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// indexUnescaped returns the index of the first c in s not preceded by a backslash, or -1
+func indexUnescaped(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++ // Skip escaped character
+			continue
+		}
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// invalidAt builds a validation error pointing at a byte offset
+func invalidAt(offset int, reason string) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrInvalidMarkdownV2, reason, offset)
+}
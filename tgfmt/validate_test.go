@@ -0,0 +1,60 @@
+package tgfmt
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestValidateMarkdownV2 tests ValidateMarkdownV2 with known-good and known-bad strings
+//
+// Testing strategy:
+//   - Good strings: output of our own helpers and hand-written valid markup
+//   - Bad strings: common mistakes (unescaped dot, unclosed bold, broken links)
+//   - Every error must wrap ErrInvalidMarkdownV2
+func TestValidateMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		// Known-good
+		{name: "empty", input: "", wantErr: false},
+		{name: "plain text", input: "Hello World", wantErr: false},
+		{name: "escaped dot", input: "Price: 12\\.99", wantErr: false},
+		{name: "bold and italic", input: "*bold* and _italic_", wantErr: false},
+		{name: "nested entities", input: "*bold _italic bold_*", wantErr: false},
+		{name: "underline and spoiler", input: "__under__ ||secret||", wantErr: false},
+		{name: "code with reserved chars", input: "run `go test ./...` now", wantErr: false},
+		{name: "pre block", input: "```\nfunc main() {}\n```", wantErr: false},
+		{name: "link", input: "[docs](https://core.telegram.org/bots/api)", wantErr: false},
+		{name: "blockquote", input: "> quoted\nline", wantErr: false},
+		{name: "escaped everything", input: EscapeMarkdownV2("_*[]()~`>#+-=|{}.!\\"), wantErr: false},
+		{name: "helpers output", input: Bold("12.99 EUR/mo") + " \\- " + Italic("FQN: a.b-c") + " " + Code("x.y"), wantErr: false},
+
+		// Known-bad
+		{name: "unescaped dot (price bug)", input: "*12.99 EUR/mo*", wantErr: true},
+		{name: "unescaped dash", input: "/start - welcome", wantErr: true},
+		{name: "unescaped parentheses", input: "Roll (1-6)", wantErr: true},
+		{name: "unescaped exclamation", input: "Hello!", wantErr: true},
+		{name: "unclosed bold", input: "*bold", wantErr: true},
+		{name: "unclosed code", input: "`code", wantErr: true},
+		{name: "unclosed pre", input: "```code", wantErr: true},
+		{name: "link without url", input: "[text] more", wantErr: true},
+		{name: "stray closing bracket", input: "text]", wantErr: true},
+		{name: "trailing backslash", input: "text\\", wantErr: true},
+		{name: "greater than mid-line", input: "a > b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMarkdownV2(tt.input)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateMarkdownV2(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidMarkdownV2) {
+				t.Errorf("ValidateMarkdownV2(%q) error %v does not wrap ErrInvalidMarkdownV2", tt.input, err)
+			}
+		})
+	}
+}